@@ -37,10 +37,18 @@ func (b *{{.BreachType}}Breach) GetCheckType() string {
 	return b.CheckType
 }
 
+func (b *{{.BreachType}}Breach) GetDocUrl() string {
+	return b.DocUrl
+}
+
 func (b *{{.BreachType}}Breach) GetRemediation() *Remediation {
 	return &b.Remediation
 }
 
+func (b *{{.BreachType}}Breach) GetRemediationHint() string {
+	return b.RemediationHint
+}
+
 func (b *{{.BreachType}}Breach) GetSeverity() string {
 	return b.Severity
 }
@@ -56,6 +64,11 @@ func (b *{{.BreachType}}Breach) SetCommonValues(checkType string, checkName stri
 	b.Severity = severity
 }
 
+func (b *{{.BreachType}}Breach) SetDocs(docUrl string, remediationHint string) {
+	b.DocUrl = docUrl
+	b.RemediationHint = remediationHint
+}
+
 func (b *{{.BreachType}}Breach) SetRemediation(status RemediationStatus, msg string) {
 	b.Remediation.Status = status
 	if msg != "" {
@@ -2,22 +2,32 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"text/template"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v3"
 
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/elasticsearch"
 	"github.com/salsadigitalauorg/shipshape/pkg/lagoon"
+	"github.com/salsadigitalauorg/shipshape/pkg/notify"
+	"github.com/salsadigitalauorg/shipshape/pkg/plugin"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/s3"
+	"github.com/salsadigitalauorg/shipshape/pkg/server"
 	"github.com/salsadigitalauorg/shipshape/pkg/shipshape"
 	"github.com/salsadigitalauorg/shipshape/pkg/utils"
 )
@@ -35,6 +45,18 @@ var (
 	displayVersion bool
 	dumpConfig     bool
 	listChecks     bool
+	listPresets    bool
+	initConfig     bool
+	showPlan       bool
+	exportPolicies bool
+	printSchema    bool
+	serveReports   bool
+	migrateConfig  bool
+	migrateCheck   bool
+	encryptConfig  bool
+	fleetRun       bool
+	diffRuns       bool
+	dumpFacts      bool
 	// selfUpdate     bool
 
 	errorCodeOnFailure bool
@@ -49,6 +71,30 @@ var (
 	debug              bool
 	lagoonApiBaseUrl   string
 	lagoonApiToken     string
+	s3When             string
+	lagoonPushWhen     string
+	notifyWhen         string
+	elasticsearchWhen  string
+	outputFile         string
+	outputTemplate     string
+	outputPostCommand  string
+	historyFile        string
+	baselineFile       string
+	listenAddr         string
+	webhooksFile       string
+	exportFormat       string
+	pluginsDir         string
+	noProgress         bool
+	maxDuration        string
+	fleetTargetsFile   string
+	fleetConcurrency   int
+	fleetInterval      string
+	fleetStateDir      string
+	fleetRestart       bool
+	diffFiles          []string
+	diffPrevious       bool
+	factsOnly          []string
+	encryptArgs        []string
 )
 
 func main() {
@@ -62,6 +108,13 @@ func main() {
 		os.Exit(0)
 	}
 
+	if pluginsDir != "" {
+		if err := plugin.LoadDir(pluginsDir); err != nil {
+			log.Fatalf("Unable to load the plugins: %s", err)
+		}
+		shipshape.OutputFormats = append(shipshape.OutputFormats, plugin.OutputFormats()...)
+	}
+
 	if listChecks {
 		fmt.Println("Type of checks available:")
 		checks := []string{}
@@ -75,27 +128,98 @@ func main() {
 		os.Exit(0)
 	}
 
+	if listPresets {
+		fmt.Println("Presets available:")
+		for _, p := range shipshape.Presets() {
+			fmt.Println("  - " + shipshape.PresetPrefix + p)
+		}
+		os.Exit(0)
+	}
+
 	parseArgs()
-	if !isValidOutputFormat(&outputFormat) {
+	if initConfig {
+		scaffoldConfig()
+		os.Exit(0)
+	}
+
+	if migrateConfig {
+		migrateConfigFiles()
+		os.Exit(0)
+	}
+
+	if encryptConfig {
+		encryptConfigValue()
+		os.Exit(0)
+	}
+
+	if printSchema {
+		fmt.Print(string(result.Schema))
+		os.Exit(0)
+	}
+
+	if diffRuns {
+		diffReports()
+	}
+
+	if serveReports {
+		dir := projectDir
+		if dir == "" {
+			dir = "."
+		}
+		s := &server.Server{Dir: dir}
+		if webhooksFile != "" {
+			var err error
+			if s.Webhooks, err = server.ReadWebhooks(webhooksFile); err != nil {
+				log.Fatalf("Unable to read the webhooks: %s", err)
+			}
+		}
+		fmt.Printf("Serving the reports in '%s' on %s\n", dir, listenAddr)
+		log.Fatal(server.Serve(listenAddr, s))
+	}
+
+	if !dumpFacts && !isValidOutputFormat(&outputFormat) {
 		log.Fatalf("Invalid output format; needs to be one of: %s.", strings.Join(shipshape.OutputFormats, "|"))
 	}
 
+	// Parse the template upfront so that errors are reported before the
+	// checks are run.
+	var outputTmpl *template.Template
+	if outputFormat == "template" {
+		if outputTemplate == "" {
+			log.Fatal("An output template is required for the template output format; provide it using --output-template.")
+		}
+		data, err := os.ReadFile(outputTemplate)
+		if err != nil {
+			log.Fatalf("Unable to read the output template: %s", err)
+		}
+		outputTmpl, err = shipshape.ParseOutputTemplate(filepath.Base(outputTemplate), string(data))
+		if err != nil {
+			log.Fatalf("Unable to parse the output template: %s", err)
+		}
+	}
+
 	determineLogLevel()
 
-	// simple check to ensure we have everything we need to write to the API if required.
-	if lagoon.PushProblemsToInsightRemote {
-		if lagoonApiBaseUrl == "" {
-			log.Fatal("lagoon api base url not provided")
-		}
-		if lagoonApiToken == "" {
-			log.Fatal("lagoon api token not provided")
+	if shipshape.FailSeverity != "" && !shipshape.FailSeverity.IsValid() {
+		log.Fatalf("Invalid fail severity '%s'", shipshape.FailSeverity)
+	}
+
+	if shipshape.JUnitErrorSeverity != "" && !shipshape.JUnitErrorSeverity.IsValid() {
+		log.Fatalf("Invalid junit error severity '%s'", shipshape.JUnitErrorSeverity)
+	}
+
+	if maxDuration != "" {
+		var err error
+		if shipshape.MaxDuration, err = utils.ParseDuration(maxDuration); err != nil {
+			log.Fatalf("Invalid max duration: %s", err)
 		}
 	}
 
 	for _, f := range checksFiles {
-		if !utils.StringIsUrl(f) {
+		if !utils.StringIsUrl(f) && !shipshape.IsPreset(f) {
 			if _, err := os.Stat(f); os.IsNotExist(err) {
 				fmt.Fprintf(os.Stderr, "checks file '%s' not found\n", f)
+				reportConfigError()
 
 				if errorCodeOnFailure {
 					os.Exit(1)
@@ -105,6 +229,10 @@ func main() {
 		}
 	}
 
+	if fleetRun {
+		runFleet(outputTmpl)
+	}
+
 	err := shipshape.Init(
 		projectDir,
 		checksFiles,
@@ -115,11 +243,19 @@ func main() {
 		lagoonApiBaseUrl,
 		lagoonApiToken)
 	if err != nil {
-		log.Fatal(err)
+		log.Error(err)
+		reportConfigError()
+		os.Exit(1)
 	}
 
 	if dumpConfig {
-		out, err := yaml.Marshal(shipshape.RunConfig)
+		// The decrypted values are not shown.
+		node := yaml.Node{}
+		if err := node.Encode(shipshape.RunConfig); err != nil {
+			log.Fatal(err)
+		}
+		config.RedactNode(&node)
+		out, err := yaml.Marshal(&node)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -127,27 +263,127 @@ func main() {
 		os.Exit(0)
 	}
 
+	// The credentials can be provided in the config, so these are validated
+	// once it is loaded.
+	creds := shipshape.RunConfig.Credentials
+	// simple check to ensure we have everything we need to write to the API if required.
+	if lagoon.PushProblemsToInsightRemote {
+		if lagoon.ApiBaseUrl == "" {
+			log.Fatal("lagoon api base url not provided")
+		}
+		if lagoon.ApiToken == "" {
+			log.Fatal("lagoon api token not provided")
+		}
+	}
+
+	if s3.Bucket != "" {
+		if err := s3.MustHaveEnvVars(creds); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if elasticsearch.Url != "" {
+		if err := elasticsearch.ReadEnvVars(creds); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if notify.Webhook == "" {
+		notify.Webhook = creds.NotifyWebhook
+	}
+	if notify.Webhook != "" {
+		if _, err := utils.ParseDuration(notify.Window); err != nil {
+			log.Fatalf("Invalid notification window: %s", err)
+		}
+	}
+
+	if exportPolicies {
+		if err := shipshape.ExportDisplay(bufio.NewWriter(os.Stdout), shipshape.Inventory(), exportFormat); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	if dumpFacts {
+		if err := shipshape.FactsDisplay(bufio.NewWriter(os.Stdout), shipshape.Facts(factsOnly), outputFormat); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	if showPlan {
+		plan := shipshape.Plan()
+		if outputFormat == "json" {
+			data, err := json.Marshal(plan)
+			if err != nil {
+				log.Fatalf("Unable to convert plan to json: %+v\n", err)
+			}
+			fmt.Println(string(data))
+		} else {
+			shipshape.PlanDisplay(bufio.NewWriter(os.Stdout), plan, remediate)
+		}
+		os.Exit(0)
+	}
+
+	// Progress is only rendered on an interactive terminal, and not when it
+	// would be interleaved with logs.
+	if !noProgress && !verbose && !debug && shipshape.IsInteractive(os.Stderr) {
+		shipshape.RunProgress = shipshape.NewProgress(os.Stderr)
+	}
+	if historyFile != "" {
+		shipshape.RunHistory, err = shipshape.ReadHistory(historyFile)
+		if err != nil {
+			log.Fatalf("Unable to read the history: %s", err)
+		}
+	}
+	if baselineFile != "" {
+		shipshape.RunBaseline, err = shipshape.ReadBaseline(baselineFile)
+		if err != nil {
+			log.Fatalf("Unable to read the baseline: %s", err)
+		}
+	}
+	shipshape.LoadPreviousResults()
+
 	shipshape.RunChecks()
 
-	switch outputFormat {
-	case "json":
-		data, err := json.Marshal(shipshape.RunResultList)
+	if shipshape.RunHistory != nil {
+		shipshape.RunHistory.Update(shipshape.RunResultList)
+		if err := shipshape.RunHistory.Write(historyFile); err != nil {
+			log.Fatalf("Unable to write the history to '%s': %s", historyFile, err)
+		}
+	}
+
+	// Keep a copy of the rendered report if it is to be uploaded.
+	var out io.Writer = os.Stdout
+	var report bytes.Buffer
+	if s3.Bucket != "" || outputFile != "" {
+		out = io.MultiWriter(os.Stdout, &report)
+	}
+
+	renderReport(out, outputTmpl)
+
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, report.Bytes(), 0644); err != nil {
+			log.Fatalf("Unable to write the report to '%s': %s", outputFile, err)
+		}
+		if outputPostCommand != "" {
+			cmdOut, err := shipshape.RunPostCommand(outputPostCommand, outputFile)
+			if err != nil {
+				log.Fatalf("Output post-command failed: %s", command.GetMsgFromCommandError(err))
+			}
+			log.WithField("output", string(cmdOut)).Info("output post-command run")
+		}
+	}
+
+	if s3.Bucket != "" && shouldOutput("s3", s3When) {
+		key, err := s3.UploadReport(outputFormat, report.Bytes(), shipshape.RunResultList)
 		if err != nil {
-			log.Fatalf("Unable to convert result to json: %+v\n", err)
+			log.Fatal(err)
 		}
-		fmt.Println(string(data))
-	case "junit":
-		w := bufio.NewWriter(os.Stdout)
-		shipshape.JUnit(w)
-	case "table":
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		shipshape.TableDisplay(w)
-	case "simple":
-		w := bufio.NewWriter(os.Stdout)
-		shipshape.SimpleDisplay(w)
+		log.WithField("key", key).Info("report uploaded to s3")
 	}
 
-	if lagoon.PushProblemsToInsightRemote {
+	if lagoon.PushProblemsToInsightRemote && shouldOutput("lagoon", lagoonPushWhen) {
 		w := bufio.NewWriter(os.Stdout)
 		err := lagoon.ProcessResultList(w, shipshape.RunResultList)
 		if err != nil {
@@ -155,8 +391,27 @@ func main() {
 		}
 	}
 
+	if elasticsearch.Url != "" && shouldOutput("elasticsearch", elasticsearchWhen) {
+		count, err := elasticsearch.Index(shipshape.RunResultList)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.WithField("documents", count).Info("results indexed in elasticsearch")
+	}
+
+	if notify.Webhook != "" && shouldOutput("notify", notifyWhen) {
+		count, err := notify.Notify(shipshape.RunResultList)
+		if err != nil {
+			log.Fatalf("Unable to notify the breaches: %s", err)
+		}
+		log.WithField("breaches", count).Info("breaches notified")
+	}
+
+	plugin.CloseAll()
+	logOutcome(shipshape.RunResultList.ReasonCodes)
+
 	if shipshape.RunResultList.Status() == result.Fail && errorCodeOnFailure &&
-		len(shipshape.RunResultList.GetBreachesBySeverity(string(shipshape.RunConfig.FailSeverity))) > 0 {
+		shipshape.RunResultList.FailingBreaches > 0 {
 
 		os.Exit(2)
 	}
@@ -167,7 +422,7 @@ func parseFlags() {
 
 	pflag.Usage = func() {
 		fmt.Fprint(os.Stderr, "Shipshape\n\nRun checks quickly on your project.\n\n")
-		fmt.Fprintf(os.Stderr, "Usage:\n  %s [dir]\n\nFlags:\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage:\n  %s [dir]\n  %s init [dir]\n  %s plan [dir]\n  %s export [dir]\n  %s facts [dir]\n  %s schema\n  %s serve [reports-dir]\n  %s config migrate\n  %s config encrypt [value]\n  %s fleet run --targets targets.yml [dir]\n  %s diff <old-report.json> <new-report.json>\n  %s diff --previous <report.json>\n\nFlags:\n", os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
 		pflag.PrintDefaults()
 	}
 
@@ -175,21 +430,64 @@ func parseFlags() {
 	pflag.BoolVarP(&displayVersion, "version", "", false, "Displays the application version")
 	pflag.BoolVar(&dumpConfig, "dump-config", false, "Dump the final config - useful to make sure multiple config files are being merged as expected")
 	pflag.BoolVar(&listChecks, "list-checks", false, "List available checks")
+	pflag.BoolVar(&listPresets, "list-presets", false, "List available built-in presets, which can be used as a checks file")
 	// pflag.BoolVarP(&selfUpdate, "self-update", "u", false, "Updates shipshape to the latest version")
 
 	pflag.BoolVarP(&errorCodeOnFailure, "error-code", "e", false, "Exit with error code if a failure is detected (env: SHIPSHAPE_ERROR_ON_FAILURE)")
 	pflag.StringSliceVarP(&checksFiles, "file", "f", []string{"shipshape.yml"}, "Path to the file containing the checks. Can be specified as comma-separated single argument or using --types multiple times")
-	pflag.StringVarP(&outputFormat, "output", "o", "simple", "Output format [json|junit|simple|table] (env: SHIPSHAPE_OUTPUT_FORMAT)")
+	pflag.StringVarP(&outputFormat, "output", "o", "simple", "Output format [coverage|json|junit|simple|table|template] (env: SHIPSHAPE_OUTPUT_FORMAT)")
+	pflag.StringVar(&outputTemplate, "output-template", "", "Path to the Go template rendering the report for the template output format")
+	pflag.BoolVar(&shipshape.OnlyFailures, "only-failures", false, "Only list the failing checks in the simple & table outputs")
+	pflag.IntVar(&shipshape.MaxBreachesPerCheck, "max-breaches-per-check", 0, "Maximum number of breaches listed for each check in the simple & table outputs, the others being summarised; 0 lists all of them")
+	pflag.StringVar((*string)(&shipshape.JUnitErrorSeverity), "junit-error-severity", "", "Severity [low|normal|high|critical] from which breaches are reported as errors in the junit output; breaches below it are reported as failures. All breaches are errors if not set")
+	pflag.StringVar((*string)(&shipshape.FailSeverity), "fail-severity", "", "Severity [low|normal|high|critical] from which breaches fail their check & the run; breaches below it are informational. Overrides the config's fail-severity")
 	pflag.StringSliceVarP(&checkTypesToRun, "types", "t", []string(nil), "List of checks to run; default is empty, which will run all checks. Can be specified as comma-separated single argument or using --types multiple times")
 	pflag.StringVarP(&logLevel, "log-level", "l", "warn", "Level of logs to display")
 	pflag.BoolVarP(&verbose, "verbose", "v", false, "Display verbose output - equivalent to --log-level info")
 	pflag.BoolVarP(&debug, "debug", "d", false, "Display debug information - equivalent to --log-level debug")
 	pflag.BoolVarP(&excludeDb, "exclude-db", "x", false, "Exclude checks requiring a database; overrides any db checks specified by '--types'")
 	pflag.BoolVarP(&remediate, "remediate", "r", false, "Run remediation for supported checks")
+	pflag.BoolVar(&noProgress, "no-progress", false, "Do not display the progress of the checks run on an interactive terminal")
+	pflag.StringVar(&maxDuration, "max-duration", "", "Time budget of the run, e.g, 10m; once reached, no new checks are started and the remaining ones are skipped")
+	pflag.IntVar(&shipshape.MaxConcurrentChecks, "max-concurrent-checks", 0, "Maximum number of checks run at the same time; no limit if 0")
+	pflag.BoolVar(&config.DisableSampling, "no-sample", false, "Run file-based checks against all their files, ignoring any sample configured")
+	pflag.BoolVar(&shipshape.Offline, "offline", false, "Skip the checks & outputs requiring network access instead of failing, e.g, in air-gapped environments")
 	pflag.StringVar(&lagoonApiBaseUrl, "lagoon-api-base-url", "", "Base url for the Lagoon API when pushing problems to API (env: LAGOON_API_BASE_URL)")
 	pflag.StringVar(&lagoonApiToken, "lagoon-api-token", "", "Lagoon API token when pushing problems to API (env: LAGOON_API_TOKEN)")
 	pflag.BoolVar(&lagoon.PushProblemsToInsightRemote, "lagoon-push-problems-to-insights", false, "Push audit facts to Lagoon via Insights Remote")
 	pflag.StringVar(&lagoon.LagoonInsightsRemoteEndpoint, "lagoon-insights-remote-endpoint", "http://lagoon-remote-insights-remote.lagoon.svc/problems", "Insights Remote Problems endpoint")
+	pflag.StringVar(&s3.Bucket, "s3-bucket", "", "Upload the rendered report to this S3 bucket; credentials are read from AWS_ACCESS_KEY_ID & AWS_SECRET_ACCESS_KEY (env: SHIPSHAPE_S3_BUCKET)")
+	pflag.StringVar(&s3.KeyTemplate, "s3-key", s3.DefaultKeyTemplate, "Template for the uploaded report's object key")
+	pflag.StringVar(&s3.Endpoint, "s3-endpoint", "", "Endpoint of an S3-compatible storage, e.g, https://storage.example.com; defaults to AWS")
+	pflag.StringVar(&s3.Region, "s3-region", "", "Region of the bucket (env: AWS_REGION)")
+	pflag.StringVar(&outputFile, "output-file", "", "Also write the rendered report to this file")
+	pflag.StringVar(&outputPostCommand, "output-file-post-command", "", "Shell command to run once the report file is written, e.g, to upload it; the file path is passed as $1")
+	pflag.StringVar(&historyFile, "history-file", "", "File recording when breaches were first seen across runs, used to escalate unresolved ones")
+	pflag.StringVar(&baselineFile, "baseline-file", "", "Json report of a previous run, made available to templates & checks as the previous results")
+	pflag.StringVar(&listenAddr, "listen", server.DefaultListenAddr, "Address on which serve listens")
+	pflag.StringVar(&config.KeyFile, "config-key-file", "", "File holding the key decrypting the encrypted config values, used if SHIPSHAPE_CONFIG_KEY is not set (env: SHIPSHAPE_CONFIG_KEY_FILE)")
+	pflag.BoolVar(&migrateCheck, "check", false, "Only report the deprecated config keys with config migrate, failing if any is found")
+	pflag.StringVar(&pluginsDir, "plugins-dir", "", "Directory containing plugin binaries providing additional check types & outputs (env: SHIPSHAPE_PLUGINS_DIR)")
+	pflag.StringVar(&shipshape.DefaultTarget, "target", "", "Run the checks not specifying a target against this target from the config")
+	pflag.StringVar(&fleetTargetsFile, "targets", "", "File defining the targets the checks are run against with fleet run")
+	pflag.IntVar(&fleetConcurrency, "concurrency", shipshape.DefaultFleetConcurrency, "Maximum number of targets run at the same time with fleet run")
+	pflag.StringVar(&fleetInterval, "interval", "0s", "Minimum duration between the start of the targets' runs with fleet run, e.g, 5s")
+	pflag.StringVar(&fleetStateDir, "state-dir", shipshape.DefaultFleetStateDir, "Directory keeping the completed targets' results, so that an interrupted fleet run can be resumed")
+	pflag.BoolVar(&fleetRestart, "restart", false, "Discard the results of an interrupted fleet run and run all the targets again")
+	pflag.StringVar(&exportFormat, "format", "csv", "Format [csv|json] of the policy inventory for export")
+	pflag.StringSliceVar(&factsOnly, "only", []string(nil), "Only fetch the data of the checks with these names with facts; the output format is one of [simple|json|yaml]")
+	pflag.BoolVar(&diffPrevious, "previous", false, "Compare the report with the previous one in its directory with diff")
+	pflag.StringVar(&webhooksFile, "webhooks", "", "Path to the file defining the webhooks, triggering runs, for serve")
+	pflag.StringVar(&elasticsearch.Url, "elasticsearch-url", "", "Index the results in this Elasticsearch or OpenSearch cluster; credentials are read from ELASTICSEARCH_API_KEY or ELASTICSEARCH_USERNAME & ELASTICSEARCH_PASSWORD (env: SHIPSHAPE_ELASTICSEARCH_URL)")
+	pflag.StringVar(&elasticsearch.IndexTemplate, "elasticsearch-index", elasticsearch.DefaultIndexTemplate, "Template for the name of the index the results are indexed into")
+	pflag.StringVar(&elasticsearch.IdTemplate, "elasticsearch-id", elasticsearch.DefaultIdTemplate, "Template for the ids of the indexed documents")
+	pflag.StringVar(&elasticsearchWhen, "elasticsearch-when", "", "Only index the results when the condition is met, e.g, 'breaches'")
+	pflag.StringVar(&notify.Webhook, "notify-webhook", "", "Post the breaches detected to this webhook, e.g, a Slack incoming webhook (env: SHIPSHAPE_NOTIFY_WEBHOOK)")
+	pflag.StringVar(&notify.StateFile, "notify-state-file", "", "File recording the breaches notified, so that they are not notified again within the window")
+	pflag.StringVar(&notify.Window, "notify-window", notify.DefaultWindow, "Window during which a breach is not notified again, e.g, 12h or 7d")
+	pflag.StringVar(&notifyWhen, "notify-when", "", "Only notify the breaches when the condition is met, e.g, 'severity>=high'")
+	pflag.StringVar(&s3When, "s3-when", "", "Only upload the report when the condition is met, e.g, 'breaches' or 'severity>=high'")
+	pflag.StringVar(&lagoonPushWhen, "lagoon-push-when", "", "Only push problems to Lagoon when the condition is met, e.g, 'breaches' or 'severity>=high'")
 	pflag.Parse()
 
 	if displayUsage {
@@ -227,10 +525,75 @@ func parseEnvVars() {
 	if outputFormatEnv != "" {
 		lagoonApiToken = lagoonApiTokenEnv
 	}
+
+	notifyWebhookEnv := os.Getenv("SHIPSHAPE_NOTIFY_WEBHOOK")
+	if notifyWebhookEnv != "" {
+		notify.Webhook = notifyWebhookEnv
+	}
+
+	elasticsearchUrlEnv := os.Getenv("SHIPSHAPE_ELASTICSEARCH_URL")
+	if elasticsearchUrlEnv != "" {
+		elasticsearch.Url = elasticsearchUrlEnv
+	}
+
+	pluginsDirEnv := os.Getenv("SHIPSHAPE_PLUGINS_DIR")
+	if pluginsDirEnv != "" {
+		pluginsDir = pluginsDirEnv
+	}
+
+	configKeyFileEnv := os.Getenv("SHIPSHAPE_CONFIG_KEY_FILE")
+	if configKeyFileEnv != "" {
+		config.KeyFile = configKeyFileEnv
+	}
+
+	s3BucketEnv := os.Getenv("SHIPSHAPE_S3_BUCKET")
+	if s3BucketEnv != "" {
+		s3.Bucket = s3BucketEnv
+	}
 }
 
 func parseArgs() {
 	args := pflag.Args()
+	if len(args) > 0 && args[0] == "init" {
+		initConfig = true
+		args = args[1:]
+	} else if len(args) > 0 && args[0] == "plan" {
+		showPlan = true
+		args = args[1:]
+	} else if len(args) > 0 && args[0] == "export" {
+		exportPolicies = true
+		args = args[1:]
+	} else if len(args) > 0 && args[0] == "facts" {
+		dumpFacts = true
+		args = args[1:]
+	} else if len(args) > 0 && args[0] == "schema" {
+		printSchema = true
+		args = args[1:]
+	} else if len(args) > 0 && args[0] == "serve" {
+		serveReports = true
+		args = args[1:]
+	} else if len(args) > 0 && args[0] == "config" {
+		if len(args) >= 2 && args[1] == "migrate" {
+			migrateConfig = true
+			args = args[2:]
+		} else if len(args) >= 2 && args[1] == "encrypt" {
+			encryptConfig = true
+			encryptArgs = args[2:]
+			args = nil
+		} else {
+			log.Fatal("Unknown config command; expected 'config migrate' or 'config encrypt'")
+		}
+	} else if len(args) > 0 && args[0] == "fleet" {
+		if len(args) < 2 || args[1] != "run" {
+			log.Fatal("Unknown fleet command; expected 'fleet run'")
+		}
+		fleetRun = true
+		args = args[2:]
+	} else if len(args) > 0 && args[0] == "diff" {
+		diffRuns = true
+		diffFiles = args[1:]
+		args = nil
+	}
 	if len(args) > 1 {
 		log.Fatalf("Max 1 argument expected, got '%+v'\n", args)
 	} else if len(args) == 1 {
@@ -238,6 +601,307 @@ func parseArgs() {
 	}
 }
 
+// diffReports compares two json reports, or a report with the previous one
+// in its directory, displaying the new, resolved & persisting breaches. With
+// --error-code, it exits with an error code if new breaches are found.
+func diffReports() {
+	var from, to server.Run
+	var err error
+	if diffPrevious {
+		if len(diffFiles) != 1 {
+			log.Fatalf("diff --previous expects 1 report, got '%+v'", diffFiles)
+		}
+		s := &server.Server{Dir: filepath.Dir(diffFiles[0])}
+		if to, err = s.Run(filepath.Base(diffFiles[0])); err != nil {
+			log.Fatalf("Unable to read the report '%s': %s", diffFiles[0], err)
+		}
+		if from, err = s.PreviousRun(to.Name); err != nil {
+			log.Fatalf("Unable to find the report preceding '%s': %s", diffFiles[0], err)
+		}
+	} else {
+		if len(diffFiles) != 2 {
+			log.Fatalf("diff expects 2 reports, got '%+v'", diffFiles)
+		}
+		if from, err = readReport(diffFiles[0]); err != nil {
+			log.Fatalf("Unable to read the report '%s': %s", diffFiles[0], err)
+		}
+		if to, err = readReport(diffFiles[1]); err != nil {
+			log.Fatalf("Unable to read the report '%s': %s", diffFiles[1], err)
+		}
+	}
+
+	d := result.DiffResultLists(from.Results, to.Results)
+	if err := shipshape.DiffDisplay(bufio.NewWriter(os.Stdout), d, outputFormat); err != nil {
+		log.Fatal(err)
+	}
+	if errorCodeOnFailure && len(d.New) > 0 {
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+func readReport(path string) (server.Run, error) {
+	s := &server.Server{Dir: filepath.Dir(path)}
+	return s.Run(filepath.Base(path))
+}
+
+// encryptConfigValue prints the value encrypted for use in the config; it is
+// read from stdin if not provided, so that it is not kept in the shell's
+// history.
+func encryptConfigValue() {
+	if len(encryptArgs) > 1 {
+		log.Fatalf("config encrypt expects at most 1 value, got '%+v'", encryptArgs)
+	}
+	key, err := config.EncryptionKey()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var value string
+	if len(encryptArgs) == 1 {
+		value = encryptArgs[0]
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("Unable to read the value: %s", err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+	encrypted, err := config.EncryptValue(value, key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(encrypted)
+}
+
+// scaffoldConfig generates a starter shipshape.yml in the project directory
+// based on the type of project detected.
+func scaffoldConfig() {
+	dir := projectDir
+	if dir == "" {
+		dir = "."
+	}
+	f := filepath.Join(dir, "shipshape.yml")
+	if _, err := os.Stat(f); err == nil {
+		log.Fatalf("config file '%s' already exists", f)
+	}
+
+	data, err := shipshape.Scaffold(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(f, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Config file '%s' created\n", f)
+}
+
+// migrateConfigFiles rewrites the deprecated keys of the config files; with
+// --check, the files are left as-is and the command fails if any is found.
+func migrateConfigFiles() {
+	deprecated := false
+	for _, f := range checksFiles {
+		if utils.StringIsUrl(f) || shipshape.IsPreset(f) {
+			log.Fatalf("Only local config files can be migrated, got '%s'", f)
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		migrated, changes, err := config.MigrateConfig(data)
+		if err != nil {
+			log.Fatalf("Unable to parse config file '%s': %s", f, err)
+		}
+		for _, c := range changes {
+			fmt.Printf("%s: %s\n", f, c)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		deprecated = true
+		if migrateCheck {
+			continue
+		}
+		if err := os.WriteFile(f, migrated, 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Config file '%s' migrated\n", f)
+	}
+	if migrateCheck && deprecated {
+		os.Exit(1)
+	}
+}
+
+// runFleet runs the config against all the targets of the targets file, then
+// renders the aggregated results.
+func runFleet(outputTmpl *template.Template) {
+	if logrusLevel, err := log.ParseLevel(logLevel); err == nil {
+		log.SetLevel(logrusLevel)
+	}
+	if fleetTargetsFile == "" {
+		log.Fatal("A targets file is required for fleet run; provide it using --targets.")
+	}
+	targets, err := shipshape.ReadFleetTargets(fleetTargetsFile)
+	if err != nil {
+		log.Errorf("Unable to read the targets: %s", err)
+		reportConfigError()
+		os.Exit(1)
+	}
+	interval, err := utils.ParseDuration(fleetInterval)
+	if err != nil {
+		log.Fatalf("Invalid interval: %s", err)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if fleetRestart {
+		if err := os.RemoveAll(fleetStateDir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	args := []string{"-f", strings.Join(checksFiles, ",")}
+	if len(checkTypesToRun) > 0 {
+		args = append(args, "-t", strings.Join(checkTypesToRun, ","))
+	}
+	if excludeDb {
+		args = append(args, "-x")
+	}
+	if remediate {
+		args = append(args, "-r")
+	}
+	if shipshape.Offline {
+		args = append(args, "--offline")
+	}
+	if maxDuration != "" {
+		args = append(args, "--max-duration", maxDuration)
+	}
+	if shipshape.MaxConcurrentChecks > 0 {
+		args = append(args, "--max-concurrent-checks", strconv.Itoa(shipshape.MaxConcurrentChecks))
+	}
+	if shipshape.FailSeverity != "" {
+		args = append(args, "--fail-severity", string(shipshape.FailSeverity))
+	}
+	if projectDir != "" {
+		args = append(args, projectDir)
+	}
+
+	runner := &shipshape.FleetRunner{
+		TargetsFile: fleetTargetsFile,
+		Targets:     targets,
+		Executable:  executable,
+		Args:        args,
+		Concurrency: fleetConcurrency,
+		Interval:    interval,
+		StateDir:    fleetStateDir,
+	}
+	runs, err := runner.Run()
+	if err != nil {
+		log.Fatalf("Unable to run the fleet: %s", err)
+	}
+	shipshape.RunResultList = shipshape.AggregateFleet(runs)
+
+	var out io.Writer = os.Stdout
+	var report bytes.Buffer
+	if outputFile != "" {
+		out = io.MultiWriter(os.Stdout, &report)
+	}
+	renderReport(out, outputTmpl)
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, report.Bytes(), 0644); err != nil {
+			log.Fatalf("Unable to write the report to '%s': %s", outputFile, err)
+		}
+	}
+	logOutcome(shipshape.RunResultList.ReasonCodes)
+
+	if shipshape.RunResultList.Status() == result.Fail && errorCodeOnFailure &&
+		shipshape.RunResultList.FailingBreaches > 0 {
+
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+// reportConfigError reports that the config could not be loaded, including
+// an empty report with the reason code for the json output.
+func reportConfigError() {
+	if outputFormat == "json" {
+		rl := result.NewResultList(remediate)
+		rl.ReasonCodes = []result.ReasonCode{result.ReasonConfigError}
+		data, err := json.Marshal(rl)
+		if err != nil {
+			log.Fatalf("Unable to convert result to json: %+v\n", err)
+		}
+		fmt.Println(string(data))
+	}
+	logOutcome([]result.ReasonCode{result.ReasonConfigError})
+}
+
+// logOutcome writes the reason codes of the run's outcome as the final line
+// on stderr, in a stable format for wrapper scripts.
+func logOutcome(codes []result.ReasonCode) {
+	strCodes := []string{}
+	for _, c := range codes {
+		strCodes = append(strCodes, string(c))
+	}
+	fmt.Fprintf(os.Stderr, "shipshape outcome: %s\n", strings.Join(strCodes, " "))
+}
+
+// renderReport renders the results in the output format.
+func renderReport(out io.Writer, outputTmpl *template.Template) {
+	switch outputFormat {
+	case "json":
+		data, err := json.Marshal(shipshape.RunResultList)
+		if err != nil {
+			log.Fatalf("Unable to convert result to json: %+v\n", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "junit":
+		w := bufio.NewWriter(out)
+		shipshape.JUnit(w)
+	case "table":
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		shipshape.TableDisplay(w)
+	case "simple":
+		w := bufio.NewWriter(out)
+		shipshape.SimpleDisplay(w)
+	case "coverage":
+		w := bufio.NewWriter(out)
+		shipshape.CoverageDisplay(w)
+	case "template":
+		w := bufio.NewWriter(out)
+		if err := shipshape.TemplateDisplay(w, outputTmpl); err != nil {
+			log.Fatalf("Unable to render the output template: %s", err)
+		}
+	default:
+		rendered, err := plugin.RenderOutput(outputFormat, shipshape.RunResultList)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprint(out, rendered)
+	}
+}
+
+// shouldOutput evaluates the condition for running an output; the outputs
+// all require network access, so they are skipped in offline mode.
+func shouldOutput(output string, when string) bool {
+	if shipshape.Offline {
+		log.WithField("output", output).Warn("skipping output since it requires network access")
+		return false
+	}
+	met, err := shipshape.EvaluateWhen(when, shipshape.RunResultList)
+	if err != nil {
+		log.Fatalf("Invalid condition for the %s output: %s", output, err)
+	}
+	if !met {
+		log.WithFields(log.Fields{
+			"output": output,
+			"when":   when,
+		}).Info("condition not met, skipping output")
+	}
+	return met
+}
+
 func isValidOutputFormat(of *string) bool {
 	valid := false
 	for _, fm := range shipshape.OutputFormats {
@@ -0,0 +1,43 @@
+// Package apm provides checks against the configuration of APM agents.
+package apm
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=apm
+
+func RegisterChecks() {
+	config.ChecksRegistry[Apm] = func() config.Check { return &ApmCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// Setting is an agent setting, which can be provided through an environment
+// variable or an ini directive; the environment variable takes precedence.
+type Setting struct {
+	Env string
+	Ini string
+}
+
+// AgentSettings is the list of supported agents with their settings.
+var AgentSettings = map[string]map[string]Setting{
+	"newrelic": {
+		"enabled":  {Env: "NEW_RELIC_ENABLED", Ini: "newrelic.enabled"},
+		"license":  {Env: "NEW_RELIC_LICENSE_KEY", Ini: "newrelic.license"},
+		"app-name": {Env: "NEW_RELIC_APP_NAME", Ini: "newrelic.appname"},
+	},
+	"datadog": {
+		"enabled":     {Env: "DD_TRACE_ENABLED", Ini: "datadog.trace.enabled"},
+		"app-name":    {Env: "DD_SERVICE", Ini: "datadog.service"},
+		"environment": {Env: "DD_ENV", Ini: "datadog.env"},
+	},
+}
+
+// RequiredSettings is the list of settings which must be set, per agent.
+var RequiredSettings = map[string][]string{
+	"newrelic": {"license", "app-name"},
+	"datadog":  {"app-name", "environment"},
+}
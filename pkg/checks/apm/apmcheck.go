@@ -0,0 +1,207 @@
+package apm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nikolalohinski/gonja/v2"
+	"github.com/nikolalohinski/gonja/v2/exec"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/php"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Apm config.CheckType = "apm"
+
+// ApmCheck verifies that an APM agent is enabled and configured with the
+// application name & environment expected for the current environment.
+type ApmCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// One of the AgentSettings keys, e.g, newrelic.
+	Agent string `yaml:"agent"`
+	// Ini file in which the agent is configured.
+	IniFile string `yaml:"ini-file"`
+	// Variable holding the name of the environment.
+	EnvironmentVar string `yaml:"environment-var"`
+	// Expected application name - the service for datadog. It is rendered as
+	// a template with the environment and env variables.
+	AppName string `yaml:"app-name"`
+	// Expected environment, for datadog; rendered as AppName.
+	Environment string `yaml:"environment"`
+	// Settings found, with the variable or directive they come from.
+	settings map[string]string
+	sources  map[string]string
+}
+
+// Init implementation for the apm check.
+func (c *ApmCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.EnvironmentVar == "" {
+		c.EnvironmentVar = "LAGOON_ENVIRONMENT"
+	}
+	if c.Agent == "datadog" && c.Environment == "" {
+		c.Environment = "{{ environment }}"
+	}
+}
+
+// Merge implementation for apm check.
+func (c *ApmCheck) Merge(mergeCheck config.Check) error {
+	apmMergeCheck := mergeCheck.(*ApmCheck)
+	if err := c.CheckBase.Merge(&apmMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Agent, apmMergeCheck.Agent)
+	utils.MergeString(&c.IniFile, apmMergeCheck.IniFile)
+	utils.MergeString(&c.EnvironmentVar, apmMergeCheck.EnvironmentVar)
+	utils.MergeString(&c.AppName, apmMergeCheck.AppName)
+	utils.MergeString(&c.Environment, apmMergeCheck.Environment)
+	return nil
+}
+
+// FetchData reads the ini file, if any, and the agent's settings from the
+// ini directives & environment variables.
+func (c *ApmCheck) FetchData() {
+	settings, ok := AgentSettings[c.Agent]
+	if !ok {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unsupported agent",
+			Value:      c.Agent})
+		return
+	}
+
+	c.DataMap = map[string][]byte{}
+	directives := map[string]string{}
+	if c.IniFile != "" {
+		iniFile := c.IniFile
+		if !filepath.IsAbs(iniFile) {
+			iniFile = filepath.Join(config.ProjectDir, iniFile)
+		}
+		data, err := os.ReadFile(iniFile)
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "error reading ini file",
+				Value:      err.Error()})
+			return
+		}
+		c.DataMap[c.IniFile] = data
+		directives = php.ParseIni(data)
+	}
+
+	c.settings = map[string]string{}
+	c.sources = map[string]string{}
+	for name, s := range settings {
+		if v := os.Getenv(s.Env); v != "" {
+			c.settings[name] = v
+			c.sources[name] = s.Env
+		} else if v, ok := directives[s.Ini]; ok {
+			c.settings[name] = v
+			c.sources[name] = s.Ini
+		}
+	}
+}
+
+// RunCheck verifies the agent is enabled and its settings.
+func (c *ApmCheck) RunCheck() {
+	if enabled, ok := c.settings["enabled"]; ok && !isEnabled(enabled) {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "agent",
+			Key:        c.Agent,
+			ValueLabel: "status",
+			Value:      fmt.Sprintf("disabled by %s", c.sources["enabled"]),
+		})
+		return
+	}
+
+	for _, name := range RequiredSettings[c.Agent] {
+		if c.settings[name] == "" {
+			s := AgentSettings[c.Agent][name]
+			sources := s.Env
+			if c.IniFile != "" {
+				sources += " or " + s.Ini
+			}
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "agent",
+				Key:        c.Agent,
+				ValueLabel: "missing " + name,
+				Value:      sources,
+			})
+			continue
+		}
+		c.AddPass(fmt.Sprintf("[%s] %s is set in %s", c.Agent, name, c.sources[name]))
+	}
+
+	c.verifySetting("app-name", c.AppName)
+	c.verifySetting("environment", c.Environment)
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// verifySetting verifies a setting matches the rendered expected value.
+// Multiple application names can be provided to New Relic, separated by
+// semicolons, in which case any of them can match.
+func (c *ApmCheck) verifySetting(name string, tpl string) {
+	value := c.settings[name]
+	if tpl == "" || value == "" {
+		return
+	}
+
+	expected, err := c.render(tpl)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid " + name + " template",
+			Value:      err.Error()})
+		return
+	}
+	if expected == "" {
+		c.AddWarning(fmt.Sprintf("[%s] expected %s is empty; is %s set?", c.Agent, name, c.EnvironmentVar))
+		return
+	}
+
+	for _, v := range strings.Split(value, ";") {
+		if utils.MatchString(expected, strings.TrimSpace(v)) {
+			c.AddPass(fmt.Sprintf("[%s] %s '%s' matches '%s'", c.Agent, name, value, expected))
+			return
+		}
+	}
+	c.AddBreach(&result.KeyValueBreach{
+		KeyLabel:      "agent",
+		Key:           c.Agent,
+		ValueLabel:    name,
+		ExpectedValue: expected,
+		Value:         value,
+	})
+}
+
+// render renders a template with the environment and the env variables.
+func (c *ApmCheck) render(tpl string) (string, error) {
+	t, err := gonja.FromString(tpl)
+	if err != nil {
+		return "", err
+	}
+	env := map[string]interface{}{}
+	for _, e := range os.Environ() {
+		name, value, _ := strings.Cut(e, "=")
+		env[name] = value
+	}
+	out, err := t.ExecuteToString(exec.NewContext(map[string]interface{}{
+		"environment": os.Getenv(c.EnvironmentVar),
+		"env":         env,
+	}))
+	return strings.TrimSpace(out), err
+}
+
+// isEnabled determines whether an enabled setting is truthy; ini booleans
+// are already converted to "1" or "".
+func isEnabled(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "0", "false", "off", "no":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package apm_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/apm"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Apm]()
+	assert.Equal(t, "*apm.ApmCheck", reflect.TypeOf(c).String())
+}
+
+func TestApmCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ApmCheck{Agent: "newrelic"}
+	c.Init(Apm)
+	assert.Equal("LAGOON_ENVIRONMENT", c.EnvironmentVar)
+	assert.Equal("", c.Environment)
+
+	c = ApmCheck{Agent: "datadog", EnvironmentVar: "APP_ENV"}
+	c.Init(Apm)
+	assert.Equal("APP_ENV", c.EnvironmentVar)
+	assert.Equal("{{ environment }}", c.Environment)
+}
+
+func TestApmCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ApmCheck{Agent: "newrelic", AppName: "foo"}
+	err := c.Merge(&ApmCheck{IniFile: "newrelic.ini", AppName: "bar"})
+	assert.NoError(err)
+	assert.Equal(ApmCheck{Agent: "newrelic", IniFile: "newrelic.ini", AppName: "bar"}, c)
+}
+
+func TestApmCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ApmCheck{Agent: "appdynamics"}
+	c.Init(Apm)
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "apm",
+		Severity:   "normal",
+		ValueLabel: "unsupported agent",
+		Value:      "appdynamics",
+	}}, c.Result.Breaches)
+
+	c = ApmCheck{Agent: "newrelic", IniFile: "testdata/non-existent.ini"}
+	c.Init(Apm)
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "apm",
+		Severity:   "normal",
+		ValueLabel: "error reading ini file",
+		Value:      "open testdata/non-existent.ini: no such file or directory",
+	}}, c.Result.Breaches)
+}
+
+func TestApmCheckRunCheck(t *testing.T) {
+	tt := []struct {
+		internal.RunCheckTest
+		env map[string]string
+	}{
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "newrelicIni",
+				Check:        &ApmCheck{Agent: "newrelic", IniFile: "testdata/newrelic.ini", AppName: "{{ env.LAGOON_PROJECT }}-{{ environment }}"},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{
+					"[newrelic] license is set in newrelic.license",
+					"[newrelic] app-name is set in newrelic.appname",
+					"[newrelic] app-name 'myproject-main;myproject' matches 'myproject-main'",
+				},
+				ExpectNoFail: true,
+			},
+			env: map[string]string{"LAGOON_PROJECT": "myproject", "LAGOON_ENVIRONMENT": "main"},
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "newrelicEnvOverridesIni",
+				Check:        &ApmCheck{Agent: "newrelic", IniFile: "testdata/newrelic.ini", AppName: "{{ env.LAGOON_PROJECT }}-{{ environment }}"},
+				ExpectStatus: result.Fail,
+				ExpectPasses: []string{
+					"[newrelic] license is set in newrelic.license",
+					"[newrelic] app-name is set in NEW_RELIC_APP_NAME",
+				},
+				ExpectFails: []result.Breach{&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "apm",
+					Severity:      "normal",
+					KeyLabel:      "agent",
+					Key:           "newrelic",
+					ValueLabel:    "app-name",
+					ExpectedValue: "myproject-develop",
+					Value:         "myproject-main",
+				}},
+			},
+			env: map[string]string{
+				"LAGOON_PROJECT":     "myproject",
+				"LAGOON_ENVIRONMENT": "develop",
+				"NEW_RELIC_APP_NAME": "myproject-main",
+			},
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "newrelicDisabled",
+				Check:        &ApmCheck{Agent: "newrelic", IniFile: "testdata/newrelic.ini"},
+				ExpectStatus: result.Fail,
+				ExpectNoPass: true,
+				ExpectFails: []result.Breach{&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "apm",
+					Severity:   "normal",
+					KeyLabel:   "agent",
+					Key:        "newrelic",
+					ValueLabel: "status",
+					Value:      "disabled by NEW_RELIC_ENABLED",
+				}},
+			},
+			env: map[string]string{"NEW_RELIC_ENABLED": "false"},
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "datadogMissing",
+				Check:        &ApmCheck{Agent: "datadog", AppName: "re:^myproject"},
+				ExpectStatus: result.Fail,
+				ExpectPasses: []string{
+					"[datadog] environment is set in DD_ENV",
+				},
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "apm",
+						Severity:   "normal",
+						KeyLabel:   "agent",
+						Key:        "datadog",
+						ValueLabel: "missing app-name",
+						Value:      "DD_SERVICE",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "apm",
+						Severity:      "normal",
+						KeyLabel:      "agent",
+						Key:           "datadog",
+						ValueLabel:    "environment",
+						ExpectedValue: "production",
+						Value:         "prod",
+					},
+				},
+			},
+			env: map[string]string{"LAGOON_ENVIRONMENT": "production", "DD_ENV": "prod"},
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "datadog",
+				Check:        &ApmCheck{Agent: "datadog", AppName: "re:^myproject"},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{
+					"[datadog] app-name is set in DD_SERVICE",
+					"[datadog] environment is set in DD_ENV",
+					"[datadog] app-name 'myproject-web' matches 're:^myproject'",
+					"[datadog] environment 'production' matches 'production'",
+				},
+				ExpectNoFail: true,
+			},
+			env: map[string]string{
+				"LAGOON_ENVIRONMENT": "production",
+				"DD_SERVICE":         "myproject-web",
+				"DD_ENV":             "production",
+				"DD_TRACE_ENABLED":   "true",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			for _, name := range []string{"NEW_RELIC_ENABLED", "NEW_RELIC_LICENSE_KEY",
+				"NEW_RELIC_APP_NAME", "DD_TRACE_ENABLED", "DD_SERVICE", "DD_ENV",
+				"LAGOON_PROJECT", "LAGOON_ENVIRONMENT"} {
+				t.Setenv(name, "")
+			}
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			tc.Check.Init(Apm)
+			tc.Check.FetchData()
+			internal.TestRunCheck(t, tc.RunCheckTest)
+		})
+	}
+}
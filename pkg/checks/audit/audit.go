@@ -0,0 +1,17 @@
+// Package audit provides a check running package managers' audit tools and
+// parsing their json output into vulnerabilities.
+package audit
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=audit
+
+func RegisterChecks() {
+	config.ChecksRegistry[DependencyAudit] = func() config.Check { return &DependencyAuditCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
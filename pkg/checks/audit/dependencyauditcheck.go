@@ -0,0 +1,353 @@
+package audit
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const DependencyAudit config.CheckType = "dependency-audit"
+
+// ToolDefault holds the defaults for running a supported audit tool and
+// parsing its output.
+type ToolDefault struct {
+	Bin  string
+	Args []string
+	// Argument used to point the tool to the project directory.
+	DirArg string
+	// Whether the project directory is passed as the last argument instead,
+	// defaulting to the current directory.
+	DirLast bool
+	// Arguments taking a file path, which is resolved against the project
+	// directory for tools which do not support DirArg.
+	PathArgs []string
+	Parser   VulnerabilityParser
+}
+
+// ToolDefaults is the list of supported tools.
+var ToolDefaults = map[string]ToolDefault{
+	"composer": {
+		Bin:    "composer",
+		Args:   []string{"audit", "--format=json", "--no-interaction"},
+		DirArg: "--working-dir",
+		Parser: ParseComposerAudit,
+	},
+	"npm": {
+		Bin:    "npm",
+		Args:   []string{"audit", "--json"},
+		DirArg: "--prefix",
+		Parser: ParseNpmAudit,
+	},
+	"govulncheck": {
+		Bin:    "govulncheck",
+		Args:   []string{"-json", "./..."},
+		DirArg: "-C",
+		Parser: ParseGovulncheck,
+	},
+	"pip-audit": {
+		Bin:      "pip-audit",
+		Args:     []string{"--format=json", "--progress-spinner=off"},
+		PathArgs: []string{"-r", "--requirement"},
+		Parser:   ParsePipAudit,
+	},
+	"trivy": {
+		Bin:     "trivy",
+		Args:    []string{"fs", "--format=json", "--quiet", "--scanners=vuln"},
+		DirLast: true,
+		Parser:  ParseTrivy,
+	},
+	// The image to scan is provided using the check's args.
+	"trivy-image": {
+		Bin:    "trivy",
+		Args:   []string{"image", "--format=json", "--quiet", "--scanners=vuln"},
+		Parser: ParseTrivy,
+	},
+}
+
+// DependencyAuditCheck runs a package manager's audit and reports the
+// vulnerable packages.
+type DependencyAuditCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// One of the ToolDefaults keys, e.g, npm.
+	Tool string `yaml:"tool"`
+	// Overrides the tool's default binary.
+	Bin string `yaml:"binary"`
+	// Additional arguments passed to the tool.
+	Args []string `yaml:"args"`
+	// Vulnerabilities with a lower severity (info, low, moderate, high,
+	// critical) are ignored.
+	MinSeverity VulnerabilitySeverity `yaml:"min-severity"`
+	// List of advisory ids or package names to ignore; regexes ("re:") and
+	// globs ("glob:") are supported.
+	Ignore []string `yaml:"ignore"`
+	// Advisories ignored until a given date, e.g, while waiting for a fix.
+	Exceptions []AuditException `yaml:"exceptions"`
+	// Breach severity of the advisories by their severity, e.g,
+	// critical: critical; each advisory is reported as a separate breach
+	// when set.
+	SeverityMap     map[VulnerabilitySeverity]config.Severity `yaml:"severity-map"`
+	vulnerabilities []Vulnerability
+}
+
+// AuditException ignores an advisory until it expires.
+type AuditException struct {
+	// Advisory or CVE id, e.g, CVE-2023-29197.
+	Id      string    `yaml:"id"`
+	Expires time.Time `yaml:"expires"`
+	// Why the advisory is ignored.
+	Reason string `yaml:"reason"`
+}
+
+// Merge implementation for dependency-audit check.
+func (c *DependencyAuditCheck) Merge(mergeCheck config.Check) error {
+	dependencyAuditMergeCheck := mergeCheck.(*DependencyAuditCheck)
+	if err := c.CheckBase.Merge(&dependencyAuditMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Tool, dependencyAuditMergeCheck.Tool)
+	utils.MergeString(&c.Bin, dependencyAuditMergeCheck.Bin)
+	utils.MergeStringSlice(&c.Args, dependencyAuditMergeCheck.Args)
+	if dependencyAuditMergeCheck.MinSeverity != "" {
+		c.MinSeverity = dependencyAuditMergeCheck.MinSeverity
+	}
+	utils.MergeStringSlice(&c.Ignore, dependencyAuditMergeCheck.Ignore)
+	if len(dependencyAuditMergeCheck.Exceptions) > 0 {
+		c.Exceptions = dependencyAuditMergeCheck.Exceptions
+	}
+	if len(dependencyAuditMergeCheck.SeverityMap) > 0 {
+		c.SeverityMap = dependencyAuditMergeCheck.SeverityMap
+	}
+	return nil
+}
+
+// toolCommand determines the binary and arguments to run the tool.
+func (c *DependencyAuditCheck) toolCommand(tool ToolDefault) (string, []string) {
+	bin := tool.Bin
+	if c.Bin != "" {
+		bin = c.Bin
+	}
+	// The directory is passed first since some tools stop parsing flags at
+	// the first positional argument.
+	args := []string{}
+	if tool.DirArg != "" && config.ProjectDir != "" {
+		args = append(args, tool.DirArg, config.ProjectDir)
+	}
+	args = append(args, tool.Args...)
+	args = append(args, resolvePathArgs(c.Args, tool.PathArgs)...)
+	if tool.DirLast {
+		dir := config.ProjectDir
+		if dir == "" {
+			dir = "."
+		}
+		args = append(args, dir)
+	}
+	return bin, args
+}
+
+// Commands implements config.CommandReporter.
+func (c *DependencyAuditCheck) Commands() [][]string {
+	tool, ok := ToolDefaults[c.Tool]
+	if !ok {
+		return nil
+	}
+	bin, args := c.toolCommand(tool)
+	return [][]string{append([]string{bin}, args...)}
+}
+
+// FetchData runs the audit tool to populate data for the check.
+func (c *DependencyAuditCheck) FetchData() {
+	tool, ok := ToolDefaults[c.Tool]
+	if !ok {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unsupported tool",
+			Value:      c.Tool})
+		return
+	}
+	if c.MinSeverity != "" && !c.MinSeverity.IsValid() {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid min-severity",
+			Value:      string(c.MinSeverity)})
+		return
+	}
+	for vs, s := range c.SeverityMap {
+		if !vs.IsValid() && vs != VulnerabilitySeverityUnknown || !s.IsValid() {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "invalid severity-map",
+				Key:        string(vs),
+				ValueLabel: "severity",
+				Value:      string(s)})
+			return
+		}
+	}
+	for _, e := range c.Exceptions {
+		if e.Id == "" || e.Expires.IsZero() {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "invalid exception",
+				Value:      "both id and expires are required"})
+			return
+		}
+	}
+
+	bin, args := c.toolCommand(tool)
+	var err error
+	c.DataMap = map[string][]byte{}
+	c.DataMap[c.Tool], err = command.ShellCommander(bin, args...).Output()
+	if err != nil {
+		if pathErr, ok := err.(*fs.PathError); ok {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: pathErr.Path,
+				Value:      pathErr.Err.Error()})
+		} else if len(c.DataMap[c.Tool]) == 0 {
+			// Audit tools exit with a non-zero code when vulnerabilities are
+			// found, so only fail if there is no output.
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: c.Tool + " failed to run",
+				Value:      command.GetMsgFromCommandError(err)})
+		}
+	}
+}
+
+// resolvePathArgs resolves the relative paths passed to the given arguments,
+// as "-r file" or "--requirement=file", against the project directory.
+func resolvePathArgs(args []string, pathArgs []string) []string {
+	if config.ProjectDir == "" || len(pathArgs) == 0 {
+		return args
+	}
+	resolve := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(config.ProjectDir, p)
+	}
+
+	resolved := []string{}
+	for i, a := range args {
+		if i > 0 && utils.StringSliceContains(pathArgs, args[i-1]) {
+			a = resolve(a)
+		} else if name, value, found := strings.Cut(a, "="); found &&
+			utils.StringSliceContains(pathArgs, name) {
+			a = name + "=" + resolve(value)
+		}
+		resolved = append(resolved, a)
+	}
+	return resolved
+}
+
+// UnmarshalDataMap parses the tool's output into vulnerabilities.
+func (c *DependencyAuditCheck) UnmarshalDataMap() {
+	var err error
+	c.vulnerabilities, err = ToolDefaults[c.Tool].Parser(c.DataMap[c.Tool])
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: fmt.Sprintf("unable to parse %s result", c.Tool),
+			Value:      err.Error()})
+	}
+}
+
+// RunCheck filters the vulnerabilities and reports them per package, or per
+// advisory if a severity map is set.
+func (c *DependencyAuditCheck) RunCheck() {
+	minSeverity := c.MinSeverity
+	if minSeverity == "" {
+		minSeverity = VulnerabilitySeverityLow
+	}
+
+	now := utils.TimeNow()
+	pkgVulns := map[string][]string{}
+	for _, v := range c.vulnerabilities {
+		if !v.Severity.AtLeast(minSeverity) {
+			continue
+		}
+		if c.ignored(v) {
+			continue
+		}
+		ids := v.Ids()
+		desc := fmt.Sprintf("[%s] %s (%s)", v.Severity, v.Summary(), strings.Join(ids, ", "))
+		if e, ok := c.exception(ids); ok {
+			if !now.After(e.Expires) {
+				continue
+			}
+			desc += " - exception expired on " + e.Expires.Format("2006-01-02")
+		}
+
+		if len(c.SeverityMap) == 0 {
+			pkgVulns[v.Package] = append(pkgVulns[v.Package], desc)
+			continue
+		}
+		c.addAdvisoryBreach(v, desc)
+	}
+
+	if len(pkgVulns) == 0 && len(c.Result.Breaches) == 0 {
+		c.AddPass("no vulnerable package found")
+		c.Result.Status = result.Pass
+		return
+	}
+
+	pkgs := []string{}
+	for p := range pkgVulns {
+		pkgs = append(pkgs, p)
+	}
+	sort.Strings(pkgs)
+	for _, p := range pkgs {
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "package",
+			Key:        p,
+			ValueLabel: "vulnerabilities",
+			Values:     pkgVulns[p],
+		})
+	}
+}
+
+// ignored determines whether the advisory's package or ids are ignored.
+func (c *DependencyAuditCheck) ignored(v Vulnerability) bool {
+	for _, item := range append([]string{v.Package}, v.Ids()...) {
+		if utils.StringSliceMatchAny(c.Ignore, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// exception finds the exception for any of the advisory's ids.
+func (c *DependencyAuditCheck) exception(ids []string) (AuditException, bool) {
+	for _, e := range c.Exceptions {
+		if utils.StringSliceContains(ids, e.Id) {
+			return e, true
+		}
+	}
+	return AuditException{}, false
+}
+
+// addAdvisoryBreach reports the advisory using the mapped severity, raising
+// the result's severity if needed.
+func (c *DependencyAuditCheck) addAdvisoryBreach(v Vulnerability, desc string) {
+	b := &result.KeyValueBreach{
+		KeyLabel:   "package",
+		Key:        v.Package,
+		ValueLabel: "vulnerability",
+		Value:      desc,
+	}
+	c.AddBreach(b)
+	severity, ok := c.SeverityMap[v.Severity]
+	if !ok {
+		return
+	}
+	b.SetCommonValues(b.GetCheckType(), b.GetCheckName(), string(severity))
+	if severity.Compare(config.Severity(c.Result.Severity)) > 0 {
+		c.Result.Severity = string(severity)
+	}
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *DependencyAuditCheck) RequiresNetwork() bool {
+	return true
+}
@@ -0,0 +1,505 @@
+package audit_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/audit"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[DependencyAudit]()
+	assert.Equal(t, "*audit.DependencyAuditCheck", reflect.TypeOf(c).String())
+}
+
+func TestParseNpmAudit(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/npm-audit.json")
+	vulns, err := ParseNpmAudit(data)
+	assert.NoError(err)
+	assert.Equal([]Vulnerability{
+		{Package: "lodash", Id: "GHSA-jf85-cpcp-j695",
+			Severity: VulnerabilitySeverityCritical, Title: "Prototype Pollution in lodash"},
+		{Package: "lodash", Id: "GHSA-x5rq-j2xg-h7qm",
+			Severity: VulnerabilitySeverityModerate, Title: "Regular Expression Denial of Service (ReDoS) in lodash"},
+		{Package: "minimist", Id: "GHSA-vh95-rmgr-6w4m",
+			Severity: VulnerabilitySeverityLow, Title: "Prototype Pollution in minimist"},
+	}, vulns)
+
+	_, err = ParseNpmAudit([]byte("npm ERR! code ENOLOCK"))
+	assert.EqualError(err, "invalid character 'p' in literal null (expecting 'u')")
+}
+
+func TestParseComposerAudit(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/composer-audit.json")
+	vulns, err := ParseComposerAudit(data)
+	assert.NoError(err)
+	assert.Equal([]Vulnerability{
+		{Package: "drupal/core", Id: "PKSA-8r1f-4y6x-mfq7",
+			Severity: VulnerabilitySeverityModerate,
+			Title:    "Drupal core - Moderately critical - Denial of Service - SA-CORE-2024-001"},
+		{Package: "guzzlehttp/psr7", Id: "PKSA-ywh9-7xb6-86bs", Cve: "CVE-2023-29197",
+			Severity: VulnerabilitySeverityHigh, Title: "Improper header validation in guzzlehttp/psr7"},
+		{Package: "guzzlehttp/psr7", Id: "PKSA-bv7g-bx5p-3z7g", Cve: "CVE-2022-24775",
+			Severity: VulnerabilitySeverityUnknown, Title: "Improper Input Validation in guzzlehttp/psr7"},
+	}, vulns)
+
+	// No advisories.
+	vulns, err = ParseComposerAudit([]byte(`{"advisories": [], "abandoned": []}`))
+	assert.NoError(err)
+	assert.Empty(vulns)
+
+	_, err = ParseComposerAudit([]byte("No composer.lock present"))
+	assert.Error(err)
+}
+
+func TestParsePipAudit(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/pip-audit.json")
+	vulns, err := ParsePipAudit(data)
+	assert.NoError(err)
+	assert.Equal([]Vulnerability{
+		{Package: "django", Id: "PYSEC-2021-98",
+			Severity: VulnerabilitySeverityUnknown,
+			Title:    "Django before 2.2.24, 3.x before 3.1.12, and 3.2.x before 3.2.4 has a potenti... - fixed in 2.2.24, 3.1.12, 3.2.4"},
+		{Package: "pyyaml", Id: "GHSA-8q59-q68h-6hv4",
+			Severity: VulnerabilitySeverityUnknown, Title: "Arbitrary code execution."},
+	}, vulns)
+
+	// Older versions output a list.
+	vulns, err = ParsePipAudit([]byte(`[{"name": "pyyaml", "version": "5.3", "vulns": [{"id": "GHSA-8q59-q68h-6hv4", "fix_versions": ["5.4"], "description": "Arbitrary code execution."}]}]`))
+	assert.NoError(err)
+	assert.Equal([]Vulnerability{
+		{Package: "pyyaml", Id: "GHSA-8q59-q68h-6hv4",
+			Severity: VulnerabilitySeverityUnknown, Title: "Arbitrary code execution. - fixed in 5.4"},
+	}, vulns)
+
+	_, err = ParsePipAudit([]byte("No known vulnerabilities found"))
+	assert.Error(err)
+}
+
+func TestParseGovulncheck(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/govulncheck.json")
+	vulns, err := ParseGovulncheck(data)
+	assert.NoError(err)
+	// Findings at the module or package level are not reported.
+	assert.Equal([]Vulnerability{
+		{Package: "golang.org/x/net", Id: "GO-2023-2102",
+			Severity: VulnerabilitySeverityUnknown,
+			Title:    "HTTP/2 rapid reset can cause excessive work in net/http - fixed in v0.17.0"},
+	}, vulns)
+
+	_, err = ParseGovulncheck([]byte("govulncheck: no go.mod file"))
+	assert.Error(err)
+}
+
+func TestParseTrivy(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/trivy.json")
+	vulns, err := ParseTrivy(data)
+	assert.NoError(err)
+	// The vulnerability found in both lock files is reported once.
+	assert.Equal([]Vulnerability{
+		{Package: "acme/legacy", Id: "CVE-2022-0001",
+			Severity: VulnerabilitySeverityUnknown, Title: "Unspecified issue",
+			InstalledVersion: "1.0.0"},
+		{Package: "drupal/core", Id: "CVE-2024-22362",
+			Severity: VulnerabilitySeverityModerate, Title: "Denial of service",
+			InstalledVersion: "10.1.6", FixedVersion: "10.1.8"},
+		{Package: "guzzlehttp/psr7", Id: "CVE-2023-29197",
+			Severity: VulnerabilitySeverityHigh, Title: "Improper header validation",
+			InstalledVersion: "2.4.3", FixedVersion: "2.4.5"},
+	}, vulns)
+
+	// No vulnerabilities.
+	vulns, err = ParseTrivy([]byte(`{"SchemaVersion": 2, "ArtifactName": "alpine:3.19"}`))
+	assert.NoError(err)
+	assert.Empty(vulns)
+
+	_, err = ParseTrivy([]byte("FATAL	Fatal error	image scan error"))
+	assert.Error(err)
+}
+
+func TestVulnerabilitySummary(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Denial of service", Vulnerability{Title: "Denial of service"}.Summary())
+	assert.Equal("Denial of service - 10.1.6 installed, fixed in 10.1.8", Vulnerability{
+		Title: "Denial of service", InstalledVersion: "10.1.6", FixedVersion: "10.1.8"}.Summary())
+	assert.Equal("Unspecified issue - 1.0.0 installed", Vulnerability{
+		Title: "Unspecified issue", InstalledVersion: "1.0.0"}.Summary())
+}
+
+func TestVulnerabilitySeverity(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(VulnerabilitySeverityHigh.IsValid())
+	assert.False(VulnerabilitySeverityUnknown.IsValid())
+	assert.False(VulnerabilitySeverity("severe").IsValid())
+	assert.True(VulnerabilitySeverityCritical.AtLeast(VulnerabilitySeverityHigh))
+	assert.False(VulnerabilitySeverityModerate.AtLeast(VulnerabilitySeverityHigh))
+	assert.True(VulnerabilitySeverity("").AtLeast(VulnerabilitySeverityCritical))
+}
+
+func TestDependencyAuditCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	expires := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	c := DependencyAuditCheck{
+		Tool:        "npm",
+		Ignore:      []string{"GHSA-jf85-cpcp-j695"},
+		SeverityMap: map[VulnerabilitySeverity]config.Severity{"critical": "critical"},
+	}
+	err := c.Merge(&DependencyAuditCheck{
+		Args:        []string{"--omit=dev"},
+		MinSeverity: VulnerabilitySeverityHigh,
+		Exceptions:  []AuditException{{Id: "GHSA-x5rq-j2xg-h7qm", Expires: expires}},
+	})
+	assert.Nil(err)
+	assert.EqualValues(DependencyAuditCheck{
+		Tool:        "npm",
+		Args:        []string{"--omit=dev"},
+		MinSeverity: VulnerabilitySeverityHigh,
+		Ignore:      []string{"GHSA-jf85-cpcp-j695"},
+		Exceptions:  []AuditException{{Id: "GHSA-x5rq-j2xg-h7qm", Expires: expires}},
+		SeverityMap: map[VulnerabilitySeverity]config.Severity{"critical": "critical"},
+	}, c)
+}
+
+func TestDependencyAuditCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	c := DependencyAuditCheck{Tool: "gem"}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "unsupported tool",
+		Value:      "gem",
+	}}, c.Result.Breaches)
+
+	c = DependencyAuditCheck{Tool: "npm", MinSeverity: "severe"}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "invalid min-severity",
+		Value:      "severe",
+	}}, c.Result.Breaches)
+
+	c = DependencyAuditCheck{Tool: "composer",
+		SeverityMap: map[VulnerabilitySeverity]config.Severity{"medium": "high"}}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.KeyValueBreach{
+		BreachType: "key-value",
+		KeyLabel:   "invalid severity-map",
+		Key:        "medium",
+		ValueLabel: "severity",
+		Value:      "high",
+	}}, c.Result.Breaches)
+
+	c = DependencyAuditCheck{Tool: "composer",
+		Exceptions: []AuditException{{Id: "CVE-2023-29197"}}}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "invalid exception",
+		Value:      "both id and expires are required",
+	}}, c.Result.Breaches)
+
+	config.ProjectDir = "/app"
+	defer func() { config.ProjectDir = "" }()
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{`{"vulnerabilities":{}}`}[0], nil, &generatedCommand)
+	c = DependencyAuditCheck{Tool: "npm", Args: []string{"--omit=dev"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("npm --prefix /app audit --json --omit=dev", generatedCommand)
+	assert.Equal([]byte(`{"vulnerabilities":{}}`), c.DataMap["npm"])
+
+	c = DependencyAuditCheck{Tool: "pip-audit", Args: []string{
+		"-r", "requirements.txt", "--requirement=/tmp/dev.txt", "--strict"}}
+	c.FetchData()
+	assert.Equal("pip-audit --format=json --progress-spinner=off "+
+		"-r /app/requirements.txt --requirement=/tmp/dev.txt --strict", generatedCommand)
+
+	c = DependencyAuditCheck{Tool: "composer", Args: []string{"--no-dev"}}
+	c.FetchData()
+	assert.Equal("composer --working-dir /app audit --format=json --no-interaction --no-dev", generatedCommand)
+
+	c = DependencyAuditCheck{Tool: "trivy", Args: []string{"--skip-dirs=vendor"}}
+	c.FetchData()
+	assert.Equal("trivy fs --format=json --quiet --scanners=vuln --skip-dirs=vendor /app", generatedCommand)
+
+	c = DependencyAuditCheck{Tool: "trivy-image", Args: []string{"registry.example.com/app:latest"}}
+	c.FetchData()
+	assert.Equal("trivy image --format=json --quiet --scanners=vuln registry.example.com/app:latest", generatedCommand)
+
+	config.ProjectDir = ""
+	c = DependencyAuditCheck{Tool: "trivy"}
+	c.FetchData()
+	assert.Equal("trivy fs --format=json --quiet --scanners=vuln .", generatedCommand)
+}
+
+func TestDependencyAuditCheckRunCheck(t *testing.T) {
+	npmData, _ := os.ReadFile("testdata/npm-audit.json")
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "noVulnerabilities",
+			Check: &DependencyAuditCheck{
+				Tool: "npm",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"npm": []byte(`{"vulnerabilities":{}}`)}},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"no vulnerable package found"},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "vulnerabilitiesPerPackage",
+			Check: &DependencyAuditCheck{
+				Tool: "npm",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"npm": npmData}},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					KeyLabel:   "package",
+					Key:        "lodash",
+					ValueLabel: "vulnerabilities",
+					Values: []string{
+						"[critical] Prototype Pollution in lodash (GHSA-jf85-cpcp-j695)",
+						"[moderate] Regular Expression Denial of Service (ReDoS) in lodash (GHSA-x5rq-j2xg-h7qm)",
+					},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					KeyLabel:   "package",
+					Key:        "minimist",
+					ValueLabel: "vulnerabilities",
+					Values:     []string{"[low] Prototype Pollution in minimist (GHSA-vh95-rmgr-6w4m)"},
+				},
+			},
+		},
+		{
+			Name: "vulnerabilitiesFiltered",
+			Check: &DependencyAuditCheck{
+				Tool: "npm",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"npm": npmData}},
+				MinSeverity: VulnerabilitySeverityModerate,
+				Ignore:      []string{"GHSA-jf85-cpcp-j695"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.KeyValuesBreach{
+				BreachType: "key-values",
+				KeyLabel:   "package",
+				Key:        "lodash",
+				ValueLabel: "vulnerabilities",
+				Values: []string{
+					"[moderate] Regular Expression Denial of Service (ReDoS) in lodash (GHSA-x5rq-j2xg-h7qm)",
+				},
+			}},
+		},
+		{
+			Name: "packagesIgnored",
+			Check: &DependencyAuditCheck{
+				Tool: "npm",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"npm": npmData}},
+				Ignore: []string{"lodash", "minimist"},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"no vulnerable package found"},
+			ExpectNoFail: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
+
+func TestDependencyAuditCheckRunCheckTrivy(t *testing.T) {
+	trivyData, _ := os.ReadFile("testdata/trivy.json")
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "vulnerabilitiesAboveThreshold",
+			Check: &DependencyAuditCheck{
+				Tool: "trivy",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"trivy": trivyData}},
+				MinSeverity: VulnerabilitySeverityHigh,
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					KeyLabel:   "package",
+					Key:        "acme/legacy",
+					ValueLabel: "vulnerabilities",
+					Values:     []string{"[unknown] Unspecified issue - 1.0.0 installed (CVE-2022-0001)"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					KeyLabel:   "package",
+					Key:        "guzzlehttp/psr7",
+					ValueLabel: "vulnerabilities",
+					Values: []string{
+						"[high] Improper header validation - 2.4.3 installed, fixed in 2.4.5 (CVE-2023-29197)",
+					},
+				},
+			},
+		},
+		{
+			Name: "noVulnerabilityAboveThreshold",
+			Check: &DependencyAuditCheck{
+				Tool: "trivy-image",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"trivy-image": trivyData}},
+				MinSeverity: VulnerabilitySeverityCritical,
+				Ignore:      []string{"acme/legacy"},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"no vulnerable package found"},
+			ExpectNoFail: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
+
+func TestDependencyAuditCheckRunCheckComposer(t *testing.T) {
+	composerData, _ := os.ReadFile("testdata/composer-audit.json")
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC) }
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "exceptions",
+			Check: &DependencyAuditCheck{
+				Tool: "composer",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"composer": composerData}},
+				Exceptions: []AuditException{
+					{Id: "CVE-2023-29197", Expires: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)},
+					{Id: "PKSA-8r1f-4y6x-mfq7", Expires: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "dependency-audit",
+					Severity:   "normal",
+					KeyLabel:   "package",
+					Key:        "drupal/core",
+					ValueLabel: "vulnerabilities",
+					Values: []string{
+						"[moderate] Drupal core - Moderately critical - Denial of Service - SA-CORE-2024-001 (PKSA-8r1f-4y6x-mfq7) - exception expired on 2026-09-30",
+					},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "dependency-audit",
+					Severity:   "normal",
+					KeyLabel:   "package",
+					Key:        "guzzlehttp/psr7",
+					ValueLabel: "vulnerabilities",
+					Values: []string{
+						"[unknown] Improper Input Validation in guzzlehttp/psr7 (PKSA-bv7g-bx5p-3z7g, CVE-2022-24775)",
+					},
+				},
+			},
+		},
+		{
+			Name: "severityMap",
+			Check: &DependencyAuditCheck{
+				Tool: "composer",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"composer": composerData}},
+				Ignore: []string{"CVE-2022-24775"},
+				SeverityMap: map[VulnerabilitySeverity]config.Severity{
+					"high":     "critical",
+					"moderate": "low",
+				},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "dependency-audit",
+					Severity:   "low",
+					KeyLabel:   "package",
+					Key:        "drupal/core",
+					ValueLabel: "vulnerability",
+					Value:      "[moderate] Drupal core - Moderately critical - Denial of Service - SA-CORE-2024-001 (PKSA-8r1f-4y6x-mfq7)",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "dependency-audit",
+					Severity:   "critical",
+					KeyLabel:   "package",
+					Key:        "guzzlehttp/psr7",
+					ValueLabel: "vulnerability",
+					Value:      "[high] Improper header validation in guzzlehttp/psr7 (PKSA-ywh9-7xb6-86bs, CVE-2023-29197)",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(DependencyAudit)
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+
+	c := DependencyAuditCheck{
+		Tool: "composer",
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{"composer": composerData}},
+		SeverityMap: map[VulnerabilitySeverity]config.Severity{"high": "critical"},
+	}
+	c.Init(DependencyAudit)
+	c.UnmarshalDataMap()
+	c.RunCheck()
+	assert.Equal(t, "critical", c.Result.Severity)
+}
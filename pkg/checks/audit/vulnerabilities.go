@@ -0,0 +1,371 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Vulnerability is a single advisory affecting a package.
+type Vulnerability struct {
+	Package string
+	Id      string
+	// CVE id of the advisory, if known and not already the Id.
+	Cve      string
+	Severity VulnerabilitySeverity
+	Title    string
+	// Installed & fixed versions of the package, for tools providing them
+	// separately from the title.
+	InstalledVersion string
+	FixedVersion     string
+}
+
+// Summary returns the title along with the installed & fixed versions, if
+// known.
+func (v Vulnerability) Summary() string {
+	versions := []string{}
+	if v.InstalledVersion != "" {
+		versions = append(versions, v.InstalledVersion+" installed")
+	}
+	if v.FixedVersion != "" {
+		versions = append(versions, "fixed in "+v.FixedVersion)
+	}
+	if len(versions) == 0 {
+		return v.Title
+	}
+	return v.Title + " - " + strings.Join(versions, ", ")
+}
+
+// Ids returns the advisory id along with the CVE id, if any.
+func (v Vulnerability) Ids() []string {
+	ids := []string{}
+	for _, id := range []string{v.Id, v.Cve} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// VulnerabilitySeverity is the normalised severity of an advisory.
+type VulnerabilitySeverity string
+
+const (
+	VulnerabilitySeverityUnknown  VulnerabilitySeverity = "unknown"
+	VulnerabilitySeverityInfo     VulnerabilitySeverity = "info"
+	VulnerabilitySeverityLow      VulnerabilitySeverity = "low"
+	VulnerabilitySeverityModerate VulnerabilitySeverity = "moderate"
+	VulnerabilitySeverityHigh     VulnerabilitySeverity = "high"
+	VulnerabilitySeverityCritical VulnerabilitySeverity = "critical"
+)
+
+// Vulnerabilities of unknown severity are always reported.
+var vulnerabilitySeverityLevels = map[VulnerabilitySeverity]int{
+	VulnerabilitySeverityInfo:     0,
+	VulnerabilitySeverityLow:      1,
+	VulnerabilitySeverityModerate: 2,
+	VulnerabilitySeverityHigh:     3,
+	VulnerabilitySeverityCritical: 4,
+	VulnerabilitySeverityUnknown:  5,
+}
+
+// IsValid determines whether the severity is a known one.
+func (s VulnerabilitySeverity) IsValid() bool {
+	_, ok := vulnerabilitySeverityLevels[s]
+	return ok && s != VulnerabilitySeverityUnknown
+}
+
+// AtLeast determines whether the severity is equal to or higher than min.
+func (s VulnerabilitySeverity) AtLeast(min VulnerabilitySeverity) bool {
+	level, ok := vulnerabilitySeverityLevels[s]
+	if !ok {
+		level = vulnerabilitySeverityLevels[VulnerabilitySeverityUnknown]
+	}
+	return level >= vulnerabilitySeverityLevels[min]
+}
+
+// VulnerabilityParser converts a tool's json output into a list of
+// vulnerabilities.
+type VulnerabilityParser func(data []byte) ([]Vulnerability, error)
+
+// ParseNpmAudit parses the output of `npm audit --json` (npm >= 7).
+func ParseNpmAudit(data []byte) ([]Vulnerability, error) {
+	res := struct {
+		Vulnerabilities map[string]struct {
+			// Via contains either advisories or the names of the vulnerable
+			// dependencies through which this package is affected.
+			Via []json.RawMessage `json:"via"`
+		} `json:"vulnerabilities"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	pkgs := []string{}
+	for p := range res.Vulnerabilities {
+		pkgs = append(pkgs, p)
+	}
+	sort.Strings(pkgs)
+
+	vulns := []Vulnerability{}
+	for _, p := range pkgs {
+		for _, raw := range res.Vulnerabilities[p].Via {
+			advisory := struct {
+				Url      string `json:"url"`
+				Title    string `json:"title"`
+				Severity string `json:"severity"`
+			}{}
+			if err := json.Unmarshal(raw, &advisory); err != nil {
+				// A dependency name.
+				continue
+			}
+			vulns = append(vulns, Vulnerability{
+				Package:  p,
+				Id:       advisoryIdFromUrl(advisory.Url),
+				Severity: VulnerabilitySeverity(advisory.Severity),
+				Title:    advisory.Title,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+// ParseComposerAudit parses the output of `composer audit --format=json`.
+// Composer's medium severity is normalised to moderate; advisories without
+// a severity are of unknown severity.
+func ParseComposerAudit(data []byte) ([]Vulnerability, error) {
+	res := struct {
+		// An empty list when there are no advisories.
+		Advisories json.RawMessage `json:"advisories"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	advisories := map[string][]struct {
+		AdvisoryId string `json:"advisoryId"`
+		Title      string `json:"title"`
+		Cve        string `json:"cve"`
+		Severity   string `json:"severity"`
+	}{}
+	if len(res.Advisories) > 0 && res.Advisories[0] == '{' {
+		if err := json.Unmarshal(res.Advisories, &advisories); err != nil {
+			return nil, err
+		}
+	}
+
+	pkgs := []string{}
+	for p := range advisories {
+		pkgs = append(pkgs, p)
+	}
+	sort.Strings(pkgs)
+
+	vulns := []Vulnerability{}
+	for _, p := range pkgs {
+		for _, a := range advisories[p] {
+			severity := VulnerabilitySeverity(a.Severity)
+			switch {
+			case a.Severity == "medium":
+				severity = VulnerabilitySeverityModerate
+			case !severity.IsValid():
+				severity = VulnerabilitySeverityUnknown
+			}
+			vulns = append(vulns, Vulnerability{
+				Package:  p,
+				Id:       a.AdvisoryId,
+				Cve:      a.Cve,
+				Severity: severity,
+				Title:    a.Title,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+// ParsePipAudit parses the output of `pip-audit --format=json`. pip-audit
+// does not provide severities, so all vulnerabilities are of unknown severity.
+func ParsePipAudit(data []byte) ([]Vulnerability, error) {
+	type dependency struct {
+		Name  string `json:"name"`
+		Vulns []struct {
+			Id          string   `json:"id"`
+			FixVersions []string `json:"fix_versions"`
+			Description string   `json:"description"`
+		} `json:"vulns"`
+	}
+
+	deps := []dependency{}
+	// Older versions of pip-audit output the list of dependencies directly.
+	if err := json.Unmarshal(data, &deps); err != nil {
+		res := struct {
+			Dependencies []dependency `json:"dependencies"`
+		}{}
+		if err := json.Unmarshal(data, &res); err != nil {
+			return nil, err
+		}
+		deps = res.Dependencies
+	}
+
+	vulns := []Vulnerability{}
+	for _, d := range deps {
+		for _, v := range d.Vulns {
+			title := truncate(strings.TrimSpace(v.Description), 80)
+			if len(v.FixVersions) > 0 {
+				title += fmt.Sprintf(" - fixed in %s", strings.Join(v.FixVersions, ", "))
+			}
+			vulns = append(vulns, Vulnerability{
+				Package:  d.Name,
+				Id:       v.Id,
+				Severity: VulnerabilitySeverityUnknown,
+				Title:    title,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+// ParseGovulncheck parses the output of `govulncheck -json`, which is a stream
+// of json messages. Only vulnerabilities in called functions are reported, as
+// per govulncheck's default text output; the Go vulnerability database does
+// not provide severities.
+func ParseGovulncheck(data []byte) ([]Vulnerability, error) {
+	type message struct {
+		Osv *struct {
+			Id      string `json:"id"`
+			Summary string `json:"summary"`
+		} `json:"osv"`
+		Finding *struct {
+			Osv          string `json:"osv"`
+			FixedVersion string `json:"fixed_version"`
+			Trace        []struct {
+				Module   string `json:"module"`
+				Function string `json:"function"`
+			} `json:"trace"`
+		} `json:"finding"`
+	}
+
+	summaries := map[string]string{}
+	vulns := []Vulnerability{}
+	seen := map[string]bool{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var msg message
+		if err := dec.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if msg.Osv != nil {
+			summaries[msg.Osv.Id] = msg.Osv.Summary
+			continue
+		}
+		if msg.Finding == nil || len(msg.Finding.Trace) == 0 ||
+			msg.Finding.Trace[0].Function == "" {
+			continue
+		}
+
+		f := msg.Finding
+		if seen[f.Trace[0].Module+f.Osv] {
+			continue
+		}
+		seen[f.Trace[0].Module+f.Osv] = true
+
+		title := summaries[f.Osv]
+		if f.FixedVersion != "" {
+			title += " - fixed in " + f.FixedVersion
+		}
+		vulns = append(vulns, Vulnerability{
+			Package:  f.Trace[0].Module,
+			Id:       f.Osv,
+			Severity: VulnerabilitySeverityUnknown,
+			Title:    title,
+		})
+	}
+	sort.Slice(vulns, func(i, j int) bool {
+		if vulns[i].Package != vulns[j].Package {
+			return vulns[i].Package < vulns[j].Package
+		}
+		return vulns[i].Id < vulns[j].Id
+	})
+	return vulns, nil
+}
+
+// ParseTrivy parses the output of `trivy fs` or `trivy image` with
+// --format=json. A vulnerability found in several of the scanned targets, e.g,
+// lock files, is only reported once per package version; trivy's medium
+// severity is normalised to moderate.
+func ParseTrivy(data []byte) ([]Vulnerability, error) {
+	res := struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID  string `json:"VulnerabilityID"`
+				PkgName          string `json:"PkgName"`
+				InstalledVersion string `json:"InstalledVersion"`
+				FixedVersion     string `json:"FixedVersion"`
+				Title            string `json:"Title"`
+				Severity         string `json:"Severity"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	vulns := []Vulnerability{}
+	seen := map[string]bool{}
+	for _, r := range res.Results {
+		for _, v := range r.Vulnerabilities {
+			key := v.PkgName + "@" + v.InstalledVersion + " " + v.VulnerabilityID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			severity := VulnerabilitySeverity(strings.ToLower(v.Severity))
+			switch {
+			case severity == "medium":
+				severity = VulnerabilitySeverityModerate
+			case !severity.IsValid():
+				severity = VulnerabilitySeverityUnknown
+			}
+			vulns = append(vulns, Vulnerability{
+				Package:          v.PkgName,
+				Id:               v.VulnerabilityID,
+				Severity:         severity,
+				Title:            v.Title,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+			})
+		}
+	}
+	sort.SliceStable(vulns, func(i, j int) bool {
+		if vulns[i].Package != vulns[j].Package {
+			return vulns[i].Package < vulns[j].Package
+		}
+		return vulns[i].Id < vulns[j].Id
+	})
+	return vulns, nil
+}
+
+// truncate shortens s to at most max characters, appending an ellipsis.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
+
+// advisoryIdFromUrl extracts the id from an advisory url such as
+// https://github.com/advisories/GHSA-xxxx-xxxx-xxxx.
+func advisoryIdFromUrl(url string) string {
+	for i := len(url) - 1; i >= 0; i-- {
+		if url[i] == '/' {
+			return url[i+1:]
+		}
+	}
+	return url
+}
@@ -0,0 +1,86 @@
+// Package cloudflare provides checks against the Cloudflare API.
+package cloudflare
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=cloudflare
+
+// DefaultApiUrl is the Cloudflare API used when none is provided.
+const DefaultApiUrl = "https://api.cloudflare.com/client/v4"
+
+func RegisterChecks() {
+	config.ChecksRegistry[Zone] = func() config.Check { return &ZoneCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// apiResponse is the envelope of the API responses.
+type apiResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+// apiGet queries the API, authenticating with the token; it returns the
+// response's result and status code.
+func apiGet(apiUrl string, token string, path string) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(apiUrl, "/")+path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := utils.HttpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	apiResp := apiResponse{}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, resp.StatusCode, err
+	}
+	if !apiResp.Success || resp.StatusCode >= 300 {
+		msgs := []string{}
+		for _, e := range apiResp.Errors {
+			msgs = append(msgs, fmt.Sprintf("%s (%d)", e.Message, e.Code))
+		}
+		if len(msgs) == 0 {
+			msgs = append(msgs, fmt.Sprintf("request failed (%d)", resp.StatusCode))
+		}
+		return nil, resp.StatusCode, errors.New(strings.Join(msgs, ", "))
+	}
+	return apiResp.Result, resp.StatusCode, nil
+}
+
+// settingValue converts a setting's value to a string; objects are converted
+// to json.
+func settingValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
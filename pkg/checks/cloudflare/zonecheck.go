@@ -0,0 +1,216 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Zone config.CheckType = "cloudflare-zone"
+
+// ZoneCheck verifies a Cloudflare zone's settings, e.g, the TLS mode or WAF,
+// and the presence of cache rules.
+type ZoneCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Name of the zone, e.g, example.com.
+	Zone string `yaml:"zone"`
+	// Url of the API.
+	ApiUrl string `yaml:"api-url"`
+	// Token authenticating the API requests, which can be an encrypted
+	// value; defaults to the CLOUDFLARE_API_TOKEN environment variable.
+	ApiToken string `yaml:"api-token"`
+	// Expected values of the zone's settings, keyed by setting id.
+	Settings map[string]string `yaml:"settings"`
+	// Require at least one enabled cache rule.
+	CacheRules bool `yaml:"cache-rules"`
+	settings   map[string]string
+	cacheRules int
+}
+
+// Init implementation for the cloudflare-zone check.
+func (c *ZoneCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.ApiUrl == "" {
+		c.ApiUrl = DefaultApiUrl
+	}
+}
+
+// Merge implementation for cloudflare-zone check.
+func (c *ZoneCheck) Merge(mergeCheck config.Check) error {
+	zoneMergeCheck := mergeCheck.(*ZoneCheck)
+	if err := c.CheckBase.Merge(&zoneMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Zone, zoneMergeCheck.Zone)
+	utils.MergeString(&c.ApiUrl, zoneMergeCheck.ApiUrl)
+	utils.MergeString(&c.ApiToken, zoneMergeCheck.ApiToken)
+	if len(zoneMergeCheck.Settings) > 0 {
+		c.Settings = zoneMergeCheck.Settings
+	}
+	if zoneMergeCheck.CacheRules {
+		c.CacheRules = true
+	}
+	return nil
+}
+
+// apiToken returns the token authenticating the API requests.
+func (c *ZoneCheck) apiToken() string {
+	if c.ApiToken != "" {
+		return c.ApiToken
+	}
+	return os.Getenv("CLOUDFLARE_API_TOKEN")
+}
+
+// FetchData looks up the zone, then queries its settings and cache rules.
+func (c *ZoneCheck) FetchData() {
+	if c.Zone == "" {
+		c.AddBreach(&result.ValueBreach{Value: "no zone provided"})
+		return
+	}
+	if c.apiToken() == "" {
+		c.AddBreach(&result.ValueBreach{Value: "CLOUDFLARE_API_TOKEN is not set"})
+		return
+	}
+
+	data, _, err := apiGet(c.ApiUrl, c.apiToken(), "/zones?name="+url.QueryEscape(c.Zone))
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to fetch zone " + c.Zone,
+			Value:      err.Error()})
+		return
+	}
+	zones := []struct {
+		Id string `json:"id"`
+	}{}
+	json.Unmarshal(data, &zones)
+	if len(zones) == 0 {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "zone not found or not accessible",
+			Value:      c.Zone})
+		return
+	}
+	zoneId := zones[0].Id
+
+	c.DataMap = map[string][]byte{}
+	c.DataMap["settings"], _, err = apiGet(c.ApiUrl, c.apiToken(), "/zones/"+zoneId+"/settings")
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to fetch settings for zone " + c.Zone,
+			Value:      err.Error()})
+		return
+	}
+
+	if !c.CacheRules {
+		return
+	}
+	data, status, err := apiGet(c.ApiUrl, c.apiToken(),
+		"/zones/"+zoneId+"/rulesets/phases/http_request_cache_settings/entrypoint")
+	// Zones without cache rules have no entrypoint ruleset.
+	if status == http.StatusNotFound {
+		return
+	} else if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to fetch cache rules for zone " + c.Zone,
+			Value:      err.Error()})
+		return
+	}
+	c.DataMap["cache-rules"] = data
+}
+
+// UnmarshalDataMap parses the settings and counts the enabled cache rules.
+func (c *ZoneCheck) UnmarshalDataMap() {
+	settings := []struct {
+		Id    string `json:"id"`
+		Value any    `json:"value"`
+	}{}
+	if err := json.Unmarshal(c.DataMap["settings"], &settings); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to parse settings", Value: err.Error()})
+		return
+	}
+	c.settings = map[string]string{}
+	for _, s := range settings {
+		c.settings[s.Id] = settingValue(s.Value)
+	}
+
+	c.cacheRules = 0
+	if data, ok := c.DataMap["cache-rules"]; ok {
+		ruleset := struct {
+			Rules []struct {
+				Enabled *bool `json:"enabled"`
+			} `json:"rules"`
+		}{}
+		if err := json.Unmarshal(data, &ruleset); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to parse cache rules", Value: err.Error()})
+			return
+		}
+		for _, r := range ruleset.Rules {
+			// Rules are enabled by default.
+			if r.Enabled == nil || *r.Enabled {
+				c.cacheRules++
+			}
+		}
+	}
+}
+
+// RunCheck verifies the zone's settings and cache rules.
+func (c *ZoneCheck) RunCheck() {
+	ids := []string{}
+	for id := range c.Settings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		expected := c.Settings[id]
+		value, ok := c.settings[id]
+		if !ok {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "zone",
+				Key:        c.Zone,
+				ValueLabel: "setting not found",
+				Value:      id,
+			})
+		} else if !utils.MatchString(expected, value) {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "zone",
+				Key:           c.Zone,
+				ValueLabel:    id,
+				ExpectedValue: expected,
+				Value:         value,
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] %s is %s", c.Zone, id, value))
+		}
+	}
+
+	if c.CacheRules {
+		if c.cacheRules == 0 {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "zone",
+				Key:        c.Zone,
+				ValueLabel: "cache rules",
+				Value:      "none enabled",
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] %d cache rule(s) enabled", c.Zone, c.cacheRules))
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *ZoneCheck) RequiresNetwork() bool {
+	return true
+}
@@ -0,0 +1,210 @@
+package cloudflare_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/cloudflare"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Zone]()
+	assert.Equal(t, "*cloudflare.ZoneCheck", reflect.TypeOf(c).String())
+}
+
+func TestZoneCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ZoneCheck{Zone: "example.com", Settings: map[string]string{"ssl": "full"}}
+	err := c.Merge(&ZoneCheck{Settings: map[string]string{"ssl": "strict"}, CacheRules: true})
+	assert.NoError(err)
+	assert.Equal(ZoneCheck{
+		Zone:       "example.com",
+		Settings:   map[string]string{"ssl": "strict"},
+		CacheRules: true,
+	}, c)
+}
+
+// newTestServer serves the testdata for the example.com zone; cache rules
+// are only served if withCacheRules is true.
+func newTestServer(t *testing.T, withCacheRules bool) *httptest.Server {
+	zonesData, _ := os.ReadFile("testdata/zones.json")
+	settingsData, _ := os.ReadFile("testdata/settings.json")
+	cacheRulesData, _ := os.ReadFile("testdata/cache_rules.json")
+	zoneId := "023e105f4ecef8ad9ca31a8372d0c353"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		if r.URL.Query().Get("name") != "example.com" {
+			w.Write([]byte(`{"success":true,"errors":[],"result":[]}`))
+			return
+		}
+		w.Write(zonesData)
+	})
+	mux.HandleFunc("/zones/"+zoneId+"/settings", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(settingsData)
+	})
+	mux.HandleFunc("/zones/"+zoneId+"/rulesets/phases/http_request_cache_settings/entrypoint", func(w http.ResponseWriter, r *http.Request) {
+		if !withCacheRules {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"errors":[{"code":10003,"message":"Not found"}],"result":null}`))
+			return
+		}
+		w.Write(cacheRulesData)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestZoneCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer(t, true)
+	defer srv.Close()
+
+	c := ZoneCheck{Zone: "example.com", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		Value:      "CLOUDFLARE_API_TOKEN is not set",
+	}}, c.Result.Breaches)
+
+	// The token can be provided in the config.
+	c = ZoneCheck{Zone: "example.com", ApiUrl: srv.URL, ApiToken: "secret"}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+
+	t.Setenv("CLOUDFLARE_API_TOKEN", "secret")
+	c = ZoneCheck{Zone: "example.org", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "zone not found or not accessible",
+		Value:      "example.org",
+	}}, c.Result.Breaches)
+
+	c = ZoneCheck{Zone: "example.com", ApiUrl: srv.URL + "/invalid"}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "unable to fetch zone example.com",
+		Value:      "invalid character 'p' after top-level value",
+	}}, c.Result.Breaches)
+
+	c = ZoneCheck{Zone: "example.com", ApiUrl: srv.URL, CacheRules: true}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Contains(c.DataMap, "settings")
+	assert.Contains(c.DataMap, "cache-rules")
+}
+
+func TestZoneCheckRunCheck(t *testing.T) {
+	t.Setenv("CLOUDFLARE_API_TOKEN", "secret")
+
+	tt := []struct {
+		internal.RunCheckTest
+		withCacheRules bool
+	}{
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "compliant",
+				Check: &ZoneCheck{
+					Zone: "example.com",
+					Settings: map[string]string{
+						"ssl":              "re:^(full|strict)$",
+						"waf":              "on",
+						"always_use_https": "on",
+					},
+					CacheRules: true,
+				},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{
+					"[example.com] always_use_https is on",
+					"[example.com] ssl is full",
+					"[example.com] waf is on",
+					"[example.com] 1 cache rule(s) enabled",
+				},
+				ExpectNoFail: true,
+			},
+			withCacheRules: true,
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "nonCompliant",
+				Check: &ZoneCheck{
+					Zone: "example.com",
+					Settings: map[string]string{
+						"ssl":             "strict",
+						"min_tls_version": "1.2",
+						"security_header": `{"strict_transport_security":{"enabled":true,"max_age":31536000}}`,
+						"tls_1_3":         "on",
+					},
+					CacheRules: true,
+				},
+				ExpectStatus: result.Fail,
+				ExpectPasses: []string{
+					`[example.com] security_header is {"strict_transport_security":{"enabled":true,"max_age":31536000}}`,
+				},
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "cloudflare-zone",
+						Severity:      "normal",
+						KeyLabel:      "zone",
+						Key:           "example.com",
+						ValueLabel:    "min_tls_version",
+						ExpectedValue: "1.2",
+						Value:         "1.0",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "cloudflare-zone",
+						Severity:      "normal",
+						KeyLabel:      "zone",
+						Key:           "example.com",
+						ValueLabel:    "ssl",
+						ExpectedValue: "strict",
+						Value:         "full",
+					},
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "cloudflare-zone",
+						Severity:   "normal",
+						KeyLabel:   "zone",
+						Key:        "example.com",
+						ValueLabel: "setting not found",
+						Value:      "tls_1_3",
+					},
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "cloudflare-zone",
+						Severity:   "normal",
+						KeyLabel:   "zone",
+						Key:        "example.com",
+						ValueLabel: "cache rules",
+						Value:      "none enabled",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			srv := newTestServer(t, tc.withCacheRules)
+			defer srv.Close()
+			c := tc.Check.(*ZoneCheck)
+			c.ApiUrl = srv.URL
+			c.Init(Zone)
+			c.FetchData()
+			c.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc.RunCheckTest)
+		})
+	}
+}
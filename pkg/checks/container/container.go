@@ -0,0 +1,16 @@
+// Package container provides checks for container images.
+package container
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=container
+
+func RegisterChecks() {
+	config.ChecksRegistry[ImageProvenance] = func() config.Check { return &ImageProvenanceCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
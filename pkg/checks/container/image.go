@@ -0,0 +1,98 @@
+package container
+
+import (
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRegistry is the registry used by images without an explicit one.
+const DefaultRegistry = "docker.io"
+
+// Image is a parsed image reference, e.g, ghcr.io/org/app:1.0.
+type Image struct {
+	Ref        string
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseImage parses an image reference, applying the same defaults as docker
+// for the registry & official images.
+func ParseImage(ref string) Image {
+	img := Image{Ref: ref}
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		img.Digest = name[i+1:]
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 && i > strings.LastIndex(name, "/") {
+		img.Tag = name[i+1:]
+		name = name[:i]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		img.Registry = parts[0]
+		img.Repository = parts[1]
+	} else {
+		img.Registry = DefaultRegistry
+		img.Repository = name
+		if len(parts) == 1 {
+			img.Repository = "library/" + name
+		}
+	}
+	return img
+}
+
+// Name returns the image's fully qualified name, without tag or digest.
+func (img Image) Name() string {
+	return img.Registry + "/" + img.Repository
+}
+
+// IsAllowed determines whether the image comes from one of the allowed
+// registries. Entries are either a registry, which may contain wildcards
+// (e.g, *.dkr.ecr.*.amazonaws.com), or a registry & namespace prefix
+// (e.g, docker.io/library).
+func (img Image) IsAllowed(allowed []string) bool {
+	for _, a := range allowed {
+		if !strings.Contains(a, "/") {
+			if match, _ := path.Match(a, img.Registry); match {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(img.Name()+"/", strings.TrimSuffix(a, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// FindImages returns the image references in a yaml document, looking up
+// all the `image` keys as found in docker compose files & kubernetes
+// manifests. References using variables are skipped since they cannot be
+// resolved.
+func FindImages(n *yaml.Node) []string {
+	images := []string{}
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, c := range n.Content {
+			images = append(images, FindImages(c)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Value == "image" && v.Kind == yaml.ScalarNode {
+				if v.Value != "" && !strings.Contains(v.Value, "$") {
+					images = append(images, v.Value)
+				}
+				continue
+			}
+			images = append(images, FindImages(v)...)
+		}
+	}
+	return images
+}
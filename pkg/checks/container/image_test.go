@@ -0,0 +1,59 @@
+package container_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/container"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseImage(t *testing.T) {
+	tt := []struct {
+		ref      string
+		expected Image
+	}{
+		{"nginx", Image{Ref: "nginx", Registry: "docker.io", Repository: "library/nginx"}},
+		{"mariadb:10.11", Image{Ref: "mariadb:10.11", Registry: "docker.io", Repository: "library/mariadb", Tag: "10.11"}},
+		{"uselagoon/php-8.2-fpm:latest", Image{Ref: "uselagoon/php-8.2-fpm:latest", Registry: "docker.io", Repository: "uselagoon/php-8.2-fpm", Tag: "latest"}},
+		{"ghcr.io/org/app:1.0", Image{Ref: "ghcr.io/org/app:1.0", Registry: "ghcr.io", Repository: "org/app", Tag: "1.0"}},
+		{"localhost:5000/app", Image{Ref: "localhost:5000/app", Registry: "localhost:5000", Repository: "app"}},
+		{"quay.io/org/app@sha256:0f5a", Image{Ref: "quay.io/org/app@sha256:0f5a", Registry: "quay.io", Repository: "org/app", Digest: "sha256:0f5a"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.ref, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseImage(tc.ref))
+		})
+	}
+}
+
+func TestImageIsAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(ParseImage("ghcr.io/org/app").IsAllowed([]string{"ghcr.io"}))
+	assert.True(ParseImage("123456789012.dkr.ecr.ap-southeast-2.amazonaws.com/app").
+		IsAllowed([]string{"*.dkr.ecr.*.amazonaws.com"}))
+	assert.True(ParseImage("mariadb").IsAllowed([]string{"docker.io/library"}))
+	assert.True(ParseImage("uselagoon/nginx").IsAllowed([]string{"docker.io/uselagoon/"}))
+	assert.False(ParseImage("someone/nginx").IsAllowed([]string{"docker.io/library", "ghcr.io"}))
+	assert.False(ParseImage("docker.io/uselagoonx/nginx").IsAllowed([]string{"docker.io/uselagoon"}))
+	assert.False(ParseImage("ghcr.io/org/app").IsAllowed(nil))
+}
+
+func TestFindImages(t *testing.T) {
+	var n yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+services:
+  app:
+    image: ghcr.io/org/app
+  cli:
+    image: ${CLI_IMAGE}
+  values:
+    image:
+      repository: nginx
+spec:
+  containers:
+    - image: nginx
+`), &n))
+	assert.Equal(t, []string{"ghcr.io/org/app", "nginx"}, FindImages(&n))
+}
@@ -0,0 +1,166 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const ImageProvenance config.CheckType = "image-provenance"
+
+// ImageProvenanceCheck verifies that the images referenced in docker compose
+// files or kubernetes manifests come from allowed registries, and optionally
+// that they are signed.
+type ImageProvenanceCheck struct {
+	yaml.YamlCheck `yaml:",inline"`
+	// List of registries images can be pulled from.
+	AllowedRegistries []string `yaml:"allowed-registries"`
+	// Verify the images' signatures using cosign.
+	VerifySignature *bool `yaml:"verify-signature"`
+	// Path to the cosign public key.
+	CosignKey string `yaml:"cosign-key"`
+	// Additional arguments passed to cosign verify, e.g, for keyless
+	// verification.
+	CosignArgs []string `yaml:"cosign-args"`
+	images     map[string][]string
+}
+
+// Merge implementation for image-provenance check.
+func (c *ImageProvenanceCheck) Merge(mergeCheck config.Check) error {
+	imageMergeCheck := mergeCheck.(*ImageProvenanceCheck)
+	if err := c.YamlCheck.Merge(&imageMergeCheck.YamlCheck); err != nil {
+		return err
+	}
+
+	utils.MergeStringSlice(&c.AllowedRegistries, imageMergeCheck.AllowedRegistries)
+	if imageMergeCheck.VerifySignature != nil {
+		c.VerifySignature = imageMergeCheck.VerifySignature
+	}
+	utils.MergeString(&c.CosignKey, imageMergeCheck.CosignKey)
+	utils.MergeStringSlice(&c.CosignArgs, imageMergeCheck.CosignArgs)
+	return nil
+}
+
+// UnmarshalDataMap extracts the image references from all the documents in
+// the files.
+func (c *ImageProvenanceCheck) UnmarshalDataMap() {
+	c.images = map[string][]string{}
+	for configName, data := range c.DataMap {
+		c.images[configName] = []string{}
+		dec := yamlv3.NewDecoder(bytes.NewReader(data))
+		for {
+			var n yamlv3.Node
+			err := dec.Decode(&n)
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				c.AddBreach(&result.ValueBreach{ValueLabel: configName, Value: err.Error()})
+				return
+			}
+			c.images[configName] = append(c.images[configName], FindImages(&n)...)
+		}
+	}
+}
+
+// RunCheck verifies the images' registries and signatures.
+func (c *ImageProvenanceCheck) RunCheck() {
+	if len(c.AllowedRegistries) == 0 && !c.verify() {
+		c.AddBreach(&result.ValueBreach{
+			Value: "no allowed registries provided and signature verification disabled"})
+		return
+	}
+
+	configNames := []string{}
+	for configName := range c.images {
+		configNames = append(configNames, configName)
+	}
+	sort.Strings(configNames)
+
+	verified := map[string]error{}
+	for _, configName := range configNames {
+		disallowed := []string{}
+		unsigned := []string{}
+		for _, ref := range c.images[configName] {
+			img := ParseImage(ref)
+			if len(c.AllowedRegistries) > 0 && !img.IsAllowed(c.AllowedRegistries) {
+				disallowed = append(disallowed, ref)
+				continue
+			}
+			if !c.verify() {
+				continue
+			}
+			err, ok := verified[ref]
+			if !ok {
+				err = c.verifySignature(ref)
+				verified[ref] = err
+			}
+			if err != nil {
+				unsigned = append(unsigned, fmt.Sprintf("%s: %s", ref, err))
+			}
+		}
+
+		if len(disallowed) > 0 {
+			c.AddBreach(&result.KeyValuesBreach{
+				KeyLabel:   "file",
+				Key:        configName,
+				ValueLabel: "images from disallowed registries",
+				Values:     disallowed,
+			})
+		}
+		if len(unsigned) > 0 {
+			c.AddBreach(&result.KeyValuesBreach{
+				KeyLabel:   "file",
+				Key:        configName,
+				ValueLabel: "images failing signature verification",
+				Values:     unsigned,
+			})
+		}
+		if len(disallowed) == 0 && len(unsigned) == 0 {
+			c.AddPass(fmt.Sprintf("[%s] all images are allowed", configName))
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// verifySignature runs cosign verify against the image.
+func (c *ImageProvenanceCheck) verifySignature(ref string) error {
+	if _, err := command.ShellCommander("cosign", c.cosignArgs(ref)...).Output(); err != nil {
+		return errors.New(command.GetMsgFromCommandError(err))
+	}
+	return nil
+}
+
+// cosignArgs returns the arguments for cosign to verify the image.
+func (c *ImageProvenanceCheck) cosignArgs(ref string) []string {
+	args := []string{"verify"}
+	if c.CosignKey != "" {
+		args = append(args, "--key", c.CosignKey)
+	}
+	args = append(args, c.CosignArgs...)
+	return append(args, ref)
+}
+
+// Commands implements config.CommandReporter.
+func (c *ImageProvenanceCheck) Commands() [][]string {
+	if !c.verify() {
+		return nil
+	}
+	return [][]string{append([]string{"cosign"}, c.cosignArgs("<image>")...)}
+}
+
+func (c *ImageProvenanceCheck) verify() bool {
+	return c.VerifySignature != nil && *c.VerifySignature
+}
@@ -0,0 +1,163 @@
+package container_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/container"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[ImageProvenance]()
+	assert.Equal(t, "*container.ImageProvenanceCheck", reflect.TypeOf(c).String())
+}
+
+func TestImageProvenanceCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	verify := true
+	c := ImageProvenanceCheck{
+		YamlCheck:         yaml.YamlCheck{File: "docker-compose.yml"},
+		AllowedRegistries: []string{"docker.io"},
+	}
+	err := c.Merge(&ImageProvenanceCheck{
+		AllowedRegistries: []string{"ghcr.io"},
+		VerifySignature:   &verify,
+		CosignKey:         "cosign.pub",
+	})
+	assert.NoError(err)
+	assert.Equal("docker-compose.yml", c.File)
+	assert.Equal([]string{"ghcr.io"}, c.AllowedRegistries)
+	assert.True(*c.VerifySignature)
+	assert.Equal("cosign.pub", c.CosignKey)
+}
+
+func TestImageProvenanceCheckUnmarshalDataMap(t *testing.T) {
+	c := ImageProvenanceCheck{}
+	c.DataMap = map[string][]byte{"deployment.yml": []byte("image: [")}
+	c.UnmarshalDataMap()
+	assert.EqualValues(t, []result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "deployment.yml",
+		Value:      "yaml: line 1: did not find expected node content",
+	}}, c.Result.Breaches)
+}
+
+func TestImageProvenanceCheckRunCheck(t *testing.T) {
+	assert := assert.New(t)
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	verify := true
+	tt := []struct {
+		name         string
+		check        ImageProvenanceCheck
+		files        []string
+		commander    func(name string, arg ...string) command.IShellCommand
+		expectStatus result.Status
+		expectPasses []string
+		expectFails  []result.Breach
+	}{
+		{
+			name:  "noPolicy",
+			check: ImageProvenanceCheck{},
+			files: []string{"docker-compose.yml"},
+			expectFails: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "image-provenance",
+				Severity:   "normal",
+				Value:      "no allowed registries provided and signature verification disabled",
+			}},
+			expectStatus: result.Fail,
+		},
+		{
+			name: "allowedRegistries",
+			check: ImageProvenanceCheck{
+				AllowedRegistries: []string{"*.dkr.ecr.*.amazonaws.com", "docker.io/library", "ghcr.io"},
+			},
+			files:        []string{"docker-compose.yml", "deployment.yml"},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "image-provenance",
+					Severity:   "normal",
+					KeyLabel:   "file",
+					Key:        "deployment.yml",
+					ValueLabel: "images from disallowed registries",
+					Values:     []string{"quay.io/someone/cleanup@sha256:0f5a"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "image-provenance",
+					Severity:   "normal",
+					KeyLabel:   "file",
+					Key:        "docker-compose.yml",
+					ValueLabel: "images from disallowed registries",
+					Values:     []string{"uselagoon/nginx-drupal:24.1.0"},
+				},
+			},
+		},
+		{
+			name: "verifySignature",
+			check: ImageProvenanceCheck{
+				AllowedRegistries: []string{"ghcr.io", "docker.io"},
+				VerifySignature:   &verify,
+				CosignKey:         "cosign.pub",
+			},
+			files: []string{"docker-compose.yml"},
+			commander: func(name string, arg ...string) command.IShellCommand {
+				return internal.TestShellCommand{OutputterFunc: func() ([]byte, error) {
+					if arg[len(arg)-1] == "ghcr.io/salsadigitalauorg/php:8.2" {
+						return []byte("[]"), nil
+					}
+					return nil, errors.New("no matching signatures")
+				}}
+			},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "image-provenance",
+					Severity:   "normal",
+					KeyLabel:   "file",
+					Key:        "docker-compose.yml",
+					ValueLabel: "images failing signature verification",
+					Values: []string{
+						"uselagoon/nginx-drupal:24.1.0: no matching signatures",
+						"mariadb:10.11: no matching signatures",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.commander != nil {
+				command.ShellCommander = tc.commander
+			}
+			c := tc.check
+			c.Files = tc.files
+			c.Init(ImageProvenance)
+			c.FetchData()
+			c.UnmarshalDataMap()
+			assert.Empty(c.Result.Breaches)
+			c.RunCheck()
+			c.Result.DetermineResultStatus(false)
+			assert.Equal(tc.expectStatus, c.Result.Status)
+			assert.ElementsMatch(tc.expectPasses, c.Result.Passes)
+			assert.ElementsMatch(tc.expectFails, c.Result.Breaches)
+		})
+	}
+}
@@ -18,8 +18,8 @@ import (
 type CrawlerCheck struct {
 	config.CheckBase `yaml:",inline"`
 	Domain           string   `yaml:"domain"`
-	ExtraDomains     []string `yaml:"extra_domains"`
-	IncludeURLs      []string `yaml:"include_urls"`
+	ExtraDomains     []string `yaml:"extra-domains"`
+	IncludeURLs      []string `yaml:"include-urls"`
 	Limit            int      `yaml:"limit"`
 }
 
@@ -27,6 +27,8 @@ const Crawler config.CheckType = "crawler"
 
 func RegisterChecks() {
 	config.ChecksRegistry[Crawler] = func() config.Check { return &CrawlerCheck{} }
+	config.DeprecateKey(Crawler, "extra_domains", "extra-domains")
+	config.DeprecateKey(Crawler, "include_urls", "include-urls")
 }
 
 func init() {
@@ -105,3 +107,8 @@ func (c *CrawlerCheck) RunCheck() {
 		c.AddPass("All requests completed successfully")
 	}
 }
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *CrawlerCheck) RequiresNetwork() bool {
+	return true
+}
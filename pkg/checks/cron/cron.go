@@ -0,0 +1,201 @@
+// Package cron provides checks against cron schedule expressions, such as
+// the ones found in crontabs, Lagoon cronjobs or Kubernetes CronJobs.
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=cron
+
+func RegisterChecks() {
+	config.ChecksRegistry[Cron] = func() config.Check { return &CronCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// Schedule is a parsed cron expression, where each field contains the list of
+// values at which the job runs.
+type Schedule struct {
+	Minute []int
+	Hour   []int
+	Dom    []int
+	Month  []int
+	Dow    []int
+	// Reboot is set for the @reboot macro, which has no schedule.
+	Reboot bool
+}
+
+// field defines the bounds and names of a cron expression field.
+type field struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun",
+		"jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a standard 5-field cron expression or one of the @ macros.
+// Lagoon's `M` and `H` placeholders, which are replaced by a random minute
+// and hour respectively, are accepted as single values.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "@reboot" {
+		return Schedule{Reboot: true}, nil
+	}
+	if strings.HasPrefix(expr, "@") {
+		macro, ok := macros[expr]
+		if !ok {
+			return Schedule{}, fmt.Errorf("unknown macro '%s'", expr)
+		}
+		expr = macro
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return Schedule{}, fmt.Errorf("expected %d fields, got %d", len(fields), len(parts))
+	}
+
+	values := [][]int{}
+	for i, f := range fields {
+		v, err := f.parse(parts[i], i)
+		if err != nil {
+			return Schedule{}, err
+		}
+		values = append(values, v)
+	}
+	return Schedule{
+		Minute: values[0],
+		Hour:   values[1],
+		Dom:    values[2],
+		Month:  values[3],
+		Dow:    values[4],
+	}, nil
+}
+
+// parse expands a single field into its sorted list of values.
+func (f field) parse(s string, i int) ([]int, error) {
+	set := map[int]bool{}
+	for _, item := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step '%s' in %s field", stepStr, f.name)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rng == "*":
+			start, end = f.min, f.max
+		case (i == 0 && rng == "M") || (i == 1 && rng == "H"):
+			start, end = f.min, f.min
+			if hasStep {
+				end = f.max
+			}
+		default:
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = f.value(from); err != nil {
+				return nil, err
+			}
+			end = start
+			if isRange {
+				if end, err = f.value(to); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				end = f.max
+			}
+			if start > end {
+				return nil, fmt.Errorf("invalid range '%s' in %s field", rng, f.name)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			set[v] = true
+		}
+	}
+
+	values := []int{}
+	for v := range set {
+		// Sunday can be either 0 or 7.
+		if f.max == 7 && v == 7 {
+			v = 0
+		}
+		if !utils.IntSliceContains(values, v) {
+			values = append(values, v)
+		}
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+// value parses a single numeric or named value of the field.
+func (f field) value(s string) (int, error) {
+	for i, n := range f.names {
+		if strings.EqualFold(s, n) {
+			return i + f.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value '%s' in %s field", s, f.name)
+	}
+	return v, nil
+}
+
+// MinInterval determines the shortest interval between two runs of the
+// schedule, assuming it runs every day.
+func (s Schedule) MinInterval() time.Duration {
+	if s.Reboot {
+		return 0
+	}
+
+	times := []int{}
+	for _, h := range s.Hour {
+		for _, m := range s.Minute {
+			times = append(times, h*60+m)
+		}
+	}
+	sort.Ints(times)
+
+	minutes := times[0] + 24*60 - times[len(times)-1]
+	for i := 1; i < len(times); i++ {
+		if gap := times[i] - times[i-1]; gap < minutes {
+			minutes = gap
+		}
+	}
+	return time.Duration(minutes) * time.Minute
+}
@@ -0,0 +1,82 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/cron"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := Parse("*/15 9-17 * jan,JUL mon-fri")
+	assert.NoError(err)
+	assert.Equal(Schedule{
+		Minute: []int{0, 15, 30, 45},
+		Hour:   []int{9, 10, 11, 12, 13, 14, 15, 16, 17},
+		Dom: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+			17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31},
+		Month: []int{1, 7},
+		Dow:   []int{1, 2, 3, 4, 5},
+	}, s)
+
+	s, err = Parse("@weekly")
+	assert.NoError(err)
+	assert.Equal([]int{0}, s.Dow)
+
+	s, err = Parse("5/20 H * * 7")
+	assert.NoError(err)
+	assert.Equal([]int{5, 25, 45}, s.Minute)
+	assert.Equal([]int{0}, s.Hour)
+	assert.Equal([]int{0}, s.Dow)
+
+	s, err = Parse("@reboot")
+	assert.NoError(err)
+	assert.True(s.Reboot)
+
+	_, err = Parse("* * * *")
+	assert.EqualError(err, "expected 5 fields, got 4")
+	_, err = Parse("60 * * * *")
+	assert.EqualError(err, "invalid value '60' in minute field")
+	_, err = Parse("* 5-2 * * *")
+	assert.EqualError(err, "invalid range '5-2' in hour field")
+	_, err = Parse("*/0 * * * *")
+	assert.EqualError(err, "invalid step '0' in minute field")
+	_, err = Parse("* * * foo *")
+	assert.EqualError(err, "invalid value 'foo' in month field")
+	_, err = Parse("@often")
+	assert.EqualError(err, "unknown macro '@often'")
+}
+
+func TestScheduleMinInterval(t *testing.T) {
+	tt := map[string]time.Duration{
+		"* * * * *":       time.Minute,
+		"*/5 * * * *":     5 * time.Minute,
+		"0,50 * * * *":    10 * time.Minute,
+		"M/15 * * * *":    15 * time.Minute,
+		"0 */6 * * *":     6 * time.Hour,
+		"30 1,23 * * *":   2 * time.Hour,
+		"@daily":          24 * time.Hour,
+		"0 3 * * mon-fri": 24 * time.Hour,
+		"@reboot":         0,
+	}
+	for expr, expected := range tt {
+		t.Run(expr, func(t *testing.T) {
+			s, err := Parse(expr)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, s.MinInterval())
+		})
+	}
+}
+
+func TestParseCrontab(t *testing.T) {
+	assert.Equal(t, []string{"*/10 * * * *", "@daily", "* * *"}, ParseCrontab([]byte(`
+# m h dom mon dow command
+SHELL=/bin/bash
+*/10 * * * * /usr/local/bin/sync
+@daily   /usr/local/bin/cleanup
+* * *
+`)))
+}
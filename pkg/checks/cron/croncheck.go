@@ -0,0 +1,167 @@
+package cron
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Cron config.CheckType = "cron"
+
+// CronCheck validates the cron expressions found in crontab or yaml files,
+// e.g, .lagoon.yml or Kubernetes CronJob manifests, and optionally verifies
+// that they do not run more often than the minimum interval.
+type CronCheck struct {
+	yaml.YamlCheck `yaml:",inline"`
+	// Format of the files; either yaml (default) or crontab.
+	Format string `yaml:"format"`
+	// Yaml paths to the cron expressions, e.g, `spec.schedule`.
+	Keys []string `yaml:"keys"`
+	// Shortest interval allowed between two runs, e.g, 5m.
+	MinInterval string `yaml:"min-interval"`
+	// Expressions found, keyed by file.
+	expressions map[string][]string
+}
+
+// Init implementation for the cron check.
+func (c *CronCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.Format == "" {
+		c.Format = "yaml"
+	}
+}
+
+// Merge implementation for cron check.
+func (c *CronCheck) Merge(mergeCheck config.Check) error {
+	cronMergeCheck := mergeCheck.(*CronCheck)
+	if err := c.YamlCheck.Merge(&cronMergeCheck.YamlCheck); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Format, cronMergeCheck.Format)
+	utils.MergeStringSlice(&c.Keys, cronMergeCheck.Keys)
+	utils.MergeString(&c.MinInterval, cronMergeCheck.MinInterval)
+	return nil
+}
+
+// UnmarshalDataMap extracts the cron expressions from the files, either
+// from each line of a crontab or from the keys of a yaml file.
+func (c *CronCheck) UnmarshalDataMap() {
+	c.expressions = map[string][]string{}
+	if c.Format == "crontab" {
+		for configName, data := range c.DataMap {
+			c.expressions[configName] = ParseCrontab(data)
+		}
+		return
+	}
+
+	if len(c.Keys) == 0 {
+		c.AddBreach(&result.ValueBreach{Value: "no keys provided"})
+		return
+	}
+
+	c.YamlCheck.UnmarshalDataMap()
+	for _, configName := range utils.SortedKeys(c.NodeMap) {
+		node := c.NodeMap[configName]
+		c.expressions[configName] = []string{}
+		for _, k := range c.Keys {
+			foundNodes, err := utils.LookupYamlPath(&node, k)
+			if err != nil {
+				c.AddBreach(&result.ValueBreach{Value: err.Error()})
+				return
+			}
+			for _, n := range foundNodes {
+				c.expressions[configName] = append(c.expressions[configName], n.Value)
+			}
+		}
+	}
+}
+
+// RunCheck implements the Check logic for cron expressions.
+func (c *CronCheck) RunCheck() {
+	var minInterval time.Duration
+	if c.MinInterval != "" {
+		var err error
+		if minInterval, err = utils.ParseDuration(c.MinInterval); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "invalid min-interval",
+				Value:      err.Error()})
+			return
+		}
+	}
+
+	configNames := []string{}
+	for configName := range c.expressions {
+		configNames = append(configNames, configName)
+	}
+	sort.Strings(configNames)
+
+	for _, configName := range configNames {
+		if len(c.expressions[configName]) == 0 {
+			c.AddWarning(fmt.Sprintf("[%s] no cron expressions found", configName))
+			continue
+		}
+		for _, expr := range c.expressions[configName] {
+			s, err := Parse(expr)
+			if err != nil {
+				c.AddBreach(&result.KeyValueBreach{
+					KeyLabel:   "config",
+					Key:        configName,
+					ValueLabel: fmt.Sprintf("invalid cron expression '%s'", expr),
+					Value:      err.Error(),
+				})
+				continue
+			}
+
+			if minInterval > 0 && !s.Reboot && s.MinInterval() < minInterval {
+				c.AddBreach(&result.KeyValueBreach{
+					KeyLabel:      "config",
+					Key:           configName,
+					ValueLabel:    fmt.Sprintf("cron expression '%s' runs too often", expr),
+					ExpectedValue: "every " + c.MinInterval,
+					Value:         "every " + s.MinInterval().String(),
+				})
+				continue
+			}
+			c.AddPass(fmt.Sprintf("[%s] '%s' is a valid schedule", configName, expr))
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// ParseCrontab extracts the cron expressions from crontab data, ignoring
+// blank lines, comments and variable assignments.
+func ParseCrontab(data []byte) []string {
+	expressions := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Fields(line)
+		if strings.Contains(parts[0], "=") {
+			continue
+		}
+		if strings.HasPrefix(parts[0], "@") {
+			expressions = append(expressions, parts[0])
+		} else if len(parts) < 5 {
+			expressions = append(expressions, line)
+		} else {
+			expressions = append(expressions, strings.Join(parts[:5], " "))
+		}
+	}
+	return expressions
+}
@@ -0,0 +1,141 @@
+package cron_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/cron"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Cron]()
+	assert.Equal(t, "*cron.CronCheck", reflect.TypeOf(c).String())
+}
+
+func TestCronCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := CronCheck{
+		YamlCheck:   yaml.YamlCheck{File: ".lagoon.yml"},
+		Keys:        []string{"environments.*.cronjobs[*].schedule"},
+		MinInterval: "5m",
+	}
+	err := c.Merge(&CronCheck{MinInterval: "15m"})
+	assert.NoError(err)
+	assert.Equal(".lagoon.yml", c.File)
+	assert.Equal([]string{"environments.*.cronjobs[*].schedule"}, c.Keys)
+	assert.Equal("15m", c.MinInterval)
+}
+
+func TestCronCheckRunCheck(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "lagoon",
+			Check: &CronCheck{
+				YamlCheck:   yaml.YamlCheck{File: ".lagoon.yml"},
+				Keys:        []string{"environments.*.cronjobs[*].schedule"},
+				MinInterval: "5m",
+			},
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{"[.lagoon.yml] 'M/15 * * * *' is a valid schedule"},
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "cron",
+					Severity:      "normal",
+					KeyLabel:      "config",
+					Key:           ".lagoon.yml",
+					ValueLabel:    "cron expression '* * * * *' runs too often",
+					ExpectedValue: "every 5m",
+					Value:         "every 1m0s",
+				},
+			},
+		},
+		{
+			Name: "cronJobs",
+			Check: &CronCheck{
+				YamlCheck: yaml.YamlCheck{Pattern: ".*\\.yml$"},
+				Keys:      []string{"spec.schedule"},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"[testdata/cronjob.yml] '0 3 * * mon-fri' is a valid schedule"},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "crontab",
+			Check: &CronCheck{
+				YamlCheck:   yaml.YamlCheck{File: "crontab"},
+				Format:      "crontab",
+				MinInterval: "5m",
+			},
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{
+				"[crontab] '*/10 * * * *' is a valid schedule",
+				"[crontab] '@daily' is a valid schedule",
+			},
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "cron",
+					Severity:   "normal",
+					KeyLabel:   "config",
+					Key:        "crontab",
+					ValueLabel: "invalid cron expression '61 * * * *'",
+					Value:      "invalid value '61' in minute field",
+				},
+			},
+		},
+		{
+			Name: "noKeys",
+			Check: &CronCheck{
+				YamlCheck: yaml.YamlCheck{File: ".lagoon.yml"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.ValueBreach{
+					BreachType: "value",
+					CheckType:  "cron",
+					Severity:   "normal",
+					Value:      "no keys provided",
+				},
+			},
+		},
+		{
+			Name: "invalidMinInterval",
+			Check: &CronCheck{
+				YamlCheck:   yaml.YamlCheck{File: "crontab"},
+				Format:      "crontab",
+				MinInterval: "often",
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.ValueBreach{
+					BreachType: "value",
+					CheckType:  "cron",
+					Severity:   "normal",
+					ValueLabel: "invalid min-interval",
+					Value:      "time: invalid duration \"often\"",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Cron)
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
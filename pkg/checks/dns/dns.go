@@ -0,0 +1,54 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=dns
+
+func RegisterChecks() {
+	config.ChecksRegistry[Dns] = func() config.Check { return &DnsCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// IResolver is an interface for resolving DNS records, allowing lookups to be
+// mocked in tests.
+type IResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// NewNetResolver returns a resolver using the system's default, or the
+// provided server address (host:port) if not empty.
+func NewNetResolver(server string) IResolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			d := net.Dialer{Timeout: 10 * time.Second}
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// LookupTimeout is the time after which a lookup is abandoned.
+var LookupTimeout = 10 * time.Second
+
+// ResolverMaker provides a wrapper around the resolver to allow for better
+// testing and mocking.
+var ResolverMaker = NewNetResolver
@@ -0,0 +1,241 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Dns config.CheckType = "dns"
+
+type RecordType string
+
+const (
+	RecordTypeA     RecordType = "A"
+	RecordTypeAAAA  RecordType = "AAAA"
+	RecordTypeCNAME RecordType = "CNAME"
+	RecordTypeMX    RecordType = "MX"
+	RecordTypeNS    RecordType = "NS"
+	RecordTypeTXT   RecordType = "TXT"
+)
+
+// DnsRecord defines the values expected for a record of a given name & type.
+type DnsRecord struct {
+	Name string     `yaml:"name"`
+	Type RecordType `yaml:"type"`
+	// List of values which must all be present in the resolved record.
+	Expected []string `yaml:"expected"`
+}
+
+// DnsCheck resolves records for the configured names and verifies them
+// against expected values and common email & wildcard policies.
+type DnsCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Custom resolver to use (host or host:port); defaults to the system's.
+	Resolver string      `yaml:"resolver"`
+	Records  []DnsRecord `yaml:"records"`
+	// List of domains to which the policies below apply.
+	Domains []string `yaml:"domains"`
+	// Require an SPF TXT record on the domains.
+	RequireSpf *bool `yaml:"require-spf"`
+	// Require a DMARC TXT record at _dmarc.{domain}.
+	RequireDmarc *bool `yaml:"require-dmarc"`
+	// Breach if a wildcard record resolves under the domains.
+	DisallowWildcard *bool `yaml:"disallow-wildcard"`
+}
+
+// wildcardProbeLabel is a label which should not exist under any domain; if
+// it resolves, a wildcard record is in place.
+const wildcardProbeLabel = "shipshape-wildcard-probe"
+
+// Merge implementation for dns check.
+func (c *DnsCheck) Merge(mergeCheck config.Check) error {
+	dnsMergeCheck := mergeCheck.(*DnsCheck)
+	if err := c.CheckBase.Merge(&dnsMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Resolver, dnsMergeCheck.Resolver)
+	if len(dnsMergeCheck.Records) > 0 {
+		c.Records = dnsMergeCheck.Records
+	}
+	utils.MergeStringSlice(&c.Domains, dnsMergeCheck.Domains)
+	if dnsMergeCheck.RequireSpf != nil {
+		c.RequireSpf = dnsMergeCheck.RequireSpf
+	}
+	if dnsMergeCheck.RequireDmarc != nil {
+		c.RequireDmarc = dnsMergeCheck.RequireDmarc
+	}
+	if dnsMergeCheck.DisallowWildcard != nil {
+		c.DisallowWildcard = dnsMergeCheck.DisallowWildcard
+	}
+	return nil
+}
+
+// RequiresData implementation for dns check.
+// Records are resolved while running the check.
+func (c *DnsCheck) RequiresData() bool { return false }
+
+// Lookup resolves the values of a record of the given type.
+func Lookup(r IResolver, name string, rt RecordType) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), LookupTimeout)
+	defer cancel()
+	values := []string{}
+	switch RecordType(strings.ToUpper(string(rt))) {
+	case RecordTypeA, RecordTypeAAAA:
+		addrs, err := r.LookupIPAddr(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		wantV4 := strings.ToUpper(string(rt)) == string(RecordTypeA)
+		for _, a := range addrs {
+			if (a.IP.To4() != nil) == wantV4 {
+				values = append(values, a.IP.String())
+			}
+		}
+	case RecordTypeCNAME:
+		cname, err := r.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, strings.TrimSuffix(cname, "."))
+	case RecordTypeMX:
+		mxs, err := r.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			values = append(values, strings.TrimSuffix(mx.Host, "."))
+		}
+	case RecordTypeNS:
+		nss, err := r.LookupNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			values = append(values, strings.TrimSuffix(ns.Host, "."))
+		}
+	case RecordTypeTXT:
+		txts, err := r.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, txts...)
+	default:
+		return nil, fmt.Errorf("unsupported record type '%s'", rt)
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+// RunCheck resolves the configured records and applies the domain policies.
+func (c *DnsCheck) RunCheck() {
+	if len(c.Records) == 0 && len(c.Domains) == 0 {
+		c.AddBreach(&result.ValueBreach{Value: "no records or domains provided"})
+		return
+	}
+
+	r := ResolverMaker(c.Resolver)
+	for _, rec := range c.Records {
+		c.checkRecord(r, rec)
+	}
+	for _, d := range c.Domains {
+		c.checkDomainPolicies(r, d)
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+func (c *DnsCheck) checkRecord(r IResolver, rec DnsRecord) {
+	key := fmt.Sprintf("%s %s", rec.Name, strings.ToUpper(string(rec.Type)))
+	values, err := Lookup(r, rec.Name, rec.Type)
+	if err != nil {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "record",
+			Key:        key,
+			ValueLabel: "lookup error",
+			Value:      err.Error(),
+		})
+		return
+	}
+
+	missing := utils.StringSlicesInterdiff(values, rec.Expected)
+	if len(missing) > 0 {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:      "record",
+			Key:           key,
+			Value:         strings.Join(values, ", "),
+			ExpectedValue: strings.Join(rec.Expected, ", "),
+		})
+		return
+	}
+	c.AddPass(fmt.Sprintf("[%s] record resolved as expected", key))
+}
+
+func (c *DnsCheck) checkDomainPolicies(r IResolver, d string) {
+	if c.RequireSpf != nil && *c.RequireSpf {
+		txts, _ := Lookup(r, d, RecordTypeTXT)
+		if !hasTxtPrefix(txts, "v=spf1") {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel: "domain", Key: d, Value: "SPF record not found"})
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] SPF record found", d))
+		}
+	}
+
+	if c.RequireDmarc != nil && *c.RequireDmarc {
+		txts, _ := Lookup(r, "_dmarc."+d, RecordTypeTXT)
+		if !hasTxtPrefix(txts, "v=DMARC1") {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel: "domain", Key: d, Value: "DMARC record not found"})
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] DMARC record found", d))
+		}
+	}
+
+	if c.DisallowWildcard != nil && *c.DisallowWildcard {
+		probe := wildcardProbeLabel + "." + d
+		values := []string{}
+		for _, rt := range []RecordType{RecordTypeA, RecordTypeAAAA, RecordTypeCNAME} {
+			found, err := Lookup(r, probe, rt)
+			if err != nil {
+				continue
+			}
+			for _, v := range found {
+				// Without a CNAME, the probe's own name is returned.
+				if rt == RecordTypeCNAME && strings.EqualFold(v, probe) {
+					continue
+				}
+				values = append(values, v)
+			}
+		}
+		if len(values) > 0 {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel: "domain", Key: d, ValueLabel: "wildcard record resolves to",
+				Value: strings.Join(values, ", ")})
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] no wildcard record", d))
+		}
+	}
+}
+
+func hasTxtPrefix(txts []string, prefix string) bool {
+	for _, t := range txts {
+		if strings.HasPrefix(strings.TrimSpace(t), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *DnsCheck) RequiresNetwork() bool {
+	return true
+}
@@ -0,0 +1,277 @@
+package dns_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/dns"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+type testResolver struct {
+	ips   map[string][]net.IPAddr
+	cname map[string]string
+	mx    map[string][]*net.MX
+	ns    map[string][]*net.NS
+	txt   map[string][]string
+}
+
+var errNotFound = errors.New("no such host")
+
+func (r testResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if v, ok := r.ips[host]; ok {
+		return v, nil
+	}
+	return nil, errNotFound
+}
+
+func (r testResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	if v, ok := r.cname[host]; ok {
+		return v, nil
+	}
+	return "", errNotFound
+}
+
+func (r testResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if v, ok := r.mx[name]; ok {
+		return v, nil
+	}
+	return nil, errNotFound
+}
+
+func (r testResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	if v, ok := r.ns[name]; ok {
+		return v, nil
+	}
+	return nil, errNotFound
+}
+
+func (r testResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if v, ok := r.txt[name]; ok {
+		return v, nil
+	}
+	return nil, errNotFound
+}
+
+var resolver = testResolver{
+	ips: map[string][]net.IPAddr{
+		"example.com": {
+			{IP: net.ParseIP("192.0.2.1")},
+			{IP: net.ParseIP("2001:db8::1")},
+		},
+		"shipshape-wildcard-probe.wild.example":  {{IP: net.ParseIP("192.0.2.9")}},
+		"shipshape-wildcard-probe.wild6.example": {{IP: net.ParseIP("2001:db8::9")}},
+	},
+	cname: map[string]string{
+		"www.example.com":                        "example.com.",
+		"shipshape-wildcard-probe.wild.example":  "shipshape-wildcard-probe.wild.example.",
+		"shipshape-wildcard-probe.alias.example": "dangling.example.net.",
+	},
+	mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+	ns: map[string][]*net.NS{"example.com": {{Host: "ns2.example.com."}, {Host: "ns1.example.com."}}},
+	txt: map[string][]string{
+		"example.com":        {"v=spf1 -all", "google-site-verification=foo"},
+		"_dmarc.example.com": {"v=DMARC1; p=reject"},
+	},
+}
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Dns]()
+	assert.Equal(t, "*dns.DnsCheck", reflect.TypeOf(c).String())
+}
+
+func TestDnsCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	spf := true
+	c := DnsCheck{
+		Resolver: "1.1.1.1",
+		Domains:  []string{"example.com"},
+	}
+	err := c.Merge(&DnsCheck{
+		Resolver:   "8.8.8.8:53",
+		Records:    []DnsRecord{{Name: "example.com", Type: "A"}},
+		RequireSpf: &spf,
+	})
+	assert.Nil(err)
+	assert.EqualValues(DnsCheck{
+		Resolver:   "8.8.8.8:53",
+		Records:    []DnsRecord{{Name: "example.com", Type: "A"}},
+		Domains:    []string{"example.com"},
+		RequireSpf: &spf,
+	}, c)
+}
+
+func TestLookup(t *testing.T) {
+	assert := assert.New(t)
+
+	values, err := Lookup(resolver, "example.com", RecordTypeA)
+	assert.Nil(err)
+	assert.Equal([]string{"192.0.2.1"}, values)
+
+	values, err = Lookup(resolver, "example.com", "aaaa")
+	assert.Nil(err)
+	assert.Equal([]string{"2001:db8::1"}, values)
+
+	values, err = Lookup(resolver, "www.example.com", RecordTypeCNAME)
+	assert.Nil(err)
+	assert.Equal([]string{"example.com"}, values)
+
+	values, err = Lookup(resolver, "example.com", RecordTypeNS)
+	assert.Nil(err)
+	assert.Equal([]string{"ns1.example.com", "ns2.example.com"}, values)
+
+	_, err = Lookup(resolver, "example.com", "SRV")
+	assert.EqualError(err, "unsupported record type 'SRV'")
+
+	// Lookups are abandoned after LookupTimeout.
+	curLookupTimeout := LookupTimeout
+	defer func() { LookupTimeout = curLookupTimeout }()
+	LookupTimeout = time.Millisecond
+	_, err = Lookup(slowResolver{}, "example.com", RecordTypeTXT)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+}
+
+// slowResolver only answers once the lookup's context is done.
+type slowResolver struct {
+	testResolver
+}
+
+func (slowResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestDnsCheckRunCheck(t *testing.T) {
+	curResolverMaker := ResolverMaker
+	defer func() { ResolverMaker = curResolverMaker }()
+	ResolverMaker = func(server string) IResolver { return resolver }
+
+	enabled := true
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "noConfig",
+			Check:        &DnsCheck{},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				Value:      "no records or domains provided"}},
+		},
+		{
+			Name: "recordsAsExpected",
+			Check: &DnsCheck{
+				Records: []DnsRecord{
+					{Name: "example.com", Type: "A", Expected: []string{"192.0.2.1"}},
+					{Name: "example.com", Type: "MX", Expected: []string{"mx.example.com"}},
+				},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"[example.com A] record resolved as expected",
+				"[example.com MX] record resolved as expected",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "recordsNotAsExpected",
+			Check: &DnsCheck{
+				Records: []DnsRecord{
+					{Name: "example.com", Type: "A", Expected: []string{"192.0.2.2"}},
+					{Name: "missing.example.com", Type: "TXT"},
+				},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					KeyLabel:      "record",
+					Key:           "example.com A",
+					Value:         "192.0.2.1",
+					ExpectedValue: "192.0.2.2",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "record",
+					Key:        "missing.example.com TXT",
+					ValueLabel: "lookup error",
+					Value:      "no such host",
+				},
+			},
+		},
+		{
+			Name: "domainPolicies",
+			Check: &DnsCheck{
+				Domains:          []string{"example.com", "wild.example"},
+				RequireSpf:       &enabled,
+				RequireDmarc:     &enabled,
+				DisallowWildcard: &enabled,
+			},
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{
+				"[example.com] SPF record found",
+				"[example.com] DMARC record found",
+				"[example.com] no wildcard record",
+			},
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "domain",
+					Key:        "wild.example",
+					Value:      "SPF record not found",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "domain",
+					Key:        "wild.example",
+					Value:      "DMARC record not found",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "domain",
+					Key:        "wild.example",
+					ValueLabel: "wildcard record resolves to",
+					Value:      "192.0.2.9",
+				},
+			},
+		},
+		{
+			Name: "wildcardRecordTypes",
+			Check: &DnsCheck{
+				Domains:          []string{"wild6.example", "alias.example"},
+				DisallowWildcard: &enabled,
+			},
+			ExpectStatus: result.Fail,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "domain",
+					Key:        "wild6.example",
+					ValueLabel: "wildcard record resolves to",
+					Value:      "2001:db8::9",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "domain",
+					Key:        "alias.example",
+					ValueLabel: "wildcard record resolves to",
+					Value:      "dangling.example.net",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
@@ -2,8 +2,10 @@ package docker
 
 import (
 	"bufio"
+	"fmt"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
@@ -35,6 +37,12 @@ type ComposeService struct {
 	} `yaml:"build"`
 }
 
+// Regexes extracting the image name & its optional tag, e.g, the version.
+var (
+	fromRegex  = regexp.MustCompile(`^FROM (.[^:@]*)?[:@]?(\S*)`)
+	imageRegex = regexp.MustCompile(`^(.[^:@]*)?[:@]?(\S*)`)
+)
+
 // Merge implementation for DbModuleCheck check.
 func (c *BaseImageCheck) Merge(mergeCheck config.Check) error {
 	baseImageMergeCheck := mergeCheck.(*BaseImageCheck)
@@ -67,8 +75,9 @@ func (c *BaseImageCheck) RunCheck() {
 			continue
 		}
 
-		for name, def := range compose.Services {
-			if utils.StringSliceContains(c.Exclude, name) {
+		for _, name := range utils.SortedKeys(compose.Services) {
+			def := compose.Services[name]
+			if utils.StringSliceMatchAny(c.Exclude, name) {
 				continue
 			}
 
@@ -80,22 +89,30 @@ func (c *BaseImageCheck) RunCheck() {
 				}
 				defer df.Close()
 				scanner := bufio.NewScanner(df)
+				var allowed bool
+				var reason string
 				for scanner.Scan() {
-					from_regex := regexp.MustCompile("^FROM (.[^:@]*)?[:@]?([^ latest$]*)")
-					match := from_regex.FindStringSubmatch(scanner.Text())
+					match := fromRegex.FindStringSubmatch(scanner.Text())
 
 					if len(match) < 1 {
 						continue
 					}
 
-					if len(c.Allowed) > 0 && !utils.PackageCheckString(c.Allowed, match[1], match[2]) {
+					if len(c.Allowed) == 0 {
+						allowed, reason = true, ""
+					} else {
+						allowed, reason = c.isAllowed(match[1], match[2])
+					}
+					if !allowed && reason != "" {
+						c.addVersionBreach(name, match[1], reason)
+					} else if !allowed {
 						c.AddBreach(&result.KeyValueBreach{
 							KeyLabel:   "service",
 							Key:        name,
 							ValueLabel: "invalid base image",
 							Value:      match[1],
 						})
-					} else if len(c.Deprecated) > 0 && utils.StringSliceMatch(c.Deprecated, match[1]) {
+					} else if len(c.Deprecated) > 0 && c.isDeprecated(match[1]) {
 						c.AddWarning(name + " is using deprecated image " + match[1])
 					} else {
 						c.AddPass(name + " is using valid base images")
@@ -103,21 +120,22 @@ func (c *BaseImageCheck) RunCheck() {
 				}
 			} else {
 				// Extract image package name and optional version from definition.
-				image_regex := regexp.MustCompile("^(.[^:@]*)?[:@]?([^ latest$]*)")
-				match := image_regex.FindStringSubmatch(def.Image)
+				match := imageRegex.FindStringSubmatch(def.Image)
 
 				if len(match) < 1 {
 					continue
 				}
 
-				if !utils.PackageCheckString(c.Allowed, match[1], match[2]) {
+				if allowed, reason := c.isAllowed(match[1], match[2]); !allowed && reason != "" {
+					c.addVersionBreach(name, match[1], reason)
+				} else if !allowed {
 					c.AddBreach(&result.KeyValueBreach{
 						KeyLabel:   "service",
 						Key:        name,
 						ValueLabel: "invalid base image",
 						Value:      def.Image,
 					})
-				} else if utils.StringSliceMatch(c.Deprecated, match[1]) {
+				} else if c.isDeprecated(match[1]) {
 					c.AddWarning(name + " is using deprecated image " + match[1])
 				} else {
 					c.AddPass(name + " is using valid base images")
@@ -131,3 +149,44 @@ func (c *BaseImageCheck) RunCheck() {
 	}
 
 }
+
+// isAllowed determines whether the image is allowed, either by name and
+// version constraint, e.g, php:^8.1, or by a regex ("re:") or glob ("glob:")
+// entry. When the image is only disallowed by the version constraint of its
+// entries, the reason is returned.
+func (c *BaseImageCheck) isAllowed(image string, version string) (bool, string) {
+	if utils.StringSliceMatchAny(c.Allowed, image) {
+		return true, ""
+	}
+	if version == "latest" {
+		version = ""
+	}
+	allowed, constraints, err := utils.PackageVersionAllowed(c.Allowed, image, version)
+	if err != nil {
+		return false, err.Error()
+	}
+	if allowed || len(constraints) == 0 {
+		return allowed, ""
+	}
+	if version == "" {
+		version = "latest"
+	}
+	return false, fmt.Sprintf("version %s does not satisfy '%s'",
+		version, strings.Join(constraints, "' or '"))
+}
+
+func (c *BaseImageCheck) addVersionBreach(service string, image string, reason string) {
+	c.AddBreach(&result.KeyValueBreach{
+		KeyLabel:   "service",
+		Key:        service,
+		ValueLabel: "invalid base image",
+		Value:      fmt.Sprintf("%s: %s", image, reason),
+	})
+}
+
+// isDeprecated determines whether the image contains a deprecated entry or
+// matches a regex ("re:") or glob ("glob:") one.
+func (c *BaseImageCheck) isDeprecated(image string) bool {
+	return utils.StringSliceMatch(c.Deprecated, image) ||
+		utils.StringSliceMatchAny(c.Deprecated, image)
+}
@@ -274,3 +274,56 @@ func TestDeprecatedImage(t *testing.T) {
 		c.Result.Warnings,
 	)
 }
+
+func TestImageVersionConstraints(t *testing.T) {
+	assert := assert.New(t)
+	c := docker.BaseImageCheck{
+		Allowed: []string{
+			"bitnami/kubectl:^1.25",
+			"bitnami/postgresql@>=14,<16",
+			"bitnami/postgresql@^17",
+			"bitnami/redis:^7",
+			"bitnami/mongodb:~> 5.0.19",
+		},
+		Paths: []string{"./fixtures/compose-image"},
+	}
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.ElementsMatch(
+		[]result.Breach{
+			&result.KeyValueBreach{
+				BreachType: result.BreachTypeKeyValue,
+				KeyLabel:   "service",
+				Key:        "service2",
+				ValueLabel: "invalid base image",
+				Value:      "bitnami/postgresql: version 16 does not satisfy '>=14,<16' or '^17'",
+			},
+			&result.KeyValueBreach{
+				BreachType: result.BreachTypeKeyValue,
+				KeyLabel:   "service",
+				Key:        "service3",
+				ValueLabel: "invalid base image",
+				Value:      "bitnami/redis: version {MY_VERSION} does not satisfy '^7'",
+			},
+		},
+		c.Result.Breaches,
+	)
+	assert.ElementsMatch([]string{
+		"service1 is using valid base images",
+		"service4 is using valid base images",
+	}, c.Result.Passes)
+
+	c = docker.BaseImageCheck{
+		Allowed: []string{"bitnami/kubectl:^foo"},
+		Paths:   []string{"./fixtures/compose-image"},
+	}
+	c.RunCheck()
+	assert.Contains(c.Result.Breaches, &result.KeyValueBreach{
+		BreachType: result.BreachTypeKeyValue,
+		KeyLabel:   "service",
+		Key:        "service1",
+		ValueLabel: "invalid base image",
+		Value:      "bitnami/kubectl: invalid version constraint '^foo': Malformed version: foo",
+	})
+}
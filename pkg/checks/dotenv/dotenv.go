@@ -0,0 +1,55 @@
+package dotenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=dotenv
+
+func RegisterChecks() {
+	config.ChecksRegistry[Dotenv] = func() config.Check { return &DotenvCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// Parse reads dotenv-formatted data into a map of variables.
+// Blank lines and comments are ignored, the `export` prefix is stripped and
+// surrounding quotes are removed from values.
+func Parse(data []byte) (map[string]string, error) {
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid line %d: %s", lineNum, line)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) > 1 && (value[0] == '"' || value[0] == '\'') {
+			if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+				value = value[1 : end+1]
+			}
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			// Strip inline comments for unquoted values.
+			value = strings.TrimSpace(value[:i])
+		}
+		vars[key] = value
+	}
+	return vars, scanner.Err()
+}
@@ -0,0 +1,57 @@
+package dotenv
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const Dotenv config.CheckType = "dotenv"
+
+// DotenvCheck verifies the variables defined in dotenv files, e.g, .env,
+// using the same values as the yaml check.
+type DotenvCheck struct {
+	yaml.YamlCheck `yaml:",inline"`
+}
+
+// Init implementation for the dotenv check.
+func (c *DotenvCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.File == "" && len(c.Files) == 0 && c.Pattern == "" {
+		c.File = ".env"
+	}
+}
+
+// Merge implementation for dotenv check.
+func (c *DotenvCheck) Merge(mergeCheck config.Check) error {
+	dotenvMergeCheck := mergeCheck.(*DotenvCheck)
+	return c.YamlCheck.Merge(&dotenvMergeCheck.YamlCheck)
+}
+
+// UnmarshalDataMap parses the dotenv files into Yaml nodes so that the values
+// can be verified by the YamlBase logic.
+func (c *DotenvCheck) UnmarshalDataMap() {
+	c.NodeMap = map[string]yamlv3.Node{}
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[configName]
+		vars, err := Parse(data)
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: configName,
+				Value:      err.Error()})
+			return
+		}
+
+		n := yamlv3.Node{}
+		if err := n.Encode(vars); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: configName,
+				Value:      err.Error()})
+			return
+		}
+		c.NodeMap[configName] = n
+	}
+}
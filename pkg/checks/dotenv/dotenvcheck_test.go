@@ -0,0 +1,116 @@
+package dotenv_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/dotenv"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Dotenv]()
+	assert.Equal(t, "*dotenv.DotenvCheck", reflect.TypeOf(c).String())
+}
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	vars, err := Parse([]byte(`
+# Laravel
+APP_NAME="My App"
+APP_ENV=production
+export APP_DEBUG=false # disabled
+APP_KEY=
+DB_PASSWORD='p#ss'
+`))
+	assert.NoError(err)
+	assert.Equal(map[string]string{
+		"APP_NAME":    "My App",
+		"APP_ENV":     "production",
+		"APP_DEBUG":   "false",
+		"APP_KEY":     "",
+		"DB_PASSWORD": "p#ss",
+	}, vars)
+
+	_, err = Parse([]byte("APP_ENV=prod\nAPP_DEBUG\n"))
+	assert.EqualError(err, "invalid line 2: APP_DEBUG")
+}
+
+func TestDotenvCheckInit(t *testing.T) {
+	c := DotenvCheck{}
+	c.Init(Dotenv)
+	assert.Equal(t, ".env", c.File)
+
+	c = DotenvCheck{YamlCheck: yaml.YamlCheck{Files: []string{".env.local"}}}
+	c.Init(Dotenv)
+	assert.Equal(t, "", c.File)
+}
+
+func TestDotenvCheckRunCheck(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "expectedValues",
+			Check: &DotenvCheck{YamlCheck: yaml.YamlCheck{YamlBase: yaml.YamlBase{
+				Values: []yaml.KeyValue{
+					{Key: "APP_ENV", Value: "production"},
+					{Key: "APP_DEBUG", Value: "false", Truthy: true},
+				},
+			}}},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"[.env] 'APP_ENV' equals 'production'",
+				"[.env] 'APP_DEBUG' equals 'false'",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "unexpectedValues",
+			Check: &DotenvCheck{YamlCheck: yaml.YamlCheck{YamlBase: yaml.YamlBase{
+				Values: []yaml.KeyValue{
+					{Key: "APP_ENV", Value: "local"},
+					{Key: "APP_URL", Value: "https://example.com"},
+				},
+			}}},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "dotenv",
+					Severity:      "normal",
+					KeyLabel:      "config:.env",
+					Key:           "APP_ENV",
+					ValueLabel:    "actual",
+					Value:         "production",
+					ExpectedValue: "local",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "dotenv",
+					Severity:   "normal",
+					KeyLabel:   "config",
+					Key:        ".env",
+					ValueLabel: "key not found",
+					Value:      "APP_URL",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Dotenv)
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
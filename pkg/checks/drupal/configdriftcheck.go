@@ -0,0 +1,129 @@
+package drupal
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const ConfigDrift config.CheckType = "drupal-config-drift"
+
+// ConfigDriftCheck verifies that the active config matches the exported
+// config in the sync directory.
+type ConfigDriftCheck struct {
+	config.CheckBase `yaml:",inline"`
+	DrushCommand     `yaml:",inline"`
+	// List of config names expected to differ, e.g, environment-specific
+	// overrides. Wildcards are supported, e.g, 'environment_indicator.*'.
+	Allowed []string `yaml:"allowed"`
+	drift   map[string]string
+}
+
+// Init implementation for the drush-based config drift check.
+func (c *ConfigDriftCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	c.RequiresDb = true
+}
+
+// Merge implementation for ConfigDriftCheck check.
+func (c *ConfigDriftCheck) Merge(mergeCheck config.Check) error {
+	configDriftMergeCheck := mergeCheck.(*ConfigDriftCheck)
+	if err := c.CheckBase.Merge(&configDriftMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.DrushCommand.Merge(configDriftMergeCheck.DrushCommand)
+	utils.MergeStringSlice(&c.Allowed, configDriftMergeCheck.Allowed)
+	return nil
+}
+
+// FetchData runs the drush command to populate data for the config drift
+// check.
+func (c *ConfigDriftCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	// Command: drush config:status --format=json
+	cmd := []string{"config:status", "--format=json"}
+	c.DataMap["config-status"], err = Drush(c.DrushPath, c.Alias, cmd).Exec()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+	}
+}
+
+// UnmarshalDataMap parses the drush config:status json into the drift map
+// for further processing.
+func (c *ConfigDriftCheck) UnmarshalDataMap() {
+	c.drift = map[string]string{}
+	// No output means there are no differences.
+	if len(c.DataMap["config-status"]) == 0 {
+		return
+	}
+
+	// Unmarshal config:status JSON.
+	// {
+	//    "system.site": {
+	//        "name": "system.site",
+	//        "state": "Different"
+	//    }
+	// }
+	statusMap := map[string]map[string]string{}
+	if err := json.Unmarshal(c.DataMap["config-status"], &statusMap); err != nil {
+		// An empty list is returned by some versions when in sync.
+		var emptyList []any
+		if json.Unmarshal(c.DataMap["config-status"], &emptyList) == nil {
+			return
+		}
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+		return
+	}
+	for name, fields := range statusMap {
+		c.drift[name] = fields["state"]
+	}
+}
+
+// RunCheck implements the Check logic for config drift.
+func (c *ConfigDriftCheck) RunCheck() {
+	names := []string{}
+	for name := range c.drift {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if c.isAllowed(name) {
+			continue
+		}
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "config",
+			Key:        name,
+			ValueLabel: "state",
+			Value:      c.drift[name],
+		})
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+		c.AddPass("active config matches the exported config")
+	}
+}
+
+// isAllowed determines whether the config name matches an allowed pattern.
+func (c *ConfigDriftCheck) isAllowed(name string) bool {
+	for _, pattern := range c.Allowed {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Commands implements config.CommandReporter.
+func (c *ConfigDriftCheck) Commands() [][]string {
+	cmd := []string{"config:status", "--format=json"}
+	return [][]string{Drush(c.DrushPath, c.Alias, cmd).Line()}
+}
@@ -0,0 +1,162 @@
+package drupal_test
+
+import (
+	"os/exec"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDriftCheckInit(t *testing.T) {
+	c := ConfigDriftCheck{}
+	c.Init(ConfigDrift)
+	assert.True(t, c.RequiresDb)
+}
+
+func TestConfigDriftMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ConfigDriftCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush"},
+		Allowed:      []string{"system.performance"},
+	}
+	c.Merge(&ConfigDriftCheck{
+		DrushCommand: DrushCommand{Alias: "@prod"},
+		Allowed:      []string{"environment_indicator.*"},
+	})
+	assert.EqualValues(ConfigDriftCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush", Alias: "@prod"},
+		Allowed:      []string{"environment_indicator.*"},
+	}, c)
+}
+
+func TestConfigDriftFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	t.Run("drushError", func(t *testing.T) {
+		command.ShellCommander = internal.ShellCommanderMaker(
+			nil,
+			&exec.ExitError{Stderr: []byte("unable to run drush command")},
+			nil)
+		c := ConfigDriftCheck{}
+		c.FetchData()
+		assert.EqualValues(
+			[]result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				Value:      "unable to run drush command",
+			}},
+			c.Result.Breaches,
+		)
+	})
+
+	t.Run("drushCommandIsCorrect", func(t *testing.T) {
+		var generatedCommand string
+		command.ShellCommander = internal.ShellCommanderMaker(
+			&[]string{`{"system.site":{"name":"system.site","state":"Different"}}`}[0],
+			nil,
+			&generatedCommand)
+		c := ConfigDriftCheck{}
+		c.FetchData()
+		assert.Empty(c.Result.Breaches)
+		assert.Equal("vendor/drush/drush/drush config:status --format=json", generatedCommand)
+	})
+}
+
+func TestConfigDriftUnmarshalDataMap(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ConfigDriftCheck{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{"config-status": []byte(`{"system.site":`)},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "unexpected end of JSON input",
+		}},
+		c.Result.Breaches,
+	)
+
+	c = ConfigDriftCheck{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{"config-status": []byte(`[]`)},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.Empty(c.Result.Breaches)
+}
+
+func TestConfigDriftRunCheck(t *testing.T) {
+	statusData := []byte(`
+{
+	"system.site": {"name": "system.site", "state": "Different"},
+	"system.performance": {"name": "system.performance", "state": "Different"},
+	"environment_indicator.indicator": {"name": "environment_indicator.indicator", "state": "Only in DB"},
+	"views.view.old": {"name": "views.view.old", "state": "Only in sync dir"}
+}`)
+
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "noDrift",
+			Check:        &ConfigDriftCheck{},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"active config matches the exported config"},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "allowedDrift",
+			Check: &ConfigDriftCheck{
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"config-status": statusData},
+				},
+				Allowed: []string{"system.*", "environment_indicator.*", "views.view.old"},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"active config matches the exported config"},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "drift",
+			Check: &ConfigDriftCheck{
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"config-status": statusData},
+				},
+				Allowed: []string{"system.performance", "environment_indicator.*"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "config",
+					Key:        "system.site",
+					ValueLabel: "state",
+					Value:      "Different",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "config",
+					Key:        "views.view.old",
+					ValueLabel: "state",
+					Value:      "Only in sync dir",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		tc.Check.UnmarshalDataMap()
+		internal.TestRunCheck(t, tc)
+	}
+}
@@ -0,0 +1,122 @@
+package drupal
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Cron config.CheckType = "drupal-cron"
+
+const DefaultCronMaxAge = "24h"
+
+// CronCheck verifies that the site's cron has run recently, using the
+// system.cron_last state reported by drush.
+type CronCheck struct {
+	config.CheckBase `yaml:",inline"`
+	DrushCommand     `yaml:",inline"`
+	// Maximum time since the last cron run, e.g, 3h or 2d.
+	MaxAge  string `yaml:"max-age"`
+	lastRun time.Time
+}
+
+// Init implementation for the drush-based cron check.
+func (c *CronCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	c.RequiresDb = true
+	if c.MaxAge == "" {
+		c.MaxAge = DefaultCronMaxAge
+	}
+}
+
+// Merge implementation for CronCheck check.
+func (c *CronCheck) Merge(mergeCheck config.Check) error {
+	cronMergeCheck := mergeCheck.(*CronCheck)
+	if err := c.CheckBase.Merge(&cronMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.DrushCommand.Merge(cronMergeCheck.DrushCommand)
+	utils.MergeString(&c.MaxAge, cronMergeCheck.MaxAge)
+	return nil
+}
+
+func (c *CronCheck) args() []string {
+	return []string{"state:get", "system.cron_last", "--format=json"}
+}
+
+// FetchData runs the drush command to populate data for the cron check.
+func (c *CronCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	// Command: drush state:get system.cron_last --format=json
+	c.DataMap["cron-last"], err = Drush(c.DrushPath, c.Alias, c.args()).Exec()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+	}
+}
+
+// UnmarshalDataMap parses the timestamp of the last cron run; it is left
+// empty if cron has never run.
+func (c *CronCheck) UnmarshalDataMap() {
+	c.lastRun = time.Time{}
+	data := string(bytes.Trim(bytes.TrimSpace(c.DataMap["cron-last"]), `"`))
+	// Drush outputs null, or nothing, when the state is not set.
+	if data == "" || data == "null" {
+		return
+	}
+	ts, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid cron last run",
+			Value:      data,
+		})
+		return
+	}
+	c.lastRun = time.Unix(ts, 0)
+}
+
+// RunCheck verifies the time since the last cron run against the max age.
+func (c *CronCheck) RunCheck() {
+	maxAge, err := utils.ParseDuration(c.MaxAge)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid max-age",
+			Value:      err.Error(),
+		})
+		return
+	}
+
+	if c.lastRun.IsZero() {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel:    "cron last run",
+			ExpectedValue: "within " + c.MaxAge,
+			Value:         "never",
+		})
+		return
+	}
+
+	age := utils.TimeNow().Sub(c.lastRun)
+	if age > maxAge {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel:    "cron last run",
+			ExpectedValue: "within " + c.MaxAge,
+			Value: fmt.Sprintf("%s ago (%s)", utils.HumanizeDuration(age),
+				c.lastRun.UTC().Format(time.RFC3339)),
+		})
+		return
+	}
+	c.AddPass(fmt.Sprintf("cron last ran %s ago", utils.HumanizeDuration(age)))
+	c.Result.Status = result.Pass
+}
+
+// Commands implements config.CommandReporter.
+func (c *CronCheck) Commands() [][]string {
+	return [][]string{Drush(c.DrushPath, c.Alias, c.args()).Line()}
+}
@@ -0,0 +1,176 @@
+package drupal_test
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := CronCheck{}
+	c.Init(Cron)
+	assert.True(c.RequiresDb)
+	assert.Equal("24h", c.MaxAge)
+
+	c = CronCheck{MaxAge: "3h"}
+	c.Init(Cron)
+	assert.Equal("3h", c.MaxAge)
+}
+
+func TestCronCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := CronCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush"},
+		MaxAge:       "24h",
+	}
+	err := c.Merge(&CronCheck{
+		DrushCommand: DrushCommand{Alias: "prod"},
+		MaxAge:       "3h",
+	})
+	assert.NoError(err)
+	assert.Equal("/path/to/drush", c.DrushPath)
+	assert.Equal("prod", c.Alias)
+	assert.Equal("3h", c.MaxAge)
+}
+
+func TestCronCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil,
+		&exec.ExitError{Stderr: []byte("unable to run drush command")},
+		nil)
+	c := CronCheck{}
+	c.Init(Cron)
+	c.FetchData()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			CheckType:  "drupal-cron",
+			Severity:   "normal",
+			Value:      "unable to run drush command",
+		}},
+		c.Result.Breaches,
+	)
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{"1792144800\n"}[0], nil, &generatedCommand)
+	c = CronCheck{DrushCommand: DrushCommand{Alias: "prod"}}
+	c.Init(Cron)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("vendor/drush/drush/drush @prod state:get system.cron_last --format=json", generatedCommand)
+	assert.Equal([]byte("1792144800\n"), c.DataMap["cron-last"])
+	assert.Equal([][]string{{"vendor/drush/drush/drush", "@prod", "state:get",
+		"system.cron_last", "--format=json"}}, c.Commands())
+}
+
+func TestCronCheckRunCheck(t *testing.T) {
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	// 2026-10-16T12:00:00Z
+	utils.TimeNow = func() time.Time { return time.Unix(1792152000, 0) }
+
+	newCheck := func(maxAge string, data string) *CronCheck {
+		return &CronCheck{
+			CheckBase: config.CheckBase{
+				DataMap: map[string][]byte{"cron-last": []byte(data)},
+			},
+			MaxAge: maxAge,
+		}
+	}
+
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "recent",
+			Check:        newCheck("", "1792144800\n"),
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"cron last ran 2 hours ago"},
+			ExpectNoFail: true,
+		},
+		{
+			Name:         "stale",
+			Check:        newCheck("1h", "1792144800"),
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType:    "value",
+				CheckType:     "drupal-cron",
+				Severity:      "normal",
+				ValueLabel:    "cron last run",
+				ExpectedValue: "within 1h",
+				Value:         "2 hours ago (2026-10-16T10:00:00Z)",
+			}},
+		},
+		{
+			Name:         "neverRun",
+			Check:        newCheck("", "null\n"),
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType:    "value",
+				CheckType:     "drupal-cron",
+				Severity:      "normal",
+				ValueLabel:    "cron last run",
+				ExpectedValue: "within 24h",
+				Value:         "never",
+			}},
+		},
+		{
+			Name:         "invalidMaxAge",
+			Check:        newCheck("daily", "1792144800"),
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "drupal-cron",
+				Severity:   "normal",
+				ValueLabel: "invalid max-age",
+				Value:      `time: invalid duration "daily"`,
+			}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Cron)
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
+
+func TestCronCheckUnmarshalDataMap(t *testing.T) {
+	assert := assert.New(t)
+
+	c := CronCheck{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{"cron-last": []byte(`"yesterday"`)},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			ValueLabel: "invalid cron last run",
+			Value:      "yesterday",
+		}},
+		c.Result.Breaches,
+	)
+}
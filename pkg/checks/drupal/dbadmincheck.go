@@ -109,7 +109,8 @@ func (c *AdminUserCheck) UnmarshalDataMap() {
 	}
 
 	c.roleConfigs = map[string]bool{}
-	for _, element := range c.DataMap {
+	for _, name := range utils.SortedKeys(c.DataMap) {
+		element := c.DataMap[name]
 		var role roleConf
 		err := json.Unmarshal([]byte(element), &role)
 		var synErr *json.SyntaxError
@@ -124,7 +125,8 @@ func (c *AdminUserCheck) UnmarshalDataMap() {
 
 // RunCheck implements the Check logic for all active roles.
 func (c *AdminUserCheck) RunCheck() {
-	for roleName, isAdmin := range c.roleConfigs {
+	for _, roleName := range utils.SortedKeys(c.roleConfigs) {
+		isAdmin := c.roleConfigs[roleName]
 		allowedRole := utils.StringSliceContains(c.AllowedRoles, roleName)
 		if allowedRole {
 			continue
@@ -159,3 +161,11 @@ func (c *AdminUserCheck) Remediate() {
 		}
 	}
 }
+
+// Commands implements config.CommandReporter.
+func (c *AdminUserCheck) Commands() [][]string {
+	return [][]string{
+		Drush(c.DrushPath, c.Alias, []string{"role:list", "--fields=.", "--format=json"}).Line(),
+		Drush(c.DrushPath, c.Alias, []string{"cget", "user.role.<role>", "--format=json"}).Line(),
+	}
+}
@@ -54,7 +54,8 @@ func (c *DbPermissionsCheck) RunCheck() {
 		c.AddBreach(&result.ValueBreach{Value: "list of disallowed perms not provided"})
 	}
 
-	for r, perms := range c.Permissions {
+	for _, r := range utils.SortedKeys(c.Permissions) {
+		perms := c.Permissions[r]
 		if utils.StringSliceContains(c.ExcludeRoles, r) {
 			continue
 		}
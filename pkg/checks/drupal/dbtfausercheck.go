@@ -28,11 +28,11 @@ func (c *DbUserTfaCheck) Init(ct config.CheckType) {
 	c.RequiresDb = true
 }
 
-// FetchData runs the Drush command to extract user information from the Drupal database.
-func (c *DbUserTfaCheck) FetchData() {
-	cmd := []string{
-		"ev",
-		`return \\Drupal::database()->query(
+// dbUserTfaArgs are the drush arguments listing the active users without
+// tfa.
+var dbUserTfaArgs = []string{
+	"ev",
+	`return \\Drupal::database()->query(
 			\"SELECT users.uid, users_field_data.name
 				FROM users
 				LEFT JOIN users_field_data
@@ -44,8 +44,11 @@ func (c *DbUserTfaCheck) FetchData() {
 						FROM users_data
 						WHERE users.uid = users_data.uid
 						 	AND users_data.module = 'tfa');\")->fetchAll()`,
-		"--format=json"}
-	res, err := Drush(c.DrushPath, c.Alias, cmd).Exec()
+	"--format=json"}
+
+// FetchData runs the Drush command to extract user information from the Drupal database.
+func (c *DbUserTfaCheck) FetchData() {
+	res, err := Drush(c.DrushPath, c.Alias, dbUserTfaArgs).Exec()
 	if err != nil {
 		c.Result.Status = result.Fail
 		c.AddBreach(&result.ValueBreach{
@@ -85,3 +88,8 @@ func (c *DbUserTfaCheck) RunCheck() {
 func (c *DbUserTfaCheck) Merge(mergeCheck config.Check) error {
 	return nil
 }
+
+// Commands implements config.CommandReporter.
+func (c *DbUserTfaCheck) Commands() [][]string {
+	return [][]string{Drush(c.DrushPath, c.Alias, dbUserTfaArgs).Line()}
+}
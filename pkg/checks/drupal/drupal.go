@@ -24,6 +24,14 @@ func RegisterChecks() {
 	config.ChecksRegistry[AdminUser] = func() config.Check { return &AdminUserCheck{} }
 	config.ChecksRegistry[DbUserTfa] = func() config.Check { return &DbUserTfaCheck{} }
 	config.ChecksRegistry[ForbiddenUser] = func() config.Check { return &ForbiddenUserCheck{} }
+	config.ChecksRegistry[PermissionsMatrix] = func() config.Check { return &PermissionsMatrixCheck{} }
+	config.ChecksRegistry[ViewsAccess] = func() config.Check { return &ViewsAccessCheck{} }
+	config.ChecksRegistry[ConfigDrift] = func() config.Check { return &ConfigDriftCheck{} }
+	config.ChecksRegistry[Status] = func() config.Check { return &StatusCheck{} }
+	config.ChecksRegistry[StatusReport] = func() config.Check { return &StatusReportCheck{} }
+	config.ChecksRegistry[Watchdog] = func() config.Check { return &WatchdogCheck{} }
+	config.ChecksRegistry[Cron] = func() config.Check { return &CronCheck{} }
+	config.ChecksRegistry[DrushCheckType] = func() config.Check { return &DrushCheck{} }
 }
 
 func init() {
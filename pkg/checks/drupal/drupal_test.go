@@ -15,14 +15,22 @@ import (
 
 func TestRegisterChecks(t *testing.T) {
 	checksMap := map[config.CheckType]string{
-		DrushYaml:     "*drupal.DrushYamlCheck",
-		FileModule:    "*drupal.FileModuleCheck",
-		DbModule:      "*drupal.DbModuleCheck",
-		DbPermissions: "*drupal.DbPermissionsCheck",
-		TrackingCode:  "*drupal.TrackingCodeCheck",
-		UserRole:      "*drupal.UserRoleCheck",
-		AdminUser:     "*drupal.AdminUserCheck",
-		DbUserTfa:     "*drupal.DbUserTfaCheck",
+		DrushYaml:         "*drupal.DrushYamlCheck",
+		FileModule:        "*drupal.FileModuleCheck",
+		DbModule:          "*drupal.DbModuleCheck",
+		DbPermissions:     "*drupal.DbPermissionsCheck",
+		TrackingCode:      "*drupal.TrackingCodeCheck",
+		UserRole:          "*drupal.UserRoleCheck",
+		AdminUser:         "*drupal.AdminUserCheck",
+		DbUserTfa:         "*drupal.DbUserTfaCheck",
+		PermissionsMatrix: "*drupal.PermissionsMatrixCheck",
+		ViewsAccess:       "*drupal.ViewsAccessCheck",
+		ConfigDrift:       "*drupal.ConfigDriftCheck",
+		Status:            "*drupal.StatusCheck",
+		StatusReport:      "*drupal.StatusReportCheck",
+		Watchdog:          "*drupal.WatchdogCheck",
+		Cron:              "*drupal.CronCheck",
+		DrushCheckType:    "*drupal.DrushCheck",
 	}
 	for ct, ts := range checksMap {
 		c := config.ChecksRegistry[ct]()
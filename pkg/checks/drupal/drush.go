@@ -37,6 +37,15 @@ func (cmd *DrushCommand) Exec() ([]byte, error) {
 	return command.ShellCommander(cmd.DrushPath, cmd.Args...).Output()
 }
 
+// Line returns the command run by Exec, without running it.
+func (cmd *DrushCommand) Line() []string {
+	line := []string{cmd.DrushPath}
+	if cmd.Alias != "" {
+		line = append(line, "@"+cmd.Alias)
+	}
+	return append(line, cmd.Args...)
+}
+
 // Query runs the drush sql:query command and returns the output.
 func (cmd *DrushCommand) Query(qry string) ([]byte, error) {
 	cmd.Args = []string{"sql:query", qry}
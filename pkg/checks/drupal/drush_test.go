@@ -52,6 +52,15 @@ func TestDrushExec(t *testing.T) {
 
 }
 
+func TestDrushLine(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"/path/to/drush", "status"},
+		drupal.Drush("/path/to/drush", "", []string{"status"}).Line())
+	assert.Equal([]string{"/path/to/drush", "@local", "status"},
+		drupal.Drush("/path/to/drush", "local", []string{"status"}).Line())
+}
+
 func TestDrushQuery(t *testing.T) {
 	curShellCommander := command.ShellCommander
 	defer func() { command.ShellCommander = curShellCommander }()
@@ -0,0 +1,210 @@
+package drupal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const DrushCheckType config.CheckType = "drush"
+
+// DrushSubcommand holds how one of the drush commands supported by the drush
+// check is run and how its json output is normalised into a map.
+type DrushSubcommand struct {
+	// Determines the command's arguments, apart from the format, from the
+	// check's fields.
+	Args func(c *DrushCheck) ([]string, error)
+	// Normalises the command's json output; the output is used as is if nil.
+	Normalise func(c *DrushCheck, data any) (any, error)
+}
+
+// DrushSubcommands is the list of commands supported by the drush check.
+var DrushSubcommands = map[string]DrushSubcommand{
+	"pm:list": {
+		Args: func(c *DrushCheck) ([]string, error) {
+			args := []string{"pm:list"}
+			if c.ExtensionStatus != "" {
+				args = append(args, "--status="+c.ExtensionStatus)
+			}
+			if c.ExtensionType != "" {
+				args = append(args, "--type="+c.ExtensionType)
+			}
+			return args, nil
+		},
+	},
+	"config:get": {
+		Args: func(c *DrushCheck) ([]string, error) {
+			if c.ConfigName == "" {
+				return nil, fmt.Errorf("config-name is required for config:get")
+			}
+			return []string{"config:get", c.ConfigName}, nil
+		},
+	},
+	"state:get": {
+		Args: func(c *DrushCheck) ([]string, error) {
+			if c.StateKey == "" {
+				return nil, fmt.Errorf("state-key is required for state:get")
+			}
+			return []string{"state:get", c.StateKey}, nil
+		},
+		// The value is keyed by the state key, e.g, system.cron_last: 1792144800.
+		Normalise: func(c *DrushCheck, data any) (any, error) {
+			return map[string]any{c.StateKey: data}, nil
+		},
+	},
+	"user:information": {
+		Args: func(c *DrushCheck) ([]string, error) {
+			if len(c.Users) == 0 {
+				return nil, fmt.Errorf("users is required for user:information")
+			}
+			return []string{"user:information", strings.Join(c.Users, ",")}, nil
+		},
+		// Users are keyed by uid in drush's output, and by name here, e.g,
+		// admin.user_status.
+		Normalise: func(c *DrushCheck, data any) (any, error) {
+			users, ok := data.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("unexpected user:information output")
+			}
+			byName := map[string]any{}
+			for uid, u := range users {
+				user, ok := u.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("unexpected user:information output for uid %s", uid)
+				}
+				name, _ := user["name"].(string)
+				if name == "" {
+					name = uid
+				}
+				byName[name] = user
+			}
+			return byName, nil
+		},
+	},
+}
+
+// DrushCheck runs one of the supported drush commands, e.g, pm:list, and
+// verifies its output, normalised into a map, using the same values as the
+// yaml check; the data can also be published for other checks to consume.
+type DrushCheck struct {
+	yaml.YamlBase `yaml:",inline"`
+	DrushCommand  `yaml:",inline"`
+	// One of the DrushSubcommands keys, e.g, pm:list.
+	Command string `yaml:"command"`
+	// Status (enabled or disabled) & type (module or theme) of the extensions
+	// listed by pm:list.
+	ExtensionStatus string `yaml:"extension-status"`
+	ExtensionType   string `yaml:"extension-type"`
+	// Name of the config read by config:get, e.g, system.performance.
+	ConfigName string `yaml:"config-name"`
+	// Key of the state read by state:get, e.g, system.maintenance_mode.
+	StateKey string `yaml:"state-key"`
+	// Names of the users read by user:information.
+	Users []string `yaml:"users"`
+}
+
+// Init implementation for the drush check.
+func (c *DrushCheck) Init(ct config.CheckType) {
+	c.YamlBase.Init(ct)
+	c.RequiresDb = true
+}
+
+// Merge implementation for the drush check.
+func (c *DrushCheck) Merge(mergeCheck config.Check) error {
+	drushMergeCheck := mergeCheck.(*DrushCheck)
+	if err := c.YamlBase.Merge(&drushMergeCheck.YamlBase); err != nil {
+		return err
+	}
+
+	c.DrushCommand.Merge(drushMergeCheck.DrushCommand)
+	utils.MergeString(&c.Command, drushMergeCheck.Command)
+	utils.MergeString(&c.ExtensionStatus, drushMergeCheck.ExtensionStatus)
+	utils.MergeString(&c.ExtensionType, drushMergeCheck.ExtensionType)
+	utils.MergeString(&c.ConfigName, drushMergeCheck.ConfigName)
+	utils.MergeString(&c.StateKey, drushMergeCheck.StateKey)
+	utils.MergeStringSlice(&c.Users, drushMergeCheck.Users)
+	return nil
+}
+
+// drushArgs determines the arguments of the drush command.
+func (c *DrushCheck) drushArgs() ([]string, error) {
+	sub, ok := DrushSubcommands[c.Command]
+	if !ok {
+		return nil, fmt.Errorf("unsupported command '%s'", c.Command)
+	}
+	args, err := sub.Args(c)
+	if err != nil {
+		return nil, err
+	}
+	return append(args, "--format=json"), nil
+}
+
+// FetchData runs the drush command and stores its normalised output as yaml
+// in the DataMap, keyed by the command.
+func (c *DrushCheck) FetchData() {
+	c.DataMap = map[string][]byte{}
+	args, err := c.drushArgs()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid drush command",
+			Value:      err.Error()})
+		return
+	}
+
+	out, err := Drush(c.DrushPath, c.Alias, args).Exec()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: c.Command,
+			Value:      command.GetMsgFromCommandError(err)})
+		return
+	}
+
+	data, err := c.normalise(out)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to parse " + c.Command + " output",
+			Value:      err.Error()})
+		return
+	}
+	c.DataMap[c.Command] = data
+}
+
+// normalise converts the command's json output into the yaml data verified
+// by the check.
+func (c *DrushCheck) normalise(out []byte) ([]byte, error) {
+	var data any = map[string]any{}
+	// Empty lists are output by drush as [], and missing states as nothing.
+	trimmed := bytes.TrimSpace(out)
+	if len(trimmed) > 0 && string(trimmed) != "[]" {
+		if err := json.Unmarshal(trimmed, &data); err != nil {
+			return nil, err
+		}
+	} else if c.Command == "state:get" {
+		data = nil
+	}
+	if normalise := DrushSubcommands[c.Command].Normalise; normalise != nil {
+		var err error
+		if data, err = normalise(c, data); err != nil {
+			return nil, err
+		}
+	}
+	return yamlv3.Marshal(data)
+}
+
+// Commands implements config.CommandReporter.
+func (c *DrushCheck) Commands() [][]string {
+	args, err := c.drushArgs()
+	if err != nil {
+		return nil
+	}
+	return [][]string{Drush(c.DrushPath, c.Alias, args).Line()}
+}
@@ -0,0 +1,215 @@
+package drupal_test
+
+import (
+	"os/exec"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrushCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := DrushCheck{}
+	c.Init(DrushCheckType)
+	assert.True(c.RequiresDb)
+	assert.Equal("drush", c.Result.CheckType)
+}
+
+func TestDrushCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := DrushCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush"},
+		Command:      "pm:list",
+		Users:        []string{"admin"},
+	}
+	err := c.Merge(&DrushCheck{
+		DrushCommand:    DrushCommand{Alias: "prod"},
+		ExtensionStatus: "enabled",
+		Users:           []string{"editor"},
+	})
+	assert.NoError(err)
+	assert.Equal("/path/to/drush", c.DrushPath)
+	assert.Equal("prod", c.Alias)
+	assert.Equal("pm:list", c.Command)
+	assert.Equal("enabled", c.ExtensionStatus)
+	assert.Equal([]string{"editor"}, c.Users)
+}
+
+func TestDrushCheckFetchData(t *testing.T) {
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	tt := []struct {
+		name            string
+		check           DrushCheck
+		output          string
+		err             error
+		expectCommand   string
+		expectData      string
+		expectBreachVal string
+	}{
+		{
+			name:            "unsupportedCommand",
+			check:           DrushCheck{Command: "sql:query"},
+			expectBreachVal: "unsupported command 'sql:query'",
+		},
+		{
+			name:            "missingConfigName",
+			check:           DrushCheck{Command: "config:get"},
+			expectBreachVal: "config-name is required for config:get",
+		},
+		{
+			name:            "missingUsers",
+			check:           DrushCheck{Command: "user:information"},
+			expectBreachVal: "users is required for user:information",
+		},
+		{
+			name:            "commandError",
+			check:           DrushCheck{Command: "pm:list"},
+			err:             &exec.ExitError{Stderr: []byte("unable to run drush command")},
+			expectCommand:   "vendor/drush/drush/drush pm:list --format=json",
+			expectBreachVal: "unable to run drush command",
+		},
+		{
+			name:            "invalidOutput",
+			check:           DrushCheck{Command: "pm:list"},
+			output:          "not json",
+			expectCommand:   "vendor/drush/drush/drush pm:list --format=json",
+			expectBreachVal: "invalid character 'o' in literal null (expecting 'u')",
+		},
+		{
+			name: "pmList",
+			check: DrushCheck{
+				DrushCommand:    DrushCommand{Alias: "prod"},
+				Command:         "pm:list",
+				ExtensionStatus: "enabled",
+				ExtensionType:   "module",
+			},
+			output:        `{"node":{"package":"Core","display_name":"Node (node)","status":"Enabled","version":"10.3.1"}}`,
+			expectCommand: "vendor/drush/drush/drush @prod pm:list --status=enabled --type=module --format=json",
+			expectData: `node:
+    display_name: Node (node)
+    package: Core
+    status: Enabled
+    version: 10.3.1
+`,
+		},
+		{
+			name:          "pmListEmpty",
+			check:         DrushCheck{Command: "pm:list"},
+			output:        "[]\n",
+			expectCommand: "vendor/drush/drush/drush pm:list --format=json",
+			expectData:    "{}\n",
+		},
+		{
+			name:          "configGet",
+			check:         DrushCheck{Command: "config:get", ConfigName: "system.performance"},
+			output:        `{"css":{"preprocess":true},"js":{"preprocess":false}}`,
+			expectCommand: "vendor/drush/drush/drush config:get system.performance --format=json",
+			expectData: `css:
+    preprocess: true
+js:
+    preprocess: false
+`,
+		},
+		{
+			name:          "stateGet",
+			check:         DrushCheck{Command: "state:get", StateKey: "system.maintenance_mode"},
+			output:        "1\n",
+			expectCommand: "vendor/drush/drush/drush state:get system.maintenance_mode --format=json",
+			expectData:    "system.maintenance_mode: 1\n",
+		},
+		{
+			name:          "stateGetMissing",
+			check:         DrushCheck{Command: "state:get", StateKey: "system.maintenance_mode"},
+			output:        "",
+			expectCommand: "vendor/drush/drush/drush state:get system.maintenance_mode --format=json",
+			expectData:    "system.maintenance_mode: null\n",
+		},
+		{
+			name:          "userInformation",
+			check:         DrushCheck{Command: "user:information", Users: []string{"admin", "editor"}},
+			output:        `{"1":{"uid":"1","name":"admin","user_status":"0"},"2":{"uid":"2","name":"editor","user_status":"1"}}`,
+			expectCommand: "vendor/drush/drush/drush user:information admin,editor --format=json",
+			expectData: `admin:
+    name: admin
+    uid: "1"
+    user_status: "0"
+editor:
+    name: editor
+    uid: "2"
+    user_status: "1"
+`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var generatedCommand string
+			command.ShellCommander = internal.ShellCommanderMaker(
+				&tc.output, tc.err, &generatedCommand)
+			c := tc.check
+			c.Init(DrushCheckType)
+			c.FetchData()
+			assert.Equal(tc.expectCommand, generatedCommand)
+			if tc.expectBreachVal != "" {
+				assert.Len(c.Result.Breaches, 1)
+				assert.Equal(tc.expectBreachVal,
+					c.Result.Breaches[0].(*result.ValueBreach).Value)
+				assert.Empty(c.DataMap)
+				return
+			}
+			assert.Empty(c.Result.Breaches)
+			assert.Equal(tc.expectData, string(c.DataMap[c.Command]))
+		})
+	}
+}
+
+func TestDrushCheckCommands(t *testing.T) {
+	assert := assert.New(t)
+
+	c := DrushCheck{
+		DrushCommand: DrushCommand{Alias: "prod"},
+		Command:      "config:get",
+		ConfigName:   "system.site",
+	}
+	assert.Equal([][]string{{"vendor/drush/drush/drush", "@prod", "config:get",
+		"system.site", "--format=json"}}, c.Commands())
+
+	c = DrushCheck{Command: "config:get"}
+	assert.Nil(c.Commands())
+}
+
+func TestDrushCheckRunCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{`{"node":{"status":"Enabled"},"devel":{"status":"Enabled"}}`}[0],
+		nil, nil)
+	c := DrushCheck{
+		YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+			{Key: "node.status", Value: "Enabled"},
+			{Key: "devel.status", Value: "Disabled"},
+		}},
+		Command: "pm:list",
+	}
+	c.Init(DrushCheckType)
+	c.FetchData()
+	c.UnmarshalDataMap()
+	c.RunCheck()
+	assert.Len(c.Result.Breaches, 1)
+	assert.Len(c.Result.Passes, 1)
+}
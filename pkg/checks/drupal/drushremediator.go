@@ -0,0 +1,133 @@
+package drupal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikolalohinski/gonja/v2"
+	"github.com/nikolalohinski/gonja/v2/exec"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// DrushRemediator remediates breaches by running drush commands. The commands
+// are Jinja templates rendered for each breach value, with the `key` and
+// `value` variables available; commands rendered empty are skipped.
+// The variables are shell-quoted when needed, so that each is passed as a
+// single argument once the rendered command is split into arguments.
+type DrushRemediator struct {
+	// Drush commands to run, e.g, `pm:uninstall {{ value }} -y`.
+	Commands []string `yaml:"drush"`
+	// Report the commands instead of running them.
+	DryRun bool `yaml:"dry-run"`
+	// Files changed by the commands, relative to the project directory; the
+	// changes are attached to the remediation as a diff.
+	Files []string `yaml:"files"`
+}
+
+// Merge implementation for DrushRemediator.
+func (r *DrushRemediator) Merge(mergeRemediator DrushRemediator) {
+	utils.MergeStringSlice(&r.Commands, mergeRemediator.Commands)
+	if mergeRemediator.DryRun {
+		r.DryRun = true
+	}
+	utils.MergeStringSlice(&r.Files, mergeRemediator.Files)
+}
+
+// Remediate runs the commands for each of the breaches using the provided
+// drush command's path and alias, capturing the output as remediation
+// messages. Breaches remain unremediated in dry-run mode.
+func (r *DrushRemediator) Remediate(drush DrushCommand, breaches []result.Breach) {
+	for _, b := range breaches {
+		key, values := breachKeyValues(b)
+		remediation := b.GetRemediation()
+		remediation.Messages = []string{}
+		snapshot := utils.SnapshotFiles(config.ProjectDir, r.Files)
+		succeeded, failed := 0, 0
+		for _, v := range values {
+			for _, tpl := range r.Commands {
+				cmd, err := renderCommand(tpl, key, v)
+				if err != nil {
+					failed++
+					remediation.Messages = append(remediation.Messages, fmt.Sprintf(
+						"error rendering command '%s': %s", tpl, err))
+					continue
+				}
+				if cmd == "" {
+					continue
+				}
+				if r.DryRun {
+					remediation.Messages = append(remediation.Messages,
+						"[dry-run] drush "+cmd)
+					continue
+				}
+
+				args, err := utils.ShellSplit(cmd)
+				if err != nil {
+					failed++
+					remediation.Messages = append(remediation.Messages, fmt.Sprintf(
+						"error parsing command '%s': %s", cmd, err))
+					continue
+				}
+				out, err := Drush(drush.DrushPath, drush.Alias, args).Exec()
+				if err != nil {
+					failed++
+					remediation.Messages = append(remediation.Messages, fmt.Sprintf(
+						"drush %s failed: %s", cmd,
+						strings.TrimSpace(command.GetMsgFromCommandError(err))))
+					continue
+				}
+				succeeded++
+				msg := fmt.Sprintf("drush %s ran successfully", cmd)
+				if output := strings.TrimSpace(string(out)); output != "" {
+					msg += ": " + output
+				}
+				remediation.Messages = append(remediation.Messages, msg)
+			}
+		}
+
+		remediation.Diff = snapshot.Diff()
+
+		switch {
+		case succeeded == 0 && failed == 0:
+			remediation.Status = result.RemediationStatusNoSupport
+		case failed == 0:
+			remediation.Status = result.RemediationStatusSuccess
+		case succeeded == 0:
+			remediation.Status = result.RemediationStatusFailed
+		default:
+			remediation.Status = result.RemediationStatusPartial
+		}
+	}
+}
+
+// breachKeyValues extracts the key and values of a breach to render the
+// commands with.
+func breachKeyValues(b result.Breach) (string, []string) {
+	switch breach := b.(type) {
+	case *result.ValueBreach:
+		return breach.ValueLabel, []string{breach.Value}
+	case *result.KeyValueBreach:
+		return breach.Key, []string{breach.Value}
+	case *result.KeyValuesBreach:
+		return breach.Key, breach.Values
+	}
+	return "", []string{""}
+}
+
+// renderCommand renders a command template for the given key and value,
+// quoting them so that they cannot be split into several arguments.
+func renderCommand(tpl string, key string, value string) (string, error) {
+	t, err := gonja.FromString(tpl)
+	if err != nil {
+		return "", err
+	}
+	cmd, err := t.ExecuteToString(exec.NewContext(map[string]interface{}{
+		"key":   utils.ShellQuote(key),
+		"value": utils.ShellQuote(value),
+	}))
+	return strings.TrimSpace(cmd), err
+}
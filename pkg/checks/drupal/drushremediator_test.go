@@ -0,0 +1,222 @@
+package drupal_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrushRemediatorMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	r := DrushRemediator{Commands: []string{"cr"}}
+	r.Merge(DrushRemediator{DryRun: true})
+	assert.Equal(DrushRemediator{Commands: []string{"cr"}, DryRun: true}, r)
+
+	r.Merge(DrushRemediator{Commands: []string{"pmu {{ value }} -y"}})
+	assert.Equal(DrushRemediator{Commands: []string{"pmu {{ value }} -y"}, DryRun: true}, r)
+
+	r.Merge(DrushRemediator{Files: []string{"config/sync/core.extension.yml"}})
+	assert.Equal([]string{"config/sync/core.extension.yml"}, r.Files)
+}
+
+func TestDrushRemediatorRemediate(t *testing.T) {
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	tt := []struct {
+		name              string
+		remediator        DrushRemediator
+		breach            result.Breach
+		out               string
+		err               error
+		expectCommands    []string
+		expectArgs        [][]string
+		expectRemediation result.Remediation
+	}{
+		{
+			name: "dryRun",
+			remediator: DrushRemediator{
+				Commands: []string{"pmu {{ value }} -y"},
+				DryRun:   true,
+			},
+			breach: &result.KeyValuesBreach{
+				Key:    "disallowed modules are enabled",
+				Values: []string{"devel", "kint"},
+			},
+			expectRemediation: result.Remediation{
+				Status: result.RemediationStatusNoSupport,
+				Messages: []string{
+					"[dry-run] drush pmu devel -y",
+					"[dry-run] drush pmu kint -y",
+				},
+			},
+		},
+		{
+			name: "success",
+			remediator: DrushRemediator{Commands: []string{
+				"cset {{ key }} enabled {{ value }} -y",
+				"cr",
+			}},
+			breach: &result.KeyValueBreach{Key: "clamav.settings", Value: "1"},
+			out:    "Cache rebuild complete.\n",
+			expectCommands: []string{
+				"/drush @local cset clamav.settings enabled 1 -y",
+				"/drush @local cr",
+			},
+			expectRemediation: result.Remediation{
+				Status: result.RemediationStatusSuccess,
+				Messages: []string{
+					"drush cset clamav.settings enabled 1 -y ran successfully: Cache rebuild complete.",
+					"drush cr ran successfully: Cache rebuild complete.",
+				},
+			},
+		},
+		{
+			name: "skipEmptyCommands",
+			remediator: DrushRemediator{Commands: []string{
+				"{% if 'disallowed' in key %}pmu {{ value }} -y{% endif %}",
+			}},
+			breach: &result.KeyValuesBreach{
+				Key:    "required modules are not enabled",
+				Values: []string{"clamav"},
+			},
+			expectRemediation: result.Remediation{
+				Status:   result.RemediationStatusNoSupport,
+				Messages: []string{},
+			},
+		},
+		{
+			name: "valueWithSpaces",
+			remediator: DrushRemediator{Commands: []string{
+				"cset system.site {{ key }} {{ value }} -y",
+				"cset system.site slogan 'Just a site' -y",
+			}},
+			breach: &result.KeyValueBreach{Key: "name", Value: "My site"},
+			expectCommands: []string{
+				"/drush @local cset system.site name 'My site' -y",
+				"/drush @local cset system.site slogan 'Just a site' -y",
+			},
+			expectArgs: [][]string{
+				{"@local", "cset", "system.site", "name", "My site", "-y"},
+				{"@local", "cset", "system.site", "slogan", "Just a site", "-y"},
+			},
+			expectRemediation: result.Remediation{
+				Status: result.RemediationStatusSuccess,
+				Messages: []string{
+					"drush cset system.site name 'My site' -y ran successfully",
+					"drush cset system.site slogan 'Just a site' -y ran successfully",
+				},
+			},
+		},
+		{
+			name:       "valueInjection",
+			remediator: DrushRemediator{Commands: []string{"pmu {{ value }} -y"}},
+			breach:     &result.ValueBreach{Value: "devel' --uri='evil"},
+			expectCommands: []string{
+				"/drush @local pmu 'devel' --uri='evil' -y",
+			},
+			expectArgs: [][]string{
+				{"@local", "pmu", "devel' --uri='evil", "-y"},
+			},
+			expectRemediation: result.Remediation{
+				Status: result.RemediationStatusSuccess,
+			},
+		},
+		{
+			name:       "failure",
+			remediator: DrushRemediator{Commands: []string{"pmu {{ value }} -y"}},
+			breach:     &result.ValueBreach{Value: "devel"},
+			err:        &exec.ExitError{Stderr: []byte("  [error] Unable to uninstall devel.\n")},
+			expectCommands: []string{
+				"/drush @local pmu devel -y",
+			},
+			expectRemediation: result.Remediation{
+				Status: result.RemediationStatusFailed,
+				Messages: []string{
+					"drush pmu devel -y failed: [error] Unable to uninstall devel.",
+				},
+			},
+		},
+		{
+			name:       "invalidTemplate",
+			remediator: DrushRemediator{Commands: []string{"pmu {{ value | unknown }}"}},
+			breach:     &result.ValueBreach{Value: "devel"},
+			expectRemediation: result.Remediation{
+				Status: result.RemediationStatusFailed,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			commands := []string{}
+			args := [][]string{}
+			command.ShellCommander = func(name string, arg ...string) command.IShellCommand {
+				var generated string
+				internal.ShellCommanderMaker(nil, nil, &generated)(name, arg...)
+				commands = append(commands, generated)
+				args = append(args, arg)
+				return internal.ShellCommanderMaker(&tc.out, tc.err, nil)(name, arg...)
+			}
+
+			tc.remediator.Remediate(DrushCommand{DrushPath: "/drush", Alias: "local"}, []result.Breach{tc.breach})
+			assert.ElementsMatch(tc.expectCommands, commands)
+			if tc.expectArgs != nil {
+				assert.Equal(tc.expectArgs, args)
+			}
+			assert.Equal(tc.expectRemediation.Status, tc.breach.GetRemediation().Status)
+			if tc.expectRemediation.Messages != nil {
+				assert.Equal(tc.expectRemediation.Messages, tc.breach.GetRemediation().Messages)
+			}
+		})
+	}
+}
+
+func TestDrushRemediatorRemediateDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	currProjectDir := config.ProjectDir
+	defer func() { config.ProjectDir = currProjectDir }()
+	config.ProjectDir = t.TempDir()
+
+	file := filepath.Join(config.ProjectDir, "core.extension.yml")
+	os.WriteFile(file, []byte("module:\n  devel: 0\n  node: 0\n"), 0644)
+
+	// The command exports the config without the uninstalled module.
+	command.ShellCommander = func(name string, arg ...string) command.IShellCommand {
+		return internal.TestShellCommand{
+			OutputterFunc: func() ([]byte, error) {
+				return nil, os.WriteFile(file, []byte("module:\n  node: 0\n"), 0644)
+			},
+		}
+	}
+
+	r := DrushRemediator{
+		Commands: []string{"pmu {{ value }} -y", "cex -y"},
+		Files:    []string{"core.extension.yml", "missing.yml"},
+	}
+	b := &result.ValueBreach{Value: "devel"}
+	r.Remediate(DrushCommand{}, []result.Breach{b})
+	assert.Equal(result.RemediationStatusSuccess, b.Remediation.Status)
+	assert.Equal(`--- a/core.extension.yml
++++ b/core.extension.yml
+@@ -1,3 +1,2 @@
+ module:
+-  devel: 0
+   node: 0
+`, b.Remediation.Diff)
+}
@@ -28,6 +28,7 @@ func (c *DrushYamlCheck) Merge(mergeCheck config.Check) error {
 	}
 
 	c.DrushCommand.Merge(drushYamlMergeCheck.DrushCommand)
+	c.Remediation.Merge(drushYamlMergeCheck.Remediation)
 	utils.MergeString(&c.Command, drushYamlMergeCheck.Command)
 	utils.MergeString(&c.ConfigName, drushYamlMergeCheck.ConfigName)
 	return nil
@@ -54,9 +55,14 @@ func (c *DrushYamlCheck) FetchData() {
 	}
 }
 
-// Remediate attempts to remediate a breach by running the drush command
-// specified in the check.
+// Remediate attempts to remediate a breach by running the drush remediation
+// commands, or otherwise the remediation command specified in the check.
 func (c *DrushYamlCheck) Remediate() {
+	if len(c.Remediation.Commands) > 0 {
+		c.Remediation.Remediate(c.DrushCommand, c.Result.Breaches)
+		return
+	}
+
 	for _, b := range c.Result.Breaches {
 		contextLogger := log.WithFields(log.Fields{
 			"check-type": c.GetType(),
@@ -84,3 +90,9 @@ func (c *DrushYamlCheck) Remediate() {
 		}
 	}
 }
+
+// Commands implements config.CommandReporter.
+func (c *DrushYamlCheck) Commands() [][]string {
+	args := append(strings.Fields(c.Command), "--format=yaml")
+	return [][]string{Drush(c.DrushPath, c.Alias, args).Line()}
+}
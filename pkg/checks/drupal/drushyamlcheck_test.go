@@ -255,6 +255,28 @@ drush config:set clamav.settings enabled true
 						"remediation command for config '' ran successfully"}}}},
 			ExpectRemediationStatus: result.RemediationStatusSuccess,
 		},
+		{
+			Name: "drushRemediation",
+			Check: &DrushYamlCheck{
+				YamlBase: yaml.YamlBase{
+					CheckBase: config.CheckBase{
+						Result: result.Result{
+							Breaches: []result.Breach{&result.KeyValueBreach{
+								Key: "clamav.settings", Value: "0"}}}}},
+				RemediateCommand: "drush config:set clamav.settings enabled 1",
+				Remediation: DrushRemediator{
+					Commands: []string{"cset {{ key }} enabled 1 -y"},
+					DryRun:   true,
+				}},
+			ExpectBreaches: []result.Breach{&result.KeyValueBreach{
+				Key: "clamav.settings", Value: "0",
+				Remediation: result.Remediation{
+					Status: "no-support",
+					Messages: []string{
+						"[dry-run] drush cset clamav.settings enabled 1 -y"}}}},
+			ExpectStatusFail:        true,
+			ExpectRemediationStatus: result.RemediationStatusNoSupport,
+		},
 	}
 
 	for _, tc := range tt {
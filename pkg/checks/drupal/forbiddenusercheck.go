@@ -113,3 +113,9 @@ func (c *ForbiddenUserCheck) RunCheck() {
 		c.AddPass("No forbidden user is active.")
 	}
 }
+
+// Commands implements config.CommandReporter.
+func (c *ForbiddenUserCheck) Commands() [][]string {
+	cmd := []string{"user:info", "--uid=" + c.UserId, "--fields=user_status", "--format=json"}
+	return [][]string{Drush(c.DrushPath, c.Alias, cmd).Line()}
+}
@@ -0,0 +1,166 @@
+package drupal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const PermissionsMatrix config.CheckType = "drupal-permissions-matrix"
+
+// PermissionsMatrixWildcard is the role key applying forbidden permissions
+// to every role in the matrix.
+const PermissionsMatrixWildcard = "*"
+
+// PermissionsMatrixCheck validates all roles' permissions against a matrix of
+// forbidden permissions per role.
+type PermissionsMatrixCheck struct {
+	config.CheckBase `yaml:",inline"`
+	DrushCommand     `yaml:",inline"`
+	// Map of role ID to the list of permissions the role must not have.
+	// The special role '*' applies to all roles.
+	Forbidden map[string][]string `yaml:"forbidden"`
+	// Map of role ID to the list of permissions the role is allowed to have
+	// even if they are forbidden by the matrix.
+	Exceptions map[string][]string `yaml:"exceptions"`
+	// List of roles to skip entirely, e.g, administrator.
+	ExcludeRoles []string `yaml:"exclude-roles"`
+	rolePerms    map[string][]string
+}
+
+// Init implementation for the drush-based permissions matrix check.
+func (c *PermissionsMatrixCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	c.RequiresDb = true
+}
+
+// Merge implementation for PermissionsMatrixCheck check.
+func (c *PermissionsMatrixCheck) Merge(mergeCheck config.Check) error {
+	permissionsMatrixMergeCheck := mergeCheck.(*PermissionsMatrixCheck)
+	if err := c.CheckBase.Merge(&permissionsMatrixMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.DrushCommand.Merge(permissionsMatrixMergeCheck.DrushCommand)
+	mergeRolePermsMap(&c.Forbidden, permissionsMatrixMergeCheck.Forbidden)
+	mergeRolePermsMap(&c.Exceptions, permissionsMatrixMergeCheck.Exceptions)
+	utils.MergeStringSlice(&c.ExcludeRoles, permissionsMatrixMergeCheck.ExcludeRoles)
+	return nil
+}
+
+// mergeRolePermsMap replaces the permissions of each role in mapA with the
+// ones from mapB.
+func mergeRolePermsMap(mapA *map[string][]string, mapB map[string][]string) {
+	if len(mapB) == 0 {
+		return
+	}
+	if *mapA == nil {
+		*mapA = map[string][]string{}
+	}
+	for role, perms := range mapB {
+		rolePerms := (*mapA)[role]
+		utils.MergeStringSlice(&rolePerms, perms)
+		(*mapA)[role] = rolePerms
+	}
+}
+
+// FetchData runs the drush command to populate data for the permissions
+// matrix check.
+func (c *PermissionsMatrixCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	// Command: drush role:list --fields=perms --format=json
+	cmd := []string{"role:list", "--fields=perms", "--format=json"}
+	c.DataMap["roles"], err = Drush(c.DrushPath, c.Alias, cmd).Exec()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+	}
+}
+
+// UnmarshalDataMap parses the drush role:list json into the rolePerms map
+// for further processing.
+func (c *PermissionsMatrixCheck) UnmarshalDataMap() {
+	if len(c.DataMap["roles"]) == 0 {
+		c.AddBreach(&result.ValueBreach{Value: "no data provided"})
+		return
+	}
+
+	// Unmarshal role:list JSON.
+	// {
+	//    "anonymous": {
+	//        "perms": [
+	//            "access content",
+	//            "view media"
+	//        ]
+	//    }
+	// }
+	rolesMap := map[string]map[string][]string{}
+	err := json.Unmarshal(c.DataMap["roles"], &rolesMap)
+	var synErr *json.SyntaxError
+	if err != nil && errors.As(err, &synErr) {
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+		return
+	}
+
+	c.rolePerms = map[string][]string{}
+	for role, fields := range rolesMap {
+		c.rolePerms[role] = fields["perms"]
+	}
+}
+
+// RunCheck implements the Check logic for the permissions matrix.
+func (c *PermissionsMatrixCheck) RunCheck() {
+	if len(c.Forbidden) == 0 {
+		c.AddBreach(&result.ValueBreach{Value: "no forbidden permissions provided"})
+		return
+	}
+
+	roles := []string{}
+	for r := range c.rolePerms {
+		roles = append(roles, r)
+	}
+	sort.Strings(roles)
+
+	for _, r := range roles {
+		if utils.StringSliceContains(c.ExcludeRoles, r) {
+			continue
+		}
+
+		forbidden := append([]string{}, c.Forbidden[PermissionsMatrixWildcard]...)
+		forbidden = append(forbidden, c.Forbidden[r]...)
+		if len(forbidden) == 0 {
+			continue
+		}
+
+		fails := utils.StringSlicesIntersectUnique(c.rolePerms[r], forbidden)
+		fails = utils.StringSlicesInterdiffUnique(c.Exceptions[r], fails)
+		if len(fails) == 0 {
+			c.AddPass(fmt.Sprintf("[%s] no forbidden permissions", r))
+			continue
+		}
+
+		sort.Strings(fails)
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "role",
+			Key:        r,
+			ValueLabel: "forbidden permissions",
+			Values:     fails,
+		})
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// Commands implements config.CommandReporter.
+func (c *PermissionsMatrixCheck) Commands() [][]string {
+	cmd := []string{"role:list", "--fields=perms", "--format=json"}
+	return [][]string{Drush(c.DrushPath, c.Alias, cmd).Line()}
+}
@@ -0,0 +1,190 @@
+package drupal_test
+
+import (
+	"os/exec"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionsMatrixCheckInit(t *testing.T) {
+	c := PermissionsMatrixCheck{}
+	c.Init(PermissionsMatrix)
+	assert.True(t, c.RequiresDb)
+}
+
+func TestPermissionsMatrixMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := PermissionsMatrixCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush"},
+		Forbidden: map[string][]string{
+			"*":      {"administer modules"},
+			"editor": {"administer users"},
+		},
+		ExcludeRoles: []string{"administrator"},
+	}
+	c.Merge(&PermissionsMatrixCheck{
+		DrushCommand: DrushCommand{DrushPath: "/new/path/to/drush"},
+		Forbidden: map[string][]string{
+			"editor": {"administer permissions"},
+		},
+		Exceptions: map[string][]string{
+			"site_admin": {"administer modules"},
+		},
+	})
+	assert.EqualValues(PermissionsMatrixCheck{
+		DrushCommand: DrushCommand{DrushPath: "/new/path/to/drush"},
+		Forbidden: map[string][]string{
+			"*":      {"administer modules"},
+			"editor": {"administer permissions"},
+		},
+		Exceptions: map[string][]string{
+			"site_admin": {"administer modules"},
+		},
+		ExcludeRoles: []string{"administrator"},
+	}, c)
+}
+
+func TestPermissionsMatrixFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	t.Run("drushError", func(t *testing.T) {
+		command.ShellCommander = internal.ShellCommanderMaker(
+			nil,
+			&exec.ExitError{Stderr: []byte("unable to run drush command")},
+			nil)
+		c := PermissionsMatrixCheck{}
+		c.FetchData()
+		assert.EqualValues(
+			[]result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				Value:      "unable to run drush command",
+			}},
+			c.Result.Breaches,
+		)
+	})
+
+	t.Run("drushCommandIsCorrect", func(t *testing.T) {
+		var generatedCommand string
+		command.ShellCommander = internal.ShellCommanderMaker(
+			&[]string{`{"anonymous":{"perms":["access content"]}}`}[0],
+			nil,
+			&generatedCommand)
+		c := PermissionsMatrixCheck{}
+		c.FetchData()
+		assert.Empty(c.Result.Breaches)
+		assert.Equal("vendor/drush/drush/drush role:list --fields=perms --format=json", generatedCommand)
+		assert.Equal([]byte(`{"anonymous":{"perms":["access content"]}}`), c.DataMap["roles"])
+	})
+}
+
+func TestPermissionsMatrixUnmarshalDataMap(t *testing.T) {
+	assert := assert.New(t)
+
+	c := PermissionsMatrixCheck{}
+	c.UnmarshalDataMap()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "no data provided",
+		}},
+		c.Result.Breaches,
+	)
+
+	c = PermissionsMatrixCheck{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{
+				"roles": []byte(`{"anonymous":{"perms":"access content"]}}`)},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "invalid character ']' after object key:value pair",
+		}},
+		c.Result.Breaches,
+	)
+}
+
+func TestPermissionsMatrixRunCheck(t *testing.T) {
+	rolesData := []byte(`
+{
+	"anonymous": {"perms": ["access content"]},
+	"editor": {"perms": ["access content", "administer users", "administer modules"]},
+	"site_admin": {"perms": ["administer modules", "administer users"]},
+	"administrator": {"perms": ["administer modules", "administer users"]}
+}`)
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "noForbiddenProvided",
+			Check: &PermissionsMatrixCheck{
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"roles": rolesData},
+				},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				Value:      "no forbidden permissions provided"}},
+		},
+		{
+			Name: "noBreaches",
+			Check: &PermissionsMatrixCheck{
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"roles": rolesData},
+				},
+				Forbidden: map[string][]string{
+					"anonymous": {"administer modules"},
+				},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"[anonymous] no forbidden permissions"},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "breachesWithExceptionsAndExcludedRoles",
+			Check: &PermissionsMatrixCheck{
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"roles": rolesData},
+				},
+				Forbidden: map[string][]string{
+					"*":      {"administer modules"},
+					"editor": {"administer users"},
+				},
+				Exceptions: map[string][]string{
+					"site_admin": {"administer modules"},
+				},
+				ExcludeRoles: []string{"administrator"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{
+				"[anonymous] no forbidden permissions",
+				"[site_admin] no forbidden permissions",
+			},
+			ExpectFails: []result.Breach{&result.KeyValuesBreach{
+				BreachType: "key-values",
+				KeyLabel:   "role",
+				Key:        "editor",
+				ValueLabel: "forbidden permissions",
+				Values:     []string{"administer modules", "administer users"}}},
+		},
+	}
+
+	for _, tc := range tt {
+		tc.Check.UnmarshalDataMap()
+		internal.TestRunCheck(t, tc)
+	}
+}
@@ -111,3 +111,9 @@ func (c *RolePermissionsCheck) RunCheck() {
 		c.Result.Status = result.Pass
 	}
 }
+
+// Commands implements config.CommandReporter.
+func (c *RolePermissionsCheck) Commands() [][]string {
+	cmd := []string{"role:list", "--filter=id=" + c.RoleId, "--fields=perms", "--format=json"}
+	return [][]string{Drush(c.DrushPath, c.Alias, cmd).Line()}
+}
@@ -0,0 +1,179 @@
+package drupal
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Status config.CheckType = "drupal-status"
+
+// DefaultStatusExpected are the status values expected unless overridden.
+var DefaultStatusExpected = map[string]string{
+	"bootstrap": "Successful",
+	"db-status": "Connected",
+}
+
+// StatusCheck verifies the site's environment as reported by drush
+// core:status, e.g, the database driver or the install profile, and that
+// paths such as the temporary directory are writable.
+type StatusCheck struct {
+	config.CheckBase `yaml:",inline"`
+	DrushCommand     `yaml:",inline"`
+	// Expected status values, keyed by status field, e.g, db-driver: mysql.
+	// Values can be regex (re:) or glob (glob:) patterns.
+	Expected map[string]string `yaml:"expected"`
+	// Status fields containing paths which must be writable, e.g, temp.
+	Writable []string `yaml:"writable"`
+	status   map[string]any
+}
+
+// Init implementation for the drush-based status check.
+func (c *StatusCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	c.RequiresDb = true
+	if c.Expected == nil {
+		c.Expected = map[string]string{}
+	}
+	for k, v := range DefaultStatusExpected {
+		if _, ok := c.Expected[k]; !ok {
+			c.Expected[k] = v
+		}
+	}
+}
+
+// Merge implementation for StatusCheck check.
+func (c *StatusCheck) Merge(mergeCheck config.Check) error {
+	statusMergeCheck := mergeCheck.(*StatusCheck)
+	if err := c.CheckBase.Merge(&statusMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.DrushCommand.Merge(statusMergeCheck.DrushCommand)
+	if len(statusMergeCheck.Expected) > 0 && c.Expected == nil {
+		c.Expected = map[string]string{}
+	}
+	for k, v := range statusMergeCheck.Expected {
+		c.Expected[k] = v
+	}
+	utils.MergeStringSlice(&c.Writable, statusMergeCheck.Writable)
+	return nil
+}
+
+// FetchData runs the drush command to populate data for the status check.
+func (c *StatusCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	// Command: drush core:status --format=json
+	cmd := []string{"core:status", "--format=json"}
+	c.DataMap["status"], err = Drush(c.DrushPath, c.Alias, cmd).Exec()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+	}
+}
+
+// UnmarshalDataMap parses the drush core:status json.
+func (c *StatusCheck) UnmarshalDataMap() {
+	// Unmarshal core:status JSON.
+	// {
+	//    "drupal-version": "10.1.6",
+	//    "db-driver": "mysql",
+	//    "db-status": "Connected",
+	//    "bootstrap": "Successful",
+	//    "root": "/app/web",
+	//    "files": "sites/default/files",
+	//    "temp": "/tmp"
+	// }
+	c.status = map[string]any{}
+	if err := json.Unmarshal(c.DataMap["status"], &c.status); err != nil {
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+	}
+}
+
+// RunCheck implements the Check logic for the drush status.
+func (c *StatusCheck) RunCheck() {
+	keys := []string{}
+	for k := range c.Expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		actual, ok := c.status[k]
+		if !ok {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "status",
+				Key:           k,
+				ValueLabel:    "actual",
+				ExpectedValue: c.Expected[k],
+				Value:         "<not set>",
+			})
+			continue
+		}
+		if !utils.MatchString(c.Expected[k], fmt.Sprint(actual)) {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "status",
+				Key:           k,
+				ValueLabel:    "actual",
+				ExpectedValue: c.Expected[k],
+				Value:         fmt.Sprint(actual),
+			})
+			continue
+		}
+		c.AddPass(fmt.Sprintf("%s is %v", k, actual))
+	}
+
+	for _, k := range c.Writable {
+		c.verifyWritable(k)
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// verifyWritable verifies that the path in the status field is writable;
+// relative paths are relative to the Drupal root.
+func (c *StatusCheck) verifyWritable(k string) {
+	p, ok := c.status[k].(string)
+	if !ok || p == "" {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "status",
+			Key:        k,
+			ValueLabel: "path",
+			Value:      "<not set>",
+		})
+		return
+	}
+	if root, ok := c.status["root"].(string); ok && !filepath.IsAbs(p) {
+		p = filepath.Join(root, p)
+	}
+
+	// Run through the shell commander so the path is verified on the target.
+	if _, err := command.ShellCommander("test", "-w", p).Output(); err != nil {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "status",
+			Key:        k,
+			ValueLabel: "path not writable",
+			Value:      p,
+		})
+		return
+	}
+	c.AddPass(fmt.Sprintf("%s path %s is writable", k, p))
+}
+
+// Commands implements config.CommandReporter.
+func (c *StatusCheck) Commands() [][]string {
+	cmd := []string{"core:status", "--format=json"}
+	cmds := [][]string{Drush(c.DrushPath, c.Alias, cmd).Line()}
+	for _, k := range c.Writable {
+		cmds = append(cmds, []string{"test", "-w", "<" + k + " path>"})
+	}
+	return cmds
+}
@@ -0,0 +1,229 @@
+package drupal_test
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := StatusCheck{Expected: map[string]string{"bootstrap": "re:.*"}}
+	c.Init(Status)
+	assert.True(c.RequiresDb)
+	assert.Equal(map[string]string{
+		"bootstrap": "re:.*",
+		"db-status": "Connected",
+	}, c.Expected)
+}
+
+func TestStatusCheckCommands(t *testing.T) {
+	assert := assert.New(t)
+
+	c := StatusCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush", Alias: "prod"},
+		Writable:     []string{"files"},
+	}
+	assert.Equal([][]string{
+		{"/path/to/drush", "@prod", "core:status", "--format=json"},
+		{"test", "-w", "<files path>"},
+	}, c.Commands())
+}
+
+func TestStatusCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := StatusCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush"},
+		Expected:     map[string]string{"db-driver": "mysql"},
+		Writable:     []string{"temp"},
+	}
+	c.Merge(&StatusCheck{
+		DrushCommand: DrushCommand{Alias: "@prod"},
+		Expected:     map[string]string{"install-profile": "minimal"},
+		Writable:     []string{"temp", "files"},
+	})
+	assert.EqualValues(StatusCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush", Alias: "@prod"},
+		Expected:     map[string]string{"db-driver": "mysql", "install-profile": "minimal"},
+		Writable:     []string{"temp", "files"},
+	}, c)
+}
+
+func TestStatusCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil,
+		&exec.ExitError{Stderr: []byte("unable to run drush command")},
+		nil)
+	c := StatusCheck{}
+	c.FetchData()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "unable to run drush command",
+		}},
+		c.Result.Breaches,
+	)
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{`{"bootstrap":"Successful"}`}[0], nil, &generatedCommand)
+	c = StatusCheck{}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("vendor/drush/drush/drush core:status --format=json", generatedCommand)
+}
+
+func TestStatusCheckUnmarshalDataMap(t *testing.T) {
+	c := StatusCheck{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{"status": []byte(`{"bootstrap":`)},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.EqualValues(t,
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "unexpected end of JSON input",
+		}},
+		c.Result.Breaches,
+	)
+}
+
+func TestStatusCheckRunCheck(t *testing.T) {
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	status := `{
+	"drupal-version": "10.1.6",
+	"db-driver": "mysql",
+	"db-status": "Connected",
+	"bootstrap": "Successful",
+	"install-profile": "standard",
+	"root": "/app/web",
+	"files": "sites/default/files",
+	"temp": "/tmp"
+}`
+
+	tt := []struct {
+		internal.RunCheckTest
+		writableErr error
+	}{
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "expectedEnvironment",
+				Check: &StatusCheck{
+					Expected: map[string]string{
+						"db-driver":      "mysql",
+						"drupal-version": "glob:10.*",
+					},
+					Writable: []string{"temp", "files"},
+				},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{
+					"bootstrap is Successful",
+					"db-driver is mysql",
+					"db-status is Connected",
+					"drupal-version is 10.1.6",
+					"temp path /tmp is writable",
+					"files path /app/web/sites/default/files is writable",
+				},
+				ExpectNoFail: true,
+			},
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "unexpectedEnvironment",
+				Check: &StatusCheck{
+					Expected: map[string]string{
+						"db-driver":       "pgsql",
+						"install-profile": "re:^(minimal|custom)$",
+						"php-version":     "8.2",
+					},
+					Writable: []string{"temp", "private"},
+				},
+				ExpectStatus: result.Fail,
+				ExpectPasses: []string{
+					"bootstrap is Successful",
+					"db-status is Connected",
+				},
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "drupal-status",
+						Severity:      "normal",
+						KeyLabel:      "status",
+						Key:           "db-driver",
+						ValueLabel:    "actual",
+						ExpectedValue: "pgsql",
+						Value:         "mysql",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "drupal-status",
+						Severity:      "normal",
+						KeyLabel:      "status",
+						Key:           "install-profile",
+						ValueLabel:    "actual",
+						ExpectedValue: "re:^(minimal|custom)$",
+						Value:         "standard",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "drupal-status",
+						Severity:      "normal",
+						KeyLabel:      "status",
+						Key:           "php-version",
+						ValueLabel:    "actual",
+						ExpectedValue: "8.2",
+						Value:         "<not set>",
+					},
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "drupal-status",
+						Severity:   "normal",
+						KeyLabel:   "status",
+						Key:        "temp",
+						ValueLabel: "path not writable",
+						Value:      "/tmp",
+					},
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "drupal-status",
+						Severity:   "normal",
+						KeyLabel:   "status",
+						Key:        "private",
+						ValueLabel: "path",
+						Value:      "<not set>",
+					},
+				},
+			},
+			writableErr: errors.New("exit status 1"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			command.ShellCommander = internal.ShellCommanderMaker(nil, tc.writableErr, nil)
+			c := tc.Check.(*StatusCheck)
+			c.Init(Status)
+			c.DataMap = map[string][]byte{"status": []byte(status)}
+			c.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc.RunCheckTest)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package drupal
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const StatusReport config.CheckType = "drupal-status-report"
+
+// Requirement is an entry of the site's status report.
+type Requirement struct {
+	Title       string `json:"title"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+}
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// Summary returns the requirement's title and value, without markup.
+func (r Requirement) Summary() string {
+	summary := r.Title
+	if v := strings.Join(strings.Fields(htmlTagRegex.ReplaceAllString(r.Value, " ")), " "); v != "" {
+		summary += ": " + v
+	}
+	return summary
+}
+
+// StatusReportCheck verifies the site's status report, as reported by drush
+// core:requirements, has no errors or warnings.
+type StatusReportCheck struct {
+	config.CheckBase `yaml:",inline"`
+	DrushCommand     `yaml:",inline"`
+	// Requirements to ignore, e.g, update_contrib; values can be regex (re:)
+	// or glob (glob:) patterns.
+	Ignore []string `yaml:"ignore"`
+	// Requirements for which warnings are acceptable; errors are still
+	// reported.
+	IgnoreWarnings []string `yaml:"ignore-warnings"`
+	requirements   map[string]Requirement
+}
+
+var statusReportArgs = []string{"core:requirements", "--format=json"}
+
+// Init implementation for the drush-based status report check.
+func (c *StatusReportCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	c.RequiresDb = true
+}
+
+// Merge implementation for StatusReportCheck check.
+func (c *StatusReportCheck) Merge(mergeCheck config.Check) error {
+	statusReportMergeCheck := mergeCheck.(*StatusReportCheck)
+	if err := c.CheckBase.Merge(&statusReportMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.DrushCommand.Merge(statusReportMergeCheck.DrushCommand)
+	utils.MergeStringSlice(&c.Ignore, statusReportMergeCheck.Ignore)
+	utils.MergeStringSlice(&c.IgnoreWarnings, statusReportMergeCheck.IgnoreWarnings)
+	return nil
+}
+
+// FetchData runs the drush command to populate data for the status report
+// check.
+func (c *StatusReportCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	// Command: drush core:requirements --format=json
+	c.DataMap["requirements"], err = Drush(c.DrushPath, c.Alias, statusReportArgs).Exec()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+	}
+}
+
+// UnmarshalDataMap parses the drush core:requirements json.
+func (c *StatusReportCheck) UnmarshalDataMap() {
+	// Unmarshal core:requirements JSON.
+	// {
+	//    "cron": {
+	//      "title": "Cron maintenance tasks",
+	//      "severity": "Warning",
+	//      "sid": 1,
+	//      "description": "Cron has not run recently.",
+	//      "value": "Last run 3 weeks ago"
+	//    }
+	// }
+	c.requirements = map[string]Requirement{}
+	if err := json.Unmarshal(c.DataMap["requirements"], &c.requirements); err != nil {
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+	}
+}
+
+// RunCheck implements the Check logic for the status report.
+func (c *StatusReportCheck) RunCheck() {
+	ids := []string{}
+	for id := range c.requirements {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		req := c.requirements[id]
+		severity := strings.ToLower(req.Severity)
+		if severity != "error" && severity != "warning" {
+			continue
+		}
+		if utils.StringSliceMatchAny(c.Ignore, id) {
+			continue
+		}
+		if severity == "warning" && utils.StringSliceMatchAny(c.IgnoreWarnings, id) {
+			continue
+		}
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "requirement",
+			Key:        id,
+			ValueLabel: severity,
+			Value:      req.Summary(),
+		})
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.AddPass("no errors or warnings in the status report")
+		c.Result.Status = result.Pass
+	}
+}
+
+// Commands implements config.CommandReporter.
+func (c *StatusReportCheck) Commands() [][]string {
+	return [][]string{Drush(c.DrushPath, c.Alias, statusReportArgs).Line()}
+}
@@ -0,0 +1,185 @@
+package drupal_test
+
+import (
+	"os/exec"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusReportCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := StatusReportCheck{
+		DrushCommand: DrushCommand{DrushPath: "/path/to/drush"},
+		Ignore:       []string{"update_core"},
+	}
+	err := c.Merge(&StatusReportCheck{
+		DrushCommand:   DrushCommand{Alias: "prod"},
+		IgnoreWarnings: []string{"cron"},
+	})
+	assert.NoError(err)
+	assert.Equal("/path/to/drush", c.DrushPath)
+	assert.Equal("prod", c.Alias)
+	assert.Equal([]string{"update_core"}, c.Ignore)
+	assert.Equal([]string{"cron"}, c.IgnoreWarnings)
+}
+
+func TestStatusReportCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil,
+		&exec.ExitError{Stderr: []byte("unable to run drush command")},
+		nil)
+	c := StatusReportCheck{}
+	c.FetchData()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "unable to run drush command",
+		}},
+		c.Result.Breaches,
+	)
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{`{}`}[0], nil, &generatedCommand)
+	c = StatusReportCheck{}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("vendor/drush/drush/drush core:requirements --format=json", generatedCommand)
+	assert.Equal([][]string{{"vendor/drush/drush/drush", "core:requirements", "--format=json"}}, c.Commands())
+}
+
+func TestStatusReportCheckUnmarshalDataMap(t *testing.T) {
+	c := StatusReportCheck{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{"requirements": []byte(`{"cron":`)},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.EqualValues(t,
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "unexpected end of JSON input",
+		}},
+		c.Result.Breaches,
+	)
+}
+
+func TestStatusReportCheckRunCheck(t *testing.T) {
+	requirements := `{
+	"cron": {
+		"title": "Cron maintenance tasks",
+		"severity": "Warning",
+		"sid": 1,
+		"description": "Cron has not run recently.",
+		"value": "Last run 3 weeks ago"
+	},
+	"php": {
+		"title": "PHP",
+		"severity": "Info",
+		"sid": -1,
+		"value": "8.2.12 (<a href=\"/admin/reports/status/php\">more information</a>)"
+	},
+	"update_contrib": {
+		"title": "Module and theme update status",
+		"severity": "Error",
+		"sid": 2,
+		"value": "<strong>Not secure!</strong>\n  There are security updates available."
+	},
+	"update_core": {
+		"title": "Drupal core update status",
+		"severity": "Warning",
+		"sid": 1,
+		"value": "Out of date"
+	}
+}`
+
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "errorsAndWarnings",
+			Check:        &StatusReportCheck{},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "drupal-status-report",
+					Severity:   "normal",
+					KeyLabel:   "requirement",
+					Key:        "cron",
+					ValueLabel: "warning",
+					Value:      "Cron maintenance tasks: Last run 3 weeks ago",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "drupal-status-report",
+					Severity:   "normal",
+					KeyLabel:   "requirement",
+					Key:        "update_contrib",
+					ValueLabel: "error",
+					Value:      "Module and theme update status: Not secure! There are security updates available.",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "drupal-status-report",
+					Severity:   "normal",
+					KeyLabel:   "requirement",
+					Key:        "update_core",
+					ValueLabel: "warning",
+					Value:      "Drupal core update status: Out of date",
+				},
+			},
+		},
+		{
+			Name: "ignoredWarnings",
+			Check: &StatusReportCheck{
+				IgnoreWarnings: []string{"cron", "glob:update_*"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "drupal-status-report",
+					Severity:   "normal",
+					KeyLabel:   "requirement",
+					Key:        "update_contrib",
+					ValueLabel: "error",
+					Value:      "Module and theme update status: Not secure! There are security updates available.",
+				},
+			},
+		},
+		{
+			Name: "ignored",
+			Check: &StatusReportCheck{
+				Ignore:         []string{"re:^update_"},
+				IgnoreWarnings: []string{"cron"},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"no errors or warnings in the status report"},
+			ExpectNoFail: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			c := tc.Check.(*StatusReportCheck)
+			c.Init(StatusReport)
+			c.DataMap = map[string][]byte{"requirements": []byte(requirements)}
+			c.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
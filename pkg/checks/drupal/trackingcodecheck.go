@@ -3,7 +3,6 @@ package drupal
 import (
 	"fmt"
 	"io"
-	"net/http"
 	"regexp"
 
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
@@ -49,7 +48,7 @@ func (c *TrackingCodeCheck) UnmarshalDataMap() {
 }
 
 func (c *TrackingCodeCheck) RunCheck() {
-	resp, err := http.Get(c.DrushStatus.Uri)
+	resp, err := utils.HttpClient.Get(c.DrushStatus.Uri)
 
 	if err != nil {
 		c.AddBreach(&result.ValueBreach{Value: "could not determine site uri"})
@@ -72,3 +71,8 @@ func (c *TrackingCodeCheck) RunCheck() {
 	}
 
 }
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *TrackingCodeCheck) RequiresNetwork() bool {
+	return true
+}
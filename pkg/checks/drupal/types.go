@@ -24,10 +24,11 @@ type DrushCommand struct {
 type DrushYamlCheck struct {
 	yaml.YamlBase    `yaml:",inline"`
 	DrushCommand     `yaml:",inline"`
-	Command          string `yaml:"command"`
-	ConfigName       string `yaml:"config-name"`
-	RemediateCommand string `yaml:"remediate-command"`
-	RemediateMsg     string `yaml:"remediate-msg"`
+	Command          string          `yaml:"command"`
+	ConfigName       string          `yaml:"config-name"`
+	RemediateCommand string          `yaml:"remediate-command"`
+	RemediateMsg     string          `yaml:"remediate-msg"`
+	Remediation      DrushRemediator `yaml:"remediation"`
 }
 
 type FileModuleCheck struct {
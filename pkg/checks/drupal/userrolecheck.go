@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os/exec"
+	"sort"
 	"strings"
 
 	"github.com/salsadigitalauorg/shipshape/pkg/command"
@@ -114,7 +115,13 @@ func (c *UserRoleCheck) RunCheck() {
 		return
 	}
 
-	for uid, roles := range c.userRoles {
+	uids := []int{}
+	for uid := range c.userRoles {
+		uids = append(uids, uid)
+	}
+	sort.Ints(uids)
+	for _, uid := range uids {
+		roles := c.userRoles[uid]
 		allowedUser := utils.IntSliceContains(c.AllowedUsers, uid)
 		if allowedUser {
 			continue
@@ -135,3 +142,11 @@ func (c *UserRoleCheck) RunCheck() {
 		c.Result.Status = result.Pass
 	}
 }
+
+// Commands implements config.CommandReporter.
+func (c *UserRoleCheck) Commands() [][]string {
+	return [][]string{
+		Drush(c.DrushPath, c.Alias, []string{"sql:query", "SELECT GROUP_CONCAT(uid) FROM users"}).Line(),
+		Drush(c.DrushPath, c.Alias, []string{"user:information", "--uid=<uids>", "--fields=roles", "--format=json"}).Line(),
+	}
+}
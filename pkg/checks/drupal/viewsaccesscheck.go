@@ -0,0 +1,188 @@
+package drupal
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const ViewsAccess config.CheckType = "drupal-views-access"
+
+// ViewsAccessCheck inspects exported views config for displays lacking
+// access control or exposing sensitive entity data to anonymous users.
+type ViewsAccessCheck struct {
+	yaml.YamlCheck `yaml:",inline"`
+	// Base tables considered sensitive, e.g, users_field_data.
+	SensitiveBaseTables []string `yaml:"sensitive-base-tables"`
+	// Permissions held by anonymous users; a display restricted by one of
+	// these is considered publicly accessible.
+	AnonymousPermissions []string `yaml:"anonymous-permissions"`
+	// List of view IDs or view:display IDs to skip.
+	ExcludeViews []string `yaml:"exclude-views"`
+	views        map[string]viewConfig
+}
+
+type viewAccess struct {
+	Type    string                 `yaml:"type"`
+	Options map[string]interface{} `yaml:"options"`
+}
+
+type viewDisplay struct {
+	DisplayPlugin  string `yaml:"display_plugin"`
+	DisplayOptions struct {
+		Access *viewAccess `yaml:"access"`
+	} `yaml:"display_options"`
+}
+
+type viewConfig struct {
+	Id        string                 `yaml:"id"`
+	Status    *bool                  `yaml:"status"`
+	BaseTable string                 `yaml:"base_table"`
+	Display   map[string]viewDisplay `yaml:"display"`
+}
+
+// ViewsAccessDefaultPattern is the file pattern for exported views.
+const ViewsAccessDefaultPattern = `^views\.view\..*\.yml$`
+
+// Init implementation for the views access check.
+func (c *ViewsAccessCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.File == "" && len(c.Files) == 0 && c.Pattern == "" {
+		c.Pattern = ViewsAccessDefaultPattern
+	}
+	if c.IgnoreMissing == nil {
+		cTrue := true
+		c.IgnoreMissing = &cTrue
+	}
+	if len(c.SensitiveBaseTables) == 0 {
+		c.SensitiveBaseTables = []string{"users_field_data", "webform_submission"}
+	}
+	if len(c.AnonymousPermissions) == 0 {
+		c.AnonymousPermissions = []string{"access content"}
+	}
+}
+
+// Merge implementation for ViewsAccessCheck check.
+func (c *ViewsAccessCheck) Merge(mergeCheck config.Check) error {
+	viewsAccessMergeCheck := mergeCheck.(*ViewsAccessCheck)
+	if err := c.YamlCheck.Merge(&viewsAccessMergeCheck.YamlCheck); err != nil {
+		return err
+	}
+
+	utils.MergeStringSlice(&c.SensitiveBaseTables, viewsAccessMergeCheck.SensitiveBaseTables)
+	utils.MergeStringSlice(&c.AnonymousPermissions, viewsAccessMergeCheck.AnonymousPermissions)
+	utils.MergeStringSlice(&c.ExcludeViews, viewsAccessMergeCheck.ExcludeViews)
+	return nil
+}
+
+// UnmarshalDataMap parses the views config files for further processing.
+func (c *ViewsAccessCheck) UnmarshalDataMap() {
+	c.views = map[string]viewConfig{}
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[configName]
+		v := viewConfig{}
+		if err := yamlv3.Unmarshal(data, &v); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: configName,
+				Value:      err.Error()})
+			return
+		}
+		c.views[configName] = v
+	}
+}
+
+// RunCheck implements the Check logic for views access.
+func (c *ViewsAccessCheck) RunCheck() {
+	configNames := []string{}
+	for configName := range c.views {
+		configNames = append(configNames, configName)
+	}
+	sort.Strings(configNames)
+
+	for _, configName := range configNames {
+		v := c.views[configName]
+		if utils.StringSliceContains(c.ExcludeViews, v.Id) {
+			continue
+		}
+		// Disabled views are not accessible.
+		if v.Status != nil && !*v.Status {
+			continue
+		}
+
+		var defaultAccess *viewAccess
+		if d, ok := v.Display["default"]; ok {
+			defaultAccess = d.DisplayOptions.Access
+		}
+
+		displayIds := []string{}
+		for displayId := range v.Display {
+			displayIds = append(displayIds, displayId)
+		}
+		sort.Strings(displayIds)
+
+		for _, displayId := range displayIds {
+			d := v.Display[displayId]
+			if displayId == "default" || d.DisplayPlugin == "default" {
+				continue
+			}
+			key := v.Id + ":" + displayId
+			if utils.StringSliceContains(c.ExcludeViews, key) {
+				continue
+			}
+
+			access := d.DisplayOptions.Access
+			if access == nil {
+				access = defaultAccess
+			}
+
+			if access == nil || access.Type == "" || access.Type == "none" {
+				c.AddBreach(&result.KeyValueBreach{
+					KeyLabel:   "view",
+					Key:        key,
+					ValueLabel: "access",
+					Value:      "no access control",
+				})
+				continue
+			}
+
+			if utils.StringSliceContains(c.SensitiveBaseTables, v.BaseTable) &&
+				c.isAnonymousAccess(access) {
+				c.AddBreach(&result.KeyValueBreach{
+					KeyLabel:   "view",
+					Key:        key,
+					ValueLabel: "anonymous access to",
+					Value:      v.BaseTable,
+				})
+				continue
+			}
+			c.AddPass(fmt.Sprintf("[%s] access restricted by %s", key, access.Type))
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// isAnonymousAccess determines whether the access plugin settings allow
+// anonymous users through.
+func (c *ViewsAccessCheck) isAnonymousAccess(access *viewAccess) bool {
+	switch access.Type {
+	case "role":
+		roles, _ := access.Options["role"].(map[string]interface{})
+		for rid, val := range roles {
+			if rid == "anonymous" && val != nil && val != "" && val != 0 && val != false {
+				return true
+			}
+		}
+	case "perm":
+		perm, _ := access.Options["perm"].(string)
+		return utils.StringSliceContains(c.AnonymousPermissions, perm)
+	}
+	return false
+}
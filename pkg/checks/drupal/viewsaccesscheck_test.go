@@ -0,0 +1,175 @@
+package drupal_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewsAccessCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ViewsAccessCheck{}
+	c.Init(ViewsAccess)
+	assert.Equal(ViewsAccessDefaultPattern, c.Pattern)
+	assert.True(*c.IgnoreMissing)
+	assert.Equal([]string{"users_field_data", "webform_submission"}, c.SensitiveBaseTables)
+	assert.Equal([]string{"access content"}, c.AnonymousPermissions)
+
+	c = ViewsAccessCheck{YamlCheck: yaml.YamlCheck{File: "views.view.foo.yml"}}
+	c.Init(ViewsAccess)
+	assert.Equal("", c.Pattern)
+}
+
+func TestViewsAccessMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ViewsAccessCheck{
+		SensitiveBaseTables: []string{"users_field_data"},
+		ExcludeViews:        []string{"content"},
+	}
+	c.Merge(&ViewsAccessCheck{
+		YamlCheck:    yaml.YamlCheck{Path: "config/sync"},
+		ExcludeViews: []string{"frontpage"},
+	})
+	assert.EqualValues(ViewsAccessCheck{
+		YamlCheck:           yaml.YamlCheck{Path: "config/sync"},
+		SensitiveBaseTables: []string{"users_field_data"},
+		ExcludeViews:        []string{"frontpage"},
+	}, c)
+}
+
+func TestViewsAccessRunCheck(t *testing.T) {
+	viewsData := map[string][]byte{
+		"views.view.content.yml": []byte(`
+id: content
+base_table: node_field_data
+display:
+  default:
+    display_plugin: default
+    id: default
+    display_options:
+      access:
+        type: perm
+        options:
+          perm: 'access content overview'
+  page_1:
+    display_plugin: page
+    id: page_1
+`),
+		"views.view.open.yml": []byte(`
+id: open
+base_table: node_field_data
+display:
+  default:
+    display_plugin: default
+    display_options:
+      access:
+        type: none
+  rest_export_1:
+    display_plugin: rest_export
+`),
+		"views.view.people.yml": []byte(`
+id: people
+base_table: users_field_data
+display:
+  default:
+    display_plugin: default
+    display_options:
+      access:
+        type: perm
+        options:
+          perm: 'administer users'
+  page_1:
+    display_plugin: page
+  feed_1:
+    display_plugin: feed
+    display_options:
+      access:
+        type: role
+        options:
+          role:
+            anonymous: anonymous
+`),
+		"views.view.disabled.yml": []byte(`
+id: disabled
+status: false
+base_table: users_field_data
+display:
+  page_1:
+    display_plugin: page
+`),
+	}
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "noViews",
+			Check: &ViewsAccessCheck{
+				YamlCheck: yaml.YamlCheck{YamlBase: yaml.YamlBase{CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{}}}},
+			},
+			Init:         true,
+			ExpectStatus: result.Pass,
+			ExpectNoPass: true,
+			ExpectNoFail: true,
+		},
+		{
+			Name: "breaches",
+			Check: &ViewsAccessCheck{
+				YamlCheck: yaml.YamlCheck{YamlBase: yaml.YamlBase{CheckBase: config.CheckBase{
+					DataMap: viewsData}}},
+			},
+			Init:         true,
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{
+				"[content:page_1] access restricted by perm",
+				"[people:page_1] access restricted by perm",
+			},
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					Severity:   "normal",
+					KeyLabel:   "view",
+					Key:        "open:rest_export_1",
+					ValueLabel: "access",
+					Value:      "no access control",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					Severity:   "normal",
+					KeyLabel:   "view",
+					Key:        "people:feed_1",
+					ValueLabel: "anonymous access to",
+					Value:      "users_field_data",
+				},
+			},
+		},
+		{
+			Name: "excludedViews",
+			Check: &ViewsAccessCheck{
+				YamlCheck: yaml.YamlCheck{YamlBase: yaml.YamlBase{CheckBase: config.CheckBase{
+					DataMap: viewsData}}},
+				ExcludeViews: []string{"open", "people:feed_1"},
+			},
+			Init:         true,
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"[content:page_1] access restricted by perm",
+				"[people:page_1] access restricted by perm",
+			},
+			ExpectNoFail: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
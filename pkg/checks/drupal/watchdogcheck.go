@@ -0,0 +1,237 @@
+package drupal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Watchdog config.CheckType = "drupal-watchdog"
+
+const (
+	DefaultWatchdogWindow = "24h"
+	DefaultWatchdogCount  = 1000
+)
+
+// WatchdogEntry is a message logged in the site's watchdog (dblog).
+type WatchdogEntry struct {
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	Date     string `json:"date"`
+}
+
+// watchdogDateLayouts are the formats of the date of the entries, depending
+// on the drush version; the first one has no year.
+var watchdogDateLayouts = []string{"02/Jan 15:04", "2006-01-02 15:04:05"}
+
+// Time returns when the message was logged; ok is false if the date could not
+// be parsed.
+func (e WatchdogEntry) Time(now time.Time) (t time.Time, ok bool) {
+	for i, layout := range watchdogDateLayouts {
+		t, err := time.ParseInLocation(layout, e.Date, now.Location())
+		if err != nil {
+			continue
+		}
+		if i == 0 {
+			t = t.AddDate(now.Year(), 0, 0)
+			if t.After(now) {
+				t = t.AddDate(-1, 0, 0)
+			}
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// WatchdogCheck verifies the errors logged in the site's watchdog, as reported
+// by drush watchdog:show, over a recent time window.
+type WatchdogCheck struct {
+	config.CheckBase `yaml:",inline"`
+	DrushCommand     `yaml:",inline"`
+	// Time window of the errors to consider, e.g, 24h or 7d.
+	Window string `yaml:"window"`
+	// Maximum number of messages fetched.
+	Count int `yaml:"count"`
+	// Maximum number of errors over the window.
+	MaxErrors int `yaml:"max-errors"`
+	// Maximum number of errors over the window, by message type, e.g, php.
+	MaxErrorsByType map[string]int `yaml:"max-errors-by-type"`
+	// Messages which must not be logged; values can be regex (re:) or glob
+	// (glob:) patterns.
+	DisallowedMessages []string `yaml:"disallowed-messages"`
+	entries            []WatchdogEntry
+}
+
+// Init implementation for the drush-based watchdog check.
+func (c *WatchdogCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	c.RequiresDb = true
+	if c.Window == "" {
+		c.Window = DefaultWatchdogWindow
+	}
+	if c.Count == 0 {
+		c.Count = DefaultWatchdogCount
+	}
+}
+
+// Merge implementation for WatchdogCheck check.
+func (c *WatchdogCheck) Merge(mergeCheck config.Check) error {
+	watchdogMergeCheck := mergeCheck.(*WatchdogCheck)
+	if err := c.CheckBase.Merge(&watchdogMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.DrushCommand.Merge(watchdogMergeCheck.DrushCommand)
+	utils.MergeString(&c.Window, watchdogMergeCheck.Window)
+	if watchdogMergeCheck.Count != 0 {
+		c.Count = watchdogMergeCheck.Count
+	}
+	if watchdogMergeCheck.MaxErrors != 0 {
+		c.MaxErrors = watchdogMergeCheck.MaxErrors
+	}
+	if len(watchdogMergeCheck.MaxErrorsByType) > 0 && c.MaxErrorsByType == nil {
+		c.MaxErrorsByType = map[string]int{}
+	}
+	for t, max := range watchdogMergeCheck.MaxErrorsByType {
+		c.MaxErrorsByType[t] = max
+	}
+	utils.MergeStringSlice(&c.DisallowedMessages, watchdogMergeCheck.DisallowedMessages)
+	return nil
+}
+
+func (c *WatchdogCheck) args() []string {
+	return []string{"watchdog:show", "--severity=Error", "--extended",
+		"--count=" + strconv.Itoa(c.Count), "--format=json"}
+}
+
+// FetchData runs the drush command to populate data for the watchdog check.
+func (c *WatchdogCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	// Command: drush watchdog:show --severity=Error --extended --count=1000 --format=json
+	c.DataMap["watchdog"], err = Drush(c.DrushPath, c.Alias, c.args()).Exec()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+	}
+}
+
+// UnmarshalDataMap parses the drush watchdog:show json.
+func (c *WatchdogCheck) UnmarshalDataMap() {
+	// Unmarshal watchdog:show JSON.
+	// {
+	//    "1234": {
+	//      "wid": "1234",
+	//      "type": "php",
+	//      "message": "Error: Call to undefined function foo()",
+	//      "severity": "Error",
+	//      "date": "14/Oct 10:00"
+	//    }
+	// }
+	c.entries = []WatchdogEntry{}
+	data := bytes.TrimSpace(c.DataMap["watchdog"])
+	// Drush outputs nothing, or an empty list, when there are no messages.
+	if len(data) == 0 || string(data) == "[]" {
+		return
+	}
+	entries := map[string]WatchdogEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+		return
+	}
+	wids := []string{}
+	for wid := range entries {
+		wids = append(wids, wid)
+	}
+	sort.Strings(wids)
+	for _, wid := range wids {
+		c.entries = append(c.entries, entries[wid])
+	}
+}
+
+// RunCheck implements the Check logic for the watchdog errors.
+func (c *WatchdogCheck) RunCheck() {
+	window, err := utils.ParseDuration(c.Window)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid window",
+			Value:      err.Error(),
+		})
+		return
+	}
+
+	now := utils.TimeNow()
+	total := 0
+	countByType := map[string]int{}
+	disallowed := map[string][]string{}
+	for _, e := range c.entries {
+		// Entries whose date is unknown are considered recent.
+		if t, ok := e.Time(now); ok && now.Sub(t) > window {
+			continue
+		}
+		total++
+		countByType[e.Type]++
+		if utils.StringSliceMatchAny(c.DisallowedMessages, e.Message) &&
+			!utils.StringSliceContains(disallowed[e.Type], e.Message) {
+			disallowed[e.Type] = append(disallowed[e.Type], e.Message)
+		}
+	}
+
+	if total > c.MaxErrors {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "errors logged in the last " + c.Window,
+			Value:      fmt.Sprintf("%d (max: %d)", total, c.MaxErrors),
+		})
+	}
+
+	types := []string{}
+	for t := range c.MaxErrorsByType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if countByType[t] > c.MaxErrorsByType[t] {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "type",
+				Key:        t,
+				ValueLabel: "errors logged in the last " + c.Window,
+				Value:      fmt.Sprintf("%d (max: %d)", countByType[t], c.MaxErrorsByType[t]),
+			})
+		}
+	}
+
+	types = []string{}
+	for t := range disallowed {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		for _, msg := range disallowed[t] {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "type",
+				Key:        t,
+				ValueLabel: "disallowed message",
+				Value:      strings.TrimSpace(msg),
+			})
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.AddPass(fmt.Sprintf("%d errors logged in the last %s", total, c.Window))
+		c.Result.Status = result.Pass
+	}
+}
+
+// Commands implements config.CommandReporter.
+func (c *WatchdogCheck) Commands() [][]string {
+	return [][]string{Drush(c.DrushPath, c.Alias, c.args()).Line()}
+}
@@ -0,0 +1,233 @@
+package drupal_test
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdogCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := WatchdogCheck{}
+	c.Init(Watchdog)
+	assert.True(c.RequiresDb)
+	assert.Equal("24h", c.Window)
+	assert.Equal(1000, c.Count)
+
+	c = WatchdogCheck{Window: "7d", Count: 50}
+	c.Init(Watchdog)
+	assert.Equal("7d", c.Window)
+	assert.Equal(50, c.Count)
+}
+
+func TestWatchdogCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := WatchdogCheck{
+		DrushCommand:    DrushCommand{DrushPath: "/path/to/drush"},
+		Window:          "24h",
+		MaxErrorsByType: map[string]int{"php": 1},
+	}
+	err := c.Merge(&WatchdogCheck{
+		DrushCommand:       DrushCommand{Alias: "prod"},
+		Window:             "7d",
+		MaxErrors:          10,
+		MaxErrorsByType:    map[string]int{"cron": 0},
+		DisallowedMessages: []string{"glob:*PDOException*"},
+	})
+	assert.NoError(err)
+	assert.Equal("/path/to/drush", c.DrushPath)
+	assert.Equal("prod", c.Alias)
+	assert.Equal("7d", c.Window)
+	assert.Equal(10, c.MaxErrors)
+	assert.Equal(map[string]int{"php": 1, "cron": 0}, c.MaxErrorsByType)
+	assert.Equal([]string{"glob:*PDOException*"}, c.DisallowedMessages)
+}
+
+func TestWatchdogCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil,
+		&exec.ExitError{Stderr: []byte("unable to run drush command")},
+		nil)
+	c := WatchdogCheck{}
+	c.Init(Watchdog)
+	c.FetchData()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			CheckType:  "drupal-watchdog",
+			Severity:   "normal",
+			Value:      "unable to run drush command",
+		}},
+		c.Result.Breaches,
+	)
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{`{}`}[0], nil, &generatedCommand)
+	c = WatchdogCheck{DrushCommand: DrushCommand{Alias: "prod"}, Count: 50}
+	c.Init(Watchdog)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("vendor/drush/drush/drush @prod watchdog:show --severity=Error --extended --count=50 --format=json", generatedCommand)
+	assert.Equal([][]string{{"vendor/drush/drush/drush", "@prod", "watchdog:show",
+		"--severity=Error", "--extended", "--count=50", "--format=json"}}, c.Commands())
+}
+
+func TestWatchdogCheckUnmarshalDataMap(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, data := range []string{"", "[]\n"} {
+		c := WatchdogCheck{
+			CheckBase: config.CheckBase{
+				DataMap: map[string][]byte{"watchdog": []byte(data)},
+			},
+		}
+		c.UnmarshalDataMap()
+		assert.Empty(c.Result.Breaches)
+	}
+
+	c := WatchdogCheck{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{"watchdog": []byte(`{"1":`)},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "unexpected end of JSON input",
+		}},
+		c.Result.Breaches,
+	)
+}
+
+func TestWatchdogEntryTime(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tm, ok := WatchdogEntry{Date: "02/Jan 09:30"}.Time(now)
+	assert.True(ok)
+	assert.Equal(time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC), tm)
+
+	// Dates without a year later than now are from the previous year.
+	tm, ok = WatchdogEntry{Date: "31/Dec 23:00"}.Time(now)
+	assert.True(ok)
+	assert.Equal(time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC), tm)
+
+	tm, ok = WatchdogEntry{Date: "2025-12-30 08:15:00"}.Time(now)
+	assert.True(ok)
+	assert.Equal(time.Date(2025, 12, 30, 8, 15, 0, 0, time.UTC), tm)
+
+	_, ok = WatchdogEntry{Date: "yesterday"}.Time(now)
+	assert.False(ok)
+}
+
+func TestWatchdogCheckRunCheck(t *testing.T) {
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local) }
+
+	entries := `{
+	"101": {"wid": "101", "type": "php", "message": "PDOException: SQLSTATE[HY000] gone away", "severity": "Error", "date": "16/Oct 11:00"},
+	"102": {"wid": "102", "type": "php", "message": "PDOException: SQLSTATE[HY000] gone away", "severity": "Error", "date": "16/Oct 11:30"},
+	"103": {"wid": "103", "type": "cron", "message": "Cron run exceeded the time limit", "severity": "Error", "date": "16/Oct 02:00"},
+	"90": {"wid": "90", "type": "php", "message": "Warning: Undefined array key", "severity": "Error", "date": "10/Oct 08:00"}
+}`
+
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "defaults",
+			Check:        &WatchdogCheck{},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.ValueBreach{
+					BreachType: "value",
+					CheckType:  "drupal-watchdog",
+					Severity:   "normal",
+					ValueLabel: "errors logged in the last 24h",
+					Value:      "3 (max: 0)",
+				},
+			},
+		},
+		{
+			Name: "thresholds",
+			Check: &WatchdogCheck{
+				Window:             "7d",
+				MaxErrors:          5,
+				MaxErrorsByType:    map[string]int{"php": 2, "cron": 1},
+				DisallowedMessages: []string{"glob:PDOException*"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "drupal-watchdog",
+					Severity:   "normal",
+					KeyLabel:   "type",
+					Key:        "php",
+					ValueLabel: "errors logged in the last 7d",
+					Value:      "3 (max: 2)",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "drupal-watchdog",
+					Severity:   "normal",
+					KeyLabel:   "type",
+					Key:        "php",
+					ValueLabel: "disallowed message",
+					Value:      "PDOException: SQLSTATE[HY000] gone away",
+				},
+			},
+		},
+		{
+			Name:         "withinThresholds",
+			Check:        &WatchdogCheck{Window: "2h", MaxErrors: 2, MaxErrorsByType: map[string]int{"php": 2}},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"2 errors logged in the last 2h"},
+			ExpectNoFail: true,
+		},
+		{
+			Name:         "invalidWindow",
+			Check:        &WatchdogCheck{Window: "a day"},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.ValueBreach{
+					BreachType: "value",
+					CheckType:  "drupal-watchdog",
+					Severity:   "normal",
+					ValueLabel: "invalid window",
+					Value:      `time: invalid duration "a day"`,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			c := tc.Check.(*WatchdogCheck)
+			c.Init(Watchdog)
+			c.DataMap = map[string][]byte{"watchdog": []byte(entries)}
+			c.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Package env provides checks against the environment variables available
+// to shipshape.
+package env
+
+import (
+	"path"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=env
+
+// MaskedValue replaces the value of variables considered secrets.
+const MaskedValue = "********"
+
+// DefaultSecretPatterns are the variable name patterns for which values are
+// masked when no secret patterns are provided. Patterns are case-sensitive.
+var DefaultSecretPatterns = []string{
+	"*PASSWORD*", "*PASSWD*", "*SECRET*", "*TOKEN*", "*_KEY", "*_KEY_*",
+	"*CREDENTIALS*", "*PRIVATE*",
+}
+
+func RegisterChecks() {
+	config.ChecksRegistry[Vars] = func() config.Check { return &VarsCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// matchAny determines whether the name matches any of the glob patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if match, _ := path.Match(p, name); match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const Vars config.CheckType = "env-vars"
+
+// VarsCheck captures the environment variables matching the provided
+// patterns and verifies them using the same values as the yaml check.
+// Values of variables considered secrets are verified as-is but masked in
+// the breaches and passes, so that they never end up in the report.
+type VarsCheck struct {
+	yaml.YamlBase `yaml:",inline"`
+	// Glob patterns of the variables to capture; defaults to all variables.
+	Vars []string `yaml:"vars"`
+	// Variables which must be defined.
+	Required []string `yaml:"required"`
+	// Glob patterns of the variable names for which values are masked.
+	SecretPatterns []string `yaml:"secret-patterns"`
+	// Names of the captured variables.
+	names []string
+	// Values of the captured secrets, by variable name.
+	secrets map[string]string
+}
+
+// Init implementation for the env-vars check.
+func (c *VarsCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if len(c.Vars) == 0 {
+		c.Vars = []string{"*"}
+	}
+	if len(c.SecretPatterns) == 0 {
+		c.SecretPatterns = DefaultSecretPatterns
+	}
+}
+
+// Merge implementation for env-vars check.
+func (c *VarsCheck) Merge(mergeCheck config.Check) error {
+	varsMergeCheck := mergeCheck.(*VarsCheck)
+	if err := c.YamlBase.Merge(&varsMergeCheck.YamlBase); err != nil {
+		return err
+	}
+
+	utils.MergeStringSlice(&c.Vars, varsMergeCheck.Vars)
+	utils.MergeStringSlice(&c.Required, varsMergeCheck.Required)
+	utils.MergeStringSlice(&c.SecretPatterns, varsMergeCheck.SecretPatterns)
+	return nil
+}
+
+// FetchData captures the matching environment variables, as well as the
+// required ones.
+func (c *VarsCheck) FetchData() {
+	vars := map[string]string{}
+	c.names = []string{}
+	c.secrets = map[string]string{}
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if !matchAny(c.Vars, name) && !utils.StringSliceContains(c.Required, name) {
+			continue
+		}
+		vars[name] = value
+		c.names = append(c.names, name)
+		if value != "" && matchAny(c.SecretPatterns, name) {
+			c.secrets[name] = value
+		}
+	}
+
+	data, err := yamlv3.Marshal(vars)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+		return
+	}
+	c.DataMap = map[string][]byte{"env": data}
+}
+
+// RunCheck verifies the required variables are defined, then the values,
+// masking the secrets as the breaches and passes are added.
+func (c *VarsCheck) RunCheck() {
+	missing := utils.StringSlicesInterdiffUnique(c.names, c.Required)
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			Key:    "required variables not set",
+			Values: missing,
+		})
+	} else if len(c.Required) > 0 {
+		c.AddPass(fmt.Sprintf("all %d required variables are set", len(c.Required)))
+	}
+
+	c.PublishData()
+	for _, kv := range c.Values {
+		docs, err := kv.SelectDocuments(c.Documents["env"])
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{Value: err.Error()})
+			continue
+		}
+		kvr, fails, err := yaml.CheckDocumentsKeyValue(docs, kv)
+		// Only the values of the secret variables are masked, so that values
+		// of other variables are left intact, even if they happen to contain
+		// a secret.
+		if secret, ok := c.secrets[kv.Key]; ok {
+			kv.Value = maskValue(kv.Value, secret)
+			for i, f := range fails {
+				fails[i] = maskValue(f, secret)
+			}
+		}
+		yaml.AddKeyValueResult(&c.CheckBase, "env", "config:env", kv, kvr, fails, err)
+	}
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// maskValue masks a value which is the secret itself or a message starting
+// with it, e.g, "s3cr3t does not match '^sk_'".
+func maskValue(v string, secret string) string {
+	if strings.EqualFold(v, secret) {
+		return MaskedValue
+	}
+	if rest, ok := strings.CutPrefix(v, secret+" "); ok {
+		return MaskedValue + " " + rest
+	}
+	return v
+}
+
+// MaskedDataMap implements config.DataMasker, masking the values of the
+// variables considered secrets.
+func (c *VarsCheck) MaskedDataMap() map[string][]byte {
+	vars := map[string]string{}
+	if err := yamlv3.Unmarshal(c.DataMap["env"], &vars); err != nil {
+		return map[string][]byte{}
+	}
+	for name, value := range vars {
+		if matchAny(c.SecretPatterns, name) {
+			vars[name] = result.MaskValue(value)
+		}
+	}
+	data, err := yamlv3.Marshal(vars)
+	if err != nil {
+		return map[string][]byte{}
+	}
+	return map[string][]byte{"env": data}
+}
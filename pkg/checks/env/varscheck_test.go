@@ -0,0 +1,251 @@
+package env_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/env"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Vars]()
+	assert.Equal(t, "*env.VarsCheck", reflect.TypeOf(c).String())
+}
+
+func TestVarsCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := VarsCheck{}
+	c.Init(Vars)
+	assert.Equal([]string{"*"}, c.Vars)
+	assert.Equal(DefaultSecretPatterns, c.SecretPatterns)
+
+	c = VarsCheck{Vars: []string{"APP_*"}, SecretPatterns: []string{"*_DSN"}}
+	c.Init(Vars)
+	assert.Equal([]string{"APP_*"}, c.Vars)
+	assert.Equal([]string{"*_DSN"}, c.SecretPatterns)
+}
+
+func TestVarsCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := VarsCheck{
+		Vars:     []string{"APP_*"},
+		Required: []string{"APP_ENV"},
+	}
+	err := c.Merge(&VarsCheck{
+		Required:       []string{"APP_ENV", "APP_KEY"},
+		SecretPatterns: []string{"*_DSN"},
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"APP_*"}, c.Vars)
+	assert.Equal([]string{"APP_ENV", "APP_KEY"}, c.Required)
+	assert.Equal([]string{"*_DSN"}, c.SecretPatterns)
+}
+
+func TestVarsCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("SHIPSHAPE_TEST_ENV", "production")
+	t.Setenv("SHIPSHAPE_TEST_TOKEN", "s3cr3t")
+	t.Setenv("OTHER_TEST_VAR", "other")
+
+	c := VarsCheck{Vars: []string{"SHIPSHAPE_TEST_*"}}
+	c.Init(Vars)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("SHIPSHAPE_TEST_ENV: production\nSHIPSHAPE_TEST_TOKEN: s3cr3t\n",
+		string(c.DataMap["env"]))
+
+	// Required variables are captured regardless of the patterns.
+	c = VarsCheck{Vars: []string{"SHIPSHAPE_TEST_ENV"}, Required: []string{"OTHER_TEST_VAR"}}
+	c.Init(Vars)
+	c.FetchData()
+	assert.Equal("OTHER_TEST_VAR: other\nSHIPSHAPE_TEST_ENV: production\n",
+		string(c.DataMap["env"]))
+}
+
+func TestVarsCheckMaskedDataMap(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("SHIPSHAPE_TEST_ENV", "production")
+	t.Setenv("SHIPSHAPE_TEST_TOKEN", "s3cr3t")
+
+	c := VarsCheck{Vars: []string{"SHIPSHAPE_TEST_*"}}
+	c.Init(Vars)
+	c.FetchData()
+	assert.Equal(map[string][]byte{"env": []byte("SHIPSHAPE_TEST_ENV: production\nSHIPSHAPE_TEST_TOKEN: " +
+		result.MaskValue("s3cr3t") + "\n")}, c.MaskedDataMap())
+	// The fetched data is left untouched.
+	assert.Contains(string(c.DataMap["env"]), "s3cr3t")
+}
+
+func TestVarsCheckRunCheck(t *testing.T) {
+	t.Setenv("SHIPSHAPE_TEST_ENV", "production")
+	t.Setenv("SHIPSHAPE_TEST_DEBUG", "false")
+	t.Setenv("SHIPSHAPE_TEST_TOKEN", "s3cr3t")
+	t.Setenv("SHIPSHAPE_TEST_WORKERS", "10")
+	t.Setenv("SHIPSHAPE_TEST_PIN_TOKEN", "1")
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "expectedValues",
+			Check: &VarsCheck{
+				YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+					{Key: "SHIPSHAPE_TEST_ENV", Value: "production"},
+					{Key: "SHIPSHAPE_TEST_TOKEN", Value: "s3cr3t"},
+				}},
+				Vars:     []string{"SHIPSHAPE_TEST_*"},
+				Required: []string{"SHIPSHAPE_TEST_ENV", "SHIPSHAPE_TEST_TOKEN"},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"all 2 required variables are set",
+				"[env] 'SHIPSHAPE_TEST_ENV' equals 'production'",
+				"[env] 'SHIPSHAPE_TEST_TOKEN' equals '********'",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "unexpectedValues",
+			Check: &VarsCheck{
+				YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+					{Key: "SHIPSHAPE_TEST_DEBUG", Value: "true"},
+					{Key: "SHIPSHAPE_TEST_TOKEN", Value: "changeme"},
+				}},
+				Vars:     []string{"SHIPSHAPE_TEST_*"},
+				Required: []string{"SHIPSHAPE_TEST_KEY", "SHIPSHAPE_TEST_ENV", "SHIPSHAPE_TEST_DSN"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "env-vars",
+					Severity:   "normal",
+					Key:        "required variables not set",
+					Values:     []string{"SHIPSHAPE_TEST_DSN", "SHIPSHAPE_TEST_KEY"},
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "env-vars",
+					Severity:      "normal",
+					KeyLabel:      "config:env",
+					Key:           "SHIPSHAPE_TEST_DEBUG",
+					ValueLabel:    "actual",
+					Value:         "false",
+					ExpectedValue: "true",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "env-vars",
+					Severity:      "normal",
+					KeyLabel:      "config:env",
+					Key:           "SHIPSHAPE_TEST_TOKEN",
+					ValueLabel:    "actual",
+					Value:         "********",
+					ExpectedValue: "changeme",
+				},
+			},
+		},
+		{
+			Name: "disallowedSecret",
+			Check: &VarsCheck{
+				YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+					{Key: "SHIPSHAPE_TEST_TOKEN", Disallowed: []string{"s3cr3t"}},
+				}},
+				Vars: []string{"SHIPSHAPE_TEST_TOKEN"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "env-vars",
+					Severity:   "normal",
+					KeyLabel:   "config",
+					Key:        "env",
+					ValueLabel: "disallowed SHIPSHAPE_TEST_TOKEN",
+					Values:     []string{"********"},
+				},
+			},
+		},
+		{
+			Name: "patternSecret",
+			Check: &VarsCheck{
+				YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+					{Key: "SHIPSHAPE_TEST_TOKEN", Pattern: "^sk_"},
+					{Key: "SHIPSHAPE_TEST_ENV", Pattern: "^s3cr3t"},
+				}},
+				Vars: []string{"SHIPSHAPE_TEST_TOKEN", "SHIPSHAPE_TEST_ENV"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "env-vars",
+					Severity:   "normal",
+					KeyLabel:   "config",
+					Key:        "env",
+					ValueLabel: "invalid value for SHIPSHAPE_TEST_TOKEN",
+					Values:     []string{"******** does not match '^sk_'"},
+				},
+				// Only the values of the secret variables are masked.
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "env-vars",
+					Severity:   "normal",
+					KeyLabel:   "config",
+					Key:        "env",
+					ValueLabel: "invalid value for SHIPSHAPE_TEST_ENV",
+					Values:     []string{"production does not match '^s3cr3t'"},
+				},
+			},
+		},
+		{
+			Name: "shortSecret",
+			Check: &VarsCheck{
+				YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+					{Key: "SHIPSHAPE_TEST_WORKERS", Min: "12"},
+					{Key: "SHIPSHAPE_TEST_PIN_TOKEN", Min: "2"},
+				}},
+				Vars: []string{"SHIPSHAPE_TEST_WORKERS", "SHIPSHAPE_TEST_PIN_TOKEN"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "env-vars",
+					Severity:   "normal",
+					KeyLabel:   "config",
+					Key:        "env",
+					ValueLabel: "out of range SHIPSHAPE_TEST_WORKERS",
+					Values:     []string{"10 is less than 12"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "env-vars",
+					Severity:   "normal",
+					KeyLabel:   "config",
+					Key:        "env",
+					ValueLabel: "out of range SHIPSHAPE_TEST_PIN_TOKEN",
+					Values:     []string{"******** is less than 2"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Vars)
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
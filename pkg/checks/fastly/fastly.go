@@ -0,0 +1,73 @@
+// Package fastly provides checks against the Fastly API.
+package fastly
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=fastly
+
+// DefaultApiUrl is the Fastly API used when none is provided.
+const DefaultApiUrl = "https://api.fastly.com"
+
+func RegisterChecks() {
+	config.ChecksRegistry[Service] = func() config.Check { return &ServiceCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// apiError is the error returned by the API.
+type apiError struct {
+	Msg    string `json:"msg"`
+	Detail string `json:"detail"`
+}
+
+// apiGet queries the API, authenticating with the token; it returns the
+// response body and status code.
+func apiGet(apiUrl string, token string, path string) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(apiUrl, "/")+path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Fastly-Key", token)
+
+	resp, err := utils.HttpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := apiError{}
+		json.Unmarshal(body, &apiErr)
+		msg := apiErr.Msg
+		if apiErr.Detail != "" {
+			msg += ": " + apiErr.Detail
+		}
+		return body, resp.StatusCode, fmt.Errorf("%s (%d)", msg, resp.StatusCode)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// isTruthy determines whether an API value is true; booleans are returned
+// either as such or as "1"/"0".
+func isTruthy(v any) bool {
+	switch fmt.Sprint(v) {
+	case "true", "1":
+		return true
+	}
+	return false
+}
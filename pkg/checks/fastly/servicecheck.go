@@ -0,0 +1,251 @@
+package fastly
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Service config.CheckType = "fastly-service"
+
+// ServiceCheck verifies the active version of a Fastly service: its
+// settings, TLS enforcement and cache settings.
+type ServiceCheck struct {
+	config.CheckBase `yaml:",inline"`
+	ServiceId        string `yaml:"service-id"`
+	// Url of the API.
+	ApiUrl string `yaml:"api-url"`
+	// Token authenticating the API requests, which can be an encrypted
+	// value; defaults to the FASTLY_API_TOKEN environment variable.
+	ApiToken string `yaml:"api-token"`
+	// Expected values of the version's settings, e.g, general.default_ttl.
+	Settings map[string]string `yaml:"settings"`
+	// Require requests to be redirected to https.
+	ForceTls bool `yaml:"force-tls"`
+	// Require at least one cache setting.
+	CacheSettings bool `yaml:"cache-settings"`
+	version       int
+	settings      map[string]string
+	forceTls      bool
+	cacheSettings int
+}
+
+// Init implementation for the fastly-service check.
+func (c *ServiceCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.ApiUrl == "" {
+		c.ApiUrl = DefaultApiUrl
+	}
+}
+
+// Merge implementation for fastly-service check.
+func (c *ServiceCheck) Merge(mergeCheck config.Check) error {
+	serviceMergeCheck := mergeCheck.(*ServiceCheck)
+	if err := c.CheckBase.Merge(&serviceMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.ServiceId, serviceMergeCheck.ServiceId)
+	utils.MergeString(&c.ApiUrl, serviceMergeCheck.ApiUrl)
+	utils.MergeString(&c.ApiToken, serviceMergeCheck.ApiToken)
+	if len(serviceMergeCheck.Settings) > 0 {
+		c.Settings = serviceMergeCheck.Settings
+	}
+	if serviceMergeCheck.ForceTls {
+		c.ForceTls = true
+	}
+	if serviceMergeCheck.CacheSettings {
+		c.CacheSettings = true
+	}
+	return nil
+}
+
+// apiToken returns the token authenticating the API requests.
+func (c *ServiceCheck) apiToken() string {
+	if c.ApiToken != "" {
+		return c.ApiToken
+	}
+	return os.Getenv("FASTLY_API_TOKEN")
+}
+
+// FetchData queries the service for its active version, then the version's
+// settings, request settings and cache settings.
+func (c *ServiceCheck) FetchData() {
+	if c.ServiceId == "" {
+		c.AddBreach(&result.ValueBreach{Value: "no service-id provided"})
+		return
+	}
+	if c.apiToken() == "" {
+		c.AddBreach(&result.ValueBreach{Value: "FASTLY_API_TOKEN is not set"})
+		return
+	}
+
+	data, _, err := apiGet(c.ApiUrl, c.apiToken(), "/service/"+c.ServiceId)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to fetch service " + c.ServiceId,
+			Value:      err.Error()})
+		return
+	}
+	service := struct {
+		Versions []struct {
+			Number int  `json:"number"`
+			Active bool `json:"active"`
+		} `json:"versions"`
+	}{}
+	json.Unmarshal(data, &service)
+	c.version = 0
+	for _, v := range service.Versions {
+		if v.Active {
+			c.version = v.Number
+		}
+	}
+	if c.version == 0 {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "no active version for service",
+			Value:      c.ServiceId})
+		return
+	}
+
+	c.DataMap = map[string][]byte{}
+	versionPath := fmt.Sprintf("/service/%s/version/%d", c.ServiceId, c.version)
+	endpoints := map[string]bool{
+		"settings":         len(c.Settings) > 0,
+		"request_settings": c.ForceTls,
+		"cache_settings":   c.CacheSettings,
+	}
+	for _, e := range []string{"settings", "request_settings", "cache_settings"} {
+		if !endpoints[e] {
+			continue
+		}
+		c.DataMap[e], _, err = apiGet(c.ApiUrl, c.apiToken(), versionPath+"/"+e)
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: fmt.Sprintf("unable to fetch %s for version %d", e, c.version),
+				Value:      err.Error()})
+			return
+		}
+	}
+}
+
+// UnmarshalDataMap parses the API responses.
+func (c *ServiceCheck) UnmarshalDataMap() {
+	c.settings = map[string]string{}
+	if data, ok := c.DataMap["settings"]; ok {
+		settings := map[string]any{}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to parse settings", Value: err.Error()})
+			return
+		}
+		for k, v := range settings {
+			if f, ok := v.(float64); ok {
+				c.settings[k] = strconv.FormatFloat(f, 'f', -1, 64)
+			} else if v != nil {
+				c.settings[k] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	c.forceTls = false
+	if data, ok := c.DataMap["request_settings"]; ok {
+		requestSettings := []struct {
+			ForceSsl any `json:"force_ssl"`
+		}{}
+		if err := json.Unmarshal(data, &requestSettings); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to parse request settings", Value: err.Error()})
+			return
+		}
+		for _, rs := range requestSettings {
+			if isTruthy(rs.ForceSsl) {
+				c.forceTls = true
+			}
+		}
+	}
+
+	c.cacheSettings = 0
+	if data, ok := c.DataMap["cache_settings"]; ok {
+		cacheSettings := []any{}
+		if err := json.Unmarshal(data, &cacheSettings); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to parse cache settings", Value: err.Error()})
+			return
+		}
+		c.cacheSettings = len(cacheSettings)
+	}
+}
+
+// RunCheck verifies the active version's configuration.
+func (c *ServiceCheck) RunCheck() {
+	key := fmt.Sprintf("%s (version %d)", c.ServiceId, c.version)
+
+	names := []string{}
+	for name := range c.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		expected := c.Settings[name]
+		value, ok := c.settings[name]
+		if !ok {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "service",
+				Key:        key,
+				ValueLabel: "setting not found",
+				Value:      name,
+			})
+		} else if !utils.MatchString(expected, value) {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "service",
+				Key:           key,
+				ValueLabel:    name,
+				ExpectedValue: expected,
+				Value:         value,
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] %s is %s", key, name, value))
+		}
+	}
+
+	if c.ForceTls {
+		if !c.forceTls {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "service",
+				Key:        key,
+				ValueLabel: "force tls",
+				Value:      "not enabled in any request setting",
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] tls is enforced", key))
+		}
+	}
+
+	if c.CacheSettings {
+		if c.cacheSettings == 0 {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "service",
+				Key:        key,
+				ValueLabel: "cache settings",
+				Value:      "none configured",
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] %d cache setting(s) configured", key, c.cacheSettings))
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *ServiceCheck) RequiresNetwork() bool {
+	return true
+}
@@ -0,0 +1,197 @@
+package fastly_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/fastly"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Service]()
+	assert.Equal(t, "*fastly.ServiceCheck", reflect.TypeOf(c).String())
+}
+
+func TestServiceCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ServiceCheck{ServiceId: "SU1Z0isxPaozGVKXdv0eY", ForceTls: true}
+	err := c.Merge(&ServiceCheck{Settings: map[string]string{"general.default_ttl": "3600"}})
+	assert.NoError(err)
+	assert.Equal(ServiceCheck{
+		ServiceId: "SU1Z0isxPaozGVKXdv0eY",
+		Settings:  map[string]string{"general.default_ttl": "3600"},
+		ForceTls:  true,
+	}, c)
+}
+
+// newTestServer serves the testdata for the active version of the service;
+// the cache settings are empty if configured is false.
+func newTestServer(t *testing.T, configured bool) *httptest.Server {
+	mux := http.NewServeMux()
+	serve := func(path string, file string) {
+		data, _ := os.ReadFile(file)
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "secret", r.Header.Get("Fastly-Key"))
+			w.Write(data)
+		})
+	}
+	serve("/service/SU1Z0isxPaozGVKXdv0eY", "testdata/service.json")
+	serve("/service/SU1Z0isxPaozGVKXdv0eY/version/2/settings", "testdata/settings.json")
+	if configured {
+		serve("/service/SU1Z0isxPaozGVKXdv0eY/version/2/request_settings", "testdata/request_settings.json")
+		serve("/service/SU1Z0isxPaozGVKXdv0eY/version/2/cache_settings", "testdata/cache_settings.json")
+	} else {
+		mux.HandleFunc("/service/SU1Z0isxPaozGVKXdv0eY/version/2/cache_settings", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[]`))
+		})
+	}
+	mux.HandleFunc("/service/inactive", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"inactive","versions":[{"number":1,"active":false}]}`))
+	})
+	mux.HandleFunc("/service/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"msg":"Record not found","detail":"Cannot find service 'missing'"}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestServiceCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer(t, true)
+	defer srv.Close()
+
+	c := ServiceCheck{ServiceId: "SU1Z0isxPaozGVKXdv0eY", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		Value:      "FASTLY_API_TOKEN is not set",
+	}}, c.Result.Breaches)
+
+	// The token can be provided in the config.
+	c = ServiceCheck{ServiceId: "SU1Z0isxPaozGVKXdv0eY", ApiUrl: srv.URL, ApiToken: "secret"}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+
+	t.Setenv("FASTLY_API_TOKEN", "secret")
+	c = ServiceCheck{ServiceId: "missing", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "unable to fetch service missing",
+		Value:      "Record not found: Cannot find service 'missing' (404)",
+	}}, c.Result.Breaches)
+
+	c = ServiceCheck{ServiceId: "inactive", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "no active version for service",
+		Value:      "inactive",
+	}}, c.Result.Breaches)
+
+	c = ServiceCheck{ServiceId: "SU1Z0isxPaozGVKXdv0eY", ApiUrl: srv.URL, ForceTls: true}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Len(c.DataMap, 1)
+	assert.Contains(c.DataMap, "request_settings")
+}
+
+func TestServiceCheckRunCheck(t *testing.T) {
+	t.Setenv("FASTLY_API_TOKEN", "secret")
+
+	tt := []struct {
+		internal.RunCheckTest
+		configured bool
+	}{
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "compliant",
+				Check: &ServiceCheck{
+					ServiceId: "SU1Z0isxPaozGVKXdv0eY",
+					Settings: map[string]string{
+						"general.default_ttl":    "3600",
+						"general.stale_if_error": "false",
+					},
+					ForceTls:      true,
+					CacheSettings: true,
+				},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{
+					"[SU1Z0isxPaozGVKXdv0eY (version 2)] general.default_ttl is 3600",
+					"[SU1Z0isxPaozGVKXdv0eY (version 2)] general.stale_if_error is false",
+					"[SU1Z0isxPaozGVKXdv0eY (version 2)] tls is enforced",
+					"[SU1Z0isxPaozGVKXdv0eY (version 2)] 1 cache setting(s) configured",
+				},
+				ExpectNoFail: true,
+			},
+			configured: true,
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "nonCompliant",
+				Check: &ServiceCheck{
+					ServiceId: "SU1Z0isxPaozGVKXdv0eY",
+					Settings: map[string]string{
+						"general.stale_if_error": "true",
+						"general.unknown":        "1",
+					},
+					CacheSettings: true,
+				},
+				ExpectStatus: result.Fail,
+				ExpectNoPass: true,
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "fastly-service",
+						Severity:      "normal",
+						KeyLabel:      "service",
+						Key:           "SU1Z0isxPaozGVKXdv0eY (version 2)",
+						ValueLabel:    "general.stale_if_error",
+						ExpectedValue: "true",
+						Value:         "false",
+					},
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "fastly-service",
+						Severity:   "normal",
+						KeyLabel:   "service",
+						Key:        "SU1Z0isxPaozGVKXdv0eY (version 2)",
+						ValueLabel: "setting not found",
+						Value:      "general.unknown",
+					},
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "fastly-service",
+						Severity:   "normal",
+						KeyLabel:   "service",
+						Key:        "SU1Z0isxPaozGVKXdv0eY (version 2)",
+						ValueLabel: "cache settings",
+						Value:      "none configured",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			srv := newTestServer(t, tc.configured)
+			defer srv.Close()
+			c := tc.Check.(*ServiceCheck)
+			c.ApiUrl = srv.URL
+			c.Init(Service)
+			c.FetchData()
+			c.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc.RunCheckTest)
+		})
+	}
+}
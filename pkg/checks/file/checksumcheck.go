@@ -0,0 +1,203 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Checksum config.CheckType = "file:checksum"
+
+// ChecksumAlgorithms is the list of supported hash algorithms.
+var ChecksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// ChecksumCheck computes the checksum of files and verifies them against
+// the expected values, e.g, to detect tampering of vendored scripts.
+type ChecksumCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Hash algorithm: sha256 (default) or sha512.
+	Algorithm string `yaml:"algorithm"`
+	// Expected checksums keyed by file path, relative to the project
+	// directory; files with an empty checksum are only computed.
+	Files map[string]string `yaml:"files"`
+	// Path to a manifest of expected checksums in the sha256sum/sha512sum
+	// format, i.e, `<checksum>  <path>` per line.
+	Manifest string `yaml:"manifest"`
+	// Name under which the computed checksums are published as json, for
+	// subsequent checks to consume.
+	Publish  string `yaml:"publish"`
+	expected map[string]string
+}
+
+// Init implementation for the checksum check.
+func (c *ChecksumCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.Algorithm == "" {
+		c.Algorithm = "sha256"
+	}
+}
+
+// Merge implementation for checksum check.
+func (c *ChecksumCheck) Merge(mergeCheck config.Check) error {
+	checksumMergeCheck := mergeCheck.(*ChecksumCheck)
+	if err := c.CheckBase.Merge(&checksumMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Algorithm, checksumMergeCheck.Algorithm)
+	if len(checksumMergeCheck.Files) > 0 && c.Files == nil {
+		c.Files = map[string]string{}
+	}
+	for f, sum := range checksumMergeCheck.Files {
+		c.Files[f] = sum
+	}
+	utils.MergeString(&c.Manifest, checksumMergeCheck.Manifest)
+	utils.MergeString(&c.Publish, checksumMergeCheck.Publish)
+	return nil
+}
+
+// PublishesData implements config.DataPublisher.
+func (c *ChecksumCheck) PublishesData() []string {
+	if c.Publish == "" {
+		return nil
+	}
+	return []string{c.Publish}
+}
+
+// FetchData determines the expected checksums, then computes the checksum of
+// each of the files.
+func (c *ChecksumCheck) FetchData() {
+	newHash, ok := ChecksumAlgorithms[c.Algorithm]
+	if !ok {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unsupported algorithm",
+			Value:      c.Algorithm})
+		return
+	}
+
+	c.expected = map[string]string{}
+	if c.Manifest != "" {
+		data, err := os.ReadFile(filepath.Join(config.ProjectDir, c.Manifest))
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "error reading manifest",
+				Value:      err.Error()})
+			return
+		}
+		if c.expected, err = ParseChecksumManifest(data); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "error parsing manifest",
+				Value:      err.Error()})
+			return
+		}
+	}
+	for f, sum := range c.Files {
+		c.expected[f] = sum
+	}
+	if len(c.expected) == 0 {
+		c.AddBreach(&result.ValueBreach{Value: "no files or manifest provided"})
+		return
+	}
+
+	c.DataMap = map[string][]byte{}
+	for _, f := range utils.SortedKeys(c.expected) {
+		sum, err := fileChecksum(filepath.Join(config.ProjectDir, f), newHash())
+		if err != nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "file",
+				Key:        f,
+				ValueLabel: "error reading file",
+				Value:      err.Error(),
+			})
+			continue
+		}
+		c.DataMap[f] = []byte(sum)
+	}
+}
+
+// RunCheck verifies the computed checksums against the expected ones.
+func (c *ChecksumCheck) RunCheck() {
+	if c.Publish != "" {
+		checksums := map[string]string{}
+		for f, sum := range c.DataMap {
+			checksums[f] = string(sum)
+		}
+		out, _ := json.Marshal(checksums)
+		config.PublishData(c.Publish, out)
+	}
+
+	for _, f := range utils.SortedKeys(c.DataMap) {
+		sum := string(c.DataMap[f])
+		expected := strings.ToLower(c.expected[f])
+		if expected == "" {
+			c.AddPass(fmt.Sprintf("%s %s is %s", f, c.Algorithm, sum))
+			continue
+		}
+		if sum != expected {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "file",
+				Key:           f,
+				ValueLabel:    c.Algorithm,
+				ExpectedValue: expected,
+				Value:         sum,
+			})
+			continue
+		}
+		c.AddPass(fmt.Sprintf("%s checksum matches", f))
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// ParseChecksumManifest parses a manifest in the sha256sum/sha512sum format
+// into the checksums keyed by file path.
+func ParseChecksumManifest(data []byte) (map[string]string, error) {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		sum, f, found := strings.Cut(l, " ")
+		// The file name is prefixed with '*' in binary mode.
+		f = strings.TrimPrefix(strings.TrimLeft(f, " "), "*")
+		if !found || f == "" {
+			return nil, fmt.Errorf("invalid line %d: %s", line, l)
+		}
+		checksums[f] = sum
+	}
+	return checksums, scanner.Err()
+}
+
+func fileChecksum(path string, h hash.Hash) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,186 @@
+package file_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/file"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+const deployShSha256 = "fa1d9f79a8460a824f5c6a3fd5876b3055546b46a59da5dffd4e5e3c5f672f2d"
+const htaccessSha256 = "74d7c0a057cc6a4e4b762757f8f69194a1249028bff3ede8ed80a66f06494d21"
+
+func TestChecksumCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ChecksumCheck{Files: map[string]string{"deploy.sh": "abc"}}
+	err := c.Merge(&ChecksumCheck{
+		Algorithm: "sha512",
+		Files:     map[string]string{".htaccess": "def"},
+		Manifest:  "checksums.txt",
+	})
+	assert.NoError(err)
+	assert.Equal("sha512", c.Algorithm)
+	assert.Equal(map[string]string{"deploy.sh": "abc", ".htaccess": "def"}, c.Files)
+	assert.Equal("checksums.txt", c.Manifest)
+}
+
+func TestParseChecksumManifest(t *testing.T) {
+	assert := assert.New(t)
+
+	checksums, err := ParseChecksumManifest([]byte("# comment\nabc  deploy.sh\n\ndef *bin/tool\n"))
+	assert.NoError(err)
+	assert.Equal(map[string]string{"deploy.sh": "abc", "bin/tool": "def"}, checksums)
+
+	_, err = ParseChecksumManifest([]byte("abc\n"))
+	assert.EqualError(err, "invalid line 1: abc")
+}
+
+func TestChecksumCheckFetchData(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	tt := []internal.FetchDataTest{
+		{
+			Name:  "noFiles",
+			Check: &ChecksumCheck{},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "file:checksum",
+				Severity:   "normal",
+				Value:      "no files or manifest provided",
+			}},
+		},
+		{
+			Name:  "unsupportedAlgorithm",
+			Check: &ChecksumCheck{Algorithm: "md5"},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "file:checksum",
+				Severity:   "normal",
+				ValueLabel: "unsupported algorithm",
+				Value:      "md5",
+			}},
+		},
+		{
+			Name:  "missingManifest",
+			Check: &ChecksumCheck{Manifest: "missing.sha256"},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "file:checksum",
+				Severity:   "normal",
+				ValueLabel: "error reading manifest",
+				Value:      "open testdata/missing.sha256: no such file or directory",
+			}},
+		},
+		{
+			Name:  "missingFile",
+			Check: &ChecksumCheck{Files: map[string]string{"checksum/missing.sh": ""}},
+			ExpectBreaches: []result.Breach{&result.KeyValueBreach{
+				BreachType: "key-value",
+				CheckType:  "file:checksum",
+				Severity:   "normal",
+				KeyLabel:   "file",
+				Key:        "checksum/missing.sh",
+				ValueLabel: "error reading file",
+				Value:      "open testdata/checksum/missing.sh: no such file or directory",
+			}},
+			ExpectDataMap: map[string][]byte{},
+		},
+		{
+			Name:  "manifest",
+			Check: &ChecksumCheck{Manifest: "checksum/manifest.sha256"},
+			ExpectDataMap: map[string][]byte{
+				"checksum/.htaccess": []byte(htaccessSha256),
+				"checksum/deploy.sh": []byte(deployShSha256),
+			},
+		},
+		{
+			Name:  "sha512",
+			Check: &ChecksumCheck{Algorithm: "sha512", Files: map[string]string{"checksum/deploy.sh": ""}},
+			ExpectDataMap: map[string][]byte{
+				"checksum/deploy.sh": []byte("08bfef8143fb910c34411bde6b2af9e0d2c3893e82f6c1c22412f167db66552361a1fd92c300f2c9e4333f00347b68b07f9f7c4889ba8c286a5180f87648016e"),
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Checksum)
+			internal.TestFetchData(t, tc)
+		})
+	}
+}
+
+func TestChecksumCheckRunCheck(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "manifestMatches",
+			Check:        &ChecksumCheck{Manifest: "checksum/manifest.sha256"},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"checksum/.htaccess checksum matches",
+				"checksum/deploy.sh checksum matches",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "tampered",
+			Check: &ChecksumCheck{
+				Manifest: "checksum/tampered.sha256",
+				// Files override the manifest.
+				Files: map[string]string{"checksum/.htaccess": "0000"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "file:checksum",
+					Severity:      "normal",
+					KeyLabel:      "file",
+					Key:           "checksum/.htaccess",
+					ValueLabel:    "sha256",
+					ExpectedValue: "0000",
+					Value:         htaccessSha256,
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "file:checksum",
+					Severity:      "normal",
+					KeyLabel:      "file",
+					Key:           "checksum/deploy.sh",
+					ValueLabel:    "sha256",
+					ExpectedValue: "0000000000000000000000000000000000000000000000000000000000000000",
+					Value:         deployShSha256,
+				},
+			},
+		},
+		{
+			Name:         "computeOnly",
+			Check:        &ChecksumCheck{Files: map[string]string{"checksum/deploy.sh": ""}, Publish: "checksums"},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"checksum/deploy.sh sha256 is " + deployShSha256},
+			ExpectNoFail: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Checksum)
+			tc.Check.FetchData()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+
+	defer config.ResetPublishedData()
+	data, ok := config.GetPublishedData("checksums")
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"checksum/deploy.sh":"`+deployShSha256+`"}`, string(data))
+}
@@ -7,6 +7,8 @@ import "github.com/salsadigitalauorg/shipshape/pkg/config"
 func RegisterChecks() {
 	config.ChecksRegistry[File] = func() config.Check { return &FileCheck{} }
 	config.ChecksRegistry[FileDiff] = func() config.Check { return &FileDiffCheck{} }
+	config.ChecksRegistry[Checksum] = func() config.Check { return &ChecksumCheck{} }
+	config.ChecksRegistry[Stats] = func() config.Check { return &StatsCheck{} }
 }
 
 func init() {
@@ -1,6 +1,7 @@
 package file
 
 import (
+	"os"
 	"path/filepath"
 
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
@@ -9,13 +10,15 @@ import (
 )
 
 // FileCheck is a simple File absence check which can be for a single
-// file or a pattern.
+// file or a pattern; it can also verify that a list of files is present.
 type FileCheck struct {
 	config.CheckBase  `yaml:",inline"`
 	Path              string   `yaml:"path"`
 	DisallowedPattern string   `yaml:"disallowed-pattern"`
 	ExcludePattern    string   `yaml:"exclude-pattern"`
 	SkipDir           []string `yaml:"skip-dir"`
+	// List of files, relative to Path, which must exist.
+	RequiredFiles []string `yaml:"required-files"`
 }
 
 const File config.CheckType = "file"
@@ -29,6 +32,7 @@ func (c *FileCheck) Merge(mergeCheck config.Check) error {
 
 	utils.MergeString(&c.Path, fileMergeCheck.Path)
 	utils.MergeString(&c.DisallowedPattern, fileMergeCheck.DisallowedPattern)
+	utils.MergeStringSlice(&c.RequiredFiles, fileMergeCheck.RequiredFiles)
 	return nil
 }
 
@@ -39,8 +43,25 @@ func (c *FileCheck) RequiresData() bool { return false }
 
 // RunCheck scans a directory for a list of disallowed files, while excluding
 // the provided regex ExcludePattern and skipping the list of provided relative
-// directories.
+// directories. Required files are then verified to exist.
 func (c *FileCheck) RunCheck() {
+	if c.DisallowedPattern == "" && len(c.RequiredFiles) == 0 {
+		c.AddBreach(&result.ValueBreach{
+			Value: "no disallowed pattern or required files provided"})
+		return
+	}
+	if c.DisallowedPattern != "" {
+		c.checkDisallowed()
+	}
+	if len(c.RequiredFiles) > 0 {
+		c.checkRequired()
+	}
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+func (c *FileCheck) checkDisallowed() {
 	files, err := utils.FindFiles(filepath.Join(config.ProjectDir, c.Path), c.DisallowedPattern, c.ExcludePattern, c.SkipDir)
 	if err != nil {
 		c.AddBreach(&result.ValueBreach{
@@ -49,7 +70,6 @@ func (c *FileCheck) RunCheck() {
 		return
 	}
 	if len(files) == 0 {
-		c.Result.Status = result.Pass
 		c.AddPass("No illegal files")
 		return
 	}
@@ -58,3 +78,20 @@ func (c *FileCheck) RunCheck() {
 		Values: files,
 	})
 }
+
+func (c *FileCheck) checkRequired() {
+	missing := []string{}
+	for _, f := range c.RequiredFiles {
+		if _, err := os.Stat(filepath.Join(config.ProjectDir, c.Path, f)); err != nil {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) == 0 {
+		c.AddPass("All required files present")
+		return
+	}
+	c.AddBreach(&result.KeyValuesBreach{
+		Key:    "required files not found",
+		Values: missing,
+	})
+}
@@ -107,3 +107,56 @@ func TestFileCheckRunCheck(t *testing.T) {
 	assert.Equal(0, len(c.Result.Breaches))
 	assert.EqualValues([]string{"No illegal files"}, c.Result.Passes)
 }
+
+func TestFileCheckRequiredFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = "testdata"
+	c := FileCheck{}
+	c.Init(File)
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			CheckType:  "file",
+			BreachType: result.BreachTypeValue,
+			Severity:   "normal",
+			Value:      "no disallowed pattern or required files provided",
+		}},
+		c.Result.Breaches,
+	)
+
+	c = FileCheck{
+		Path:          "correct",
+		RequiredFiles: []string{"index.php", "bootstrap/cache/config.php"},
+	}
+	c.Init(File)
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.Equal(0, len(c.Result.Passes))
+	assert.EqualValues(
+		[]result.Breach{&result.KeyValuesBreach{
+			CheckType:  "file",
+			BreachType: "key-values",
+			Severity:   "normal",
+			Key:        "required files not found",
+			Values:     []string{"bootstrap/cache/config.php"},
+		}},
+		c.Result.Breaches,
+	)
+
+	c = FileCheck{
+		Path:              "correct",
+		DisallowedPattern: "^(adminer|phpmyadmin|bigdump)?\\.php$",
+		RequiredFiles:     []string{"index.php", "file.csv"},
+	}
+	c.Init(File)
+	c.RunCheck()
+	assert.Equal(result.Pass, c.Result.Status)
+	assert.Equal(0, len(c.Result.Breaches))
+	assert.EqualValues(
+		[]string{"No illegal files", "All required files present"},
+		c.Result.Passes)
+}
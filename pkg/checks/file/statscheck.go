@@ -0,0 +1,135 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Stats config.CheckType = "file:stats"
+
+// StatsCheck computes the number and cumulative size of the files in
+// directories, breaching when they exceed the limits, e.g, a public files
+// directory larger than 20G.
+type StatsCheck struct {
+	config.CheckBase `yaml:",inline"`
+	Paths            []StatsPath `yaml:"paths"`
+}
+
+// StatsPath is a directory whose files are counted, along with its limits.
+type StatsPath struct {
+	// Directory, relative to the project directory unless absolute.
+	Path string `yaml:"path"`
+	// Regex the file names must match to be counted; all files are counted
+	// if empty.
+	Pattern        string   `yaml:"pattern"`
+	ExcludePattern string   `yaml:"exclude-pattern"`
+	SkipDir        []string `yaml:"skip-dir"`
+	// Maximum cumulative size of the files, e.g, 20G.
+	MaxSize string `yaml:"max-size"`
+	// Maximum number of files; unlimited if 0.
+	MaxFiles int `yaml:"max-files"`
+}
+
+// Merge implementation for file:stats check.
+func (c *StatsCheck) Merge(mergeCheck config.Check) error {
+	statsMergeCheck := mergeCheck.(*StatsCheck)
+	if err := c.CheckBase.Merge(&statsMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	if len(statsMergeCheck.Paths) > 0 {
+		c.Paths = statsMergeCheck.Paths
+	}
+	return nil
+}
+
+// RequiresData implementation for file:stats check.
+// Since this check acts on the files on disk, it does not require any data.
+func (c *StatsCheck) RequiresData() bool { return false }
+
+// RunCheck computes the totals of each path and verifies them against its
+// limits.
+func (c *StatsCheck) RunCheck() {
+	if len(c.Paths) == 0 {
+		c.AddBreach(&result.ValueBreach{Value: "no paths provided"})
+		return
+	}
+	for _, p := range c.Paths {
+		c.checkPath(p)
+	}
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+func (c *StatsCheck) checkPath(p StatsPath) {
+	var maxSize float64
+	if p.MaxSize != "" {
+		var err error
+		if maxSize, err = utils.ParseSize(p.MaxSize); err != nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "path",
+				Key:        p.Path,
+				ValueLabel: "invalid max-size",
+				Value:      p.MaxSize,
+			})
+			return
+		}
+	}
+
+	pattern := p.Pattern
+	if pattern == "" {
+		pattern = "."
+	}
+	dir := p.Path
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(config.ProjectDir, dir)
+	}
+	files, err := utils.FindFiles(dir, pattern, p.ExcludePattern, p.SkipDir)
+	if err != nil {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "path",
+			Key:        p.Path,
+			ValueLabel: "error finding files",
+			Value:      err.Error(),
+		})
+		return
+	}
+	var size float64
+	for _, f := range files {
+		// Symlinks are not followed, so that their targets are not counted.
+		if fi, err := os.Lstat(f); err == nil {
+			size += float64(fi.Size())
+		}
+	}
+
+	exceeded := false
+	if p.MaxFiles > 0 && len(files) > p.MaxFiles {
+		exceeded = true
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:      "path",
+			Key:           p.Path,
+			ValueLabel:    "number of files",
+			ExpectedValue: fmt.Sprintf("at most %d", p.MaxFiles),
+			Value:         fmt.Sprint(len(files)),
+		})
+	}
+	if p.MaxSize != "" && size > maxSize {
+		exceeded = true
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:      "path",
+			Key:           p.Path,
+			ValueLabel:    "size",
+			ExpectedValue: "at most " + p.MaxSize,
+			Value:         utils.FormatSize(size),
+		})
+	}
+	if !exceeded {
+		c.AddPass(fmt.Sprintf("%s has %d files totalling %s", p.Path, len(files), utils.FormatSize(size)))
+	}
+}
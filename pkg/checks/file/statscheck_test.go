@@ -0,0 +1,125 @@
+package file_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/file"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := StatsCheck{Paths: []StatsPath{{Path: "web/sites/default/files", MaxSize: "20G"}}}
+	err := c.Merge(&StatsCheck{})
+	assert.NoError(err)
+	assert.Equal([]StatsPath{{Path: "web/sites/default/files", MaxSize: "20G"}}, c.Paths)
+
+	err = c.Merge(&StatsCheck{Paths: []StatsPath{{Path: "tmp", MaxFiles: 1000}}})
+	assert.NoError(err)
+	assert.Equal([]StatsPath{{Path: "tmp", MaxFiles: 1000}}, c.Paths)
+}
+
+func TestStatsCheckRunCheckAbsolutePath(t *testing.T) {
+	config.ProjectDir = "/nonexistent"
+	defer func() { config.ProjectDir = "" }()
+
+	dir, _ := filepath.Abs("testdata/filediff")
+	c := StatsCheck{Paths: []StatsPath{{Path: dir, MaxFiles: 4}}}
+	c.RunCheck()
+	assert.Empty(t, c.Result.Breaches)
+	assert.Equal(t, []string{dir + " has 4 files totalling 91"}, c.Result.Passes)
+}
+
+func TestStatsCheckRunCheck(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "noPaths",
+			Check:        &StatsCheck{},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				Value:      "no paths provided",
+			}},
+		},
+		{
+			Name: "withinLimits",
+			Check: &StatsCheck{Paths: []StatsPath{
+				{Path: "filediff", MaxSize: "1K", MaxFiles: 4},
+				{Path: "checksum", Pattern: `\.sha256$`},
+			}},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"filediff has 4 files totalling 91",
+				"checksum has 2 files totalling 359",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "limitsExceeded",
+			Check: &StatsCheck{Paths: []StatsPath{
+				{Path: "filediff", MaxSize: "64", MaxFiles: 3},
+				{Path: "checksum", ExcludePattern: `\.sha256$`, MaxSize: "1KB"},
+			}},
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{"checksum has 2 files totalling 41"},
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					KeyLabel:      "path",
+					Key:           "filediff",
+					ValueLabel:    "number of files",
+					ExpectedValue: "at most 3",
+					Value:         "4",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					KeyLabel:      "path",
+					Key:           "filediff",
+					ValueLabel:    "size",
+					ExpectedValue: "at most 64",
+					Value:         "91",
+				},
+			},
+		},
+		{
+			Name: "invalidPath",
+			Check: &StatsCheck{Paths: []StatsPath{
+				{Path: "missing", MaxFiles: 1},
+				{Path: "filediff", MaxSize: "lots"},
+			}},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "path",
+					Key:        "missing",
+					ValueLabel: "error finding files",
+					Value:      "lstat testdata/missing: no such file or directory",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					KeyLabel:   "path",
+					Key:        "filediff",
+					ValueLabel: "invalid max-size",
+					Value:      "lots",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Package github provides checks against the GitHub API.
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=github
+
+// DefaultApiUrl is the GitHub API used when none is provided through the
+// check or the GITHUB_API_URL environment variable.
+const DefaultApiUrl = "https://api.github.com"
+
+func RegisterChecks() {
+	config.ChecksRegistry[Repo] = func() config.Check { return &RepoCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// apiError is the error returned by the API.
+type apiError struct {
+	Message string `json:"message"`
+}
+
+// apiGet queries the API, authenticating with the token if provided; it
+// returns the response body and status code.
+func apiGet(apiUrl string, token string, path string) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(apiUrl, "/")+path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := utils.HttpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := apiError{}
+		json.Unmarshal(body, &apiErr)
+		return body, resp.StatusCode, fmt.Errorf("%s (%d)", apiErr.Message, resp.StatusCode)
+	}
+	return body, resp.StatusCode, nil
+}
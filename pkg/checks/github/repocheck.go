@@ -0,0 +1,315 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Repo config.CheckType = "github-repo"
+
+// Repository is the subset of the repository's settings verified.
+type Repository struct {
+	FullName            string `json:"full_name"`
+	DefaultBranch       string `json:"default_branch"`
+	SecurityAndAnalysis *struct {
+		SecretScanning struct {
+			Status string `json:"status"`
+		} `json:"secret_scanning"`
+	} `json:"security_and_analysis"`
+}
+
+// BranchProtection is the subset of a branch's protection rules verified.
+type BranchProtection struct {
+	RequiredPullRequestReviews *struct {
+		RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+		RequireCodeOwnerReviews      bool `json:"require_code_owner_reviews"`
+	} `json:"required_pull_request_reviews"`
+	EnforceAdmins *struct {
+		Enabled bool `json:"enabled"`
+	} `json:"enforce_admins"`
+}
+
+// RepoCheck verifies a GitHub repository's settings against a baseline.
+type RepoCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Repository in the owner/name format; defaults to the GITHUB_REPOSITORY
+	// environment variable.
+	Repository string `yaml:"repository"`
+	// Branch to verify the protection of; defaults to the default branch.
+	Branch string `yaml:"branch"`
+	// Url of the API, e.g, for GitHub Enterprise Server.
+	ApiUrl string `yaml:"api-url"`
+	// Token authenticating the API requests, which can be an encrypted
+	// value; defaults to the GITHUB_TOKEN environment variable.
+	Token string `yaml:"token"`
+	// Expected default branch.
+	DefaultBranch string `yaml:"default-branch"`
+	// Require the branch to be protected.
+	BranchProtection *bool `yaml:"branch-protection"`
+	// Minimum number of approving reviews required on pull requests; 0
+	// disables a requirement set in a merged config.
+	RequiredApprovals *int `yaml:"required-approvals"`
+	// Require reviews from code owners.
+	CodeOwnerReviews *bool `yaml:"code-owner-reviews"`
+	// Require the protection rules to apply to administrators.
+	EnforceAdmins *bool `yaml:"enforce-admins"`
+	// Require secret scanning to be enabled.
+	SecretScanning *bool `yaml:"secret-scanning"`
+	repo           Repository
+	protection     *BranchProtection
+}
+
+// Init implementation for the github-repo check.
+func (c *RepoCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.Repository == "" {
+		c.Repository = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if c.ApiUrl == "" {
+		c.ApiUrl = os.Getenv("GITHUB_API_URL")
+	}
+	if c.ApiUrl == "" {
+		c.ApiUrl = DefaultApiUrl
+	}
+}
+
+// Merge implementation for github-repo check.
+func (c *RepoCheck) Merge(mergeCheck config.Check) error {
+	repoMergeCheck := mergeCheck.(*RepoCheck)
+	if err := c.CheckBase.Merge(&repoMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Repository, repoMergeCheck.Repository)
+	utils.MergeString(&c.Branch, repoMergeCheck.Branch)
+	utils.MergeString(&c.ApiUrl, repoMergeCheck.ApiUrl)
+	utils.MergeString(&c.Token, repoMergeCheck.Token)
+	utils.MergeString(&c.DefaultBranch, repoMergeCheck.DefaultBranch)
+	if repoMergeCheck.RequiredApprovals != nil {
+		c.RequiredApprovals = repoMergeCheck.RequiredApprovals
+	}
+	if repoMergeCheck.BranchProtection != nil {
+		c.BranchProtection = repoMergeCheck.BranchProtection
+	}
+	if repoMergeCheck.CodeOwnerReviews != nil {
+		c.CodeOwnerReviews = repoMergeCheck.CodeOwnerReviews
+	}
+	if repoMergeCheck.EnforceAdmins != nil {
+		c.EnforceAdmins = repoMergeCheck.EnforceAdmins
+	}
+	if repoMergeCheck.SecretScanning != nil {
+		c.SecretScanning = repoMergeCheck.SecretScanning
+	}
+	return nil
+}
+
+// apiToken returns the token authenticating the API requests.
+func (c *RepoCheck) apiToken() string {
+	if c.Token != "" {
+		return c.Token
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+// FetchData queries the API for the repository's settings and the branch's
+// protection rules.
+func (c *RepoCheck) FetchData() {
+	if c.Repository == "" {
+		c.AddBreach(&result.ValueBreach{Value: "no repository provided"})
+		return
+	}
+
+	var err error
+	c.DataMap = map[string][]byte{}
+	var status int
+	c.DataMap["repository"], status, err = apiGet(c.ApiUrl, c.apiToken(), "/repos/"+c.Repository)
+	// Private repositories the token has no access to also return a 404.
+	if status == http.StatusNotFound {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "repository not found or not accessible",
+			Value:      c.Repository})
+		return
+	} else if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to fetch repository " + c.Repository,
+			Value:      err.Error()})
+		return
+	}
+
+	if !c.requiresProtection() {
+		return
+	}
+	branch := c.Branch
+	if branch == "" {
+		repo := Repository{}
+		json.Unmarshal(c.DataMap["repository"], &repo)
+		branch = repo.DefaultBranch
+		c.Branch = branch
+	}
+	data, status, err := apiGet(c.ApiUrl, c.apiToken(), fmt.Sprintf(
+		"/repos/%s/branches/%s/protection", c.Repository, url.PathEscape(branch)))
+	// Unprotected branches return a 404.
+	if status == http.StatusNotFound {
+		return
+	} else if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to fetch protection for branch " + branch,
+			Value:      err.Error()})
+		return
+	}
+	c.DataMap["protection"] = data
+}
+
+// UnmarshalDataMap parses the API responses.
+func (c *RepoCheck) UnmarshalDataMap() {
+	c.repo = Repository{}
+	if err := json.Unmarshal(c.DataMap["repository"], &c.repo); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to parse repository", Value: err.Error()})
+		return
+	}
+
+	c.protection = nil
+	if data, ok := c.DataMap["protection"]; ok {
+		c.protection = &BranchProtection{}
+		if err := json.Unmarshal(data, c.protection); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to parse branch protection", Value: err.Error()})
+		}
+	}
+}
+
+// RunCheck verifies the repository settings against the baseline.
+func (c *RepoCheck) RunCheck() {
+	if c.DefaultBranch != "" {
+		if c.repo.DefaultBranch != c.DefaultBranch {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "repository",
+				Key:           c.Repository,
+				ValueLabel:    "default branch",
+				ExpectedValue: c.DefaultBranch,
+				Value:         c.repo.DefaultBranch,
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("default branch is %s", c.DefaultBranch))
+		}
+	}
+
+	if isTrue(c.SecretScanning) {
+		if c.repo.SecurityAndAnalysis == nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "repository",
+				Key:        c.Repository,
+				ValueLabel: "secret scanning",
+				Value:      "status not available; admin access is required",
+			})
+		} else if status := c.repo.SecurityAndAnalysis.SecretScanning.Status; status != "enabled" {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "repository",
+				Key:        c.Repository,
+				ValueLabel: "secret scanning",
+				Value:      status,
+			})
+		} else {
+			c.AddPass("secret scanning is enabled")
+		}
+	}
+
+	if c.requiresProtection() {
+		c.checkProtection()
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// checkProtection verifies the branch's protection rules.
+func (c *RepoCheck) checkProtection() {
+	if c.protection == nil {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "branch",
+			Key:        c.Branch,
+			ValueLabel: "branch protection",
+			Value:      "disabled",
+		})
+		return
+	}
+	if isTrue(c.BranchProtection) {
+		c.AddPass(fmt.Sprintf("branch %s is protected", c.Branch))
+	}
+
+	reviews := c.protection.RequiredPullRequestReviews
+	if c.requiresApprovals() {
+		approvals := 0
+		if reviews != nil {
+			approvals = reviews.RequiredApprovingReviewCount
+		}
+		if approvals < *c.RequiredApprovals {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "branch",
+				Key:           c.Branch,
+				ValueLabel:    "required approvals",
+				ExpectedValue: strconv.Itoa(*c.RequiredApprovals),
+				Value:         strconv.Itoa(approvals),
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("branch %s requires %d approvals", c.Branch, approvals))
+		}
+	}
+
+	if isTrue(c.CodeOwnerReviews) {
+		if reviews == nil || !reviews.RequireCodeOwnerReviews {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "branch",
+				Key:        c.Branch,
+				ValueLabel: "code owner reviews",
+				Value:      "not required",
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("branch %s requires code owner reviews", c.Branch))
+		}
+	}
+
+	if isTrue(c.EnforceAdmins) {
+		if c.protection.EnforceAdmins == nil || !c.protection.EnforceAdmins.Enabled {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "branch",
+				Key:        c.Branch,
+				ValueLabel: "enforce admins",
+				Value:      "disabled",
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("branch %s protection applies to admins", c.Branch))
+		}
+	}
+}
+
+// requiresProtection determines whether the branch protection needs to be
+// fetched.
+func (c *RepoCheck) requiresProtection() bool {
+	return isTrue(c.BranchProtection) || c.requiresApprovals() ||
+		isTrue(c.CodeOwnerReviews) || isTrue(c.EnforceAdmins)
+}
+
+// requiresApprovals determines whether the approvals are verified.
+func (c *RepoCheck) requiresApprovals() bool {
+	return c.RequiredApprovals != nil && *c.RequiredApprovals > 0
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *RepoCheck) RequiresNetwork() bool {
+	return true
+}
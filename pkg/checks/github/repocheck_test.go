@@ -0,0 +1,273 @@
+package github_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/github"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Repo]()
+	assert.Equal(t, "*github.RepoCheck", reflect.TypeOf(c).String())
+}
+
+func TestRepoCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("GITHUB_REPOSITORY", "octocat/hello-world")
+	c := RepoCheck{}
+	c.Init(Repo)
+	assert.Equal("octocat/hello-world", c.Repository)
+	assert.Equal("https://api.github.com", c.ApiUrl)
+
+	t.Setenv("GITHUB_API_URL", "https://github.example.com/api/v3")
+	c = RepoCheck{Repository: "org/repo"}
+	c.Init(Repo)
+	assert.Equal("org/repo", c.Repository)
+	assert.Equal("https://github.example.com/api/v3", c.ApiUrl)
+}
+
+func TestRepoCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	enabled := true
+	disabled := false
+	approvals, noApprovals := 2, 0
+	c := RepoCheck{
+		Repository:       "octocat/hello-world",
+		BranchProtection: &enabled,
+		SecretScanning:   &enabled,
+	}
+	err := c.Merge(&RepoCheck{
+		DefaultBranch:     "main",
+		RequiredApprovals: &approvals,
+		SecretScanning:    &disabled,
+	})
+	assert.NoError(err)
+	assert.Equal("octocat/hello-world", c.Repository)
+	assert.Equal("main", c.DefaultBranch)
+	assert.Equal(2, *c.RequiredApprovals)
+	assert.True(*c.BranchProtection)
+	assert.False(*c.SecretScanning)
+
+	// The required approvals can be disabled.
+	assert.NoError(c.Merge(&RepoCheck{RequiredApprovals: &noApprovals}))
+	assert.Equal(0, *c.RequiredApprovals)
+	assert.NoError(c.Merge(&RepoCheck{}))
+	assert.Equal(0, *c.RequiredApprovals)
+}
+
+// newTestServer serves the testdata, with the protection of the given
+// branches only.
+func newTestServer(t *testing.T, protected ...string) *httptest.Server {
+	repoData, _ := os.ReadFile("testdata/repository.json")
+	protectionData, _ := os.ReadFile("testdata/protection.json")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/octocat/hello-world", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.Write(repoData)
+	})
+	for _, b := range protected {
+		mux.HandleFunc("/repos/octocat/hello-world/branches/"+b+"/protection", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(protectionData)
+		})
+	}
+	mux.HandleFunc("/repos/octocat/private", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message": "Bad credentials"}`))
+	})
+	mux.HandleFunc("/repos/octocat/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Not Found"}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestRepoCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("GITHUB_TOKEN", "secret")
+	srv := newTestServer(t, "main")
+	defer srv.Close()
+
+	c := RepoCheck{}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		Value:      "no repository provided",
+	}}, c.Result.Breaches)
+
+	c = RepoCheck{Repository: "octocat/private", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "unable to fetch repository octocat/private",
+		Value:      "Bad credentials (401)",
+	}}, c.Result.Breaches)
+
+	enabled := true
+	c = RepoCheck{Repository: "octocat/missing", ApiUrl: srv.URL, BranchProtection: &enabled}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "repository not found or not accessible",
+		Value:      "octocat/missing",
+	}}, c.Result.Breaches)
+	assert.Equal("", c.Branch)
+
+	// Protection is only fetched when required.
+	c = RepoCheck{Repository: "octocat/hello-world", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Len(c.DataMap, 1)
+
+	c = RepoCheck{Repository: "octocat/hello-world", ApiUrl: srv.URL, BranchProtection: &enabled}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("main", c.Branch)
+	assert.Contains(c.DataMap, "protection")
+
+	c = RepoCheck{Repository: "octocat/hello-world", ApiUrl: srv.URL, BranchProtection: &enabled, Branch: "develop"}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.NotContains(c.DataMap, "protection")
+
+	// The token can be provided in the config.
+	t.Setenv("GITHUB_TOKEN", "")
+	c = RepoCheck{Repository: "octocat/hello-world", ApiUrl: srv.URL, Token: "secret"}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+}
+
+func TestRepoCheckRunCheck(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("GITHUB_TOKEN", "secret")
+	srv := newTestServer(t, "main")
+	defer srv.Close()
+
+	enabled := true
+	one, two := 1, 2
+	tt := []struct {
+		name         string
+		check        RepoCheck
+		expectStatus result.Status
+		expectPasses []string
+		expectFails  []result.Breach
+	}{
+		{
+			name: "baselineMet",
+			check: RepoCheck{
+				DefaultBranch:     "main",
+				BranchProtection:  &enabled,
+				RequiredApprovals: &one,
+				EnforceAdmins:     &enabled,
+				SecretScanning:    &enabled,
+			},
+			expectStatus: result.Pass,
+			expectPasses: []string{
+				"default branch is main",
+				"secret scanning is enabled",
+				"branch main is protected",
+				"branch main requires 1 approvals",
+				"branch main protection applies to admins",
+			},
+		},
+		{
+			name: "baselineNotMet",
+			check: RepoCheck{
+				DefaultBranch:     "master",
+				RequiredApprovals: &two,
+				CodeOwnerReviews:  &enabled,
+			},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "github-repo",
+					Severity:      "normal",
+					KeyLabel:      "repository",
+					Key:           "octocat/hello-world",
+					ValueLabel:    "default branch",
+					ExpectedValue: "master",
+					Value:         "main",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "github-repo",
+					Severity:      "normal",
+					KeyLabel:      "branch",
+					Key:           "main",
+					ValueLabel:    "required approvals",
+					ExpectedValue: "2",
+					Value:         "1",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "github-repo",
+					Severity:   "normal",
+					KeyLabel:   "branch",
+					Key:        "main",
+					ValueLabel: "code owner reviews",
+					Value:      "not required",
+				},
+			},
+		},
+		{
+			name: "unprotectedBranch",
+			check: RepoCheck{
+				Branch:           "develop",
+				BranchProtection: &enabled,
+			},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "github-repo",
+					Severity:   "normal",
+					KeyLabel:   "branch",
+					Key:        "develop",
+					ValueLabel: "branch protection",
+					Value:      "disabled",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.check
+			c.Repository = "octocat/hello-world"
+			c.ApiUrl = srv.URL
+			c.Init(Repo)
+			c.FetchData()
+			assert.Empty(c.Result.Breaches)
+			c.UnmarshalDataMap()
+			c.RunCheck()
+			c.Result.DetermineResultStatus(false)
+			assert.Equal(tc.expectStatus, c.Result.Status)
+			assert.ElementsMatch(tc.expectPasses, c.Result.Passes)
+			assert.ElementsMatch(tc.expectFails, c.Result.Breaches)
+		})
+	}
+}
+
+func TestRepoCheckSecretScanningUnavailable(t *testing.T) {
+	enabled := true
+	c := RepoCheck{Repository: "octocat/hello-world", SecretScanning: &enabled}
+	c.DataMap = map[string][]byte{"repository": []byte(`{"default_branch": "main"}`)}
+	c.UnmarshalDataMap()
+	c.RunCheck()
+	assert.EqualValues(t, []result.Breach{&result.KeyValueBreach{
+		BreachType: "key-value",
+		KeyLabel:   "repository",
+		Key:        "octocat/hello-world",
+		ValueLabel: "secret scanning",
+		Value:      "status not available; admin access is required",
+	}}, c.Result.Breaches)
+}
@@ -0,0 +1,60 @@
+// Package gitlab provides checks against the GitLab API.
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=gitlab
+
+// DefaultApiUrl is the GitLab API used when none is provided through the
+// check or the CI_API_V4_URL environment variable.
+const DefaultApiUrl = "https://gitlab.com/api/v4"
+
+func RegisterChecks() {
+	config.ChecksRegistry[Project] = func() config.Check { return &ProjectCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// apiError is the error returned by the API.
+type apiError struct {
+	Message any `json:"message"`
+}
+
+// apiGet queries the API, authenticating with the token if provided; it
+// returns the response body and status code.
+func apiGet(apiUrl string, token string, path string) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(apiUrl, "/")+path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	resp, err := utils.HttpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := apiError{}
+		json.Unmarshal(body, &apiErr)
+		return body, resp.StatusCode, fmt.Errorf("%v (%d)", apiErr.Message, resp.StatusCode)
+	}
+	return body, resp.StatusCode, nil
+}
@@ -0,0 +1,447 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Project config.CheckType = "gitlab-project"
+
+// ProjectSettings is the subset of the project's settings verified.
+type ProjectSettings struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+}
+
+// ProtectedBranch is the subset of a branch's protection rules verified.
+type ProtectedBranch struct {
+	Name           string `json:"name"`
+	AllowForcePush bool   `json:"allow_force_push"`
+}
+
+// Approvals is the subset of the project's merge request approval settings
+// verified.
+type Approvals struct {
+	ResetApprovalsOnPush        bool `json:"reset_approvals_on_push"`
+	MergeRequestsAuthorApproval bool `json:"merge_requests_author_approval"`
+}
+
+// ApprovalRule is a merge request approval rule.
+type ApprovalRule struct {
+	Name              string `json:"name"`
+	ApprovalsRequired int    `json:"approvals_required"`
+}
+
+// Variable is a CI/CD variable; its value is not kept.
+type Variable struct {
+	Key       string `json:"key"`
+	Protected bool   `json:"protected"`
+	Masked    bool   `json:"masked"`
+}
+
+// ProjectCheck verifies a GitLab project's settings against a baseline.
+type ProjectCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Project path, e.g, group/project; defaults to the CI_PROJECT_PATH
+	// environment variable.
+	Project string `yaml:"project"`
+	// Branch to verify the protection of; defaults to the default branch.
+	Branch string `yaml:"branch"`
+	// Url of the API, e.g, for self-managed instances.
+	ApiUrl string `yaml:"api-url"`
+	// Token authenticating the API requests, which can be an encrypted
+	// value; defaults to the GITLAB_TOKEN environment variable.
+	Token string `yaml:"token"`
+	// Expected default branch.
+	DefaultBranch string `yaml:"default-branch"`
+	// Require the branch to be protected.
+	BranchProtection *bool `yaml:"branch-protection"`
+	// Require force pushes to the branch to be prevented.
+	PreventForcePush *bool `yaml:"prevent-force-push"`
+	// Minimum number of approvals required on merge requests; 0 disables a
+	// requirement set in a merged config.
+	RequiredApprovals *int `yaml:"required-approvals"`
+	// Require approvals to be reset when new commits are pushed.
+	ResetApprovalsOnPush *bool `yaml:"reset-approvals-on-push"`
+	// Require authors to be prevented from approving their merge requests.
+	PreventAuthorApproval *bool `yaml:"prevent-author-approval"`
+	// Glob patterns of the CI/CD variables which must be protected.
+	ProtectedVariables []string `yaml:"protected-variables"`
+	// Glob patterns of the CI/CD variables which must be masked.
+	MaskedVariables []string `yaml:"masked-variables"`
+	project         ProjectSettings
+	protection      *ProtectedBranch
+	approvals       Approvals
+	approvalRules   []ApprovalRule
+	variables       []Variable
+}
+
+// Init implementation for the gitlab-project check.
+func (c *ProjectCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.Project == "" {
+		c.Project = os.Getenv("CI_PROJECT_PATH")
+	}
+	if c.ApiUrl == "" {
+		c.ApiUrl = os.Getenv("CI_API_V4_URL")
+	}
+	if c.ApiUrl == "" {
+		c.ApiUrl = DefaultApiUrl
+	}
+}
+
+// Merge implementation for gitlab-project check.
+func (c *ProjectCheck) Merge(mergeCheck config.Check) error {
+	projectMergeCheck := mergeCheck.(*ProjectCheck)
+	if err := c.CheckBase.Merge(&projectMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Project, projectMergeCheck.Project)
+	utils.MergeString(&c.Branch, projectMergeCheck.Branch)
+	utils.MergeString(&c.ApiUrl, projectMergeCheck.ApiUrl)
+	utils.MergeString(&c.Token, projectMergeCheck.Token)
+	utils.MergeString(&c.DefaultBranch, projectMergeCheck.DefaultBranch)
+	if projectMergeCheck.RequiredApprovals != nil {
+		c.RequiredApprovals = projectMergeCheck.RequiredApprovals
+	}
+	if projectMergeCheck.BranchProtection != nil {
+		c.BranchProtection = projectMergeCheck.BranchProtection
+	}
+	if projectMergeCheck.PreventForcePush != nil {
+		c.PreventForcePush = projectMergeCheck.PreventForcePush
+	}
+	if projectMergeCheck.ResetApprovalsOnPush != nil {
+		c.ResetApprovalsOnPush = projectMergeCheck.ResetApprovalsOnPush
+	}
+	if projectMergeCheck.PreventAuthorApproval != nil {
+		c.PreventAuthorApproval = projectMergeCheck.PreventAuthorApproval
+	}
+	utils.MergeStringSlice(&c.ProtectedVariables, projectMergeCheck.ProtectedVariables)
+	utils.MergeStringSlice(&c.MaskedVariables, projectMergeCheck.MaskedVariables)
+	return nil
+}
+
+// apiToken returns the token authenticating the API requests.
+func (c *ProjectCheck) apiToken() string {
+	if c.Token != "" {
+		return c.Token
+	}
+	return os.Getenv("GITLAB_TOKEN")
+}
+
+// FetchData queries the API for the project's settings, as well as the
+// branch's protection, the approval settings and the variables if required.
+func (c *ProjectCheck) FetchData() {
+	if c.Project == "" {
+		c.AddBreach(&result.ValueBreach{Value: "no project provided"})
+		return
+	}
+
+	projectPath := "/projects/" + url.PathEscape(c.Project)
+	var err error
+	var status int
+	c.DataMap = map[string][]byte{}
+	c.DataMap["project"], status, err = apiGet(c.ApiUrl, c.apiToken(), projectPath)
+	// Private projects the token has no access to also return a 404.
+	if status == http.StatusNotFound {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "project not found or not accessible",
+			Value:      c.Project})
+		return
+	} else if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to fetch project " + c.Project,
+			Value:      err.Error()})
+		return
+	}
+
+	if c.requiresProtection() {
+		if c.Branch == "" {
+			project := ProjectSettings{}
+			json.Unmarshal(c.DataMap["project"], &project)
+			c.Branch = project.DefaultBranch
+		}
+		data, status, err := apiGet(c.ApiUrl, c.apiToken(), projectPath+"/protected_branches/"+url.PathEscape(c.Branch))
+		if err != nil && status != http.StatusNotFound {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to fetch protection for branch " + c.Branch,
+				Value:      err.Error()})
+			return
+		}
+		// Unprotected branches return a 404.
+		if status != http.StatusNotFound {
+			c.DataMap["protection"] = data
+		}
+	}
+
+	fetches := map[string]bool{
+		"approvals":      isTrue(c.ResetApprovalsOnPush) || isTrue(c.PreventAuthorApproval),
+		"approval_rules": c.requiresApprovals(),
+		"variables":      len(c.ProtectedVariables) > 0 || len(c.MaskedVariables) > 0,
+	}
+	for _, name := range []string{"approvals", "approval_rules", "variables"} {
+		if !fetches[name] {
+			continue
+		}
+		c.DataMap[name], _, err = apiGet(c.ApiUrl, c.apiToken(), projectPath+"/"+name+"?per_page=100")
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to fetch " + name + " for project " + c.Project,
+				Value:      err.Error()})
+			return
+		}
+	}
+}
+
+// UnmarshalDataMap parses the API responses.
+func (c *ProjectCheck) UnmarshalDataMap() {
+	c.project = ProjectSettings{}
+	if err := json.Unmarshal(c.DataMap["project"], &c.project); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to parse project", Value: err.Error()})
+		return
+	}
+
+	c.protection = nil
+	if data, ok := c.DataMap["protection"]; ok {
+		c.protection = &ProtectedBranch{}
+		if err := json.Unmarshal(data, c.protection); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to parse branch protection", Value: err.Error()})
+			return
+		}
+	}
+
+	targets := []struct {
+		name   string
+		target any
+	}{
+		{"approvals", &c.approvals},
+		{"approval_rules", &c.approvalRules},
+		{"variables", &c.variables},
+	}
+	for _, t := range targets {
+		name, target := t.name, t.target
+		data, ok := c.DataMap[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unable to parse " + name, Value: err.Error()})
+			return
+		}
+	}
+}
+
+// RunCheck verifies the project settings against the baseline.
+func (c *ProjectCheck) RunCheck() {
+	if c.DefaultBranch != "" {
+		if c.project.DefaultBranch != c.DefaultBranch {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "project",
+				Key:           c.Project,
+				ValueLabel:    "default branch",
+				ExpectedValue: c.DefaultBranch,
+				Value:         c.project.DefaultBranch,
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("default branch is %s", c.DefaultBranch))
+		}
+	}
+
+	if c.requiresProtection() {
+		c.checkProtection()
+	}
+	c.checkApprovals()
+	c.checkVariables()
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// checkProtection verifies the branch's protection rules.
+func (c *ProjectCheck) checkProtection() {
+	if c.protection == nil {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "branch",
+			Key:        c.Branch,
+			ValueLabel: "branch protection",
+			Value:      "disabled",
+		})
+		return
+	}
+	if isTrue(c.BranchProtection) {
+		c.AddPass(fmt.Sprintf("branch %s is protected", c.Branch))
+	}
+
+	if isTrue(c.PreventForcePush) {
+		if c.protection.AllowForcePush {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "branch",
+				Key:        c.Branch,
+				ValueLabel: "force push",
+				Value:      "allowed",
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("branch %s prevents force pushes", c.Branch))
+		}
+	}
+}
+
+// checkApprovals verifies the merge request approval settings & rules.
+func (c *ProjectCheck) checkApprovals() {
+	if c.requiresApprovals() {
+		approvals := 0
+		for _, r := range c.approvalRules {
+			if r.ApprovalsRequired > approvals {
+				approvals = r.ApprovalsRequired
+			}
+		}
+		if approvals < *c.RequiredApprovals {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "project",
+				Key:           c.Project,
+				ValueLabel:    "required approvals",
+				ExpectedValue: strconv.Itoa(*c.RequiredApprovals),
+				Value:         strconv.Itoa(approvals),
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("merge requests require %d approvals", approvals))
+		}
+	}
+
+	if isTrue(c.ResetApprovalsOnPush) {
+		if !c.approvals.ResetApprovalsOnPush {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "project",
+				Key:        c.Project,
+				ValueLabel: "reset approvals on push",
+				Value:      "disabled",
+			})
+		} else {
+			c.AddPass("approvals are reset on push")
+		}
+	}
+
+	if isTrue(c.PreventAuthorApproval) {
+		if c.approvals.MergeRequestsAuthorApproval {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "project",
+				Key:        c.Project,
+				ValueLabel: "author approval",
+				Value:      "allowed",
+			})
+		} else {
+			c.AddPass("authors cannot approve their merge requests")
+		}
+	}
+}
+
+// checkVariables verifies the protection flags of the CI/CD variables.
+func (c *ProjectCheck) checkVariables() {
+	if len(c.ProtectedVariables) == 0 && len(c.MaskedVariables) == 0 {
+		return
+	}
+
+	unprotected := []string{}
+	unmasked := []string{}
+	for _, v := range c.variables {
+		if matchAny(c.ProtectedVariables, v.Key) && !v.Protected {
+			unprotected = append(unprotected, v.Key)
+		}
+		if matchAny(c.MaskedVariables, v.Key) && !v.Masked {
+			unmasked = append(unmasked, v.Key)
+		}
+	}
+	sort.Strings(unprotected)
+	sort.Strings(unmasked)
+
+	if len(unprotected) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "project",
+			Key:        c.Project,
+			ValueLabel: "unprotected variables",
+			Values:     unprotected,
+		})
+	} else if len(c.ProtectedVariables) > 0 {
+		c.AddPass("variables are protected")
+	}
+	if len(unmasked) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "project",
+			Key:        c.Project,
+			ValueLabel: "unmasked variables",
+			Values:     unmasked,
+		})
+	} else if len(c.MaskedVariables) > 0 {
+		c.AddPass("variables are masked")
+	}
+}
+
+// requiresProtection determines whether the branch protection needs to be
+// fetched.
+func (c *ProjectCheck) requiresProtection() bool {
+	return isTrue(c.BranchProtection) || isTrue(c.PreventForcePush)
+}
+
+// requiresApprovals determines whether the approvals are verified.
+func (c *ProjectCheck) requiresApprovals() bool {
+	return c.RequiredApprovals != nil && *c.RequiredApprovals > 0
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// matchAny determines whether the name matches any of the glob patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if match, _ := path.Match(p, name); match {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *ProjectCheck) RequiresNetwork() bool {
+	return true
+}
+
+// MaskedDataMap implements config.DataMasker, masking the values of the
+// CI/CD variables.
+func (c *ProjectCheck) MaskedDataMap() map[string][]byte {
+	masked := map[string][]byte{}
+	for k, v := range c.DataMap {
+		masked[k] = v
+	}
+	data, ok := c.DataMap["variables"]
+	if !ok {
+		return masked
+	}
+	variables := []map[string]any{}
+	if err := json.Unmarshal(data, &variables); err != nil {
+		delete(masked, "variables")
+		return masked
+	}
+	for _, v := range variables {
+		if value, ok := v["value"].(string); ok {
+			v["value"] = result.MaskValue(value)
+		}
+	}
+	masked["variables"], _ = json.Marshal(variables)
+	return masked
+}
@@ -0,0 +1,311 @@
+package gitlab_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/gitlab"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Project]()
+	assert.Equal(t, "*gitlab.ProjectCheck", reflect.TypeOf(c).String())
+}
+
+func TestProjectCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("CI_PROJECT_PATH", "acme/website")
+	t.Setenv("CI_API_V4_URL", "")
+	c := ProjectCheck{}
+	c.Init(Project)
+	assert.Equal("acme/website", c.Project)
+	assert.Equal("https://gitlab.com/api/v4", c.ApiUrl)
+
+	t.Setenv("CI_API_V4_URL", "https://gitlab.example.com/api/v4")
+	c = ProjectCheck{Project: "group/project"}
+	c.Init(Project)
+	assert.Equal("group/project", c.Project)
+	assert.Equal("https://gitlab.example.com/api/v4", c.ApiUrl)
+}
+
+func TestProjectCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	enabled := true
+	disabled := false
+	approvals, noApprovals := 2, 0
+	c := ProjectCheck{
+		Project:            "acme/website",
+		BranchProtection:   &enabled,
+		PreventForcePush:   &enabled,
+		ProtectedVariables: []string{"*TOKEN*"},
+	}
+	err := c.Merge(&ProjectCheck{
+		DefaultBranch:      "main",
+		RequiredApprovals:  &approvals,
+		PreventForcePush:   &disabled,
+		ProtectedVariables: []string{"*SECRET*"},
+	})
+	assert.NoError(err)
+	assert.Equal("acme/website", c.Project)
+	assert.Equal("main", c.DefaultBranch)
+	assert.Equal(2, *c.RequiredApprovals)
+	assert.True(*c.BranchProtection)
+	assert.False(*c.PreventForcePush)
+	assert.Equal([]string{"*SECRET*"}, c.ProtectedVariables)
+
+	// The required approvals can be disabled.
+	assert.NoError(c.Merge(&ProjectCheck{RequiredApprovals: &noApprovals}))
+	assert.Equal(0, *c.RequiredApprovals)
+	assert.NoError(c.Merge(&ProjectCheck{}))
+	assert.Equal(0, *c.RequiredApprovals)
+}
+
+// newTestServer serves the testdata, with the protection of the given
+// branches only.
+func newTestServer(t *testing.T, protected ...string) *httptest.Server {
+	mux := http.NewServeMux()
+	serveFile := func(path string, file string) {
+		data, _ := os.ReadFile(file)
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+			w.Write(data)
+		})
+	}
+	serveFile("/projects/acme/website", "testdata/project.json")
+	serveFile("/projects/acme/website/approvals", "testdata/approvals.json")
+	serveFile("/projects/acme/website/approval_rules", "testdata/approval_rules.json")
+	serveFile("/projects/acme/website/variables", "testdata/variables.json")
+	for _, b := range protected {
+		serveFile("/projects/acme/website/protected_branches/"+b, "testdata/protected_branch.json")
+	}
+	mux.HandleFunc("/projects/acme/private", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message": "401 Unauthorized"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "404 Not Found"}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestProjectCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("GITLAB_TOKEN", "secret")
+	srv := newTestServer(t, "main")
+	defer srv.Close()
+
+	c := ProjectCheck{}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		Value:      "no project provided",
+	}}, c.Result.Breaches)
+
+	c = ProjectCheck{Project: "acme/private", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "unable to fetch project acme/private",
+		Value:      "401 Unauthorized (401)",
+	}}, c.Result.Breaches)
+
+	enabled := true
+	one := 1
+	c = ProjectCheck{Project: "acme/missing", ApiUrl: srv.URL, BranchProtection: &enabled}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "project not found or not accessible",
+		Value:      "acme/missing",
+	}}, c.Result.Breaches)
+
+	// Only the required data is fetched.
+	c = ProjectCheck{Project: "acme/website", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Len(c.DataMap, 1)
+
+	c = ProjectCheck{Project: "acme/website", ApiUrl: srv.URL,
+		BranchProtection: &enabled, RequiredApprovals: &one, MaskedVariables: []string{"*"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("main", c.Branch)
+	assert.Contains(c.DataMap, "protection")
+	assert.Contains(c.DataMap, "approval_rules")
+	assert.Contains(c.DataMap, "variables")
+	assert.NotContains(c.DataMap, "approvals")
+
+	c = ProjectCheck{Project: "acme/website", ApiUrl: srv.URL, BranchProtection: &enabled, Branch: "develop"}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.NotContains(c.DataMap, "protection")
+
+	// The token can be provided in the config.
+	t.Setenv("GITLAB_TOKEN", "")
+	c = ProjectCheck{Project: "acme/website", ApiUrl: srv.URL, Token: "secret"}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+}
+
+func TestProjectCheckMaskedDataMap(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("GITLAB_TOKEN", "secret")
+	srv := newTestServer(t, "main")
+	defer srv.Close()
+
+	c := ProjectCheck{Project: "acme/website", ApiUrl: srv.URL, MaskedVariables: []string{"*"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	masked := c.MaskedDataMap()
+	assert.Equal(c.DataMap["project"], masked["project"])
+	assert.NotContains(string(masked["variables"]), "s3cr3t")
+	assert.NotContains(string(masked["variables"]), "production")
+	assert.Contains(string(masked["variables"]), "DEPLOY_TOKEN")
+	assert.Contains(string(masked["variables"]), result.MaskValue("s3cr3t"))
+	assert.Contains(string(c.DataMap["variables"]), "s3cr3t")
+}
+
+func TestProjectCheckRunCheck(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("GITLAB_TOKEN", "secret")
+	srv := newTestServer(t, "main")
+	defer srv.Close()
+
+	enabled := true
+	two, three := 2, 3
+	tt := []struct {
+		name         string
+		check        ProjectCheck
+		expectStatus result.Status
+		expectPasses []string
+		expectFails  []result.Breach
+	}{
+		{
+			name: "baselineMet",
+			check: ProjectCheck{
+				DefaultBranch:        "main",
+				BranchProtection:     &enabled,
+				PreventForcePush:     &enabled,
+				RequiredApprovals:    &two,
+				ResetApprovalsOnPush: &enabled,
+				ProtectedVariables:   []string{"DEPLOY_*"},
+				MaskedVariables:      []string{"AWS_*"},
+			},
+			expectStatus: result.Pass,
+			expectPasses: []string{
+				"default branch is main",
+				"branch main is protected",
+				"branch main prevents force pushes",
+				"merge requests require 2 approvals",
+				"approvals are reset on push",
+				"variables are protected",
+				"variables are masked",
+			},
+		},
+		{
+			name: "baselineNotMet",
+			check: ProjectCheck{
+				DefaultBranch:         "master",
+				RequiredApprovals:     &three,
+				PreventAuthorApproval: &enabled,
+				ProtectedVariables:    []string{"*TOKEN*", "*SECRET*"},
+				MaskedVariables:       []string{"*TOKEN*", "*SECRET*"},
+			},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "gitlab-project",
+					Severity:      "normal",
+					KeyLabel:      "project",
+					Key:           "acme/website",
+					ValueLabel:    "default branch",
+					ExpectedValue: "master",
+					Value:         "main",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "gitlab-project",
+					Severity:      "normal",
+					KeyLabel:      "project",
+					Key:           "acme/website",
+					ValueLabel:    "required approvals",
+					ExpectedValue: "3",
+					Value:         "2",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "gitlab-project",
+					Severity:   "normal",
+					KeyLabel:   "project",
+					Key:        "acme/website",
+					ValueLabel: "author approval",
+					Value:      "allowed",
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "gitlab-project",
+					Severity:   "normal",
+					KeyLabel:   "project",
+					Key:        "acme/website",
+					ValueLabel: "unprotected variables",
+					Values:     []string{"AWS_SECRET_ACCESS_KEY"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "gitlab-project",
+					Severity:   "normal",
+					KeyLabel:   "project",
+					Key:        "acme/website",
+					ValueLabel: "unmasked variables",
+					Values:     []string{"DEPLOY_TOKEN"},
+				},
+			},
+		},
+		{
+			name: "unprotectedBranch",
+			check: ProjectCheck{
+				Branch:           "develop",
+				BranchProtection: &enabled,
+			},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "gitlab-project",
+					Severity:   "normal",
+					KeyLabel:   "branch",
+					Key:        "develop",
+					ValueLabel: "branch protection",
+					Value:      "disabled",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.check
+			c.Project = "acme/website"
+			c.ApiUrl = srv.URL
+			c.Init(Project)
+			c.FetchData()
+			assert.Empty(c.Result.Breaches)
+			c.UnmarshalDataMap()
+			c.RunCheck()
+			c.Result.DetermineResultStatus(false)
+			assert.Equal(tc.expectStatus, c.Result.Status)
+			assert.ElementsMatch(tc.expectPasses, c.Result.Passes)
+			assert.ElementsMatch(tc.expectFails, c.Result.Breaches)
+		})
+	}
+}
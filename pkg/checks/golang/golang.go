@@ -0,0 +1,16 @@
+// Package golang provides checks for Go projects.
+package golang
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=golang
+
+func RegisterChecks() {
+	config.ChecksRegistry[GoMod] = func() config.Check { return &GoModCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
@@ -0,0 +1,71 @@
+package golang
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	jsoncheck "github.com/salsadigitalauorg/shipshape/pkg/checks/json"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const GoMod config.CheckType = "go-mod"
+
+// GoModCheck verifies the modules, replace directives & toolchain defined in
+// go.mod files, using the same key-values as the json check.
+type GoModCheck struct {
+	jsoncheck.JsonCheck `yaml:",inline"`
+	sums                map[string][]byte
+}
+
+// Init implementation for the go-mod check.
+func (c *GoModCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.File == "" && len(c.Files) == 0 && c.Pattern == "" {
+		c.File = "go.mod"
+	}
+}
+
+// Merge implementation for go-mod check.
+func (c *GoModCheck) Merge(mergeCheck config.Check) error {
+	goModMergeCheck := mergeCheck.(*GoModCheck)
+	return c.JsonCheck.Merge(&goModMergeCheck.JsonCheck)
+}
+
+// FetchData reads the go.mod files along with their go.sum.
+func (c *GoModCheck) FetchData() {
+	c.JsonCheck.FetchData()
+	c.sums = map[string][]byte{}
+	for configName := range c.DataMap {
+		// Files found by pattern already include the project directory.
+		modPath := configName
+		if c.Pattern == "" {
+			modPath = filepath.Join(config.ProjectDir, configName)
+		}
+		// A missing go.sum is reported through the unverified modules.
+		c.sums[configName], _ = os.ReadFile(filepath.Join(filepath.Dir(modPath), "go.sum"))
+	}
+}
+
+// UnmarshalDataMap parses the go.mod files into the same structure as json
+// data so that the key-values can be verified by the json check logic.
+func (c *GoModCheck) UnmarshalDataMap() {
+	c.Node = map[string]any{}
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[configName]
+		m, err := ParseModules(configName, data, c.sums[configName])
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{ValueLabel: configName, Value: err.Error()})
+			return
+		}
+
+		// Round-trip through json to get the generic types expected by the
+		// json key-values.
+		b, _ := json.Marshal(m)
+		var n any
+		json.Unmarshal(b, &n)
+		c.Node[configName] = n
+	}
+}
@@ -0,0 +1,93 @@
+package golang_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/golang"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/json"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[GoMod]()
+	assert.Equal(t, "*golang.GoModCheck", reflect.TypeOf(c).String())
+}
+
+func TestGoModCheckInit(t *testing.T) {
+	c := GoModCheck{}
+	c.Init(GoMod)
+	assert.Equal(t, "go.mod", c.File)
+}
+
+func TestGoModCheck(t *testing.T) {
+	assert := assert.New(t)
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	c := GoModCheck{JsonCheck: json.JsonCheck{KeyValues: []json.KeyValue{
+		{KeyValue: yaml.KeyValue{Key: "go", VersionConstraint: ">= 1.21"}},
+		{KeyValue: yaml.KeyValue{Key: "toolchain", Value: "go1.21.5"}},
+		{KeyValue: yaml.KeyValue{Key: "require[].path", IsList: true},
+			DisallowedValues: []any{"github.com/pkg/errors"}},
+		{KeyValue: yaml.KeyValue{Key: "length(replace)", Value: "0"}},
+		{KeyValue: yaml.KeyValue{Key: "length(unverified)", Value: "0"}},
+	}}}
+	c.Init(GoMod)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	c.UnmarshalDataMap()
+	assert.Empty(c.Result.Breaches)
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.ElementsMatch([]string{
+		"[go.mod] 'go' satisfies '>= 1.21'",
+		"[go.mod] 'toolchain' equals 'go1.21.5'",
+	}, c.Result.Passes)
+	assert.ElementsMatch([]result.Breach{
+		&result.KeyValuesBreach{
+			BreachType: "key-values",
+			CheckType:  "go-mod",
+			Severity:   "normal",
+			KeyLabel:   "config",
+			Key:        "go.mod",
+			ValueLabel: "disallowed require[].path",
+			Values:     []string{"github.com/pkg/errors"},
+		},
+		&result.KeyValueBreach{
+			BreachType:    "key-value",
+			CheckType:     "go-mod",
+			Severity:      "normal",
+			KeyLabel:      "go.mod",
+			Key:           "length(replace)",
+			ValueLabel:    "actual",
+			ExpectedValue: "0",
+			Value:         "2",
+		},
+		&result.KeyValueBreach{
+			BreachType:    "key-value",
+			CheckType:     "go-mod",
+			Severity:      "normal",
+			KeyLabel:      "go.mod",
+			Key:           "length(unverified)",
+			ValueLabel:    "actual",
+			ExpectedValue: "0",
+			Value:         "1",
+		},
+	}, c.Result.Breaches)
+}
+
+func TestGoModCheckUnmarshalDataMap(t *testing.T) {
+	c := GoModCheck{}
+	c.DataMap = map[string][]byte{"go.mod": []byte("require (")}
+	c.UnmarshalDataMap()
+	assert.EqualValues(t, []result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "go.mod",
+		Value:      "go.mod:1:10: syntax error (unterminated block started at go.mod:1:1)",
+	}}, c.Result.Breaches)
+}
@@ -0,0 +1,119 @@
+package golang
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Module is a module path & version, e.g, golang.org/x/mod v0.14.0.
+type Module struct {
+	Path     string `json:"path"`
+	Version  string `json:"version,omitempty"`
+	Indirect bool   `json:"indirect,omitempty"`
+}
+
+// Replace is a replace directive.
+type Replace struct {
+	Old Module `json:"old"`
+	New Module `json:"new"`
+}
+
+// Modules is the data extracted from go.mod & go.sum files.
+type Modules struct {
+	Module    string    `json:"module"`
+	Go        string    `json:"go"`
+	Toolchain string    `json:"toolchain"`
+	Require   []Module  `json:"require"`
+	Replace   []Replace `json:"replace"`
+	Exclude   []Module  `json:"exclude"`
+	// Required modules for which go.sum has no hash.
+	Unverified []string `json:"unverified"`
+}
+
+// ParseModules parses the content of go.mod & go.sum files; sum may be nil
+// if there is no go.sum file.
+func ParseModules(file string, mod []byte, sum []byte) (Modules, error) {
+	f, err := modfile.Parse(file, mod, nil)
+	if err != nil {
+		return Modules{}, err
+	}
+
+	m := Modules{
+		Require:    []Module{},
+		Replace:    []Replace{},
+		Exclude:    []Module{},
+		Unverified: []string{},
+	}
+	if f.Module != nil {
+		m.Module = f.Module.Mod.Path
+	}
+	if f.Go != nil {
+		m.Go = f.Go.Version
+	}
+	if f.Toolchain != nil {
+		m.Toolchain = f.Toolchain.Name
+	}
+	for _, r := range f.Require {
+		m.Require = append(m.Require, Module{
+			Path: r.Mod.Path, Version: r.Mod.Version, Indirect: r.Indirect})
+	}
+	for _, r := range f.Replace {
+		m.Replace = append(m.Replace, Replace{
+			Old: Module{Path: r.Old.Path, Version: r.Old.Version},
+			New: Module{Path: r.New.Path, Version: r.New.Version},
+		})
+	}
+	for _, e := range f.Exclude {
+		m.Exclude = append(m.Exclude, Module{Path: e.Mod.Path, Version: e.Mod.Version})
+	}
+
+	sums := ParseSums(sum)
+	for _, r := range m.Require {
+		mod := m.replacement(r)
+		// Modules replaced by a local directory have no hash.
+		if mod.Version == "" && modfile.IsDirectoryPath(mod.Path) {
+			continue
+		}
+		if !sums[mod.Path+"@"+mod.Version] {
+			m.Unverified = append(m.Unverified, r.Path+"@"+r.Version)
+		}
+	}
+	return m, nil
+}
+
+// replacement returns the module replacing the required one, if any; a
+// replace directive for the specific version takes precedence over one for
+// all versions.
+func (m Modules) replacement(r Module) Module {
+	mod := r
+	for _, rep := range m.Replace {
+		if rep.Old.Path != r.Path {
+			continue
+		}
+		if rep.Old.Version == r.Version {
+			return rep.New
+		}
+		if rep.Old.Version == "" {
+			mod = rep.New
+		}
+	}
+	return mod
+}
+
+// ParseSums returns the set of module versions, as path@version, having
+// a hash in go.sum data.
+func ParseSums(data []byte) map[string]bool {
+	sums := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		sums[fields[0]+"@"+strings.TrimSuffix(fields[1], "/go.mod")] = true
+	}
+	return sums
+}
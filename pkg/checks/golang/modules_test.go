@@ -0,0 +1,55 @@
+package golang_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/golang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseModules(t *testing.T) {
+	assert := assert.New(t)
+
+	mod, _ := os.ReadFile("testdata/go.mod")
+	sum, _ := os.ReadFile("testdata/go.sum")
+	m, err := ParseModules("go.mod", mod, sum)
+	assert.NoError(err)
+	assert.Equal(Modules{
+		Module:    "example.com/app",
+		Go:        "1.21",
+		Toolchain: "go1.21.5",
+		Require: []Module{
+			{Path: "github.com/google/uuid", Version: "v1.3.0"},
+			{Path: "github.com/pkg/errors", Version: "v0.9.1"},
+			{Path: "golang.org/x/crypto", Version: "v0.14.0"},
+			{Path: "golang.org/x/net", Version: "v0.10.0"},
+			{Path: "golang.org/x/text", Version: "v0.9.0", Indirect: true},
+		},
+		Replace: []Replace{
+			{
+				Old: Module{Path: "github.com/pkg/errors"},
+				New: Module{Path: "../errors"},
+			},
+			{
+				Old: Module{Path: "golang.org/x/crypto", Version: "v0.14.0"},
+				New: Module{Path: "github.com/example/crypto", Version: "v0.14.1"},
+			},
+		},
+		Exclude:    []Module{{Path: "golang.org/x/net", Version: "v0.9.0"}},
+		Unverified: []string{"github.com/google/uuid@v1.3.0"},
+	}, m)
+
+	// No go.sum; the module replaced by a local directory is skipped.
+	m, err = ParseModules("go.mod", mod, nil)
+	assert.NoError(err)
+	assert.Equal([]string{
+		"github.com/google/uuid@v1.3.0",
+		"golang.org/x/crypto@v0.14.0",
+		"golang.org/x/net@v0.10.0",
+		"golang.org/x/text@v0.9.0",
+	}, m.Unverified)
+
+	_, err = ParseModules("go.mod", []byte("require ("), nil)
+	assert.EqualError(err, "go.mod:1:10: syntax error (unterminated block started at go.mod:1:1)")
+}
@@ -0,0 +1,19 @@
+module example.com/app
+
+go 1.21
+
+toolchain go1.21.5
+
+require (
+	github.com/google/uuid v1.3.0
+	github.com/pkg/errors v0.9.1
+	golang.org/x/crypto v0.14.0
+	golang.org/x/net v0.10.0
+	golang.org/x/text v0.9.0 // indirect
+)
+
+replace github.com/pkg/errors => ../errors
+
+replace golang.org/x/crypto v0.14.0 => github.com/example/crypto v0.14.1
+
+exclude golang.org/x/net v0.9.0
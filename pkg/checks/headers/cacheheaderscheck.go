@@ -0,0 +1,165 @@
+package headers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const CacheHeaders config.CheckType = "http-cache-headers"
+
+// CachePolicy defines the caching expected for a list of paths.
+type CachePolicy struct {
+	Name string `yaml:"name"`
+	// Paths to request, relative to the base url, or full urls.
+	Paths []string `yaml:"paths"`
+	// Header to verify; defaults to Cache-Control.
+	Header string `yaml:"header"`
+	// Minimum & maximum ttl, in seconds.
+	MinTtl *int `yaml:"min-ttl"`
+	MaxTtl *int `yaml:"max-ttl"`
+	// Require the response not to be cached, i.e, one of the no-cache,
+	// no-store or private directives, or a ttl of 0.
+	NoCache bool `yaml:"no-cache"`
+	// Directives which must be present, e.g, public.
+	Directives []string `yaml:"directives"`
+}
+
+// CacheHeadersCheck requests urls and verifies their caching headers
+// against the policies.
+type CacheHeadersCheck struct {
+	config.CheckBase `yaml:",inline"`
+	BaseUrl          string        `yaml:"base-url"`
+	Policies         []CachePolicy `yaml:"policies"`
+	// Response headers, keyed by url.
+	headers map[string]http.Header
+}
+
+// Merge implementation for http-cache-headers check.
+func (c *CacheHeadersCheck) Merge(mergeCheck config.Check) error {
+	cacheHeadersMergeCheck := mergeCheck.(*CacheHeadersCheck)
+	if err := c.CheckBase.Merge(&cacheHeadersMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.BaseUrl, cacheHeadersMergeCheck.BaseUrl)
+	if len(cacheHeadersMergeCheck.Policies) > 0 {
+		c.Policies = cacheHeadersMergeCheck.Policies
+	}
+	return nil
+}
+
+// FetchData requests each of the policies' urls.
+func (c *CacheHeadersCheck) FetchData() {
+	c.DataMap = map[string][]byte{}
+	c.headers = map[string]http.Header{}
+	for _, p := range c.Policies {
+		for _, path := range p.Paths {
+			url := JoinUrl(c.BaseUrl, path)
+			if _, ok := c.headers[url]; ok {
+				continue
+			}
+			h, err := FetchHeaders(url)
+			if err != nil {
+				c.AddBreach(&result.KeyValueBreach{
+					KeyLabel:   "url",
+					Key:        url,
+					ValueLabel: "request failed",
+					Value:      err.Error(),
+				})
+				continue
+			}
+			c.headers[url] = h
+			c.DataMap[url] = []byte(h.Get(policyHeader(p)))
+		}
+	}
+}
+
+// RunCheck verifies the headers of each url against its policy.
+func (c *CacheHeadersCheck) RunCheck() {
+	for _, p := range c.Policies {
+		header := policyHeader(p)
+		for _, path := range p.Paths {
+			url := JoinUrl(c.BaseUrl, path)
+			h, ok := c.headers[url]
+			if !ok {
+				continue
+			}
+			c.verifyPolicy(p, header, url, h.Get(header))
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// verifyPolicy verifies a header value against the policy.
+func (c *CacheHeadersCheck) verifyPolicy(p CachePolicy, header string, url string, value string) {
+	breach := func(expected string) {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:      "url",
+			Key:           url,
+			ValueLabel:    fmt.Sprintf("[%s] %s", p.Name, header),
+			ExpectedValue: expected,
+			Value:         value,
+		})
+	}
+
+	if value == "" {
+		breach("header to be set")
+		return
+	}
+
+	directives := ParseDirectives(value)
+	ttl := Ttl(directives)
+	_, noCache := directives["no-cache"]
+	_, noStore := directives["no-store"]
+	_, private := directives["private"]
+	uncached := noCache || noStore || private
+	// Responses which are not cached by shared caches have an effective ttl
+	// of 0.
+	if uncached {
+		ttl = 0
+	}
+
+	failed := false
+	if p.NoCache && !uncached && ttl != 0 {
+		breach("no-cache, no-store, private or a ttl of 0")
+		failed = true
+	}
+	if p.MinTtl != nil && ttl < *p.MinTtl {
+		breach("ttl of at least " + strconv.Itoa(*p.MinTtl))
+		failed = true
+	}
+	if p.MaxTtl != nil && (ttl < 0 || ttl > *p.MaxTtl) {
+		breach("ttl of at most " + strconv.Itoa(*p.MaxTtl))
+		failed = true
+	}
+	for _, d := range p.Directives {
+		if _, ok := directives[d]; !ok {
+			breach("directive " + d)
+			failed = true
+		}
+	}
+
+	if !failed {
+		c.AddPass(fmt.Sprintf("[%s] %s '%s' complies with policy '%s'", url, header, value, p.Name))
+	}
+}
+
+func policyHeader(p CachePolicy) string {
+	if p.Header == "" {
+		return "Cache-Control"
+	}
+	return http.CanonicalHeaderKey(p.Header)
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *CacheHeadersCheck) RequiresNetwork() bool {
+	return true
+}
@@ -0,0 +1,145 @@
+package headers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/headers"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[CacheHeaders]()
+	assert.Equal(t, "*headers.CacheHeadersCheck", reflect.TypeOf(c).String())
+	c = config.ChecksRegistry[SecurityHeaders]()
+	assert.Equal(t, "*headers.SecurityHeadersCheck", reflect.TypeOf(c).String())
+}
+
+func TestCacheHeadersCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := CacheHeadersCheck{
+		BaseUrl:  "https://example.com",
+		Policies: []CachePolicy{{Name: "html", Paths: []string{"/"}}},
+	}
+	err := c.Merge(&CacheHeadersCheck{
+		Policies: []CachePolicy{{Name: "admin", Paths: []string{"/admin"}, NoCache: true}},
+	})
+	assert.NoError(err)
+	assert.Equal("https://example.com", c.BaseUrl)
+	assert.Equal([]CachePolicy{{Name: "admin", Paths: []string{"/admin"}, NoCache: true}}, c.Policies)
+}
+
+func newCacheServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Cache-Control", "public, max-age=300")
+			w.Header().Set("Surrogate-Control", "max-age=3600")
+		case "/logo.png":
+			w.Header().Set("Cache-Control", "public, max-age=600")
+		case "/admin":
+			w.Header().Set("Location", "/user/login")
+			w.WriteHeader(http.StatusFound)
+		case "/user/login":
+			w.Header().Set("Cache-Control", "no-cache, private")
+		}
+	}))
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestCacheHeadersCheckRunCheck(t *testing.T) {
+	srv := newCacheServer()
+	defer srv.Close()
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "compliant",
+			Check: &CacheHeadersCheck{
+				BaseUrl: srv.URL,
+				Policies: []CachePolicy{
+					{Name: "html", Paths: []string{"/"}, MaxTtl: intPtr(900), Directives: []string{"public"}},
+					{Name: "cdn", Paths: []string{"/"}, Header: "surrogate-control", MinTtl: intPtr(3600)},
+					{Name: "login", Paths: []string{"/user/login"}, NoCache: true, MaxTtl: intPtr(0)},
+				},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"[" + srv.URL + "/] Cache-Control 'public, max-age=300' complies with policy 'html'",
+				"[" + srv.URL + "/] Surrogate-Control 'max-age=3600' complies with policy 'cdn'",
+				"[" + srv.URL + "/user/login] Cache-Control 'no-cache, private' complies with policy 'login'",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "nonCompliant",
+			Check: &CacheHeadersCheck{
+				BaseUrl: srv.URL,
+				Policies: []CachePolicy{
+					{Name: "assets", Paths: []string{"/logo.png"}, MinTtl: intPtr(86400)},
+					{Name: "admin", Paths: []string{"/admin", "/"}, NoCache: true},
+				},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					KeyLabel:      "url",
+					Key:           srv.URL + "/logo.png",
+					ValueLabel:    "[assets] Cache-Control",
+					ExpectedValue: "ttl of at least 86400",
+					Value:         "public, max-age=600",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					KeyLabel:      "url",
+					Key:           srv.URL + "/admin",
+					ValueLabel:    "[admin] Cache-Control",
+					ExpectedValue: "header to be set",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					KeyLabel:      "url",
+					Key:           srv.URL + "/",
+					ValueLabel:    "[admin] Cache-Control",
+					ExpectedValue: "no-cache, no-store, private or a ttl of 0",
+					Value:         "public, max-age=300",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.FetchData()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
+
+func TestCacheHeadersCheckFetchDataError(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newCacheServer()
+	srv.Close()
+
+	c := CacheHeadersCheck{
+		BaseUrl:  srv.URL,
+		Policies: []CachePolicy{{Name: "html", Paths: []string{"/"}}},
+	}
+	c.FetchData()
+	assert.Len(c.Result.Breaches, 1)
+	b := c.Result.Breaches[0].(*result.KeyValueBreach)
+	assert.Equal(srv.URL+"/", b.Key)
+	assert.Equal("request failed", b.ValueLabel)
+	assert.Contains(b.Value, "connection refused")
+}
@@ -0,0 +1,77 @@
+// Package headers provides checks against the HTTP response headers of a
+// site.
+package headers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=headers
+
+func RegisterChecks() {
+	config.ChecksRegistry[CacheHeaders] = func() config.Check { return &CacheHeadersCheck{} }
+	config.ChecksRegistry[SecurityHeaders] = func() config.Check { return &SecurityHeadersCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// HttpClient is used to request the urls; redirects are not followed so that
+// the headers of the requested url itself are verified.
+var HttpClient = &http.Client{
+	Timeout: 10 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// FetchHeaders requests the url and returns the response headers.
+func FetchHeaders(url string) (http.Header, error) {
+	resp, err := HttpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp.Header, nil
+}
+
+// JoinUrl appends the path to the base url, unless the path is a full url.
+func JoinUrl(baseUrl string, path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimSuffix(baseUrl, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// ParseDirectives parses a Cache-Control-like header into its directives,
+// with lowercase names; directives without a value are mapped to "".
+func ParseDirectives(header string) map[string]string {
+	directives := map[string]string{}
+	for _, d := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(d), "=")
+		if name == "" {
+			continue
+		}
+		directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+	}
+	return directives
+}
+
+// Ttl determines the shared cache ttl from the directives, using s-maxage
+// over max-age; it returns -1 if neither is set or valid.
+func Ttl(directives map[string]string) int {
+	for _, d := range []string{"s-maxage", "max-age"} {
+		if v, ok := directives[d]; ok {
+			if ttl, err := strconv.Atoi(v); err == nil {
+				return ttl
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,37 @@
+package headers_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/headers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinUrl(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("https://example.com/user/login", JoinUrl("https://example.com/", "/user/login"))
+	assert.Equal("https://example.com/", JoinUrl("https://example.com", ""))
+	assert.Equal("https://cdn.example.com/logo.png", JoinUrl("https://example.com", "https://cdn.example.com/logo.png"))
+}
+
+func TestParseDirectives(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(map[string]string{}, ParseDirectives(""))
+	assert.Equal(map[string]string{
+		"public":   "",
+		"max-age":  "300",
+		"s-maxage": "3600",
+		"private":  "set-cookie",
+	}, ParseDirectives(`Public, max-age=300,s-maxage=3600, private="set-cookie"`))
+}
+
+func TestTtl(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(-1, Ttl(ParseDirectives("no-cache")))
+	assert.Equal(-1, Ttl(ParseDirectives("max-age=abc")))
+	assert.Equal(300, Ttl(ParseDirectives("public, max-age=300")))
+	assert.Equal(3600, Ttl(ParseDirectives("max-age=300, s-maxage=3600")))
+}
@@ -0,0 +1,182 @@
+package headers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const SecurityHeaders config.CheckType = "http-security-headers"
+
+// HeaderRule defines the expectation for a security header.
+type HeaderRule struct {
+	Name string `yaml:"name"`
+	// Regular expression the value must match.
+	Pattern string `yaml:"pattern"`
+	// Require the header to be absent, e.g, X-Powered-By.
+	Disallowed bool `yaml:"disallowed"`
+	// Do not fail if the header is absent; its value is verified otherwise.
+	Optional bool `yaml:"optional"`
+	// Severity of the header's breaches; defaults to the check's severity.
+	Severity config.Severity `yaml:"severity"`
+}
+
+// DefaultHeaderRules are verified when no headers are configured.
+var DefaultHeaderRules = []HeaderRule{
+	{Name: "Strict-Transport-Security", Pattern: `max-age=[1-9][0-9]*`},
+	{Name: "Content-Security-Policy"},
+	{Name: "X-Content-Type-Options", Pattern: `(?i)^nosniff$`},
+	{Name: "X-Frame-Options", Pattern: `(?i)^(deny|sameorigin)$`},
+	{Name: "Referrer-Policy"},
+	{Name: "X-Powered-By", Disallowed: true},
+}
+
+// SecurityHeadersCheck requests urls and verifies the presence, absence and
+// values of their security headers.
+type SecurityHeadersCheck struct {
+	config.CheckBase `yaml:",inline"`
+	BaseUrl          string `yaml:"base-url"`
+	// Paths to request, relative to the base url, or full urls; defaults to
+	// the base url itself.
+	Paths   []string     `yaml:"paths"`
+	Headers []HeaderRule `yaml:"headers"`
+	// Response headers, keyed by url.
+	headers map[string]http.Header
+}
+
+// Init implementation for the http-security-headers check.
+func (c *SecurityHeadersCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if len(c.Headers) == 0 {
+		c.Headers = DefaultHeaderRules
+	}
+}
+
+// Merge implementation for http-security-headers check.
+func (c *SecurityHeadersCheck) Merge(mergeCheck config.Check) error {
+	securityHeadersMergeCheck := mergeCheck.(*SecurityHeadersCheck)
+	if err := c.CheckBase.Merge(&securityHeadersMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.BaseUrl, securityHeadersMergeCheck.BaseUrl)
+	utils.MergeStringSlice(&c.Paths, securityHeadersMergeCheck.Paths)
+	if len(securityHeadersMergeCheck.Headers) > 0 {
+		c.Headers = securityHeadersMergeCheck.Headers
+	}
+	return nil
+}
+
+// FetchData requests each of the urls.
+func (c *SecurityHeadersCheck) FetchData() {
+	c.DataMap = map[string][]byte{}
+	c.headers = map[string]http.Header{}
+	for _, url := range c.urls() {
+		if _, ok := c.headers[url]; ok {
+			continue
+		}
+		h, err := FetchHeaders(url)
+		if err != nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "url",
+				Key:        url,
+				ValueLabel: "request failed",
+				Value:      err.Error(),
+			})
+			continue
+		}
+		c.headers[url] = h
+		c.DataMap[url] = []byte(fmt.Sprint(h))
+	}
+}
+
+// RunCheck verifies the headers of each url against the rules.
+func (c *SecurityHeadersCheck) RunCheck() {
+	for _, url := range c.urls() {
+		h, ok := c.headers[url]
+		if !ok {
+			continue
+		}
+		for _, rule := range c.Headers {
+			c.verifyRule(rule, url, h)
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// urls returns the list of urls to request.
+func (c *SecurityHeadersCheck) urls() []string {
+	if len(c.Paths) == 0 {
+		return []string{c.BaseUrl}
+	}
+	urls := []string{}
+	for _, path := range c.Paths {
+		urls = append(urls, JoinUrl(c.BaseUrl, path))
+	}
+	return urls
+}
+
+// verifyRule verifies a header of the response against the rule.
+func (c *SecurityHeadersCheck) verifyRule(rule HeaderRule, url string, h http.Header) {
+	name := http.CanonicalHeaderKey(rule.Name)
+	values, present := h[name]
+	value := ""
+	if present {
+		value = h.Get(name)
+	}
+
+	breach := func(expected string) {
+		b := &result.KeyValueBreach{
+			KeyLabel:      "url",
+			Key:           url,
+			ValueLabel:    name,
+			ExpectedValue: expected,
+			Value:         value,
+		}
+		c.AddBreach(b)
+		if rule.Severity != "" {
+			b.Severity = string(rule.Severity)
+		}
+	}
+
+	if rule.Disallowed {
+		if present {
+			breach("header to be absent")
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] %s is absent", url, name))
+		}
+		return
+	}
+
+	if !present || len(values) == 0 {
+		if !rule.Optional {
+			breach("header to be set")
+		}
+		return
+	}
+
+	if rule.Pattern != "" {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			breach("valid pattern: " + err.Error())
+			return
+		}
+		if !re.MatchString(value) {
+			breach("value matching " + rule.Pattern)
+			return
+		}
+	}
+	c.AddPass(fmt.Sprintf("[%s] %s is '%s'", url, name, value))
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *SecurityHeadersCheck) RequiresNetwork() bool {
+	return true
+}
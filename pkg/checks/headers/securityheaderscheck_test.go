@@ -0,0 +1,153 @@
+package headers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/headers"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeadersCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := SecurityHeadersCheck{}
+	c.Init(SecurityHeaders)
+	assert.Equal(DefaultHeaderRules, c.Headers)
+
+	c = SecurityHeadersCheck{Headers: []HeaderRule{{Name: "X-Frame-Options"}}}
+	c.Init(SecurityHeaders)
+	assert.Equal([]HeaderRule{{Name: "X-Frame-Options"}}, c.Headers)
+}
+
+func TestSecurityHeadersCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := SecurityHeadersCheck{
+		BaseUrl: "https://example.com",
+		Headers: []HeaderRule{{Name: "X-Frame-Options"}},
+	}
+	err := c.Merge(&SecurityHeadersCheck{
+		Paths:   []string{"/user/login"},
+		Headers: []HeaderRule{{Name: "X-Powered-By", Disallowed: true}},
+	})
+	assert.NoError(err)
+	assert.Equal("https://example.com", c.BaseUrl)
+	assert.Equal([]string{"/user/login"}, c.Paths)
+	assert.Equal([]HeaderRule{{Name: "X-Powered-By", Disallowed: true}}, c.Headers)
+}
+
+func newSecurityServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+			w.Header().Set("Content-Security-Policy", "default-src 'self'")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+			w.Header().Set("Referrer-Policy", "strict-origin")
+		case "/legacy":
+			w.Header().Set("Strict-Transport-Security", "max-age=0")
+			w.Header().Set("X-Frame-Options", "ALLOW-FROM https://example.com")
+			w.Header().Set("X-Powered-By", "PHP/8.1")
+		}
+	}))
+}
+
+func TestSecurityHeadersCheckRunCheck(t *testing.T) {
+	srv := newSecurityServer()
+	defer srv.Close()
+
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "compliant",
+			Check:        &SecurityHeadersCheck{BaseUrl: srv.URL + "/", Headers: DefaultHeaderRules},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"[" + srv.URL + "/] Content-Security-Policy is 'default-src 'self''",
+				"[" + srv.URL + "/] Referrer-Policy is 'strict-origin'",
+				"[" + srv.URL + "/] Strict-Transport-Security is 'max-age=31536000; includeSubDomains'",
+				"[" + srv.URL + "/] X-Content-Type-Options is 'nosniff'",
+				"[" + srv.URL + "/] X-Frame-Options is 'SAMEORIGIN'",
+				"[" + srv.URL + "/] X-Powered-By is absent",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "nonCompliant",
+			Check: &SecurityHeadersCheck{
+				BaseUrl: srv.URL,
+				Paths:   []string{"/legacy"},
+				Headers: []HeaderRule{
+					{Name: "strict-transport-security", Pattern: `max-age=[1-9][0-9]*`, Severity: "high"},
+					{Name: "Content-Security-Policy"},
+					{Name: "Permissions-Policy", Optional: true},
+					{Name: "X-Frame-Options", Pattern: `(?i)^(deny|sameorigin)$`},
+					{Name: "X-Powered-By", Disallowed: true, Severity: "low"},
+				},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					Severity:      "high",
+					KeyLabel:      "url",
+					Key:           srv.URL + "/legacy",
+					ValueLabel:    "Strict-Transport-Security",
+					ExpectedValue: "value matching max-age=[1-9][0-9]*",
+					Value:         "max-age=0",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					KeyLabel:      "url",
+					Key:           srv.URL + "/legacy",
+					ValueLabel:    "Content-Security-Policy",
+					ExpectedValue: "header to be set",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					KeyLabel:      "url",
+					Key:           srv.URL + "/legacy",
+					ValueLabel:    "X-Frame-Options",
+					ExpectedValue: "value matching (?i)^(deny|sameorigin)$",
+					Value:         "ALLOW-FROM https://example.com",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					Severity:      "low",
+					KeyLabel:      "url",
+					Key:           srv.URL + "/legacy",
+					ValueLabel:    "X-Powered-By",
+					ExpectedValue: "header to be absent",
+					Value:         "PHP/8.1",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.FetchData()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
+
+func TestSecurityHeadersCheckFetchDataError(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newSecurityServer()
+	srv.Close()
+
+	c := SecurityHeadersCheck{BaseUrl: srv.URL}
+	c.FetchData()
+	assert.Len(c.Result.Breaches, 1)
+	b := c.Result.Breaches[0].(*result.KeyValueBreach)
+	assert.Equal(srv.URL, b.Key)
+	assert.Equal("request failed", b.ValueLabel)
+	assert.Contains(b.Value, "connection refused")
+}
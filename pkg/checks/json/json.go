@@ -5,6 +5,7 @@ package json
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/goccy/go-json"
 	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
@@ -17,7 +18,8 @@ import (
 // implementation.
 func (c *JsonCheck) UnmarshalDataMap() {
 	c.Node = map[string]any{}
-	for configName, data := range c.DataMap {
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[configName]
 		var n any
 		err := json.Unmarshal(data, &n)
 		if err != nil {
@@ -34,38 +36,7 @@ func (c *JsonCheck) UnmarshalDataMap() {
 func (c *JsonCheck) processData(configName string) {
 	for _, kv := range c.KeyValues {
 		kvr, fails, err := CheckKeyValue(c.Node[configName], kv)
-		switch kvr {
-		case yaml.KeyValueError:
-			c.AddBreach(&result.ValueBreach{Value: err.Error()})
-		case yaml.KeyValueNotFound:
-			c.AddBreach(&result.KeyValueBreach{
-				KeyLabel:   "config",
-				Key:        configName,
-				ValueLabel: "key not found",
-				Value:      kv.Key,
-			})
-		case yaml.KeyValueNotEqual:
-			c.AddBreach(&result.KeyValueBreach{
-				KeyLabel:      configName,
-				Key:           kv.Key,
-				ValueLabel:    "actual",
-				ExpectedValue: kv.Value,
-				Value:         fails[0],
-			})
-		case yaml.KeyValueDisallowedFound:
-			c.AddBreach(&result.KeyValuesBreach{
-				KeyLabel:   "config",
-				Key:        configName,
-				ValueLabel: fmt.Sprintf("disallowed %s", kv.Key),
-				Values:     fails,
-			})
-		case yaml.KeyValueEqual:
-			if kv.IsList {
-				c.AddPass(fmt.Sprintf("[%s] no disallowed '%s'", configName, kv.Key))
-			} else {
-				c.AddPass(fmt.Sprintf("[%s] '%s' equals '%s'", configName, kv.Key, kv.Value))
-			}
-		}
+		yaml.AddKeyValueResult(&c.CheckBase, configName, configName, kv.KeyValue, kvr, fails, err)
 	}
 	if len(c.Result.Breaches) != 0 {
 		c.Result.Status = result.Fail
@@ -89,6 +60,29 @@ func CheckKeyValue(node any, kv KeyValue) (yaml.KeyValueResult, []string, error)
 		return yaml.KeyValueEqual, nil, nil
 	}
 
+	if kv.IsConstraintCheck() {
+		values := []any{foundValues}
+		if list, ok := foundValues.([]any); ok {
+			values = list
+		}
+		var fails []string
+		breach := yaml.KeyValueEqual
+		for _, item := range values {
+			kvr, msg, err := kv.CheckConstraint(constraintValue(item))
+			if err != nil {
+				return yaml.KeyValueError, nil, err
+			}
+			if msg != "" {
+				breach = yaml.MergeConstraintBreach(breach, kvr)
+				fails = append(fails, msg)
+			}
+		}
+		if len(fails) > 0 {
+			return breach, fails, nil
+		}
+		return yaml.KeyValueEqual, nil, nil
+	}
+
 	// Throw an error if we are checking a list but no allow/disallow list provided.
 	if len(kv.AllowedValues) == 0 && len(kv.DisallowedValues) == 0 && kv.IsList {
 		return yaml.KeyValueError, nil, errors.New("list of allowed or disallowed values not provided")
@@ -132,3 +126,13 @@ func CheckKeyValue(node any, kv KeyValue) (yaml.KeyValueResult, []string, error)
 	}
 	return yaml.KeyValueEqual, nil, nil
 }
+
+// constraintValue converts a Json value to the string verified by the
+// constraints; numbers are formatted without an exponent so that, e.g, unix
+// timestamps can be parsed.
+func constraintValue(item any) string {
+	if f, ok := item.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(item)
+}
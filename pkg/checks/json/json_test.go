@@ -1,13 +1,16 @@
 package json_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-json"
 	. "github.com/salsadigitalauorg/shipshape/pkg/checks/json"
 	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -95,6 +98,12 @@ func TestJsonCheckKeyValue(t *testing.T) {
 	}
 }`), &multiValueNode)
 
+	var versionNode any
+	json.Unmarshal([]byte(`{
+	"lockfileVersion": 1,
+	"engines": {"node": ">=18.12.0"}
+}`), &versionNode)
+
 	tests := []struct {
 		name           string
 		node           any
@@ -313,6 +322,20 @@ func TestJsonCheckKeyValue(t *testing.T) {
 			expectedValues: []string{"vcs", "library"},
 			expectedError:  "",
 		},
+		{
+			name: "JMESPath multivalue - disallowed pattern found",
+			node: multiValueNode,
+			keyValue: KeyValue{
+				KeyValue: yaml.KeyValue{
+					Key:    "repositories.*.type",
+					IsList: true,
+				},
+				DisallowedValues: []any{"re:^(vcs|lib)"},
+			},
+			expectedResult: yaml.KeyValueDisallowedFound,
+			expectedValues: []string{"vcs", "library"},
+			expectedError:  "",
+		},
 		{
 			name: "JSONPath multivalue - no disallowed value found",
 			node: multiValueNode,
@@ -397,6 +420,32 @@ func TestJsonCheckKeyValue(t *testing.T) {
 			expectedValues: []string{"vcs", "library"},
 			expectedError:  "",
 		},
+		{
+			name: "JMESPath version satisfies constraint",
+			node: versionNode,
+			keyValue: KeyValue{
+				KeyValue: yaml.KeyValue{
+					Key:               "engines.node",
+					VersionConstraint: ">= 18",
+				},
+			},
+			expectedResult: yaml.KeyValueEqual,
+			expectedValues: nil,
+			expectedError:  "",
+		},
+		{
+			name: "JMESPath version does not satisfy constraint",
+			node: versionNode,
+			keyValue: KeyValue{
+				KeyValue: yaml.KeyValue{
+					Key:               "lockfileVersion",
+					VersionConstraint: ">= 2",
+				},
+			},
+			expectedResult: yaml.KeyValueVersionBreach,
+			expectedValues: []string{"1 does not satisfy '>= 2'"},
+			expectedError:  "",
+		},
 	}
 
 	for _, test := range tests {
@@ -415,3 +464,68 @@ func TestJsonCheckKeyValue(t *testing.T) {
 	}
 
 }
+
+func TestJsonCheckKeyValueAge(t *testing.T) {
+	assert := assert.New(t)
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Unix(1700000000, 0) }
+
+	var node any
+	json.Unmarshal([]byte(`{
+	"backup": {
+		"last": 1699990000,
+		"all": [1699990000, 1690000000],
+		"date": "2023-11-14T20:00:00Z"
+	}
+}`), &node)
+
+	kvr, values, err := CheckKeyValue(node, KeyValue{
+		KeyValue: yaml.KeyValue{Key: "backup.last", MaxAge: "24h", TimeLayout: "unix"}})
+	assert.Nil(err)
+	assert.Equal(yaml.KeyValueEqual, kvr)
+	assert.Empty(values)
+
+	kvr, values, err = CheckKeyValue(node, KeyValue{
+		KeyValue: yaml.KeyValue{Key: "backup.all", IsList: true, MaxAge: "24h"}})
+	assert.Nil(err)
+	assert.Equal(yaml.KeyValueAgeBreach, kvr)
+	assert.EqualValues([]string{"1690000000 is older than 24h (age: 2777h46m40s)"}, values)
+
+	kvr, values, err = CheckKeyValue(node, KeyValue{
+		KeyValue: yaml.KeyValue{Key: "backup.date", MinAge: "3h"}})
+	assert.Nil(err)
+	assert.Equal(yaml.KeyValueAgeBreach, kvr)
+	assert.EqualValues([]string{"2023-11-14T20:00:00Z is newer than 3h (age: 2h13m20s)"}, values)
+}
+
+func TestJsonCheckStdin(t *testing.T) {
+	assert := assert.New(t)
+
+	curStdin := config.Stdin
+	defer func() {
+		config.Stdin = curStdin
+		config.ResetStdin()
+	}()
+	config.Stdin = strings.NewReader(`{"items": [
+	{"metadata": {"name": "web", "namespace": "app"}},
+	{"metadata": {"name": "worker", "namespace": "default"}}
+]}`)
+	config.ResetStdin()
+
+	c := JsonCheck{
+		YamlCheck: yaml.YamlCheck{Stdin: "json"},
+		KeyValues: []KeyValue{{
+			KeyValue:         yaml.KeyValue{Key: "$.items[*].metadata.namespace", IsList: true},
+			DisallowedValues: []any{"default", "glob:kube-*"},
+		}},
+	}
+	c.Init(Json)
+	c.FetchData()
+	c.UnmarshalDataMap()
+	c.RunCheck()
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.Len(c.Result.Breaches, 1)
+	assert.Contains(c.Result.Breaches[0].String(), "default")
+}
@@ -23,18 +23,36 @@ func (kv KeyValue) IsDisallowed(value any) bool {
 	}
 
 	// Check disallowed list.
-	if len(kv.DisallowedValues) > 0 && utils.SliceContains(kv.DisallowedValues, value) {
+	if len(kv.DisallowedValues) > 0 && valuesMatch(kv.DisallowedValues, value) {
 		return true
 	}
 
 	// Check allowed list.
-	if len(kv.AllowedValues) > 0 && !utils.SliceContains(kv.AllowedValues, value) {
+	if len(kv.AllowedValues) > 0 && !valuesMatch(kv.AllowedValues, value) {
 		return true
 	}
 
 	return false
 }
 
+// valuesMatch determines whether the value is in the list; string values can
+// also be matched by regex ("re:") or glob ("glob:") entries.
+func valuesMatch(values []any, value any) bool {
+	if utils.SliceContains(values, value) {
+		return true
+	}
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	for _, v := range values {
+		if p, ok := v.(string); ok && utils.MatchString(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty determines if a value if empty.
 func (kv KeyValue) IsEmpty(value any) bool {
 	if value == nil {
@@ -0,0 +1,72 @@
+// Package lagoon provides checks against the Lagoon API.
+package lagoon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=lagoon
+
+func RegisterChecks() {
+	config.ChecksRegistry[Project] = func() config.Check { return &ProjectCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// graphqlResponse is the response of the GraphQL API.
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// apiQuery runs a GraphQL query against the API, authenticating with the
+// token if provided; it returns the response's data.
+func apiQuery(apiUrl string, token string, query string, variables map[string]any) ([]byte, error) {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(apiUrl, "/")+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := utils.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s (%d)", strings.TrimSpace(string(respBody)), resp.StatusCode)
+	}
+
+	gqlResp := graphqlResponse{}
+	if err := json.Unmarshal(respBody, &gqlResp); err != nil {
+		return nil, err
+	}
+	if len(gqlResp.Errors) > 0 {
+		return nil, errors.New(gqlResp.Errors[0].Message)
+	}
+	return gqlResp.Data, nil
+}
@@ -0,0 +1,363 @@
+package lagoon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	lagoonapi "github.com/salsadigitalauorg/shipshape/pkg/lagoon"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Project config.CheckType = "lagoon-project"
+
+// projectQuery fetches the project's metadata and that of its environments;
+// the values of the variables are not fetched.
+const projectQuery = `query($name: String!) {
+  projectByName(name: $name) {
+    name
+    productionEnvironment
+    envVariables { name scope }
+    environments {
+      name
+      environmentType
+      openshift { name }
+      routes
+      envVariables { name scope }
+      backups { backupId created }
+    }
+  }
+}`
+
+// Variable is a project or environment variable; its value is not kept.
+type Variable struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+// Backup is an environment's backup.
+type Backup struct {
+	BackupId string `json:"backupId"`
+	Created  string `json:"created"`
+}
+
+// Environment is the subset of an environment's metadata verified.
+type Environment struct {
+	Name            string `json:"name"`
+	EnvironmentType string `json:"environmentType"`
+	// The deploy target, i.e, the cluster the environment is deployed to.
+	Openshift struct {
+		Name string `json:"name"`
+	} `json:"openshift"`
+	// Comma-separated list of the environment's routes.
+	Routes       string     `json:"routes"`
+	EnvVariables []Variable `json:"envVariables"`
+	Backups      []Backup   `json:"backups"`
+}
+
+// ProjectMetadata is the subset of a project's metadata verified.
+type ProjectMetadata struct {
+	Name                  string        `json:"name"`
+	ProductionEnvironment string        `json:"productionEnvironment"`
+	EnvVariables          []Variable    `json:"envVariables"`
+	Environments          []Environment `json:"environments"`
+}
+
+// ProjectCheck verifies a Lagoon project's environment, e.g, its deploy
+// target, variables, backups and routes, using the API.
+type ProjectCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Project name; defaults to the LAGOON_PROJECT environment variable.
+	Project string `yaml:"project"`
+	// Environment name; defaults to the LAGOON_ENVIRONMENT environment
+	// variable, then to the project's production environment.
+	Environment string `yaml:"environment"`
+	// Url of the API; defaults to the lagoon-api-base-url.
+	ApiUrl string `yaml:"api-url"`
+	// Expected deploy target; can be a regex (re:) or glob (glob:) pattern.
+	DeployTarget string `yaml:"deploy-target"`
+	// Variables which must be defined, for the project or the environment.
+	RequiredVariables []string `yaml:"required-variables"`
+	// Variables which must not be defined; values can be regex (re:) or glob
+	// (glob:) patterns.
+	DisallowedVariables []string `yaml:"disallowed-variables"`
+	// Maximum age of the environment's latest backup, e.g, 1d.
+	BackupMaxAge string `yaml:"backup-max-age"`
+	// Routes which must be served by the environment.
+	RequiredRoutes []string `yaml:"required-routes"`
+	// Name under which the project's metadata is published as json, for
+	// subsequent checks to consume.
+	Publish     string `yaml:"publish"`
+	project     ProjectMetadata
+	environment *Environment
+}
+
+// Init implementation for the lagoon-project check.
+func (c *ProjectCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.Project == "" {
+		c.Project = os.Getenv("LAGOON_PROJECT")
+	}
+	if c.Environment == "" {
+		c.Environment = os.Getenv("LAGOON_ENVIRONMENT")
+	}
+}
+
+// Merge implementation for lagoon-project check.
+func (c *ProjectCheck) Merge(mergeCheck config.Check) error {
+	projectMergeCheck := mergeCheck.(*ProjectCheck)
+	if err := c.CheckBase.Merge(&projectMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Project, projectMergeCheck.Project)
+	utils.MergeString(&c.Environment, projectMergeCheck.Environment)
+	utils.MergeString(&c.ApiUrl, projectMergeCheck.ApiUrl)
+	utils.MergeString(&c.DeployTarget, projectMergeCheck.DeployTarget)
+	utils.MergeStringSlice(&c.RequiredVariables, projectMergeCheck.RequiredVariables)
+	utils.MergeStringSlice(&c.DisallowedVariables, projectMergeCheck.DisallowedVariables)
+	utils.MergeString(&c.BackupMaxAge, projectMergeCheck.BackupMaxAge)
+	utils.MergeStringSlice(&c.RequiredRoutes, projectMergeCheck.RequiredRoutes)
+	utils.MergeString(&c.Publish, projectMergeCheck.Publish)
+	return nil
+}
+
+// PublishesData implements config.DataPublisher.
+func (c *ProjectCheck) PublishesData() []string {
+	if c.Publish == "" {
+		return nil
+	}
+	return []string{c.Publish}
+}
+
+// FetchData queries the API for the project's metadata.
+func (c *ProjectCheck) FetchData() {
+	if c.Project == "" {
+		c.AddBreach(&result.ValueBreach{Value: "no project provided"})
+		return
+	}
+	apiUrl := c.ApiUrl
+	if apiUrl == "" {
+		apiUrl = lagoonapi.ApiBaseUrl
+	}
+	if apiUrl == "" {
+		c.AddBreach(&result.ValueBreach{Value: "no api url provided"})
+		return
+	}
+
+	data, err := apiQuery(apiUrl, lagoonapi.ApiToken, projectQuery,
+		map[string]any{"name": c.Project})
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to fetch project " + c.Project,
+			Value:      err.Error()})
+		return
+	}
+	c.DataMap = map[string][]byte{"project": data}
+}
+
+// UnmarshalDataMap parses the API response, then determines the environment
+// to verify.
+func (c *ProjectCheck) UnmarshalDataMap() {
+	resp := struct {
+		ProjectByName *ProjectMetadata `json:"projectByName"`
+	}{}
+	if err := json.Unmarshal(c.DataMap["project"], &resp); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to parse project", Value: err.Error()})
+		return
+	}
+	// Projects the token has no access to are also returned as null.
+	if resp.ProjectByName == nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "project not found or not accessible",
+			Value:      c.Project})
+		return
+	}
+	c.project = *resp.ProjectByName
+
+	name := c.Environment
+	if name == "" {
+		name = c.project.ProductionEnvironment
+	}
+	c.environment = nil
+	for i := range c.project.Environments {
+		if c.project.Environments[i].Name == name {
+			c.environment = &c.project.Environments[i]
+			break
+		}
+	}
+	if c.environment == nil {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "project",
+			Key:        c.Project,
+			ValueLabel: "environment not found",
+			Value:      name,
+		})
+	}
+}
+
+// RunCheck verifies the environment's metadata against the baseline.
+func (c *ProjectCheck) RunCheck() {
+	if c.Publish != "" {
+		out, _ := json.Marshal(c.project)
+		config.PublishData(c.Publish, out)
+	}
+
+	c.checkDeployTarget()
+	c.checkVariables()
+	c.checkBackups()
+	c.checkRoutes()
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// checkDeployTarget verifies the cluster the environment is deployed to.
+func (c *ProjectCheck) checkDeployTarget() {
+	if c.DeployTarget == "" {
+		return
+	}
+	target := c.environment.Openshift.Name
+	if !utils.MatchString(c.DeployTarget, target) {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:      "environment",
+			Key:           c.environment.Name,
+			ValueLabel:    "deploy target",
+			ExpectedValue: c.DeployTarget,
+			Value:         target,
+		})
+		return
+	}
+	c.AddPass(fmt.Sprintf("environment %s is deployed to %s", c.environment.Name, target))
+}
+
+// checkVariables verifies the presence of the project's and environment's
+// variables.
+func (c *ProjectCheck) checkVariables() {
+	if len(c.RequiredVariables) == 0 && len(c.DisallowedVariables) == 0 {
+		return
+	}
+
+	names := []string{}
+	for _, v := range append(append([]Variable{}, c.project.EnvVariables...), c.environment.EnvVariables...) {
+		if !utils.StringSliceContains(names, v.Name) {
+			names = append(names, v.Name)
+		}
+	}
+	sort.Strings(names)
+
+	missing := utils.StringSlicesInterdiffUnique(names, c.RequiredVariables)
+	if len(missing) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "environment",
+			Key:        c.environment.Name,
+			ValueLabel: "missing variables",
+			Values:     missing,
+		})
+	} else if len(c.RequiredVariables) > 0 {
+		c.AddPass("required variables are defined")
+	}
+
+	disallowed := []string{}
+	for _, n := range names {
+		if utils.StringSliceMatchAny(c.DisallowedVariables, n) {
+			disallowed = append(disallowed, n)
+		}
+	}
+	if len(disallowed) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "environment",
+			Key:        c.environment.Name,
+			ValueLabel: "disallowed variables",
+			Values:     disallowed,
+		})
+	} else if len(c.DisallowedVariables) > 0 {
+		c.AddPass("no disallowed variable is defined")
+	}
+}
+
+// checkBackups verifies the age of the environment's latest backup.
+func (c *ProjectCheck) checkBackups() {
+	if c.BackupMaxAge == "" {
+		return
+	}
+	maxAge, err := utils.ParseDuration(c.BackupMaxAge)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid backup max age",
+			Value:      err.Error(),
+		})
+		return
+	}
+
+	var latest time.Time
+	for _, b := range c.environment.Backups {
+		created, err := utils.ParseTimestamp(b.Created, "")
+		if err == nil && created.After(latest) {
+			latest = created
+		}
+	}
+	if latest.IsZero() {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "environment",
+			Key:        c.environment.Name,
+			ValueLabel: "latest backup",
+			Value:      "none",
+		})
+		return
+	}
+	if age := utils.TimeNow().Sub(latest); age > maxAge {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:      "environment",
+			Key:           c.environment.Name,
+			ValueLabel:    "latest backup",
+			ExpectedValue: "within " + c.BackupMaxAge,
+			Value:         latest.UTC().Format(time.RFC3339),
+		})
+		return
+	}
+	c.AddPass(fmt.Sprintf("latest backup is within %s", c.BackupMaxAge))
+}
+
+// checkRoutes verifies the routes served by the environment.
+func (c *ProjectCheck) checkRoutes() {
+	if len(c.RequiredRoutes) == 0 {
+		return
+	}
+
+	routes := []string{}
+	for _, r := range strings.Split(c.environment.Routes, ",") {
+		if r = strings.TrimSuffix(strings.TrimSpace(r), "/"); r != "" {
+			routes = append(routes, r)
+		}
+	}
+
+	missing := []string{}
+	for _, r := range c.RequiredRoutes {
+		if !utils.StringSliceContains(routes, strings.TrimSuffix(r, "/")) {
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "environment",
+			Key:        c.environment.Name,
+			ValueLabel: "missing routes",
+			Values:     missing,
+		})
+		return
+	}
+	c.AddPass("required routes are served")
+}
+
+// RequiresNetwork implements config.NetworkRequirer.
+func (c *ProjectCheck) RequiresNetwork() bool {
+	return true
+}
@@ -0,0 +1,344 @@
+package lagoon_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/lagoon"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/lagoon"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Project]()
+	assert.Equal(t, "*lagoon.ProjectCheck", reflect.TypeOf(c).String())
+}
+
+func TestProjectCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("LAGOON_PROJECT", "acme-website")
+	t.Setenv("LAGOON_ENVIRONMENT", "develop")
+	c := ProjectCheck{}
+	c.Init(Project)
+	assert.Equal("acme-website", c.Project)
+	assert.Equal("develop", c.Environment)
+
+	c = ProjectCheck{Project: "acme-intranet", Environment: "main"}
+	c.Init(Project)
+	assert.Equal("acme-intranet", c.Project)
+	assert.Equal("main", c.Environment)
+}
+
+func TestProjectCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ProjectCheck{
+		Project:           "acme-website",
+		DeployTarget:      "prod-au",
+		RequiredVariables: []string{"SMTP_PASSWORD"},
+	}
+	err := c.Merge(&ProjectCheck{
+		Environment:       "main",
+		BackupMaxAge:      "1d",
+		RequiredVariables: []string{"NEWRELIC_ENABLED"},
+		Publish:           "lagoon",
+	})
+	assert.NoError(err)
+	assert.Equal("acme-website", c.Project)
+	assert.Equal("main", c.Environment)
+	assert.Equal("prod-au", c.DeployTarget)
+	assert.Equal("1d", c.BackupMaxAge)
+	assert.Equal([]string{"NEWRELIC_ENABLED"}, c.RequiredVariables)
+	assert.Equal([]string{"lagoon"}, c.PublishesData())
+}
+
+// newTestServer serves the testdata for the acme-website project, and null
+// for any other project.
+func newTestServer(t *testing.T) *httptest.Server {
+	data, _ := os.ReadFile("testdata/project.json")
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("Unauthorized"))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		req := struct {
+			Variables map[string]string `json:"variables"`
+		}{}
+		json.Unmarshal(body, &req)
+		switch req.Variables["name"] {
+		case "acme-website":
+			w.Write(data)
+		case "acme-broken":
+			w.Write([]byte(`{"data": null, "errors": [{"message": "Unauthorized: You don't have permission"}]}`))
+		default:
+			w.Write([]byte(`{"data": {"projectByName": null}}`))
+		}
+	}))
+}
+
+func TestProjectCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	curApiBaseUrl, curApiToken := lagoon.ApiBaseUrl, lagoon.ApiToken
+	defer func() { lagoon.ApiBaseUrl, lagoon.ApiToken = curApiBaseUrl, curApiToken }()
+	lagoon.ApiBaseUrl, lagoon.ApiToken = "", "secret"
+
+	c := ProjectCheck{}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		Value:      "no project provided",
+	}}, c.Result.Breaches)
+
+	c = ProjectCheck{Project: "acme-website"}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		Value:      "no api url provided",
+	}}, c.Result.Breaches)
+
+	c = ProjectCheck{Project: "acme-broken", ApiUrl: srv.URL}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "unable to fetch project acme-broken",
+		Value:      "Unauthorized: You don't have permission",
+	}}, c.Result.Breaches)
+
+	lagoon.ApiBaseUrl, lagoon.ApiToken = srv.URL, "invalid"
+	c = ProjectCheck{Project: "acme-website"}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "unable to fetch project acme-website",
+		Value:      "Unauthorized (401)",
+	}}, c.Result.Breaches)
+
+	// The api url defaults to the lagoon-api-base-url.
+	lagoon.ApiToken = "secret"
+	c = ProjectCheck{Project: "acme-website"}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Contains(c.DataMap, "project")
+}
+
+func TestProjectCheckUnmarshalDataMap(t *testing.T) {
+	assert := assert.New(t)
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	curApiToken := lagoon.ApiToken
+	defer func() { lagoon.ApiToken = curApiToken }()
+	lagoon.ApiToken = "secret"
+
+	c := ProjectCheck{Project: "acme-missing", ApiUrl: srv.URL}
+	c.FetchData()
+	c.UnmarshalDataMap()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "project not found or not accessible",
+		Value:      "acme-missing",
+	}}, c.Result.Breaches)
+
+	c = ProjectCheck{Project: "acme-website", Environment: "feature-x", ApiUrl: srv.URL}
+	c.FetchData()
+	c.UnmarshalDataMap()
+	assert.EqualValues([]result.Breach{&result.KeyValueBreach{
+		BreachType: "key-value",
+		KeyLabel:   "project",
+		Key:        "acme-website",
+		ValueLabel: "environment not found",
+		Value:      "feature-x",
+	}}, c.Result.Breaches)
+}
+
+func TestProjectCheckRunCheck(t *testing.T) {
+	assert := assert.New(t)
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	curApiToken := lagoon.ApiToken
+	defer func() { lagoon.ApiToken = curApiToken }()
+	lagoon.ApiToken = "secret"
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	t.Setenv("LAGOON_ENVIRONMENT", "")
+
+	tt := []struct {
+		name         string
+		check        ProjectCheck
+		expectStatus result.Status
+		expectPasses []string
+		expectFails  []result.Breach
+	}{
+		{
+			name: "baselineMet",
+			check: ProjectCheck{
+				DeployTarget:        "glob:prod-*",
+				RequiredVariables:   []string{"SMTP_PASSWORD", "NEWRELIC_ENABLED"},
+				DisallowedVariables: []string{"glob:*DEBUG*"},
+				BackupMaxAge:        "2d",
+				RequiredRoutes:      []string{"https://www.acme.com.au", "https://acme.com.au"},
+			},
+			expectStatus: result.Pass,
+			expectPasses: []string{
+				"environment main is deployed to prod-au",
+				"required variables are defined",
+				"no disallowed variable is defined",
+				"latest backup is within 2d",
+				"required routes are served",
+			},
+		},
+		{
+			name: "baselineNotMet",
+			check: ProjectCheck{
+				Environment:         "develop",
+				DeployTarget:        "glob:prod-*",
+				RequiredVariables:   []string{"SMTP_PASSWORD", "NEWRELIC_ENABLED"},
+				DisallowedVariables: []string{"glob:*DEBUG*"},
+				BackupMaxAge:        "2d",
+				RequiredRoutes:      []string{"https://develop.acme.com.au"},
+			},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "lagoon-project",
+					Severity:      "normal",
+					KeyLabel:      "environment",
+					Key:           "develop",
+					ValueLabel:    "deploy target",
+					ExpectedValue: "glob:prod-*",
+					Value:         "nonprod-au",
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "lagoon-project",
+					Severity:   "normal",
+					KeyLabel:   "environment",
+					Key:        "develop",
+					ValueLabel: "missing variables",
+					Values:     []string{"NEWRELIC_ENABLED"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "lagoon-project",
+					Severity:   "normal",
+					KeyLabel:   "environment",
+					Key:        "develop",
+					ValueLabel: "disallowed variables",
+					Values:     []string{"DRUPAL_DEBUG"},
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "lagoon-project",
+					Severity:   "normal",
+					KeyLabel:   "environment",
+					Key:        "develop",
+					ValueLabel: "latest backup",
+					Value:      "none",
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "lagoon-project",
+					Severity:   "normal",
+					KeyLabel:   "environment",
+					Key:        "develop",
+					ValueLabel: "missing routes",
+					Values:     []string{"https://develop.acme.com.au"},
+				},
+			},
+		},
+		{
+			name:         "staleBackup",
+			check:        ProjectCheck{BackupMaxAge: "12h"},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "lagoon-project",
+					Severity:      "normal",
+					KeyLabel:      "environment",
+					Key:           "main",
+					ValueLabel:    "latest backup",
+					ExpectedValue: "within 12h",
+					Value:         "2026-10-15T01:00:00Z",
+				},
+			},
+		},
+		{
+			name:         "invalidBackupMaxAge",
+			check:        ProjectCheck{BackupMaxAge: "a day"},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.ValueBreach{
+					BreachType: "value",
+					CheckType:  "lagoon-project",
+					Severity:   "normal",
+					ValueLabel: "invalid backup max age",
+					Value:      `time: invalid duration "a day"`,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.check
+			c.Project = "acme-website"
+			c.ApiUrl = srv.URL
+			c.Init(Project)
+			c.FetchData()
+			assert.Empty(c.Result.Breaches)
+			c.UnmarshalDataMap()
+			assert.Empty(c.Result.Breaches)
+			c.RunCheck()
+			c.Result.DetermineResultStatus(false)
+			assert.Equal(tc.expectStatus, c.Result.Status)
+			assert.ElementsMatch(tc.expectPasses, c.Result.Passes)
+			assert.ElementsMatch(tc.expectFails, c.Result.Breaches)
+		})
+	}
+}
+
+func TestProjectCheckPublish(t *testing.T) {
+	assert := assert.New(t)
+	srv := newTestServer(t)
+	defer srv.Close()
+	defer config.ResetPublishedData()
+
+	curApiToken := lagoon.ApiToken
+	defer func() { lagoon.ApiToken = curApiToken }()
+	lagoon.ApiToken = "secret"
+
+	c := ProjectCheck{Project: "acme-website", ApiUrl: srv.URL, Publish: "lagoon"}
+	c.Init(Project)
+	c.FetchData()
+	c.UnmarshalDataMap()
+	c.RunCheck()
+
+	data, ok := config.GetPublishedData("lagoon")
+	assert.True(ok)
+	project := ProjectMetadata{}
+	assert.NoError(json.Unmarshal(data, &project))
+	assert.Equal("main", project.ProductionEnvironment)
+	assert.Len(project.Environments, 2)
+	assert.Equal("prod-au", project.Environments[0].Openshift.Name)
+}
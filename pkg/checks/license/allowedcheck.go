@@ -0,0 +1,285 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/sbom"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Allowed config.CheckType = "license:allowed"
+
+// Sources is the list of supported package sources, along with their
+// default file.
+var Sources = map[string]string{
+	"composer": "composer.lock",
+	"npm":      "package-lock.json",
+	"sbom":     "bom.json",
+}
+
+// Package is a dependency along with its license expression.
+type Package struct {
+	Name    string
+	Version string
+	License string
+}
+
+// AllowedCheck verifies the licenses of the packages listed in a lock file
+// or SBOM against a policy.
+type AllowedCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Where the packages are read from: composer, npm or sbom.
+	Source string `yaml:"source"`
+	// Path to the file listing the packages, relative to the project
+	// directory; defaults to the source's file, e.g, composer.lock.
+	File string `yaml:"file"`
+	// Licenses allowed; any license is allowed if empty.
+	Allowed []string `yaml:"allowed"`
+	// Licenses not allowed.
+	Disallowed []string `yaml:"disallowed"`
+	// Do not fail on packages without a license.
+	AllowUnknown *bool `yaml:"allow-unknown"`
+	// Packages to skip, e.g, internal ones.
+	Ignore   []string `yaml:"ignore"`
+	packages []Package
+}
+
+// Init implementation for the license check.
+func (c *AllowedCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.File == "" {
+		c.File = Sources[c.Source]
+	}
+}
+
+// Merge implementation for license check.
+func (c *AllowedCheck) Merge(mergeCheck config.Check) error {
+	licenseMergeCheck := mergeCheck.(*AllowedCheck)
+	if err := c.CheckBase.Merge(&licenseMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Source, licenseMergeCheck.Source)
+	utils.MergeString(&c.File, licenseMergeCheck.File)
+	utils.MergeStringSlice(&c.Allowed, licenseMergeCheck.Allowed)
+	utils.MergeStringSlice(&c.Disallowed, licenseMergeCheck.Disallowed)
+	if licenseMergeCheck.AllowUnknown != nil {
+		c.AllowUnknown = licenseMergeCheck.AllowUnknown
+	}
+	utils.MergeStringSlice(&c.Ignore, licenseMergeCheck.Ignore)
+	return nil
+}
+
+// FetchData reads the file listing the packages.
+func (c *AllowedCheck) FetchData() {
+	if _, ok := Sources[c.Source]; !ok {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unsupported source",
+			Value:      c.Source})
+		return
+	}
+
+	var err error
+	c.DataMap = map[string][]byte{}
+	c.DataMap[c.File], err = os.ReadFile(filepath.Join(config.ProjectDir, c.File))
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error reading file",
+			Value:      err.Error()})
+	}
+}
+
+// UnmarshalDataMap parses the packages from the file.
+func (c *AllowedCheck) UnmarshalDataMap() {
+	var err error
+	data := c.DataMap[c.File]
+	switch c.Source {
+	case "composer":
+		c.packages, err = ParseComposerLock(data)
+	case "npm":
+		c.packages, err = ParseNpmLock(data)
+	case "sbom":
+		var bom sbom.Bom
+		bom, err = sbom.ParseSbom(data)
+		for _, comp := range bom.Components {
+			c.packages = append(c.packages, Package{
+				Name: comp.Name, Version: comp.Version, License: comp.License})
+		}
+	}
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error parsing " + c.File,
+			Value:      err.Error()})
+	}
+}
+
+// RunCheck verifies the license of each package satisfies the policy,
+// grouping the packages breaching by license.
+func (c *AllowedCheck) RunCheck() {
+	allowUnknown := c.AllowUnknown != nil && *c.AllowUnknown
+	disallowed := map[string][]string{}
+	unknown := []string{}
+	checked := 0
+	for _, p := range c.packages {
+		if utils.StringSliceMatchAny(c.Ignore, p.Name) {
+			continue
+		}
+		checked++
+
+		pkg := p.Name
+		if p.Version != "" {
+			pkg += "@" + p.Version
+		}
+		if p.License == "" {
+			if !allowUnknown {
+				unknown = append(unknown, pkg)
+			}
+			continue
+		}
+		ok, err := EvaluateExpression(p.License, c.permitted)
+		if err != nil {
+			if !allowUnknown {
+				unknown = append(unknown, fmt.Sprintf("%s (%s)", pkg, p.License))
+			}
+			continue
+		}
+		if !ok {
+			disallowed[p.License] = append(disallowed[p.License], pkg)
+		}
+	}
+
+	licenses := []string{}
+	for l := range disallowed {
+		licenses = append(licenses, l)
+	}
+	sort.Strings(licenses)
+	for _, l := range licenses {
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "license",
+			Key:        l,
+			ValueLabel: "packages",
+			Values:     disallowed[l],
+		})
+	}
+	if len(unknown) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "license",
+			Key:        "unknown",
+			ValueLabel: "packages",
+			Values:     unknown,
+		})
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.AddPass(fmt.Sprintf("all %d packages have allowed licenses", checked))
+		c.Result.Status = result.Pass
+	}
+}
+
+// permitted determines whether a license is allowed by the policy.
+func (c *AllowedCheck) permitted(id string) bool {
+	if utils.StringSliceMatchAny(c.Disallowed, id) {
+		return false
+	}
+	return len(c.Allowed) == 0 || utils.StringSliceMatchAny(c.Allowed, id)
+}
+
+// ParseComposerLock parses the packages from a composer.lock file; multiple
+// licenses mean the package is available under any of them.
+func ParseComposerLock(data []byte) ([]Package, error) {
+	var lock struct {
+		Packages    []composerPackage `json:"packages"`
+		PackagesDev []composerPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	packages := []Package{}
+	for _, p := range append(lock.Packages, lock.PackagesDev...) {
+		licenses := []string{}
+		for _, l := range p.License {
+			if strings.Contains(l, " ") {
+				l = "(" + l + ")"
+			}
+			licenses = append(licenses, l)
+		}
+		packages = append(packages, Package{
+			Name:    p.Name,
+			Version: p.Version,
+			License: strings.Join(licenses, " OR "),
+		})
+	}
+	return packages, nil
+}
+
+type composerPackage struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	License []string `json:"license"`
+}
+
+// ParseNpmLock parses the packages from a package-lock.json file, using the
+// packages section of lockfile version 2 and above.
+func ParseNpmLock(data []byte) ([]Package, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			License any    `json:"license"`
+			Link    bool   `json:"link"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for path := range lock.Packages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	packages := []Package{}
+	for _, path := range paths {
+		p := lock.Packages[path]
+		// The root package & links to local packages are skipped.
+		if path == "" || p.Link {
+			continue
+		}
+		name := p.Name
+		if name == "" {
+			name = path
+			if i := strings.LastIndex(path, "node_modules/"); i >= 0 {
+				name = path[i+len("node_modules/"):]
+			}
+		}
+		packages = append(packages, Package{
+			Name:    name,
+			Version: p.Version,
+			License: npmLicense(p.License),
+		})
+	}
+	return packages, nil
+}
+
+// npmLicense returns the license expression, supporting the deprecated
+// object form, e.g, {"type": "MIT"}.
+func npmLicense(l any) string {
+	switch v := l.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if t, ok := v["type"].(string); ok {
+			return t
+		}
+	}
+	return ""
+}
@@ -0,0 +1,209 @@
+package license_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/license"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Allowed]()
+	assert.Equal(t, "*license.AllowedCheck", reflect.TypeOf(c).String())
+}
+
+func TestAllowedCheckInit(t *testing.T) {
+	c := AllowedCheck{Source: "npm"}
+	c.Init(Allowed)
+	assert.Equal(t, "package-lock.json", c.File)
+}
+
+func TestAllowedCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	allowUnknown := true
+	c := AllowedCheck{Source: "composer", Allowed: []string{"MIT"}, AllowUnknown: &allowUnknown}
+	err := c.Merge(&AllowedCheck{Disallowed: []string{"glob:AGPL-*"}})
+	assert.NoError(err)
+	assert.Equal("composer", c.Source)
+	assert.Equal([]string{"MIT"}, c.Allowed)
+	assert.Equal([]string{"glob:AGPL-*"}, c.Disallowed)
+	assert.True(*c.AllowUnknown)
+}
+
+func TestParseComposerLock(t *testing.T) {
+	packages, err := ParseComposerLock([]byte(`{"packages": [
+		{"name": "a/a", "version": "1.0.0", "license": ["MIT", "GPL-2.0-only"]},
+		{"name": "b/b", "version": "1.0.0", "license": ["LGPL-2.1-only OR GPL-3.0-or-later"]}
+	]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Name: "a/a", Version: "1.0.0", License: "MIT OR GPL-2.0-only"},
+		{Name: "b/b", Version: "1.0.0", License: "(LGPL-2.1-only OR GPL-3.0-or-later)"},
+	}, packages)
+}
+
+func TestParseNpmLock(t *testing.T) {
+	packages, err := ParseNpmLock([]byte(`{"packages": {
+		"": {"name": "app", "license": "UNLICENSED"},
+		"node_modules/@babel/core": {"version": "7.23.0", "license": "MIT"},
+		"node_modules/a/node_modules/legacy": {"version": "0.1.0", "license": {"type": "GPL-3.0"}},
+		"packages/shared": {"name": "@app/shared", "version": "1.0.0", "license": "MIT"},
+		"node_modules/@app/shared": {"resolved": "packages/shared", "link": true}
+	}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Name: "@babel/core", Version: "7.23.0", License: "MIT"},
+		{Name: "legacy", Version: "0.1.0", License: "GPL-3.0"},
+		{Name: "@app/shared", Version: "1.0.0", License: "MIT"},
+	}, packages)
+}
+
+func TestAllowedCheckFetchData(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	tt := []internal.FetchDataTest{
+		{
+			Name:  "unsupportedSource",
+			Check: &AllowedCheck{Source: "pip"},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "license:allowed",
+				Severity:   "normal",
+				ValueLabel: "unsupported source",
+				Value:      "pip",
+			}},
+		},
+		{
+			Name:  "missingFile",
+			Check: &AllowedCheck{Source: "sbom", File: "missing.json"},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "license:allowed",
+				Severity:   "normal",
+				ValueLabel: "error reading file",
+				Value:      "open testdata/missing.json: no such file or directory",
+			}},
+			ExpectDataMap: map[string][]byte{"missing.json": nil},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Allowed)
+			internal.TestFetchData(t, tc)
+		})
+	}
+}
+
+func TestAllowedCheckRunCheck(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	allowUnknown := true
+	tt := []internal.RunCheckTest{
+		{
+			Name: "composer",
+			Check: &AllowedCheck{
+				Source:  "composer",
+				Allowed: []string{"MIT", "glob:GPL-2.0-*"},
+				Ignore:  []string{"re:^drupal/"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.KeyValuesBreach{
+				BreachType: "key-values",
+				CheckType:  "license:allowed",
+				Severity:   "normal",
+				KeyLabel:   "license",
+				Key:        "unknown",
+				ValueLabel: "packages",
+				Values:     []string{"acme/internal@1.0.0", "vendor/nolicense@0.1.0"},
+			}},
+		},
+		{
+			Name: "npm",
+			Check: &AllowedCheck{
+				Source:     "npm",
+				Disallowed: []string{"glob:GPL-*"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "license:allowed",
+					Severity:   "normal",
+					KeyLabel:   "license",
+					Key:        "GPL-3.0",
+					ValueLabel: "packages",
+					Values:     []string{"legacy@0.1.0"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "license:allowed",
+					Severity:   "normal",
+					KeyLabel:   "license",
+					Key:        "unknown",
+					ValueLabel: "packages",
+					Values:     []string{"broken@1.0.0 (MIT OR)"},
+				},
+			},
+		},
+		{
+			Name: "sbom",
+			Check: &AllowedCheck{
+				Source:       "sbom",
+				Allowed:      []string{"MIT", "Apache-2.0"},
+				AllowUnknown: &allowUnknown,
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "license:allowed",
+					Severity:   "normal",
+					KeyLabel:   "license",
+					Key:        "GPL-3.0-or-later WITH GCC-exception-3.1",
+					ValueLabel: "packages",
+					Values:     []string{"readline@8.2"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "license:allowed",
+					Severity:   "normal",
+					KeyLabel:   "license",
+					Key:        "MIT AND X11-style",
+					ValueLabel: "packages",
+					Values:     []string{"ncurses@6.4"},
+				},
+			},
+		},
+		{
+			Name: "allowed",
+			Check: &AllowedCheck{
+				Source:       "composer",
+				Disallowed:   []string{"glob:AGPL-*"},
+				AllowUnknown: &allowUnknown,
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"all 6 packages have allowed licenses"},
+			ExpectNoFail: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Allowed)
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
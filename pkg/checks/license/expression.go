@@ -0,0 +1,102 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EvaluateExpression evaluates an SPDX license expression, e.g,
+// (MIT OR Apache-2.0) AND BSD-3-Clause, determining whether it is satisfied
+// by the permitted licenses; AND takes precedence over OR and exceptions,
+// e.g, WITH Classpath-exception-2.0, are ignored.
+func EvaluateExpression(expression string, permitted func(id string) bool) (bool, error) {
+	r := strings.NewReplacer("(", " ( ", ")", " ) ")
+	p := &expressionParser{tokens: strings.Fields(r.Replace(expression)), permitted: permitted}
+	if len(p.tokens) == 0 {
+		return false, fmt.Errorf("empty license expression")
+	}
+	ok, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("unexpected '%s' in license expression", p.tokens[p.pos])
+	}
+	return ok, nil
+}
+
+type expressionParser struct {
+	tokens    []string
+	pos       int
+	permitted func(string) bool
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return strings.ToUpper(p.tokens[p.pos])
+}
+
+func (p *expressionParser) parseOr() (bool, error) {
+	ok, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == "OR" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		ok = ok || right
+	}
+	return ok, nil
+}
+
+func (p *expressionParser) parseAnd() (bool, error) {
+	ok, err := p.parseLicense()
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == "AND" {
+		p.pos++
+		right, err := p.parseLicense()
+		if err != nil {
+			return false, err
+		}
+		ok = ok && right
+	}
+	return ok, nil
+}
+
+func (p *expressionParser) parseLicense() (bool, error) {
+	switch p.peek() {
+	case "":
+		return false, fmt.Errorf("unexpected end of license expression")
+	case "AND", "OR", "WITH", ")":
+		return false, fmt.Errorf("unexpected '%s' in license expression", p.tokens[p.pos])
+	case "(":
+		p.pos++
+		ok, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if p.peek() != ")" {
+			return false, fmt.Errorf("missing ')' in license expression")
+		}
+		p.pos++
+		return ok, nil
+	}
+
+	id := p.tokens[p.pos]
+	p.pos++
+	if p.peek() == "WITH" {
+		p.pos++
+		if p.peek() == "" {
+			return false, fmt.Errorf("missing exception in license expression")
+		}
+		p.pos++
+	}
+	return p.permitted(id), nil
+}
@@ -0,0 +1,48 @@
+package license_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/license"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	permitted := func(id string) bool { return id == "MIT" || id == "Apache-2.0" }
+
+	tt := []struct {
+		expression string
+		expected   bool
+		expectErr  string
+	}{
+		{expression: "MIT", expected: true},
+		{expression: "GPL-3.0-only", expected: false},
+		{expression: "MIT OR GPL-3.0-only", expected: true},
+		{expression: "MIT AND GPL-3.0-only", expected: false},
+		{expression: "mit and Apache-2.0", expected: false},
+		{expression: "MIT and Apache-2.0", expected: true},
+		// AND takes precedence over OR.
+		{expression: "GPL-3.0-only OR MIT AND Apache-2.0", expected: true},
+		{expression: "(GPL-3.0-only OR MIT) AND BSD-3-Clause", expected: false},
+		{expression: "(GPL-3.0-only OR MIT) AND (Apache-2.0 OR BSD-3-Clause)", expected: true},
+		{expression: "Apache-2.0 WITH LLVM-exception", expected: true},
+		{expression: "", expectErr: "empty license expression"},
+		{expression: "MIT OR", expectErr: "unexpected end of license expression"},
+		{expression: "MIT MIT", expectErr: "unexpected 'MIT' in license expression"},
+		{expression: "(MIT", expectErr: "missing ')' in license expression"},
+		{expression: "MIT)", expectErr: "unexpected ')' in license expression"},
+		{expression: "AND MIT", expectErr: "unexpected 'AND' in license expression"},
+		{expression: "MIT WITH", expectErr: "missing exception in license expression"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.expression, func(t *testing.T) {
+			ok, err := EvaluateExpression(tc.expression, permitted)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ok)
+		})
+	}
+}
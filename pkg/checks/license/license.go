@@ -0,0 +1,17 @@
+// Package license provides checks for the licenses of a project's
+// dependencies.
+package license
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=license
+
+func RegisterChecks() {
+	config.ChecksRegistry[Allowed] = func() config.Check { return &AllowedCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
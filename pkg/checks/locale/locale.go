@@ -0,0 +1,89 @@
+// Package locale provides checks against the timezone & locale configured for
+// the OS, PHP and Drupal.
+package locale
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=locale
+
+func RegisterChecks() {
+	config.ChecksRegistry[Locale] = func() config.Check { return &LocaleCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// Files from which the OS timezone is determined when TZ is not set.
+var (
+	TimezoneFile  = "/etc/timezone"
+	LocaltimeFile = "/etc/localtime"
+)
+
+// Settings holds the timezone & locale of a source.
+type Settings struct {
+	Timezone string
+	Locale   string
+}
+
+// OsSettings determines the OS timezone, from the TZ variable, the timezone
+// file or the zoneinfo file the localtime is linked to, and the locale from
+// the LC_ALL or LANG variables.
+func OsSettings() Settings {
+	s := Settings{Locale: os.Getenv("LC_ALL")}
+	if s.Locale == "" {
+		s.Locale = os.Getenv("LANG")
+	}
+
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" {
+		s.Timezone = tz
+	} else if data, err := os.ReadFile(TimezoneFile); err == nil {
+		s.Timezone = strings.TrimSpace(string(data))
+	} else if target, err := filepath.EvalSymlinks(LocaltimeFile); err == nil {
+		if _, tz, found := strings.Cut(target, "zoneinfo/"); found {
+			s.Timezone = tz
+		}
+	}
+	return s
+}
+
+// ParsePhpInfo extracts the timezone & locale from the `php -i` output, using
+// the local values of the date.timezone and intl.default_locale directives.
+func ParsePhpInfo(data []byte) Settings {
+	s := Settings{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), " => ")
+		if len(parts) < 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		if value == "no value" {
+			value = ""
+		}
+		switch strings.TrimSpace(parts[0]) {
+		case "date.timezone":
+			s.Timezone = value
+		case "intl.default_locale":
+			s.Locale = value
+		}
+	}
+	return s
+}
+
+// EqualLocales compares two locales, ignoring the case and the differences
+// in the codeset notation, e.g, en_AU.UTF-8 & en_AU.utf8.
+func EqualLocales(a string, b string) bool {
+	normalise := func(l string) string {
+		return strings.ReplaceAll(strings.ToLower(l), "-", "")
+	}
+	return normalise(a) == normalise(b)
+}
@@ -0,0 +1,47 @@
+package locale_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/locale"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOsSettings(t *testing.T) {
+	assert := assert.New(t)
+
+	curTimezoneFile, curLocaltimeFile := TimezoneFile, LocaltimeFile
+	defer func() { TimezoneFile, LocaltimeFile = curTimezoneFile, curLocaltimeFile }()
+	TimezoneFile = "testdata/timezone"
+	LocaltimeFile = "testdata/localtime"
+
+	t.Setenv("TZ", ":UTC")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LANG", "en_AU.UTF-8")
+	assert.Equal(Settings{Timezone: "UTC", Locale: "en_AU.UTF-8"}, OsSettings())
+
+	t.Setenv("TZ", "")
+	t.Setenv("LC_ALL", "C.UTF-8")
+	assert.Equal(Settings{Timezone: "Australia/Sydney", Locale: "C.UTF-8"}, OsSettings())
+
+	TimezoneFile = "testdata/non-existent"
+	assert.Equal("Europe/Paris", OsSettings().Timezone)
+
+	LocaltimeFile = "testdata/non-existent"
+	assert.Equal("", OsSettings().Timezone)
+}
+
+func TestParsePhpInfo(t *testing.T) {
+	data, _ := os.ReadFile("testdata/phpinfo.txt")
+	assert.Equal(t, Settings{Timezone: "Australia/Sydney"}, ParsePhpInfo(data))
+	assert.Equal(t, Settings{Locale: "en_AU"}, ParsePhpInfo([]byte(
+		"date.timezone => no value => no value\nintl.default_locale => en_AU => no value\n")))
+}
+
+func TestEqualLocales(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(EqualLocales("en_AU.UTF-8", "en_AU.utf8"))
+	assert.True(EqualLocales("en_AU", "EN_au"))
+	assert.False(EqualLocales("en_AU.UTF-8", "en_US.UTF-8"))
+}
@@ -0,0 +1,173 @@
+package locale
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Locale config.CheckType = "locale"
+
+// LocaleCheck verifies that the timezone & locale configured for each of the
+// sources match the expected values.
+type LocaleCheck struct {
+	config.CheckBase    `yaml:",inline"`
+	drupal.DrushCommand `yaml:",inline"`
+	// Expected timezone, e.g, Australia/Sydney.
+	Timezone string `yaml:"timezone"`
+	// Expected locale, e.g, en_AU.UTF-8.
+	Locale string `yaml:"locale"`
+	// Sources to verify; any of os, php & drupal.
+	Sources []string `yaml:"sources"`
+	// Path to the php binary.
+	PhpPath string `yaml:"php-path"`
+	// Settings found, keyed by source.
+	settings map[string]Settings
+}
+
+// Init implementation for the locale check.
+func (c *LocaleCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if len(c.Sources) == 0 {
+		c.Sources = []string{"os", "php"}
+	}
+	if c.PhpPath == "" {
+		c.PhpPath = "php"
+	}
+	if utils.StringSliceContains(c.Sources, "drupal") {
+		c.RequiresDb = true
+	}
+}
+
+// Merge implementation for locale check.
+func (c *LocaleCheck) Merge(mergeCheck config.Check) error {
+	localeMergeCheck := mergeCheck.(*LocaleCheck)
+	if err := c.CheckBase.Merge(&localeMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.DrushCommand.Merge(localeMergeCheck.DrushCommand)
+	utils.MergeString(&c.Timezone, localeMergeCheck.Timezone)
+	utils.MergeString(&c.Locale, localeMergeCheck.Locale)
+	utils.MergeStringSlice(&c.Sources, localeMergeCheck.Sources)
+	utils.MergeString(&c.PhpPath, localeMergeCheck.PhpPath)
+	return nil
+}
+
+var drupalTimezoneArgs = []string{"config:get", "system.date", "timezone.default", "--format=json"}
+
+// FetchData determines the OS settings and runs the php & drush commands for
+// the other sources.
+func (c *LocaleCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	c.settings = map[string]Settings{}
+	for _, source := range c.Sources {
+		switch source {
+		case "os":
+			c.settings[source] = OsSettings()
+		case "php":
+			c.DataMap[source], err = command.ShellCommander(c.PhpPath, "-i").Output()
+		case "drupal":
+			// Command: drush config:get system.date timezone.default --format=json
+			c.DataMap[source], err = drupal.Drush(c.DrushPath, c.Alias, drupalTimezoneArgs).Exec()
+		default:
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unknown source",
+				Value:      source})
+			continue
+		}
+		if err != nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "source",
+				Key:        source,
+				ValueLabel: "unable to fetch settings",
+				Value:      command.GetMsgFromCommandError(err)})
+		}
+	}
+}
+
+// Commands implements config.CommandReporter.
+func (c *LocaleCheck) Commands() [][]string {
+	cmds := [][]string{}
+	for _, source := range c.Sources {
+		switch source {
+		case "php":
+			cmds = append(cmds, []string{c.PhpPath, "-i"})
+		case "drupal":
+			cmds = append(cmds, drupal.Drush(c.DrushPath, c.Alias, drupalTimezoneArgs).Line())
+		}
+	}
+	return cmds
+}
+
+// UnmarshalDataMap parses the php & drush output into the settings.
+func (c *LocaleCheck) UnmarshalDataMap() {
+	for source, data := range c.DataMap {
+		switch source {
+		case "php":
+			c.settings[source] = ParsePhpInfo(data)
+		case "drupal":
+			tz := map[string]string{}
+			if err := json.Unmarshal(data, &tz); err != nil {
+				c.AddBreach(&result.KeyValueBreach{
+					KeyLabel:   "source",
+					Key:        source,
+					ValueLabel: "unable to parse settings",
+					Value:      err.Error()})
+				continue
+			}
+			c.settings[source] = Settings{Timezone: tz["system.date:timezone.default"]}
+		}
+	}
+}
+
+// RunCheck implements the Check logic for timezone & locale.
+func (c *LocaleCheck) RunCheck() {
+	if c.Timezone == "" && c.Locale == "" {
+		c.AddBreach(&result.ValueBreach{Value: "no timezone or locale provided"})
+		return
+	}
+
+	for _, source := range c.Sources {
+		s, ok := c.settings[source]
+		if !ok {
+			continue
+		}
+
+		if c.Timezone != "" {
+			c.verify(source, "timezone", c.Timezone, s.Timezone, s.Timezone == c.Timezone)
+		}
+		// Drupal has no locale setting.
+		if c.Locale != "" && source != "drupal" {
+			c.verify(source, "locale", c.Locale, s.Locale, EqualLocales(s.Locale, c.Locale))
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// verify adds a pass or breach for a setting of the source.
+func (c *LocaleCheck) verify(source string, setting string, expected string, actual string, equal bool) {
+	if equal {
+		c.AddPass(fmt.Sprintf("[%s] %s is %s", source, setting, actual))
+		return
+	}
+	if actual == "" {
+		actual = "not set"
+	}
+	c.AddBreach(&result.KeyValueBreach{
+		KeyLabel:      "source",
+		Key:           source,
+		ValueLabel:    setting,
+		ExpectedValue: expected,
+		Value:         actual,
+	})
+}
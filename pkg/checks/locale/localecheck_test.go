@@ -0,0 +1,201 @@
+package locale_test
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/locale"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Locale]()
+	assert.Equal(t, "*locale.LocaleCheck", reflect.TypeOf(c).String())
+}
+
+func TestLocaleCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := LocaleCheck{}
+	c.Init(Locale)
+	assert.Equal([]string{"os", "php"}, c.Sources)
+	assert.Equal("php", c.PhpPath)
+	assert.False(c.RequiresDb)
+
+	c = LocaleCheck{Sources: []string{"drupal"}}
+	c.Init(Locale)
+	assert.True(c.RequiresDb)
+}
+
+func TestLocaleCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := LocaleCheck{Timezone: "UTC", Sources: []string{"os"}}
+	err := c.Merge(&LocaleCheck{
+		DrushCommand: drupal.DrushCommand{Alias: "prod"},
+		Timezone:     "Australia/Sydney",
+		Locale:       "en_AU.UTF-8",
+	})
+	assert.NoError(err)
+	assert.Equal("prod", c.Alias)
+	assert.Equal("Australia/Sydney", c.Timezone)
+	assert.Equal("en_AU.UTF-8", c.Locale)
+	assert.Equal([]string{"os"}, c.Sources)
+}
+
+// mockCommands returns the output of the php or drush commands.
+func mockCommands(phpOut string, drushOut string, err error) func(name string, arg ...string) command.IShellCommand {
+	return func(name string, arg ...string) command.IShellCommand {
+		if name == "php" {
+			return internal.ShellCommanderMaker(&phpOut, err, nil)(name, arg...)
+		}
+		return internal.ShellCommanderMaker(&drushOut, err, nil)(name, arg...)
+	}
+}
+
+func TestLocaleCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(nil, nil, &generatedCommand)
+	c := LocaleCheck{Sources: []string{"drupal"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("vendor/drush/drush/drush config:get system.date timezone.default --format=json", generatedCommand)
+
+	command.ShellCommander = mockCommands("", "", &exec.ExitError{Stderr: []byte("php: not found")})
+	c = LocaleCheck{Sources: []string{"php", "windows"}}
+	c.Init(Locale)
+	c.FetchData()
+	assert.EqualValues([]result.Breach{
+		&result.KeyValueBreach{
+			BreachType: "key-value",
+			CheckType:  "locale",
+			Severity:   "normal",
+			KeyLabel:   "source",
+			Key:        "php",
+			ValueLabel: "unable to fetch settings",
+			Value:      "php: not found",
+		},
+		&result.ValueBreach{
+			BreachType: "value",
+			CheckType:  "locale",
+			Severity:   "normal",
+			ValueLabel: "unknown source",
+			Value:      "windows",
+		},
+	}, c.Result.Breaches)
+}
+
+func TestLocaleCheckRunCheck(t *testing.T) {
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+	phpInfo, _ := os.ReadFile("testdata/phpinfo.txt")
+
+	tt := []internal.RunCheckTest{
+		{
+			Name:         "noExpectedValues",
+			Check:        &LocaleCheck{Sources: []string{"os"}},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.ValueBreach{
+					BreachType: "value",
+					CheckType:  "locale",
+					Severity:   "normal",
+					Value:      "no timezone or locale provided",
+				},
+			},
+		},
+		{
+			Name: "allSourcesMatch",
+			Check: &LocaleCheck{
+				Timezone: "Australia/Sydney",
+				Locale:   "en_AU.utf8",
+				Sources:  []string{"os", "drupal"},
+			},
+			PreRun: func(t *testing.T) {
+				t.Setenv("TZ", "Australia/Sydney")
+				t.Setenv("LC_ALL", "en_AU.UTF-8")
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"[os] timezone is Australia/Sydney",
+				"[os] locale is en_AU.UTF-8",
+				"[drupal] timezone is Australia/Sydney",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "mismatch",
+			Check: &LocaleCheck{
+				Timezone: "Australia/Melbourne",
+				Locale:   "en_AU.UTF-8",
+				Sources:  []string{"os", "php"},
+			},
+			PreRun: func(t *testing.T) {
+				t.Setenv("TZ", "UTC")
+				t.Setenv("LC_ALL", "en_AU.UTF-8")
+			},
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{"[os] locale is en_AU.UTF-8"},
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "locale",
+					Severity:      "normal",
+					KeyLabel:      "source",
+					Key:           "os",
+					ValueLabel:    "timezone",
+					ExpectedValue: "Australia/Melbourne",
+					Value:         "UTC",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "locale",
+					Severity:      "normal",
+					KeyLabel:      "source",
+					Key:           "php",
+					ValueLabel:    "timezone",
+					ExpectedValue: "Australia/Melbourne",
+					Value:         "Australia/Sydney",
+				},
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "locale",
+					Severity:      "normal",
+					KeyLabel:      "source",
+					Key:           "php",
+					ValueLabel:    "locale",
+					ExpectedValue: "en_AU.UTF-8",
+					Value:         "not set",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if tc.PreRun != nil {
+				tc.PreRun(t)
+			}
+			command.ShellCommander = mockCommands(string(phpInfo),
+				`{"system.date:timezone.default": "Australia/Sydney"}`, nil)
+			tc.Check.Init(Locale)
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			tc.PreRun = nil
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+// Package manual provides checks for human-verified controls.
+package manual
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=manual
+
+func RegisterChecks() {
+	config.ChecksRegistry[Manual] = func() config.Check { return &ManualCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
@@ -0,0 +1,195 @@
+package manual
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+const Manual config.CheckType = "manual"
+
+const dateLayout = "2006-01-02"
+
+// Attestation records that a control was verified by a person.
+type Attestation struct {
+	// Person who verified the control, e.g, Jane Doe <jane@example.com>.
+	AttestedBy string `yaml:"attested-by"`
+	// When the control was verified.
+	AttestedAt time.Time `yaml:"attested-at"`
+	// When the attestation is no longer valid.
+	Expires time.Time `yaml:"expires"`
+	// Outcome of the verification: pass or fail.
+	Status string `yaml:"status"`
+	Notes  string `yaml:"notes"`
+}
+
+// ManualCheck reports the status of a human-verified control, e.g, a
+// disaster recovery test, from an attestation file.
+type ManualCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Path to the attestation file, relative to the project directory.
+	File string `yaml:"file"`
+	// Maximum age of the attestation, e.g, 90d.
+	MaxAge string `yaml:"max-age"`
+	// Verify the attestation's signature using cosign.
+	VerifySignature *bool `yaml:"verify-signature"`
+	// Path to the signature; defaults to the attestation file with a .sig
+	// extension.
+	Signature string `yaml:"signature"`
+	// Path to the cosign public key.
+	CosignKey string `yaml:"cosign-key"`
+	// Additional arguments passed to cosign verify-blob, e.g, for keyless
+	// verification.
+	CosignArgs  []string `yaml:"cosign-args"`
+	attestation Attestation
+}
+
+// Merge implementation for manual check.
+func (c *ManualCheck) Merge(mergeCheck config.Check) error {
+	manualMergeCheck := mergeCheck.(*ManualCheck)
+	if err := c.CheckBase.Merge(&manualMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.File, manualMergeCheck.File)
+	utils.MergeString(&c.MaxAge, manualMergeCheck.MaxAge)
+	if manualMergeCheck.VerifySignature != nil {
+		c.VerifySignature = manualMergeCheck.VerifySignature
+	}
+	utils.MergeString(&c.Signature, manualMergeCheck.Signature)
+	utils.MergeString(&c.CosignKey, manualMergeCheck.CosignKey)
+	utils.MergeStringSlice(&c.CosignArgs, manualMergeCheck.CosignArgs)
+	return nil
+}
+
+// FetchData reads the attestation file.
+func (c *ManualCheck) FetchData() {
+	if c.File == "" {
+		c.AddBreach(&result.ValueBreach{Value: "no attestation file provided"})
+		return
+	}
+
+	var err error
+	c.DataMap = map[string][]byte{}
+	c.DataMap[c.File], err = os.ReadFile(c.path(c.File))
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error reading attestation",
+			Value:      err.Error()})
+	}
+}
+
+// UnmarshalDataMap parses the attestation.
+func (c *ManualCheck) UnmarshalDataMap() {
+	if err := yaml.Unmarshal(c.DataMap[c.File], &c.attestation); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error parsing attestation",
+			Value:      err.Error()})
+	}
+}
+
+// RunCheck verifies the attestation is valid and the control passed.
+func (c *ManualCheck) RunCheck() {
+	a := c.attestation
+	if a.AttestedBy == "" {
+		c.addAttestationBreach("attested-by", "", "<not set>")
+	}
+	if a.AttestedAt.IsZero() {
+		c.addAttestationBreach("attested-at", "", "<not set>")
+	}
+	if status := strings.ToLower(a.Status); status != "pass" {
+		if status == "" {
+			status = "<not set>"
+		}
+		c.addAttestationBreach("status", "pass", status)
+	}
+
+	now := utils.TimeNow()
+	if !a.Expires.IsZero() && now.After(a.Expires) {
+		c.addAttestationBreach("expires", "", "expired on "+a.Expires.Format(dateLayout))
+	}
+	if c.MaxAge != "" && !a.AttestedAt.IsZero() {
+		maxAge, err := utils.ParseDuration(c.MaxAge)
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "invalid max-age",
+				Value:      err.Error()})
+		} else if now.Sub(a.AttestedAt) > maxAge {
+			c.addAttestationBreach("attested-at", "within "+c.MaxAge, a.AttestedAt.Format(dateLayout))
+		}
+	}
+
+	if c.verify() {
+		if err := c.verifySignature(); err != nil {
+			c.addAttestationBreach("signature", "", err.Error())
+		} else {
+			c.AddPass("attestation signature verified")
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.AddPass(fmt.Sprintf("attested by %s on %s", a.AttestedBy, a.AttestedAt.Format(dateLayout)))
+		c.Result.Status = result.Pass
+	}
+}
+
+func (c *ManualCheck) addAttestationBreach(field string, expected string, value string) {
+	c.AddBreach(&result.KeyValueBreach{
+		KeyLabel:      "attestation",
+		Key:           c.File,
+		ValueLabel:    field,
+		ExpectedValue: expected,
+		Value:         value,
+	})
+}
+
+// verifySignature runs cosign verify-blob against the attestation file.
+func (c *ManualCheck) verifySignature() error {
+	if _, err := command.ShellCommander("cosign", c.cosignArgs()...).Output(); err != nil {
+		return errors.New(command.GetMsgFromCommandError(err))
+	}
+	return nil
+}
+
+// cosignArgs returns the arguments for cosign to verify the attestation.
+func (c *ManualCheck) cosignArgs() []string {
+	signature := c.Signature
+	if signature == "" {
+		signature = c.File + ".sig"
+	}
+	args := []string{"verify-blob", "--signature", c.path(signature)}
+	if c.CosignKey != "" {
+		args = append(args, "--key", c.CosignKey)
+	}
+	args = append(args, c.CosignArgs...)
+	return append(args, c.path(c.File))
+}
+
+// Commands implements config.CommandReporter.
+func (c *ManualCheck) Commands() [][]string {
+	if !c.verify() {
+		return nil
+	}
+	return [][]string{append([]string{"cosign"}, c.cosignArgs()...)}
+}
+
+func (c *ManualCheck) verify() bool {
+	return c.VerifySignature != nil && *c.VerifySignature
+}
+
+func (c *ManualCheck) path(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(config.ProjectDir, p)
+}
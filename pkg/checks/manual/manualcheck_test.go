@@ -0,0 +1,261 @@
+package manual_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/manual"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Manual]()
+	assert.Equal(t, "*manual.ManualCheck", reflect.TypeOf(c).String())
+}
+
+func TestManualCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	verify := true
+	c := ManualCheck{File: "attestations/dr-test.yml", MaxAge: "90d"}
+	err := c.Merge(&ManualCheck{
+		MaxAge:          "100d",
+		VerifySignature: &verify,
+		CosignKey:       "cosign.pub",
+	})
+	assert.NoError(err)
+	assert.Equal("attestations/dr-test.yml", c.File)
+	assert.Equal("100d", c.MaxAge)
+	assert.True(*c.VerifySignature)
+	assert.Equal("cosign.pub", c.CosignKey)
+}
+
+func TestManualCheckFetchData(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	tt := []internal.FetchDataTest{
+		{
+			Name:  "noFile",
+			Check: &ManualCheck{},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "manual",
+				Severity:   "normal",
+				Value:      "no attestation file provided",
+			}},
+		},
+		{
+			Name:  "missingFile",
+			Check: &ManualCheck{File: "missing.yml"},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "manual",
+				Severity:   "normal",
+				ValueLabel: "error reading attestation",
+				Value:      "open testdata/missing.yml: no such file or directory",
+			}},
+		},
+		{
+			Name:  "incomplete",
+			Check: &ManualCheck{File: "incomplete.yml"},
+			ExpectDataMap: map[string][]byte{
+				"incomplete.yml": []byte("notes: Pending.\n"),
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Manual)
+			internal.TestFetchData(t, tc)
+		})
+	}
+}
+
+func TestManualCheckUnmarshalDataMap(t *testing.T) {
+	c := ManualCheck{File: "dr-test.yml"}
+	c.DataMap = map[string][]byte{"dr-test.yml": []byte("attested-at: yesterday")}
+	c.UnmarshalDataMap()
+	assert.EqualValues(t, []result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "error parsing attestation",
+		Value:      "parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\"",
+	}}, c.Result.Breaches)
+}
+
+func TestManualCheckRunCheck(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC) }
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	verify := true
+	tt := []struct {
+		internal.RunCheckTest
+		cosignErr error
+	}{
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "valid",
+				Check:        &ManualCheck{File: "dr-test.yml", MaxAge: "90d"},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{"attested by Jane Doe <jane@example.com> on 2026-09-01"},
+				ExpectNoFail: true,
+			},
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "signed",
+				Check: &ManualCheck{
+					File:            "dr-test.yml",
+					VerifySignature: &verify,
+					CosignKey:       "cosign.pub",
+				},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{
+					"attestation signature verified",
+					"attested by Jane Doe <jane@example.com> on 2026-09-01",
+				},
+				ExpectNoFail: true,
+			},
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "invalidSignature",
+				Check: &ManualCheck{
+					File:            "dr-test.yml",
+					VerifySignature: &verify,
+					CosignKey:       "cosign.pub",
+				},
+				ExpectStatus: result.Fail,
+				ExpectNoPass: true,
+				ExpectFails: []result.Breach{&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "manual",
+					Severity:   "normal",
+					KeyLabel:   "attestation",
+					Key:        "dr-test.yml",
+					ValueLabel: "signature",
+					Value:      "invalid signature",
+				}},
+			},
+			cosignErr: errors.New("invalid signature"),
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "failedAndExpired",
+				Check:        &ManualCheck{File: "failed.yml", MaxAge: "90d"},
+				ExpectStatus: result.Fail,
+				ExpectNoPass: true,
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "manual",
+						Severity:      "normal",
+						KeyLabel:      "attestation",
+						Key:           "failed.yml",
+						ValueLabel:    "status",
+						ExpectedValue: "pass",
+						Value:         "fail",
+					},
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "manual",
+						Severity:   "normal",
+						KeyLabel:   "attestation",
+						Key:        "failed.yml",
+						ValueLabel: "expires",
+						Value:      "expired on 2026-06-01",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "manual",
+						Severity:      "normal",
+						KeyLabel:      "attestation",
+						Key:           "failed.yml",
+						ValueLabel:    "attested-at",
+						ExpectedValue: "within 90d",
+						Value:         "2026-03-01",
+					},
+				},
+			},
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "incomplete",
+				Check:        &ManualCheck{File: "incomplete.yml", MaxAge: "90d"},
+				ExpectStatus: result.Fail,
+				ExpectNoPass: true,
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "manual",
+						Severity:   "normal",
+						KeyLabel:   "attestation",
+						Key:        "incomplete.yml",
+						ValueLabel: "attested-by",
+						Value:      "<not set>",
+					},
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "manual",
+						Severity:   "normal",
+						KeyLabel:   "attestation",
+						Key:        "incomplete.yml",
+						ValueLabel: "attested-at",
+						Value:      "<not set>",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "manual",
+						Severity:      "normal",
+						KeyLabel:      "attestation",
+						Key:           "incomplete.yml",
+						ValueLabel:    "status",
+						ExpectedValue: "pass",
+						Value:         "<not set>",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			command.ShellCommander = internal.ShellCommanderMaker(nil, tc.cosignErr, nil)
+			tc.Check.Init(Manual)
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc.RunCheckTest)
+		})
+	}
+}
+
+func TestManualCheckCommands(t *testing.T) {
+	assert := assert.New(t)
+	config.ProjectDir = "/app"
+	defer func() { config.ProjectDir = "" }()
+
+	verify := true
+	c := ManualCheck{File: "dr-test.yml"}
+	assert.Nil(c.Commands())
+
+	c = ManualCheck{File: "dr-test.yml", VerifySignature: &verify, CosignKey: "cosign.pub"}
+	assert.Equal([][]string{{
+		"cosign", "verify-blob", "--signature", "/app/dr-test.yml.sig",
+		"--key", "cosign.pub", "/app/dr-test.yml",
+	}}, c.Commands())
+}
@@ -0,0 +1,84 @@
+// Package parity provides checks comparing the data collected from
+// different environments, e.g, production & staging, to detect them drifting
+// apart.
+package parity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=parity
+
+func RegisterChecks() {
+	config.ChecksRegistry[Parity] = func() config.Check { return &ParityCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// Flatten reduces a yaml document to its values keyed by path, e.g,
+// `settings.cache.backend` or `servers[1].host`; lists of scalars are kept
+// as a single sorted value so that their order does not matter. A scalar
+// document is keyed by `$`.
+func Flatten(n *yaml.Node) map[string]string {
+	values := map[string]string{}
+	if n.Kind == 0 {
+		return values
+	}
+	if n.Kind == yaml.DocumentNode {
+		if len(n.Content) == 0 {
+			return values
+		}
+		n = n.Content[0]
+	}
+	flatten(n, "", values)
+	return values
+}
+
+func flatten(n *yaml.Node, path string, values map[string]string) {
+	switch n.Kind {
+	case yaml.AliasNode:
+		flatten(n.Alias, path, values)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			flatten(n.Content[i+1], key, values)
+		}
+	case yaml.SequenceNode:
+		scalars := []string{}
+		for _, item := range n.Content {
+			if item.Kind != yaml.ScalarNode {
+				scalars = nil
+				break
+			}
+			scalars = append(scalars, item.Value)
+		}
+		if scalars != nil {
+			sort.Strings(scalars)
+			values[rootPath(path)] = "[" + strings.Join(scalars, ", ") + "]"
+			return
+		}
+		for i, item := range n.Content {
+			flatten(item, fmt.Sprintf("%s[%d]", path, i), values)
+		}
+	default:
+		values[rootPath(path)] = n.Value
+	}
+}
+
+func rootPath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
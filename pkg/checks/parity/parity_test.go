@@ -0,0 +1,55 @@
+package parity_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/parity"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFlatten(t *testing.T) {
+	assert := assert.New(t)
+
+	tt := []struct {
+		name     string
+		data     string
+		expected map[string]string
+	}{
+		{name: "empty", data: "", expected: map[string]string{}},
+		{name: "scalar", data: "10.1.6", expected: map[string]string{"$": "10.1.6"}},
+		{name: "scalarList", data: "[node, devel, admin_toolbar]",
+			expected: map[string]string{"$": "[admin_toolbar, devel, node]"}},
+		{
+			name: "nested",
+			data: `
+settings:
+  cache:
+    backend: redis
+  hash_salt: &salt abc
+  salt_alias: *salt
+modules: [views, node]
+servers:
+  - host: web1
+  - host: web2
+`,
+			expected: map[string]string{
+				"settings.cache.backend": "redis",
+				"settings.hash_salt":     "abc",
+				"settings.salt_alias":    "abc",
+				"modules":                "[node, views]",
+				"servers[0].host":        "web1",
+				"servers[1].host":        "web2",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			n := yaml.Node{}
+			assert.NoError(yaml.Unmarshal([]byte(tc.data), &n))
+			assert.Equal(tc.expected, Flatten(&n))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package parity
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+const Parity config.CheckType = "parity"
+
+// NotSetValue is reported for paths missing from one of the environments.
+const NotSetValue = "<not set>"
+
+// ParityCheck compares the data published by two checks, typically the same
+// check run against different targets, and reports the differences.
+type ParityCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Name of the published data used as the reference, e.g, prod-settings.
+	Source string `yaml:"source"`
+	// Name of the published data compared to the reference, e.g,
+	// staging-settings.
+	Compare string `yaml:"compare"`
+	// Paths allowed to differ, e.g, `trusted_host_patterns`; `re:` & `glob:`
+	// prefixes are supported.
+	Allowed []string `yaml:"allowed"`
+	source  map[string]string
+	compare map[string]string
+}
+
+// Merge implementation for ParityCheck check.
+func (c *ParityCheck) Merge(mergeCheck config.Check) error {
+	parityMergeCheck := mergeCheck.(*ParityCheck)
+	if err := c.CheckBase.Merge(&parityMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Source, parityMergeCheck.Source)
+	utils.MergeString(&c.Compare, parityMergeCheck.Compare)
+	utils.MergeStringSlice(&c.Allowed, parityMergeCheck.Allowed)
+	return nil
+}
+
+// ConsumesData implements config.DataConsumer.
+func (c *ParityCheck) ConsumesData() []string {
+	return []string{c.Source, c.Compare}
+}
+
+// FetchData reads the data published for both environments.
+func (c *ParityCheck) FetchData() {
+	c.DataMap = map[string][]byte{}
+	for _, name := range []string{c.Source, c.Compare} {
+		if name == "" {
+			c.AddBreach(&result.ValueBreach{
+				Value: "both source and compare are required"})
+			return
+		}
+		data, ok := config.GetPublishedData(name)
+		if !ok {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "no published data",
+				Value:      name})
+			continue
+		}
+		c.DataMap[name] = data
+	}
+}
+
+// UnmarshalDataMap flattens the data of both environments.
+func (c *ParityCheck) UnmarshalDataMap() {
+	flattened := map[string]map[string]string{}
+	for _, name := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[name]
+		n := yaml.Node{}
+		if err := yaml.Unmarshal(data, &n); err != nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "data",
+				Key:        name,
+				ValueLabel: "invalid data",
+				Value:      err.Error()})
+			continue
+		}
+		flattened[name] = Flatten(&n)
+	}
+	c.source = flattened[c.Source]
+	c.compare = flattened[c.Compare]
+}
+
+// RunCheck reports the paths whose values differ between the environments,
+// unless they are allowed to.
+func (c *ParityCheck) RunCheck() {
+	paths := []string{}
+	for p := range c.source {
+		paths = append(paths, p)
+	}
+	for p := range c.compare {
+		if _, ok := c.source[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	compared := 0
+	for _, p := range paths {
+		if utils.StringSliceMatchAny(c.Allowed, p) {
+			continue
+		}
+		compared++
+		expected, inSource := c.source[p]
+		actual, inCompare := c.compare[p]
+		if inSource && inCompare && expected == actual {
+			continue
+		}
+		if !inSource {
+			expected = NotSetValue
+		}
+		if !inCompare {
+			actual = NotSetValue
+		}
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:      c.Compare,
+			Key:           p,
+			ValueLabel:    "actual",
+			ExpectedValue: expected,
+			Value:         actual,
+		})
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.AddPass(fmt.Sprintf("%s matches %s for %d values", c.Compare, c.Source, compared))
+		c.Result.Status = result.Pass
+	}
+}
@@ -0,0 +1,185 @@
+package parity_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/parity"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParityCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ParityCheck{Source: "prod", Compare: "staging"}
+	c.Merge(&ParityCheck{Compare: "uat", Allowed: []string{"glob:*.url"}})
+	assert.EqualValues(ParityCheck{
+		Source:  "prod",
+		Compare: "uat",
+		Allowed: []string{"glob:*.url"},
+	}, c)
+}
+
+func TestParityCheckConsumesData(t *testing.T) {
+	c := ParityCheck{Source: "prod", Compare: "staging"}
+	assert.Equal(t, []string{"prod", "staging"}, c.ConsumesData())
+}
+
+func TestParityCheckFetchData(t *testing.T) {
+	config.ResetPublishedData()
+	defer config.ResetPublishedData()
+	config.PublishData("prod", []byte("foo: bar\n"))
+
+	tt := []internal.FetchDataTest{
+		{
+			Name:  "missingCompare",
+			Check: &ParityCheck{Source: "prod"},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "parity",
+				Severity:   "normal",
+				Value:      "both source and compare are required",
+			}},
+			ExpectDataMap: map[string][]byte{"prod": []byte("foo: bar\n")},
+		},
+		{
+			Name:  "notPublished",
+			Check: &ParityCheck{Source: "prod", Compare: "staging"},
+			ExpectBreaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "parity",
+				Severity:   "normal",
+				ValueLabel: "no published data",
+				Value:      "staging",
+			}},
+			ExpectDataMap: map[string][]byte{"prod": []byte("foo: bar\n")},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Parity)
+			internal.TestFetchData(t, tc)
+		})
+	}
+}
+
+func TestParityCheckRunCheck(t *testing.T) {
+	prod := `
+settings:
+  cache: redis
+  base_url: https://example.com
+  error_level: hide
+modules: [node, views]
+`
+
+	tt := []struct {
+		internal.RunCheckTest
+		staging string
+	}{
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "parity",
+				Check:        &ParityCheck{Allowed: []string{"glob:*.base_url"}},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{"staging matches prod for 3 values"},
+				ExpectNoFail: true,
+			},
+			staging: `
+settings:
+  cache: redis
+  base_url: https://staging.example.com
+  error_level: hide
+modules: [views, node]
+`,
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "drift",
+				Check:        &ParityCheck{Allowed: []string{"re:^settings\\.base_url$"}},
+				ExpectStatus: result.Fail,
+				ExpectNoPass: true,
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "parity",
+						Severity:      "normal",
+						KeyLabel:      "staging",
+						Key:           "modules",
+						ValueLabel:    "actual",
+						ExpectedValue: "[node, views]",
+						Value:         "[devel, node, views]",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "parity",
+						Severity:      "normal",
+						KeyLabel:      "staging",
+						Key:           "settings.cache",
+						ValueLabel:    "actual",
+						ExpectedValue: "redis",
+						Value:         "<not set>",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "parity",
+						Severity:      "normal",
+						KeyLabel:      "staging",
+						Key:           "settings.error_level",
+						ValueLabel:    "actual",
+						ExpectedValue: "hide",
+						Value:         "verbose",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "parity",
+						Severity:      "normal",
+						KeyLabel:      "staging",
+						Key:           "settings.stage_file_proxy",
+						ValueLabel:    "actual",
+						ExpectedValue: "<not set>",
+						Value:         "true",
+					},
+				},
+			},
+			staging: `
+settings:
+  base_url: https://staging.example.com
+  error_level: verbose
+  stage_file_proxy: true
+modules: [views, node, devel]
+`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			c := tc.Check.(*ParityCheck)
+			c.Source = "prod"
+			c.Compare = "staging"
+			c.Init(Parity)
+			c.DataMap = map[string][]byte{"prod": []byte(prod), "staging": []byte(tc.staging)}
+			c.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc.RunCheckTest)
+		})
+	}
+}
+
+func TestParityCheckUnmarshalDataMap(t *testing.T) {
+	c := ParityCheck{Source: "prod", Compare: "staging"}
+	c.Init(Parity)
+	c.DataMap = map[string][]byte{"prod": []byte("foo: bar"), "staging": []byte("foo: [bar")}
+	c.UnmarshalDataMap()
+	assert.Equal(t, []result.Breach{&result.KeyValueBreach{
+		BreachType: "key-value",
+		CheckType:  "parity",
+		Severity:   "normal",
+		KeyLabel:   "data",
+		Key:        "staging",
+		ValueLabel: "invalid data",
+		Value:      "yaml: line 1: did not find expected ',' or ']'",
+	}}, c.Result.Breaches)
+}
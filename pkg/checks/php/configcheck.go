@@ -0,0 +1,121 @@
+package php
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const Config config.CheckType = "php-config"
+
+// IniGetAllCode is the code run by php to output the directives as json.
+const IniGetAllCode = "echo json_encode(ini_get_all(null, false));"
+
+// ConfigCheck verifies the PHP configuration directives, either from the php
+// binary or from ini files, using the same values as the yaml check.
+type ConfigCheck struct {
+	yaml.YamlBase `yaml:",inline"`
+	// Path to the php binary.
+	PhpPath string `yaml:"php-path"`
+	// Ini files to parse instead of running php.
+	Files []string `yaml:"files"`
+}
+
+// Init implementation for the php-config check.
+func (c *ConfigCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.PhpPath == "" {
+		c.PhpPath = "php"
+	}
+}
+
+// Merge implementation for php-config check.
+func (c *ConfigCheck) Merge(mergeCheck config.Check) error {
+	configMergeCheck := mergeCheck.(*ConfigCheck)
+	if err := c.YamlBase.Merge(&configMergeCheck.YamlBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.PhpPath, configMergeCheck.PhpPath)
+	utils.MergeStringSlice(&c.Files, configMergeCheck.Files)
+	return nil
+}
+
+// FetchData reads the ini files if provided, otherwise runs php to fetch the
+// directives.
+func (c *ConfigCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	if len(c.Files) > 0 {
+		for _, f := range c.Files {
+			c.DataMap[f], err = os.ReadFile(filepath.Join(config.ProjectDir, f))
+			if err != nil {
+				c.AddBreach(&result.ValueBreach{
+					ValueLabel: "error reading file: " + f,
+					Value:      err.Error()})
+			}
+		}
+		return
+	}
+
+	// Command: php -r 'echo json_encode(ini_get_all(null, false));'
+	c.DataMap["php"], err = command.ShellCommander(c.PhpPath, "-r", IniGetAllCode).Output()
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error running php",
+			Value:      command.GetMsgFromCommandError(err)})
+	}
+}
+
+// Commands implements config.CommandReporter.
+func (c *ConfigCheck) Commands() [][]string {
+	if len(c.Files) > 0 {
+		return nil
+	}
+	return [][]string{{c.PhpPath, "-r", IniGetAllCode}}
+}
+
+// UnmarshalDataMap parses the directives into Yaml nodes so that the values
+// can be verified by the YamlBase logic.
+func (c *ConfigCheck) UnmarshalDataMap() {
+	c.NodeMap = map[string]yamlv3.Node{}
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[configName]
+		var directives map[string]string
+		if len(c.Files) > 0 {
+			directives = ParseIni(data)
+		} else {
+			values := map[string]*string{}
+			if err := json.Unmarshal(data, &values); err != nil {
+				c.AddBreach(&result.ValueBreach{
+					ValueLabel: configName,
+					Value:      err.Error()})
+				return
+			}
+			directives = map[string]string{}
+			for name, v := range values {
+				directives[name] = ""
+				if v != nil {
+					directives[name] = *v
+				}
+			}
+		}
+
+		n := yamlv3.Node{}
+		if err := n.Encode(NestDirectives(directives)); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: configName,
+				Value:      err.Error()})
+			return
+		}
+		c.NodeMap[configName] = n
+	}
+}
@@ -0,0 +1,145 @@
+package php_test
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/php"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Config]()
+	assert.Equal(t, "*php.ConfigCheck", reflect.TypeOf(c).String())
+	c = config.ChecksRegistry[Opcache]()
+	assert.Equal(t, "*php.OpcacheCheck", reflect.TypeOf(c).String())
+}
+
+func TestConfigCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := ConfigCheck{PhpPath: "/usr/bin/php"}
+	err := c.Merge(&ConfigCheck{Files: []string{"php.ini"}})
+	assert.NoError(err)
+	assert.Equal("/usr/bin/php", c.PhpPath)
+	assert.Equal([]string{"php.ini"}, c.Files)
+}
+
+func TestConfigCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(nil, nil, &generatedCommand)
+	c := ConfigCheck{}
+	c.Init(Config)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("php -r 'echo json_encode(ini_get_all(null, false));'", generatedCommand)
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil, &exec.ExitError{Stderr: []byte("php: not found")}, nil)
+	c = ConfigCheck{}
+	c.Init(Config)
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "php-config",
+		Severity:   "normal",
+		ValueLabel: "error running php",
+		Value:      "php: not found",
+	}}, c.Result.Breaches)
+
+	c = ConfigCheck{Files: []string{"testdata/non-existent.ini"}}
+	c.Init(Config)
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "php-config",
+		Severity:   "normal",
+		ValueLabel: "error reading file: testdata/non-existent.ini",
+		Value:      "open testdata/non-existent.ini: no such file or directory",
+	}}, c.Result.Breaches)
+}
+
+func TestConfigCheckRunCheck(t *testing.T) {
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+	iniGetAll, _ := os.ReadFile("testdata/ini_get_all.json")
+	command.ShellCommander = internal.ShellCommanderMaker(&[]string{string(iniGetAll)}[0], nil, nil)
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "php",
+			Check: &ConfigCheck{YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+				{Key: "memory_limit", Min: "256M"},
+				{Key: "opcache.memory_consumption", Min: "128"},
+				{Key: "opcache.jit", Value: ""},
+				{Key: "display_errors", Value: "0", Truthy: true},
+			}}},
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{
+				"[php] 'memory_limit' is within limits",
+				"[php] 'opcache.memory_consumption' is within limits",
+				"[php] 'opcache.jit' equals ''",
+			},
+			ExpectFails: []result.Breach{
+				&result.KeyValueBreach{
+					BreachType:    "key-value",
+					CheckType:     "php-config",
+					Severity:      "normal",
+					KeyLabel:      "config:php",
+					Key:           "display_errors",
+					ValueLabel:    "actual",
+					ExpectedValue: "0",
+					Value:         "1",
+				},
+			},
+		},
+		{
+			Name: "iniFiles",
+			Check: &ConfigCheck{
+				Files: []string{"testdata/php.ini"},
+				YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+					{Key: "memory_limit", Min: "256M"},
+					{Key: "expose_php", Value: ""},
+					{Key: "disable_functions", Pattern: `\bexec\b`},
+				}},
+			},
+			ExpectStatus: result.Fail,
+			ExpectPasses: []string{
+				"[testdata/php.ini] 'expose_php' equals ''",
+				"[testdata/php.ini] 'disable_functions' matches '\\bexec\\b'",
+			},
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "php-config",
+					Severity:   "normal",
+					KeyLabel:   "config",
+					Key:        "testdata/php.ini",
+					ValueLabel: "out of range memory_limit",
+					Values:     []string{"128M is less than 256M"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Config)
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package php
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Opcache config.CheckType = "php-opcache"
+
+// CacheStatusCode is the code run by php to output the opcache & APCu status
+// as json.
+const CacheStatusCode = `$s = ['opcache' => null, 'apcu' => null];
+if (function_exists('opcache_get_status') && ($o = opcache_get_status(false))) {
+  $m = $o['memory_usage'];
+  $s['opcache'] = [
+    'enabled' => $o['opcache_enabled'],
+    'memory' => $m['used_memory'] + $m['free_memory'] + $m['wasted_memory'],
+    'hits' => $o['opcache_statistics']['hits'],
+    'misses' => $o['opcache_statistics']['misses'],
+  ];
+}
+if (function_exists('apcu_enabled') && apcu_enabled()) {
+  $i = apcu_cache_info(true);
+  $m = apcu_sma_info(true);
+  $s['apcu'] = [
+    'enabled' => true,
+    'memory' => $m['num_seg'] * $m['seg_size'],
+    'hits' => $i['num_hits'],
+    'misses' => $i['num_misses'],
+  ];
+}
+echo json_encode($s);`
+
+// CacheStatus is the status of an opcode or user cache.
+type CacheStatus struct {
+	Enabled bool    `json:"enabled"`
+	Memory  float64 `json:"memory"`
+	Hits    float64 `json:"hits"`
+	Misses  float64 `json:"misses"`
+}
+
+// HitRate returns the percentage of hits, or -1 if the cache was not used.
+func (s CacheStatus) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return -1
+	}
+	return s.Hits / (s.Hits + s.Misses) * 100
+}
+
+// OpcacheCheck verifies that opcache, and optionally APCu, are enabled with
+// adequate memory and hit rate.
+type OpcacheCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// Path to the php binary.
+	PhpPath string `yaml:"php-path"`
+	// Command outputting the status instead of running php, e.g, to fetch it
+	// from the web server since opcache is usually disabled for the cli.
+	StatusCommand string `yaml:"status-command"`
+	// Minimum opcache memory, e.g, 128M.
+	MinMemory string `yaml:"min-memory"`
+	// Minimum opcache hit rate, as a percentage.
+	MinHitRate float64 `yaml:"min-hit-rate"`
+	// Require APCu to be enabled.
+	Apcu bool `yaml:"apcu"`
+	// Minimum APCu memory, e.g, 32M.
+	ApcuMinMemory string `yaml:"apcu-min-memory"`
+	// Minimum APCu hit rate, as a percentage.
+	ApcuMinHitRate float64 `yaml:"apcu-min-hit-rate"`
+	// Status of the caches.
+	status map[string]*CacheStatus
+}
+
+// Init implementation for the php-opcache check.
+func (c *OpcacheCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.PhpPath == "" {
+		c.PhpPath = "php"
+	}
+}
+
+// Merge implementation for php-opcache check.
+func (c *OpcacheCheck) Merge(mergeCheck config.Check) error {
+	opcacheMergeCheck := mergeCheck.(*OpcacheCheck)
+	if err := c.CheckBase.Merge(&opcacheMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.PhpPath, opcacheMergeCheck.PhpPath)
+	utils.MergeString(&c.StatusCommand, opcacheMergeCheck.StatusCommand)
+	utils.MergeString(&c.MinMemory, opcacheMergeCheck.MinMemory)
+	if opcacheMergeCheck.MinHitRate > 0 {
+		c.MinHitRate = opcacheMergeCheck.MinHitRate
+	}
+	if opcacheMergeCheck.Apcu {
+		c.Apcu = true
+	}
+	utils.MergeString(&c.ApcuMinMemory, opcacheMergeCheck.ApcuMinMemory)
+	if opcacheMergeCheck.ApcuMinHitRate > 0 {
+		c.ApcuMinHitRate = opcacheMergeCheck.ApcuMinHitRate
+	}
+	return nil
+}
+
+// FetchData runs php, or the status command, to fetch the caches status.
+func (c *OpcacheCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	if c.StatusCommand != "" {
+		c.DataMap["status"], err = command.ShellCommander("sh", "-c", c.StatusCommand).Output()
+	} else {
+		c.DataMap["status"], err = command.ShellCommander(c.PhpPath, "-r", CacheStatusCode).Output()
+	}
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error fetching status",
+			Value:      command.GetMsgFromCommandError(err)})
+	}
+}
+
+// Commands implements config.CommandReporter.
+func (c *OpcacheCheck) Commands() [][]string {
+	if c.StatusCommand != "" {
+		return [][]string{{"sh", "-c", c.StatusCommand}}
+	}
+	return [][]string{{c.PhpPath, "-r", CacheStatusCode}}
+}
+
+// UnmarshalDataMap parses the caches status.
+func (c *OpcacheCheck) UnmarshalDataMap() {
+	c.status = map[string]*CacheStatus{}
+	if err := json.Unmarshal(c.DataMap["status"], &c.status); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error parsing status",
+			Value:      err.Error()})
+	}
+}
+
+// RunCheck implements the Check logic for the opcache & APCu status.
+func (c *OpcacheCheck) RunCheck() {
+	c.verifyCache("opcache", c.MinMemory, c.MinHitRate)
+	if c.Apcu {
+		c.verifyCache("apcu", c.ApcuMinMemory, c.ApcuMinHitRate)
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
+
+// verifyCache verifies a cache is enabled with the minimum memory & hit rate.
+func (c *OpcacheCheck) verifyCache(cache string, minMemory string, minHitRate float64) {
+	s := c.status[cache]
+	if s == nil || !s.Enabled {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "extension",
+			Key:        cache,
+			ValueLabel: "status",
+			Value:      "disabled",
+		})
+		return
+	}
+	c.AddPass(cache + " is enabled")
+
+	if minMemory != "" {
+		min, err := utils.ParseSize(minMemory)
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "invalid minimum memory",
+				Value:      err.Error()})
+			return
+		}
+		if s.Memory < min {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "extension",
+				Key:           cache,
+				ValueLabel:    "memory",
+				ExpectedValue: "at least " + minMemory,
+				Value:         formatMegabytes(s.Memory),
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("%s memory is %s", cache, formatMegabytes(s.Memory)))
+		}
+	}
+
+	if minHitRate > 0 {
+		hitRate := s.HitRate()
+		if hitRate < 0 {
+			c.AddWarning(fmt.Sprintf("%s has no hits or misses; unable to determine the hit rate", cache))
+		} else if hitRate < minHitRate {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:      "extension",
+				Key:           cache,
+				ValueLabel:    "hit rate",
+				ExpectedValue: "at least " + formatPercentage(minHitRate),
+				Value:         formatPercentage(hitRate),
+			})
+		} else {
+			c.AddPass(fmt.Sprintf("%s hit rate is %s", cache, formatPercentage(hitRate)))
+		}
+	}
+}
+
+func formatMegabytes(bytes float64) string {
+	return strconv.FormatFloat(bytes/1024/1024, 'f', -1, 64) + "M"
+}
+
+func formatPercentage(p float64) string {
+	return strconv.FormatFloat(p, 'f', 2, 64) + "%"
+}
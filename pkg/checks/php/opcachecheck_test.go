@@ -0,0 +1,191 @@
+package php_test
+
+import (
+	"os/exec"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/php"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpcacheCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := OpcacheCheck{MinMemory: "128M", MinHitRate: 90}
+	err := c.Merge(&OpcacheCheck{MinHitRate: 95, Apcu: true, ApcuMinMemory: "32M"})
+	assert.NoError(err)
+	assert.Equal("128M", c.MinMemory)
+	assert.Equal(float64(95), c.MinHitRate)
+	assert.True(c.Apcu)
+	assert.Equal("32M", c.ApcuMinMemory)
+}
+
+func TestOpcacheCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(nil, nil, &generatedCommand)
+	c := OpcacheCheck{StatusCommand: "curl -s http://localhost/status.php"}
+	c.Init(Opcache)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("sh -c 'curl -s http://localhost/status.php'", generatedCommand)
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil, &exec.ExitError{Stderr: []byte("php: not found")}, nil)
+	c = OpcacheCheck{}
+	c.Init(Opcache)
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "php-opcache",
+		Severity:   "normal",
+		ValueLabel: "error fetching status",
+		Value:      "php: not found",
+	}}, c.Result.Breaches)
+}
+
+func TestOpcacheCheckRunCheck(t *testing.T) {
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	tt := []struct {
+		internal.RunCheckTest
+		status string
+	}{
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "opcacheDisabled",
+				Check:        &OpcacheCheck{},
+				ExpectStatus: result.Fail,
+				ExpectNoPass: true,
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType: "key-value",
+						CheckType:  "php-opcache",
+						Severity:   "normal",
+						KeyLabel:   "extension",
+						Key:        "opcache",
+						ValueLabel: "status",
+						Value:      "disabled",
+					},
+				},
+			},
+			status: `{"opcache":null,"apcu":null}`,
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name:         "opcacheHealthy",
+				Check:        &OpcacheCheck{MinMemory: "128M", MinHitRate: 95},
+				ExpectStatus: result.Pass,
+				ExpectPasses: []string{
+					"opcache is enabled",
+					"opcache memory is 128M",
+					"opcache hit rate is 98.00%",
+				},
+				ExpectNoFail: true,
+			},
+			status: `{"opcache":{"enabled":true,"memory":134217728,"hits":9800,"misses":200},"apcu":null}`,
+		},
+		{
+			RunCheckTest: internal.RunCheckTest{
+				Name: "cachesUnhealthy",
+				Check: &OpcacheCheck{
+					MinMemory:      "256M",
+					MinHitRate:     99,
+					Apcu:           true,
+					ApcuMinMemory:  "32M",
+					ApcuMinHitRate: 90,
+				},
+				ExpectStatus: result.Fail,
+				ExpectPasses: []string{
+					"opcache is enabled",
+					"opcache hit rate is 99.50%",
+					"apcu is enabled",
+				},
+				ExpectFails: []result.Breach{
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "php-opcache",
+						Severity:      "normal",
+						KeyLabel:      "extension",
+						Key:           "opcache",
+						ValueLabel:    "memory",
+						ExpectedValue: "at least 256M",
+						Value:         "128M",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "php-opcache",
+						Severity:      "normal",
+						KeyLabel:      "extension",
+						Key:           "apcu",
+						ValueLabel:    "memory",
+						ExpectedValue: "at least 32M",
+						Value:         "16M",
+					},
+					&result.KeyValueBreach{
+						BreachType:    "key-value",
+						CheckType:     "php-opcache",
+						Severity:      "normal",
+						KeyLabel:      "extension",
+						Key:           "apcu",
+						ValueLabel:    "hit rate",
+						ExpectedValue: "at least 90.00%",
+						Value:         "50.00%",
+					},
+				},
+			},
+			status: `{"opcache":{"enabled":true,"memory":134217728,"hits":995,"misses":5},"apcu":{"enabled":true,"memory":16777216,"hits":10,"misses":10}}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			command.ShellCommander = internal.ShellCommanderMaker(&tc.status, nil, nil)
+			tc.Check.Init(Opcache)
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc.RunCheckTest)
+		})
+	}
+}
+
+func TestOpcacheCheckUnmarshalDataMap(t *testing.T) {
+	assert := assert.New(t)
+
+	c := OpcacheCheck{}
+	c.Init(Opcache)
+	c.DataMap = map[string][]byte{"status": []byte("not json")}
+	c.UnmarshalDataMap()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "php-opcache",
+		Severity:   "normal",
+		ValueLabel: "error parsing status",
+		Value:      "invalid character 'o' in literal null (expecting 'u')",
+	}}, c.Result.Breaches)
+}
+
+func TestOpcacheCheckNoHitRate(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+	status := `{"opcache":{"enabled":true,"memory":134217728,"hits":0,"misses":0}}`
+	command.ShellCommander = internal.ShellCommanderMaker(&status, nil, nil)
+
+	c := OpcacheCheck{MinHitRate: 95}
+	c.Init(Opcache)
+	c.FetchData()
+	c.UnmarshalDataMap()
+	c.RunCheck()
+	assert.Equal(result.Pass, c.Result.Status)
+	assert.Equal([]string{"opcache has no hits or misses; unable to determine the hit rate"}, c.Result.Warnings)
+}
@@ -0,0 +1,89 @@
+// Package php provides checks against the PHP configuration and caches.
+package php
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=php
+
+func RegisterChecks() {
+	config.ChecksRegistry[Config] = func() config.Check { return &ConfigCheck{} }
+	config.ChecksRegistry[Opcache] = func() config.Check { return &OpcacheCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// ParseIni reads ini-formatted data into a map of directives. Comments and
+// sections are ignored, quotes are removed and boolean values are converted
+// to "1" or "" as PHP does.
+func ParseIni(data []byte) map[string]string {
+	directives := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' || line[0] == '[' {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) > 1 && (value[0] == '"' || value[0] == '\'') {
+			if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+				value = value[1 : end+1]
+			}
+		} else {
+			if i := strings.Index(value, ";"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+			switch strings.ToLower(value) {
+			case "on", "yes", "true":
+				value = "1"
+			case "off", "no", "false", "none":
+				value = ""
+			}
+		}
+		directives[key] = value
+	}
+	return directives
+}
+
+// NestDirectives converts the directives into nested maps using the dots in
+// their names, e.g, opcache.enable becomes {"opcache": {"enable": "1"}}, so
+// that they can be looked up using yaml paths.
+func NestDirectives(directives map[string]string) map[string]any {
+	names := []string{}
+	for name := range directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nested := map[string]any{}
+	for _, name := range names {
+		value := directives[name]
+		parts := strings.Split(name, ".")
+		m := nested
+		for _, p := range parts[:len(parts)-1] {
+			child, ok := m[p].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				m[p] = child
+			}
+			m = child
+		}
+		m[parts[len(parts)-1]] = value
+	}
+	return nested
+}
@@ -0,0 +1,39 @@
+package php_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/php"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIni(t *testing.T) {
+	data, _ := os.ReadFile("testdata/php.ini")
+	assert.Equal(t, map[string]string{
+		"memory_limit":               "128M",
+		"max_execution_time":         "30",
+		"expose_php":                 "",
+		"disable_functions":          "exec,passthru,shell_exec,system",
+		"opcache.enable":             "1",
+		"opcache.memory_consumption": "128",
+	}, ParseIni(data))
+}
+
+func TestNestDirectives(t *testing.T) {
+	assert.Equal(t, map[string]any{
+		"memory_limit": "128M",
+		"opcache": map[string]any{
+			"enable": "1",
+			"jit":    "tracing",
+		},
+		"session": map[string]any{
+			"upload_progress": map[string]any{"enabled": "1"},
+		},
+	}, NestDirectives(map[string]string{
+		"memory_limit":                    "128M",
+		"opcache.enable":                  "1",
+		"opcache.jit":                     "tracing",
+		"session.upload_progress.enabled": "1",
+	}))
+}
@@ -81,11 +81,9 @@ func (c *PhpStanCheck) GetBinary() (path string) {
 	return
 }
 
-// FetchData runs the phpstan command to populate data for the check.
-func (c *PhpStanCheck) FetchData() {
-	var err error
-	phpstanPath := c.GetBinary()
-
+// args determines the phpstan arguments, and whether any of the paths to
+// analyse exists.
+func (c *PhpStanCheck) args() ([]string, bool) {
 	configPath := c.Config
 	if !filepath.IsAbs(c.Config) {
 		configPath = filepath.Join(config.ProjectDir, configPath)
@@ -108,7 +106,23 @@ func (c *PhpStanCheck) FetchData() {
 			args = append(args, path)
 		}
 	}
+	return args, foundPath
+}
+
+// Commands implements config.CommandReporter.
+func (c *PhpStanCheck) Commands() [][]string {
+	args, foundPath := c.args()
+	if !foundPath {
+		return nil
+	}
+	return [][]string{append([]string{c.GetBinary()}, args...)}
+}
 
+// FetchData runs the phpstan command to populate data for the check.
+func (c *PhpStanCheck) FetchData() {
+	var err error
+	phpstanPath := c.GetBinary()
+	args, foundPath := c.args()
 	if !foundPath {
 		c.Result.Status = result.Pass
 		c.AddPass("no paths found to run phpstan on")
@@ -187,7 +201,8 @@ func (c *PhpStanCheck) RunCheck() {
 		return
 	}
 
-	for file, errors := range c.phpstanResult.Files {
+	for _, file := range utils.SortedKeys(c.phpstanResult.Files) {
+		errors := c.phpstanResult.Files[file]
 		errLines := []string{}
 		for _, er := range errors.Messages {
 			errLines = append(errLines,
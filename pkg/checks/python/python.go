@@ -0,0 +1,85 @@
+// Package python provides checks for Python projects.
+package python
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=python
+
+func RegisterChecks() {
+	config.ChecksRegistry[Requirements] = func() config.Check { return &RequirementsCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// Requirement is a single package requirement, e.g, django==4.2.7.
+type Requirement struct {
+	Name string
+	// Version specifier, e.g, '==4.2.7' or '>=2.31,<3'.
+	Specifier string
+}
+
+// IsPinned determines whether the requirement is pinned to an exact version.
+func (r Requirement) IsPinned() bool {
+	spec := strings.TrimSpace(r.Specifier)
+	if strings.Contains(spec, ",") || strings.Contains(spec, "*") {
+		return false
+	}
+	return strings.HasPrefix(spec, "==")
+}
+
+var requirementNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+// ParseRequirements reads pip requirements-formatted data, skipping
+// comments, options (e.g, -r other.txt) and urls. Names are normalised to
+// lowercase with dashes as per PEP 503.
+func ParseRequirements(data []byte) []Requirement {
+	reqs := []Requirement{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		// Drop environment markers.
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+
+		name := requirementNameRegex.FindString(line)
+		if name == "" {
+			continue
+		}
+		spec := strings.TrimSpace(line[len(name):])
+		// Drop extras, e.g, requests[security].
+		if strings.HasPrefix(spec, "[") {
+			if i := strings.Index(spec, "]"); i >= 0 {
+				spec = strings.TrimSpace(spec[i+1:])
+			}
+		}
+		reqs = append(reqs, Requirement{
+			Name:      NormaliseName(name),
+			Specifier: strings.ReplaceAll(spec, " ", ""),
+		})
+	}
+	return reqs
+}
+
+var normaliseNameRegex = regexp.MustCompile(`[-_.]+`)
+
+// NormaliseName normalises a package name as per PEP 503.
+func NormaliseName(name string) string {
+	return strings.ToLower(normaliseNameRegex.ReplaceAllString(name, "-"))
+}
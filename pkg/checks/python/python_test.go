@@ -0,0 +1,45 @@
+package python_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/python"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRequirements(t *testing.T) {
+	data := []byte(`# Application requirements.
+-r base.txt
+Django==4.2.7
+requests[security] >= 2.31, <3
+PyCrypto==2.6.1  # legacy
+celery==5.3.*
+gunicorn==21.2.0 ; sys_platform != "win32"
+git+https://github.com/example/lib.git#egg=lib
+zope.interface
+`)
+	assert.Equal(t, []Requirement{
+		{Name: "django", Specifier: "==4.2.7"},
+		{Name: "requests", Specifier: ">=2.31,<3"},
+		{Name: "pycrypto", Specifier: "==2.6.1"},
+		{Name: "celery", Specifier: "==5.3.*"},
+		{Name: "gunicorn", Specifier: "==21.2.0"},
+		{Name: "zope-interface", Specifier: ""},
+	}, ParseRequirements(data))
+}
+
+func TestRequirementIsPinned(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(Requirement{Name: "django", Specifier: "==4.2.7"}.IsPinned())
+	assert.True(Requirement{Name: "django", Specifier: "===4.2.7"}.IsPinned())
+	assert.False(Requirement{Name: "django", Specifier: ""}.IsPinned())
+	assert.False(Requirement{Name: "django", Specifier: ">=4.2"}.IsPinned())
+	assert.False(Requirement{Name: "django", Specifier: "==4.2.*"}.IsPinned())
+	assert.False(Requirement{Name: "django", Specifier: "==4.2.7,!=4.2.8"}.IsPinned())
+}
+
+func TestNormaliseName(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("zope-interface", NormaliseName("zope.interface"))
+	assert.Equal("my-package", NormaliseName("My__Package"))
+}
@@ -0,0 +1,108 @@
+package python
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Requirements config.CheckType = "python-requirements"
+
+// RequirementsCheck verifies the packages listed in pip requirements files.
+type RequirementsCheck struct {
+	yaml.YamlCheck `yaml:",inline"`
+	// Require all packages to be pinned to an exact version.
+	RequirePinned *bool `yaml:"require-pinned"`
+	// List of packages which must not be required.
+	Disallowed   []string `yaml:"disallowed"`
+	requirements map[string][]Requirement
+}
+
+// Init implementation for the python requirements check.
+func (c *RequirementsCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	if c.File == "" && len(c.Files) == 0 && c.Pattern == "" {
+		c.File = "requirements.txt"
+	}
+}
+
+// Merge implementation for python requirements check.
+func (c *RequirementsCheck) Merge(mergeCheck config.Check) error {
+	requirementsMergeCheck := mergeCheck.(*RequirementsCheck)
+	if err := c.YamlCheck.Merge(&requirementsMergeCheck.YamlCheck); err != nil {
+		return err
+	}
+
+	if requirementsMergeCheck.RequirePinned != nil {
+		c.RequirePinned = requirementsMergeCheck.RequirePinned
+	}
+	utils.MergeStringSlice(&c.Disallowed, requirementsMergeCheck.Disallowed)
+	return nil
+}
+
+// UnmarshalDataMap parses the requirements files for further processing.
+func (c *RequirementsCheck) UnmarshalDataMap() {
+	c.requirements = map[string][]Requirement{}
+	for configName, data := range c.DataMap {
+		c.requirements[configName] = ParseRequirements(data)
+	}
+}
+
+// RunCheck implements the Check logic for python requirements.
+func (c *RequirementsCheck) RunCheck() {
+	disallowed := []string{}
+	for _, d := range c.Disallowed {
+		if !strings.HasPrefix(d, "re:") && !strings.HasPrefix(d, "glob:") {
+			d = NormaliseName(d)
+		}
+		disallowed = append(disallowed, d)
+	}
+
+	configNames := []string{}
+	for configName := range c.requirements {
+		configNames = append(configNames, configName)
+	}
+	sort.Strings(configNames)
+
+	for _, configName := range configNames {
+		unpinned := []string{}
+		found := []string{}
+		for _, r := range c.requirements[configName] {
+			if utils.StringSliceMatchAny(disallowed, r.Name) {
+				found = append(found, r.Name)
+			}
+			if c.RequirePinned != nil && *c.RequirePinned && !r.IsPinned() {
+				unpinned = append(unpinned, r.Name+r.Specifier)
+			}
+		}
+
+		if len(found) > 0 {
+			c.AddBreach(&result.KeyValuesBreach{
+				KeyLabel:   "file",
+				Key:        configName,
+				ValueLabel: "disallowed packages",
+				Values:     found,
+			})
+		}
+		if len(unpinned) > 0 {
+			c.AddBreach(&result.KeyValuesBreach{
+				KeyLabel:   "file",
+				Key:        configName,
+				ValueLabel: "unpinned packages",
+				Values:     unpinned,
+			})
+		}
+		if len(found) == 0 && len(unpinned) == 0 {
+			c.AddPass(fmt.Sprintf("[%s] requirements are valid", configName))
+		}
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
@@ -0,0 +1,113 @@
+package python_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/python"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Requirements]()
+	assert.Equal(t, "*python.RequirementsCheck", reflect.TypeOf(c).String())
+}
+
+func TestRequirementsCheckInit(t *testing.T) {
+	assert := assert.New(t)
+
+	c := RequirementsCheck{}
+	c.Init(Requirements)
+	assert.Equal("requirements.txt", c.File)
+
+	c = RequirementsCheck{YamlCheck: yaml.YamlCheck{Pattern: "requirements.*.txt"}}
+	c.Init(Requirements)
+	assert.Equal("", c.File)
+}
+
+func TestRequirementsCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	pinned := true
+	c := RequirementsCheck{
+		YamlCheck:  yaml.YamlCheck{File: "requirements.txt"},
+		Disallowed: []string{"pycrypto"},
+	}
+	err := c.Merge(&RequirementsCheck{
+		YamlCheck:     yaml.YamlCheck{File: "requirements-dev.txt"},
+		RequirePinned: &pinned,
+		Disallowed:    []string{"nose"},
+	})
+	assert.NoError(err)
+	assert.Equal("requirements-dev.txt", c.File)
+	assert.True(*c.RequirePinned)
+	assert.Equal([]string{"nose"}, c.Disallowed)
+}
+
+func TestRequirementsCheckRunCheck(t *testing.T) {
+	assert := assert.New(t)
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	pinned := true
+	tt := []struct {
+		name         string
+		check        RequirementsCheck
+		expectStatus result.Status
+		expectPasses []string
+		expectFails  []result.Breach
+	}{
+		{
+			name:         "noConstraints",
+			check:        RequirementsCheck{},
+			expectStatus: result.Pass,
+			expectPasses: []string{"[requirements.txt] requirements are valid"},
+		},
+		{
+			name: "disallowedAndUnpinned",
+			check: RequirementsCheck{
+				RequirePinned: &pinned,
+				Disallowed:    []string{"PyCrypto", "nose"},
+			},
+			expectStatus: result.Fail,
+			expectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "python-requirements",
+					Severity:   "normal",
+					KeyLabel:   "file",
+					Key:        "requirements.txt",
+					ValueLabel: "disallowed packages",
+					Values:     []string{"pycrypto"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "python-requirements",
+					Severity:   "normal",
+					KeyLabel:   "file",
+					Key:        "requirements.txt",
+					ValueLabel: "unpinned packages",
+					Values:     []string{"requests>=2.31,<3", "celery==5.3.*"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.check
+			c.Init(Requirements)
+			c.FetchData()
+			assert.Empty(c.Result.Breaches)
+			c.UnmarshalDataMap()
+			c.RunCheck()
+			c.Result.DetermineResultStatus(false)
+			assert.Equal(tc.expectStatus, c.Result.Status)
+			assert.ElementsMatch(tc.expectPasses, c.Result.Passes)
+			assert.ElementsMatch(tc.expectFails, c.Result.Breaches)
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package sbom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+// Component is a software component listed in an SBOM.
+type Component struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// License expression, e.g, MIT OR Apache-2.0.
+	License string `json:"license"`
+	// License identifiers in the expression, e.g, [MIT, Apache-2.0].
+	Licenses []string `json:"licenses"`
+	Purl     string   `json:"purl"`
+}
+
+// Bom is the normalised list of components from CycloneDX & SPDX files.
+type Bom struct {
+	Format     string      `json:"format"`
+	Components []Component `json:"components"`
+}
+
+// ParseSbom detects the format of the SBOM, i.e, CycloneDX json/xml or SPDX
+// json/tag-value, and parses its components.
+func ParseSbom(data []byte) (Bom, error) {
+	data = bytes.TrimSpace(data)
+	var components []Component
+	var err error
+	format := FormatCycloneDX
+	switch {
+	case bytes.HasPrefix(data, []byte("<")):
+		components, err = parseCycloneDXXml(data)
+	case bytes.HasPrefix(data, []byte("{")):
+		var doc struct {
+			BomFormat   string `json:"bomFormat"`
+			SpdxVersion string `json:"spdxVersion"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return Bom{}, err
+		}
+		if doc.BomFormat == "CycloneDX" {
+			components, err = parseCycloneDXJson(data)
+		} else if doc.SpdxVersion != "" {
+			format = FormatSPDX
+			components, err = parseSpdxJson(data)
+		} else {
+			return Bom{}, errors.New("unknown SBOM format; expected CycloneDX or SPDX")
+		}
+	case bytes.HasPrefix(data, []byte("SPDXVersion:")):
+		format = FormatSPDX
+		components, err = parseSpdxTagValue(data)
+	default:
+		return Bom{}, errors.New("unknown SBOM format; expected CycloneDX or SPDX")
+	}
+	if err != nil {
+		return Bom{}, err
+	}
+	return Bom{Format: format, Components: components}, nil
+}
+
+// LicenseIds extracts the license identifiers from an SPDX license
+// expression; exceptions, e.g, WITH Classpath-exception-2.0, are skipped.
+func LicenseIds(expression string) []string {
+	ids := []string{}
+	r := strings.NewReplacer("(", " ", ")", " ")
+	tokens := strings.Fields(r.Replace(expression))
+	for i := 0; i < len(tokens); i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "AND", "OR":
+			continue
+		case "WITH":
+			i++
+			continue
+		}
+		if !utils.StringSliceContains(ids, tokens[i]) {
+			ids = append(ids, tokens[i])
+		}
+	}
+	return ids
+}
+
+func newComponent(name string, version string, license string, purl string) Component {
+	return Component{
+		Name:     name,
+		Version:  version,
+		License:  license,
+		Licenses: LicenseIds(license),
+		Purl:     purl,
+	}
+}
+
+type cdxJsonComponent struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Purl     string `json:"purl"`
+	Licenses []struct {
+		License struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Components []cdxJsonComponent `json:"components"`
+}
+
+func parseCycloneDXJson(data []byte) ([]Component, error) {
+	var bom struct {
+		Components []cdxJsonComponent `json:"components"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		return nil, err
+	}
+
+	components := []Component{}
+	var walk func([]cdxJsonComponent)
+	walk = func(cs []cdxJsonComponent) {
+		for _, c := range cs {
+			licenses := []string{}
+			for _, l := range c.Licenses {
+				licenses = append(licenses, firstNonEmpty(l.Expression, l.License.Id, l.License.Name))
+			}
+			components = append(components, newComponent(c.Name, c.Version, joinLicenses(licenses), c.Purl))
+			walk(c.Components)
+		}
+	}
+	walk(bom.Components)
+	return components, nil
+}
+
+type cdxXmlComponent struct {
+	Name     string `xml:"name"`
+	Version  string `xml:"version"`
+	Purl     string `xml:"purl"`
+	Licenses struct {
+		License []struct {
+			Id   string `xml:"id"`
+			Name string `xml:"name"`
+		} `xml:"license"`
+		Expression []string `xml:"expression"`
+	} `xml:"licenses"`
+	Components []cdxXmlComponent `xml:"components>component"`
+}
+
+func parseCycloneDXXml(data []byte) ([]Component, error) {
+	var bom struct {
+		XMLName    xml.Name
+		Components []cdxXmlComponent `xml:"components>component"`
+	}
+	if err := xml.Unmarshal(data, &bom); err != nil {
+		return nil, err
+	}
+	if bom.XMLName.Local != "bom" {
+		return nil, errors.New("unknown SBOM format; expected CycloneDX or SPDX")
+	}
+
+	components := []Component{}
+	var walk func([]cdxXmlComponent)
+	walk = func(cs []cdxXmlComponent) {
+		for _, c := range cs {
+			licenses := []string{}
+			for _, l := range c.Licenses.License {
+				licenses = append(licenses, firstNonEmpty(l.Id, l.Name))
+			}
+			licenses = append(licenses, c.Licenses.Expression...)
+			components = append(components, newComponent(
+				strings.TrimSpace(c.Name), strings.TrimSpace(c.Version),
+				joinLicenses(licenses), strings.TrimSpace(c.Purl)))
+			walk(c.Components)
+		}
+	}
+	walk(bom.Components)
+	return components, nil
+}
+
+func parseSpdxJson(data []byte) ([]Component, error) {
+	var doc struct {
+		Packages []struct {
+			Name             string `json:"name"`
+			VersionInfo      string `json:"versionInfo"`
+			LicenseConcluded string `json:"licenseConcluded"`
+			LicenseDeclared  string `json:"licenseDeclared"`
+			ExternalRefs     []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	components := []Component{}
+	for _, p := range doc.Packages {
+		purl := ""
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				purl = ref.ReferenceLocator
+				break
+			}
+		}
+		components = append(components, newComponent(p.Name, p.VersionInfo,
+			spdxLicense(p.LicenseConcluded, p.LicenseDeclared), purl))
+	}
+	return components, nil
+}
+
+func parseSpdxTagValue(data []byte) ([]Component, error) {
+	type pkg struct {
+		name, version, concluded, declared, purl string
+	}
+	pkgs := []*pkg{}
+	var cur *pkg
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		tag, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if tag == "PackageName" {
+			cur = &pkg{name: value}
+			pkgs = append(pkgs, cur)
+			continue
+		}
+		// Tags before the first package describe the document.
+		if cur == nil {
+			continue
+		}
+		switch tag {
+		case "PackageVersion":
+			cur.version = value
+		case "PackageLicenseConcluded":
+			cur.concluded = value
+		case "PackageLicenseDeclared":
+			cur.declared = value
+		case "ExternalRef":
+			// ExternalRef: PACKAGE-MANAGER purl pkg:npm/lodash@4.17.21
+			fields := strings.Fields(value)
+			if len(fields) == 3 && fields[1] == "purl" && cur.purl == "" {
+				cur.purl = fields[2]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	components := []Component{}
+	for _, p := range pkgs {
+		components = append(components, newComponent(p.name, p.version,
+			spdxLicense(p.concluded, p.declared), p.purl))
+	}
+	return components, nil
+}
+
+// spdxLicense returns the concluded license, falling back to the declared
+// one; NOASSERTION & NONE are treated as no license.
+func spdxLicense(concluded string, declared string) string {
+	for _, l := range []string{concluded, declared} {
+		if l != "" && l != "NOASSERTION" && l != "NONE" {
+			return l
+		}
+	}
+	return ""
+}
+
+// joinLicenses combines multiple licenses into a single expression.
+func joinLicenses(licenses []string) string {
+	if len(licenses) <= 1 {
+		return strings.Join(licenses, "")
+	}
+	for i, l := range licenses {
+		if strings.Contains(l, " ") {
+			licenses[i] = "(" + l + ")"
+		}
+	}
+	return strings.Join(licenses, " AND ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package sbom_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/sbom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSbom(t *testing.T) {
+	lodash := Component{
+		Name:     "lodash",
+		Version:  "4.17.21",
+		License:  "MIT",
+		Licenses: []string{"MIT"},
+		Purl:     "pkg:npm/lodash@4.17.21",
+	}
+	cycloneDX := Bom{
+		Format: FormatCycloneDX,
+		Components: []Component{
+			lodash,
+			{
+				Name:     "readline",
+				Version:  "8.2",
+				License:  "GPL-3.0-or-later WITH GCC-exception-3.1",
+				Licenses: []string{"GPL-3.0-or-later"},
+				Purl:     "pkg:generic/readline@8.2",
+			},
+			{
+				Name:     "ncurses",
+				Version:  "6.4",
+				License:  "MIT AND X11-style",
+				Licenses: []string{"MIT", "X11-style"},
+			},
+		},
+	}
+	spdx := Bom{
+		Format: FormatSPDX,
+		Components: []Component{
+			lodash,
+			{
+				Name:     "readline",
+				Version:  "8.2",
+				License:  "GPL-3.0-or-later",
+				Licenses: []string{"GPL-3.0-or-later"},
+			},
+		},
+	}
+
+	tt := []struct {
+		file     string
+		expected Bom
+	}{
+		{file: "bom.json", expected: cycloneDX},
+		{file: "bom.xml", expected: cycloneDX},
+		{file: "sbom.spdx.json", expected: spdx},
+		{file: "sbom.spdx", expected: spdx},
+	}
+	for _, tc := range tt {
+		t.Run(tc.file, func(t *testing.T) {
+			data, err := os.ReadFile("testdata/" + tc.file)
+			assert.NoError(t, err)
+			bom, err := ParseSbom(data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, bom)
+		})
+	}
+
+	for _, data := range []string{`{"name": "app"}`, "<project/>", "foo"} {
+		_, err := ParseSbom([]byte(data))
+		assert.EqualError(t, err, "unknown SBOM format; expected CycloneDX or SPDX")
+	}
+}
+
+func TestLicenseIds(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{}, LicenseIds(""))
+	assert.Equal([]string{"MIT"}, LicenseIds("MIT"))
+	assert.Equal([]string{"MIT", "Apache-2.0", "GPL-2.0-only"},
+		LicenseIds("(MIT OR Apache-2.0) AND (GPL-2.0-only WITH Classpath-exception-2.0 or MIT)"))
+}
@@ -0,0 +1,16 @@
+// Package sbom provides checks for Software Bill of Materials files.
+package sbom
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=sbom
+
+func RegisterChecks() {
+	config.ChecksRegistry[Sbom] = func() config.Check { return &SbomCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
@@ -0,0 +1,44 @@
+package sbom
+
+import (
+	"encoding/json"
+
+	jsoncheck "github.com/salsadigitalauorg/shipshape/pkg/checks/json"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const Sbom config.CheckType = "sbom"
+
+// SbomCheck verifies the components listed in CycloneDX or SPDX files, using
+// the same key-values as the json check against the normalised components.
+type SbomCheck struct {
+	jsoncheck.JsonCheck `yaml:",inline"`
+}
+
+// Merge implementation for sbom check.
+func (c *SbomCheck) Merge(mergeCheck config.Check) error {
+	sbomMergeCheck := mergeCheck.(*SbomCheck)
+	return c.JsonCheck.Merge(&sbomMergeCheck.JsonCheck)
+}
+
+// UnmarshalDataMap parses the SBOM files into the same structure as json
+// data so that the key-values can be verified by the json check logic.
+func (c *SbomCheck) UnmarshalDataMap() {
+	c.Node = map[string]any{}
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[configName]
+		s, err := ParseSbom(data)
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{ValueLabel: configName, Value: err.Error()})
+			return
+		}
+		// Round-trip through json to get the generic types expected by the
+		// json key-values.
+		b, _ := json.Marshal(s)
+		var n any
+		json.Unmarshal(b, &n)
+		c.Node[configName] = n
+	}
+}
@@ -0,0 +1,69 @@
+package sbom_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/json"
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/sbom"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Sbom]()
+	assert.Equal(t, "*sbom.SbomCheck", reflect.TypeOf(c).String())
+}
+
+func TestSbomCheck(t *testing.T) {
+	assert := assert.New(t)
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	c := SbomCheck{JsonCheck: json.JsonCheck{
+		YamlCheck: yaml.YamlCheck{File: "bom.xml"},
+		KeyValues: []json.KeyValue{
+			{KeyValue: yaml.KeyValue{Key: "format", Value: "cyclonedx"}},
+			{KeyValue: yaml.KeyValue{Key: "components[].licenses[]", IsList: true},
+				DisallowedValues: []any{"glob:GPL-*", "glob:AGPL-*"}},
+			{KeyValue: yaml.KeyValue{Key: "components[].purl", IsList: true},
+				DisallowedValues: []any{"glob:pkg:npm/event-stream@*"}},
+		},
+	}}
+	c.Init(Sbom)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	c.UnmarshalDataMap()
+	assert.Empty(c.Result.Breaches)
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.ElementsMatch([]string{
+		"[bom.xml] 'format' equals 'cyclonedx'",
+		"[bom.xml] no disallowed 'components[].purl'",
+	}, c.Result.Passes)
+	assert.ElementsMatch([]result.Breach{
+		&result.KeyValuesBreach{
+			BreachType: "key-values",
+			CheckType:  "sbom",
+			Severity:   "normal",
+			KeyLabel:   "config",
+			Key:        "bom.xml",
+			ValueLabel: "disallowed components[].licenses[]",
+			Values:     []string{"GPL-3.0-or-later"},
+		},
+	}, c.Result.Breaches)
+}
+
+func TestSbomCheckUnmarshalDataMap(t *testing.T) {
+	c := SbomCheck{}
+	c.DataMap = map[string][]byte{"bom.json": []byte(`{"name": "app"}`)}
+	c.UnmarshalDataMap()
+	assert.EqualValues(t, []result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "bom.json",
+		Value:      "unknown SBOM format; expected CycloneDX or SPDX",
+	}}, c.Result.Breaches)
+}
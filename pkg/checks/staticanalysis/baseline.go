@@ -0,0 +1,226 @@
+package staticanalysis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// BaselineModeCheck breaches when issues are not in the baseline.
+	BaselineModeCheck = "check"
+	// BaselineModeGenerate writes the baseline of the current issues.
+	BaselineModeGenerate = "generate"
+)
+
+// BaselineEntry is an ignored error of a phpstan baseline.
+type BaselineEntry struct {
+	// Regex matching the error message, e.g, #^Undefined variable\: \$bar$#.
+	Message string `yaml:"message"`
+	// Unescaped message, written by recent versions of phpstan.
+	RawMessage string `yaml:"rawMessage"`
+	Identifier string `yaml:"identifier"`
+	Count      int    `yaml:"count"`
+	Path       string `yaml:"path"`
+}
+
+// ParsePhpstanBaseline parses a phpstan baseline in the neon format; the
+// baselines written by phpstan are valid yaml once their tab indentation is
+// replaced.
+func ParsePhpstanBaseline(data []byte) ([]BaselineEntry, error) {
+	baseline := struct {
+		Parameters struct {
+			IgnoreErrors []BaselineEntry `yaml:"ignoreErrors"`
+		} `yaml:"parameters"`
+	}{}
+	data = []byte(strings.ReplaceAll(string(data), "\t", "    "))
+	if err := yaml.Unmarshal(data, &baseline); err != nil {
+		return nil, err
+	}
+	return baseline.Parameters.IgnoreErrors, nil
+}
+
+var regexEscapeRegex = regexp.MustCompile(`\\(.)`)
+
+// ReadableMessage returns the message of the entry without the regex
+// delimiters & escaping.
+func (e BaselineEntry) ReadableMessage() string {
+	if e.RawMessage != "" {
+		return e.RawMessage
+	}
+	msg := e.Message
+	if len(msg) > 1 {
+		if end := strings.LastIndexByte(msg, msg[0]); end > 0 {
+			msg = msg[1:end]
+		}
+	}
+	msg = strings.TrimSuffix(strings.TrimPrefix(msg, "^"), "$")
+	return regexEscapeRegex.ReplaceAllString(msg, "$1")
+}
+
+func (e BaselineEntry) key() string {
+	return fmt.Sprintf("%s\x00%s\x00%s", e.Path, e.Identifier, e.ReadableMessage())
+}
+
+func (e BaselineEntry) count() int {
+	if e.Count == 0 {
+		return 1
+	}
+	return e.Count
+}
+
+// baselinePath determines the path of the baseline file.
+func (c *StaticAnalysisCheck) baselinePath() string {
+	if filepath.IsAbs(c.Baseline) {
+		return c.Baseline
+	}
+	return filepath.Join(config.ProjectDir, c.Baseline)
+}
+
+// baselineTarget determines the file the tool generates the baseline into.
+func (c *StaticAnalysisCheck) baselineTarget() string {
+	if c.BaselineMode == BaselineModeGenerate {
+		return c.baselinePath()
+	}
+	if c.generatedBaseline == "" {
+		return "<generated baseline>"
+	}
+	return c.generatedBaseline
+}
+
+// validBaseline ensures the baseline can be used with the tool.
+func (c *StaticAnalysisCheck) validBaseline() bool {
+	if ToolDefaults[c.Tool].BaselineArg == "" {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "baseline not supported",
+			Value:      c.Tool})
+		return false
+	}
+	if c.BaselineMode != "" && c.BaselineMode != BaselineModeCheck &&
+		c.BaselineMode != BaselineModeGenerate {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid baseline-mode",
+			Value:      c.BaselineMode})
+		return false
+	}
+	return true
+}
+
+// fetchBaselines runs the tool, generating a new baseline next to the
+// existing one, so that the paths in both are relative to the same
+// directory, then reads them both.
+func (c *StaticAnalysisCheck) fetchBaselines() {
+	baseline, err := os.ReadFile(c.baselinePath())
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to read baseline",
+			Value:      err.Error()})
+		return
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(c.baselinePath()), ".shipshape-baseline-*.neon")
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to create baseline file",
+			Value:      err.Error()})
+		return
+	}
+	f.Close()
+	// The tool does not write the baseline if there are no issues.
+	os.Remove(f.Name())
+	c.generatedBaseline = f.Name()
+	defer func() {
+		os.Remove(c.generatedBaseline)
+		c.generatedBaseline = ""
+	}()
+
+	runs := c.toolArgs()
+	for _, k := range utils.SortedKeys(runs) {
+		c.runTool(k, runs[k])
+	}
+	generated, err := os.ReadFile(c.generatedBaseline)
+	if err != nil && !os.IsNotExist(err) {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to read generated baseline",
+			Value:      err.Error()})
+		return
+	}
+	c.DataMap["baseline"] = baseline
+	c.DataMap["generated-baseline"] = generated
+}
+
+// unmarshalBaselines parses the existing & generated baselines.
+func (c *StaticAnalysisCheck) unmarshalBaselines() {
+	if c.BaselineMode == BaselineModeGenerate {
+		return
+	}
+	var err error
+	if c.baselineEntries, err = ParsePhpstanBaseline(c.DataMap["baseline"]); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to parse baseline",
+			Value:      err.Error()})
+		return
+	}
+	if c.generatedEntries, err = ParsePhpstanBaseline(c.DataMap["generated-baseline"]); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to parse generated baseline",
+			Value:      err.Error()})
+	}
+}
+
+// runBaselineCheck reports the issues which are not in the baseline, or
+// occur more often than in it, per file.
+func (c *StaticAnalysisCheck) runBaselineCheck() {
+	if c.BaselineMode == BaselineModeGenerate {
+		c.AddPass(fmt.Sprintf("baseline written to %s", c.Baseline))
+		c.Result.Status = result.Pass
+		return
+	}
+
+	counts := map[string]int{}
+	total := 0
+	for _, e := range c.baselineEntries {
+		counts[e.key()] += e.count()
+		total += e.count()
+	}
+	fileIssues := map[string][]string{}
+	generatedTotal := 0
+	for _, e := range c.generatedEntries {
+		generatedTotal += e.count()
+		added := e.count() - counts[e.key()]
+		if added <= 0 {
+			continue
+		}
+		msg := formatIssue(Issue{
+			Rule:     e.Identifier,
+			Severity: IssueSeverityError,
+			Message:  e.ReadableMessage(),
+		})
+		if added > 1 {
+			msg += fmt.Sprintf(" (x%d)", added)
+		}
+		fileIssues[e.Path] = append(fileIssues[e.Path], msg)
+	}
+
+	if len(fileIssues) == 0 {
+		c.AddPass("no new issue found compared with the baseline")
+		if generatedTotal < total {
+			c.AddPass(fmt.Sprintf("%d issues fixed since the baseline was generated", total-generatedTotal))
+		}
+		c.Result.Status = result.Pass
+		return
+	}
+	for _, f := range utils.SortedKeys(fileIssues) {
+		c.AddBreach(&result.KeyValuesBreach{
+			Key:    fmt.Sprintf("file: %s", f),
+			Values: fileIssues[f],
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package staticanalysis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Configuration files of eslint, in the order of precedence.
+var (
+	EslintFlatConfigFiles = []string{"eslint.config.js", "eslint.config.mjs",
+		"eslint.config.cjs", "eslint.config.ts", "eslint.config.mts", "eslint.config.cts"}
+	EslintLegacyConfigFiles = []string{".eslintrc.js", ".eslintrc.cjs",
+		".eslintrc.yaml", ".eslintrc.yml", ".eslintrc.json", ".eslintrc"}
+)
+
+// EslintConfigType determines whether eslint is configured using a flat
+// config, i.e, eslint.config.js, or a legacy .eslintrc, returning either
+// "flat" or "legacy"; it is empty if it cannot be determined. The files
+// passed to the check take precedence over the project's; as with eslint,
+// a flat config takes precedence over a legacy one.
+func EslintConfigType(projectDir string, configs []string) string {
+	for _, cfg := range configs {
+		base := filepath.Base(cfg)
+		if strings.HasPrefix(base, "eslint.config.") {
+			return "flat"
+		} else if strings.HasPrefix(base, ".eslintrc") {
+			return "legacy"
+		}
+	}
+	for _, f := range EslintFlatConfigFiles {
+		if _, err := os.Stat(filepath.Join(projectDir, f)); err == nil {
+			return "flat"
+		}
+	}
+	for _, f := range EslintLegacyConfigFiles {
+		if _, err := os.Stat(filepath.Join(projectDir, f)); err == nil {
+			return "legacy"
+		}
+	}
+	pkg := struct {
+		EslintConfig json.RawMessage `json:"eslintConfig"`
+	}{}
+	data, err := os.ReadFile(filepath.Join(projectDir, "package.json"))
+	if err == nil && json.Unmarshal(data, &pkg) == nil && pkg.EslintConfig != nil {
+		return "legacy"
+	}
+	return ""
+}
+
+// EslintEnv selects the type of configuration eslint uses, since eslint 8
+// defaults to the legacy one and eslint 9 to the flat one.
+func EslintEnv(projectDir string, configs []string) []string {
+	switch EslintConfigType(projectDir, configs) {
+	case "flat":
+		return []string{"ESLINT_USE_FLAT_CONFIG=true"}
+	case "legacy":
+		return []string{"ESLINT_USE_FLAT_CONFIG=false"}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package staticanalysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/staticanalysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEslintConfigType(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	assert.Equal("", EslintConfigType(dir, nil))
+	assert.Nil(EslintEnv(dir, nil))
+
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name":"app"}`), 0644)
+	assert.Equal("", EslintConfigType(dir, nil))
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"eslintConfig":{"root":true}}`), 0644)
+	assert.Equal("legacy", EslintConfigType(dir, nil))
+
+	os.WriteFile(filepath.Join(dir, ".eslintrc.yml"), []byte("root: true"), 0644)
+	assert.Equal("legacy", EslintConfigType(dir, nil))
+	assert.Equal([]string{"ESLINT_USE_FLAT_CONFIG=false"}, EslintEnv(dir, nil))
+
+	// A flat config takes precedence over a legacy one.
+	os.WriteFile(filepath.Join(dir, "eslint.config.mjs"), []byte("export default [];"), 0644)
+	assert.Equal("flat", EslintConfigType(dir, nil))
+	assert.Equal([]string{"ESLINT_USE_FLAT_CONFIG=true"}, EslintEnv(dir, nil))
+
+	// The configuration passed to the check takes precedence.
+	assert.Equal("legacy", EslintConfigType(dir, []string{"lint/.eslintrc.json"}))
+	assert.Equal("flat", EslintConfigType(t.TempDir(), []string{"lint/eslint.config.js"}))
+	assert.Equal("flat", EslintConfigType(dir, []string{"lint/custom.js"}))
+}
@@ -0,0 +1,473 @@
+package staticanalysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// Issue is a single problem reported by a static analysis tool.
+type Issue struct {
+	File   string
+	Line   int
+	Column int
+	// End position of the issue, if reported by the tool.
+	EndLine   int
+	EndColumn int
+	Rule      string
+	Severity  IssueSeverity
+	Message   string
+}
+
+// IssueSeverity is the normalised severity of an issue across tools.
+type IssueSeverity string
+
+const (
+	IssueSeverityInfo    IssueSeverity = "info"
+	IssueSeverityWarning IssueSeverity = "warning"
+	IssueSeverityError   IssueSeverity = "error"
+)
+
+var issueSeverityLevels = map[IssueSeverity]int{
+	IssueSeverityInfo:    0,
+	IssueSeverityWarning: 1,
+	IssueSeverityError:   2,
+}
+
+// IsValid determines whether the severity is a known one.
+func (s IssueSeverity) IsValid() bool {
+	_, ok := issueSeverityLevels[s]
+	return ok
+}
+
+// AtLeast determines whether the severity is equal to or higher than min.
+func (s IssueSeverity) AtLeast(min IssueSeverity) bool {
+	return issueSeverityLevels[s] >= issueSeverityLevels[min]
+}
+
+// IssueParser converts a tool's json output into a list of issues.
+type IssueParser func(data []byte) ([]Issue, error)
+
+// ParsePhpstan parses the output of `phpstan analyse --error-format=json`.
+func ParsePhpstan(data []byte) ([]Issue, error) {
+	res := struct {
+		// Files is an empty list when there are no errors, a map otherwise.
+		Files  json.RawMessage `json:"files"`
+		Errors []string        `json:"errors"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	for _, e := range res.Errors {
+		issues = append(issues, Issue{Severity: IssueSeverityError, Message: e})
+	}
+	if len(res.Files) == 0 || string(res.Files) == "[]" {
+		return issues, nil
+	}
+
+	files := map[string]struct {
+		Messages []struct {
+			Message    string `json:"message"`
+			Line       int    `json:"line"`
+			Identifier string `json:"identifier"`
+		} `json:"messages"`
+	}{}
+	if err := json.Unmarshal(res.Files, &files); err != nil {
+		return nil, err
+	}
+	for _, f := range utils.SortedKeys(files) {
+		for _, m := range files[f].Messages {
+			issues = append(issues, Issue{
+				File:     f,
+				Line:     m.Line,
+				Rule:     m.Identifier,
+				Severity: IssueSeverityError,
+				Message:  strings.ReplaceAll(m.Message, "\n", ""),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// ParseEslint parses the output of `eslint --format=json`.
+func ParseEslint(data []byte) ([]Issue, error) {
+	res := []struct {
+		FilePath string `json:"filePath"`
+		Messages []struct {
+			RuleId   string `json:"ruleId"`
+			Severity int    `json:"severity"`
+			Message  string `json:"message"`
+			Line     int    `json:"line"`
+			Column   int    `json:"column"`
+		} `json:"messages"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	for _, f := range res {
+		for _, m := range f.Messages {
+			sev := IssueSeverityWarning
+			if m.Severity >= 2 {
+				sev = IssueSeverityError
+			}
+			issues = append(issues, Issue{
+				File:     f.FilePath,
+				Line:     m.Line,
+				Column:   m.Column,
+				Rule:     m.RuleId,
+				Severity: sev,
+				Message:  m.Message,
+			})
+		}
+	}
+	return issues, nil
+}
+
+// ParsePylint parses the output of `pylint --output-format=json`.
+func ParsePylint(data []byte) ([]Issue, error) {
+	res := []struct {
+		Type    string `json:"type"`
+		Path    string `json:"path"`
+		Line    int    `json:"line"`
+		Column  int    `json:"column"`
+		Symbol  string `json:"symbol"`
+		Message string `json:"message"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	for _, m := range res {
+		sev := IssueSeverityInfo
+		switch m.Type {
+		case "warning":
+			sev = IssueSeverityWarning
+		case "error", "fatal":
+			sev = IssueSeverityError
+		}
+		issues = append(issues, Issue{
+			File:     m.Path,
+			Line:     m.Line,
+			Column:   m.Column,
+			Rule:     m.Symbol,
+			Severity: sev,
+			Message:  m.Message,
+		})
+	}
+	return issues, nil
+}
+
+// ParseTflint parses the output of `tflint --format=json`.
+func ParseTflint(data []byte) ([]Issue, error) {
+	type tflintRange struct {
+		Filename string `json:"filename"`
+		Start    struct {
+			Line   int `json:"line"`
+			Column int `json:"column"`
+		} `json:"start"`
+	}
+	res := struct {
+		Issues []struct {
+			Rule struct {
+				Name     string `json:"name"`
+				Severity string `json:"severity"`
+			} `json:"rule"`
+			Message string      `json:"message"`
+			Range   tflintRange `json:"range"`
+		} `json:"issues"`
+		Errors []struct {
+			Message string       `json:"message"`
+			Range   *tflintRange `json:"range"`
+		} `json:"errors"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	for _, i := range res.Issues {
+		sev := IssueSeverityInfo
+		switch strings.ToLower(i.Rule.Severity) {
+		case "warning":
+			sev = IssueSeverityWarning
+		case "error":
+			sev = IssueSeverityError
+		}
+		issues = append(issues, Issue{
+			File:     i.Range.Filename,
+			Line:     i.Range.Start.Line,
+			Column:   i.Range.Start.Column,
+			Rule:     i.Rule.Name,
+			Severity: sev,
+			Message:  i.Message,
+		})
+	}
+	for _, e := range res.Errors {
+		i := Issue{Severity: IssueSeverityError, Message: e.Message}
+		if e.Range != nil {
+			i.File = e.Range.Filename
+			i.Line = e.Range.Start.Line
+			i.Column = e.Range.Start.Column
+		}
+		issues = append(issues, i)
+	}
+	return issues, nil
+}
+
+// ParseTfsec parses the output of `tfsec --format=json`.
+func ParseTfsec(data []byte) ([]Issue, error) {
+	res := struct {
+		Results []struct {
+			RuleId      string `json:"rule_id"`
+			LongId      string `json:"long_id"`
+			Description string `json:"description"`
+			Severity    string `json:"severity"`
+			Resource    string `json:"resource"`
+			Location    struct {
+				Filename  string `json:"filename"`
+				StartLine int    `json:"start_line"`
+			} `json:"location"`
+		} `json:"results"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	for _, r := range res.Results {
+		sev := IssueSeverityInfo
+		switch strings.ToUpper(r.Severity) {
+		case "MEDIUM":
+			sev = IssueSeverityWarning
+		case "HIGH", "CRITICAL":
+			sev = IssueSeverityError
+		}
+		rule := r.LongId
+		if rule == "" {
+			rule = r.RuleId
+		}
+		msg := r.Description
+		if r.Resource != "" {
+			msg = fmt.Sprintf("%s: %s", r.Resource, msg)
+		}
+		issues = append(issues, Issue{
+			File:     r.Location.Filename,
+			Line:     r.Location.StartLine,
+			Rule:     rule,
+			Severity: sev,
+			Message:  msg,
+		})
+	}
+	return issues, nil
+}
+
+// ParseSemgrep parses the output of `semgrep scan --json`.
+func ParseSemgrep(data []byte) ([]Issue, error) {
+	type semgrepPosition struct {
+		Line int `json:"line"`
+		Col  int `json:"col"`
+	}
+	res := struct {
+		Results []struct {
+			CheckId string          `json:"check_id"`
+			Path    string          `json:"path"`
+			Start   semgrepPosition `json:"start"`
+			End     semgrepPosition `json:"end"`
+			Extra   struct {
+				Message  string `json:"message"`
+				Severity string `json:"severity"`
+			} `json:"extra"`
+		} `json:"results"`
+		Errors []struct {
+			Level   string `json:"level"`
+			Message string `json:"message"`
+			Path    string `json:"path"`
+			RuleId  string `json:"rule_id"`
+		} `json:"errors"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	for _, r := range res.Results {
+		sev := IssueSeverityInfo
+		switch strings.ToUpper(r.Extra.Severity) {
+		case "WARNING", "MEDIUM":
+			sev = IssueSeverityWarning
+		case "ERROR", "HIGH", "CRITICAL":
+			sev = IssueSeverityError
+		}
+		issues = append(issues, Issue{
+			File:      r.Path,
+			Line:      r.Start.Line,
+			Column:    r.Start.Col,
+			EndLine:   r.End.Line,
+			EndColumn: r.End.Col,
+			Rule:      r.CheckId,
+			Severity:  sev,
+			Message:   strings.TrimSpace(r.Extra.Message),
+		})
+	}
+	for _, e := range res.Errors {
+		sev := IssueSeverityError
+		if e.Level == "warn" {
+			sev = IssueSeverityWarning
+		}
+		issues = append(issues, Issue{
+			File:     e.Path,
+			Rule:     e.RuleId,
+			Severity: sev,
+			Message:  strings.TrimSpace(e.Message),
+		})
+	}
+	return issues, nil
+}
+
+// ParseRubocop parses the output of `rubocop --format json`.
+func ParseRubocop(data []byte) ([]Issue, error) {
+	res := struct {
+		Files []struct {
+			Path     string `json:"path"`
+			Offenses []struct {
+				Severity string `json:"severity"`
+				Message  string `json:"message"`
+				CopName  string `json:"cop_name"`
+				Location struct {
+					StartLine   int `json:"start_line"`
+					StartColumn int `json:"start_column"`
+					LastLine    int `json:"last_line"`
+					LastColumn  int `json:"last_column"`
+				} `json:"location"`
+			} `json:"offenses"`
+		} `json:"files"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	for _, f := range res.Files {
+		for _, o := range f.Offenses {
+			sev := IssueSeverityInfo
+			switch o.Severity {
+			case "warning":
+				sev = IssueSeverityWarning
+			case "error", "fatal":
+				sev = IssueSeverityError
+			}
+			issues = append(issues, Issue{
+				File:      f.Path,
+				Line:      o.Location.StartLine,
+				Column:    o.Location.StartColumn,
+				EndLine:   o.Location.LastLine,
+				EndColumn: o.Location.LastColumn,
+				Rule:      o.CopName,
+				Severity:  sev,
+				// The cop name is already reported as the rule.
+				Message: strings.TrimPrefix(o.Message, o.CopName+": "),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// ParseStylelint parses the output of `stylelint --formatter=json`; an empty
+// output means no issue, since stylelint 16+ writes its report to stderr.
+func ParseStylelint(data []byte) ([]Issue, error) {
+	issues := []Issue{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return issues, nil
+	}
+
+	type stylelintWarning struct {
+		Line      int    `json:"line"`
+		Column    int    `json:"column"`
+		EndLine   int    `json:"endLine"`
+		EndColumn int    `json:"endColumn"`
+		Rule      string `json:"rule"`
+		Severity  string `json:"severity"`
+		Text      string `json:"text"`
+	}
+	res := []struct {
+		Source                string             `json:"source"`
+		Warnings              []stylelintWarning `json:"warnings"`
+		ParseErrors           []stylelintWarning `json:"parseErrors"`
+		InvalidOptionWarnings []struct {
+			Text string `json:"text"`
+		} `json:"invalidOptionWarnings"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	invalidOptions := []string{}
+	for _, f := range res {
+		for _, w := range append(f.ParseErrors, f.Warnings...) {
+			sev := IssueSeverityError
+			if w.Severity == "warning" {
+				sev = IssueSeverityWarning
+			}
+			issues = append(issues, Issue{
+				File:      f.Source,
+				Line:      w.Line,
+				Column:    w.Column,
+				EndLine:   w.EndLine,
+				EndColumn: w.EndColumn,
+				Rule:      w.Rule,
+				Severity:  sev,
+				// The rule is already reported separately.
+				Message: strings.TrimSuffix(w.Text, " ("+w.Rule+")"),
+			})
+		}
+		// Invalid options are reported for each file.
+		for _, o := range f.InvalidOptionWarnings {
+			if utils.StringSliceContains(invalidOptions, o.Text) {
+				continue
+			}
+			invalidOptions = append(invalidOptions, o.Text)
+			issues = append(issues, Issue{Severity: IssueSeverityError, Message: o.Text})
+		}
+	}
+	return issues, nil
+}
+
+// IsStylelintRunError determines whether stylelint's output reports an
+// error, e.g, "No configuration provided for ...", rather than problems
+// found, which are reported as a json array.
+func IsStylelintRunError(output []byte) bool {
+	return !bytes.HasPrefix(bytes.TrimSpace(output), []byte("["))
+}
+
+// ParsePrettier parses the output of `prettier --list-different`, listing
+// the files not formatted as configured; the `[warn]` lines of
+// `prettier --check` are also supported.
+func ParsePrettier(data []byte) ([]Issue, error) {
+	issues := []Issue{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "Checking formatting..." {
+			continue
+		}
+		if f, ok := strings.CutPrefix(line, "[warn] "); ok {
+			if strings.HasPrefix(f, "Code style issues ") {
+				continue
+			}
+			line = f
+		}
+		issues = append(issues, Issue{
+			File:     line,
+			Severity: IssueSeverityWarning,
+			Message:  "file is not formatted according to prettier",
+		})
+	}
+	return issues, nil
+}
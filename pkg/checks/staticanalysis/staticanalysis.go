@@ -0,0 +1,17 @@
+// Package staticanalysis provides a check running static analysis tools and
+// parsing their json output into structured issues.
+package staticanalysis
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=staticanalysis
+
+func RegisterChecks() {
+	config.ChecksRegistry[StaticAnalysis] = func() config.Check { return &StaticAnalysisCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
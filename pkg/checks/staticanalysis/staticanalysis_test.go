@@ -0,0 +1,219 @@
+package staticanalysis_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/staticanalysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePhpstan(t *testing.T) {
+	assert := assert.New(t)
+
+	issues, err := ParsePhpstan([]byte(`{"totals":{"errors":0,"file_errors":0},"files":[],"errors":[]}`))
+	assert.NoError(err)
+	assert.Empty(issues)
+
+	data, _ := os.ReadFile("testdata/phpstan.json")
+	issues, err = ParsePhpstan(data)
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{Severity: IssueSeverityError, Message: "Ignored error pattern was not matched."},
+		{File: "/app/src/Foo.php", Line: 12, Rule: "variable.undefined",
+			Severity: IssueSeverityError, Message: "Undefined variable: $bar"},
+		{File: "/app/src/Foo.php", Line: 20, Rule: "missingType.return",
+			Severity: IssueSeverityError, Message: "Method Foo::baz() has no return typespecified."},
+	}, issues)
+
+	_, err = ParsePhpstan([]byte(`{"files":`))
+	assert.EqualError(err, "unexpected end of JSON input")
+}
+
+func TestParsePhpstanBaseline(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/phpstan-baseline.neon")
+	entries, err := ParsePhpstanBaseline(data)
+	assert.NoError(err)
+	assert.Equal([]BaselineEntry{
+		{Message: `#^Undefined variable\: \$bar$#`, Identifier: "variable.undefined",
+			Count: 2, Path: "src/Foo.php"},
+		{Message: `#^Method Foo\:\:baz\(\) has no return type specified\.$#`,
+			Identifier: "missingType.return", Count: 1, Path: "src/Foo.php"},
+	}, entries)
+	assert.Equal("Undefined variable: $bar", entries[0].ReadableMessage())
+	assert.Equal("Method Foo::baz() has no return type specified.", entries[1].ReadableMessage())
+	assert.Equal("Foo", BaselineEntry{Message: "#^Bar$#", RawMessage: "Foo"}.ReadableMessage())
+
+	entries, err = ParsePhpstanBaseline(nil)
+	assert.NoError(err)
+	assert.Empty(entries)
+	_, err = ParsePhpstanBaseline([]byte("parameters: ["))
+	assert.Error(err)
+}
+
+func TestParseEslint(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/eslint.json")
+	issues, err := ParseEslint(data)
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "/app/src/index.js", Line: 1, Column: 7, Rule: "no-unused-vars",
+			Severity: IssueSeverityError, Message: "'foo' is defined but never used."},
+		{File: "/app/src/index.js", Line: 3, Column: 14, Rule: "semi",
+			Severity: IssueSeverityWarning, Message: "Missing semicolon."},
+	}, issues)
+}
+
+func TestParsePylint(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/pylint.json")
+	issues, err := ParsePylint(data)
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "app.py", Line: 1, Rule: "missing-module-docstring",
+			Severity: IssueSeverityInfo, Message: "Missing module docstring"},
+		{File: "app.py", Line: 5, Column: 4, Rule: "undefined-variable",
+			Severity: IssueSeverityError, Message: "Undefined variable 'foo'"},
+	}, issues)
+}
+
+func TestParseTflint(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/tflint.json")
+	issues, err := ParseTflint(data)
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "variables.tf", Line: 1, Column: 1, Rule: "terraform_unused_declarations",
+			Severity: IssueSeverityWarning, Message: `variable "region" is declared but not used`},
+		{File: "main.tf", Line: 3, Column: 19, Rule: "aws_instance_invalid_type",
+			Severity: IssueSeverityError, Message: `"t2.mega" is an invalid value as instance_type`},
+	}, issues)
+
+	issues, err = ParseTflint([]byte(`{"issues":[],"errors":[{"message":"Failed to load configurations","severity":"error"}]}`))
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{Severity: IssueSeverityError, Message: "Failed to load configurations"},
+	}, issues)
+}
+
+func TestParseTfsec(t *testing.T) {
+	assert := assert.New(t)
+
+	issues, err := ParseTfsec([]byte(`{"results":null}`))
+	assert.NoError(err)
+	assert.Empty(issues)
+
+	data, _ := os.ReadFile("testdata/tfsec.json")
+	issues, err = ParseTfsec(data)
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "/app/infra/main.tf", Line: 10, Rule: "aws-s3-block-public-acls", Severity: IssueSeverityError,
+			Message: "aws_s3_bucket.assets: No public access block so not blocking public acls"},
+		{File: "/app/infra/main.tf", Line: 10, Rule: "aws-s3-enable-bucket-logging", Severity: IssueSeverityWarning,
+			Message: "aws_s3_bucket.assets: Bucket does not have logging enabled"},
+	}, issues)
+}
+
+func TestParseSemgrep(t *testing.T) {
+	assert := assert.New(t)
+
+	issues, err := ParseSemgrep([]byte(`{"results":[],"errors":[]}`))
+	assert.NoError(err)
+	assert.Empty(issues)
+
+	data, _ := os.ReadFile("testdata/semgrep.json")
+	issues, err = ParseSemgrep(data)
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "src/Foo.php", Line: 5, Column: 5, EndLine: 7, EndColumn: 7, Rule: "rules.php-eval",
+			Severity: IssueSeverityError, Message: "Avoid eval() with user input."},
+		{File: "src/Foo.php", Line: 12, Column: 3, EndLine: 12, EndColumn: 20, Rule: "rules.php-debug",
+			Severity: IssueSeverityWarning, Message: "Debug output left in code."},
+		{File: "src/Bar.php", Severity: IssueSeverityWarning, Message: "Syntax error at line src/Bar.php:3"},
+	}, issues)
+}
+
+func TestParseRubocop(t *testing.T) {
+	assert := assert.New(t)
+
+	issues, err := ParseRubocop([]byte(`{"files":[]}`))
+	assert.NoError(err)
+	assert.Empty(issues)
+
+	data, _ := os.ReadFile("testdata/rubocop.json")
+	issues, err = ParseRubocop(data)
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "app/models/user.rb", Line: 1, Column: 1, EndLine: 1, EndColumn: 1, Rule: "Style/FrozenStringLiteralComment",
+			Severity: IssueSeverityInfo, Message: "Missing frozen string literal comment."},
+		{File: "app/models/user.rb", Line: 8, Column: 5, EndLine: 8, EndColumn: 8, Rule: "Lint/UselessAssignment",
+			Severity: IssueSeverityWarning, Message: "Useless assignment to variable - `name`."},
+		{File: "lib/tasks/import.rb", Line: 12, Column: 1, EndLine: 12, EndColumn: 3, Rule: "Lint/Syntax",
+			Severity: IssueSeverityError, Message: "unexpected token kEND"},
+	}, issues)
+}
+
+func TestParseStylelint(t *testing.T) {
+	assert := assert.New(t)
+
+	issues, err := ParseStylelint([]byte(""))
+	assert.NoError(err)
+	assert.Empty(issues)
+
+	data, _ := os.ReadFile("testdata/stylelint.json")
+	issues, err = ParseStylelint(data)
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "/app/src/styles.css", Line: 3, Column: 10, EndLine: 3, EndColumn: 14, Rule: "color-no-invalid-hex",
+			Severity: IssueSeverityError, Message: "Unexpected invalid hex color \"#ggg\""},
+		{File: "/app/src/styles.css", Line: 7, Column: 1, Rule: "block-no-empty",
+			Severity: IssueSeverityWarning, Message: "Unexpected empty block"},
+		{Severity: IssueSeverityError, Message: "Invalid option value \"foo\" for rule \"color-named\""},
+	}, issues)
+}
+
+func TestParsePrettier(t *testing.T) {
+	assert := assert.New(t)
+
+	issues, err := ParsePrettier([]byte(""))
+	assert.NoError(err)
+	assert.Empty(issues)
+
+	issues, err = ParsePrettier([]byte("src/index.js\nsrc/styles.css\n"))
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "src/index.js", Severity: IssueSeverityWarning, Message: "file is not formatted according to prettier"},
+		{File: "src/styles.css", Severity: IssueSeverityWarning, Message: "file is not formatted according to prettier"},
+	}, issues)
+
+	// Output of --check.
+	issues, err = ParsePrettier([]byte("Checking formatting...\n[warn] src/index.js\n" +
+		"[warn] Code style issues found in the above file. Run Prettier with --write to fix.\n"))
+	assert.NoError(err)
+	assert.Equal([]Issue{
+		{File: "src/index.js", Severity: IssueSeverityWarning, Message: "file is not formatted according to prettier"},
+	}, issues)
+}
+
+func TestIsStylelintRunError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(IsStylelintRunError([]byte("[]\n")))
+	assert.True(IsStylelintRunError([]byte("Error: No configuration provided for /app/src/styles.css\n")))
+	assert.True(IsStylelintRunError([]byte("ConfigurationError: Could not find \"stylelint-config-standard\"")))
+}
+
+func TestIssueSeverity(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IssueSeverityWarning.IsValid())
+	assert.False(IssueSeverity("critical").IsValid())
+	assert.True(IssueSeverityError.AtLeast(IssueSeverityWarning))
+	assert.True(IssueSeverityWarning.AtLeast(IssueSeverityWarning))
+	assert.False(IssueSeverityInfo.AtLeast(IssueSeverityWarning))
+}
@@ -0,0 +1,509 @@
+package staticanalysis
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const StaticAnalysis config.CheckType = "static-analysis"
+
+// ToolDefault holds the defaults for running a supported tool and parsing
+// its output.
+type ToolDefault struct {
+	// Binary path, relative to the project directory if it contains a
+	// separator, otherwise looked up in $PATH.
+	Bin  string
+	Args []string
+	// Whether the tool only analyses a single directory per run, in which
+	// case it is run for each path.
+	SinglePath bool
+	// Argument prefix used to pass the path, e.g, --chdir=; the path is
+	// passed as a positional argument if empty.
+	PathArg string
+	// Argument prefix used to pass the tool's configuration, e.g, --config=.
+	ConfigArg string
+	// Exit codes of a successful run, e.g, when issues are found; if empty,
+	// the tool is only considered as failed if there is no output.
+	SuccessExitCodes []int
+	// Name of the gem providing the tool; the tool is run through
+	// `bundle exec` if the project's Gemfile.lock includes it.
+	BundlerGem string
+	// Name of the npm package providing the tool; the tool is run through
+	// `npx` if its binary is not installed in the project.
+	NpxPackage string
+	// Whether the tool may write its report to stderr, in which case it is
+	// read from there when there is none on stdout.
+	StderrReport bool
+	// Determines whether the output of a run exiting with a non-zero code
+	// reports an error, e.g, an invalid configuration, rather than issues.
+	IsRunError func(output []byte) bool
+	// Binary of a daemonised variant of the tool, used instead when the
+	// check's daemon is set; it is looked up in $PATH if it is not installed
+	// in the project.
+	DaemonBin string
+	// Argument prefix used to write a baseline of the current issues to a
+	// file, e.g, --generate-baseline=; baselines are only supported if set.
+	BaselineArg string
+	// Arguments added to the tool's to fix the issues in place, e.g,
+	// --write; remediation is only supported if set.
+	FixArgs []string
+	// Determines the environment variables the tool is run with, e.g,
+	// depending on the project's configuration files.
+	Env    func(projectDir string, configs []string) []string
+	Parser IssueParser
+}
+
+// ToolDefaults is the list of supported tools.
+var ToolDefaults = map[string]ToolDefault{
+	"phpstan": {
+		Bin:         "vendor/bin/phpstan",
+		Args:        []string{"analyse", "--no-progress", "--error-format=json"},
+		ConfigArg:   "--configuration=",
+		BaselineArg: "--generate-baseline=",
+		Parser:      ParsePhpstan,
+	},
+	"eslint": {
+		Bin:       "node_modules/.bin/eslint",
+		Args:      []string{"--format=json"},
+		ConfigArg: "--config=",
+		DaemonBin: "node_modules/.bin/eslint_d",
+		Env:       EslintEnv,
+		Parser:    ParseEslint,
+	},
+	"pylint": {
+		Bin:       "pylint",
+		Args:      []string{"--output-format=json"},
+		ConfigArg: "--rcfile=",
+		Parser:    ParsePylint,
+	},
+	"tflint": {
+		Bin:        "tflint",
+		Args:       []string{"--format=json"},
+		SinglePath: true,
+		PathArg:    "--chdir=",
+		ConfigArg:  "--config=",
+		Parser:     ParseTflint,
+	},
+	"tfsec": {
+		Bin:        "tfsec",
+		Args:       []string{"--format=json", "--no-colour"},
+		SinglePath: true,
+		ConfigArg:  "--config-file=",
+		Parser:     ParseTfsec,
+	},
+	"semgrep": {
+		Bin:       "semgrep",
+		Args:      []string{"scan", "--json", "--quiet"},
+		ConfigArg: "--config=",
+		Parser:    ParseSemgrep,
+	},
+	"rubocop": {
+		Bin:              "rubocop",
+		Args:             []string{"--format", "json"},
+		ConfigArg:        "--config=",
+		SuccessExitCodes: []int{0, 1},
+		BundlerGem:       "rubocop",
+		Parser:           ParseRubocop,
+	},
+	"stylelint": {
+		Bin:          "node_modules/.bin/stylelint",
+		Args:         []string{"--formatter=json"},
+		ConfigArg:    "--config=",
+		NpxPackage:   "stylelint",
+		StderrReport: true,
+		IsRunError:   IsStylelintRunError,
+		Parser:       ParseStylelint,
+	},
+	"prettier": {
+		Bin:              "node_modules/.bin/prettier",
+		Args:             []string{"--list-different"},
+		ConfigArg:        "--config=",
+		SuccessExitCodes: []int{0, 1},
+		NpxPackage:       "prettier",
+		FixArgs:          []string{"--write"},
+		Parser:           ParsePrettier,
+	},
+}
+
+// StaticAnalysisCheck runs a static analysis tool and reports its issues
+// grouped per file.
+type StaticAnalysisCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// One of the ToolDefaults keys, e.g, phpstan.
+	Tool string `yaml:"tool"`
+	// Overrides the tool's default binary.
+	Bin string `yaml:"binary"`
+	// Configuration passed to the tool, e.g, a ruleset for semgrep.
+	Config []string `yaml:"config"`
+	// Additional arguments passed to the tool.
+	Args  []string `yaml:"args"`
+	Paths []string `yaml:"paths"`
+	// Issues with a lower severity (info, warning, error) are ignored.
+	MinSeverity IssueSeverity `yaml:"min-severity"`
+	// List of rule identifiers for which issues are ignored.
+	IgnoreRules []string `yaml:"ignore-rules"`
+	// Run the tool's daemonised variant, e.g, eslint_d, so that repeated
+	// runs are faster.
+	Daemon bool `yaml:"daemon"`
+	// Baseline of the known issues, e.g, phpstan-baseline.neon, relative to
+	// the project directory; only the issues not in it are then reported.
+	Baseline string `yaml:"baseline"`
+	// check (default) compares the issues with the baseline, generate writes
+	// the baseline instead.
+	BaselineMode      string `yaml:"baseline-mode"`
+	issues            []Issue
+	generatedBaseline string
+	baselineEntries   []BaselineEntry
+	generatedEntries  []BaselineEntry
+}
+
+// Merge implementation for static-analysis check.
+func (c *StaticAnalysisCheck) Merge(mergeCheck config.Check) error {
+	staticAnalysisMergeCheck := mergeCheck.(*StaticAnalysisCheck)
+	if err := c.CheckBase.Merge(&staticAnalysisMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	utils.MergeString(&c.Tool, staticAnalysisMergeCheck.Tool)
+	utils.MergeString(&c.Bin, staticAnalysisMergeCheck.Bin)
+	utils.MergeStringSlice(&c.Config, staticAnalysisMergeCheck.Config)
+	utils.MergeStringSlice(&c.Args, staticAnalysisMergeCheck.Args)
+	utils.MergeStringSlice(&c.Paths, staticAnalysisMergeCheck.Paths)
+	if staticAnalysisMergeCheck.MinSeverity != "" {
+		c.MinSeverity = staticAnalysisMergeCheck.MinSeverity
+	}
+	utils.MergeStringSlice(&c.IgnoreRules, staticAnalysisMergeCheck.IgnoreRules)
+	if staticAnalysisMergeCheck.Daemon {
+		c.Daemon = true
+	}
+	utils.MergeString(&c.Baseline, staticAnalysisMergeCheck.Baseline)
+	utils.MergeString(&c.BaselineMode, staticAnalysisMergeCheck.BaselineMode)
+	return nil
+}
+
+// GetBinary determines the path of the tool's binary.
+func (c *StaticAnalysisCheck) GetBinary() string {
+	if c.Bin != "" {
+		return c.Bin
+	}
+	bin := ToolDefaults[c.Tool].Bin
+	if c.Daemon {
+		bin = ToolDefaults[c.Tool].DaemonBin
+		// Daemons are commonly installed globally.
+		if _, err := os.Stat(filepath.Join(config.ProjectDir, bin)); err != nil {
+			return filepath.Base(bin)
+		}
+	}
+	if strings.Contains(bin, "/") {
+		return filepath.Join(config.ProjectDir, bin)
+	}
+	return bin
+}
+
+// GetCommand determines the binary and the arguments preceding the tool's
+// own to run it, using bundler if the project's Gemfile.lock includes the
+// tool's gem, or npx if the tool's npm package is not installed. The tool is
+// run through env when it requires environment variables.
+func (c *StaticAnalysisCheck) GetCommand() (string, []string) {
+	bin, args := c.baseCommand()
+	tool := ToolDefaults[c.Tool]
+	if tool.Env == nil {
+		return bin, args
+	}
+	env := tool.Env(config.ProjectDir, c.Config)
+	if len(env) == 0 {
+		return bin, args
+	}
+	return "env", append(append(env, bin), args...)
+}
+
+func (c *StaticAnalysisCheck) baseCommand() (string, []string) {
+	tool := ToolDefaults[c.Tool]
+	if c.Bin == "" && tool.NpxPackage != "" {
+		if _, err := os.Stat(c.GetBinary()); err != nil {
+			return "npx", []string{"--no", tool.NpxPackage}
+		}
+	}
+	if c.Bin == "" && tool.BundlerGem != "" {
+		gemfile := filepath.Join(config.ProjectDir, "Gemfile")
+		lock, err := os.ReadFile(gemfile + ".lock")
+		gemRegex := regexp.MustCompile(`(?m)^\s+` + regexp.QuoteMeta(tool.BundlerGem) + `( \(|$)`)
+		if err == nil && gemRegex.Match(lock) {
+			return "env", []string{"BUNDLE_GEMFILE=" + gemfile, "bundle", "exec", tool.Bin}
+		}
+	}
+	return c.GetBinary(), nil
+}
+
+// FetchData runs the tool to populate data for the check.
+func (c *StaticAnalysisCheck) FetchData() {
+	if _, ok := ToolDefaults[c.Tool]; !ok {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unsupported tool",
+			Value:      c.Tool})
+		return
+	}
+	if c.Daemon && ToolDefaults[c.Tool].DaemonBin == "" {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "daemon not supported",
+			Value:      c.Tool})
+		return
+	}
+	if c.MinSeverity != "" && !c.MinSeverity.IsValid() {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid min-severity",
+			Value:      string(c.MinSeverity)})
+		return
+	}
+	if c.Baseline != "" && !c.validBaseline() {
+		return
+	}
+
+	runs := c.toolArgs()
+	if len(runs) == 0 {
+		c.Result.Status = result.Pass
+		c.AddPass(fmt.Sprintf("no paths found to run %s on", c.Tool))
+		return
+	}
+
+	c.DataMap = map[string][]byte{}
+	if c.Baseline != "" && c.BaselineMode != BaselineModeGenerate {
+		c.fetchBaselines()
+		return
+	}
+	for _, k := range utils.SortedKeys(runs) {
+		c.runTool(k, runs[k])
+	}
+}
+
+// toolArgs determines the tool's arguments for each of its runs, keyed by
+// the data key; tools only supporting a single path are run for each path.
+func (c *StaticAnalysisCheck) toolArgs() map[string][]string {
+	tool := ToolDefaults[c.Tool]
+	args := append([]string{}, tool.Args...)
+	for _, cfg := range c.Config {
+		args = append(args, tool.ConfigArg+cfg)
+	}
+	args = append(args, c.Args...)
+	if c.Baseline != "" && tool.BaselineArg != "" {
+		args = append(args, tool.BaselineArg+c.baselineTarget())
+	}
+	paths := map[string]string{}
+	for _, p := range c.Paths {
+		path := p
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(config.ProjectDir, p)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			paths[p] = tool.PathArg + path
+		}
+	}
+
+	runs := map[string][]string{}
+	if len(paths) == 0 {
+		return runs
+	}
+	if !tool.SinglePath {
+		for _, p := range utils.SortedKeys(paths) {
+			args = append(args, paths[p])
+		}
+		runs[c.Tool] = args
+		return runs
+	}
+	for _, p := range utils.SortedKeys(paths) {
+		runs[p] = append(append([]string{}, args...), paths[p])
+	}
+	return runs
+}
+
+// Commands implements config.CommandReporter.
+func (c *StaticAnalysisCheck) Commands() [][]string {
+	if _, ok := ToolDefaults[c.Tool]; !ok {
+		return nil
+	}
+	bin, cmdArgs := c.GetCommand()
+	runs := c.toolArgs()
+	cmds := [][]string{}
+	for _, k := range utils.SortedKeys(runs) {
+		cmds = append(cmds, append(append([]string{bin}, cmdArgs...), runs[k]...))
+	}
+	return cmds
+}
+
+// runTool executes the tool and stores its output in the DataMap.
+func (c *StaticAnalysisCheck) runTool(dataKey string, args []string) {
+	var err error
+	tool := ToolDefaults[c.Tool]
+	bin, cmdArgs := c.GetCommand()
+	c.DataMap[dataKey], err = command.ShellCommander(bin, append(cmdArgs, args...)...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		isExitErr := errors.As(err, &exitErr)
+		if isExitErr && tool.StderrReport && len(c.DataMap[dataKey]) == 0 {
+			c.DataMap[dataKey] = exitErr.Stderr
+		}
+		if pathErr, ok := err.(*fs.PathError); ok {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: pathErr.Path,
+				Value:      pathErr.Err.Error()})
+		} else if isExitErr && len(tool.SuccessExitCodes) > 0 &&
+			!utils.IntSliceContains(tool.SuccessExitCodes, exitErr.ExitCode()) {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: c.Tool + " failed to run",
+				Value:      command.GetMsgFromCommandError(err)})
+		} else if isExitErr && tool.IsRunError != nil && len(c.DataMap[dataKey]) > 0 &&
+			tool.IsRunError(c.DataMap[dataKey]) {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: c.Tool + " failed to run",
+				Value:      strings.TrimSpace(string(c.DataMap[dataKey]))})
+		} else if len(c.DataMap[dataKey]) == 0 {
+			// Tools exit with a non-zero code when issues are found, so only
+			// fail if there is no output.
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: c.Tool + " failed to run",
+				Value:      command.GetMsgFromCommandError(err)})
+		}
+	}
+}
+
+// HasData is overridden here to prevent the check from failing if there is no
+// path for the tool to scan.
+func (c *StaticAnalysisCheck) HasData(failCheck bool) bool {
+	if c.DataMap == nil && len(c.Result.Passes) == 0 {
+		if failCheck {
+			c.AddBreach(&result.ValueBreach{Value: "no data available"})
+		}
+		return false
+	}
+	return true
+}
+
+// UnmarshalDataMap parses the tool's output into issues.
+// For tools run per path, relative file names are resolved against the path.
+func (c *StaticAnalysisCheck) UnmarshalDataMap() {
+	if c.Result.Status == result.Pass {
+		return
+	}
+
+	if c.Baseline != "" {
+		c.unmarshalBaselines()
+		return
+	}
+
+	tool := ToolDefaults[c.Tool]
+	c.issues = []Issue{}
+	for _, dataKey := range utils.SortedKeys(c.DataMap) {
+		issues, err := tool.Parser(c.DataMap[dataKey])
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: fmt.Sprintf("unable to parse %s result", c.Tool),
+				Value:      err.Error()})
+			return
+		}
+		for _, i := range issues {
+			if tool.SinglePath && i.File != "" && !filepath.IsAbs(i.File) {
+				i.File = filepath.Join(dataKey, i.File)
+			}
+			c.issues = append(c.issues, i)
+		}
+	}
+}
+
+// RunCheck filters the issues and reports them per file.
+func (c *StaticAnalysisCheck) RunCheck() {
+	if c.Baseline != "" {
+		c.runBaselineCheck()
+		return
+	}
+
+	minSeverity := c.MinSeverity
+	if minSeverity == "" {
+		minSeverity = IssueSeverityInfo
+	}
+
+	fileIssues := map[string][]string{}
+	for _, i := range c.issues {
+		if !i.Severity.AtLeast(minSeverity) {
+			continue
+		}
+		if i.Rule != "" && utils.StringSliceMatchAny(c.IgnoreRules, i.Rule) {
+			continue
+		}
+		fileIssues[i.File] = append(fileIssues[i.File], formatIssue(i))
+	}
+
+	if len(fileIssues) == 0 {
+		c.AddPass("no issue found")
+		c.Result.Status = result.Pass
+		return
+	}
+
+	for _, f := range utils.SortedKeys(fileIssues) {
+		key := fmt.Sprintf("file: %s", f)
+		if f == "" {
+			key = fmt.Sprintf("errors encountered when running %s", c.Tool)
+		}
+		c.AddBreach(&result.KeyValuesBreach{
+			Key:    key,
+			Values: fileIssues[f],
+		})
+	}
+}
+
+// Remediate runs the tool with its fix arguments over the check's paths, e.g,
+// prettier --write, if the tool supports fixing the issues.
+func (c *StaticAnalysisCheck) Remediate() {
+	tool := ToolDefaults[c.Tool]
+	if len(tool.FixArgs) == 0 || c.Baseline != "" || c.Result.DataError {
+		c.CheckBase.Remediate()
+		return
+	}
+
+	bin, cmdArgs := c.GetCommand()
+	runs := c.toolArgs()
+	errs := []string{}
+	for _, k := range utils.SortedKeys(runs) {
+		args := append(append(append([]string{}, cmdArgs...), runs[k]...), tool.FixArgs...)
+		_, err := command.ShellCommander(bin, args...).Output()
+		var exitErr *exec.ExitError
+		if err != nil && !(errors.As(err, &exitErr) &&
+			utils.IntSliceContains(tool.SuccessExitCodes, exitErr.ExitCode())) {
+			errs = append(errs, command.GetMsgFromCommandError(err))
+		}
+	}
+
+	for _, b := range c.Result.Breaches {
+		if len(errs) > 0 {
+			b.SetRemediation(result.RemediationStatusFailed, fmt.Sprintf(
+				"error fixing the issues using %s: %s", c.Tool, strings.Join(errs, "; ")))
+		} else {
+			b.SetRemediation(result.RemediationStatusSuccess, fmt.Sprintf(
+				"fixed the issues using %s", c.Tool))
+		}
+	}
+}
+
+func formatIssue(i Issue) string {
+	msg := fmt.Sprintf("[%s] %s", i.Severity, i.Message)
+	if i.Rule != "" {
+		msg += fmt.Sprintf(" (%s)", i.Rule)
+	}
+	if i.File == "" || i.Line == 0 {
+		return msg
+	}
+	if i.EndLine > i.Line {
+		return fmt.Sprintf("lines %d-%d: %s", i.Line, i.EndLine, msg)
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, msg)
+}
@@ -0,0 +1,579 @@
+package staticanalysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/staticanalysis"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[StaticAnalysis]()
+	assert.Equal(t, "*staticanalysis.StaticAnalysisCheck", reflect.TypeOf(c).String())
+}
+
+func TestStaticAnalysisCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := StaticAnalysisCheck{
+		Tool:        "eslint",
+		Paths:       []string{"src"},
+		IgnoreRules: []string{"semi"},
+	}
+	err := c.Merge(&StaticAnalysisCheck{
+		Bin:         "/usr/bin/eslint",
+		MinSeverity: IssueSeverityError,
+		Daemon:      true,
+	})
+	assert.Nil(err)
+	assert.EqualValues(StaticAnalysisCheck{
+		Tool:        "eslint",
+		Bin:         "/usr/bin/eslint",
+		Paths:       []string{"src"},
+		MinSeverity: IssueSeverityError,
+		IgnoreRules: []string{"semi"},
+		Daemon:      true,
+	}, c)
+}
+
+func TestStaticAnalysisCheckGetBinary(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = "/app"
+	defer func() { config.ProjectDir = "" }()
+
+	c := StaticAnalysisCheck{Tool: "phpstan"}
+	assert.Equal("/app/vendor/bin/phpstan", c.GetBinary())
+	c = StaticAnalysisCheck{Tool: "pylint"}
+	assert.Equal("pylint", c.GetBinary())
+	c = StaticAnalysisCheck{Tool: "pylint", Bin: "/venv/bin/pylint"}
+	assert.Equal("/venv/bin/pylint", c.GetBinary())
+
+	// Daemons are used from $PATH unless installed in the project.
+	c = StaticAnalysisCheck{Tool: "eslint", Daemon: true}
+	assert.Equal("eslint_d", c.GetBinary())
+	config.ProjectDir = t.TempDir()
+	binDir := filepath.Join(config.ProjectDir, "node_modules", ".bin")
+	os.MkdirAll(binDir, 0755)
+	os.WriteFile(filepath.Join(binDir, "eslint_d"), []byte(""), 0755)
+	assert.Equal(filepath.Join(binDir, "eslint_d"), c.GetBinary())
+}
+
+func TestStaticAnalysisCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	c := StaticAnalysisCheck{Tool: "psalm"}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "unsupported tool",
+		Value:      "psalm",
+	}}, c.Result.Breaches)
+
+	c = StaticAnalysisCheck{Tool: "phpstan", Daemon: true}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "daemon not supported",
+		Value:      "phpstan",
+	}}, c.Result.Breaches)
+
+	c = StaticAnalysisCheck{Tool: "eslint", Paths: []string{"non-existent"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal(result.Pass, c.Result.Status)
+	assert.Equal([]string{"no paths found to run eslint on"}, c.Result.Passes)
+	assert.True(c.HasData(true))
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{"[]"}[0], nil, &generatedCommand)
+	c = StaticAnalysisCheck{
+		Tool:  "eslint",
+		Args:  []string{"--max-warnings=0"},
+		Paths: []string{"src"},
+	}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("testdata/node_modules/.bin/eslint --format=json --max-warnings=0 testdata/src", generatedCommand)
+	assert.Equal([]byte("[]"), c.DataMap["eslint"])
+
+	generatedCommands := []string{}
+	command.ShellCommander = func(name string, arg ...string) command.IShellCommand {
+		generatedCommands = append(generatedCommands, name+" "+strings.Join(arg, " "))
+		return internal.TestShellCommand{OutputterFunc: func() ([]byte, error) {
+			return []byte(`{"issues":[],"errors":[]}`), nil
+		}}
+	}
+	c = StaticAnalysisCheck{Tool: "tflint", Paths: []string{"src", "infra", "non-existent"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal([]string{
+		"tflint --format=json --chdir=testdata/infra",
+		"tflint --format=json --chdir=testdata/src",
+	}, generatedCommands)
+	assert.Len(c.DataMap, 2)
+	assert.Contains(c.DataMap, "infra")
+	assert.Contains(c.DataMap, "src")
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{`{"results":[]}`}[0], nil, &generatedCommand)
+	c = StaticAnalysisCheck{
+		Tool:   "semgrep",
+		Config: []string{"p/php", "rules"},
+		Paths:  []string{"src"},
+	}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("semgrep scan --json --quiet --config=p/php --config=rules testdata/src", generatedCommand)
+}
+
+func TestStaticAnalysisCheckGetCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	c := StaticAnalysisCheck{Tool: "rubocop"}
+	bin, args := c.GetCommand()
+	assert.Equal("rubocop", bin)
+	assert.Empty(args)
+
+	config.ProjectDir = "testdata/bundler"
+	bin, args = c.GetCommand()
+	assert.Equal("env", bin)
+	assert.Equal([]string{"BUNDLE_GEMFILE=testdata/bundler/Gemfile", "bundle", "exec", "rubocop"}, args)
+
+	c = StaticAnalysisCheck{Tool: "rubocop", Bin: "bin/rubocop"}
+	bin, args = c.GetCommand()
+	assert.Equal("bin/rubocop", bin)
+	assert.Empty(args)
+}
+
+func TestStaticAnalysisCheckGetCommandEslint(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = t.TempDir()
+	defer func() { config.ProjectDir = "" }()
+
+	c := StaticAnalysisCheck{Tool: "eslint"}
+	bin, args := c.GetCommand()
+	assert.Equal(filepath.Join(config.ProjectDir, "node_modules/.bin/eslint"), bin)
+	assert.Empty(args)
+
+	os.WriteFile(filepath.Join(config.ProjectDir, "eslint.config.js"), []byte(""), 0644)
+	bin, args = c.GetCommand()
+	assert.Equal("env", bin)
+	assert.Equal([]string{"ESLINT_USE_FLAT_CONFIG=true",
+		filepath.Join(config.ProjectDir, "node_modules/.bin/eslint")}, args)
+
+	c = StaticAnalysisCheck{Tool: "eslint", Config: []string{".eslintrc.json"}, Daemon: true}
+	bin, args = c.GetCommand()
+	assert.Equal("env", bin)
+	assert.Equal([]string{"ESLINT_USE_FLAT_CONFIG=false", "eslint_d"}, args)
+}
+
+func TestStaticAnalysisCheckGetCommandNpx(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = t.TempDir()
+	defer func() { config.ProjectDir = "" }()
+
+	c := StaticAnalysisCheck{Tool: "stylelint"}
+	bin, args := c.GetCommand()
+	assert.Equal("npx", bin)
+	assert.Equal([]string{"--no", "stylelint"}, args)
+
+	binDir := filepath.Join(config.ProjectDir, "node_modules", ".bin")
+	os.MkdirAll(binDir, 0755)
+	os.WriteFile(filepath.Join(binDir, "stylelint"), []byte(""), 0755)
+	bin, args = c.GetCommand()
+	assert.Equal(filepath.Join(binDir, "stylelint"), bin)
+	assert.Empty(args)
+}
+
+func TestStaticAnalysisCheckFetchDataStylelint(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	// Stylelint 16+ writes the report to stderr, exiting with 2 when
+	// problems are found.
+	bin := filepath.Join(t.TempDir(), "stylelint")
+	os.WriteFile(bin, []byte("#!/bin/sh\necho '[]' >&2\nexit 2\n"), 0755)
+	c := StaticAnalysisCheck{Tool: "stylelint", Bin: bin, Paths: []string{"src"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("[]\n", string(c.DataMap["stylelint"]))
+
+	// Configuration errors are not parsed as problems.
+	os.WriteFile(bin, []byte("#!/bin/sh\necho 'Error: No configuration provided for src/a.css' >&2\nexit 78\n"), 0755)
+	c = StaticAnalysisCheck{Tool: "stylelint", Bin: bin, Paths: []string{"src"}}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "stylelint failed to run",
+		Value:      "Error: No configuration provided for src/a.css",
+	}}, c.Result.Breaches)
+}
+
+func TestStaticAnalysisCheckFetchDataExitCodes(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	// Rubocop exits with 1 when offenses are found.
+	bin := filepath.Join(t.TempDir(), "rubocop")
+	os.WriteFile(bin, []byte("#!/bin/sh\necho '{\"files\":[]}'\nexit 1\n"), 0755)
+	c := StaticAnalysisCheck{Tool: "rubocop", Bin: bin, Paths: []string{"src"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("{\"files\":[]}\n", string(c.DataMap["rubocop"]))
+
+	// Other exit codes are failures, even with output.
+	os.WriteFile(bin, []byte("#!/bin/sh\necho '{\"files\":[]}'\necho 'invalid config' >&2\nexit 2\n"), 0755)
+	c = StaticAnalysisCheck{Tool: "rubocop", Bin: bin, Paths: []string{"src"}}
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "rubocop failed to run",
+		Value:      "invalid config\n",
+	}}, c.Result.Breaches)
+}
+
+func TestStaticAnalysisCheckRunCheck(t *testing.T) {
+	eslintData, _ := os.ReadFile("testdata/eslint.json")
+	phpstanData, _ := os.ReadFile("testdata/phpstan.json")
+	tflintData, _ := os.ReadFile("testdata/tflint.json")
+	semgrepData, _ := os.ReadFile("testdata/semgrep.json")
+	rubocopData, _ := os.ReadFile("testdata/rubocop.json")
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "noIssues",
+			Check: &StaticAnalysisCheck{
+				Tool: "eslint",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"eslint": []byte("[]")}},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"no issue found"},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "issuesFiltered",
+			Check: &StaticAnalysisCheck{
+				Tool: "eslint",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"eslint": eslintData}},
+				MinSeverity: IssueSeverityWarning,
+				IgnoreRules: []string{"no-unused-vars"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.KeyValuesBreach{
+				BreachType: "key-values",
+				Key:        "file: /app/src/index.js",
+				Values:     []string{"line 3: [warning] Missing semicolon. (semi)"},
+			}},
+		},
+		{
+			Name: "issuesPerFile",
+			Check: &StaticAnalysisCheck{
+				Tool: "phpstan",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"phpstan": phpstanData}},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					Key:        "errors encountered when running phpstan",
+					Values:     []string{"[error] Ignored error pattern was not matched."},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					Key:        "file: /app/src/Foo.php",
+					Values: []string{
+						"line 12: [error] Undefined variable: $bar (variable.undefined)",
+						"line 20: [error] Method Foo::baz() has no return typespecified. (missingType.return)",
+					},
+				},
+			},
+		},
+		{
+			Name: "issuesPerPath",
+			Check: &StaticAnalysisCheck{
+				Tool: "tflint",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"infra": tflintData}},
+				IgnoreRules: []string{"terraform_unused_declarations"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.KeyValuesBreach{
+				BreachType: "key-values",
+				Key:        "file: infra/main.tf",
+				Values: []string{
+					`line 3: [error] "t2.mega" is an invalid value as instance_type (aws_instance_invalid_type)`,
+				},
+			}},
+		},
+		{
+			Name: "issuesWithRange",
+			Check: &StaticAnalysisCheck{
+				Tool: "semgrep",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"semgrep": semgrepData}},
+				MinSeverity: IssueSeverityWarning,
+				IgnoreRules: []string{"rules.php-debug"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					Key:        "file: src/Bar.php",
+					Values:     []string{"[warning] Syntax error at line src/Bar.php:3"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					Key:        "file: src/Foo.php",
+					Values:     []string{"lines 5-7: [error] Avoid eval() with user input. (rules.php-eval)"},
+				},
+			},
+		},
+		{
+			Name: "rubocopOffenses",
+			Check: &StaticAnalysisCheck{
+				Tool: "rubocop",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"rubocop": rubocopData}},
+				IgnoreRules: []string{"glob:Style/*"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					Key:        "file: app/models/user.rb",
+					Values:     []string{"line 8: [warning] Useless assignment to variable - `name`. (Lint/UselessAssignment)"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					Key:        "file: lib/tasks/import.rb",
+					Values:     []string{"line 12: [error] unexpected token kEND (Lint/Syntax)"},
+				},
+			},
+		},
+		{
+			Name: "invalidOutput",
+			Check: &StaticAnalysisCheck{
+				Tool: "pylint",
+				CheckBase: config.CheckBase{
+					DataMap: map[string][]byte{"pylint": []byte("Traceback")}},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				ValueLabel: "unable to parse pylint result",
+				Value:      "invalid character 'T' looking for beginning of value",
+			}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.UnmarshalDataMap()
+			if len(tc.Check.GetResult().Breaches) > 0 {
+				tc.Check.GetResult().DetermineResultStatus(false)
+				assert.Equal(t, tc.ExpectStatus, tc.Check.GetResult().Status)
+				assert.EqualValues(t, tc.ExpectFails, tc.Check.GetResult().Breaches)
+				return
+			}
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
+
+func TestStaticAnalysisCheckBaseline(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = t.TempDir()
+	defer func() { config.ProjectDir = "" }()
+	baseline, _ := os.ReadFile("testdata/phpstan-baseline.neon")
+	os.WriteFile(filepath.Join(config.ProjectDir, "phpstan-baseline.neon"), baseline, 0644)
+	os.Mkdir(filepath.Join(config.ProjectDir, "src"), 0755)
+
+	// Writes the generated baseline to the file passed to it.
+	bin := filepath.Join(t.TempDir(), "phpstan")
+	generated := filepath.Join(t.TempDir(), "generated.neon")
+	os.WriteFile(bin, []byte(`#!/bin/sh
+for a in "$@"; do
+  case "$a" in --generate-baseline=*) [ -f `+generated+` ] && cp `+generated+` "${a#--generate-baseline=}";; esac
+done
+exit 0
+`), 0755)
+
+	newCheck := func(mode string) *StaticAnalysisCheck {
+		c := &StaticAnalysisCheck{Tool: "phpstan", Bin: bin, Paths: []string{"src"},
+			Baseline: "phpstan-baseline.neon", BaselineMode: mode}
+		c.Init(StaticAnalysis)
+		return c
+	}
+	run := func(c *StaticAnalysisCheck) {
+		c.FetchData()
+		if len(c.Result.Breaches) == 0 {
+			c.UnmarshalDataMap()
+			c.RunCheck()
+		}
+	}
+
+	t.Run("commands", func(t *testing.T) {
+		assert.Equal([][]string{{bin, "analyse", "--no-progress", "--error-format=json",
+			"--generate-baseline=<generated baseline>", filepath.Join(config.ProjectDir, "src")}},
+			newCheck("").Commands())
+		assert.Equal([][]string{{bin, "analyse", "--no-progress", "--error-format=json",
+			"--generate-baseline=" + filepath.Join(config.ProjectDir, "phpstan-baseline.neon"),
+			filepath.Join(config.ProjectDir, "src")}},
+			newCheck(BaselineModeGenerate).Commands())
+	})
+
+	t.Run("noNewIssues", func(t *testing.T) {
+		// Only the first error remains.
+		os.WriteFile(generated, []byte(strings.SplitN(string(baseline), "\n\n", 2)[0]), 0644)
+		c := newCheck("")
+		run(c)
+		assert.Empty(c.Result.Breaches)
+		assert.Equal(result.Pass, c.Result.Status)
+		assert.Equal([]string{
+			"no new issue found compared with the baseline",
+			"1 issues fixed since the baseline was generated",
+		}, c.Result.Passes)
+		// The generated baseline is removed.
+		files, _ := filepath.Glob(filepath.Join(config.ProjectDir, ".shipshape-baseline-*"))
+		assert.Empty(files)
+	})
+
+	t.Run("noIssues", func(t *testing.T) {
+		os.Remove(generated)
+		c := newCheck(BaselineModeCheck)
+		run(c)
+		assert.Empty(c.Result.Breaches)
+		assert.Equal(result.Pass, c.Result.Status)
+	})
+
+	t.Run("newIssues", func(t *testing.T) {
+		os.WriteFile(generated, []byte(strings.ReplaceAll(string(baseline)+`
+		-
+			rawMessage: 'Call to an undefined method Foo::qux().'
+			identifier: method.notFound
+			count: 1
+			path: src/Bar.php
+`, "count: 2", "count: 4")), 0644)
+		c := newCheck("")
+		run(c)
+		assert.EqualValues([]result.Breach{
+			&result.KeyValuesBreach{
+				BreachType: "key-values",
+				CheckType:  "static-analysis",
+				Severity:   "normal",
+				Key:        "file: src/Bar.php",
+				Values:     []string{"[error] Call to an undefined method Foo::qux(). (method.notFound)"},
+			},
+			&result.KeyValuesBreach{
+				BreachType: "key-values",
+				CheckType:  "static-analysis",
+				Severity:   "normal",
+				Key:        "file: src/Foo.php",
+				Values:     []string{"[error] Undefined variable: $bar (variable.undefined) (x2)"},
+			},
+		}, c.Result.Breaches)
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		os.WriteFile(generated, []byte("parameters:\n\tignoreErrors: []\n"), 0644)
+		c := newCheck(BaselineModeGenerate)
+		run(c)
+		assert.Empty(c.Result.Breaches)
+		assert.Equal([]string{"baseline written to phpstan-baseline.neon"}, c.Result.Passes)
+		data, _ := os.ReadFile(filepath.Join(config.ProjectDir, "phpstan-baseline.neon"))
+		assert.Equal("parameters:\n\tignoreErrors: []\n", string(data))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		c := &StaticAnalysisCheck{Tool: "eslint", Paths: []string{"src"}, Baseline: "baseline.json"}
+		c.FetchData()
+		assert.EqualValues([]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			ValueLabel: "baseline not supported",
+			Value:      "eslint",
+		}}, c.Result.Breaches)
+
+		c = newCheck("update")
+		c.FetchData()
+		assert.Equal("invalid baseline-mode", c.Result.Breaches[0].(*result.ValueBreach).ValueLabel)
+
+		c = newCheck("")
+		c.Baseline = "missing.neon"
+		c.FetchData()
+		assert.Equal("unable to read baseline", c.Result.Breaches[0].(*result.ValueBreach).ValueLabel)
+	})
+}
+
+func TestStaticAnalysisCheckRemediate(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "prettier")
+	os.WriteFile(bin, []byte("#!/bin/sh\necho \"$@\" > "+dir+"/args\n"), 0755)
+	c := StaticAnalysisCheck{Tool: "prettier", Bin: bin, Paths: []string{"src"}}
+	c.Init(StaticAnalysis)
+	c.AddBreach(&result.KeyValuesBreach{Key: "file: src/index.js",
+		Values: []string{"[warning] file is not formatted according to prettier"}})
+	c.Remediate()
+	args, _ := os.ReadFile(filepath.Join(dir, "args"))
+	assert.Equal("--list-different testdata/src --write\n", string(args))
+	assert.Equal(&result.Remediation{
+		Status:   result.RemediationStatusSuccess,
+		Messages: []string{"fixed the issues using prettier"},
+	}, c.Result.Breaches[0].GetRemediation())
+
+	os.WriteFile(bin, []byte("#!/bin/sh\necho 'SyntaxError: Unexpected token' >&2\nexit 2\n"), 0755)
+	c = StaticAnalysisCheck{Tool: "prettier", Bin: bin, Paths: []string{"src"}}
+	c.Init(StaticAnalysis)
+	c.AddBreach(&result.KeyValuesBreach{Key: "file: src/index.js"})
+	c.Remediate()
+	assert.Equal(&result.Remediation{
+		Status:   result.RemediationStatusFailed,
+		Messages: []string{"error fixing the issues using prettier: SyntaxError: Unexpected token\n"},
+	}, c.Result.Breaches[0].GetRemediation())
+
+	// Tools without fix arguments do not support remediation.
+	c = StaticAnalysisCheck{Tool: "phpstan", Bin: bin, Paths: []string{"src"}}
+	c.Init(StaticAnalysis)
+	c.AddBreach(&result.KeyValuesBreach{Key: "file: src/index.php"})
+	c.Remediate()
+	assert.Equal(result.RemediationStatusNoSupport, c.Result.Breaches[0].GetRemediation().Status)
+}
@@ -0,0 +1,15 @@
+package toml
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=toml
+
+func RegisterChecks() {
+	config.ChecksRegistry[Toml] = func() config.Check { return &TomlCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
@@ -0,0 +1,50 @@
+package toml
+
+import (
+	"github.com/BurntSushi/toml"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const Toml config.CheckType = "toml"
+
+// TomlCheck verifies the values in toml files, e.g, pyproject.toml, using
+// the same values as the yaml check.
+type TomlCheck struct {
+	yaml.YamlCheck `yaml:",inline"`
+}
+
+// Merge implementation for toml check.
+func (c *TomlCheck) Merge(mergeCheck config.Check) error {
+	tomlMergeCheck := mergeCheck.(*TomlCheck)
+	return c.YamlCheck.Merge(&tomlMergeCheck.YamlCheck)
+}
+
+// UnmarshalDataMap parses the toml files into Yaml nodes so that the values
+// can be verified by the YamlBase logic.
+func (c *TomlCheck) UnmarshalDataMap() {
+	c.NodeMap = map[string]yamlv3.Node{}
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[configName]
+		var values map[string]any
+		if err := toml.Unmarshal(data, &values); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: configName,
+				Value:      err.Error()})
+			return
+		}
+
+		n := yamlv3.Node{}
+		if err := n.Encode(values); err != nil {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: configName,
+				Value:      err.Error()})
+			return
+		}
+		c.NodeMap[configName] = n
+	}
+}
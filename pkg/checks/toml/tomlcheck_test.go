@@ -0,0 +1,81 @@
+package toml_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/toml"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	c := config.ChecksRegistry[Toml]()
+	assert.Equal(t, "*toml.TomlCheck", reflect.TypeOf(c).String())
+}
+
+func TestTomlCheckUnmarshalDataMap(t *testing.T) {
+	c := TomlCheck{}
+	c.DataMap = map[string][]byte{"pyproject.toml": []byte("[project")}
+	c.UnmarshalDataMap()
+	assert.EqualValues(t, []result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		ValueLabel: "pyproject.toml",
+		Value:      "toml: line 0: expected '.' or ']' to end table name, but got '\\x00' instead",
+	}}, c.Result.Breaches)
+}
+
+func TestTomlCheckRunCheck(t *testing.T) {
+	config.ProjectDir = "testdata"
+	defer func() { config.ProjectDir = "" }()
+
+	tt := []internal.RunCheckTest{
+		{
+			Name: "expectedValues",
+			Check: &TomlCheck{YamlCheck: yaml.YamlCheck{
+				File: "pyproject.toml",
+				YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+					{Key: "project.name", Value: "myapp"},
+					{Key: "tool.black.line-length", Value: "88"},
+					{Key: "project.dependencies", IsList: true, Disallowed: []string{"pycrypto"}},
+				}},
+			}},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"[pyproject.toml] 'project.name' equals 'myapp'",
+				"[pyproject.toml] 'tool.black.line-length' equals '88'",
+				"[pyproject.toml] no disallowed 'project.dependencies'",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "versionConstraint",
+			Check: &TomlCheck{YamlCheck: yaml.YamlCheck{
+				File: "pyproject.toml",
+				YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+					{Key: "project.requires-python", VersionConstraint: ">= 3.9"},
+				}},
+			}},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.KeyValuesBreach{
+				BreachType: "key-values",
+				KeyLabel:   "config",
+				Key:        "pyproject.toml",
+				ValueLabel: "invalid version for project.requires-python",
+				Values:     []string{">=3.8 does not satisfy '>= 3.9'"},
+			}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.FetchData()
+			tc.Check.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package wordpress
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// CoreCheck verifies the version of WordPress core.
+type CoreCheck struct {
+	config.CheckBase `yaml:",inline"`
+	WpCommand        `yaml:",inline"`
+	// Constraint the version must satisfy, e.g, ">= 6.4".
+	VersionConstraint string `yaml:"version-constraint"`
+}
+
+// Merge implementation for CoreCheck check.
+func (c *CoreCheck) Merge(mergeCheck config.Check) error {
+	coreMergeCheck := mergeCheck.(*CoreCheck)
+	if err := c.CheckBase.Merge(&coreMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.WpCommand.Merge(coreMergeCheck.WpCommand)
+	utils.MergeString(&c.VersionConstraint, coreMergeCheck.VersionConstraint)
+	return nil
+}
+
+// FetchData runs wp-cli to determine the core version.
+func (c *CoreCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	c.DataMap["version"], err = c.WpCommand.Exec("core", "version")
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+	}
+}
+
+// RunCheck verifies the version satisfies the constraint.
+func (c *CoreCheck) RunCheck() {
+	v := strings.TrimSpace(string(c.DataMap["version"]))
+	msg, err := yaml.KeyValue{VersionConstraint: c.VersionConstraint}.CheckVersion(v)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "unable to verify version",
+			Value:      err.Error()})
+		return
+	}
+	if msg != "" {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "WordPress version",
+			Value:      msg})
+		return
+	}
+	c.AddPass(fmt.Sprintf("WordPress version %s satisfies '%s'", v, c.VersionConstraint))
+	c.Result.Status = result.Pass
+}
+
+// Commands implements config.CommandReporter.
+func (c *CoreCheck) Commands() [][]string {
+	return [][]string{c.WpCommand.Line("core", "version")}
+}
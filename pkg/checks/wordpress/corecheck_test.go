@@ -0,0 +1,118 @@
+package wordpress_test
+
+import (
+	"os/exec"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/wordpress"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoreCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := CoreCheck{VersionConstraint: ">= 6.3"}
+	c.Merge(&CoreCheck{
+		WpCommand:         WpCommand{Url: "https://example.com"},
+		VersionConstraint: ">= 6.4",
+	})
+	assert.EqualValues(CoreCheck{
+		WpCommand:         WpCommand{Url: "https://example.com"},
+		VersionConstraint: ">= 6.4",
+	}, c)
+}
+
+func TestCoreCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil,
+		&exec.ExitError{Stderr: []byte("Error: This does not seem to be a WordPress installation.")},
+		nil)
+	c := CoreCheck{}
+	c.FetchData()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "Error: This does not seem to be a WordPress installation.",
+		}},
+		c.Result.Breaches,
+	)
+
+	command.ShellCommander = internal.ShellCommanderMaker(&[]string{"6.4.2\n"}[0], nil, nil)
+	c = CoreCheck{}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal([]byte("6.4.2\n"), c.DataMap["version"])
+}
+
+func TestCoreCheckRunCheck(t *testing.T) {
+	tt := []internal.RunCheckTest{
+		{
+			Name: "versionSatisfied",
+			Check: &CoreCheck{
+				CheckBase:         config.CheckBase{DataMap: map[string][]byte{"version": []byte("6.4.2\n")}},
+				VersionConstraint: ">= 6.4",
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{"WordPress version 6.4.2 satisfies '>= 6.4'"},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "versionNotSatisfied",
+			Check: &CoreCheck{
+				CheckBase:         config.CheckBase{DataMap: map[string][]byte{"version": []byte("6.2.3\n")}},
+				VersionConstraint: ">= 6.4",
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "wp-core",
+				Severity:   "normal",
+				ValueLabel: "WordPress version",
+				Value:      "6.2.3 does not satisfy '>= 6.4'",
+			}},
+		},
+		{
+			Name: "invalidConstraint",
+			Check: &CoreCheck{
+				CheckBase:         config.CheckBase{DataMap: map[string][]byte{"version": []byte("6.4.2")}},
+				VersionConstraint: "latest",
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "wp-core",
+				Severity:   "normal",
+				ValueLabel: "unable to verify version",
+				Value:      "Malformed constraint: latest",
+			}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Check.Init(Core)
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
+
+func TestCoreCheckCommands(t *testing.T) {
+	currProjectDir := config.ProjectDir
+	defer func() { config.ProjectDir = currProjectDir }()
+	config.ProjectDir = "/app"
+
+	c := CoreCheck{WpCommand: WpCommand{WpPath: "vendor/bin/wp"}}
+	assert.Equal(t, [][]string{{"/app/vendor/bin/wp", "--path=/app", "core", "version"}}, c.Commands())
+}
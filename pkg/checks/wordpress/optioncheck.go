@@ -0,0 +1,100 @@
+package wordpress
+
+import (
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// OptionCheck verifies site options using the yaml key-value checks; the
+// options are fetched using the first segment of each key, e.g, `blogname`
+// or `permalink_structure`.
+type OptionCheck struct {
+	yaml.YamlBase `yaml:",inline"`
+	WpCommand     `yaml:",inline"`
+}
+
+// Init implementation for the wp-cli-based option check.
+func (c *OptionCheck) Init(ct config.CheckType) {
+	c.YamlBase.Init(ct)
+	c.RequiresDb = true
+}
+
+// Merge implementation for OptionCheck check.
+func (c *OptionCheck) Merge(mergeCheck config.Check) error {
+	optionMergeCheck := mergeCheck.(*OptionCheck)
+	if err := c.YamlBase.Merge(&optionMergeCheck.YamlBase); err != nil {
+		return err
+	}
+
+	c.WpCommand.Merge(optionMergeCheck.WpCommand)
+	return nil
+}
+
+// FetchData runs wp-cli to get each option, building a yaml document keyed
+// by option; options which are not set are left out so they are reported
+// as not found.
+func (c *OptionCheck) FetchData() {
+	options := map[string]any{}
+	for _, name := range c.optionNames() {
+		out, err := c.WpCommand.Exec("option", "get", name, "--format=json")
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				continue
+			}
+			c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+			return
+		}
+		var v any
+		if err := json.Unmarshal(out, &v); err != nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "option",
+				Key:        name,
+				ValueLabel: "invalid value",
+				Value:      err.Error(),
+			})
+			continue
+		}
+		options[name] = v
+	}
+
+	data, err := yamlv3.Marshal(options)
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+		return
+	}
+	c.DataMap = map[string][]byte{"options": data}
+}
+
+// Commands implements config.CommandReporter.
+func (c *OptionCheck) Commands() [][]string {
+	cmds := [][]string{}
+	for _, name := range c.optionNames() {
+		cmds = append(cmds, c.WpCommand.Line("option", "get", name, "--format=json"))
+	}
+	return cmds
+}
+
+// optionNames returns the unique options referenced by the values' keys.
+func (c *OptionCheck) optionNames() []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, kv := range c.Values {
+		name, _, _ := strings.Cut(kv.Key, ".")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,168 @@
+package wordpress_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/wordpress"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// optionsCommander mocks wp-cli returning the options' json values; options
+// not listed fail like wp-cli does.
+func optionsCommander(options map[string]string) func(string, ...string) command.IShellCommand {
+	return func(name string, arg ...string) command.IShellCommand {
+		option := arg[len(arg)-2]
+		return internal.TestShellCommand{
+			OutputterFunc: func() ([]byte, error) {
+				if v, ok := options[option]; ok {
+					return []byte(v), nil
+				}
+				return nil, &exec.ExitError{Stderr: []byte("Error: Could not get '" + option + "' option. Does it exist?")}
+			},
+		}
+	}
+}
+
+func TestOptionCheckInit(t *testing.T) {
+	c := OptionCheck{}
+	c.Init(Option)
+	assert.True(t, c.RequiresDb)
+}
+
+func TestOptionCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := OptionCheck{
+		YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{{Key: "blog_public", Value: "0"}}},
+	}
+	c.Merge(&OptionCheck{
+		YamlBase:  yaml.YamlBase{Values: []yaml.KeyValue{{Key: "users_can_register", Value: "0"}}},
+		WpCommand: WpCommand{Url: "https://example.com"},
+	})
+	assert.EqualValues(OptionCheck{
+		YamlBase:  yaml.YamlBase{Values: []yaml.KeyValue{{Key: "users_can_register", Value: "0"}}},
+		WpCommand: WpCommand{Url: "https://example.com"},
+	}, c)
+}
+
+func TestOptionCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	command.ShellCommander = optionsCommander(map[string]string{
+		"blog_public":   "0",
+		"wordfence_ls":  `{"enabled":true,"roles":["administrator"]}`,
+		"invalid_value": `{"enabled":`,
+	})
+	c := OptionCheck{YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+		{Key: "blog_public", Value: "0"},
+		{Key: "wordfence_ls.enabled", Value: "true"},
+		{Key: "wordfence_ls.roles", Value: "editor", IsList: true},
+		{Key: "invalid_value", Value: "1"},
+		{Key: "missing", Value: "1"},
+	}}}
+	c.FetchData()
+	assert.EqualValues(
+		[]result.Breach{&result.KeyValueBreach{
+			BreachType: "key-value",
+			KeyLabel:   "option",
+			Key:        "invalid_value",
+			ValueLabel: "invalid value",
+			Value:      "unexpected end of JSON input",
+		}},
+		c.Result.Breaches,
+	)
+	assert.Equal(strings.Join([]string{
+		"blog_public: 0",
+		"wordfence_ls:",
+		"    enabled: true",
+		"    roles:",
+		"        - administrator",
+		"",
+	}, "\n"), string(c.DataMap["options"]))
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil, &exec.Error{Name: "wp", Err: exec.ErrNotFound}, nil)
+	c = OptionCheck{YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{{Key: "blog_public", Value: "0"}}}}
+	c.FetchData()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      `exec: "wp": executable file not found in $PATH`,
+		}},
+		c.Result.Breaches,
+	)
+}
+
+func TestOptionCheckRunCheck(t *testing.T) {
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+	command.ShellCommander = optionsCommander(map[string]string{
+		"blog_public":         "1",
+		"users_can_register":  "0",
+		"permalink_structure": `"/%postname%/"`,
+	})
+
+	c := OptionCheck{YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+		{Key: "blog_public", Value: "0"},
+		{Key: "users_can_register", Value: "0"},
+		{Key: "permalink_structure", Pattern: "%postname%"},
+		{Key: "default_role", Value: "subscriber"},
+	}}}
+	c.Init(Option)
+	c.FetchData()
+	c.UnmarshalDataMap()
+	internal.TestRunCheck(t, internal.RunCheckTest{
+		Check:        &c,
+		ExpectStatus: result.Fail,
+		ExpectPasses: []string{
+			"[options] 'users_can_register' equals '0'",
+			"[options] 'permalink_structure' matches '%postname%'",
+		},
+		ExpectFails: []result.Breach{
+			&result.KeyValueBreach{
+				BreachType:    "key-value",
+				CheckType:     "wp-option",
+				Severity:      "normal",
+				KeyLabel:      "config:options",
+				Key:           "blog_public",
+				ValueLabel:    "actual",
+				ExpectedValue: "0",
+				Value:         "1",
+			},
+			&result.KeyValueBreach{
+				BreachType: "key-value",
+				CheckType:  "wp-option",
+				Severity:   "normal",
+				KeyLabel:   "config",
+				Key:        "options",
+				ValueLabel: "key not found",
+				Value:      "default_role",
+			},
+		},
+	})
+}
+
+func TestOptionCheckCommands(t *testing.T) {
+	c := OptionCheck{
+		YamlBase: yaml.YamlBase{Values: []yaml.KeyValue{
+			{Key: "blog_public", Value: "0"},
+			{Key: "wordfence_ls.enabled", Value: "true"},
+			{Key: "wordfence_ls.roles", Value: "editor", IsList: true},
+		}},
+		WpCommand: WpCommand{Path: "/app"},
+	}
+	assert.Equal(t, [][]string{
+		{"wp", "--path=/app", "option", "get", "blog_public", "--format=json"},
+		{"wp", "--path=/app", "option", "get", "wordfence_ls", "--format=json"},
+	}, c.Commands())
+}
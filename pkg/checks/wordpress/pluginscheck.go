@@ -0,0 +1,179 @@
+package wordpress
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// Plugin is a plugin as listed by `wp plugin list`.
+type Plugin struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Update  string `json:"update"`
+	Version string `json:"version"`
+}
+
+// PluginsCheck verifies the plugins active on the site against required,
+// disallowed and allowed lists, as well as their versions.
+type PluginsCheck struct {
+	config.CheckBase `yaml:",inline"`
+	WpCommand        `yaml:",inline"`
+	// Plugins which must be active.
+	Required []string `yaml:"required"`
+	// Plugins which must not be active.
+	Disallowed []string `yaml:"disallowed"`
+	// If set, only these plugins can be active; `re:` & `glob:` prefixes are
+	// supported.
+	Allowed []string `yaml:"allowed"`
+	// Version constraints keyed by plugin, e.g, woocommerce: ">= 8.0".
+	Versions map[string]string `yaml:"versions"`
+	plugins  []Plugin
+}
+
+// Init implementation for the wp-cli-based plugins check.
+func (c *PluginsCheck) Init(ct config.CheckType) {
+	c.CheckBase.Init(ct)
+	c.RequiresDb = true
+}
+
+// Merge implementation for PluginsCheck check.
+func (c *PluginsCheck) Merge(mergeCheck config.Check) error {
+	pluginsMergeCheck := mergeCheck.(*PluginsCheck)
+	if err := c.CheckBase.Merge(&pluginsMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	c.WpCommand.Merge(pluginsMergeCheck.WpCommand)
+	utils.MergeStringSlice(&c.Required, pluginsMergeCheck.Required)
+	utils.MergeStringSlice(&c.Disallowed, pluginsMergeCheck.Disallowed)
+	utils.MergeStringSlice(&c.Allowed, pluginsMergeCheck.Allowed)
+	if len(pluginsMergeCheck.Versions) > 0 && c.Versions == nil {
+		c.Versions = map[string]string{}
+	}
+	for p, v := range pluginsMergeCheck.Versions {
+		c.Versions[p] = v
+	}
+	return nil
+}
+
+// FetchData runs wp-cli to list the plugins.
+func (c *PluginsCheck) FetchData() {
+	var err error
+	c.DataMap = map[string][]byte{}
+	c.DataMap["plugins"], err = c.WpCommand.Exec("plugin", "list", "--format=json")
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{Value: command.GetMsgFromCommandError(err)})
+	}
+}
+
+// UnmarshalDataMap parses the plugins list json.
+func (c *PluginsCheck) UnmarshalDataMap() {
+	c.plugins = []Plugin{}
+	if err := json.Unmarshal(c.DataMap["plugins"], &c.plugins); err != nil {
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+	}
+}
+
+// RunCheck verifies the active plugins and their versions.
+func (c *PluginsCheck) RunCheck() {
+	active := map[string]Plugin{}
+	for _, p := range c.plugins {
+		if p.Status == "active" || p.Status == "active-network" || p.Status == "must-use" {
+			active[p.Name] = p
+		}
+	}
+
+	inactive := []string{}
+	for _, p := range c.Required {
+		if _, ok := active[p]; !ok {
+			inactive = append(inactive, p)
+		}
+	}
+	if len(inactive) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			Key:    "required plugins are not active",
+			Values: inactive})
+	} else if len(c.Required) > 0 {
+		c.AddPass("all required plugins are active")
+	}
+
+	names := []string{}
+	for name := range active {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	disallowed := []string{}
+	notAllowed := []string{}
+	for _, name := range names {
+		if utils.StringSliceMatchAny(c.Disallowed, name) {
+			disallowed = append(disallowed, name)
+		} else if len(c.Allowed) > 0 && !utils.StringSliceMatchAny(c.Allowed, name) &&
+			!utils.StringSliceContains(c.Required, name) {
+			notAllowed = append(notAllowed, name)
+		}
+	}
+	if len(disallowed) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			Key:    "disallowed plugins are active",
+			Values: disallowed})
+	}
+	if len(notAllowed) > 0 {
+		c.AddBreach(&result.KeyValuesBreach{
+			Key:    "plugins not allowed are active",
+			Values: notAllowed})
+	}
+
+	versioned := []string{}
+	for name := range c.Versions {
+		versioned = append(versioned, name)
+	}
+	sort.Strings(versioned)
+	for _, name := range versioned {
+		c.verifyVersion(name, c.Versions[name])
+	}
+
+	if len(c.Result.Breaches) == 0 {
+		c.AddPass(fmt.Sprintf("%d active plugins verified", len(active)))
+		c.Result.Status = result.Pass
+	}
+}
+
+// verifyVersion verifies the installed plugin's version satisfies the
+// constraint; plugins not installed are ignored.
+func (c *PluginsCheck) verifyVersion(name string, constraint string) {
+	for _, p := range c.plugins {
+		if p.Name != name {
+			continue
+		}
+		msg, err := yaml.KeyValue{VersionConstraint: constraint}.CheckVersion(p.Version)
+		if err != nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "plugin",
+				Key:        name,
+				ValueLabel: "unable to verify version",
+				Value:      err.Error(),
+			})
+		} else if msg != "" {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "plugin",
+				Key:        name,
+				ValueLabel: "version",
+				Value:      msg,
+			})
+		}
+		return
+	}
+}
+
+// Commands implements config.CommandReporter.
+func (c *PluginsCheck) Commands() [][]string {
+	return [][]string{c.WpCommand.Line("plugin", "list", "--format=json")}
+}
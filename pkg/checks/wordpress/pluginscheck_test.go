@@ -0,0 +1,180 @@
+package wordpress_test
+
+import (
+	"os/exec"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/wordpress"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pluginList = `[
+	{"name":"akismet","status":"inactive","update":"none","version":"5.3"},
+	{"name":"wordfence","status":"active","update":"available","version":"7.10.0"},
+	{"name":"woocommerce","status":"active-network","update":"none","version":"8.4.0"},
+	{"name":"hello","status":"active","update":"none","version":"1.7.2"}
+]`
+
+func TestPluginsCheckInit(t *testing.T) {
+	c := PluginsCheck{}
+	c.Init(Plugins)
+	assert.True(t, c.RequiresDb)
+}
+
+func TestPluginsCheckMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := PluginsCheck{
+		Required: []string{"wordfence"},
+		Versions: map[string]string{"wordfence": ">= 7.0"},
+	}
+	c.Merge(&PluginsCheck{
+		WpCommand:  WpCommand{Path: "web"},
+		Disallowed: []string{"hello"},
+		Versions:   map[string]string{"woocommerce": ">= 8.0"},
+	})
+	assert.EqualValues(PluginsCheck{
+		WpCommand:  WpCommand{Path: "web"},
+		Required:   []string{"wordfence"},
+		Disallowed: []string{"hello"},
+		Versions:   map[string]string{"wordfence": ">= 7.0", "woocommerce": ">= 8.0"},
+	}, c)
+}
+
+func TestPluginsCheckFetchData(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil,
+		&exec.ExitError{Stderr: []byte("Error: Error establishing a database connection.")},
+		nil)
+	c := PluginsCheck{}
+	c.FetchData()
+	assert.EqualValues(
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "Error: Error establishing a database connection.",
+		}},
+		c.Result.Breaches,
+	)
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(&[]string{pluginList}[0], nil, &generatedCommand)
+	c = PluginsCheck{WpCommand: WpCommand{WpPath: "/usr/bin/wp", Path: "/app"}}
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Equal("/usr/bin/wp --path=/app plugin list --format=json", generatedCommand)
+}
+
+func TestPluginsCheckUnmarshalDataMap(t *testing.T) {
+	c := PluginsCheck{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{"plugins": []byte(`[{"name":`)},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.EqualValues(t,
+		[]result.Breach{&result.ValueBreach{
+			BreachType: "value",
+			Value:      "unexpected end of JSON input",
+		}},
+		c.Result.Breaches,
+	)
+}
+
+func TestPluginsCheckRunCheck(t *testing.T) {
+	tt := []internal.RunCheckTest{
+		{
+			Name: "compliant",
+			Check: &PluginsCheck{
+				Required:   []string{"wordfence"},
+				Disallowed: []string{"akismet"},
+				Allowed:    []string{"woocommerce", "glob:hel*"},
+				Versions:   map[string]string{"woocommerce": ">= 8.0", "jetpack": ">= 12.0"},
+			},
+			ExpectStatus: result.Pass,
+			ExpectPasses: []string{
+				"all required plugins are active",
+				"3 active plugins verified",
+			},
+			ExpectNoFail: true,
+		},
+		{
+			Name: "breaches",
+			Check: &PluginsCheck{
+				Required:   []string{"wordfence", "two-factor"},
+				Disallowed: []string{"hello"},
+				Allowed:    []string{"re:^word"},
+				Versions:   map[string]string{"wordfence": ">= 7.11", "woocommerce": "~> foo"},
+			},
+			ExpectStatus: result.Fail,
+			ExpectNoPass: true,
+			ExpectFails: []result.Breach{
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "wp-plugins",
+					Severity:   "normal",
+					Key:        "required plugins are not active",
+					Values:     []string{"two-factor"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "wp-plugins",
+					Severity:   "normal",
+					Key:        "disallowed plugins are active",
+					Values:     []string{"hello"},
+				},
+				&result.KeyValuesBreach{
+					BreachType: "key-values",
+					CheckType:  "wp-plugins",
+					Severity:   "normal",
+					Key:        "plugins not allowed are active",
+					Values:     []string{"woocommerce"},
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "wp-plugins",
+					Severity:   "normal",
+					KeyLabel:   "plugin",
+					Key:        "woocommerce",
+					ValueLabel: "unable to verify version",
+					Value:      "Malformed constraint: ~> foo",
+				},
+				&result.KeyValueBreach{
+					BreachType: "key-value",
+					CheckType:  "wp-plugins",
+					Severity:   "normal",
+					KeyLabel:   "plugin",
+					Key:        "wordfence",
+					ValueLabel: "version",
+					Value:      "7.10.0 does not satisfy '>= 7.11'",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			c := tc.Check.(*PluginsCheck)
+			c.Init(Plugins)
+			c.DataMap = map[string][]byte{"plugins": []byte(pluginList)}
+			c.UnmarshalDataMap()
+			internal.TestRunCheck(t, tc)
+		})
+	}
+}
+
+func TestPluginsCheckCommands(t *testing.T) {
+	c := PluginsCheck{WpCommand: WpCommand{Path: "/var/www", Url: "https://example.com"}}
+	assert.Equal(t, [][]string{
+		{"wp", "--path=/var/www", "--url=https://example.com", "plugin", "list", "--format=json"},
+	}, c.Commands())
+}
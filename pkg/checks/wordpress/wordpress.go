@@ -0,0 +1,23 @@
+package wordpress
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=wordpress
+
+const (
+	Plugins config.CheckType = "wp-plugins"
+	Core    config.CheckType = "wp-core"
+	Option  config.CheckType = "wp-option"
+)
+
+func RegisterChecks() {
+	config.ChecksRegistry[Plugins] = func() config.Check { return &PluginsCheck{} }
+	config.ChecksRegistry[Core] = func() config.Check { return &CoreCheck{} }
+	config.ChecksRegistry[Option] = func() config.Check { return &OptionCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
@@ -0,0 +1,58 @@
+package wordpress
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const WpDefaultPath = "wp"
+
+// WpCommand runs wp-cli against the WordPress installation.
+type WpCommand struct {
+	// Path to the wp-cli binary, relative to the project directory if it
+	// contains a separator, otherwise looked up in $PATH.
+	WpPath string `yaml:"wp-path"`
+	// Path to the WordPress files, relative to the project directory;
+	// defaults to the project directory.
+	Path string `yaml:"path"`
+	// Url of the site, for multisite installations.
+	Url string `yaml:"url"`
+}
+
+// Merge implementation for WpCommand.
+func (cmd *WpCommand) Merge(mergeCmd WpCommand) {
+	utils.MergeString(&cmd.WpPath, mergeCmd.WpPath)
+	utils.MergeString(&cmd.Path, mergeCmd.Path)
+	utils.MergeString(&cmd.Url, mergeCmd.Url)
+}
+
+// Line returns the command run by Exec, without running it.
+func (cmd *WpCommand) Line(args ...string) []string {
+	bin := cmd.WpPath
+	if bin == "" {
+		bin = WpDefaultPath
+	}
+	if strings.Contains(bin, "/") && !filepath.IsAbs(bin) {
+		bin = filepath.Join(config.ProjectDir, bin)
+	}
+	path := cmd.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(config.ProjectDir, path)
+	}
+
+	line := []string{bin, "--path=" + path}
+	if cmd.Url != "" {
+		line = append(line, "--url="+cmd.Url)
+	}
+	return append(line, args...)
+}
+
+// Exec runs the wp-cli command and returns the output.
+func (cmd *WpCommand) Exec(args ...string) ([]byte, error) {
+	line := cmd.Line(args...)
+	return command.ShellCommander(line[0], line[1:]...).Output()
+}
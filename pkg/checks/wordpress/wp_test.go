@@ -0,0 +1,72 @@
+package wordpress_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/wordpress"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWpCommandMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := WpCommand{WpPath: "vendor/bin/wp", Path: "web"}
+	cmd.Merge(WpCommand{Url: "https://example.com"})
+	assert.Equal(WpCommand{
+		WpPath: "vendor/bin/wp",
+		Path:   "web",
+		Url:    "https://example.com",
+	}, cmd)
+
+	cmd.Merge(WpCommand{WpPath: "wp"})
+	assert.Equal("wp", cmd.WpPath)
+}
+
+func TestWpCommandLine(t *testing.T) {
+	assert := assert.New(t)
+
+	currProjectDir := config.ProjectDir
+	defer func() { config.ProjectDir = currProjectDir }()
+	config.ProjectDir = "/app"
+
+	assert.Equal([]string{"wp", "--path=/app", "core", "version"},
+		(&WpCommand{}).Line("core", "version"))
+	assert.Equal([]string{"/app/vendor/bin/wp", "--path=/app/web", "core", "version"},
+		(&WpCommand{WpPath: "vendor/bin/wp", Path: "web"}).Line("core", "version"))
+	assert.Equal(
+		[]string{"/usr/local/bin/wp", "--path=/var/www", "--url=https://example.com", "core", "version"},
+		(&WpCommand{
+			WpPath: "/usr/local/bin/wp",
+			Path:   "/var/www",
+			Url:    "https://example.com",
+		}).Line("core", "version"))
+}
+
+func TestWpCommandExec(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	currProjectDir := config.ProjectDir
+	defer func() { config.ProjectDir = currProjectDir }()
+	config.ProjectDir = "/app"
+
+	command.ShellCommander = internal.ShellCommanderMaker(
+		nil, errors.New("wp: command not found"), nil)
+	_, err := (&WpCommand{}).Exec("core", "version")
+	assert.EqualError(err, "wp: command not found")
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(
+		&[]string{"6.4.2\n"}[0], nil, &generatedCommand)
+	out, err := (&WpCommand{}).Exec("core", "version")
+	assert.NoError(err)
+	assert.Equal([]byte("6.4.2\n"), out)
+	assert.Equal("wp --path=/app core version", generatedCommand)
+}
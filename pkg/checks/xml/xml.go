@@ -0,0 +1,73 @@
+// Package xml provides checks against XML files using XPath expressions.
+package xml
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+//go:generate go run ../../../cmd/gen.go registry --checkpackage=xml
+
+const Xml config.CheckType = "xml" // Represents an XmlCheck.
+
+// XmlCheck represents an XML file-based check, which can be for a single file
+// or across a number of files defined by a regex pattern.
+type XmlCheck struct {
+	yaml.YamlCheck `yaml:",inline"`
+	// Cannot override struct field with same YAML key.
+	// https://github.com/go-yaml/yaml/issues/467
+	KeyValues []KeyValue                `yaml:"key-values"`
+	Node      map[string]*xmlquery.Node `yaml:"-"`
+}
+
+// KeyValue uses an XPath expression as the key.
+type KeyValue struct {
+	yaml.KeyValue `yaml:",inline"`
+	// Whether the expression must match (true) or must not match (false);
+	// when set, no other verification is done.
+	Exists *bool `yaml:"exists"`
+}
+
+func RegisterChecks() {
+	config.ChecksRegistry[Xml] = func() config.Check { return &XmlCheck{} }
+}
+
+func init() {
+	RegisterChecks()
+}
+
+// EvaluateXPath evaluates the XPath expression and returns the values found.
+// Nodes are converted to their text content and attributes to their value;
+// expressions evaluating to a number, string or boolean return a single value.
+func EvaluateXPath(expr string, node *xmlquery.Node) ([]string, error) {
+	e, err := xpath.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := e.Evaluate(xmlquery.CreateXPathNavigator(node)).(type) {
+	case *xpath.NodeIterator:
+		values := []string{}
+		for v.MoveNext() {
+			values = append(values, v.Current().Value())
+		}
+		return values, nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case bool:
+		return []string{strconv.FormatBool(v)}, nil
+	case string:
+		return []string{v}, nil
+	}
+	return nil, nil
+}
+
+// ParseXml parses the XML data into a document node.
+func ParseXml(data []byte) (*xmlquery.Node, error) {
+	return xmlquery.Parse(bytes.NewReader(data))
+}
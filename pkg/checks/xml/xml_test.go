@@ -0,0 +1,39 @@
+package xml_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/xml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateXPath(t *testing.T) {
+	assert := assert.New(t)
+
+	data, _ := os.ReadFile("testdata/phpunit.xml")
+	doc, err := ParseXml(data)
+	assert.NoError(err)
+
+	tt := []struct {
+		expr     string
+		expected []string
+	}{
+		{"/phpunit/@colors", []string{"true"}},
+		{"//ini[@name='memory_limit']/@value", []string{"-1"}},
+		{"//testsuite/@name", []string{"unit", "kernel"}},
+		{"//testsuite[@name='unit']/directory", []string{"web/modules/custom/*/tests/src/Unit"}},
+		{"count(//testsuite)", []string{"2"}},
+		{"boolean(//env[@name='SIMPLETEST_DB'])", []string{"true"}},
+		{"string(/phpunit/@bootstrap)", []string{"web/core/tests/bootstrap.php"}},
+		{"//listeners", []string{}},
+	}
+	for _, tc := range tt {
+		values, err := EvaluateXPath(tc.expr, doc)
+		assert.NoError(err, tc.expr)
+		assert.Equal(tc.expected, values, tc.expr)
+	}
+
+	_, err = EvaluateXPath("//[", doc)
+	assert.Error(err)
+}
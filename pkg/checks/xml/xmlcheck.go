@@ -0,0 +1,149 @@
+package xml
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// Merge implementation for XML check.
+func (c *XmlCheck) Merge(mergeCheck config.Check) error {
+	xmlMergeCheck := mergeCheck.(*XmlCheck)
+	if err := c.YamlCheck.Merge(&xmlMergeCheck.YamlCheck); err != nil {
+		return err
+	}
+
+	if len(xmlMergeCheck.KeyValues) > 0 {
+		c.KeyValues = xmlMergeCheck.KeyValues
+	}
+	return nil
+}
+
+// UnmarshalDataMap parses the DataMap into XML documents for further
+// processing.
+func (c *XmlCheck) UnmarshalDataMap() {
+	c.Node = map[string]*xmlquery.Node{}
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[configName]
+		n, err := ParseXml(data)
+		if err != nil {
+			c.AddBreach(&result.ValueBreach{ValueLabel: "XML error", Value: err.Error()})
+			return
+		}
+		c.Node[configName] = n
+	}
+}
+
+// RunCheck implements the base logic for running checks against XML data.
+func (c *XmlCheck) RunCheck() {
+	configNames := []string{}
+	for configName := range c.Node {
+		configNames = append(configNames, configName)
+	}
+	sort.Strings(configNames)
+	for _, configName := range configNames {
+		c.processData(configName)
+	}
+
+	if len(c.Result.Breaches) != 0 {
+		c.Result.Status = result.Fail
+	} else {
+		c.Result.Status = result.Pass
+	}
+}
+
+// processData runs the checks against the list of KeyValues provided in the
+// Check configuration.
+func (c *XmlCheck) processData(configName string) {
+	for _, kv := range c.KeyValues {
+		kvr, fails, err := CheckKeyValue(c.Node[configName], kv)
+		if kvr == yaml.KeyValueEqual && kv.Exists != nil {
+			if *kv.Exists {
+				c.AddPass(fmt.Sprintf("[%s] '%s' exists", configName, kv.Key))
+			} else {
+				c.AddPass(fmt.Sprintf("[%s] '%s' does not exist", configName, kv.Key))
+			}
+			continue
+		}
+		yaml.AddKeyValueResult(&c.CheckBase, configName, configName, kv.KeyValue, kvr, fails, err)
+	}
+}
+
+// CheckKeyValue evaluates the KeyValue's XPath expression against the XML
+// document and returns the result, actual values and errors.
+func CheckKeyValue(node *xmlquery.Node, kv KeyValue) (yaml.KeyValueResult, []string, error) {
+	foundValues, err := EvaluateXPath(kv.Key, node)
+	if err != nil {
+		return yaml.KeyValueError, nil, err
+	}
+
+	if kv.Exists != nil {
+		if *kv.Exists && len(foundValues) == 0 {
+			return yaml.KeyValueNotFound, nil, nil
+		} else if !*kv.Exists && len(foundValues) > 0 {
+			return yaml.KeyValueDisallowedFound, foundValues, nil
+		}
+		return yaml.KeyValueEqual, nil, nil
+	}
+
+	if len(foundValues) == 0 {
+		if !kv.Optional {
+			return yaml.KeyValueNotFound, nil, nil
+		}
+		return yaml.KeyValueEqual, nil, nil
+	}
+
+	if kv.IsConstraintCheck() {
+		var fails []string
+		breach := yaml.KeyValueEqual
+		for _, v := range foundValues {
+			kvr, msg, err := kv.CheckConstraint(v)
+			if err != nil {
+				return yaml.KeyValueError, nil, err
+			}
+			if msg != "" {
+				breach = yaml.MergeConstraintBreach(breach, kvr)
+				fails = append(fails, msg)
+			}
+		}
+		if len(fails) > 0 {
+			return breach, fails, nil
+		}
+		return yaml.KeyValueEqual, nil, nil
+	}
+
+	if len(foundValues) > 1 && !kv.IsList {
+		return yaml.KeyValueError, nil, errors.New("A list of values was found but is-list is not set")
+	}
+
+	// Throw an error if we are checking a list but no allow/disallow list provided.
+	if len(kv.Allowed) == 0 && len(kv.Disallowed) == 0 && kv.IsList {
+		return yaml.KeyValueError, nil, errors.New("list of allowed or disallowed values not provided")
+	}
+
+	// Perform direct comparison if no allow/disallow list provided.
+	if len(kv.Allowed) == 0 && len(kv.Disallowed) == 0 {
+		if !kv.Equals(foundValues[0]) {
+			return yaml.KeyValueNotEqual, foundValues, nil
+		}
+		return yaml.KeyValueEqual, nil, nil
+	}
+
+	// Check each value against the allowed & disallowed lists.
+	var fails []string
+	for _, v := range foundValues {
+		if kv.IsDisallowed(v) && !utils.StringSliceContains(fails, kv.DisallowedValue(v)) {
+			fails = append(fails, kv.DisallowedValue(v))
+		}
+	}
+	if len(fails) > 0 {
+		return yaml.KeyValueDisallowedFound, fails, nil
+	}
+	return yaml.KeyValueEqual, nil, nil
+}
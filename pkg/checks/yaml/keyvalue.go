@@ -1,15 +1,33 @@
 package yaml
 
 import (
+	"fmt"
+	"math"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-version"
 	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"gopkg.in/yaml.v3"
 )
 
 // KeyValue represents a check to be made against Yaml data.
 // It can be a simple Key=Value check, or match against a list of Disallowed or
-// Allowed values. If the source is a list then IsList must be true.
+// Allowed values, which can be regexes ("re:^drupal/.*") or globs
+// ("glob:*-dev"). If the source is a list then IsList must be true.
 // If Optional is set then the validation will not fail if the key is not present.
+// If MaxAge or MinAge is set, the value is parsed as a timestamp using
+// TimeLayout and its age is verified instead.
+// If VersionConstraint is set, the minimum version in the value is verified
+// against it instead, e.g, ">= 18" for a "^18.2" value.
+// If Pattern is set, the value must match the regular expression instead.
+// If Min or Max is set, the value is parsed as a number, optionally with a
+// K, M or G multiplier, and verified to be within the limits instead; -1 is
+// considered unlimited.
+// These constraints can be combined, in which case all of them are verified.
+// For multi-document data, e.g, Kubernetes manifests, the key is looked up
+// in all the documents unless restricted using Document or Select.
 type KeyValue struct {
 	Key        string   `yaml:"key"`
 	Value      string   `yaml:"value"`
@@ -18,6 +36,64 @@ type KeyValue struct {
 	Optional   bool     `yaml:"optional"`
 	Disallowed []string `yaml:"disallowed"`
 	Allowed    []string `yaml:"allowed"`
+	MaxAge     string   `yaml:"max-age"`
+	MinAge     string   `yaml:"min-age"`
+	TimeLayout string   `yaml:"time-layout"`
+	// Constraint the minimum version in the value must satisfy.
+	VersionConstraint string `yaml:"version-constraint"`
+	// Regular expression the value must match.
+	Pattern string `yaml:"pattern"`
+	// Inclusive limits the value must be within, e.g, "128M".
+	Min string `yaml:"min"`
+	Max string `yaml:"max"`
+	// Index of the document to verify, starting at 0.
+	Document *int `yaml:"document"`
+	// Only verifies the documents in which the selector's key equals its
+	// value, e.g, kind=Deployment.
+	Select *DocumentSelector `yaml:"select"`
+}
+
+// DocumentSelector selects documents by the value of one of their keys.
+type DocumentSelector struct {
+	Key   string `yaml:"key"`
+	Value string `yaml:"value"`
+}
+
+func (s DocumentSelector) String() string {
+	return fmt.Sprintf("%s=%s", s.Key, s.Value)
+}
+
+// IsDocumentCheck returns whether the documents verified are restricted.
+func (kv KeyValue) IsDocumentCheck() bool {
+	return kv.Document != nil || kv.Select != nil
+}
+
+// SelectDocuments returns the documents the KeyValue applies to.
+func (kv KeyValue) SelectDocuments(docs []yaml.Node) ([]yaml.Node, error) {
+	selected := []yaml.Node{}
+	for i, d := range docs {
+		if kv.Document != nil && *kv.Document != i {
+			continue
+		}
+		if kv.Select != nil {
+			found, err := utils.LookupYamlPath(&d, kv.Select.Key)
+			if err != nil {
+				return nil, err
+			}
+			matches := false
+			for _, n := range found {
+				if n.Value == kv.Select.Value {
+					matches = true
+					break
+				}
+			}
+			if !matches {
+				continue
+			}
+		}
+		selected = append(selected, d)
+	}
+	return selected, nil
 }
 
 // KeyValueResult represents the different outcomes of the KeyValue check.
@@ -29,6 +105,12 @@ const (
 	KeyValueNotEqual        KeyValueResult = 0
 	KeyValueEqual           KeyValueResult = 1
 	KeyValueDisallowedFound KeyValueResult = 2
+	KeyValueAgeBreach       KeyValueResult = 3
+	KeyValueVersionBreach   KeyValueResult = 4
+	KeyValuePatternBreach   KeyValueResult = 5
+	KeyValueThresholdBreach KeyValueResult = 6
+	// Constraints of different kinds failed.
+	KeyValueConstraintBreach KeyValueResult = 7
 )
 
 var truthyValues = []string{"1", "true"}
@@ -82,7 +164,9 @@ func (kv KeyValue) EqualsTruthy(value string) bool {
 }
 
 // IsDisallowed validates against the allow/disallow lists and returns
-// true if a disallowed value is present.
+// true if a disallowed value is present. Allowed entries can include a
+// version constraint, e.g, drupal/core:^10.3, verified against the version of
+// values such as drupal/core:10.3.6.
 func (kv KeyValue) IsDisallowed(value string) bool {
 
 	// Ignore blank and null values.
@@ -91,14 +175,229 @@ func (kv KeyValue) IsDisallowed(value string) bool {
 	}
 
 	// Check disallowed list.
-	if len(kv.Disallowed) > 0 && utils.StringSliceContains(kv.Disallowed, value) {
+	if len(kv.Disallowed) > 0 && utils.StringSliceMatchAny(kv.Disallowed, value) {
 		return true
 	}
 
 	// Check allowed list.
-	if len(kv.Allowed) > 0 && !utils.StringSliceContains(kv.Allowed, value) {
-		return true
+	if len(kv.Allowed) > 0 && !utils.StringSliceMatchAny(kv.Allowed, value) {
+		allowed, _, _ := kv.allowedVersion(value)
+		return !allowed
 	}
 
 	return false
 }
+
+// DisallowedValue returns the value to report when it is disallowed; for a
+// package not allowed by the version constraints of its Allowed entries, the
+// version and the constraints are reported, e.g, "drupal/core: version 10.2.8
+// does not satisfy '^10.3'".
+func (kv KeyValue) DisallowedValue(value string) string {
+	if len(kv.Disallowed) > 0 && utils.StringSliceMatchAny(kv.Disallowed, value) {
+		return value
+	}
+	name, ver := utils.SplitPackageVersion(value)
+	_, constraints, err := kv.allowedVersion(value)
+	if err != nil {
+		return fmt.Sprintf("%s: %s", name, err)
+	}
+	if len(constraints) == 0 {
+		return value
+	}
+	return fmt.Sprintf("%s: version %s does not satisfy '%s'",
+		name, ver, strings.Join(constraints, "' or '"))
+}
+
+// allowedVersion determines whether the value, as <name>:<version> or
+// <name>@<version>, is allowed by the Allowed entries with a version or
+// version constraint, e.g, drupal/core:^10.3.
+func (kv KeyValue) allowedVersion(value string) (bool, []string, error) {
+	entries := []string{}
+	for _, e := range kv.Allowed {
+		if strings.HasPrefix(e, "re:") || strings.HasPrefix(e, "glob:") {
+			continue
+		}
+		if _, constraint := utils.SplitPackageVersion(e); constraint != "" {
+			entries = append(entries, e)
+		}
+	}
+	name, ver := utils.SplitPackageVersion(value)
+	if len(entries) == 0 || ver == "" {
+		return false, nil, nil
+	}
+	return utils.PackageVersionAllowed(entries, name, ver)
+}
+
+// IsAgeCheck returns whether the KeyValue verifies the age of a timestamp.
+func (kv KeyValue) IsAgeCheck() bool {
+	return kv.MaxAge != "" || kv.MinAge != ""
+}
+
+// CheckAge parses the value as a timestamp and verifies its age against the
+// MaxAge and MinAge durations. A non-empty message is returned if the value
+// is older or newer than allowed.
+func (kv KeyValue) CheckAge(value string) (string, error) {
+	t, err := utils.ParseTimestamp(value, kv.TimeLayout)
+	if err != nil {
+		return "", err
+	}
+	age := utils.TimeNow().Sub(t).Truncate(time.Second)
+
+	if kv.MaxAge != "" {
+		maxAge, err := utils.ParseDuration(kv.MaxAge)
+		if err != nil {
+			return "", err
+		}
+		if age > maxAge {
+			return fmt.Sprintf("%s is older than %s (age: %s)", value, kv.MaxAge, age), nil
+		}
+	}
+
+	if kv.MinAge != "" {
+		minAge, err := utils.ParseDuration(kv.MinAge)
+		if err != nil {
+			return "", err
+		}
+		if age < minAge {
+			return fmt.Sprintf("%s is newer than %s (age: %s)", value, kv.MinAge, age), nil
+		}
+	}
+	return "", nil
+}
+
+// IsVersionCheck returns whether the KeyValue verifies a version constraint.
+func (kv KeyValue) IsVersionCheck() bool {
+	return kv.VersionConstraint != ""
+}
+
+// CheckVersion extracts the minimum version from the value and verifies it
+// against the VersionConstraint. A non-empty message is returned if the
+// constraint is not satisfied.
+func (kv KeyValue) CheckVersion(value string) (string, error) {
+	constraint, err := version.NewConstraint(kv.VersionConstraint)
+	if err != nil {
+		return "", err
+	}
+	v, err := utils.MinimumVersion(value)
+	if err != nil {
+		return "", err
+	}
+	if !constraint.Check(v) {
+		return fmt.Sprintf("%s does not satisfy '%s'", value, kv.VersionConstraint), nil
+	}
+	return "", nil
+}
+
+// IsPatternCheck returns whether the KeyValue verifies the value against a
+// regular expression.
+func (kv KeyValue) IsPatternCheck() bool {
+	return kv.Pattern != ""
+}
+
+// CheckPattern verifies the value matches the Pattern. A non-empty message is
+// returned if it does not.
+func (kv KeyValue) CheckPattern(value string) (string, error) {
+	re, err := regexp.Compile(kv.Pattern)
+	if err != nil {
+		return "", err
+	}
+	if !re.MatchString(value) {
+		return fmt.Sprintf("%s does not match '%s'", value, kv.Pattern), nil
+	}
+	return "", nil
+}
+
+// IsThresholdCheck returns whether the KeyValue verifies the value is within
+// numeric limits.
+func (kv KeyValue) IsThresholdCheck() bool {
+	return kv.Min != "" || kv.Max != ""
+}
+
+// CheckThreshold parses the value as a number and verifies it against the
+// Min and Max limits. A non-empty message is returned if it is out of range.
+// A value of -1 means unlimited, as in php.ini, e.g, "memory_limit = -1".
+func (kv KeyValue) CheckThreshold(value string) (string, error) {
+	n, err := utils.ParseSize(value)
+	if err != nil {
+		return "", err
+	}
+	if n == -1 {
+		n = math.Inf(1)
+	}
+
+	if kv.Min != "" {
+		min, err := utils.ParseSize(kv.Min)
+		if err != nil {
+			return "", err
+		}
+		if n < min {
+			return fmt.Sprintf("%s is less than %s", value, kv.Min), nil
+		}
+	}
+
+	if kv.Max != "" {
+		max, err := utils.ParseSize(kv.Max)
+		if err != nil {
+			return "", err
+		}
+		if n > max {
+			return fmt.Sprintf("%s is greater than %s", value, kv.Max), nil
+		}
+	}
+	return "", nil
+}
+
+// IsConstraintCheck returns whether the KeyValue verifies the age, version,
+// pattern or threshold of the value rather than the value itself.
+func (kv KeyValue) IsConstraintCheck() bool {
+	return kv.IsAgeCheck() || kv.IsVersionCheck() || kv.IsPatternCheck() ||
+		kv.IsThresholdCheck()
+}
+
+// CheckConstraint verifies the value using all the age, version, pattern and
+// threshold constraints set, returning the breach result to use along with
+// the messages of the failed constraints joined, if any. When constraints of
+// different kinds fail, KeyValueConstraintBreach is returned.
+func (kv KeyValue) CheckConstraint(value string) (KeyValueResult, string, error) {
+	checks := []struct {
+		enabled bool
+		kvr     KeyValueResult
+		check   func(string) (string, error)
+	}{
+		{kv.IsThresholdCheck(), KeyValueThresholdBreach, kv.CheckThreshold},
+		{kv.IsPatternCheck(), KeyValuePatternBreach, kv.CheckPattern},
+		{kv.IsVersionCheck(), KeyValueVersionBreach, kv.CheckVersion},
+		{kv.IsAgeCheck(), KeyValueAgeBreach, kv.CheckAge},
+	}
+	breach := KeyValueEqual
+	msgs := []string{}
+	for _, c := range checks {
+		if !c.enabled {
+			continue
+		}
+		msg, err := c.check(value)
+		if err != nil {
+			return c.kvr, "", err
+		}
+		if msg == "" {
+			continue
+		}
+		if breach == KeyValueEqual {
+			breach = c.kvr
+		} else {
+			breach = KeyValueConstraintBreach
+		}
+		msgs = append(msgs, msg)
+	}
+	return breach, strings.Join(msgs, "; "), nil
+}
+
+// MergeConstraintBreach combines the breach result of a value with the one of
+// the previous values; constraints of different kinds failing result in
+// KeyValueConstraintBreach.
+func MergeConstraintBreach(breach KeyValueResult, kvr KeyValueResult) KeyValueResult {
+	if breach == KeyValueEqual || breach == kvr {
+		return kvr
+	}
+	return KeyValueConstraintBreach
+}
@@ -2,9 +2,12 @@ package yaml_test
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 func TestMergeKeyValueSlice(t *testing.T) {
@@ -104,3 +107,131 @@ func TestEquals(t *testing.T) {
 		assert.True(kv.Equals("0"))
 	})
 }
+
+func TestKeyValueCheckAge(t *testing.T) {
+	assert := assert.New(t)
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Unix(1700000000, 0) }
+
+	kv := KeyValue{Key: "k"}
+	assert.False(kv.IsAgeCheck())
+
+	kv = KeyValue{Key: "k", MaxAge: "72h"}
+	assert.True(kv.IsAgeCheck())
+	msg, err := kv.CheckAge("1699900000")
+	assert.Nil(err)
+	assert.Equal("", msg)
+	msg, err = kv.CheckAge("1699000000")
+	assert.Nil(err)
+	assert.Equal("1699000000 is older than 72h (age: 277h46m40s)", msg)
+
+	kv = KeyValue{Key: "k", MinAge: "1d", TimeLayout: "2006-01-02 15:04"}
+	msg, err = kv.CheckAge("2023-11-14 20:00")
+	assert.Nil(err)
+	assert.Equal("2023-11-14 20:00 is newer than 1d (age: 2h13m20s)", msg)
+	_, err = kv.CheckAge("1699000000")
+	assert.Error(err)
+
+	kv = KeyValue{Key: "k", MaxAge: "soon"}
+	_, err = kv.CheckAge("1699000000")
+	assert.EqualError(err, `time: invalid duration "soon"`)
+}
+
+func TestKeyValueCheckVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	kv := KeyValue{Key: "k"}
+	assert.False(kv.IsVersionCheck())
+	assert.False(kv.IsConstraintCheck())
+
+	kv = KeyValue{Key: "k", VersionConstraint: ">= 18"}
+	assert.True(kv.IsVersionCheck())
+	assert.True(kv.IsConstraintCheck())
+	msg, err := kv.CheckVersion("^20.1")
+	assert.Nil(err)
+	assert.Equal("", msg)
+	msg, err = kv.CheckVersion(">=16.0.0")
+	assert.Nil(err)
+	assert.Equal(">=16.0.0 does not satisfy '>= 18'", msg)
+
+	kvr, msg, err := kv.CheckConstraint("v16")
+	assert.Nil(err)
+	assert.Equal(KeyValueVersionBreach, kvr)
+	assert.Equal("v16 does not satisfy '>= 18'", msg)
+
+	_, err = kv.CheckVersion("latest")
+	assert.EqualError(err, "no version found in 'latest'")
+
+	kv = KeyValue{Key: "k", VersionConstraint: "newest"}
+	_, err = kv.CheckVersion("18")
+	assert.EqualError(err, "Malformed constraint: newest")
+}
+
+func TestKeyValueCheckConstraint(t *testing.T) {
+	assert := assert.New(t)
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Unix(1700000000, 0) }
+
+	// All the constraints set are verified.
+	kv := KeyValue{Key: "k", VersionConstraint: ">= 1600000000", MaxAge: "72h"}
+	kvr, msg, err := kv.CheckConstraint("1699900000")
+	assert.Nil(err)
+	assert.Equal(KeyValueEqual, kvr)
+	assert.Equal("", msg)
+
+	kvr, msg, err = kv.CheckConstraint("1699000000")
+	assert.Nil(err)
+	assert.Equal(KeyValueAgeBreach, kvr)
+	assert.Equal("1699000000 is older than 72h (age: 277h46m40s)", msg)
+
+	kv = KeyValue{Key: "k", VersionConstraint: ">= 1700000000", MaxAge: "72h"}
+	kvr, msg, err = kv.CheckConstraint("1699000000")
+	assert.Nil(err)
+	assert.Equal(KeyValueConstraintBreach, kvr)
+	assert.Equal("1699000000 does not satisfy '>= 1700000000'; "+
+		"1699000000 is older than 72h (age: 277h46m40s)", msg)
+
+	kv = KeyValue{Key: "k", VersionConstraint: ">= 18", Pattern: "^v"}
+	_, _, err = kv.CheckConstraint("latest")
+	assert.EqualError(err, "no version found in 'latest'")
+
+	assert.Equal(KeyValueAgeBreach, MergeConstraintBreach(KeyValueEqual, KeyValueAgeBreach))
+	assert.Equal(KeyValueAgeBreach, MergeConstraintBreach(KeyValueAgeBreach, KeyValueAgeBreach))
+	assert.Equal(KeyValueConstraintBreach, MergeConstraintBreach(KeyValueAgeBreach, KeyValueVersionBreach))
+}
+
+func TestKeyValueAllowedVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	kv := KeyValue{Key: "k", IsList: true, Allowed: []string{"drupal/core:^10.3", "drupal/token", "re:^drupal/admin_"}}
+	assert.False(kv.IsDisallowed("drupal/core:10.3.6"))
+	assert.False(kv.IsDisallowed("drupal/token"))
+	assert.False(kv.IsDisallowed("drupal/admin_toolbar:3.4.1"))
+	assert.True(kv.IsDisallowed("drupal/core:10.2.8"))
+	assert.True(kv.IsDisallowed("drupal/core"))
+	assert.Equal("drupal/core: version 10.2.8 does not satisfy '^10.3'", kv.DisallowedValue("drupal/core:10.2.8"))
+	// Entries without a version only allow the exact value.
+	assert.True(kv.IsDisallowed("drupal/token:1.13"))
+	assert.Equal("drupal/token:1.13", kv.DisallowedValue("drupal/token:1.13"))
+	assert.Equal("drupal/devel:5.1", kv.DisallowedValue("drupal/devel:5.1"))
+
+	node := yamlv3.Node{}
+	yamlv3.Unmarshal([]byte(`
+packages:
+  - drupal/core:10.2.8
+  - drupal/token
+  - drupal/devel:5.1
+`), &node)
+	kvr, fails, err := CheckKeyValue(node, KeyValue{Key: "packages", IsList: true, Allowed: kv.Allowed})
+	assert.NoError(err)
+	assert.Equal(KeyValueDisallowedFound, kvr)
+	assert.Equal([]string{"drupal/core: version 10.2.8 does not satisfy '^10.3'", "drupal/devel:5.1"}, fails)
+
+	kv = KeyValue{Key: "k", Allowed: []string{"drupal/core:^x"}}
+	assert.True(kv.IsDisallowed("drupal/core:10.3.6"))
+	assert.Equal("drupal/core: invalid version constraint '^x': Malformed version: x", kv.DisallowedValue("drupal/core:10.3.6"))
+}
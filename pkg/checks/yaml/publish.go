@@ -0,0 +1,148 @@
+package yaml
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Publication represents data derived from a check's Yaml data, published
+// under Name so that subsequent checks can consume it.
+// The data is the list of values found at Key, e.g, a filtered list such as
+// "$.items[?(@.enabled == true)].name", or a single value if only one is
+// found. If Aggregate is set, the values - and items of the lists and maps
+// found - are instead reduced to their count, sum, min, max or list of unique
+// values.
+type Publication struct {
+	Name      string `yaml:"name"`
+	Key       string `yaml:"key"`
+	Aggregate string `yaml:"aggregate"`
+}
+
+// PublishesData implements config.DataPublisher.
+func (c *YamlBase) PublishesData() []string {
+	names := []string{}
+	for _, p := range c.Publish {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// PublishData publishes the data derived from the NodeMap for each of the
+// publications.
+func (c *YamlBase) PublishData() {
+	configNames := []string{}
+	for configName := range c.NodeMap {
+		configNames = append(configNames, configName)
+	}
+	sort.Strings(configNames)
+
+	for _, p := range c.Publish {
+		found := []*yaml.Node{}
+		for _, configName := range configNames {
+			n := c.NodeMap[configName]
+			nodes, err := utils.LookupYamlPath(&n, p.Key)
+			if err != nil {
+				c.AddBreach(&result.KeyValueBreach{
+					KeyLabel:   "publish",
+					Key:        p.Name,
+					ValueLabel: "invalid key",
+					Value:      err.Error(),
+				})
+				return
+			}
+			found = append(found, nodes...)
+		}
+
+		data, err := DerivePublicationNode(found, p.Aggregate)
+		if err == nil {
+			var out []byte
+			out, err = yaml.Marshal(data)
+			if err == nil {
+				config.PublishData(p.Name, out)
+				continue
+			}
+		}
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "publish",
+			Key:        p.Name,
+			ValueLabel: "error deriving data",
+			Value:      err.Error(),
+		})
+	}
+}
+
+// DerivePublicationNode builds the node to publish from the nodes found,
+// reducing them using the aggregate if provided.
+func DerivePublicationNode(found []*yaml.Node, aggregate string) (*yaml.Node, error) {
+	if aggregate == "" {
+		if len(found) == 1 {
+			return found[0], nil
+		}
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: found}, nil
+	}
+
+	// Flatten the lists and maps found into their items.
+	values := []*yaml.Node{}
+	for _, n := range found {
+		switch n.Kind {
+		case yaml.SequenceNode:
+			values = append(values, n.Content...)
+		case yaml.MappingNode:
+			for i := 1; i < len(n.Content); i += 2 {
+				values = append(values, n.Content[i])
+			}
+		default:
+			values = append(values, n)
+		}
+	}
+
+	switch aggregate {
+	case "count":
+		return scalarNode(float64(len(values))), nil
+	case "sum", "min", "max":
+		var agg float64
+		for i, v := range values {
+			f, err := strconv.ParseFloat(v.Value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("non-numeric value '%s'", v.Value)
+			}
+			if i == 0 || aggregate == "sum" {
+				if aggregate == "sum" {
+					agg += f
+				} else {
+					agg = f
+				}
+				continue
+			}
+			if (aggregate == "min" && f < agg) || (aggregate == "max" && f > agg) {
+				agg = f
+			}
+		}
+		return scalarNode(agg), nil
+	case "unique":
+		seen := []string{}
+		for _, v := range values {
+			if !utils.StringSliceContains(seen, v.Value) {
+				seen = append(seen, v.Value)
+			}
+		}
+		sort.Strings(seen)
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, s := range seen {
+			seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s})
+		}
+		return seq, nil
+	}
+	return nil, fmt.Errorf("unsupported aggregate '%s'", aggregate)
+}
+
+func scalarNode(f float64) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatFloat(f, 'f', -1, 64)}
+}
@@ -0,0 +1,117 @@
+package yaml_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDerivePublicationNode(t *testing.T) {
+	data := `
+items:
+  - name: a
+    size: 2
+  - name: b
+    size: 5
+  - name: a
+    size: 1
+`
+	n := yaml.Node{}
+	if err := yaml.Unmarshal([]byte(data), &n); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		name        string
+		key         string
+		aggregate   string
+		expected    string
+		expectedErr string
+	}{
+		{name: "single", key: "$.items[0].name", expected: "a\n"},
+		{name: "list", key: "$.items[*].name", expected: "- a\n- b\n- a\n"},
+		{name: "filtered", key: "$.items[?(@.size > 1)].name", expected: "- a\n- b\n"},
+		{name: "count", key: "$.items", aggregate: "count", expected: "3\n"},
+		{name: "sum", key: "$.items[*].size", aggregate: "sum", expected: "8\n"},
+		{name: "min", key: "$.items[*].size", aggregate: "min", expected: "1\n"},
+		{name: "max", key: "$.items[*].size", aggregate: "max", expected: "5\n"},
+		{name: "unique", key: "$.items[*].name", aggregate: "unique", expected: "- a\n- b\n"},
+		{name: "nonNumeric", key: "$.items[*].name", aggregate: "sum", expectedErr: "non-numeric value 'a'"},
+		{name: "unsupported", key: "$.items", aggregate: "avg", expectedErr: "unsupported aggregate 'avg'"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			found, err := utils.LookupYamlPath(&n, tc.key)
+			assert.NoError(err)
+			node, err := DerivePublicationNode(found, tc.aggregate)
+			if tc.expectedErr != "" {
+				assert.EqualError(err, tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+			out, err := yaml.Marshal(node)
+			assert.NoError(err)
+			assert.Equal(tc.expected, string(out))
+		})
+	}
+}
+
+func TestYamlCheckPublishData(t *testing.T) {
+	assert := assert.New(t)
+	config.ResetPublishedData()
+	defer config.ResetPublishedData()
+
+	publisher := YamlCheck{
+		YamlBase: YamlBase{
+			Publish: []Publication{
+				{Name: "modules", Key: "$.module"},
+				{Name: "modules-count", Key: "$.module", Aggregate: "count"},
+			},
+		},
+		Path: "testdata",
+		File: "core.extension.yml",
+	}
+	assert.Equal([]string{"modules", "modules-count"}, publisher.PublishesData())
+	publisher.Init(Yaml)
+	publisher.FetchData()
+	publisher.UnmarshalDataMap()
+	publisher.RunCheck()
+	assert.Empty(publisher.Result.Breaches)
+
+	data, ok := config.GetPublishedData("modules")
+	assert.True(ok)
+	assert.Equal("block: 0\nnode: 0\n", string(data))
+	data, ok = config.GetPublishedData("modules-count")
+	assert.True(ok)
+	assert.Equal("2\n", string(data))
+
+	consumer := YamlCheck{
+		YamlBase: YamlBase{Values: []KeyValue{{Key: "block", Value: "0"}}},
+		From:     "modules",
+	}
+	assert.Equal([]string{"modules"}, consumer.ConsumesData())
+	consumer.Init(Yaml)
+	consumer.FetchData()
+	consumer.UnmarshalDataMap()
+	consumer.RunCheck()
+	assert.Equal(result.Pass, consumer.Result.Status)
+	assert.Equal([]string{"[modules] 'block' equals '0'"}, consumer.Result.Passes)
+
+	consumer = YamlCheck{From: "unknown"}
+	consumer.Init(Yaml)
+	consumer.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "yaml",
+		Severity:   "normal",
+		ValueLabel: "no published data",
+		Value:      "unknown",
+	}}, consumer.Result.Breaches)
+}
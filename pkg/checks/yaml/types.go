@@ -9,8 +9,13 @@ import (
 type YamlBase struct {
 	config.CheckBase `yaml:",inline"`
 	Values           []KeyValue `yaml:"values"`
-	Node             yaml.Node
-	NodeMap          map[string]yaml.Node
+	// Data derived from the Yaml data, for subsequent checks to consume.
+	Publish []Publication `yaml:"publish"`
+	Node    yaml.Node
+	NodeMap map[string]yaml.Node
+	// All the documents of multi-document data, the first one being in the
+	// NodeMap.
+	Documents map[string][]yaml.Node
 }
 
 // YamlCheck represents a Yaml file-based check, which can be for a single file
@@ -22,6 +27,16 @@ type YamlCheck struct {
 	Files          []string `yaml:"files"`           // A list of files to lint.
 	Pattern        string   `yaml:"pattern"`         // Pattern-based files.
 	ExcludePattern string   `yaml:"exclude-pattern"` // Pattern-based excluded files.
+	From           string   `yaml:"from"`            // Name of data published by another check.
+	Stdin          string   `yaml:"stdin"`           // Format of the data piped to stdin: json, yaml or raw.
+	// Command writing its output to the file passed as $1, for tools which
+	// don't output the data to stdout.
+	Command string `yaml:"command"`
+	// Format of the file written by the command: json (default), yaml or raw.
+	CommandFormat string `yaml:"command-format"`
+
+	// Restricts the check to a subset of the Files or Pattern-based files.
+	Sample *config.Sample `yaml:"sample,omitempty"`
 
 	// IgnoreMissing allows non-existent files to not be counted as a Fail.
 	// Using a pointer here so we can differentiate between
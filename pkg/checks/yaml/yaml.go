@@ -1,8 +1,11 @@
 package yaml
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
@@ -30,11 +33,15 @@ func (c *YamlBase) Merge(mergeCheck config.Check) error {
 	}
 
 	MergeKeyValueSlice(&c.Values, yBaseCheck.Values)
+	if len(yBaseCheck.Publish) > 0 {
+		c.Publish = yBaseCheck.Publish
+	}
 	return nil
 }
 
 // RunCheck implements the base logic for running checks against Yaml data.
 func (c *YamlBase) RunCheck() {
+	c.PublishData()
 	for configName := range c.DataMap {
 		c.determineBreaches(configName)
 	}
@@ -45,58 +52,189 @@ func (c *YamlBase) RunCheck() {
 
 // UnmarshalDataMap parses the DataMap into Yaml for further processing.
 // DataMap is expected to be populated from FetchData in the respective Check
-// implementation.
+// implementation. All the documents of multi-document data are parsed.
 func (c *YamlBase) UnmarshalDataMap() {
 	c.NodeMap = map[string]yaml.Node{}
-	for configName, data := range c.DataMap {
-		n := yaml.Node{}
-		err := yaml.Unmarshal([]byte(data), &n)
+	c.Documents = map[string][]yaml.Node{}
+	for _, configName := range utils.SortedKeys(c.DataMap) {
+		docs, err := unmarshalDocuments(c.DataMap[configName])
 		if err != nil {
 			c.AddBreach(&result.ValueBreach{Value: err.Error()})
 			return
 		}
-		c.NodeMap[configName] = n
+		c.NodeMap[configName] = yaml.Node{}
+		if len(docs) > 0 {
+			c.NodeMap[configName] = docs[0]
+		}
+		c.Documents[configName] = docs
+	}
+}
+
+// unmarshalDocuments parses each of the documents in the data.
+func unmarshalDocuments(data []byte) ([]yaml.Node, error) {
+	docs := []yaml.Node{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		n := yaml.Node{}
+		if err := dec.Decode(&n); errors.Is(err, io.EOF) {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		docs = append(docs, n)
+	}
+}
+
+// documents returns the documents of the config; checks populating the
+// NodeMap themselves only have one.
+func (c *YamlBase) documents(configName string) []yaml.Node {
+	if docs, ok := c.Documents[configName]; ok && len(docs) > 0 {
+		return docs
 	}
+	return []yaml.Node{c.NodeMap[configName]}
 }
 
 // determineBreaches runs the actual checks against the list of KeyValues provided in
 // the Check configuration and determines possible breaches.
 func (c *YamlBase) determineBreaches(configName string) {
 	for _, kv := range c.Values {
-		kvr, fails, err := CheckKeyValue(c.NodeMap[configName], kv)
-		switch kvr {
-		case KeyValueError:
+		docs, err := kv.SelectDocuments(c.documents(configName))
+		if err != nil {
 			c.AddBreach(&result.ValueBreach{Value: err.Error()})
-		case KeyValueNotFound:
-			c.AddBreach(&result.KeyValueBreach{
-				KeyLabel:   "config",
-				Key:        configName,
-				ValueLabel: "key not found",
-				Value:      kv.Key,
-			})
-		case KeyValueNotEqual:
+			continue
+		}
+		if len(docs) == 0 {
 			c.AddBreach(&result.KeyValueBreach{
-				KeyLabel:      "config:" + configName,
-				Key:           kv.Key,
-				ValueLabel:    "actual",
-				ExpectedValue: kv.Value,
-				Value:         fails[0],
-			})
-		case KeyValueDisallowedFound:
-			c.AddBreach(&result.KeyValuesBreach{
 				KeyLabel:   "config",
 				Key:        configName,
-				ValueLabel: fmt.Sprintf("disallowed %s", kv.Key),
-				Values:     fails,
+				ValueLabel: "no document matching",
+				Value:      documentsLabel(kv),
 			})
+			continue
+		}
+		kvr, fails, err := CheckDocumentsKeyValue(docs, kv)
+		AddKeyValueResult(&c.CheckBase, configName, "config:"+configName, kv, kvr, fails, err)
+	}
+}
+
+// AddKeyValueResult adds the breach or pass corresponding to the result of
+// verifying a KeyValue against a config to the check; keyLabel is the label of
+// the breach when the value is not the one expected.
+func AddKeyValueResult(c *config.CheckBase, configName string, keyLabel string,
+	kv KeyValue, kvr KeyValueResult, fails []string, err error) {
+	switch kvr {
+	case KeyValueError:
+		c.AddBreach(&result.ValueBreach{Value: err.Error()})
+	case KeyValueNotFound:
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "config",
+			Key:        configName,
+			ValueLabel: "key not found",
+			Value:      kv.Key,
+		})
+	case KeyValueNotEqual:
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:      keyLabel,
+			Key:           kv.Key,
+			ValueLabel:    "actual",
+			ExpectedValue: kv.Value,
+			Value:         fails[0],
+		})
+	case KeyValueDisallowedFound:
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "config",
+			Key:        configName,
+			ValueLabel: fmt.Sprintf("disallowed %s", kv.Key),
+			Values:     fails,
+		})
+	case KeyValueAgeBreach:
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "config",
+			Key:        configName,
+			ValueLabel: fmt.Sprintf("invalid age for %s", kv.Key),
+			Values:     fails,
+		})
+	case KeyValueVersionBreach:
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "config",
+			Key:        configName,
+			ValueLabel: fmt.Sprintf("invalid version for %s", kv.Key),
+			Values:     fails,
+		})
+	case KeyValuePatternBreach, KeyValueConstraintBreach:
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "config",
+			Key:        configName,
+			ValueLabel: fmt.Sprintf("invalid value for %s", kv.Key),
+			Values:     fails,
+		})
+	case KeyValueThresholdBreach:
+		c.AddBreach(&result.KeyValuesBreach{
+			KeyLabel:   "config",
+			Key:        configName,
+			ValueLabel: fmt.Sprintf("out of range %s", kv.Key),
+			Values:     fails,
+		})
+	case KeyValueEqual:
+		if kv.IsThresholdCheck() {
+			c.AddPass(fmt.Sprintf("[%s] '%s' is within limits", configName, kv.Key))
+		} else if kv.IsPatternCheck() {
+			c.AddPass(fmt.Sprintf("[%s] '%s' matches '%s'", configName, kv.Key, kv.Pattern))
+		} else if kv.IsAgeCheck() {
+			c.AddPass(fmt.Sprintf("[%s] '%s' age is within limits", configName, kv.Key))
+		} else if kv.IsVersionCheck() {
+			c.AddPass(fmt.Sprintf("[%s] '%s' satisfies '%s'", configName, kv.Key, kv.VersionConstraint))
+		} else if kv.IsList {
+			c.AddPass(fmt.Sprintf("[%s] no disallowed '%s'", configName, kv.Key))
+		} else {
+			c.AddPass(fmt.Sprintf("[%s] '%s' equals '%s'", configName, kv.Key, kv.Value))
+		}
+	}
+}
+
+func documentsLabel(kv KeyValue) string {
+	labels := []string{}
+	if kv.Document != nil {
+		labels = append(labels, fmt.Sprintf("document %d", *kv.Document))
+	}
+	if kv.Select != nil {
+		labels = append(labels, kv.Select.String())
+	}
+	return strings.Join(labels, ", ")
+}
+
+// CheckDocumentsKeyValue verifies a KeyValue against each of the documents;
+// the key is only not found if it is missing from all of them, while the
+// values breaching in any of them are returned.
+func CheckDocumentsKeyValue(docs []yaml.Node, kv KeyValue) (KeyValueResult, []string, error) {
+	if len(docs) == 1 {
+		return CheckKeyValue(docs[0], kv)
+	}
+	final := KeyValueNotFound
+	fails := []string{}
+	for _, d := range docs {
+		kvr, docFails, err := CheckKeyValue(d, kv)
+		switch kvr {
+		case KeyValueError:
+			return kvr, nil, err
+		case KeyValueNotFound:
 		case KeyValueEqual:
-			if kv.IsList {
-				c.AddPass(fmt.Sprintf("[%s] no disallowed '%s'", configName, kv.Key))
-			} else {
-				c.AddPass(fmt.Sprintf("[%s] '%s' equals '%s'", configName, kv.Key, kv.Value))
+			if final == KeyValueNotFound {
+				final = KeyValueEqual
+			}
+		default:
+			final = kvr
+			for _, f := range docFails {
+				if !utils.StringSliceContains(fails, f) {
+					fails = append(fails, f)
+				}
 			}
 		}
 	}
+	if final == KeyValueNotFound || final == KeyValueEqual {
+		return final, nil, nil
+	}
+	return final, fails, nil
 }
 
 // CheckKeyValue lookups the Yaml data for a specific KeyValue and returns the
@@ -111,6 +249,31 @@ func CheckKeyValue(node yaml.Node, kv KeyValue) (KeyValueResult, []string, error
 		return KeyValueNotFound, nil, nil
 	}
 
+	if kv.IsConstraintCheck() {
+		fails := []string{}
+		breach := KeyValueEqual
+		for _, item := range foundNodes {
+			values := []*yaml.Node{item}
+			if kv.IsList {
+				values = item.Content
+			}
+			for _, v := range values {
+				kvr, msg, err := kv.CheckConstraint(v.Value)
+				if err != nil {
+					return KeyValueError, nil, err
+				}
+				if msg != "" {
+					breach = MergeConstraintBreach(breach, kvr)
+					fails = append(fails, msg)
+				}
+			}
+		}
+		if len(fails) > 0 {
+			return breach, fails, nil
+		}
+		return KeyValueEqual, nil, nil
+	}
+
 	// Throw an error if we are checking a list but no allow/disallow list provided.
 	if len(kv.Allowed) == 0 && len(kv.Disallowed) == 0 && kv.IsList {
 		return KeyValueError, nil, errors.New("list of allowed or disallowed values not provided")
@@ -135,13 +298,13 @@ func CheckKeyValue(node yaml.Node, kv KeyValue) (KeyValueResult, []string, error
 	for _, item := range foundNodes {
 		if kv.IsList {
 			for _, v := range item.Content {
-				if kv.IsDisallowed(v.Value) && !utils.StringSliceContains(fails, v.Value) {
-					fails = append(fails, v.Value)
+				if kv.IsDisallowed(v.Value) && !utils.StringSliceContains(fails, kv.DisallowedValue(v.Value)) {
+					fails = append(fails, kv.DisallowedValue(v.Value))
 				}
 			}
 		} else {
-			if kv.IsDisallowed(item.Value) && !utils.StringSliceContains(fails, item.Value) {
-				fails = append(fails, item.Value)
+			if kv.IsDisallowed(item.Value) && !utils.StringSliceContains(fails, kv.DisallowedValue(item.Value)) {
+				fails = append(fails, kv.DisallowedValue(item.Value))
 			}
 		}
 	}
@@ -3,12 +3,14 @@ package yaml_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	yamlv3 "gopkg.in/yaml.v3"
 
 	. "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -215,6 +217,18 @@ foo:
 			expectedValues: []string{"baz", "zoo"},
 			expectedError:  nil,
 		},
+		{
+			name: "multivalue - disallowed patterns in yaml",
+			node: multiValueNode,
+			keyValue: KeyValue{
+				Key:        "foo.bar",
+				IsList:     true,
+				Disallowed: []string{"re:^zoo", "glob:b?z"},
+			},
+			expectedResult: KeyValueDisallowedFound,
+			expectedValues: []string{"baz", "zoo", "zoom"},
+			expectedError:  nil,
+		},
 		{
 			name: "multivalue - no disallowed values in yaml",
 			node: multiValueNode,
@@ -239,6 +253,18 @@ foo:
 			expectedValues: nil,
 			expectedError:  nil,
 		},
+		{
+			name: "multivalue - allowed patterns in yaml all match",
+			node: multiValueNode,
+			keyValue: KeyValue{
+				Key:     "foo.bar",
+				IsList:  true,
+				Allowed: []string{"baz", "glob:zoo*"},
+			},
+			expectedResult: KeyValueEqual,
+			expectedValues: nil,
+			expectedError:  nil,
+		},
 		{
 			name: "multivalue - value not in allowed list",
 			node: multiValueNode,
@@ -265,6 +291,176 @@ foo:
 	}
 }
 
+func TestYamlCheckKeyValueAge(t *testing.T) {
+	assert := assert.New(t)
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Unix(1700000000, 0) }
+
+	node := yamlv3.Node{}
+	yamlv3.Unmarshal([]byte(`
+backup:
+  last: 1699990000
+  all:
+    - 1699990000
+    - 1690000000
+`), &node)
+
+	kvr, values, err := CheckKeyValue(node, KeyValue{Key: "backup.last", MaxAge: "24h"})
+	assert.Nil(err)
+	assert.Equal(KeyValueEqual, kvr)
+	assert.Empty(values)
+
+	kvr, values, err = CheckKeyValue(node, KeyValue{Key: "backup.all", IsList: true, MaxAge: "24h"})
+	assert.Nil(err)
+	assert.Equal(KeyValueAgeBreach, kvr)
+	assert.EqualValues([]string{"1690000000 is older than 24h (age: 2777h46m40s)"}, values)
+
+	kvr, _, err = CheckKeyValue(node, KeyValue{Key: "backup", MaxAge: "24h", TimeLayout: "unix"})
+	assert.Equal(KeyValueError, kvr)
+	assert.EqualError(err, "invalid unix timestamp ''")
+
+	c := YamlBase{
+		Values: []KeyValue{
+			{Key: "backup.last", MinAge: "3h"},
+		},
+	}
+	c.NodeMap = map[string]yamlv3.Node{"backup.yml": node}
+	c.DataMap = map[string][]byte{"backup.yml": nil}
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.EqualValues([]result.Breach{&result.KeyValuesBreach{
+		BreachType: "key-values",
+		KeyLabel:   "config",
+		Key:        "backup.yml",
+		ValueLabel: "invalid age for backup.last",
+		Values:     []string{"1699990000 is newer than 3h (age: 2h46m40s)"},
+	}}, c.Result.Breaches)
+}
+
+func TestYamlCheckKeyValueVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	node := yamlv3.Node{}
+	yamlv3.Unmarshal([]byte(`
+runtime:
+  php: "8.1"
+  node: ^16.2
+`), &node)
+
+	kvr, values, err := CheckKeyValue(node, KeyValue{Key: "runtime.php", VersionConstraint: ">= 8.1"})
+	assert.Nil(err)
+	assert.Equal(KeyValueEqual, kvr)
+	assert.Empty(values)
+
+	c := YamlBase{
+		Values: []KeyValue{
+			{Key: "runtime.php", VersionConstraint: ">= 8.1"},
+			{Key: "runtime.node", VersionConstraint: ">= 18"},
+		},
+	}
+	c.NodeMap = map[string]yamlv3.Node{"runtime.yml": node}
+	c.DataMap = map[string][]byte{"runtime.yml": nil}
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.EqualValues([]string{"[runtime.yml] 'runtime.php' satisfies '>= 8.1'"}, c.Result.Passes)
+	assert.EqualValues([]result.Breach{&result.KeyValuesBreach{
+		BreachType: "key-values",
+		KeyLabel:   "config",
+		Key:        "runtime.yml",
+		ValueLabel: "invalid version for runtime.node",
+		Values:     []string{"^16.2 does not satisfy '>= 18'"},
+	}}, c.Result.Breaches)
+}
+
+func TestYamlCheckKeyValuePattern(t *testing.T) {
+	assert := assert.New(t)
+
+	node := yamlv3.Node{}
+	yamlv3.Unmarshal([]byte(`
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@8ade135a41bc03ea155e62e844d188df1ea18608
+      - uses: actions/setup-node@v4
+      - run: npm ci
+`), &node)
+
+	kvr, _, err := CheckKeyValue(node, KeyValue{Key: "jobs.build.steps[0].uses", Pattern: "("})
+	assert.Equal(KeyValueError, kvr)
+	assert.EqualError(err, "error parsing regexp: missing closing ): `(`")
+
+	c := YamlBase{
+		Values: []KeyValue{
+			{Key: "jobs.build~", Pattern: "^(build|test)$"},
+			{Key: "jobs.*.steps[*].uses", Pattern: "@[0-9a-f]{40}$"},
+		},
+	}
+	c.NodeMap = map[string]yamlv3.Node{"ci.yml": node}
+	c.DataMap = map[string][]byte{"ci.yml": nil}
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.EqualValues([]string{"[ci.yml] 'jobs.build~' matches '^(build|test)$'"}, c.Result.Passes)
+	assert.EqualValues([]result.Breach{&result.KeyValuesBreach{
+		BreachType: "key-values",
+		KeyLabel:   "config",
+		Key:        "ci.yml",
+		ValueLabel: "invalid value for jobs.*.steps[*].uses",
+		Values:     []string{"actions/setup-node@v4 does not match '@[0-9a-f]{40}$'"},
+	}}, c.Result.Breaches)
+}
+
+func TestYamlCheckKeyValueThreshold(t *testing.T) {
+	assert := assert.New(t)
+
+	node := yamlv3.Node{}
+	yamlv3.Unmarshal([]byte(`
+php:
+  memory_limit: 128M
+  max_execution_time: 30
+  upload_max_filesize: unlimited
+`), &node)
+
+	kvr, _, err := CheckKeyValue(node, KeyValue{Key: "php.upload_max_filesize", Max: "64M"})
+	assert.Equal(KeyValueError, kvr)
+	assert.EqualError(err, "invalid number 'unlimited'")
+
+	c := YamlBase{
+		Values: []KeyValue{
+			{Key: "php.max_execution_time", Min: "10", Max: "60"},
+			{Key: "php.memory_limit", Min: "256M"},
+		},
+	}
+	c.NodeMap = map[string]yamlv3.Node{"php.yml": node}
+	c.DataMap = map[string][]byte{"php.yml": nil}
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.EqualValues([]string{"[php.yml] 'php.max_execution_time' is within limits"}, c.Result.Passes)
+	assert.EqualValues([]result.Breach{&result.KeyValuesBreach{
+		BreachType: "key-values",
+		KeyLabel:   "config",
+		Key:        "php.yml",
+		ValueLabel: "out of range php.memory_limit",
+		Values:     []string{"128M is less than 256M"},
+	}}, c.Result.Breaches)
+
+	// -1 is unlimited.
+	msg, err := KeyValue{Key: "memory_limit", Min: "256M"}.CheckThreshold("-1")
+	assert.NoError(err)
+	assert.Empty(msg)
+	msg, err = KeyValue{Key: "memory_limit", Max: "1G"}.CheckThreshold("-1")
+	assert.NoError(err)
+	assert.Equal("-1 is greater than 1G", msg)
+	msg, err = KeyValue{Key: "offset", Min: "-2"}.CheckThreshold("-3")
+	assert.NoError(err)
+	assert.Equal("-3 is less than -2", msg)
+}
+
 func TestYamlBase(t *testing.T) {
 	assert := assert.New(t)
 
@@ -455,3 +651,126 @@ foo:
 	assert.EqualValues(0, len(c.Result.Breaches))
 	assert.EqualValues([]string{"[data] no disallowed 'foo'"}, c.Result.Passes)
 }
+
+func TestYamlCheckMultiDocument(t *testing.T) {
+	assert := assert.New(t)
+
+	c := YamlBase{
+		CheckBase: config.CheckBase{
+			DataMap: map[string][]byte{
+				"k8s.yml": []byte(`
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+kind: Service
+metadata:
+  name: web
+---
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 3
+`),
+			},
+		},
+	}
+	c.UnmarshalDataMap()
+	assert.Empty(c.Result.Breaches)
+	assert.Len(c.Documents["k8s.yml"], 3)
+	first, _ := utils.LookupYamlPath(&c.Documents["k8s.yml"][2], "metadata.name")
+	assert.Equal("worker", first[0].Value)
+	first, _ = utils.LookupYamlPath(&c.Documents["k8s.yml"][0], "kind")
+	assert.Equal("Deployment", first[0].Value)
+	node := c.NodeMap["k8s.yml"]
+	first, _ = utils.LookupYamlPath(&node, "metadata.name")
+	assert.Equal("web", first[0].Value)
+
+	docs, err := KeyValue{Select: &DocumentSelector{Key: "kind", Value: "Deployment"}}.
+		SelectDocuments(c.Documents["k8s.yml"])
+	assert.NoError(err)
+	assert.Len(docs, 2)
+	document := 1
+	docs, _ = KeyValue{Document: &document}.SelectDocuments(c.Documents["k8s.yml"])
+	assert.Len(docs, 1)
+
+	worker := 2
+	missing := 5
+	c.Values = []KeyValue{
+		// Looked up in all the documents, only missing from the Service.
+		{Key: "spec.replicas", Min: "2"},
+		{Key: "kind", Value: "Service", Document: &document},
+		{Key: "metadata.name", Value: "worker", Document: &worker,
+			Select: &DocumentSelector{Key: "kind", Value: "Deployment"}},
+		{Key: "kind", Value: "Deployment", Document: &missing},
+		{Key: "spec.replicas", Value: "1", Select: &DocumentSelector{Key: "kind", Value: "Job"}},
+		{Key: "spec.type", Value: "ClusterIP", Select: &DocumentSelector{Key: "kind", Value: "Service"}},
+	}
+	c.RunCheck()
+	c.Result.DetermineResultStatus(false)
+	assert.Equal(result.Fail, c.Result.Status)
+	assert.Equal([]string{
+		"[k8s.yml] 'kind' equals 'Service'",
+		"[k8s.yml] 'metadata.name' equals 'worker'",
+	}, c.Result.Passes)
+	assert.EqualValues([]result.Breach{
+		&result.KeyValuesBreach{
+			BreachType: "key-values",
+			KeyLabel:   "config",
+			Key:        "k8s.yml",
+			ValueLabel: "out of range spec.replicas",
+			Values:     []string{"1 is less than 2"},
+		},
+		&result.KeyValueBreach{
+			BreachType: "key-value",
+			KeyLabel:   "config",
+			Key:        "k8s.yml",
+			ValueLabel: "no document matching",
+			Value:      "document 5",
+		},
+		&result.KeyValueBreach{
+			BreachType: "key-value",
+			KeyLabel:   "config",
+			Key:        "k8s.yml",
+			ValueLabel: "no document matching",
+			Value:      "kind=Job",
+		},
+		&result.KeyValueBreach{
+			BreachType: "key-value",
+			KeyLabel:   "config",
+			Key:        "k8s.yml",
+			ValueLabel: "key not found",
+			Value:      "spec.type",
+		},
+	}, c.Result.Breaches)
+}
+
+func TestAddKeyValueResult(t *testing.T) {
+	assert := assert.New(t)
+
+	c := config.CheckBase{}
+	AddKeyValueResult(&c, "composer.json", "composer.json", KeyValue{Key: "name", Value: "foo"}, KeyValueNotEqual, []string{"bar"}, nil)
+	AddKeyValueResult(&c, "composer.json", "composer.json", KeyValue{Key: "version", VersionConstraint: ">= 2"}, KeyValueVersionBreach, []string{"1 does not satisfy '>= 2'"}, nil)
+	AddKeyValueResult(&c, "composer.json", "composer.json", KeyValue{Key: "type", Value: "project"}, KeyValueEqual, nil, nil)
+	assert.EqualValues([]result.Breach{
+		&result.KeyValueBreach{
+			BreachType:    "key-value",
+			KeyLabel:      "composer.json",
+			Key:           "name",
+			ValueLabel:    "actual",
+			Value:         "bar",
+			ExpectedValue: "foo",
+		},
+		&result.KeyValuesBreach{
+			BreachType: "key-values",
+			KeyLabel:   "config",
+			Key:        "composer.json",
+			ValueLabel: "invalid version for version",
+			Values:     []string{"1 does not satisfy '>= 2'"},
+		},
+	}, c.Result.Breaches)
+	assert.EqualValues([]string{"[composer.json] 'type' equals 'project'"}, c.Result.Passes)
+}
@@ -1,14 +1,18 @@
 package yaml
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
 	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Merge implementation for Yaml check.
@@ -23,10 +27,25 @@ func (c *YamlCheck) Merge(mergeCheck config.Check) error {
 	utils.MergeStringSlice(&c.Files, yCheck.Files)
 	utils.MergeString(&c.Pattern, yCheck.Pattern)
 	utils.MergeString(&c.ExcludePattern, yCheck.ExcludePattern)
+	utils.MergeString(&c.From, yCheck.From)
+	utils.MergeString(&c.Stdin, yCheck.Stdin)
+	utils.MergeString(&c.Command, yCheck.Command)
+	utils.MergeString(&c.CommandFormat, yCheck.CommandFormat)
 	utils.MergeBoolPtrs(c.IgnoreMissing, yCheck.IgnoreMissing)
+	if yCheck.Sample != nil {
+		c.Sample = yCheck.Sample
+	}
 	return nil
 }
 
+// ConsumesData implements config.DataConsumer.
+func (c *YamlCheck) ConsumesData() []string {
+	if c.From == "" {
+		return nil
+	}
+	return []string{c.From}
+}
+
 // readFile attempts to read a file and assign it to the check's data map using
 // the provided file key.
 func (c *YamlCheck) readFile(fkey string, fname string) {
@@ -45,15 +64,107 @@ func (c *YamlCheck) readFile(fkey string, fname string) {
 	}
 }
 
+// convertData converts data in the given format to json, which can be parsed
+// by both the yaml and json checks.
+func convertData(format string, data []byte) ([]byte, error) {
+	switch format {
+	case "json":
+		return data, nil
+	case "yaml":
+		var v any
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	case "raw":
+		// A json string is also a valid yaml scalar.
+		return json.Marshal(string(data))
+	}
+	return nil, fmt.Errorf("unsupported format '%s'", format)
+}
+
+// readStdin reads the data piped to stdin.
+func (c *YamlCheck) readStdin() {
+	data, err := config.ReadStdin()
+	if err == nil {
+		c.DataMap["stdin"], err = convertData(c.Stdin, data)
+	}
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error reading stdin",
+			Value:      err.Error()})
+	}
+}
+
+// Commands implements config.CommandReporter.
+func (c *YamlCheck) Commands() [][]string {
+	if c.Command == "" {
+		return nil
+	}
+	return [][]string{{"sh", "-c", c.Command, "shipshape", "<output file>"}}
+}
+
+// readCommandOutput runs the command with the path to a temporary file as $1,
+// then reads the file written by the command.
+func (c *YamlCheck) readCommandOutput() {
+	f, err := os.CreateTemp("", "shipshape-command-*")
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error creating output file",
+			Value:      err.Error()})
+		return
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	if _, err := command.ShellCommander("sh", "-c", c.Command, "shipshape", f.Name()).Output(); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error running command",
+			Value:      command.GetMsgFromCommandError(err)})
+		return
+	}
+
+	format := c.CommandFormat
+	if format == "" {
+		format = "json"
+	}
+	data, err := os.ReadFile(f.Name())
+	if err == nil {
+		c.DataMap["command"], err = convertData(format, data)
+	}
+	if err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "error reading command output",
+			Value:      err.Error()})
+	}
+}
+
 // FetchData populates the DataMap for a File-based Yaml check.
 // The check can be run either against a single File, or based on a
-// regex Pattern.
+// regex Pattern, against data published by another check, against the
+// data piped to stdin or against the file written by a command.
 func (c *YamlCheck) FetchData() {
 	c.DataMap = map[string][]byte{}
-	if c.File != "" {
+	if c.Stdin != "" {
+		c.readStdin()
+	} else if c.Command != "" {
+		c.readCommandOutput()
+	} else if c.From != "" {
+		data, ok := config.GetPublishedData(c.From)
+		if !ok {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "no published data",
+				Value:      c.From})
+			return
+		}
+		c.DataMap[c.From] = data
+	} else if c.File != "" {
 		c.readFile(filepath.Join(c.Path, c.File), filepath.Join(config.ProjectDir, c.Path, c.File))
 	} else if len(c.Files) > 0 {
-		for _, f := range c.Files {
+		if !c.validSample() {
+			return
+		}
+		for _, f := range c.sampleFiles(c.Files) {
 			c.readFile(filepath.Join(c.Path, f), filepath.Join(config.ProjectDir, c.Path, f))
 		}
 	} else if c.Pattern != "" {
@@ -83,8 +194,11 @@ func (c *YamlCheck) FetchData() {
 			return
 		}
 
+		if !c.validSample() {
+			return
+		}
 		c.DataMap = map[string][]byte{}
-		for _, fname := range files {
+		for _, fname := range c.sampleFiles(files) {
 			c.readFile(fname, fname)
 		}
 	} else {
@@ -93,3 +207,27 @@ func (c *YamlCheck) FetchData() {
 			Value:      "no file provided"})
 	}
 }
+
+// validSample ensures the sample, if any, is valid.
+func (c *YamlCheck) validSample() bool {
+	if c.Sample == nil {
+		return true
+	}
+	if err := c.Sample.Validate(); err != nil {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "invalid sample",
+			Value:      err.Error()})
+		return false
+	}
+	return true
+}
+
+// sampleFiles selects the files to check based on the sample, and notes
+// when only some of the files are checked.
+func (c *YamlCheck) sampleFiles(files []string) []string {
+	selected := c.Sample.Select(files)
+	if len(selected) < len(files) {
+		c.AddWarning(fmt.Sprintf("checked a sample of %d of %d files", len(selected), len(files)))
+	}
+	return selected
+}
@@ -1,9 +1,12 @@
 package yaml_test
 
 import (
+	"os/exec"
+	"strings"
 	"testing"
 
 	. "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/internal"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
@@ -238,3 +241,203 @@ notification:
 		})
 	}
 }
+
+func TestYamlCheckFetchDataSample(t *testing.T) {
+	assert := assert.New(t)
+
+	config.ProjectDir = "testdata"
+	c := YamlCheck{Pattern: ".*.bar.yml", Sample: &config.Sample{MaxFiles: 2}}
+	c.Init(Yaml)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Len(c.DataMap, 2)
+	assert.Equal([]string{"checked a sample of 2 of 6 files"}, c.Result.Warnings)
+
+	c = YamlCheck{Files: []string{"foo.bar.yml", "zoom.bar.yml"}, Sample: &config.Sample{Percentage: 100}}
+	c.Init(Yaml)
+	c.FetchData()
+	assert.Empty(c.Result.Breaches)
+	assert.Len(c.DataMap, 2)
+	assert.Empty(c.Result.Warnings)
+
+	c = YamlCheck{Pattern: ".*.bar.yml", Sample: &config.Sample{}}
+	c.Init(Yaml)
+	c.FetchData()
+	assert.EqualValues([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "yaml",
+		Severity:   "normal",
+		ValueLabel: "invalid sample",
+		Value:      "percentage or max-files must be provided",
+	}}, c.Result.Breaches)
+}
+
+func TestYamlCheckFetchDataStdin(t *testing.T) {
+	curStdin := config.Stdin
+	defer func() {
+		config.Stdin = curStdin
+		config.ResetStdin()
+	}()
+
+	tt := []struct {
+		internal.FetchDataTest
+		stdin string
+	}{
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:          "json",
+				Check:         &YamlCheck{Stdin: "json"},
+				ExpectDataMap: map[string][]byte{"stdin": []byte(`{"items": []}`)},
+			},
+			stdin: `{"items": []}`,
+		},
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:          "yaml",
+				Check:         &YamlCheck{Stdin: "yaml"},
+				ExpectDataMap: map[string][]byte{"stdin": []byte(`{"items":["foo"]}`)},
+			},
+			stdin: "items:\n  - foo\n",
+		},
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:          "raw",
+				Check:         &YamlCheck{Stdin: "raw"},
+				ExpectDataMap: map[string][]byte{"stdin": []byte(`"line 1\nline 2\n"`)},
+			},
+			stdin: "line 1\nline 2\n",
+		},
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:  "invalidYaml",
+				Check: &YamlCheck{Stdin: "yaml"},
+				ExpectBreaches: []result.Breach{
+					&result.ValueBreach{
+						BreachType: "value",
+						CheckType:  "yaml",
+						Severity:   "normal",
+						ValueLabel: "error reading stdin",
+						Value:      "yaml: line 1: did not find expected node content",
+					},
+				},
+			},
+			stdin: "[",
+		},
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:  "unsupportedFormat",
+				Check: &YamlCheck{Stdin: "xml"},
+				ExpectBreaches: []result.Breach{
+					&result.ValueBreach{
+						BreachType: "value",
+						CheckType:  "yaml",
+						Severity:   "normal",
+						ValueLabel: "error reading stdin",
+						Value:      "unsupported format 'xml'",
+					},
+				},
+			},
+			stdin: "<foo/>",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(innerT *testing.T) {
+			config.Stdin = strings.NewReader(tc.stdin)
+			config.ResetStdin()
+			tc.Check.Init(Yaml)
+			internal.TestFetchData(innerT, tc.FetchDataTest)
+		})
+	}
+}
+
+func TestYamlCheckStdinRaw(t *testing.T) {
+	assert := assert.New(t)
+
+	curStdin := config.Stdin
+	defer func() {
+		config.Stdin = curStdin
+		config.ResetStdin()
+	}()
+	config.Stdin = strings.NewReader("deployment complete\n")
+	config.ResetStdin()
+
+	c := YamlCheck{
+		YamlBase: YamlBase{Values: []KeyValue{{Key: "$", Pattern: "complete"}}},
+		Stdin:    "raw",
+	}
+	c.Init(Yaml)
+	c.FetchData()
+	c.UnmarshalDataMap()
+	c.RunCheck()
+	assert.Equal(result.Pass, c.Result.Status)
+	assert.Equal([]string{"[stdin] '$' matches 'complete'"}, c.Result.Passes)
+}
+
+func TestYamlCheckFetchDataCommand(t *testing.T) {
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	tt := []struct {
+		internal.FetchDataTest
+		commander func(name string, arg ...string) command.IShellCommand
+	}{
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:          "json",
+				Check:         &YamlCheck{Command: `echo '{"issues": []}' > "$1"`},
+				ExpectDataMap: map[string][]byte{"command": []byte("{\"issues\": []}\n")},
+			},
+			commander: curShellCommander,
+		},
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:          "yaml",
+				Check:         &YamlCheck{Command: `printf 'issues:\n  - foo\n' > "$1"`, CommandFormat: "yaml"},
+				ExpectDataMap: map[string][]byte{"command": []byte(`{"issues":["foo"]}`)},
+			},
+			commander: curShellCommander,
+		},
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:  "commandError",
+				Check: &YamlCheck{Command: "tool --output $1"},
+				ExpectBreaches: []result.Breach{
+					&result.ValueBreach{
+						BreachType: "value",
+						CheckType:  "yaml",
+						Severity:   "normal",
+						ValueLabel: "error running command",
+						Value:      "tool: not found",
+					},
+				},
+			},
+			commander: internal.ShellCommanderMaker(
+				nil, &exec.ExitError{Stderr: []byte("tool: not found")}, nil),
+		},
+		{
+			FetchDataTest: internal.FetchDataTest{
+				Name:  "invalidFormat",
+				Check: &YamlCheck{Command: "tool --output $1", CommandFormat: "xml"},
+				ExpectBreaches: []result.Breach{
+					&result.ValueBreach{
+						BreachType: "value",
+						CheckType:  "yaml",
+						Severity:   "normal",
+						ValueLabel: "error reading command output",
+						Value:      "unsupported format 'xml'",
+					},
+				},
+			},
+			commander: internal.ShellCommanderMaker(nil, nil, nil),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(innerT *testing.T) {
+			command.ShellCommander = tc.commander
+			tc.Check.Init(Yaml)
+			internal.TestFetchData(innerT, tc.FetchDataTest)
+		})
+	}
+}
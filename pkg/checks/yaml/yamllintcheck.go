@@ -6,6 +6,7 @@ import (
 
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
 	"gopkg.in/yaml.v3"
 )
 
@@ -21,7 +22,8 @@ func (c *YamlLintCheck) Merge(mergeCheck config.Check) error {
 // UnmarshalDataMap tries to parse the yaml file into a generic structure and
 // returns any errors as failures.
 func (c *YamlLintCheck) UnmarshalDataMap() {
-	for f, data := range c.DataMap {
+	for _, f := range utils.SortedKeys(c.DataMap) {
+		data := c.DataMap[f]
 		var ifc interface{}
 		err := yaml.Unmarshal([]byte(data), &ifc)
 		if err != nil {
@@ -39,6 +39,19 @@ func (c *ExecShellCommand) Output() ([]byte, error) {
 // testing and mocking.
 var ShellCommander = NewExecShellCommander
 
+// PrefixShellCommander returns a commander which runs commands through the
+// given prefix using the provided commander, e.g, to run them in a container
+// with `docker exec -i <container>` or on a remote host with `ssh <host>`.
+func PrefixShellCommander(commander func(name string, arg ...string) IShellCommand, prefix ...string) func(name string, arg ...string) IShellCommand {
+	return func(name string, arg ...string) IShellCommand {
+		if len(prefix) == 0 {
+			return commander(name, arg...)
+		}
+		args := append(append(append([]string{}, prefix[1:]...), name), arg...)
+		return commander(prefix[0], args...)
+	}
+}
+
 // GetMsgFromCommandError attempts to extract the error message from a command
 // run's stderr.
 func GetMsgFromCommandError(err error) string {
@@ -92,3 +92,16 @@ func TestGetMsgFromCommandError(t *testing.T) {
 		assert.Equal("basic error", msg)
 	})
 }
+
+func TestPrefixShellCommander(t *testing.T) {
+	assert := assert.New(t)
+
+	var generatedCommand string
+	commander := internal.ShellCommanderMaker(nil, nil, &generatedCommand)
+
+	command.PrefixShellCommander(commander)("drush", "status")
+	assert.Equal("drush status", generatedCommand)
+
+	command.PrefixShellCommander(commander, "docker", "exec", "-i", "web")("drush", "status")
+	assert.Equal("docker exec -i web drush status", generatedCommand)
+}
@@ -13,7 +13,8 @@ func (c *CheckBase) Init(ct CheckType) {
 		c.Severity = NormalSeverity
 	}
 	if c.Result.CheckType == "" {
-		c.Result = result.Result{Name: c.Name, CheckType: string(ct)}
+		c.Result = result.Result{Name: c.Name, CheckType: string(ct), Target: c.Target, Controls: c.Controls,
+			Tags: c.Tags, Description: c.Description, Rationale: c.Rationale}
 	}
 	if c.Result.Severity == "" {
 		c.Result.Severity = string(c.Severity)
@@ -33,6 +34,48 @@ func (c *CheckBase) GetType() CheckType { return c.cType }
 // GetSeverity returns the severity of a check.
 func (c *CheckBase) GetSeverity() Severity { return c.Severity }
 
+// GetTarget returns the name of the target the check runs against.
+func (c *CheckBase) GetTarget() string { return c.Target }
+
+// GetWhen returns the condition for running the check.
+func (c *CheckBase) GetWhen() string { return c.When }
+
+// GetControls returns the compliance framework controls the check maps to.
+func (c *CheckBase) GetControls() []string { return c.Controls }
+
+// GetTags returns the labels of the check.
+func (c *CheckBase) GetTags() []string { return c.Tags }
+
+// GetOwner returns the person or team responsible for the check.
+func (c *CheckBase) GetOwner() string { return c.Owner }
+
+// GetDescription returns what the check verifies.
+func (c *CheckBase) GetDescription() string { return c.Description }
+
+// GetRationale returns why the check's policy exists.
+func (c *CheckBase) GetRationale() string { return c.Rationale }
+
+// GetDocUrl returns the link to the runbook explaining the check's policy.
+func (c *CheckBase) GetDocUrl() string { return c.DocUrl }
+
+// GetRemediationHint returns how to fix the check's breaches.
+func (c *CheckBase) GetRemediationHint() string { return c.RemediationHint }
+
+// GetDependsOn returns the names of the checks this check depends on.
+func (c *CheckBase) GetDependsOn() []string { return c.DependsOn }
+
+// IsDisabled returns whether the check is disabled in the config.
+func (c *CheckBase) IsDisabled() bool { return c.Disabled }
+
+// SetName renames the check.
+func (c *CheckBase) SetName(name string) { c.Name = name }
+
+// SetDependsOn replaces the names of the checks this check depends on.
+func (c *CheckBase) SetDependsOn(names []string) { c.DependsOn = names }
+
+// IsSensitive returns whether the check's breach values are masked.
+func (c *CheckBase) IsSensitive() bool { return c.Sensitive }
+
 // Merge merges values from another check into this one.
 func (c *CheckBase) Merge(mergeCheck Check) error {
 	// Empty name means the merge will be done for all checks of the same type.
@@ -42,6 +85,42 @@ func (c *CheckBase) Merge(mergeCheck Check) error {
 	if mergeCheck.GetSeverity() != "" {
 		c.Severity = mergeCheck.GetSeverity()
 	}
+	if mergeCheck.GetTarget() != "" {
+		c.Target = mergeCheck.GetTarget()
+	}
+	if mergeCheck.GetWhen() != "" {
+		c.When = mergeCheck.GetWhen()
+	}
+	if len(mergeCheck.GetControls()) > 0 {
+		c.Controls = mergeCheck.GetControls()
+	}
+	if len(mergeCheck.GetTags()) > 0 {
+		c.Tags = mergeCheck.GetTags()
+	}
+	if mergeCheck.GetOwner() != "" {
+		c.Owner = mergeCheck.GetOwner()
+	}
+	if mergeCheck.GetDescription() != "" {
+		c.Description = mergeCheck.GetDescription()
+	}
+	if mergeCheck.GetRationale() != "" {
+		c.Rationale = mergeCheck.GetRationale()
+	}
+	if mergeCheck.GetDocUrl() != "" {
+		c.DocUrl = mergeCheck.GetDocUrl()
+	}
+	if mergeCheck.GetRemediationHint() != "" {
+		c.RemediationHint = mergeCheck.GetRemediationHint()
+	}
+	if len(mergeCheck.GetDependsOn()) > 0 {
+		c.DependsOn = mergeCheck.GetDependsOn()
+	}
+	if mergeCheck.IsSensitive() {
+		c.Sensitive = true
+	}
+	if mergeCheck.IsDisabled() {
+		c.Disabled = true
+	}
 	return nil
 }
 
@@ -70,6 +149,9 @@ func (c *CheckBase) HasData(failCheck bool) bool {
 	return true
 }
 
+// GetDataMap returns the data fetched by the check.
+func (c *CheckBase) GetDataMap() map[string][]byte { return c.DataMap }
+
 // UnmarshalDataMap attempts to parse the DataMap into a structure that
 // can be used to execute the check. Any failure here should fail the check.
 func (c *CheckBase) UnmarshalDataMap() {}
@@ -77,6 +159,7 @@ func (c *CheckBase) UnmarshalDataMap() {}
 // AddBreach appends a Breach to the Result and sets the Check as Fail.
 func (c *CheckBase) AddBreach(b result.Breach) {
 	b.SetCommonValues(string(c.cType), c.Name, string(c.Severity))
+	b.SetDocs(c.DocUrl, c.RemediationHint)
 	c.Result.Breaches = append(
 		c.Result.Breaches,
 		b,
@@ -25,6 +25,21 @@ func TestCheckBaseInit(t *testing.T) {
 	assert.Equal("foo", c.Result.Name)
 	assert.Equal(string(NormalSeverity), c.Result.Severity)
 	assert.Equal(testCheckForCheckBaseInitType, c.GetType())
+
+	c = CheckBase{Name: "foo", Target: "web"}
+	c.Init(testCheckForCheckBaseInitType)
+	assert.Equal("web", c.GetTarget())
+	assert.Equal("web", c.Result.Target)
+
+	c = CheckBase{Name: "foo", Controls: []string{"iso27001:A.12.6.1"}}
+	c.Init(testCheckForCheckBaseInitType)
+	assert.Equal([]string{"iso27001:A.12.6.1"}, c.GetControls())
+	assert.Equal([]string{"iso27001:A.12.6.1"}, c.Result.Controls)
+
+	c = CheckBase{Name: "foo", Description: "Modules are up to date", Rationale: "Outdated modules are a risk"}
+	c.Init(testCheckForCheckBaseInitType)
+	assert.Equal("Modules are up to date", c.Result.Description)
+	assert.Equal("Outdated modules are a risk", c.Result.Rationale)
 }
 
 func TestCheckBaseMerge(t *testing.T) {
@@ -41,6 +56,66 @@ func TestCheckBaseMerge(t *testing.T) {
 	c = CheckBase{Severity: LowSeverity}
 	c.Merge(&CheckBase{Name: "foo"})
 	assert.Equal(LowSeverity, c.Severity)
+
+	c = CheckBase{Name: "foo", Target: "web"}
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.Equal("web", c.Target)
+	c.Merge(&CheckBase{Name: "foo", Target: "cli"})
+	assert.Equal("cli", c.Target)
+
+	c = CheckBase{Name: "foo", When: "stack==node"}
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.Equal("stack==node", c.GetWhen())
+	c.Merge(&CheckBase{Name: "foo", When: "platform==drupal"})
+	assert.Equal("platform==drupal", c.GetWhen())
+
+	c = CheckBase{Name: "foo", Controls: []string{"iso27001:A.12.6.1"}}
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.Equal([]string{"iso27001:A.12.6.1"}, c.Controls)
+	c.Merge(&CheckBase{Name: "foo", Controls: []string{"soc2:CC7.1"}})
+	assert.Equal([]string{"soc2:CC7.1"}, c.Controls)
+
+	c = CheckBase{Name: "foo", Tags: []string{"security"}, Owner: "platform-team"}
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.Equal([]string{"security"}, c.GetTags())
+	assert.Equal("platform-team", c.GetOwner())
+	c.Merge(&CheckBase{Name: "foo", Tags: []string{"pci"}, Owner: "security-team"})
+	assert.Equal([]string{"pci"}, c.GetTags())
+	assert.Equal("security-team", c.GetOwner())
+
+	c = CheckBase{Name: "foo", Description: "Modules are up to date", Rationale: "Outdated modules are a risk"}
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.Equal("Modules are up to date", c.GetDescription())
+	assert.Equal("Outdated modules are a risk", c.GetRationale())
+	c.Merge(&CheckBase{Name: "foo", Description: "No insecure module", Rationale: "Known vulnerabilities"})
+	assert.Equal("No insecure module", c.GetDescription())
+	assert.Equal("Known vulnerabilities", c.GetRationale())
+
+	c = CheckBase{Name: "foo", DocUrl: "https://wiki.example.com/modules", RemediationHint: "Run composer update"}
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.Equal("https://wiki.example.com/modules", c.GetDocUrl())
+	assert.Equal("Run composer update", c.GetRemediationHint())
+	c.Merge(&CheckBase{Name: "foo", DocUrl: "https://wiki.example.com/security", RemediationHint: "Apply the patch"})
+	assert.Equal("https://wiki.example.com/security", c.GetDocUrl())
+	assert.Equal("Apply the patch", c.GetRemediationHint())
+
+	c = CheckBase{Name: "foo", DependsOn: []string{"bar"}}
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.Equal([]string{"bar"}, c.GetDependsOn())
+	c.Merge(&CheckBase{Name: "foo", DependsOn: []string{"baz"}})
+	assert.Equal([]string{"baz"}, c.GetDependsOn())
+
+	c = CheckBase{Name: "foo"}
+	c.Merge(&CheckBase{Name: "foo", Sensitive: true})
+	assert.True(c.IsSensitive())
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.True(c.IsSensitive())
+
+	c = CheckBase{Name: "foo"}
+	c.Merge(&CheckBase{Name: "foo", Disabled: true})
+	assert.True(c.IsDisabled())
+	c.Merge(&CheckBase{Name: "foo"})
+	assert.True(c.IsDisabled())
 }
 
 func TestRequiresData(t *testing.T) {
@@ -121,12 +196,15 @@ func TestAddBreach(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			c := CheckBase{Name: test.checkName, Severity: test.severity}
+			c := CheckBase{Name: test.checkName, Severity: test.severity,
+				DocUrl: "https://wiki.example.com/" + test.checkName, RemediationHint: "Fix it"}
 			c.Init(test.checkType)
 			c.AddBreach(test.breach)
 			assert.Equal(string(test.checkType), c.Result.Breaches[0].GetCheckType())
 			assert.Equal(test.checkName, c.Result.Breaches[0].GetCheckName())
 			assert.Equal(string(test.severity), c.Result.Breaches[0].GetSeverity())
+			assert.Equal("https://wiki.example.com/"+test.checkName, c.Result.Breaches[0].GetDocUrl())
+			assert.Equal("Fix it", c.Result.Breaches[0].GetRemediationHint())
 		})
 	}
 }
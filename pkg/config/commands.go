@@ -0,0 +1,9 @@
+package config
+
+// CommandReporter is implemented by checks running external commands, to
+// report the commands they would run without running them.
+type CommandReporter interface {
+	// Commands returns the commands as lists of arguments; values only known
+	// at runtime are represented by placeholders, e.g, <image>.
+	Commands() [][]string
+}
@@ -29,6 +29,7 @@ func (cm *CheckMap) UnmarshalYAML(value *yaml.Node) error {
 		}
 
 		for _, cv := range check_values[0].Content {
+			migrateDeprecatedKeys(ct, cv)
 			c := cFunc()
 			err := cv.Decode(c)
 			if err != nil {
@@ -49,6 +50,26 @@ func (cfg *Config) Merge(mrgCfg Config) error {
 	if mrgCfg.FailSeverity != "" {
 		cfg.FailSeverity = mrgCfg.FailSeverity
 	}
+	if len(mrgCfg.Escalation) > 0 {
+		cfg.Escalation = mrgCfg.Escalation
+	}
+	if mrgCfg.Remediation.Gate != nil {
+		cfg.Remediation.Gate = mrgCfg.Remediation.Gate
+	}
+	cfg.Naming.Merge(mrgCfg.Naming)
+	cfg.Credentials.Merge(mrgCfg.Credentials)
+	for name, v := range mrgCfg.Vars {
+		if cfg.Vars == nil {
+			cfg.Vars = map[string]string{}
+		}
+		cfg.Vars[name] = v
+	}
+	for name, t := range mrgCfg.Targets {
+		if cfg.Targets == nil {
+			cfg.Targets = map[string]Target{}
+		}
+		cfg.Targets[name] = t
+	}
 
 	if mrgCfg.Checks == nil {
 		return nil
@@ -94,6 +115,18 @@ func (cfg *Config) Merge(mrgCfg Config) error {
 	return nil
 }
 
+// Merge replaces the credentials with those of another config which are set.
+func (c *CredentialsConfig) Merge(mrg CredentialsConfig) {
+	utils.MergeString(&c.LagoonApiToken, mrg.LagoonApiToken)
+	utils.MergeString(&c.NotifyWebhook, mrg.NotifyWebhook)
+	utils.MergeString(&c.S3AccessKeyId, mrg.S3AccessKeyId)
+	utils.MergeString(&c.S3SecretAccessKey, mrg.S3SecretAccessKey)
+	utils.MergeString(&c.S3SessionToken, mrg.S3SessionToken)
+	utils.MergeString(&c.ElasticsearchApiKey, mrg.ElasticsearchApiKey)
+	utils.MergeString(&c.ElasticsearchUsername, mrg.ElasticsearchUsername)
+	utils.MergeString(&c.ElasticsearchPassword, mrg.ElasticsearchPassword)
+}
+
 // FilterChecksToRun iterates over all the checks and filters them based on
 // a provided list of check types to run or whether to exclude database checks.
 func (cfg *Config) FilterChecksToRun(checkTypesToRun []string, excludeDb bool) {
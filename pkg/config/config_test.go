@@ -101,6 +101,42 @@ func TestMerge(t *testing.T) {
 	assert.Equal("bar", cfg.ProjectDir)
 	assert.Equal(HighSeverity, cfg.FailSeverity)
 
+	// Ensure targets are merged by name.
+	err = cfg.Merge(Config{Targets: map[string]Target{
+		"web": {Exec: []string{"docker", "exec", "web"}},
+		"cli": {Exec: []string{"docker", "exec", "cli"}},
+	}})
+	assert.NoError(err)
+	err = cfg.Merge(Config{Targets: map[string]Target{
+		"web": {Exec: []string{"ssh", "web.example.com"}},
+	}})
+	assert.NoError(err)
+	assert.Equal(map[string]Target{
+		"web": {Exec: []string{"ssh", "web.example.com"}},
+		"cli": {Exec: []string{"docker", "exec", "cli"}},
+	}, cfg.Targets)
+
+	// Ensure variables are merged by name.
+	err = cfg.Merge(Config{Vars: map[string]string{"domain": "example.com", "docroot": "web"}})
+	assert.NoError(err)
+	err = cfg.Merge(Config{Vars: map[string]string{"domain": "example.org"}})
+	assert.NoError(err)
+	assert.Equal(map[string]string{"domain": "example.org", "docroot": "web"}, cfg.Vars)
+
+	// Ensure escalation rules are replaced.
+	err = cfg.Merge(Config{Escalation: []EscalationRule{{After: "30d", Severity: HighSeverity}}})
+	assert.NoError(err)
+	err = cfg.Merge(Config{})
+	assert.NoError(err)
+	assert.Equal([]EscalationRule{{After: "30d", Severity: HighSeverity}}, cfg.Escalation)
+
+	// Ensure the remediation gate is replaced.
+	err = cfg.Merge(Config{Remediation: RemediationConfig{Gate: &RemediationGate{File: "/tmp/approved"}}})
+	assert.NoError(err)
+	err = cfg.Merge(Config{})
+	assert.NoError(err)
+	assert.Equal(&RemediationGate{File: "/tmp/approved"}, cfg.Remediation.Gate)
+
 	// Ensure checks are merged properly.
 	err = cfg.Merge(Config{
 		Checks: CheckMap{
@@ -242,6 +278,18 @@ func TestMerge(t *testing.T) {
 	)
 }
 
+func TestCredentialsConfigMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	c := CredentialsConfig{NotifyWebhook: "https://hooks.example.com/a", S3AccessKeyId: "AKID"}
+	c.Merge(CredentialsConfig{NotifyWebhook: "https://hooks.example.com/b", S3SecretAccessKey: "SECRET"})
+	assert.Equal(CredentialsConfig{
+		NotifyWebhook:     "https://hooks.example.com/b",
+		S3AccessKeyId:     "AKID",
+		S3SecretAccessKey: "SECRET",
+	}, c)
+}
+
 func TestFilterChecksToRun(t *testing.T) {
 	assert := assert.New(t)
 
@@ -307,3 +355,13 @@ func TestFilterChecksToRun(t *testing.T) {
 		}, cfg)
 	})
 }
+
+func TestSeverityCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(HighSeverity.IsValid())
+	assert.False(Severity("urgent").IsValid())
+	assert.Equal(-1, LowSeverity.Compare(NormalSeverity))
+	assert.Equal(0, HighSeverity.Compare(HighSeverity))
+	assert.Equal(1, CriticalSeverity.Compare(HighSeverity))
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EncryptedPrefix identifies the encrypted values in the config; the rest of
+// the value is the base64-encoded nonce & ciphertext, using AES-256-GCM.
+const EncryptedPrefix = "enc:v1:"
+
+// KeyEnvVar is the environment variable holding the base64-encoded key used
+// to decrypt the config values.
+const KeyEnvVar = "SHIPSHAPE_CONFIG_KEY"
+
+// Redacted replaces the decrypted values in the config's dump & logs.
+const Redacted = "********"
+
+// KeyFile is the file holding the base64-encoded key, used when KeyEnvVar
+// is not set.
+var KeyFile string
+
+// decryptedValues are the values decrypted when loading the config, to be
+// redacted from its output.
+var decryptedValues []string
+
+// EncryptionKey reads the key used to encrypt & decrypt the config values.
+func EncryptionKey() ([]byte, error) {
+	encoded := os.Getenv(KeyEnvVar)
+	if encoded == "" && KeyFile != "" {
+		data, err := os.ReadFile(KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read config key file: %w", err)
+		}
+		encoded = string(data)
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("no config key provided; set %s or --config-key-file", KeyEnvVar)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("invalid config key; expected 32 base64-encoded bytes")
+	}
+	return key, nil
+}
+
+// EncryptValue encrypts the value for use in the config.
+func EncryptValue(value string, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue decrypts a value encrypted using EncryptValue.
+func DecryptValue(value string, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted value")
+	}
+	nonceSize := gcm.NonceSize()
+	plain, err := gcm.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", errors.New("unable to decrypt value; the key may be wrong")
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// DecryptValues decrypts the encrypted scalar values of the config in place;
+// the key is only read if any is found, so that configs without encrypted
+// values do not require one.
+func DecryptValues(n *yaml.Node) error {
+	var key []byte
+	var decrypt func(n *yaml.Node) error
+	decrypt = func(n *yaml.Node) error {
+		if n.Kind == yaml.ScalarNode {
+			if !strings.HasPrefix(n.Value, EncryptedPrefix) {
+				return nil
+			}
+			if key == nil {
+				var err error
+				if key, err = EncryptionKey(); err != nil {
+					return err
+				}
+			}
+			v, err := DecryptValue(n.Value, key)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", n.Line, err)
+			}
+			n.Value = v
+			if v != "" {
+				decryptedValues = append(decryptedValues, v)
+			}
+			// Decrypted values are always strings, e.g, a token made of digits.
+			n.Tag = "!!str"
+			return nil
+		}
+		for _, c := range n.Content {
+			if err := decrypt(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return decrypt(n)
+}
+
+// RedactString replaces the decrypted values found in the string, including
+// their json-escaped form.
+func RedactString(s string) string {
+	for _, v := range decryptedValues {
+		s = strings.ReplaceAll(s, v, Redacted)
+		escaped, _ := json.Marshal(v)
+		s = strings.ReplaceAll(s, string(escaped[1:len(escaped)-1]), Redacted)
+	}
+	return s
+}
+
+// RedactNode replaces the decrypted values in the scalar values of the node,
+// e.g, before dumping the config.
+func RedactNode(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode {
+		n.Value = RedactString(n.Value)
+		return
+	}
+	for _, c := range n.Content {
+		RedactNode(c)
+	}
+}
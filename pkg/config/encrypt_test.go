@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+const testKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func TestEncryptionKey(t *testing.T) {
+	assert := assert.New(t)
+	defer func() { KeyFile = "" }()
+
+	t.Setenv(KeyEnvVar, "")
+	_, err := EncryptionKey()
+	assert.EqualError(err, "no config key provided; set SHIPSHAPE_CONFIG_KEY or --config-key-file")
+
+	KeyFile = filepath.Join(t.TempDir(), "key")
+	_, err = EncryptionKey()
+	assert.ErrorContains(err, "unable to read config key file")
+	os.WriteFile(KeyFile, []byte(testKey+"\n"), 0600)
+	key, err := EncryptionKey()
+	assert.NoError(err)
+	assert.Equal([]byte("0123456789abcdef0123456789abcdef"), key)
+
+	// The environment variable takes precedence over the file.
+	t.Setenv(KeyEnvVar, "c2hvcnQ=")
+	_, err = EncryptionKey()
+	assert.EqualError(err, "invalid config key; expected 32 base64-encoded bytes")
+}
+
+func TestEncryptValue(t *testing.T) {
+	assert := assert.New(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	encrypted, err := EncryptValue("xoxb-token", key)
+	assert.NoError(err)
+	assert.True(strings.HasPrefix(encrypted, EncryptedPrefix))
+	other, _ := EncryptValue("xoxb-token", key)
+	assert.NotEqual(encrypted, other)
+
+	v, err := DecryptValue(encrypted, key)
+	assert.NoError(err)
+	assert.Equal("xoxb-token", v)
+
+	_, err = DecryptValue(encrypted, []byte("fedcba9876543210fedcba9876543210"))
+	assert.EqualError(err, "unable to decrypt value; the key may be wrong")
+	_, err = DecryptValue(EncryptedPrefix+"not-base64!", key)
+	assert.EqualError(err, "invalid encrypted value")
+}
+
+func TestDecryptValues(t *testing.T) {
+	assert := assert.New(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+	encrypted, _ := EncryptValue("12345", key)
+
+	n := yaml.Node{}
+	yaml.Unmarshal([]byte(`
+vars:
+  token: `+encrypted+`
+  plain: value
+`), &n)
+
+	// No key is required if there are no encrypted values.
+	t.Setenv(KeyEnvVar, "")
+	plain := yaml.Node{}
+	yaml.Unmarshal([]byte("vars:\n  plain: value\n"), &plain)
+	assert.NoError(DecryptValues(&plain))
+	assert.EqualError(DecryptValues(&n),
+		"no config key provided; set SHIPSHAPE_CONFIG_KEY or --config-key-file")
+
+	t.Setenv(KeyEnvVar, testKey)
+	assert.NoError(DecryptValues(&n))
+	vars := struct {
+		Vars map[string]string `yaml:"vars"`
+	}{}
+	assert.NoError(n.Decode(&vars))
+	assert.Equal(map[string]string{"token": "12345", "plain": "value"}, vars.Vars)
+
+	t.Setenv(KeyEnvVar, "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=")
+	yaml.Unmarshal([]byte("vars:\n  token: "+encrypted+"\n"), &n)
+	assert.EqualError(DecryptValues(&n), "line 2: unable to decrypt value; the key may be wrong")
+}
+
+func TestRedact(t *testing.T) {
+	assert := assert.New(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+	encrypted, _ := EncryptValue(`xoxb-"secret"`, key)
+
+	t.Setenv(KeyEnvVar, testKey)
+	n := yaml.Node{}
+	yaml.Unmarshal([]byte("vars:\n  token: "+encrypted+"\n"), &n)
+	assert.NoError(DecryptValues(&n))
+
+	assert.Equal("https://hooks.example.com/********", RedactString(`https://hooks.example.com/xoxb-"secret"`))
+	assert.Equal(`{"token":"********"}`, RedactString(`{"token":"xoxb-\"secret\""}`))
+	assert.Equal("plain", RedactString("plain"))
+
+	RedactNode(&n)
+	out, _ := yaml.Marshal(&n)
+	assert.Equal("vars:\n    token: '********'\n", string(out))
+}
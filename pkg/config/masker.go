@@ -0,0 +1,7 @@
+package config
+
+// DataMasker is implemented by checks whose fetched data may contain secrets,
+// to provide a copy of the data with the secrets masked, e.g, for the facts.
+type DataMasker interface {
+	MaskedDataMap() map[string][]byte
+}
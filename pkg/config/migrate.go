@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// KeyRename is a deprecated check key along with the key replacing it.
+type KeyRename struct {
+	Old string
+	New string
+}
+
+// DeprecatedKeys lists the renamed keys for each check type; the old keys
+// are still read, but should be migrated using `shipshape config migrate`.
+var DeprecatedKeys = map[CheckType][]KeyRename{}
+
+// DeprecateKey registers a renamed key for the check type.
+func DeprecateKey(ct CheckType, old string, new string) {
+	DeprecatedKeys[ct] = append(DeprecatedKeys[ct], KeyRename{Old: old, New: new})
+}
+
+// MigrateCheckNode renames the deprecated keys of a check's yaml mapping,
+// noting the change in a comment, and returns the changes made. If the new
+// key is also present, the deprecated one is removed.
+func MigrateCheckNode(ct CheckType, n *yaml.Node) []string {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	changes := []string{}
+	for _, rename := range DeprecatedKeys[ct] {
+		oldIdx, newIdx := -1, -1
+		for i := 0; i < len(n.Content)-1; i += 2 {
+			switch n.Content[i].Value {
+			case rename.Old:
+				oldIdx = i
+			case rename.New:
+				newIdx = i
+			}
+		}
+		if oldIdx < 0 {
+			continue
+		}
+		if newIdx >= 0 {
+			n.Content = append(n.Content[:oldIdx], n.Content[oldIdx+2:]...)
+			changes = append(changes, fmt.Sprintf(
+				"removed '%s', superseded by '%s'", rename.Old, rename.New))
+			continue
+		}
+		key := n.Content[oldIdx]
+		key.Value = rename.New
+		comment := fmt.Sprintf("# Renamed from %s.", rename.Old)
+		if key.HeadComment != "" {
+			comment = key.HeadComment + "\n" + comment
+		}
+		key.HeadComment = comment
+		changes = append(changes, fmt.Sprintf(
+			"renamed '%s' to '%s'", rename.Old, rename.New))
+	}
+	return changes
+}
+
+// MigrateConfig rewrites the deprecated keys of a config file to their
+// current name, returning the migrated config along with the changes made
+// for each check; the config is returned as-is if there is no change.
+func MigrateConfig(data []byte) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil, nil
+	}
+
+	changes := []string{}
+	root := doc.Content[0]
+	for i := 0; i < len(root.Content)-1; i += 2 {
+		if root.Content[i].Value != "checks" || root.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+		checks := root.Content[i+1]
+		for j := 0; j < len(checks.Content)-1; j += 2 {
+			ct := CheckType(checks.Content[j].Value)
+			if checks.Content[j+1].Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, cn := range checks.Content[j+1].Content {
+				name := ""
+				for k := 0; k < len(cn.Content)-1; k += 2 {
+					if cn.Content[k].Value == "name" {
+						name = cn.Content[k+1].Value
+					}
+				}
+				for _, change := range MigrateCheckNode(ct, cn) {
+					changes = append(changes, fmt.Sprintf("%s '%s': %s", ct, name, change))
+				}
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return data, changes, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), changes, nil
+}
+
+// migrateDeprecatedKeys renames the deprecated keys of a check being parsed,
+// warning about them.
+func migrateDeprecatedKeys(ct CheckType, n *yaml.Node) {
+	for _, change := range MigrateCheckNode(ct, n) {
+		log.WithField("check-type", ct).
+			Warnf("deprecated config key: %s; run `shipshape config migrate` to update the config", change)
+	}
+}
@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/config/testdata/testchecks"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	DeprecateKey(testchecks.TestCheck1, "old_foo", "foo")
+	defer delete(DeprecatedKeys, testchecks.TestCheck1)
+
+	t.Run("noChange", func(t *testing.T) {
+		data := []byte("checks:\n    test-check-1:\n        - name: a\n          foo: bar\n")
+		migrated, changes, err := MigrateConfig(data)
+		assert.NoError(err)
+		assert.Empty(changes)
+		assert.Equal(data, migrated)
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		migrated, changes, err := MigrateConfig([]byte(`
+# Checks.
+checks:
+  test-check-1:
+    - name: a
+      old_foo: bar # Comment kept.
+    - name: b
+      old_foo: baz
+      foo: zoom
+`))
+		assert.NoError(err)
+		assert.Equal([]string{
+			"test-check-1 'a': renamed 'old_foo' to 'foo'",
+			"test-check-1 'b': removed 'old_foo', superseded by 'foo'",
+		}, changes)
+		assert.Equal(`# Checks.
+checks:
+  test-check-1:
+    - name: a
+      # Renamed from old_foo.
+      foo: bar # Comment kept.
+    - name: b
+      foo: zoom
+`, string(migrated))
+	})
+
+	t.Run("invalidYaml", func(t *testing.T) {
+		_, _, err := MigrateConfig([]byte("checks: ["))
+		assert.Error(err)
+	})
+}
+
+func TestCheckMapUnmarshalYamlDeprecatedKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	currRegistry := ChecksRegistry
+	defer func() { ChecksRegistry = currRegistry }()
+	ChecksRegistry = map[CheckType]func() Check{}
+	testchecks.RegisterChecks()
+	DeprecateKey(testchecks.TestCheck1, "old_foo", "foo")
+	defer delete(DeprecatedKeys, testchecks.TestCheck1)
+
+	var cm CheckMap
+	err := yaml.Unmarshal([]byte(`
+test-check-1:
+  - name: My test check 1
+    old_foo: baz
+`), &cm)
+	assert.NoError(err)
+	assert.Equal(CheckMap{
+		testchecks.TestCheck1: {
+			&testchecks.TestCheck1Check{
+				CheckBase: CheckBase{Name: "My test check 1"},
+				Foo:       "baz",
+			},
+		},
+	}, cm)
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Merge merges the naming conventions of another config into these; the
+// aliases are merged one by one.
+func (n *NamingConfig) Merge(mrg NamingConfig) {
+	if mrg.Pattern != "" {
+		n.Pattern = mrg.Pattern
+	}
+	for legacy, name := range mrg.Aliases {
+		if n.Aliases == nil {
+			n.Aliases = map[string]string{}
+		}
+		n.Aliases[legacy] = name
+	}
+}
+
+// RenameAliases renames the checks using legacy names, along with the
+// dependencies on them.
+func (cm CheckMap) RenameAliases(aliases map[string]string) {
+	if len(aliases) == 0 {
+		return
+	}
+	for ct, checks := range cm {
+		for _, c := range checks {
+			if name, ok := aliases[c.GetName()]; ok {
+				log.WithFields(log.Fields{
+					"check-type":  ct,
+					"legacy-name": c.GetName(),
+					"name":        name,
+				}).Info("renaming check using legacy name")
+				c.SetName(name)
+			}
+
+			deps := c.GetDependsOn()
+			if len(deps) == 0 {
+				continue
+			}
+			renamed := make([]string, len(deps))
+			for i, d := range deps {
+				renamed[i] = d
+				if name, ok := aliases[d]; ok {
+					renamed[i] = name
+				}
+			}
+			c.SetDependsOn(renamed)
+		}
+	}
+}
+
+// Validate verifies the pattern is valid and that the checks' names match
+// it.
+func (n NamingConfig) Validate(cm CheckMap) error {
+	if n.Pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(n.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid naming pattern: %w", err)
+	}
+
+	invalid := []string{}
+	for _, checks := range cm {
+		for _, c := range checks {
+			if !re.MatchString(c.GetName()) {
+				invalid = append(invalid, c.GetName())
+			}
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("check name '%s' does not match the naming pattern '%s'",
+		invalid[0], n.Pattern)
+}
@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"io"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/config/testdata/testchecks"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamingConfigMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	n := NamingConfig{}
+	n.Merge(NamingConfig{Pattern: "^[a-z-]+$", Aliases: map[string]string{"Old": "old"}})
+	assert.Equal(NamingConfig{Pattern: "^[a-z-]+$", Aliases: map[string]string{"Old": "old"}}, n)
+
+	n.Merge(NamingConfig{Aliases: map[string]string{"Legacy": "legacy", "Old": "older"}})
+	assert.Equal(NamingConfig{
+		Pattern: "^[a-z-]+$",
+		Aliases: map[string]string{"Old": "older", "Legacy": "legacy"},
+	}, n)
+}
+
+func TestCheckMapRenameAliases(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	cm := CheckMap{
+		testchecks.TestCheck1: {
+			&testchecks.TestCheck1Check{CheckBase: CheckBase{Name: "Legacy name"}},
+			&testchecks.TestCheck1Check{CheckBase: CheckBase{Name: "current-name"}},
+		},
+		testchecks.TestCheck2: {
+			&testchecks.TestCheck2Check{CheckBase: CheckBase{
+				Name:      "dependent",
+				DependsOn: []string{"Legacy name", "current-name"},
+			}},
+		},
+	}
+	cm.RenameAliases(map[string]string{"Legacy name": "legacy-name"})
+	assert.Equal("legacy-name", cm[testchecks.TestCheck1][0].GetName())
+	assert.Equal("current-name", cm[testchecks.TestCheck1][1].GetName())
+	assert.Equal("dependent", cm[testchecks.TestCheck2][0].GetName())
+	assert.Equal([]string{"legacy-name", "current-name"}, cm[testchecks.TestCheck2][0].GetDependsOn())
+}
+
+func TestNamingConfigValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	cm := CheckMap{
+		testchecks.TestCheck1: {
+			&testchecks.TestCheck1Check{CheckBase: CheckBase{Name: "valid-name"}},
+			&testchecks.TestCheck1Check{CheckBase: CheckBase{Name: "Invalid name"}},
+		},
+		testchecks.TestCheck2: {
+			&testchecks.TestCheck2Check{CheckBase: CheckBase{Name: "Another invalid"}},
+		},
+	}
+
+	assert.NoError(NamingConfig{}.Validate(cm))
+	assert.NoError(NamingConfig{Pattern: "^[A-Za-z -]+$"}.Validate(cm))
+
+	err := NamingConfig{Pattern: "^[a-z-]+$"}.Validate(cm)
+	assert.EqualError(err, "check name 'Another invalid' does not match the naming pattern '^[a-z-]+$'")
+
+	err = NamingConfig{Pattern: "^[a-z"}.Validate(cm)
+	assert.EqualError(err, "invalid naming pattern: error parsing regexp: missing closing ]: `[a-z`")
+}
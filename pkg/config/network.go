@@ -0,0 +1,7 @@
+package config
+
+// NetworkRequirer is implemented by checks requiring network access to run,
+// e.g, calling an external api; they are skipped in offline mode.
+type NetworkRequirer interface {
+	RequiresNetwork() bool
+}
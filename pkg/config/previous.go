@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+)
+
+// PreviousResult is the outcome of a check in a previous run, from the
+// history or a baseline report.
+type PreviousResult struct {
+	Status   result.Status
+	Passes   []string
+	Breaches []string
+	// When the check last ran & last passed; zero if unknown.
+	LastRun    time.Time
+	LastPassed time.Time
+}
+
+var previousResults = map[string]PreviousResult{}
+var previousResultsLock = sync.RWMutex{}
+
+// PreviousResultKey identifies a check across runs.
+func PreviousResultKey(checkType string, name string, target string) string {
+	if target != "" {
+		return fmt.Sprintf("%s:%s@%s", checkType, name, target)
+	}
+	return fmt.Sprintf("%s:%s", checkType, name)
+}
+
+// SetPreviousResults replaces the previous results, keyed by
+// PreviousResultKey.
+func SetPreviousResults(results map[string]PreviousResult) {
+	previousResultsLock.Lock()
+	defer previousResultsLock.Unlock()
+	previousResults = results
+}
+
+// GetPreviousResult fetches the previous result of a check.
+func GetPreviousResult(checkType string, name string, target string) (PreviousResult, bool) {
+	previousResultsLock.RLock()
+	defer previousResultsLock.RUnlock()
+	pr, ok := previousResults[PreviousResultKey(checkType, name, target)]
+	return pr, ok
+}
+
+// PreviousResult fetches the check's result from a previous run, so that
+// checks can compare against prior values.
+func (c *CheckBase) PreviousResult() (PreviousResult, bool) {
+	return GetPreviousResult(string(c.cType), c.Name, c.Target)
+}
@@ -0,0 +1,32 @@
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviousResult(t *testing.T) {
+	assert := assert.New(t)
+	defer SetPreviousResults(map[string]PreviousResult{})
+
+	assert.Equal("file:foo", PreviousResultKey("file", "foo", ""))
+	assert.Equal("file:foo@web", PreviousResultKey("file", "foo", "web"))
+
+	SetPreviousResults(map[string]PreviousResult{
+		"file:foo@web": {Status: result.Pass},
+	})
+	pr, ok := GetPreviousResult("file", "foo", "web")
+	assert.True(ok)
+	assert.Equal(result.Pass, pr.Status)
+	_, ok = GetPreviousResult("file", "foo", "")
+	assert.False(ok)
+
+	c := CheckBase{Name: "foo", Target: "web"}
+	c.Init("file")
+	pr, ok = c.PreviousResult()
+	assert.True(ok)
+	assert.Equal(result.Pass, pr.Status)
+}
@@ -0,0 +1,40 @@
+package config
+
+import "sync"
+
+// DataPublisher is implemented by checks publishing derived data under a
+// name for other checks to consume.
+type DataPublisher interface {
+	PublishesData() []string
+}
+
+// DataConsumer is implemented by checks consuming data published by other
+// checks; they are run after the checks publishing the data.
+type DataConsumer interface {
+	ConsumesData() []string
+}
+
+var publishedData = map[string][]byte{}
+var publishedDataLock = sync.RWMutex{}
+
+// PublishData stores data under a name for other checks to consume.
+func PublishData(name string, data []byte) {
+	publishedDataLock.Lock()
+	defer publishedDataLock.Unlock()
+	publishedData[name] = data
+}
+
+// GetPublishedData fetches the data published under a name.
+func GetPublishedData(name string) ([]byte, bool) {
+	publishedDataLock.RLock()
+	defer publishedDataLock.RUnlock()
+	data, ok := publishedData[name]
+	return data, ok
+}
+
+// ResetPublishedData removes all published data.
+func ResetPublishedData() {
+	publishedDataLock.Lock()
+	defer publishedDataLock.Unlock()
+	publishedData = map[string][]byte{}
+}
@@ -0,0 +1,25 @@
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishedData(t *testing.T) {
+	assert := assert.New(t)
+	ResetPublishedData()
+
+	_, ok := GetPublishedData("foo")
+	assert.False(ok)
+
+	PublishData("foo", []byte("bar"))
+	data, ok := GetPublishedData("foo")
+	assert.True(ok)
+	assert.Equal([]byte("bar"), data)
+
+	ResetPublishedData()
+	_, ok = GetPublishedData("foo")
+	assert.False(ok)
+}
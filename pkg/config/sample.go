@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// DisableSampling makes the checks run against all their files, ignoring any
+// sample configured; useful to get full coverage on scheduled runs.
+var DisableSampling bool
+
+// Sample restricts a file-based check to a subset of its files, so that large
+// repositories can be checked quickly. The same files are selected on each
+// run unless the seed is changed.
+type Sample struct {
+	// Percentage of the files to check.
+	Percentage float64 `yaml:"percentage,omitempty"`
+	// Maximum number of files to check.
+	MaxFiles int `yaml:"max-files,omitempty"`
+	// Seed used to select the files.
+	Seed int64 `yaml:"seed,omitempty"`
+}
+
+// Validate ensures the sample's values are usable.
+func (s *Sample) Validate() error {
+	if s.Percentage < 0 || s.Percentage > 100 {
+		return errors.New("percentage must be between 0 and 100")
+	}
+	if s.MaxFiles < 0 {
+		return errors.New("max-files must not be negative")
+	}
+	if s.Percentage == 0 && s.MaxFiles == 0 {
+		return errors.New("percentage or max-files must be provided")
+	}
+	return nil
+}
+
+// Select returns the sampled subset of the files, sorted; all files are
+// returned if the sample is nil or sampling is disabled.
+func (s *Sample) Select(files []string) []string {
+	if s == nil || DisableSampling {
+		return files
+	}
+
+	n := len(files)
+	if s.Percentage > 0 {
+		n = int(math.Ceil(float64(len(files)) * s.Percentage / 100))
+	}
+	if s.MaxFiles > 0 && n > s.MaxFiles {
+		n = s.MaxFiles
+	}
+	if n >= len(files) {
+		return files
+	}
+
+	// Sort first so the selection does not depend on the order the files
+	// were found in.
+	sorted := append([]string{}, files...)
+	sort.Strings(sorted)
+	selected := []string{}
+	for _, i := range rand.New(rand.NewSource(s.Seed)).Perm(len(sorted))[:n] {
+		selected = append(selected, sorted[i])
+	}
+	sort.Strings(selected)
+	return selected
+}
@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&Sample{Percentage: 10}).Validate())
+	assert.NoError((&Sample{MaxFiles: 10}).Validate())
+	assert.EqualError((&Sample{}).Validate(), "percentage or max-files must be provided")
+	assert.EqualError((&Sample{Percentage: 120}).Validate(), "percentage must be between 0 and 100")
+	assert.EqualError((&Sample{MaxFiles: -1}).Validate(), "max-files must not be negative")
+}
+
+func TestSampleSelect(t *testing.T) {
+	assert := assert.New(t)
+
+	files := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+
+	var nilSample *Sample
+	assert.Equal(files, nilSample.Select(files))
+	assert.Equal(files, (&Sample{Percentage: 100}).Select(files))
+	assert.Equal(files, (&Sample{MaxFiles: 20}).Select(files))
+
+	assert.Len((&Sample{Percentage: 25}).Select(files), 3)
+	assert.Len((&Sample{Percentage: 50, MaxFiles: 2}).Select(files), 2)
+
+	// The selection is deterministic and does not depend on the files order.
+	selected := (&Sample{MaxFiles: 4, Seed: 42}).Select(files)
+	assert.Equal(selected, (&Sample{MaxFiles: 4, Seed: 42}).Select(files))
+	reversed := []string{}
+	for i := len(files) - 1; i >= 0; i-- {
+		reversed = append(reversed, files[i])
+	}
+	assert.Equal(selected, (&Sample{MaxFiles: 4, Seed: 42}).Select(reversed))
+	assert.NotEqual(selected, (&Sample{MaxFiles: 4, Seed: 1}).Select(files))
+
+	curDisableSampling := DisableSampling
+	defer func() { DisableSampling = curDisableSampling }()
+	DisableSampling = true
+	assert.Equal(files, (&Sample{MaxFiles: 4}).Select(files))
+}
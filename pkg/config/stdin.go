@@ -0,0 +1,37 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// Stdin is the reader for the data piped to shipshape.
+var Stdin io.Reader = os.Stdin
+
+var stdinData []byte
+var stdinErr error
+var stdinOnce sync.Once
+
+// ReadStdin reads the data piped to shipshape; it is only read once and
+// shared across the checks consuming it.
+func ReadStdin() ([]byte, error) {
+	stdinOnce.Do(func() {
+		if f, ok := Stdin.(*os.File); ok {
+			if fi, err := f.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+				stdinErr = errors.New("no data piped to stdin")
+				return
+			}
+		}
+		stdinData, stdinErr = io.ReadAll(Stdin)
+	})
+	return stdinData, stdinErr
+}
+
+// ResetStdin allows the data piped to shipshape to be read again.
+func ResetStdin() {
+	stdinData = nil
+	stdinErr = nil
+	stdinOnce = sync.Once{}
+}
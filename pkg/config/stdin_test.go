@@ -0,0 +1,30 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadStdin(t *testing.T) {
+	assert := assert.New(t)
+
+	curStdin := Stdin
+	defer func() {
+		Stdin = curStdin
+		ResetStdin()
+	}()
+
+	Stdin = strings.NewReader("foo: bar")
+	ResetStdin()
+	data, err := ReadStdin()
+	assert.NoError(err)
+	assert.Equal("foo: bar", string(data))
+
+	// Data is shared across reads.
+	data, err = ReadStdin()
+	assert.NoError(err)
+	assert.Equal("foo: bar", string(data))
+}
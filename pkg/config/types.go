@@ -9,10 +9,126 @@ type Config struct {
 	// Default is high.
 	FailSeverity Severity `yaml:"fail-severity"`
 	Checks       CheckMap `yaml:"checks"`
-	Remediate    bool     `yaml:"-"`
+	// Hosts or containers against which checks can be run, keyed by name.
+	Targets map[string]Target `yaml:"targets"`
+	// Rules raising the severity of breaches, e.g, those left unresolved
+	// based on the history of the previous runs, or those of the checks
+	// tagged security on production.
+	Escalation []EscalationRule `yaml:"escalation"`
+	// Settings applying to the remediation of all checks.
+	Remediation RemediationConfig `yaml:"remediation"`
+	Remediate   bool              `yaml:"-"`
+	// Conventions the checks' names are normalised to.
+	Naming NamingConfig `yaml:"naming"`
+	// Default values of the variables used in the config as
+	// {{ vars.<name> }}.
+	Vars map[string]string `yaml:"vars"`
 	// If requesting LagoonFact output, the base url and token for the Lagoon
 	// api are required to infer environment IDs and the like.
 	LagoonApiBaseUrl string `yaml:"lagoon-api-base-url"`
+	// Credentials of the outputs, which can be encrypted values; the
+	// environment variables & flags take precedence.
+	Credentials CredentialsConfig `yaml:"credentials"`
+}
+
+// CredentialsConfig holds the credentials used when pushing or sending the
+// results.
+type CredentialsConfig struct {
+	LagoonApiToken        string `yaml:"lagoon-api-token"`
+	NotifyWebhook         string `yaml:"notify-webhook"`
+	S3AccessKeyId         string `yaml:"s3-access-key-id"`
+	S3SecretAccessKey     string `yaml:"s3-secret-access-key"`
+	S3SessionToken        string `yaml:"s3-session-token"`
+	ElasticsearchApiKey   string `yaml:"elasticsearch-api-key"`
+	ElasticsearchUsername string `yaml:"elasticsearch-username"`
+	ElasticsearchPassword string `yaml:"elasticsearch-password"`
+}
+
+// Target is a host or container against which checks can be run.
+type Target struct {
+	// Command prefix through which the checks' commands are run on the
+	// target, e.g, [docker, exec, -i, web] or [ssh, user@host].
+	Exec []string `yaml:"exec"`
+	// Local directory containing the target's files, used by the file-based
+	// checks; defaults to the project directory.
+	ProjectDir string `yaml:"project-dir"`
+	// Variables used in the config when run against the target with
+	// --target, e.g, its domain; they override the config's.
+	Vars map[string]string `yaml:"vars"`
+}
+
+// EscalationRule raises the severity of the breaches meeting all its
+// conditions; a rule without any condition applies to all breaches.
+type EscalationRule struct {
+	// Duration after which an unresolved breach is escalated, e.g, 30d.
+	After string `yaml:"after"`
+	// Tags, checks & targets of the breaches' checks; values can be regex
+	// (re:) or glob (glob:) patterns, e.g, glob:prod-*.
+	Tags    []string `yaml:"tags"`
+	Checks  []string `yaml:"checks"`
+	Targets []string `yaml:"targets"`
+	// Severities of the breaches before escalation, e.g, [low].
+	Severities []Severity `yaml:"severities"`
+	// Minimum number of breaches across the run meeting the other
+	// conditions for any of them to be escalated, e.g, 10.
+	MinBreaches int      `yaml:"min-breaches"`
+	Severity    Severity `yaml:"severity"`
+}
+
+// RemediationConfig holds the settings applying to the remediation of all
+// checks.
+type RemediationConfig struct {
+	// External approval required before remediating each check's breaches.
+	Gate *RemediationGate `yaml:"gate"`
+	// Maximum number of checks remediated at the same time; unlimited if 0.
+	Concurrency int `yaml:"concurrency"`
+	// Limits applying to the remediation of the checks of some types, e.g,
+	// at most one drush-based remediation at a time.
+	RateLimits []RemediationRateLimit `yaml:"rate-limits"`
+}
+
+// RemediationRateLimit limits the remediation of the checks whose type
+// matches; the limit is shared by all the matching checks.
+type RemediationRateLimit struct {
+	// Check types the limit applies to; values can be regex (re:) or glob
+	// (glob:) patterns, e.g, glob:drupal-*.
+	Types []string `yaml:"types"`
+	// Maximum number of the checks remediated at the same time; unlimited
+	// if 0.
+	Concurrency int `yaml:"concurrency"`
+	// Minimum duration between the start of two remediations, e.g, 30s.
+	Interval string `yaml:"interval"`
+}
+
+// RemediationGate is polled for approval before remediating a check's
+// breaches, e.g, by a change-management system. Either an HTTP callback or a
+// file flag can be used.
+type RemediationGate struct {
+	// Url polled with the breaches to remediate; it responds with a status of
+	// approved, rejected or pending.
+	Url string `yaml:"url"`
+	// Bearer token for the url, which can be an encrypted value.
+	Token string `yaml:"token"`
+	// Environment variable holding a bearer token for the url, used if no
+	// token is provided.
+	TokenEnv string `yaml:"token-env"`
+	// Go template for a file whose existence approves the remediation, e.g,
+	// /tmp/approvals/{{ .Check }}; a file containing "rejected" rejects it.
+	File string `yaml:"file"`
+	// Duration between polls, e.g, 30s; defaults to 10s.
+	Interval string `yaml:"interval"`
+	// Duration after which the remediation is skipped if not approved, e.g,
+	// 2h; defaults to 1h.
+	Timeout string `yaml:"timeout"`
+}
+
+// NamingConfig holds the conventions for the checks' names, keeping them
+// consistent across runs as the configs evolve.
+type NamingConfig struct {
+	// Regular expression the checks' names must match, e.g, ^[a-z0-9-]+$.
+	Pattern string `yaml:"pattern"`
+	// Legacy check names mapped to the names replacing them.
+	Aliases map[string]string `yaml:"aliases"`
 }
 
 type Severity string
@@ -24,6 +140,31 @@ const (
 	CriticalSeverity Severity = "critical"
 )
 
+var severityLevels = map[Severity]int{
+	LowSeverity:      0,
+	NormalSeverity:   1,
+	HighSeverity:     2,
+	CriticalSeverity: 3,
+}
+
+// IsValid determines whether the severity is a known one.
+func (s Severity) IsValid() bool {
+	_, ok := severityLevels[s]
+	return ok
+}
+
+// Compare returns -1, 0 or 1 when the severity is lower than, equal to or
+// higher than the other one.
+func (s Severity) Compare(other Severity) int {
+	switch {
+	case severityLevels[s] < severityLevels[other]:
+		return -1
+	case severityLevels[s] > severityLevels[other]:
+		return 1
+	}
+	return 0
+}
+
 type CheckMap map[CheckType][]Check
 
 type CheckType string
@@ -36,11 +177,26 @@ type Check interface {
 	GetName() string
 	GetType() CheckType
 	GetSeverity() Severity
+	GetTarget() string
+	GetWhen() string
+	GetControls() []string
+	GetTags() []string
+	GetOwner() string
+	GetDescription() string
+	GetRationale() string
+	GetDocUrl() string
+	GetRemediationHint() string
+	GetDependsOn() []string
+	IsDisabled() bool
+	SetName(name string)
+	SetDependsOn(names []string)
+	IsSensitive() bool
 	Merge(Check) error
 	RequiresData() bool
 	RequiresDatabase() bool
 	HasData(failCheck bool) bool
 	FetchData()
+	GetDataMap() map[string][]byte
 	UnmarshalDataMap()
 	AddBreach(result.Breach)
 	AddPass(msg string)
@@ -61,6 +217,34 @@ type CheckBase struct {
 	DataMap    map[string][]byte `yaml:"-"`
 	Result     result.Result     `yaml:"-"`
 	// Default severity is normal.
-	Severity           `yaml:"severity"`
+	Severity `yaml:"severity"`
+	// Name of the target the check runs against; runs locally if empty.
+	Target string `yaml:"target"`
+	// Condition for running the check, e.g, platform==drupal.
+	When string `yaml:"when"`
+	// Compliance framework controls the check provides evidence for, as
+	// <framework>:<control>, e.g, iso27001:A.12.6.1.
+	Controls []string `yaml:"controls"`
+	// Free-form labels used to organise the checks, e.g, [security, pci].
+	Tags []string `yaml:"tags"`
+	// Person or team responsible for the policy, e.g, platform-team.
+	Owner string `yaml:"owner"`
+	// What the check verifies, shown alongside its results.
+	Description string `yaml:"description"`
+	// Why the policy exists, e.g, the risk it mitigates.
+	Rationale string `yaml:"rationale"`
+	// Link to the runbook explaining the policy, added to the breaches.
+	DocUrl string `yaml:"doc-url"`
+	// Short hint on how to fix the breaches, added to them.
+	RemediationHint string `yaml:"remediation-hint"`
+	// Names of the checks which must pass for this check to run; it is
+	// skipped otherwise.
+	DependsOn []string `yaml:"depends-on"`
+	// Keep the check in the config without running it; it is reported as
+	// skipped.
+	Disabled bool `yaml:"disabled"`
+	// Mask the breach values in the outputs; remediators still get the full
+	// values.
+	Sensitive          bool `yaml:"sensitive"`
 	PerformRemediation bool `yaml:"-"`
 }
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// varRegex matches the {{ vars.<name> }} and ${var.<name>} placeholders; the
+// latter does not require the value to be quoted.
+var varRegex = regexp.MustCompile(`{{\s*vars\.([A-Za-z0-9_-]+)\s*}}|\$\{vars?\.([A-Za-z0-9_-]+)\}`)
+
+var envVarRegex = regexp.MustCompile(`\$\{env\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// InterpolateVars replaces the {{ vars.<name> }} & ${var.<name>} placeholders
+// in the scalar values of the config, leaving any other template untouched.
+func InterpolateVars(n *yaml.Node, vars map[string]string) error {
+	if n.Kind == yaml.ScalarNode {
+		var err error
+		n.Value = varRegex.ReplaceAllStringFunc(n.Value, func(m string) string {
+			sm := varRegex.FindStringSubmatch(m)
+			name := sm[1] + sm[2]
+			v, ok := vars[name]
+			if !ok && err == nil {
+				err = fmt.Errorf("undefined variable '%s' in config", name)
+			}
+			return v
+		})
+		return err
+	}
+	for _, c := range n.Content {
+		if err := InterpolateVars(c, vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpandEnvVars replaces the ${env.<NAME>} placeholders in the variables'
+// values with the environment variables, so that the values can be derived
+// from the environment the config is run in.
+func ExpandEnvVars(vars map[string]string) error {
+	for _, name := range utils.SortedKeys(vars) {
+		var err error
+		vars[name] = envVarRegex.ReplaceAllStringFunc(vars[name], func(m string) string {
+			envName := envVarRegex.FindStringSubmatch(m)[1]
+			envValue, ok := os.LookupEnv(envName)
+			if !ok && err == nil {
+				err = fmt.Errorf("undefined environment variable '%s' in variable '%s'", envName, name)
+			}
+			return envValue
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestInterpolateVars(t *testing.T) {
+	assert := assert.New(t)
+
+	n := yaml.Node{}
+	yaml.Unmarshal([]byte(`
+checks:
+  http:
+    - name: Homepage
+      url: 'https://{{ vars.domain }}/{{vars.path}}'
+      app-name: '{{ env.LAGOON_PROJECT }}'
+      paths: ['{{ vars.docroot }}/sites']
+      path: ${var.docroot}/modules
+      docroot: '${vars.docroot}'
+      command: echo ${PATH}
+`), &n)
+	err := InterpolateVars(&n, map[string]string{
+		"domain":  "example.com",
+		"path":    "home",
+		"docroot": "web",
+	})
+	assert.NoError(err)
+	out, _ := yaml.Marshal(&n)
+	assert.Equal(`checks:
+    http:
+        - name: Homepage
+          url: 'https://example.com/home'
+          app-name: '{{ env.LAGOON_PROJECT }}'
+          paths: ['web/sites']
+          path: web/modules
+          docroot: 'web'
+          command: echo ${PATH}
+`, string(out))
+
+	n = yaml.Node{}
+	yaml.Unmarshal([]byte("url: 'https://{{ vars.domain }}'"), &n)
+	err = InterpolateVars(&n, map[string]string{})
+	assert.EqualError(err, "undefined variable 'domain' in config")
+
+	n = yaml.Node{}
+	yaml.Unmarshal([]byte("url: https://${var.domain}"), &n)
+	err = InterpolateVars(&n, map[string]string{})
+	assert.EqualError(err, "undefined variable 'domain' in config")
+}
+
+func TestExpandEnvVars(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("SITE_DOMAIN", "example.com")
+	vars := map[string]string{
+		"url":     "https://${env.SITE_DOMAIN}/${env.SITE_DOMAIN}",
+		"docroot": "web",
+		"shell":   "${SITE_DOMAIN}",
+	}
+	assert.NoError(ExpandEnvVars(vars))
+	assert.Equal(map[string]string{
+		"url":     "https://example.com/example.com",
+		"docroot": "web",
+		"shell":   "${SITE_DOMAIN}",
+	}, vars)
+
+	vars = map[string]string{"domain": "${env.SITE_DOMAIN_UNSET}"}
+	assert.EqualError(ExpandEnvVars(vars),
+		"undefined environment variable 'SITE_DOMAIN_UNSET' in variable 'domain'")
+}
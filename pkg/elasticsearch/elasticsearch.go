@@ -0,0 +1,263 @@
+// Package elasticsearch provides the functions for indexing the results into
+// Elasticsearch or OpenSearch using the bulk API, with a summary document for
+// each run and a document for each breach, e.g, for dashboards across sites.
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/notify"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const DefaultIndexTemplate = `shipshape-{{ now | date "2006.01" }}`
+const DefaultIdTemplate = `{{ .Project }}-{{ .RunId }}-{{ or .Fingerprint "run" }}`
+
+var Url string
+var IndexTemplate string
+var IdTemplate string
+
+var apiKey string
+var username string
+var password string
+
+// TemplateData is the data available when rendering the index & id
+// templates; Fingerprint and the check fields are empty for the run summary.
+type TemplateData struct {
+	Project     string
+	RunId       string
+	DocType     string
+	Fingerprint string
+	Check       string
+	CheckType   string
+	Target      string
+}
+
+// Document is a document to index.
+type Document struct {
+	Index string
+	Id    string
+	Body  map[string]any
+}
+
+// ReadEnvVars reads the credentials, either an API key from
+// ELASTICSEARCH_API_KEY or basic auth credentials from ELASTICSEARCH_USERNAME
+// & ELASTICSEARCH_PASSWORD, falling back to those of the config; none are
+// required for unsecured clusters.
+func ReadEnvVars(creds config.CredentialsConfig) error {
+	apiKey = os.Getenv("ELASTICSEARCH_API_KEY")
+	username = os.Getenv("ELASTICSEARCH_USERNAME")
+	password = os.Getenv("ELASTICSEARCH_PASSWORD")
+	if apiKey == "" && username == "" && password == "" {
+		apiKey = creds.ElasticsearchApiKey
+		username = creds.ElasticsearchUsername
+		password = creds.ElasticsearchPassword
+	}
+	if username != "" && password == "" {
+		return fmt.Errorf("elasticsearch password required; please ensure " +
+			"ELASTICSEARCH_PASSWORD is set along with ELASTICSEARCH_USERNAME")
+	}
+	return nil
+}
+
+// RenderTemplate renders an index or id template. Besides the TemplateData
+// fields, the template can use the `now` and `date` functions, e.g,
+// `{{ now | date "2006.01.02" }}`.
+func RenderTemplate(tmpl string, data TemplateData) (string, error) {
+	t, err := template.New("elasticsearch").Funcs(template.FuncMap{
+		"now":  utils.TimeNow,
+		"date": func(layout string, t time.Time) string { return t.Format(layout) },
+	}).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.Buffer{}
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	if buf.Len() == 0 {
+		return "", fmt.Errorf("template '%s' rendered an empty value", tmpl)
+	}
+	return buf.String(), nil
+}
+
+// Documents generates the run summary document followed by a document for
+// each breach of the results.
+func Documents(rl result.ResultList, project string) ([]Document, error) {
+	now := utils.TimeNow()
+	runId := now.UTC().Format("20060102T150405")
+	timestamp := now.UTC().Format(time.RFC3339)
+
+	run := TemplateData{Project: project, RunId: runId, DocType: "run"}
+	doc, err := newDocument(run, map[string]any{
+		"status":                   rl.Status(),
+		"total-checks":             rl.TotalChecks,
+		"total-breaches":           rl.TotalBreaches,
+		"failing-breaches":         rl.FailingBreaches,
+		"informational-breaches":   rl.InformationalBreaches,
+		"breach-count-by-type":     rl.BreachCountByType,
+		"breach-count-by-severity": rl.BreachCountBySeverity,
+		"remediation-performed":    rl.RemediationPerformed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	doc.Body["@timestamp"] = timestamp
+	docs := []Document{doc}
+
+	for _, r := range rl.Results {
+		for _, b := range r.Breaches {
+			data, err := json.Marshal(b)
+			if err != nil {
+				return nil, err
+			}
+			body := map[string]any{}
+			if err := json.Unmarshal(data, &body); err != nil {
+				return nil, err
+			}
+			body["breach"] = b.String()
+			body["check-status"] = r.Status
+
+			doc, err := newDocument(TemplateData{
+				Project:     project,
+				RunId:       runId,
+				DocType:     "breach",
+				Fingerprint: notify.Fingerprint(r, b),
+				Check:       r.Name,
+				CheckType:   r.CheckType,
+				Target:      r.Target,
+			}, body)
+			if err != nil {
+				return nil, err
+			}
+			doc.Body["@timestamp"] = timestamp
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+func newDocument(data TemplateData, body map[string]any) (Document, error) {
+	indexTmpl := IndexTemplate
+	if indexTmpl == "" {
+		indexTmpl = DefaultIndexTemplate
+	}
+	idTmpl := IdTemplate
+	if idTmpl == "" {
+		idTmpl = DefaultIdTemplate
+	}
+	index, err := RenderTemplate(indexTmpl, data)
+	if err != nil {
+		return Document{}, err
+	}
+	id, err := RenderTemplate(idTmpl, data)
+	if err != nil {
+		return Document{}, err
+	}
+
+	body["doc-type"] = data.DocType
+	body["project"] = data.Project
+	body["run-id"] = data.RunId
+	if data.Fingerprint != "" {
+		body["fingerprint"] = data.Fingerprint
+		body["target"] = data.Target
+	}
+	return Document{Index: index, Id: id, Body: body}, nil
+}
+
+// Bulk indexes the documents using a single bulk request.
+func Bulk(docs []Document) error {
+	payload := bytes.Buffer{}
+	enc := json.NewEncoder(&payload)
+	for _, d := range docs {
+		action := map[string]any{"index": map[string]string{"_index": d.Index, "_id": d.Id}}
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+		if err := enc.Encode(d.Body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(Url, "/")+"/_bulk", &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+apiKey)
+	} else if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := utils.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unable to index the results: %s: %s",
+			resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	// The bulk API succeeds even when some of the documents fail to index.
+	var bulkResp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Id    string `json:"_id"`
+			Error struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &bulkResp); err != nil {
+		return fmt.Errorf("invalid bulk response: %w", err)
+	}
+	if !bulkResp.Errors {
+		return nil
+	}
+	failed := 0
+	var firstErr string
+	for _, item := range bulkResp.Items {
+		for _, res := range item {
+			if res.Error.Type == "" {
+				continue
+			}
+			if failed == 0 {
+				firstErr = fmt.Sprintf("%s: %s: %s", res.Id, res.Error.Type, res.Error.Reason)
+			}
+			failed++
+		}
+	}
+	return fmt.Errorf("unable to index %d of %d documents, e.g, %s", failed, len(docs), firstErr)
+}
+
+// Index indexes the results, returning the number of documents indexed.
+func Index(rl result.ResultList) (int, error) {
+	projectDir, err := filepath.Abs(config.ProjectDir)
+	if err != nil {
+		return 0, err
+	}
+	docs, err := Documents(rl, filepath.Base(projectDir))
+	if err != nil {
+		return 0, err
+	}
+	if err := Bulk(docs); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
@@ -0,0 +1,186 @@
+package elasticsearch_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	. "github.com/salsadigitalauorg/shipshape/pkg/elasticsearch"
+	"github.com/salsadigitalauorg/shipshape/pkg/notify"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockTimeNow() func() {
+	curTimeNow := utils.TimeNow
+	utils.TimeNow = func() time.Time {
+		return time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	}
+	return func() { utils.TimeNow = curTimeNow }
+}
+
+func testResultList() result.ResultList {
+	breach := &result.ValueBreach{ValueLabel: "file", Value: "adminer.php"}
+	breach.SetCommonValues("file", "Illegal files", "high")
+	rl := result.NewResultList(false)
+	rl.Results = []result.Result{
+		{Name: "Illegal files", CheckType: "file", Status: result.Fail,
+			Breaches: []result.Breach{breach}},
+		{Name: "Modules", CheckType: "yaml", Status: result.Pass},
+	}
+	rl.TotalChecks = 2
+	rl.TotalBreaches = 1
+	rl.FailingBreaches = 1
+	return rl
+}
+
+func TestRenderTemplate(t *testing.T) {
+	assert := assert.New(t)
+	defer mockTimeNow()()
+
+	index, err := RenderTemplate(DefaultIndexTemplate, TemplateData{})
+	assert.NoError(err)
+	assert.Equal("shipshape-2026.10", index)
+
+	id, err := RenderTemplate(DefaultIdTemplate, TemplateData{Project: "site", RunId: "20261001T123000"})
+	assert.NoError(err)
+	assert.Equal("site-20261001T123000-run", id)
+
+	id, err = RenderTemplate(DefaultIdTemplate, TemplateData{Project: "site", RunId: "20261001T123000", Fingerprint: "abc"})
+	assert.NoError(err)
+	assert.Equal("site-20261001T123000-abc", id)
+
+	_, err = RenderTemplate(`{{ .Unknown }}`, TemplateData{})
+	assert.ErrorContains(err, "can't evaluate field Unknown")
+
+	_, err = RenderTemplate(`{{ .Target }}`, TemplateData{})
+	assert.EqualError(err, "template '{{ .Target }}' rendered an empty value")
+}
+
+func TestDocuments(t *testing.T) {
+	assert := assert.New(t)
+	defer mockTimeNow()()
+
+	rl := testResultList()
+	docs, err := Documents(rl, "site")
+	assert.NoError(err)
+	if !assert.Len(docs, 2) {
+		return
+	}
+
+	assert.Equal("shipshape-2026.10", docs[0].Index)
+	assert.Equal("site-20261001T123000-run", docs[0].Id)
+	assert.Equal(map[string]any{
+		"@timestamp":               "2026-10-01T12:30:00Z",
+		"doc-type":                 "run",
+		"project":                  "site",
+		"run-id":                   "20261001T123000",
+		"status":                   result.Fail,
+		"total-checks":             uint32(2),
+		"total-breaches":           uint32(1),
+		"failing-breaches":         uint32(1),
+		"informational-breaches":   uint32(0),
+		"breach-count-by-type":     map[string]int{},
+		"breach-count-by-severity": map[string]int{},
+		"remediation-performed":    false,
+	}, docs[0].Body)
+
+	fingerprint := notify.Fingerprint(rl.Results[0], rl.Results[0].Breaches[0])
+	assert.Equal("site-20261001T123000-"+fingerprint, docs[1].Id)
+	assert.Equal(map[string]any{
+		"@timestamp":   "2026-10-01T12:30:00Z",
+		"doc-type":     "breach",
+		"project":      "site",
+		"run-id":       "20261001T123000",
+		"fingerprint":  fingerprint,
+		"target":       "",
+		"breach-type":  "value",
+		"check-type":   "file",
+		"check-name":   "Illegal files",
+		"check-status": result.Fail,
+		"severity":     "high",
+		"value-label":  "file",
+		"value":        "adminer.php",
+		"breach":       "[file] adminer.php",
+		"remediation":  map[string]any{},
+	}, docs[1].Body)
+}
+
+func TestIndex(t *testing.T) {
+	assert := assert.New(t)
+	defer mockTimeNow()()
+
+	curUrl := Url
+	defer func() { Url = curUrl }()
+
+	var lines []map[string]any
+	var contentType, auth string
+	bulkResponse := `{"errors":false,"items":[]}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/_bulk", r.URL.Path)
+		contentType = r.Header.Get("Content-Type")
+		auth = r.Header.Get("Authorization")
+		lines = nil
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			line := map[string]any{}
+			json.Unmarshal(scanner.Bytes(), &line)
+			lines = append(lines, line)
+		}
+		w.Write([]byte(bulkResponse))
+	}))
+	defer ts.Close()
+	Url = ts.URL + "/"
+
+	t.Setenv("ELASTICSEARCH_API_KEY", "")
+	t.Setenv("ELASTICSEARCH_USERNAME", "elastic")
+	t.Setenv("ELASTICSEARCH_PASSWORD", "changeme")
+	assert.NoError(ReadEnvVars(config.CredentialsConfig{}))
+
+	count, err := Index(testResultList())
+	assert.NoError(err)
+	assert.Equal(2, count)
+	assert.Equal("application/x-ndjson", contentType)
+	assert.Equal("Basic ZWxhc3RpYzpjaGFuZ2VtZQ==", auth)
+	if assert.Len(lines, 4) {
+		assert.Equal(map[string]any{"index": map[string]any{
+			"_index": "shipshape-2026.10",
+			"_id":    lines[0]["index"].(map[string]any)["_id"],
+		}}, lines[0])
+		assert.Equal("run", lines[1]["doc-type"])
+		assert.Equal("breach", lines[3]["doc-type"])
+	}
+
+	t.Setenv("ELASTICSEARCH_API_KEY", "secret")
+	assert.NoError(ReadEnvVars(config.CredentialsConfig{}))
+	bulkResponse = `{"errors":true,"items":[
+		{"index":{"_id":"a","status":201}},
+		{"index":{"_id":"b","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}
+	]}`
+	_, err = Index(testResultList())
+	assert.EqualError(err, "unable to index 1 of 2 documents, e.g, b: mapper_parsing_exception: failed to parse")
+	assert.Equal("ApiKey secret", auth)
+}
+
+func TestReadEnvVars(t *testing.T) {
+	t.Setenv("ELASTICSEARCH_API_KEY", "")
+	t.Setenv("ELASTICSEARCH_USERNAME", "elastic")
+	t.Setenv("ELASTICSEARCH_PASSWORD", "")
+	assert.EqualError(t, ReadEnvVars(config.CredentialsConfig{}), "elasticsearch password required; "+
+		"please ensure ELASTICSEARCH_PASSWORD is set along with ELASTICSEARCH_USERNAME")
+
+	t.Setenv("ELASTICSEARCH_USERNAME", "")
+	assert.EqualError(t, ReadEnvVars(config.CredentialsConfig{
+		ElasticsearchUsername: "elastic",
+	}), "elasticsearch password required; "+
+		"please ensure ELASTICSEARCH_PASSWORD is set along with ELASTICSEARCH_USERNAME")
+	assert.NoError(t, ReadEnvVars(config.CredentialsConfig{
+		ElasticsearchUsername: "elastic",
+		ElasticsearchPassword: "changeme",
+	}))
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
 
 	"github.com/hasura/go-graphql-client"
 	log "github.com/sirupsen/logrus"
@@ -177,8 +178,7 @@ func ProblemsToInsightsRemote(problems []Problem, serviceEndpoint string, bearer
 	req, _ := http.NewRequest(http.MethodPost, serviceEndpoint, bytes.NewBuffer(bodyString))
 	req.Header.Set("Authorization", bearerToken)
 	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-	response, err := client.Do(req)
+	response, err := utils.HttpClient.Do(req)
 
 	if err != nil {
 		return err
@@ -0,0 +1,178 @@
+// Package notify provides the functions for notifying a webhook, e.g, a
+// Slack incoming webhook, of the breaches detected; breaches already notified
+// within a window are skipped to prevent alert storms from scheduled runs.
+package notify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const DefaultWindow = "24h"
+
+var Webhook string
+var StateFile string
+var Window string
+
+// Notification is a breach to be notified.
+type Notification struct {
+	Fingerprint string `json:"fingerprint"`
+	Check       string `json:"check"`
+	CheckType   string `json:"check-type"`
+	Target      string `json:"target,omitempty"`
+	Severity    string `json:"severity"`
+	Breach      string `json:"breach"`
+}
+
+// State records when breaches were last notified, keyed by fingerprint.
+type State struct {
+	Notified map[string]time.Time `json:"notified"`
+}
+
+// ReadState reads the notification state from the file, returning an empty
+// state if it does not exist yet.
+func ReadState(path string) (*State, error) {
+	s := &State{Notified: map[string]time.Time{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid notification state file '%s': %w", path, err)
+	}
+	if s.Notified == nil {
+		s.Notified = map[string]time.Time{}
+	}
+	return s, nil
+}
+
+// Write saves the state to the file.
+func (s *State) Write(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Filter returns the notifications not already sent within the window,
+// recording them as notified; entries older than the window are forgotten.
+func (s *State) Filter(notifications []Notification, window time.Duration) []Notification {
+	now := utils.TimeNow()
+	for fp, t := range s.Notified {
+		if now.Sub(t) >= window {
+			delete(s.Notified, fp)
+		}
+	}
+
+	filtered := []Notification{}
+	for _, n := range notifications {
+		if _, ok := s.Notified[n.Fingerprint]; ok {
+			continue
+		}
+		s.Notified[n.Fingerprint] = now
+		filtered = append(filtered, n)
+	}
+	return filtered
+}
+
+// Fingerprint identifies a breach across runs.
+func Fingerprint(r result.Result, b result.Breach) string {
+	sum := sha256.Sum256([]byte(strings.Join(
+		[]string{r.CheckType, r.Name, r.Target, b.String()}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Notifications lists the unresolved breaches of the results.
+func Notifications(rl result.ResultList) []Notification {
+	notifications := []Notification{}
+	for _, r := range rl.Results {
+		for _, b := range r.Breaches {
+			if b.GetRemediation().Status == result.RemediationStatusSuccess {
+				continue
+			}
+			notifications = append(notifications, Notification{
+				Fingerprint: Fingerprint(r, b),
+				Check:       r.Name,
+				CheckType:   r.CheckType,
+				Target:      r.Target,
+				Severity:    b.GetSeverity(),
+				Breach:      b.String(),
+			})
+		}
+	}
+	return notifications
+}
+
+// Send posts the notifications to the webhook; the payload's text field
+// summarises them for chat services such as Slack.
+func Send(url string, notifications []Notification) error {
+	lines := []string{fmt.Sprintf("%d new breach(es) detected by shipshape:", len(notifications))}
+	for _, n := range notifications {
+		lines = append(lines, fmt.Sprintf("• [%s] %s: %s", n.Severity, n.Check, n.Breach))
+	}
+	data, err := json.Marshal(map[string]any{
+		"text":     strings.Join(lines, "\n"),
+		"breaches": notifications,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := utils.HttpClient.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("notification failed with status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// Notify sends the breaches of the results to the webhook, skipping the ones
+// already notified within the window if a state file is configured. It
+// returns the number of breaches notified.
+func Notify(rl result.ResultList) (int, error) {
+	notifications := Notifications(rl)
+	var state *State
+	if StateFile != "" {
+		window, err := utils.ParseDuration(Window)
+		if err != nil {
+			return 0, fmt.Errorf("invalid notification window: %w", err)
+		}
+		if state, err = ReadState(StateFile); err != nil {
+			return 0, err
+		}
+		notifications = state.Filter(notifications, window)
+	}
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+
+	if err := Send(Webhook, notifications); err != nil {
+		return 0, err
+	}
+	// The state is only saved once notified, so that failed notifications
+	// are retried on the next run.
+	if state != nil {
+		if err := state.Write(StateFile); err != nil {
+			return 0, err
+		}
+	}
+	return len(notifications), nil
+}
@@ -0,0 +1,143 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/notify"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResultList() result.ResultList {
+	breach := &result.ValueBreach{Value: "adminer.php"}
+	breach.SetCommonValues("file", "Illegal files", "high")
+	remediated := &result.ValueBreach{Value: "fixed"}
+	remediated.SetRemediation(result.RemediationStatusSuccess, "removed")
+	rl := result.NewResultList(false)
+	rl.Results = []result.Result{
+		{Name: "Illegal files", CheckType: "file", Status: result.Fail,
+			Breaches: []result.Breach{breach, remediated}},
+		{Name: "Modules", CheckType: "yaml", Status: result.Pass},
+	}
+	return rl
+}
+
+func TestNotifications(t *testing.T) {
+	assert := assert.New(t)
+
+	rl := testResultList()
+	notifications := Notifications(rl)
+	assert.Equal([]Notification{{
+		Fingerprint: Fingerprint(rl.Results[0], rl.Results[0].Breaches[0]),
+		Check:       "Illegal files",
+		CheckType:   "file",
+		Severity:    "high",
+		Breach:      "adminer.php",
+	}}, notifications)
+	assert.Len(notifications[0].Fingerprint, 16)
+
+	other := rl.Results[0]
+	other.Target = "web"
+	assert.NotEqual(notifications[0].Fingerprint, Fingerprint(other, other.Breaches[0]))
+}
+
+func TestStateFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	utils.TimeNow = func() time.Time { return now }
+
+	s := &State{Notified: map[string]time.Time{
+		"recent": now.Add(-time.Hour),
+		"old":    now.Add(-48 * time.Hour),
+	}}
+	filtered := s.Filter([]Notification{
+		{Fingerprint: "recent"}, {Fingerprint: "old"}, {Fingerprint: "new"},
+	}, 24*time.Hour)
+	assert.Equal([]Notification{{Fingerprint: "old"}, {Fingerprint: "new"}}, filtered)
+	assert.Equal(map[string]time.Time{
+		"recent": now.Add(-time.Hour),
+		"old":    now,
+		"new":    now,
+	}, s.Notified)
+}
+
+func TestReadWriteState(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := ReadState(path)
+	assert.NoError(err)
+	assert.Empty(s.Notified)
+
+	notified := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	s.Notified["abc"] = notified
+	assert.NoError(s.Write(path))
+	s, err = ReadState(path)
+	assert.NoError(err)
+	assert.Equal(notified, s.Notified["abc"])
+
+	os.WriteFile(path, []byte("{"), 0644)
+	_, err = ReadState(path)
+	assert.ErrorContains(err, "invalid notification state file")
+}
+
+func TestNotify(t *testing.T) {
+	assert := assert.New(t)
+
+	var payloads []map[string]any
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]any{}
+		json.Unmarshal(body, &payload)
+		payloads = append(payloads, payload)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	Webhook = srv.URL
+	StateFile = filepath.Join(t.TempDir(), "state.json")
+	Window = DefaultWindow
+	defer func() { Webhook, StateFile, Window = "", "", "" }()
+
+	rl := testResultList()
+	count, err := Notify(rl)
+	assert.NoError(err)
+	assert.Equal(1, count)
+	assert.Len(payloads, 1)
+	assert.Equal("1 new breach(es) detected by shipshape:\n• [high] Illegal files: adminer.php",
+		payloads[0]["text"])
+	assert.Len(payloads[0]["breaches"], 1)
+
+	// The breach is not notified again within the window.
+	count, err = Notify(rl)
+	assert.NoError(err)
+	assert.Equal(0, count)
+	assert.Len(payloads, 1)
+
+	// Without a state file, all breaches are notified.
+	StateFile = ""
+	count, err = Notify(rl)
+	assert.NoError(err)
+	assert.Equal(1, count)
+
+	status = http.StatusInternalServerError
+	_, err = Notify(rl)
+	assert.ErrorContains(err, "notification failed with status 500")
+
+	StateFile = filepath.Join(t.TempDir(), "state.json")
+	Window = "soon"
+	_, err = Notify(rl)
+	assert.ErrorContains(err, "invalid notification window")
+}
@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PluginCheck is a check whose type is provided by a plugin; its config is
+// passed as-is to the plugin, which runs it.
+type PluginCheck struct {
+	config.CheckBase `yaml:",inline"`
+	// The check's config as defined in the checks file.
+	Config map[string]any `yaml:"-"`
+	plugin *Plugin
+}
+
+// UnmarshalYAML keeps the whole config for the plugin, besides the common
+// fields.
+func (c *PluginCheck) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode(&c.CheckBase); err != nil {
+		return err
+	}
+	return value.Decode(&c.Config)
+}
+
+// MarshalYAML outputs the config as defined in the checks file.
+func (c *PluginCheck) MarshalYAML() (interface{}, error) {
+	return c.Config, nil
+}
+
+// Merge implementation for PluginCheck check; the config is merged key by
+// key.
+func (c *PluginCheck) Merge(mergeCheck config.Check) error {
+	pluginMergeCheck := mergeCheck.(*PluginCheck)
+	if err := c.CheckBase.Merge(&pluginMergeCheck.CheckBase); err != nil {
+		return err
+	}
+
+	if c.Config == nil {
+		c.Config = map[string]any{}
+	}
+	for k, v := range pluginMergeCheck.Config {
+		c.Config[k] = v
+	}
+	return nil
+}
+
+// RequiresData implementation for PluginCheck check; the plugin fetches its
+// own data.
+func (c *PluginCheck) RequiresData() bool { return false }
+
+// RunCheck has the plugin run the check and adds its outcome to the result.
+func (c *PluginCheck) RunCheck() {
+	resp, err := c.plugin.RunCheck(CheckRequest{
+		Type:       string(c.GetType()),
+		Name:       c.Name,
+		Config:     c.Config,
+		ProjectDir: config.ProjectDir,
+		Target:     c.Target,
+	})
+	if err != nil {
+		c.AddBreach(&result.KeyValueBreach{
+			KeyLabel:   "plugin",
+			Key:        c.plugin.Name,
+			ValueLabel: "error",
+			Value:      err.Error()})
+		return
+	}
+
+	for _, raw := range resp.Breaches {
+		b, err := result.UnmarshalBreach(raw)
+		if err != nil {
+			c.AddBreach(&result.KeyValueBreach{
+				KeyLabel:   "plugin",
+				Key:        c.plugin.Name,
+				ValueLabel: "invalid breach",
+				Value:      err.Error()})
+			continue
+		}
+		c.AddBreach(b)
+	}
+	for _, p := range resp.Passes {
+		c.AddPass(p)
+	}
+	for _, w := range resp.Warnings {
+		c.AddWarning(w)
+	}
+	if len(c.Result.Breaches) == 0 {
+		c.Result.Status = result.Pass
+	}
+}
@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// GrpcService is the name of the gRPC service served by the plugins; see
+// plugin.proto for its definition.
+const GrpcService = "shipshape.plugin.Plugin"
+
+// pluginName is the name under which the provider is dispensed by
+// go-plugin.
+const pluginName = "provider"
+
+// Handshake is the go-plugin handshake shared by shipshape & its plugins.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  ProtocolVersion,
+	MagicCookieKey:   MagicCookieKey,
+	MagicCookieValue: MagicCookieValue,
+}
+
+// providerPlugin is the go-plugin implementation serving a Provider over
+// gRPC.
+type providerPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	provider Provider
+}
+
+func (p *providerPlugin) GRPCServer(_ *goplugin.GRPCBroker, s *grpc.Server) error {
+	s.RegisterService(&serviceDesc, &grpcServer{p.provider})
+	return nil
+}
+
+func (p *providerPlugin) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &grpcClient{c}, nil
+}
+
+// The methods carry their requests & responses as json documents wrapped in
+// a google.protobuf.BytesValue, which spares plugins written in other
+// languages from generating any code besides the well-known types.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: GrpcService,
+	HandlerType: (*Provider)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("Describe", func(p Provider, _ []byte) (any, error) {
+			return p.Describe(), nil
+		}),
+		unaryMethod("RunCheck", func(p Provider, in []byte) (any, error) {
+			req := CheckRequest{}
+			if err := json.Unmarshal(in, &req); err != nil {
+				return nil, err
+			}
+			return p.RunCheck(req)
+		}),
+		unaryMethod("Output", func(p Provider, in []byte) (any, error) {
+			req := OutputRequest{}
+			if err := json.Unmarshal(in, &req); err != nil {
+				return nil, err
+			}
+			return p.Output(req)
+		}),
+	},
+	Metadata: "plugin.proto",
+}
+
+// grpcServer is the plugin's end of the connection.
+type grpcServer struct {
+	Provider
+}
+
+// unaryMethod creates the gRPC method calling the provider with the json
+// request and replying with its json response.
+func unaryMethod(name string, call func(p Provider, in []byte) (any, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := &wrapperspb.BytesValue{}
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(_ context.Context, req any) (any, error) {
+				resp, err := call(srv.(*grpcServer).Provider, req.(*wrapperspb.BytesValue).GetValue())
+				if err != nil {
+					return nil, err
+				}
+				data, err := json.Marshal(resp)
+				if err != nil {
+					return nil, err
+				}
+				return wrapperspb.Bytes(data), nil
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + GrpcService + "/" + name}
+			return interceptor(ctx, in, info, handler)
+		},
+	}
+}
+
+// grpcClient is shipshape's end of the connection.
+type grpcClient struct {
+	conn *grpc.ClientConn
+}
+
+// call invokes the plugin's method with the json request and decodes its
+// json response.
+func (c *grpcClient) call(method string, req any, resp any) error {
+	in := &wrapperspb.BytesValue{}
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		in.Value = data
+	}
+	out := &wrapperspb.BytesValue{}
+	if err := c.conn.Invoke(context.Background(), "/"+GrpcService+"/"+method, in, out); err != nil {
+		// Only the plugin's message is kept, without the status code.
+		return errors.New(status.Convert(err).Message())
+	}
+	return json.Unmarshal(out.GetValue(), resp)
+}
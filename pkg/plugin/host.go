@@ -0,0 +1,180 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// Plugin is a plugin binary run by shipshape.
+type Plugin struct {
+	Path string
+	Description
+	client   *goplugin.Client
+	provider *grpcClient
+}
+
+// Loaded is the list of plugins loaded from the plugins directory.
+var Loaded []*Plugin
+
+// outputs maps the output formats to the plugins providing them.
+var outputs = map[string]*Plugin{}
+
+// Start runs the plugin binary and fetches its description.
+func Start(path string) (*Plugin, error) {
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          goplugin.PluginSet{pluginName: &providerPlugin{}},
+		Cmd:              exec.Command(path),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		// The plugins' logs are passed through.
+		Stderr: os.Stderr,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   "plugin",
+			Output: os.Stderr,
+			Level:  hclog.Error,
+		}),
+	})
+	p := &Plugin{Path: path, client: client}
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		p.Close()
+		return nil, fmt.Errorf("unable to start plugin '%s': %w", path, err)
+	}
+	raw, err := rpcClient.Dispense(pluginName)
+	if err != nil {
+		p.Close()
+		return nil, fmt.Errorf("unable to start plugin '%s': %w", path, err)
+	}
+	p.provider = raw.(*grpcClient)
+	if err := p.provider.call("Describe", nil, &p.Description); err != nil {
+		p.Close()
+		return nil, fmt.Errorf("unable to describe plugin '%s': %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = filepath.Base(path)
+	}
+	return p, nil
+}
+
+// Close ends the connection to the plugin and stops it.
+func (p *Plugin) Close() {
+	p.client.Kill()
+}
+
+// RunCheck runs one of the plugin's checks.
+func (p *Plugin) RunCheck(req CheckRequest) (CheckResponse, error) {
+	resp := CheckResponse{}
+	err := p.provider.call("RunCheck", req, &resp)
+	return resp, err
+}
+
+// Output renders the results in one of the plugin's output formats.
+func (p *Plugin) Output(req OutputRequest) (OutputResponse, error) {
+	resp := OutputResponse{}
+	err := p.provider.call("Output", req, &resp)
+	return resp, err
+}
+
+// LoadDir starts the executables found in the directory and registers the
+// check types & output formats they provide.
+func LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		p, err := Start(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := Register(p); err != nil {
+			p.Close()
+			return err
+		}
+		log.WithFields(log.Fields{
+			"plugin":      p.Name,
+			"check-types": p.CheckTypes,
+			"outputs":     p.Outputs,
+		}).Info("plugin loaded")
+	}
+	return nil
+}
+
+// Register adds the plugin's check types to the checks registry and makes
+// its output formats available.
+func Register(p *Plugin) error {
+	for _, ct := range p.CheckTypes {
+		if _, ok := config.ChecksRegistry[config.CheckType(ct)]; ok {
+			return fmt.Errorf("check type '%s' provided by plugin '%s' is already registered", ct, p.Name)
+		}
+	}
+	for _, o := range p.Outputs {
+		if other, ok := outputs[o]; ok {
+			return fmt.Errorf("output '%s' provided by plugin '%s' is already provided by '%s'", o, p.Name, other.Name)
+		}
+	}
+
+	for _, ct := range p.CheckTypes {
+		config.ChecksRegistry[config.CheckType(ct)] = func() config.Check {
+			return &PluginCheck{plugin: p}
+		}
+	}
+	for _, o := range p.Outputs {
+		outputs[o] = p
+	}
+	Loaded = append(Loaded, p)
+	return nil
+}
+
+// OutputFormats lists the output formats provided by the plugins.
+func OutputFormats() []string {
+	formats := []string{}
+	for o := range outputs {
+		formats = append(formats, o)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// RenderOutput renders the results using the plugin providing the format.
+func RenderOutput(format string, rl result.ResultList) (string, error) {
+	p, ok := outputs[format]
+	if !ok {
+		return "", fmt.Errorf("no plugin provides the output '%s'", format)
+	}
+	resp, err := p.Output(OutputRequest{Format: format, Results: rl})
+	if err != nil {
+		return "", fmt.Errorf("plugin '%s' failed to render the output: %w", p.Name, err)
+	}
+	return resp.Output, nil
+}
+
+// CloseAll stops the loaded plugins and unregisters their check types.
+func CloseAll() {
+	for _, p := range Loaded {
+		for _, ct := range p.CheckTypes {
+			delete(config.ChecksRegistry, config.CheckType(ct))
+		}
+		p.Close()
+	}
+	Loaded = nil
+	outputs = map[string]*Plugin{}
+}
@@ -0,0 +1,83 @@
+// Package plugin lets third parties ship check types & output formats as
+// separate binaries, discovered from a plugins directory, without recompiling
+// shipshape. Plugins are run as child processes using hashicorp's go-plugin,
+// with which shipshape communicates over gRPC, so they can be written in any
+// language; Go plugins can use Serve.
+package plugin
+
+import (
+	"encoding/json"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+)
+
+// ProtocolVersion is the version of the protocol spoken with the plugins,
+// exchanged during go-plugin's handshake; plugins reporting another version
+// are not loaded.
+const ProtocolVersion = 1
+
+// The magic cookie is set in the plugins' environment so that they can tell
+// they are being run by shipshape rather than directly.
+const (
+	MagicCookieKey   = "SHIPSHAPE_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "d3c1b6a0f7e2-shipshape"
+)
+
+// Description is returned by a plugin when it is loaded, listing what it
+// provides.
+type Description struct {
+	Name       string   `json:"name"`
+	CheckTypes []string `json:"check-types"`
+	Outputs    []string `json:"outputs"`
+}
+
+// CheckRequest is sent to a plugin to run one of its checks.
+type CheckRequest struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// The check's config as defined in the checks file.
+	Config     map[string]any `json:"config"`
+	ProjectDir string         `json:"project-dir"`
+	Target     string         `json:"target,omitempty"`
+}
+
+// CheckResponse is the outcome of a check run by a plugin; each breach is a
+// json breach as in the json output, including its breach-type.
+type CheckResponse struct {
+	Passes   []string          `json:"passes"`
+	Breaches []json.RawMessage `json:"breaches"`
+	Warnings []string          `json:"warnings"`
+}
+
+// OutputRequest is sent to a plugin to render the results in one of its
+// output formats.
+type OutputRequest struct {
+	Format  string            `json:"format"`
+	Results result.ResultList `json:"results"`
+}
+
+// OutputResponse is the rendered report.
+type OutputResponse struct {
+	Output string `json:"output"`
+}
+
+// Provider is implemented by Go plugins and served using Serve.
+type Provider interface {
+	Describe() Description
+	RunCheck(req CheckRequest) (CheckResponse, error)
+	Output(req OutputRequest) (OutputResponse, error)
+}
+
+// MarshalBreaches converts breaches for a CheckResponse.
+func MarshalBreaches(breaches ...result.Breach) ([]json.RawMessage, error) {
+	raw := []json.RawMessage{}
+	for _, b := range breaches {
+		b.SetCommonValues(b.GetCheckType(), b.GetCheckName(), b.GetSeverity())
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, data)
+	}
+	return raw, nil
+}
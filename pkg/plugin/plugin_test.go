@@ -0,0 +1,189 @@
+package plugin_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	. "github.com/salsadigitalauorg/shipshape/pkg/plugin"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// testProvider is served by the test binary when run as a plugin.
+type testProvider struct{}
+
+func (testProvider) Describe() Description {
+	return Description{
+		Name:       "test-plugin",
+		CheckTypes: []string{"test-plugin-check"},
+		Outputs:    []string{"test-plugin-output"},
+	}
+}
+
+func (testProvider) RunCheck(req CheckRequest) (CheckResponse, error) {
+	if req.Config["fail"] == "error" {
+		return CheckResponse{}, errors.New("unable to run")
+	}
+	if req.Config["fail"] == true {
+		breaches, err := MarshalBreaches(&result.KeyValueBreach{
+			Key: fmt.Sprint(req.Config["key"]), Value: req.ProjectDir})
+		return CheckResponse{Breaches: breaches}, err
+	}
+	return CheckResponse{Passes: []string{req.Name + " passed"}, Warnings: []string{"careful"}}, nil
+}
+
+func (testProvider) Output(req OutputRequest) (OutputResponse, error) {
+	return OutputResponse{Output: fmt.Sprintf("%s: %d checks\n", req.Format, req.Results.TotalChecks)}, nil
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv("SHIPSHAPE_TEST_PLUGIN") == "1" {
+		if err := Serve(testProvider{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// pluginsDir creates a directory with a plugin running the test binary.
+func pluginsDir(t *testing.T) string {
+	dir := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\nSHIPSHAPE_TEST_PLUGIN=1 exec '%s'\n", os.Args[0])
+	if err := os.WriteFile(filepath.Join(dir, "test-plugin"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Non-executable files are skipped.
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Plugins"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestServeNotRunByHost(t *testing.T) {
+	t.Setenv(MagicCookieKey, "")
+	assert.Equal(t, ErrNotRunByHost, Serve(testProvider{}))
+}
+
+func TestStartNotAPlugin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-plugin")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho hello\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Start(path)
+	assert.ErrorContains(t, err, "unable to start plugin '"+path+"'")
+}
+
+func TestLoadDir(t *testing.T) {
+	assert := assert.New(t)
+	defer CloseAll()
+
+	assert.Error(LoadDir(filepath.Join(t.TempDir(), "missing")))
+
+	dir := pluginsDir(t)
+	assert.NoError(LoadDir(dir))
+	assert.Len(Loaded, 1)
+	assert.Equal("test-plugin", Loaded[0].Name)
+	assert.Equal(filepath.Join(dir, "test-plugin"), Loaded[0].Path)
+	assert.Equal([]string{"test-plugin-output"}, OutputFormats())
+	_, ok := config.ChecksRegistry["test-plugin-check"]
+	assert.True(ok)
+
+	// The check types & outputs can only be provided once.
+	err := LoadDir(dir)
+	assert.ErrorContains(err, "check type 'test-plugin-check' provided by plugin 'test-plugin' is already registered")
+
+	rl := result.NewResultList(false)
+	rl.TotalChecks = 3
+	out, err := RenderOutput("test-plugin-output", rl)
+	assert.NoError(err)
+	assert.Equal("test-plugin-output: 3 checks\n", out)
+	_, err = RenderOutput("pdf", rl)
+	assert.EqualError(err, "no plugin provides the output 'pdf'")
+
+	CloseAll()
+	assert.Empty(Loaded)
+	assert.Empty(OutputFormats())
+	_, ok = config.ChecksRegistry["test-plugin-check"]
+	assert.False(ok)
+}
+
+func TestPluginCheck(t *testing.T) {
+	assert := assert.New(t)
+	defer CloseAll()
+	assert.NoError(LoadDir(pluginsDir(t)))
+
+	curProjectDir := config.ProjectDir
+	defer func() { config.ProjectDir = curProjectDir }()
+	config.ProjectDir = "/app"
+
+	cfg := config.Config{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+checks:
+  test-plugin-check:
+    - name: pass
+      severity: high
+    - name: fail
+      fail: true
+      key: foo
+    - name: error
+      fail: error
+`), &cfg))
+	checks := cfg.Checks["test-plugin-check"]
+	assert.Len(checks, 3)
+
+	c := checks[0].(*PluginCheck)
+	assert.Equal(config.HighSeverity, c.Severity)
+	assert.Equal(map[string]any{"name": "pass", "severity": "high"}, c.Config)
+	assert.False(c.RequiresData())
+	c.Init("test-plugin-check")
+	c.RunCheck()
+	assert.Equal(result.Pass, c.Result.Status)
+	assert.Equal([]string{"pass passed"}, c.Result.Passes)
+	assert.Equal([]string{"careful"}, c.Result.Warnings)
+
+	c = checks[1].(*PluginCheck)
+	c.Init("test-plugin-check")
+	c.RunCheck()
+	assert.Equal([]result.Breach{&result.KeyValueBreach{
+		BreachType: "key-value",
+		CheckType:  "test-plugin-check",
+		CheckName:  "fail",
+		Severity:   "normal",
+		Key:        "foo",
+		Value:      "/app",
+	}}, c.Result.Breaches)
+
+	c = checks[2].(*PluginCheck)
+	c.Init("test-plugin-check")
+	c.RunCheck()
+	assert.Equal([]result.Breach{&result.KeyValueBreach{
+		BreachType: "key-value",
+		CheckType:  "test-plugin-check",
+		CheckName:  "error",
+		Severity:   "normal",
+		KeyLabel:   "plugin",
+		Key:        "test-plugin",
+		ValueLabel: "error",
+		Value:      "unable to run",
+	}}, c.Result.Breaches)
+
+	// The config is merged key by key.
+	c = checks[1].(*PluginCheck)
+	assert.NoError(c.Merge(&PluginCheck{
+		CheckBase: config.CheckBase{Name: "fail"},
+		Config:    map[string]any{"key": "bar"},
+	}))
+	assert.Equal(map[string]any{"name": "fail", "fail": true, "key": "bar"}, c.Config)
+	out, err := yaml.Marshal(c)
+	assert.NoError(err)
+	assert.True(strings.Contains(string(out), "key: bar"))
+}
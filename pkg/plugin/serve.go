@@ -0,0 +1,25 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+// ErrNotRunByHost is returned by Serve when the plugin is run directly
+// instead of by shipshape.
+var ErrNotRunByHost = errors.New("this binary is a shipshape plugin and is not meant to be run directly")
+
+// Serve serves the provider over gRPC until shipshape stops the plugin.
+func Serve(p Provider) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return ErrNotRunByHost
+	}
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         goplugin.PluginSet{pluginName: &providerPlugin{provider: p}},
+		GRPCServer:      goplugin.DefaultGRPCServer,
+	})
+	return nil
+}
@@ -1,18 +1,23 @@
 package result
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"text/template"
 )
 
 // Breach provides a representation for different breach types.
 type Breach interface {
 	GetCheckName() string
 	GetCheckType() string
+	GetDocUrl() string
 	GetRemediation() *Remediation
+	GetRemediationHint() string
 	GetSeverity() string
 	GetType() BreachType
 	SetCommonValues(checkType string, checkName string, severity string)
+	SetDocs(docUrl string, remediationHint string)
 	SetRemediation(status RemediationStatus, msg string)
 	String() string
 }
@@ -35,14 +40,16 @@ const (
 //
 //	"file foo.ext not found": file is the ValueLabel, foo.ext is the Value
 type ValueBreach struct {
-	BreachType    `json:"breach-type"`
-	CheckType     string `json:"check-type"`
-	CheckName     string `json:"check-name"`
-	Severity      string `json:"severity"`
-	ValueLabel    string `json:"value-label,omitempty"`
-	Value         string `json:"value"`
-	ExpectedValue string `json:"expected-value,omitempty"`
-	Remediation   `json:"remediation,omitempty"`
+	BreachType      `json:"breach-type"`
+	CheckType       string `json:"check-type"`
+	CheckName       string `json:"check-name"`
+	Severity        string `json:"severity"`
+	ValueLabel      string `json:"value-label,omitempty"`
+	Value           string `json:"value"`
+	ExpectedValue   string `json:"expected-value,omitempty"`
+	DocUrl          string `json:"doc-url,omitempty"`
+	RemediationHint string `json:"remediation-hint,omitempty"`
+	Remediation     `json:"remediation,omitempty"`
 }
 
 func (b ValueBreach) String() string {
@@ -61,16 +68,18 @@ func (b ValueBreach) String() string {
 //	  - app could be the ValueLabel
 //	  - wordpress is the Value
 type KeyValueBreach struct {
-	BreachType    `json:"breach-type"`
-	CheckType     string `json:"check-type"`
-	CheckName     string `json:"check-name"`
-	Severity      string `json:"severity"`
-	KeyLabel      string `json:"key-label,omitempty"`
-	Key           string `json:"key,omitempty"`
-	ValueLabel    string `json:"value-label,omitempty"`
-	Value         string `json:"value"`
-	ExpectedValue string `json:"expected-value,omitempty"`
-	Remediation   `json:"remediation,omitempty"`
+	BreachType      `json:"breach-type"`
+	CheckType       string `json:"check-type"`
+	CheckName       string `json:"check-name"`
+	Severity        string `json:"severity"`
+	KeyLabel        string `json:"key-label,omitempty"`
+	Key             string `json:"key,omitempty"`
+	ValueLabel      string `json:"value-label,omitempty"`
+	Value           string `json:"value"`
+	ExpectedValue   string `json:"expected-value,omitempty"`
+	DocUrl          string `json:"doc-url,omitempty"`
+	RemediationHint string `json:"remediation-hint,omitempty"`
+	Remediation     `json:"remediation,omitempty"`
 }
 
 func (b KeyValueBreach) String() string {
@@ -89,15 +98,17 @@ func (b KeyValueBreach) String() string {
 //	  - permissions could be the ValueLabel
 //	  - [administer site configuration, import configuration] are the Values
 type KeyValuesBreach struct {
-	BreachType  `json:"breach-type"`
-	CheckType   string   `json:"check-type"`
-	CheckName   string   `json:"check-name"`
-	Severity    string   `json:"severity"`
-	KeyLabel    string   `json:"key-label,omitempty"`
-	Key         string   `json:"key,omitempty"`
-	ValueLabel  string   `json:"value-label,omitempty"`
-	Values      []string `json:"values"`
-	Remediation `json:"remediation,omitempty"`
+	BreachType      `json:"breach-type"`
+	CheckType       string   `json:"check-type"`
+	CheckName       string   `json:"check-name"`
+	Severity        string   `json:"severity"`
+	KeyLabel        string   `json:"key-label,omitempty"`
+	Key             string   `json:"key,omitempty"`
+	ValueLabel      string   `json:"value-label,omitempty"`
+	Values          []string `json:"values"`
+	DocUrl          string   `json:"doc-url,omitempty"`
+	RemediationHint string   `json:"remediation-hint,omitempty"`
+	Remediation     `json:"remediation,omitempty"`
 }
 
 func (b KeyValuesBreach) String() string {
@@ -108,6 +119,33 @@ func (b KeyValuesBreach) String() string {
 	return fmt.Sprintf("%s:\n        - %s", b.Key, strings.Join(b.Values, "\n        - "))
 }
 
+// UnmarshalBreach decodes a json breach into its concrete type based on its
+// breach-type.
+func UnmarshalBreach(data []byte) (Breach, error) {
+	var bt struct {
+		BreachType BreachType `json:"breach-type"`
+	}
+	if err := json.Unmarshal(data, &bt); err != nil {
+		return nil, err
+	}
+
+	var b Breach
+	switch bt.BreachType {
+	case BreachTypeValue:
+		b = &ValueBreach{}
+	case BreachTypeKeyValue:
+		b = &KeyValueBreach{}
+	case BreachTypeKeyValues:
+		b = &KeyValuesBreach{}
+	default:
+		return nil, fmt.Errorf("unknown breach type '%s'", bt.BreachType)
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
 func BreachGetKeyLabel(bIfc Breach) string {
 	if b, ok := bIfc.(*KeyValueBreach); ok {
 		return b.KeyLabel
@@ -161,3 +199,16 @@ func BreachGetExpectedValue(bIfc Breach) string {
 	}
 	return ""
 }
+
+// BreachTemplateFuncs exposes the breach getters to templates, e.g,
+// `{{ breachKey . }}`.
+var BreachTemplateFuncs = template.FuncMap{
+	"breachKeyLabel":      BreachGetKeyLabel,
+	"breachKey":           BreachGetKey,
+	"breachValueLabel":    BreachGetValueLabel,
+	"breachValue":         BreachGetValue,
+	"breachValues":        BreachGetValues,
+	"breachExpectedValue": BreachGetExpectedValue,
+	"breachDocUrl":        Breach.GetDocUrl,
+	"breachHint":          Breach.GetRemediationHint,
+}
@@ -109,10 +109,18 @@ func (b bogusBreach) GetCheckType() string {
 	return ""
 }
 
+func (b bogusBreach) GetDocUrl() string {
+	return ""
+}
+
 func (b bogusBreach) GetRemediation() *Remediation {
 	return &Remediation{}
 }
 
+func (b bogusBreach) GetRemediationHint() string {
+	return ""
+}
+
 func (b bogusBreach) GetSeverity() string {
 	return ""
 }
@@ -124,6 +132,8 @@ func (b bogusBreach) GetType() BreachType {
 func (b bogusBreach) SetCommonValues(checkType string, checkName string, severity string) {
 }
 
+func (b bogusBreach) SetDocs(docUrl string, remediationHint string) {}
+
 func (b bogusBreach) String() string {
 	return ""
 }
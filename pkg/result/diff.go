@@ -0,0 +1,54 @@
+package result
+
+import (
+	"fmt"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// BreachDiff is a breach which is compared between two result lists.
+type BreachDiff struct {
+	Check  string `json:"check"`
+	Target string `json:"target,omitempty"`
+	Breach Breach `json:"breach"`
+}
+
+// ResultListDiff lists the breaches found in a result list but not in the
+// previous one, the ones which have been resolved and the ones found in both.
+type ResultListDiff struct {
+	New        []BreachDiff `json:"new"`
+	Resolved   []BreachDiff `json:"resolved"`
+	Persisting []BreachDiff `json:"persisting"`
+}
+
+// DiffResultLists compares the breaches of two result lists; breaches are
+// matched by their check, target and value.
+func DiffResultLists(from ResultList, to ResultList) ResultListDiff {
+	fromBreaches := breachDiffs(from)
+	toBreaches := breachDiffs(to)
+	d := ResultListDiff{New: []BreachDiff{}, Resolved: []BreachDiff{}, Persisting: []BreachDiff{}}
+	for _, k := range utils.SortedKeys(toBreaches) {
+		if _, ok := fromBreaches[k]; ok {
+			d.Persisting = append(d.Persisting, toBreaches[k])
+		} else {
+			d.New = append(d.New, toBreaches[k])
+		}
+	}
+	for _, k := range utils.SortedKeys(fromBreaches) {
+		if _, ok := toBreaches[k]; !ok {
+			d.Resolved = append(d.Resolved, fromBreaches[k])
+		}
+	}
+	return d
+}
+
+func breachDiffs(rl ResultList) map[string]BreachDiff {
+	breaches := map[string]BreachDiff{}
+	for _, r := range rl.Results {
+		for _, b := range r.Breaches {
+			key := fmt.Sprintf("%s\x00%s\x00%s", r.Target, r.Name, b.String())
+			breaches[key] = BreachDiff{Check: r.Name, Target: r.Target, Breach: b}
+		}
+	}
+	return breaches
+}
@@ -0,0 +1,51 @@
+package result_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffResultLists(t *testing.T) {
+	assert := assert.New(t)
+
+	from := ResultList{Results: []Result{
+		{Name: "Illegal files", Breaches: []Breach{
+			&ValueBreach{Value: "adminer.php"},
+			&ValueBreach{Value: "info.php"},
+		}},
+		{Name: "Modules", Target: "prod", Breaches: []Breach{
+			&KeyValueBreach{KeyLabel: "module", Key: "devel", Value: "enabled"},
+		}},
+	}}
+	to := ResultList{Results: []Result{
+		{Name: "Illegal files", Breaches: []Breach{
+			&ValueBreach{Value: "info.php"},
+			&ValueBreach{Value: "bigdump.php"},
+		}},
+		// The same breach against another target is a new one.
+		{Name: "Modules", Target: "staging", Breaches: []Breach{
+			&KeyValueBreach{KeyLabel: "module", Key: "devel", Value: "enabled"},
+		}},
+	}}
+
+	d := DiffResultLists(from, to)
+	assert.Equal([]BreachDiff{
+		{Check: "Illegal files", Breach: &ValueBreach{Value: "bigdump.php"}},
+		{Check: "Modules", Target: "staging",
+			Breach: &KeyValueBreach{KeyLabel: "module", Key: "devel", Value: "enabled"}},
+	}, d.New)
+	assert.Equal([]BreachDiff{
+		{Check: "Illegal files", Breach: &ValueBreach{Value: "adminer.php"}},
+		{Check: "Modules", Target: "prod",
+			Breach: &KeyValueBreach{KeyLabel: "module", Key: "devel", Value: "enabled"}},
+	}, d.Resolved)
+	assert.Equal([]BreachDiff{
+		{Check: "Illegal files", Breach: &ValueBreach{Value: "info.php"}},
+	}, d.Persisting)
+
+	d = DiffResultLists(ResultList{}, ResultList{})
+	assert.Equal(ResultListDiff{New: []BreachDiff{}, Resolved: []BreachDiff{},
+		Persisting: []BreachDiff{}}, d)
+}
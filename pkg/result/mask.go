@@ -0,0 +1,77 @@
+package result
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaskValue masks a sensitive value, keeping a short prefix to help identify
+// it and a hash to tell values apart, e.g, sk_l****(sha256:014c0728).
+// The prefix is at most a quarter of the value, up to 4 characters.
+func MaskValue(v string) string {
+	if v == "" {
+		return v
+	}
+	runes := []rune(v)
+	prefix := len(runes) / 4
+	if prefix > 4 {
+		prefix = 4
+	}
+	sum := sha256.Sum256([]byte(v))
+	return fmt.Sprintf("%s****(sha256:%s)", string(runes[:prefix]), hex.EncodeToString(sum[:4]))
+}
+
+// MaskBreaches masks the values of the breaches, along with any occurrence
+// of them in the passes, remediation messages and diffs. It is meant to be
+// called after remediation, so that remediators have the full values.
+func (r *Result) MaskBreaches() {
+	values := []string{}
+	for _, bIfc := range r.Breaches {
+		switch b := bIfc.(type) {
+		case *ValueBreach:
+			values = append(values, b.Value, b.ExpectedValue)
+		case *KeyValueBreach:
+			values = append(values, b.Value, b.ExpectedValue)
+		case *KeyValuesBreach:
+			values = append(values, b.Values...)
+		}
+	}
+	// Replace the longest values first, in case a value contains another.
+	sort.SliceStable(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	oldnew := []string{}
+	for _, v := range values {
+		if v != "" {
+			oldnew = append(oldnew, v, MaskValue(v))
+		}
+	}
+	if len(oldnew) == 0 {
+		return
+	}
+	replacer := strings.NewReplacer(oldnew...)
+
+	for i, p := range r.Passes {
+		r.Passes[i] = replacer.Replace(p)
+	}
+	for _, bIfc := range r.Breaches {
+		switch b := bIfc.(type) {
+		case *ValueBreach:
+			b.Value = MaskValue(b.Value)
+			b.ExpectedValue = MaskValue(b.ExpectedValue)
+		case *KeyValueBreach:
+			b.Value = MaskValue(b.Value)
+			b.ExpectedValue = MaskValue(b.ExpectedValue)
+		case *KeyValuesBreach:
+			for i, v := range b.Values {
+				b.Values[i] = MaskValue(v)
+			}
+		}
+		remediation := bIfc.GetRemediation()
+		for i, msg := range remediation.Messages {
+			remediation.Messages[i] = replacer.Replace(msg)
+		}
+		remediation.Diff = replacer.Replace(remediation.Diff)
+	}
+}
@@ -0,0 +1,53 @@
+package result_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", MaskValue(""))
+	assert.Equal("****(sha256:2c26b46b)", MaskValue("foo"))
+	assert.Equal("ab****(sha256:9c56cc51)", MaskValue("abcdefgh"))
+	assert.Equal("sk_l****(sha256:014c0728)", MaskValue("sk_live_1234567890abcdef"))
+	// Same values are masked the same way.
+	assert.Equal(MaskValue("hunter22"), MaskValue("hunter22"))
+	assert.NotEqual(MaskValue("hunter22"), MaskValue("hunter23"))
+}
+
+func TestResultMaskBreaches(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := "sk_live_1234567890abcdef"
+	r := Result{
+		Passes: []string{"[env] 'PUBLIC' equals 'foo'", "token " + secret + " rotated"},
+		Breaches: []Breach{
+			&ValueBreach{
+				Value: secret,
+				Remediation: Remediation{
+					Messages: []string{"revoked " + secret},
+					Diff:     "-api_key: " + secret + "\n+api_key: ''\n",
+				},
+			},
+			&KeyValueBreach{Key: "api_key", Value: "hunter22", ExpectedValue: ""},
+			&KeyValuesBreach{Key: "tokens", Values: []string{"abcdefgh", secret}},
+		},
+	}
+	r.MaskBreaches()
+
+	masked := MaskValue(secret)
+	assert.Equal([]string{"[env] 'PUBLIC' equals 'foo'", "token " + masked + " rotated"}, r.Passes)
+	assert.Equal(&ValueBreach{
+		Value: masked,
+		Remediation: Remediation{
+			Messages: []string{"revoked " + masked},
+			Diff:     "-api_key: " + masked + "\n+api_key: ''\n",
+		},
+	}, r.Breaches[0])
+	assert.Equal(&KeyValueBreach{Key: "api_key", Value: MaskValue("hunter22")}, r.Breaches[1])
+	assert.Equal(&KeyValuesBreach{Key: "tokens", Values: []string{MaskValue("abcdefgh"), masked}}, r.Breaches[2])
+}
@@ -0,0 +1,44 @@
+package result
+
+// ReasonCode is a stable, machine-readable reason for the outcome of a run,
+// for wrapper scripts to act upon.
+type ReasonCode string
+
+const (
+	// ReasonOk is set when there is no other reason.
+	ReasonOk ReasonCode = "OK"
+	// ReasonConfigError is set when the config could not be loaded, in which
+	// case no check is run.
+	ReasonConfigError ReasonCode = "CONFIG_ERROR"
+	// ReasonFactError is set when the data of a check could not be fetched,
+	// e.g, a command failed to run.
+	ReasonFactError ReasonCode = "FACT_ERROR"
+	// ReasonBreachesHigh is set when breaches at or above the fail severity
+	// were found.
+	ReasonBreachesHigh ReasonCode = "BREACHES_HIGH"
+	// ReasonRemediationFailed is set when the remediation of some breaches
+	// failed.
+	ReasonRemediationFailed ReasonCode = "REMEDIATION_FAILED"
+)
+
+// SetReasonCodes determines the reasons for the outcome of the run from its
+// results; the breaches must have been classified and the remediation totals
+// counted beforehand.
+func (rl *ResultList) SetReasonCodes() {
+	rl.ReasonCodes = []ReasonCode{}
+	for _, r := range rl.Results {
+		if r.DataError {
+			rl.ReasonCodes = append(rl.ReasonCodes, ReasonFactError)
+			break
+		}
+	}
+	if rl.FailingBreaches > 0 {
+		rl.ReasonCodes = append(rl.ReasonCodes, ReasonBreachesHigh)
+	}
+	if rl.RemediationTotals["failed"] > 0 || rl.RemediationTotals["partial"] > 0 {
+		rl.ReasonCodes = append(rl.ReasonCodes, ReasonRemediationFailed)
+	}
+	if len(rl.ReasonCodes) == 0 {
+		rl.ReasonCodes = append(rl.ReasonCodes, ReasonOk)
+	}
+}
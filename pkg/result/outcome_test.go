@@ -0,0 +1,33 @@
+package result_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultListSetReasonCodes(t *testing.T) {
+	assert := assert.New(t)
+
+	rl := NewResultList(false)
+	rl.AddResult(Result{Name: "a", Status: Pass})
+	rl.SetReasonCodes()
+	assert.Equal([]ReasonCode{ReasonOk}, rl.ReasonCodes)
+
+	// Informational breaches do not affect the outcome.
+	rl.AddResult(Result{Name: "b", Status: Pass, Breaches: []Breach{&ValueBreach{Value: "x"}}})
+	rl.InformationalBreaches = 1
+	rl.SetReasonCodes()
+	assert.Equal([]ReasonCode{ReasonOk}, rl.ReasonCodes)
+
+	rl.FailingBreaches = 1
+	rl.SetReasonCodes()
+	assert.Equal([]ReasonCode{ReasonBreachesHigh}, rl.ReasonCodes)
+
+	rl.AddResult(Result{Name: "c", Status: Fail, DataError: true})
+	rl.AddResult(Result{Name: "d", Status: Fail, DataError: true})
+	rl.RemediationTotals = map[string]uint32{"failed": 0, "partial": 1}
+	rl.SetReasonCodes()
+	assert.Equal([]ReasonCode{ReasonFactError, ReasonBreachesHigh, ReasonRemediationFailed}, rl.ReasonCodes)
+}
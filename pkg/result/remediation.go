@@ -12,4 +12,6 @@ const (
 type Remediation struct {
 	Status   RemediationStatus `json:",omitempty"`
 	Messages []string          `json:",omitempty"`
+	// Unified diff of the changes made to files by the remediation.
+	Diff string `json:",omitempty"`
 }
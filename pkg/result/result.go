@@ -1,6 +1,7 @@
 package result
 
 import (
+	"encoding/json"
 	"sort"
 )
 
@@ -9,18 +10,78 @@ type Status string
 const (
 	Pass Status = "Pass"
 	Fail Status = "Fail"
+	// Skipped is the status of the checks which were not run, e.g, in
+	// offline mode; the reason is in the result's SkipReason.
+	Skipped Status = "Skipped"
+)
+
+// SkipReason identifies why a check was not run.
+type SkipReason string
+
+const (
+	// The check is disabled in the config.
+	SkipReasonDisabled SkipReason = "config-disabled"
+	// The check's when condition is not met.
+	SkipReasonCondition SkipReason = "when-clause-false"
+	// The check's when condition on the project's platform, stack or hosting
+	// is not met, e.g, platform==drupal on a WordPress site.
+	SkipReasonPlatform SkipReason = "unsupported-platform"
+	// A dependency of the check did not pass.
+	SkipReasonDependency SkipReason = "missing-dependency"
+	// The check requires network access in offline mode.
+	SkipReasonOffline SkipReason = "offline"
+	// The run's time budget was reached.
+	SkipReasonBudget SkipReason = "time-budget"
 )
 
 // Result provides the structure for a Check's outcome.
 type Result struct {
-	Name              string            `json:"name"`
-	Severity          string            `json:"severity"`
-	CheckType         string            `json:"check-type"`
-	Passes            []string          `json:"passes"`
-	Breaches          []Breach          `json:"breaches"`
-	Warnings          []string          `json:"warnings"`
-	Status            Status            `json:"status"`
+	Name      string `json:"name"`
+	Severity  string `json:"severity"`
+	CheckType string `json:"check-type"`
+	Target    string `json:"target,omitempty"`
+	// Compliance framework controls the check maps to.
+	Controls []string `json:"controls,omitempty"`
+	// Labels of the check, e.g, [security, pci].
+	Tags []string `json:"tags,omitempty"`
+	// What the check verifies & why its policy exists.
+	Description string   `json:"description,omitempty"`
+	Rationale   string   `json:"rationale,omitempty"`
+	Passes      []string `json:"passes"`
+	Breaches    []Breach `json:"breaches"`
+	Warnings    []string `json:"warnings"`
+	Status      Status   `json:"status"`
+	// Why the check was not run, the details being in the warnings.
+	SkipReason        SkipReason        `json:"skip-reason,omitempty"`
 	RemediationStatus RemediationStatus `json:"remediation-status"`
+	// Whether the check's data could not be fetched, e.g, a command failed
+	// to run.
+	DataError bool `json:"data-error,omitempty"`
+	// Time taken to process the check, in seconds.
+	Duration float64 `json:"duration"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the breaches into their
+// concrete types.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	type resultAlias Result
+	aux := struct {
+		*resultAlias
+		Breaches []json.RawMessage `json:"breaches"`
+	}{resultAlias: (*resultAlias)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Breaches = nil
+	for _, raw := range aux.Breaches {
+		b, err := UnmarshalBreach(raw)
+		if err != nil {
+			return err
+		}
+		r.Breaches = append(r.Breaches, b)
+	}
+	return nil
 }
 
 // Sort reorders the Passes & Failures in order to get consistent output.
@@ -1,6 +1,7 @@
 package result_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	. "github.com/salsadigitalauorg/shipshape/pkg/result"
@@ -280,3 +281,25 @@ func TestResultDetermineResultStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestResultUnmarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	r := Result{
+		Name:   "a",
+		Status: Fail,
+		Breaches: []Breach{
+			&ValueBreach{BreachType: BreachTypeValue, Value: "foo"},
+			&KeyValueBreach{BreachType: BreachTypeKeyValue, Key: "k", Value: "v"},
+			&KeyValuesBreach{BreachType: BreachTypeKeyValues, Key: "k", Values: []string{"v1", "v2"}},
+		},
+	}
+	data, err := json.Marshal(r)
+	assert.NoError(err)
+	var unmarshalled Result
+	assert.NoError(json.Unmarshal(data, &unmarshalled))
+	assert.Equal(r, unmarshalled)
+
+	err = json.Unmarshal([]byte(`{"breaches": [{"breach-type": "foo"}]}`), &unmarshalled)
+	assert.EqualError(err, "unknown breach type 'foo'")
+}
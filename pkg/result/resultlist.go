@@ -9,14 +9,20 @@ import (
 // ResultList is a wrapper around a list of results, providing some useful
 // methods to manipulate and use it.
 type ResultList struct {
+	SchemaVersion         string            `json:"schema-version"`
 	RemediationPerformed  bool              `json:"remediation-performed"`
 	TotalChecks           uint32            `json:"total-checks"`
 	TotalBreaches         uint32            `json:"total-breaches"`
+	FailingBreaches       uint32            `json:"failing-breaches"`
+	InformationalBreaches uint32            `json:"informational-breaches"`
 	RemediationTotals     map[string]uint32 `json:"remediation-totals"`
 	CheckCountByType      map[string]int    `json:"check-count-by-type"`
 	BreachCountByType     map[string]int    `json:"breach-count-by-type"`
 	BreachCountBySeverity map[string]int    `json:"breach-count-by-severity"`
-	Results               []Result          `json:"results"`
+	BreachCountByTarget   map[string]int    `json:"breach-count-by-target,omitempty"`
+	// Machine-readable reasons for the outcome of the run.
+	ReasonCodes []ReasonCode `json:"reason-codes"`
+	Results     []Result     `json:"results"`
 }
 
 // Use locks to make map mutations concurrency-safe.
@@ -24,11 +30,13 @@ var lock = sync.RWMutex{}
 
 func NewResultList(remediate bool) ResultList {
 	rl := ResultList{
+		SchemaVersion:         SchemaVersion,
 		RemediationPerformed:  remediate,
 		Results:               []Result{},
 		CheckCountByType:      map[string]int{},
 		BreachCountByType:     map[string]int{},
 		BreachCountBySeverity: map[string]int{},
+		BreachCountByTarget:   map[string]int{},
 	}
 	return rl
 }
@@ -52,6 +60,42 @@ func (rl *ResultList) AddResult(r Result) {
 	atomic.AddUint32(&rl.TotalBreaches, uint32(breachesIncr))
 	rl.BreachCountByType[r.CheckType] = rl.BreachCountByType[r.CheckType] + breachesIncr
 	rl.BreachCountBySeverity[r.Severity] = rl.BreachCountBySeverity[r.Severity] + breachesIncr
+	if r.Target != "" {
+		if rl.BreachCountByTarget == nil {
+			rl.BreachCountByTarget = map[string]int{}
+		}
+		rl.BreachCountByTarget[r.Target] = rl.BreachCountByTarget[r.Target] + breachesIncr
+	}
+}
+
+// Merge adds the checks & results of another list, e.g, from a run against
+// another project, recalculating the totals and the reasons for the outcome.
+func (rl *ResultList) Merge(other ResultList) {
+	if rl.SchemaVersion == "" {
+		rl.SchemaVersion = SchemaVersion
+	}
+	if rl.CheckCountByType == nil {
+		rl.CheckCountByType = map[string]int{}
+	}
+	if rl.BreachCountByType == nil {
+		rl.BreachCountByType = map[string]int{}
+	}
+	if rl.BreachCountBySeverity == nil {
+		rl.BreachCountBySeverity = map[string]int{}
+	}
+
+	rl.RemediationPerformed = rl.RemediationPerformed || other.RemediationPerformed
+	for ct, count := range other.CheckCountByType {
+		rl.IncrChecks(ct, count)
+	}
+	for _, r := range other.Results {
+		rl.AddResult(r)
+	}
+	rl.FailingBreaches += other.FailingBreaches
+	rl.InformationalBreaches += other.InformationalBreaches
+	rl.Sort()
+	rl.RemediationTotalsCount()
+	rl.SetReasonCodes()
 }
 
 // Status calculates and returns the overall result of all check results.
@@ -121,6 +165,28 @@ func (rl *ResultList) GetBreachesByCheckName(cn string) []Breach {
 	return breaches
 }
 
+// GetBreachesByCheckNameAndTarget fetches the list of failures by check name
+// for a specific target.
+func (rl *ResultList) GetBreachesByCheckNameAndTarget(cn string, target string) []Breach {
+	var breaches []Breach
+	for _, r := range rl.Results {
+		if r.Name == cn && r.Target == target {
+			breaches = append(breaches, r.Breaches...)
+		}
+	}
+	return breaches
+}
+
+// HasTargets determines whether any of the results was run against a target.
+func (rl *ResultList) HasTargets() bool {
+	for _, r := range rl.Results {
+		if r.Target != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBreachesBySeverity fetches the list of failures by severity.
 func (rl *ResultList) GetBreachesBySeverity(s string) []Breach {
 	var breaches []Breach
@@ -133,9 +199,16 @@ func (rl *ResultList) GetBreachesBySeverity(s string) []Breach {
 	return breaches
 }
 
-// Sort reorders the results by name.
+// Sort reorders the results by target, name, then check type; results which
+// are otherwise equal keep their order.
 func (rl *ResultList) Sort() {
-	sort.Slice(rl.Results, func(i int, j int) bool {
-		return rl.Results[i].Name < rl.Results[j].Name
+	sort.SliceStable(rl.Results, func(i int, j int) bool {
+		if rl.Results[i].Target != rl.Results[j].Target {
+			return rl.Results[i].Target < rl.Results[j].Target
+		}
+		if rl.Results[i].Name != rl.Results[j].Name {
+			return rl.Results[i].Name < rl.Results[j].Name
+		}
+		return rl.Results[i].CheckType < rl.Results[j].CheckType
 	})
 }
@@ -14,6 +14,7 @@ func TestNewResultList(t *testing.T) {
 
 	t.Run("emptyInit", func(t *testing.T) {
 		rl := NewResultList(false)
+		assert.Equal(SchemaVersion, rl.SchemaVersion)
 		assert.Equal(false, rl.RemediationPerformed)
 		assert.Equal([]Result{}, rl.Results)
 		assert.Equal(map[string]int{}, rl.CheckCountByType)
@@ -141,6 +142,16 @@ func TestResultListAddResult(t *testing.T) {
 	assert.Equal(105, rl.BreachCountByType[string(testCheck2Type)])
 	assert.Equal(105, rl.BreachCountBySeverity["high"])
 	assert.Equal(105, rl.BreachCountBySeverity["critical"])
+	assert.Empty(rl.BreachCountByTarget)
+
+	rl.AddResult(Result{
+		Severity:  "high",
+		CheckType: string(testCheckType),
+		Target:    "web",
+		Breaches:  []Breach{&ValueBreach{Value: "fail8"}},
+	})
+	assert.Equal(211, int(rl.TotalBreaches))
+	assert.Equal(map[string]int{"web": 1}, rl.BreachCountByTarget)
 }
 
 func TestResultListStatus(t *testing.T) {
@@ -339,6 +350,33 @@ func TestResultListGetBreachesByCheckName(t *testing.T) {
 		rl.GetBreachesByCheckName("check2"))
 }
 
+func TestResultListGetBreachesByCheckNameAndTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	rl := ResultList{
+		Results: []Result{
+			{
+				Name:     "check1",
+				Target:   "web",
+				Breaches: []Breach{&ValueBreach{Value: "failure1"}},
+			},
+			{
+				Name:     "check1",
+				Target:   "cli",
+				Breaches: []Breach{&ValueBreach{Value: "failure2"}},
+			},
+		},
+	}
+	assert.True(rl.HasTargets())
+	assert.EqualValues(
+		[]Breach{&ValueBreach{Value: "failure2"}},
+		rl.GetBreachesByCheckNameAndTarget("check1", "cli"))
+	assert.Empty(rl.GetBreachesByCheckNameAndTarget("check1", ""))
+
+	rl = ResultList{Results: []Result{{Name: "check1"}}}
+	assert.False(rl.HasTargets())
+}
+
 func TestResultListGetBreachesBySeverity(t *testing.T) {
 	assert := assert.New(t)
 
@@ -395,3 +433,83 @@ func TestResultListSort(t *testing.T) {
 		{Name: "zcheck"},
 	}, rl.Results)
 }
+
+func TestResultListSortByTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	rl := ResultList{
+		Results: []Result{
+			{Name: "acheck", Target: "web"},
+			{Name: "zcheck", Target: "cli"},
+			{Name: "bcheck"},
+			{Name: "acheck", Target: "cli"},
+		},
+	}
+	rl.Sort()
+	assert.EqualValues([]Result{
+		{Name: "bcheck"},
+		{Name: "acheck", Target: "cli"},
+		{Name: "zcheck", Target: "cli"},
+		{Name: "acheck", Target: "web"},
+	}, rl.Results)
+}
+
+func TestResultListSortByCheckType(t *testing.T) {
+	assert := assert.New(t)
+
+	rl := ResultList{
+		Results: []Result{
+			{Name: "acheck", CheckType: "yaml", Status: Fail},
+			{Name: "acheck", CheckType: "file", Status: Fail},
+			{Name: "acheck", CheckType: "file", Status: Pass},
+		},
+	}
+	rl.Sort()
+	assert.EqualValues([]Result{
+		{Name: "acheck", CheckType: "file", Status: Fail},
+		{Name: "acheck", CheckType: "file", Status: Pass},
+		{Name: "acheck", CheckType: "yaml", Status: Fail},
+	}, rl.Results)
+}
+
+func TestResultListMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	rl := NewResultList(false)
+	rl.IncrChecks("file", 1)
+	rl.AddResult(Result{Name: "zcheck", CheckType: "file", Severity: "high",
+		Status: Fail, Breaches: []Breach{&ValueBreach{Value: "foo"}}})
+	rl.FailingBreaches = 1
+
+	other := NewResultList(true)
+	other.IncrChecks("file", 1)
+	other.IncrChecks("yaml", 1)
+	other.AddResult(Result{Name: "acheck", CheckType: "yaml", Severity: "low",
+		Status: Fail, Breaches: []Breach{&ValueBreach{Value: "bar"}}})
+	other.AddResult(Result{Name: "bcheck", CheckType: "file", Severity: "normal", Status: Pass})
+	other.InformationalBreaches = 1
+
+	rl.Merge(other)
+	assert.True(rl.RemediationPerformed)
+	assert.Equal(uint32(3), rl.TotalChecks)
+	assert.Equal(uint32(2), rl.TotalBreaches)
+	assert.Equal(uint32(1), rl.FailingBreaches)
+	assert.Equal(uint32(1), rl.InformationalBreaches)
+	assert.Equal(map[string]int{"file": 2, "yaml": 1}, rl.CheckCountByType)
+	assert.Equal(map[string]int{"file": 1, "yaml": 1}, rl.BreachCountByType)
+	assert.Equal(map[string]int{"high": 1, "low": 1, "normal": 0}, rl.BreachCountBySeverity)
+	assert.Equal([]string{"acheck", "bcheck", "zcheck"},
+		[]string{rl.Results[0].Name, rl.Results[1].Name, rl.Results[2].Name})
+	assert.Equal(uint32(0), rl.RemediationTotals["successful"])
+	assert.Equal([]ReasonCode{ReasonBreachesHigh}, rl.ReasonCodes)
+	assert.Equal(SchemaVersion, rl.SchemaVersion)
+
+	t.Run("emptyList", func(t *testing.T) {
+		var rl ResultList
+		rl.Merge(other)
+		assert.Equal(uint32(2), rl.TotalChecks)
+		assert.Len(rl.Results, 2)
+		assert.Equal([]ReasonCode{ReasonOk}, rl.ReasonCodes)
+		assert.Equal(SchemaVersion, rl.SchemaVersion)
+	})
+}
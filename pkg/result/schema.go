@@ -0,0 +1,16 @@
+package result
+
+import (
+	_ "embed"
+)
+
+// SchemaVersion is the version of the ResultList json output, as major.minor.
+// The minor version is bumped when fields are added; the major version is
+// only bumped when fields are removed or changed, which would break existing
+// consumers.
+const SchemaVersion = "1.11"
+
+// Schema is the JSON schema for the ResultList json output.
+//
+//go:embed schema.json
+var Schema []byte
@@ -0,0 +1,78 @@
+package result_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaDef struct {
+	Properties map[string]struct {
+		Pattern    string                     `json:"pattern"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	} `json:"properties"`
+	Defs map[string]schemaDef `json:"$defs"`
+}
+
+// assertKeysInSchema verifies that all the keys of the json object are
+// declared in the schema definition.
+func assertKeysInSchema(t *testing.T, def schemaDef, obj map[string]json.RawMessage) {
+	t.Helper()
+	for k := range obj {
+		assert.Contains(t, def.Properties, k)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	schema := schemaDef{}
+	assert.NoError(json.Unmarshal(Schema, &schema))
+	assert.Regexp(regexp.MustCompile(schema.Properties["schema-version"].Pattern), SchemaVersion)
+
+	// Ensure all the fields of the output are documented in the schema.
+	rl := NewResultList(true)
+	rl.AddResult(Result{
+		Name:      "test",
+		CheckType: "test-check",
+		Tags:      []string{"security"},
+		Breaches: []Breach{
+			&ValueBreach{BreachType: BreachTypeValue, ValueLabel: "l", Value: "v", ExpectedValue: "e"},
+			&KeyValueBreach{BreachType: BreachTypeKeyValue, KeyLabel: "kl", Key: "k", ValueLabel: "l", Value: "v", ExpectedValue: "e"},
+			&KeyValuesBreach{BreachType: BreachTypeKeyValues, KeyLabel: "kl", Key: "k", ValueLabel: "l", Values: []string{"v"}},
+		},
+		Passes:     []string{"pass"},
+		Warnings:   []string{"warning"},
+		SkipReason: SkipReasonCondition,
+		DataError:  true,
+	})
+	for _, b := range rl.Results[0].Breaches {
+		b.SetRemediation(RemediationStatusSuccess, "fixed")
+	}
+	rl.RemediationTotalsCount()
+	rl.SetReasonCodes()
+
+	data, _ := json.Marshal(rl)
+	out := map[string]json.RawMessage{}
+	assert.NoError(json.Unmarshal(data, &out))
+	assertKeysInSchema(t, schema, out)
+
+	results := []map[string]json.RawMessage{}
+	assert.NoError(json.Unmarshal(out["results"], &results))
+	assertKeysInSchema(t, schema.Defs["result"], results[0])
+
+	breaches := []map[string]json.RawMessage{}
+	assert.NoError(json.Unmarshal(results[0]["breaches"], &breaches))
+	for _, b := range breaches {
+		assertKeysInSchema(t, schema.Defs["breach"], b)
+
+		remediation := map[string]json.RawMessage{}
+		assert.NoError(json.Unmarshal(b["remediation"], &remediation))
+		for k := range remediation {
+			assert.Contains(schema.Defs["breach"].Properties["remediation"].Properties, k)
+		}
+	}
+}
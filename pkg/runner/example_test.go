@@ -0,0 +1,93 @@
+package runner_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/runner"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// HostnameCheck is a custom check verifying the hostname configured.
+type HostnameCheck struct {
+	config.CheckBase `yaml:",inline"`
+	Hostname         string   `yaml:"hostname"`
+	Allowed          []string `yaml:"allowed"`
+}
+
+// RequiresData is false since the check only uses its config.
+func (c *HostnameCheck) RequiresData() bool { return false }
+
+// Merge implementation for the hostname check.
+func (c *HostnameCheck) Merge(mergeCheck config.Check) error {
+	hostnameMergeCheck := mergeCheck.(*HostnameCheck)
+	if err := c.CheckBase.Merge(&hostnameMergeCheck.CheckBase); err != nil {
+		return err
+	}
+	utils.MergeString(&c.Hostname, hostnameMergeCheck.Hostname)
+	utils.MergeStringSlice(&c.Allowed, hostnameMergeCheck.Allowed)
+	return nil
+}
+
+// RunCheck verifies the hostname is allowed.
+func (c *HostnameCheck) RunCheck() {
+	if !utils.StringSliceContains(c.Allowed, c.Hostname) {
+		c.AddBreach(&result.ValueBreach{
+			ValueLabel: "hostname not allowed",
+			Value:      c.Hostname})
+		return
+	}
+	c.AddPass("hostname is allowed")
+	c.Result.Status = result.Pass
+}
+
+func ExampleRegisterCheck() {
+	err := runner.RegisterCheck("hostname", func() config.Check { return &HostnameCheck{} })
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dir, _ := os.MkdirTemp("", "shipshape")
+	defer os.RemoveAll(dir)
+	cfg := filepath.Join(dir, "shipshape.yml")
+	os.WriteFile(cfg, []byte(`
+checks:
+  hostname:
+    - name: Production hostname
+      hostname: www.example.com
+      allowed: [example.com]
+`), 0644)
+
+	rl, err := runner.Run(runner.Options{ProjectDir: dir, ConfigFiles: []string{cfg}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(rl.Status())
+	for _, r := range rl.Results {
+		for _, b := range r.Breaches {
+			fmt.Printf("%s: %s\n", r.Name, b)
+		}
+	}
+	// Output:
+	// Fail
+	// Production hostname: [hostname not allowed] www.example.com
+}
+
+func ExampleMerge() {
+	rl1 := result.NewResultList(false)
+	rl1.IncrChecks("file", 1)
+	rl1.AddResult(result.Result{Name: "Illegal files", CheckType: "file", Status: result.Pass})
+
+	rl2 := result.NewResultList(false)
+	rl2.IncrChecks("yaml", 1)
+	rl2.AddResult(result.Result{Name: "Modules", CheckType: "yaml", Status: result.Fail,
+		Breaches: []result.Breach{&result.ValueBreach{Value: "devel enabled"}}})
+
+	rl := runner.Merge(rl1, rl2)
+	fmt.Println(rl.TotalChecks, rl.TotalBreaches, rl.Status())
+	// Output: 2 1 Fail
+}
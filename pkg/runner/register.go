@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+// RegisterCheck makes a custom check type available in the config, e.g, for
+// checks specific to an in-house distribution. The factory returns a new,
+// empty, check into which each check's config is decoded; checks usually
+// embed config.CheckBase, only implementing RunCheck & Merge.
+func RegisterCheck(ct config.CheckType, factory func() config.Check) error {
+	if ct == "" {
+		return errors.New("check type cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("no factory provided for check type '%s'", ct)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := config.ChecksRegistry[ct]; ok {
+		return fmt.Errorf("check type '%s' is already registered", ct)
+	}
+	config.ChecksRegistry[ct] = factory
+	return nil
+}
+
+// CheckTypes returns the check types available, sorted by name.
+func CheckTypes() []config.CheckType {
+	mu.Lock()
+	defer mu.Unlock()
+	types := []config.CheckType{}
+	for ct := range config.ChecksRegistry {
+		types = append(types, ct)
+	}
+	sort.Slice(types, func(i int, j int) bool { return types[i] < types[j] })
+	return types
+}
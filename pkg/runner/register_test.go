@@ -0,0 +1,36 @@
+package runner_test
+
+import (
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	. "github.com/salsadigitalauorg/shipshape/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCheck struct {
+	config.CheckBase `yaml:",inline"`
+}
+
+func TestRegisterCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	defer delete(config.ChecksRegistry, "test-register")
+	factory := func() config.Check { return &testCheck{} }
+	assert.NoError(RegisterCheck("test-register", factory))
+	assert.Contains(CheckTypes(), config.CheckType("test-register"))
+
+	assert.EqualError(RegisterCheck("test-register", factory),
+		"check type 'test-register' is already registered")
+	assert.EqualError(RegisterCheck("file", factory),
+		"check type 'file' is already registered")
+	assert.EqualError(RegisterCheck("", factory), "check type cannot be empty")
+	assert.EqualError(RegisterCheck("test-nil", nil),
+		"no factory provided for check type 'test-nil'")
+}
+
+func TestCheckTypes(t *testing.T) {
+	types := CheckTypes()
+	assert.Contains(t, types, config.CheckType("file"))
+	assert.IsIncreasing(t, types)
+}
@@ -0,0 +1,117 @@
+// Package runner provides the API for embedding shipshape in other Go
+// programs: loading the config, running the checks & merging their results.
+//
+// The checks available are the ones whose packages are imported, e.g,
+//
+//	import _ "github.com/salsadigitalauorg/shipshape/pkg/checks/file"
+//
+// Custom checks can be registered using RegisterCheck.
+//
+// Since the run state is global, runs are performed one at a time.
+//
+// This package, along with the config.Check interface, config.CheckBase and
+// the result package, is the public API of shipshape; it follows semantic
+// versioning, i.e, breaking changes to it are only made in a new major
+// version.
+package runner
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Options configures a run; they correspond to the command-line flags.
+type Options struct {
+	// Directory the checks are run against; defaults to the config's
+	// project-dir, then to the current working directory.
+	ProjectDir string
+	// Config files, urls or presets, merged in order.
+	ConfigFiles []string
+	// Check types to run; all checks are run if empty.
+	CheckTypes []string
+	// Skip the checks requiring a database.
+	ExcludeDb bool
+	// Remediate the breaches of supported checks.
+	Remediate bool
+	// Severity from which breaches fail their check; overrides the config's
+	// fail-severity when set.
+	FailSeverity config.Severity
+	// File recording when breaches were first seen, used to escalate the
+	// unresolved ones.
+	HistoryFile string
+	// Level of the logs; defaults to warn.
+	LogLevel         string
+	LagoonApiBaseUrl string
+	LagoonApiToken   string
+}
+
+var mu sync.Mutex
+
+// LoadConfig reads, merges & validates the config files, returning the
+// resulting config with the checks to be run.
+func LoadConfig(opts Options) (config.Config, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if err := initRun(opts); err != nil {
+		return config.Config{}, err
+	}
+	return shipshape.RunConfig, nil
+}
+
+// Run loads the config & runs the checks, returning their results.
+func Run(opts Options) (result.ResultList, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if err := initRun(opts); err != nil {
+		return result.ResultList{}, err
+	}
+
+	shipshape.RunHistory = nil
+	if opts.HistoryFile != "" {
+		h, err := shipshape.ReadHistory(opts.HistoryFile)
+		if err != nil {
+			return result.ResultList{}, err
+		}
+		shipshape.RunHistory = h
+	}
+
+	shipshape.RunChecks()
+
+	if shipshape.RunHistory != nil {
+		shipshape.RunHistory.Update(shipshape.RunResultList)
+		if err := shipshape.RunHistory.Write(opts.HistoryFile); err != nil {
+			return shipshape.RunResultList, err
+		}
+	}
+	return shipshape.RunResultList, nil
+}
+
+// Merge combines the results of multiple runs into a single list.
+func Merge(lists ...result.ResultList) result.ResultList {
+	rl := result.NewResultList(false)
+	for _, l := range lists {
+		rl.Merge(l)
+	}
+	return rl
+}
+
+func initRun(opts Options) error {
+	if opts.FailSeverity != "" && !opts.FailSeverity.IsValid() {
+		return fmt.Errorf("invalid fail severity '%s'", opts.FailSeverity)
+	}
+	if opts.LogLevel != "" {
+		if _, err := log.ParseLevel(opts.LogLevel); err != nil {
+			return err
+		}
+	}
+	shipshape.FailSeverity = opts.FailSeverity
+	return shipshape.Init(opts.ProjectDir, opts.ConfigFiles, opts.CheckTypes,
+		opts.ExcludeDb, opts.Remediate, opts.LogLevel, opts.LagoonApiBaseUrl,
+		opts.LagoonApiToken)
+}
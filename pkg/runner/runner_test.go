@@ -0,0 +1,88 @@
+package runner_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/file"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeProject(t *testing.T, files ...string) (string, string) {
+	dir := t.TempDir()
+	for _, f := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), []byte(""), 0644))
+	}
+	cfg := filepath.Join(dir, "shipshape.yml")
+	assert.NoError(t, os.WriteFile(cfg, []byte(`
+checks:
+  file:
+    - name: Illegal files
+      path: .
+      disallowed-pattern: '^adminer\.php$'
+      severity: high
+`), 0644))
+	return dir, cfg
+}
+
+func TestLoadConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cfg := writeProject(t)
+	c, err := LoadConfig(Options{ProjectDir: dir, ConfigFiles: []string{cfg}})
+	assert.NoError(err)
+	assert.Equal(dir, c.ProjectDir)
+	assert.Len(c.Checks["file"], 1)
+
+	_, err = LoadConfig(Options{ConfigFiles: []string{cfg}, FailSeverity: "urgent"})
+	assert.EqualError(err, "invalid fail severity 'urgent'")
+
+	_, err = LoadConfig(Options{ConfigFiles: []string{cfg}, LogLevel: "loud"})
+	assert.Error(err)
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cfg := writeProject(t, "adminer.php")
+	rl, err := Run(Options{ProjectDir: dir, ConfigFiles: []string{cfg}})
+	assert.NoError(err)
+	assert.Equal(uint32(1), rl.TotalChecks)
+	assert.Equal(uint32(1), rl.FailingBreaches)
+	assert.Equal(result.Fail, rl.Status())
+
+	rl, err = Run(Options{ProjectDir: dir, ConfigFiles: []string{cfg},
+		FailSeverity: config.CriticalSeverity})
+	assert.NoError(err)
+	assert.Equal(uint32(1), rl.InformationalBreaches)
+	assert.Equal(result.Pass, rl.Status())
+
+	t.Run("history", func(t *testing.T) {
+		history := filepath.Join(t.TempDir(), "history.json")
+		_, err := Run(Options{ProjectDir: dir, ConfigFiles: []string{cfg},
+			HistoryFile: history})
+		assert.NoError(err)
+		assert.FileExists(history)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	dir1, cfg1 := writeProject(t, "adminer.php")
+	dir2, cfg2 := writeProject(t)
+	rl1, err := Run(Options{ProjectDir: dir1, ConfigFiles: []string{cfg1}})
+	assert.NoError(err)
+	rl2, err := Run(Options{ProjectDir: dir2, ConfigFiles: []string{cfg2}})
+	assert.NoError(err)
+
+	rl := Merge(rl1, rl2)
+	assert.Equal(uint32(2), rl.TotalChecks)
+	assert.Equal(uint32(1), rl.TotalBreaches)
+	assert.Len(rl.Results, 2)
+	assert.Equal(result.Fail, rl.Status())
+}
@@ -0,0 +1,256 @@
+// Package s3 provides the functions for uploading the rendered report to a
+// bucket using the S3 API, which is supported by most object storage
+// providers.
+package s3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+const DefaultRegion = "us-east-1"
+const DefaultKeyTemplate = `{{ .Project }}/{{ now | date "2006-01-02T150405" }}.{{ .Extension }}`
+
+var Bucket string
+var KeyTemplate string
+var Endpoint string
+var Region string
+
+var accessKeyId string
+var secretAccessKey string
+var sessionToken string
+
+// KeyData is the data available when rendering the key template.
+type KeyData struct {
+	Project   string
+	Format    string
+	Extension string
+	Status    result.Status
+}
+
+var formatExtensions = map[string]string{
+	"coverage": "txt",
+	"json":     "json",
+	"junit":    "xml",
+	"simple":   "txt",
+	"table":    "txt",
+	"template": "txt",
+}
+
+var formatContentTypes = map[string]string{
+	"json":  "application/json",
+	"junit": "application/xml",
+}
+
+// MustHaveEnvVars reads the credentials from the standard AWS variables,
+// falling back to those of the config.
+func MustHaveEnvVars(creds config.CredentialsConfig) error {
+	accessKeyId = os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	if accessKeyId == "" && secretAccessKey == "" {
+		accessKeyId = creds.S3AccessKeyId
+		secretAccessKey = creds.S3SecretAccessKey
+		sessionToken = creds.S3SessionToken
+	}
+	if accessKeyId == "" || secretAccessKey == "" {
+		return fmt.Errorf("s3 credentials required; please ensure both " +
+			"AWS_ACCESS_KEY_ID & AWS_SECRET_ACCESS_KEY are set, or " +
+			"provided in the config")
+	}
+	if Region == "" {
+		Region = os.Getenv("AWS_REGION")
+	}
+	return nil
+}
+
+// RenderKey renders the object key template. Besides the KeyData fields,
+// the template can use the `now` and `date` functions, e.g,
+// `{{ now | date "2006-01-02" }}`.
+func RenderKey(tmpl string, data KeyData) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultKeyTemplate
+	}
+	t, err := template.New("key").Funcs(template.FuncMap{
+		"now":  utils.TimeNow,
+		"date": func(layout string, t time.Time) string { return t.Format(layout) },
+	}).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.Buffer{}
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	key := strings.TrimLeft(buf.String(), "/")
+	if key == "" {
+		return "", fmt.Errorf("key template rendered an empty key")
+	}
+	return key, nil
+}
+
+// UploadReport uploads the rendered report for the given output format,
+// returning the object key.
+func UploadReport(format string, report []byte, list result.ResultList) (string, error) {
+	projectDir, err := filepath.Abs(config.ProjectDir)
+	if err != nil {
+		return "", err
+	}
+	key, err := RenderKey(KeyTemplate, KeyData{
+		Project:   filepath.Base(projectDir),
+		Format:    format,
+		Extension: formatExtensions[format],
+		Status:    list.Status(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	contentType, ok := formatContentTypes[format]
+	if !ok {
+		contentType = "text/plain"
+	}
+	return key, Upload(key, report, contentType)
+}
+
+// Upload puts the object in the bucket.
+func Upload(key string, body []byte, contentType string) error {
+	region := Region
+	if region == "" {
+		region = DefaultRegion
+	}
+	endpoint := Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, strings.TrimRight(endpoint, "/"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// The path is set separately so that the escaped version used in the
+	// signature is the one sent.
+	segments := []string{uriEncode(Bucket)}
+	for _, s := range strings.Split(key, "/") {
+		segments = append(segments, uriEncode(s))
+	}
+	req.URL.RawPath = req.URL.EscapedPath() + "/" + strings.Join(segments, "/")
+	req.URL.Path = req.URL.Path + "/" + Bucket + "/" + key
+	req.Header.Set("Content-Type", contentType)
+	signRequest(req, body, region, utils.TimeNow())
+
+	resp, err := utils.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unable to upload report: %s: %s",
+			resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// signRequest adds the AWS Signature Version 4 headers to the request.
+func signRequest(req *http.Request, body []byte, region string, t time.Time) {
+	amzDate := t.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lname := strings.ToLower(name)
+		if lname == "content-type" || strings.HasPrefix(lname, "x-amz-") {
+			headers[lname] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := []string{}
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSha256([]byte("AWS4"+secretAccessKey), date)
+	signingKey = hmacSha256(signingKey, region)
+	signingKey = hmacSha256(signingKey, "s3")
+	signingKey = hmacSha256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyId, scope, signedHeaders, signature))
+}
+
+// uriEncode percent-encodes every byte outside the unreserved characters, as
+// required by the signature; url.PathEscape leaves characters such as ':'
+// or '+' as-is.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,164 @@
+package s3_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/s3"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockTimeNow() func() {
+	curTimeNow := utils.TimeNow
+	utils.TimeNow = func() time.Time {
+		return time.Date(2023, 5, 24, 10, 30, 0, 0, time.UTC)
+	}
+	return func() { utils.TimeNow = curTimeNow }
+}
+
+func TestMustHaveEnvVars(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	assert.EqualError(MustHaveEnvVars(config.CredentialsConfig{}), "s3 credentials required; please "+
+		"ensure both AWS_ACCESS_KEY_ID & AWS_SECRET_ACCESS_KEY are set, or "+
+		"provided in the config")
+
+	assert.NoError(MustHaveEnvVars(config.CredentialsConfig{
+		S3AccessKeyId:     "AKID",
+		S3SecretAccessKey: "SECRET",
+	}))
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_REGION", "ap-southeast-2")
+	defer func() { Region = "" }()
+	assert.NoError(MustHaveEnvVars(config.CredentialsConfig{}))
+	assert.Equal("ap-southeast-2", Region)
+}
+
+func TestRenderKey(t *testing.T) {
+	assert := assert.New(t)
+	defer mockTimeNow()()
+
+	data := KeyData{Project: "myproject", Format: "json", Extension: "json", Status: result.Fail}
+
+	key, err := RenderKey("", data)
+	assert.NoError(err)
+	assert.Equal("myproject/2023-05-24T103000.json", key)
+
+	key, err = RenderKey(`/reports/{{ .Project }}/{{ now | date "2006-01-02" }}-{{ .Status }}.{{ .Format }}`, data)
+	assert.NoError(err)
+	assert.Equal("reports/myproject/2023-05-24-Fail.json", key)
+
+	_, err = RenderKey("{{ .Project", data)
+	assert.EqualError(err, "template: key:1: unclosed action")
+
+	_, err = RenderKey("{{ .Unknown }}", data)
+	assert.ErrorContains(err, "can't evaluate field Unknown")
+
+	_, err = RenderKey("/", data)
+	assert.EqualError(err, "key template rendered an empty key")
+}
+
+func TestUploadReport(t *testing.T) {
+	assert := assert.New(t)
+	defer mockTimeNow()()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	assert.NoError(MustHaveEnvVars(config.CredentialsConfig{}))
+
+	var gotReq *http.Request
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReq = r
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		if strings.Contains(r.URL.Path, "denied") {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error><Code>AccessDenied</Code></Error>\n"))
+		}
+	}))
+	defer srv.Close()
+
+	Bucket = "reports"
+	Endpoint = srv.URL
+	config.ProjectDir = "/app/myproject"
+	defer func() {
+		Bucket = ""
+		Endpoint = ""
+		KeyTemplate = ""
+		config.ProjectDir = ""
+	}()
+
+	key, err := UploadReport("junit", []byte("<testsuites/>"), result.ResultList{})
+	assert.NoError(err)
+	assert.Equal("myproject/2023-05-24T103000.xml", key)
+	assert.Equal(http.MethodPut, gotReq.Method)
+	assert.Equal("/reports/myproject/2023-05-24T103000.xml", gotReq.URL.Path)
+	assert.Equal("<testsuites/>", gotBody)
+	assert.Equal("application/xml", gotReq.Header.Get("Content-Type"))
+	assert.Equal("20230524T103000Z", gotReq.Header.Get("X-Amz-Date"))
+	assert.True(strings.HasPrefix(gotReq.Header.Get("Authorization"),
+		"AWS4-HMAC-SHA256 Credential=AKID/20230524/us-east-1/s3/aws4_request, "+
+			"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="))
+
+	KeyTemplate = "denied/{{ .Project }}.txt"
+	_, err = UploadReport("simple", []byte("Ship is in top shape"), result.ResultList{})
+	assert.EqualError(err, "unable to upload report: 403 Forbidden: <Error><Code>AccessDenied</Code></Error>")
+	assert.Equal("text/plain", gotReq.Header.Get("Content-Type"))
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestUploadReportSignature(t *testing.T) {
+	assert := assert.New(t)
+	defer mockTimeNow()()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	assert.NoError(MustHaveEnvVars(config.CredentialsConfig{}))
+
+	var gotReq *http.Request
+	curHttpClient := utils.HttpClient
+	utils.HttpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotReq = req
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+
+	Bucket = "reports"
+	Endpoint = "https://s3.example.com"
+	KeyTemplate = `{{ .Project }}/{{ now | date "2006-01-02T15:04:05Z07:00" }}+dev.txt`
+	config.ProjectDir = "."
+	defer func() {
+		utils.HttpClient = curHttpClient
+		Bucket = ""
+		Endpoint = ""
+		KeyTemplate = ""
+		config.ProjectDir = ""
+	}()
+
+	key, err := UploadReport("simple", []byte("Ship is in top shape"), result.ResultList{})
+	assert.NoError(err)
+	assert.Equal("s3/2023-05-24T10:30:00Z+dev.txt", key)
+	assert.Equal("/reports/s3/2023-05-24T10%3A30%3A00Z%2Bdev.txt", gotReq.URL.EscapedPath())
+	assert.Equal("AWS4-HMAC-SHA256 Credential=AKID/20230524/us-east-1/s3/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, "+
+		"Signature=c2a336d232f0d1b58011bb97dd1e0d1ef84d8c5c45400bfe851220c2a7a3b6a2",
+		gotReq.Header.Get("Authorization"))
+}
@@ -0,0 +1,224 @@
+// Package server provides a read-only web interface for browsing the json
+// reports of previous runs, e.g, written using --output-file, so that the
+// results can be reviewed without parsing the json.
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultListenAddr = ":8080"
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+var templates = template.Must(template.New("").
+	Funcs(template.FuncMap(result.BreachTemplateFuncs)).
+	Funcs(template.FuncMap{
+		"runStatus":  func(rl result.ResultList) result.Status { return rl.Status() },
+		"formatTime": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04:05 MST") },
+	}).
+	ParseFS(templatesFS, "templates/*.html"))
+
+// ErrRunNotFound is returned when no report exists for the run.
+var ErrRunNotFound = errors.New("run not found")
+
+// Run is the report of a previous run.
+type Run struct {
+	// Name of the report file.
+	Name    string
+	Time    time.Time
+	Results result.ResultList
+}
+
+// RunDiff lists the breaches found in a run but not in the previous one, and
+// the ones which have been resolved.
+type RunDiff struct {
+	From Run
+	To   Run
+	result.ResultListDiff
+}
+
+// Server serves the reports found in a directory, and runs the webhooks'
+// profiles.
+type Server struct {
+	Dir      string
+	Webhooks map[string]Webhook
+	// Path to the shipshape binary run by the webhooks; defaults to the
+	// current one.
+	Executable string
+	mu         sync.Mutex
+	running    map[string]bool
+	wg         sync.WaitGroup
+}
+
+// Runs reads the json reports in the directory, most recent first; other
+// json files are skipped.
+func (s *Server) Runs() ([]Run, error) {
+	files, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	runs := []Run{}
+	for _, f := range files {
+		run, err := s.Run(filepath.Base(f))
+		if err != nil {
+			log.WithField("file", f).WithError(err).Debug("skipping file")
+			continue
+		}
+		runs = append(runs, run)
+	}
+	sort.SliceStable(runs, func(i int, j int) bool {
+		return runs[i].Time.After(runs[j].Time)
+	})
+	return runs, nil
+}
+
+// Run reads the report of a run from the directory.
+func (s *Server) Run(name string) (Run, error) {
+	if name != filepath.Base(name) || !strings.HasSuffix(name, ".json") {
+		return Run{}, ErrRunNotFound
+	}
+	f := filepath.Join(s.Dir, name)
+	info, err := os.Stat(f)
+	if err != nil {
+		return Run{}, ErrRunNotFound
+	}
+	data, err := os.ReadFile(f)
+	if err != nil {
+		return Run{}, err
+	}
+	run := Run{Name: name, Time: info.ModTime()}
+	if err := json.Unmarshal(data, &run.Results); err != nil {
+		return Run{}, fmt.Errorf("invalid report: %w", err)
+	}
+	if run.Results.SchemaVersion == "" {
+		return Run{}, errors.New("invalid report: no schema version")
+	}
+	return run, nil
+}
+
+// Diff compares the breaches of two runs.
+func Diff(from Run, to Run) RunDiff {
+	return RunDiff{From: from, To: to,
+		ResultListDiff: result.DiffResultLists(from.Results, to.Results)}
+}
+
+// PreviousRun returns the most recent run preceding the named one.
+func (s *Server) PreviousRun(name string) (Run, error) {
+	runs, err := s.Runs()
+	if err != nil {
+		return Run{}, err
+	}
+	for i, r := range runs {
+		if r.Name != name {
+			continue
+		}
+		if i+1 < len(runs) {
+			return runs[i+1], nil
+		}
+		break
+	}
+	return Run{}, ErrRunNotFound
+}
+
+// Handler returns the handler serving the runs list, a run's results and
+// the diff between two runs, along with the webhooks; only GET requests are
+// allowed, apart from the webhooks.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/runs/", s.handleRun)
+	mux.HandleFunc("/diff", s.handleDiff)
+	mux.HandleFunc("/webhooks/", s.handleWebhook)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/webhooks/") {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	runs, err := s.Runs()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	render(w, "index.html", runs)
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.lookupRun(w, strings.TrimPrefix(r.URL.Path, "/runs/"))
+	if !ok {
+		return
+	}
+	render(w, "run.html", run)
+}
+
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	from, ok := s.lookupRun(w, r.URL.Query().Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := s.lookupRun(w, r.URL.Query().Get("to"))
+	if !ok {
+		return
+	}
+	render(w, "diff.html", Diff(from, to))
+}
+
+// lookupRun reads the run, writing the error response if it fails.
+func (s *Server) lookupRun(w http.ResponseWriter, name string) (Run, bool) {
+	run, err := s.Run(name)
+	if errors.Is(err, ErrRunNotFound) {
+		http.Error(w, fmt.Sprintf("run '%s' not found", name), http.StatusNotFound)
+		return Run{}, false
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return Run{}, false
+	}
+	return run, true
+}
+
+func render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		log.WithError(err).Error("unable to render page")
+	}
+}
+
+// Wait waits for the webhook runs in progress to complete.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// Serve starts the server on the address.
+func Serve(addr string, s *Server) error {
+	return http.ListenAndServe(addr, s.Handler())
+}
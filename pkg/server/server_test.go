@@ -0,0 +1,164 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeReport(t *testing.T, dir string, name string, modTime time.Time, rl result.ResultList) {
+	data, err := json.Marshal(rl)
+	assert.NoError(t, err)
+	f := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(f, data, 0644))
+	assert.NoError(t, os.Chtimes(f, modTime, modTime))
+}
+
+func testServer(t *testing.T) *Server {
+	dir := t.TempDir()
+
+	first := result.NewResultList(false)
+	first.Results = []result.Result{{
+		Name:        "Illegal files",
+		Description: "No database tool is deployed",
+		Rationale:   "Database tools expose the data",
+		Status:      result.Fail,
+		Breaches: []result.Breach{
+			&result.ValueBreach{BreachType: result.BreachTypeValue, Value: "adminer.php"},
+			&result.ValueBreach{BreachType: result.BreachTypeValue, Value: "info.php",
+				DocUrl: "https://wiki.example.com/illegal-files", RemediationHint: "Delete the file"},
+		},
+	}}
+	first.TotalChecks, first.TotalBreaches = 1, 2
+	writeReport(t, dir, "first.json", time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), first)
+
+	second := result.NewResultList(false)
+	second.Results = []result.Result{{
+		Name:   "Illegal files",
+		Status: result.Fail,
+		Breaches: []result.Breach{
+			&result.ValueBreach{BreachType: result.BreachTypeValue, Value: "info.php"},
+			&result.ValueBreach{BreachType: result.BreachTypeValue, Value: "bigdump.php"},
+		},
+	}}
+	second.TotalChecks, second.TotalBreaches = 1, 2
+	writeReport(t, dir, "second.json", time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), second)
+
+	// Not a report.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "app"}`), 0644))
+	return &Server{Dir: dir}
+}
+
+func TestRuns(t *testing.T) {
+	assert := assert.New(t)
+	s := testServer(t)
+
+	runs, err := s.Runs()
+	assert.NoError(err)
+	assert.Len(runs, 2)
+	assert.Equal("second.json", runs[0].Name)
+	assert.Equal("first.json", runs[1].Name)
+	assert.Equal("info.php", runs[0].Results.Results[0].Breaches[0].String())
+
+	for _, name := range []string{"missing.json", "../first.json", "first.yml"} {
+		_, err = s.Run(name)
+		assert.ErrorIs(err, ErrRunNotFound)
+	}
+	_, err = s.Run("package.json")
+	assert.EqualError(err, "invalid report: no schema version")
+}
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+	s := testServer(t)
+
+	first, _ := s.Run("first.json")
+	second, _ := s.Run("second.json")
+	d := Diff(first, second)
+	assert.Equal([]result.BreachDiff{{
+		Check:  "Illegal files",
+		Breach: &result.ValueBreach{BreachType: result.BreachTypeValue, Value: "bigdump.php"},
+	}}, d.New)
+	assert.Equal([]result.BreachDiff{{
+		Check:  "Illegal files",
+		Breach: &result.ValueBreach{BreachType: result.BreachTypeValue, Value: "adminer.php"},
+	}}, d.Resolved)
+	assert.Len(d.Persisting, 1)
+	assert.Equal("info.php", d.Persisting[0].Breach.String())
+}
+
+func TestPreviousRun(t *testing.T) {
+	assert := assert.New(t)
+	s := testServer(t)
+
+	prev, err := s.PreviousRun("second.json")
+	assert.NoError(err)
+	assert.Equal("first.json", prev.Name)
+
+	_, err = s.PreviousRun("first.json")
+	assert.ErrorIs(err, ErrRunNotFound)
+	_, err = s.PreviousRun("missing.json")
+	assert.ErrorIs(err, ErrRunNotFound)
+}
+
+func TestHandler(t *testing.T) {
+	s := testServer(t)
+	h := s.Handler()
+
+	tt := []struct {
+		name           string
+		method         string
+		url            string
+		expectCode     int
+		expectContains []string
+	}{
+		{
+			name:           "index",
+			url:            "/",
+			expectCode:     http.StatusOK,
+			expectContains: []string{`<a href="/runs/second.json">second.json</a>`, "2026-10-01 00:00:00 UTC"},
+		},
+		{
+			name:           "run",
+			url:            "/runs/first.json",
+			expectCode:     http.StatusOK,
+			expectContains: []string{"Illegal files", "No database tool is deployed", "Database tools expose the data", "2 breaches", "<li>adminer.php</li>", `<li>info.php<br><em>Delete the file</em> <a href="https://wiki.example.com/illegal-files">docs</a></li>`},
+		},
+		{
+			name:           "diff",
+			url:            "/diff?from=first.json&to=second.json",
+			expectCode:     http.StatusOK,
+			expectContains: []string{"New breaches (1)", "bigdump.php", "Resolved breaches (1)", "adminer.php"},
+		},
+		{
+			name:           "runNotFound",
+			url:            "/runs/missing.json",
+			expectCode:     http.StatusNotFound,
+			expectContains: []string{"run 'missing.json' not found"},
+		},
+		{name: "pageNotFound", url: "/foo", expectCode: http.StatusNotFound},
+		{name: "readOnly", method: http.MethodPost, url: "/", expectCode: http.StatusMethodNotAllowed},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, tc.url, nil))
+			assert.Equal(t, tc.expectCode, rec.Code)
+			for _, s := range tc.expectContains {
+				assert.Contains(t, rec.Body.String(), s)
+			}
+		})
+	}
+}
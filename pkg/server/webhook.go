@@ -0,0 +1,160 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// maxWebhookBodySize is the size of the request body read to verify
+// signatures.
+const maxWebhookBodySize = 1 << 20
+
+var webhookNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Webhook is a profile run when its endpoint is called, e.g, by a GitHub or
+// GitLab deployment event or a Lagoon post-deploy task.
+type Webhook struct {
+	// Environment variable holding the secret authenticating the requests.
+	SecretEnv string `yaml:"secret-env"`
+	// Arguments passed to shipshape, e.g, [-f, shipshape.yml, /app]; the
+	// outputs, e.g, --s3-bucket, post the results back.
+	Args []string `yaml:"args"`
+}
+
+// ReadWebhooks reads the webhook profiles, keyed by name, from a yaml file.
+func ReadWebhooks(file string) (map[string]Webhook, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		Webhooks map[string]Webhook `yaml:"webhooks"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	for name, w := range cfg.Webhooks {
+		if !webhookNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid webhook name '%s'", name)
+		}
+		if w.SecretEnv == "" || os.Getenv(w.SecretEnv) == "" {
+			return nil, fmt.Errorf("no secret for webhook '%s'; set it in the environment variable defined by secret-env", name)
+		}
+	}
+	return cfg.Webhooks, nil
+}
+
+// handleWebhook authenticates the request and runs the webhook's profile in
+// the background, writing the json report to the directory.
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/webhooks/")
+	webhook, ok := s.Webhooks[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !Authenticated(r, body, os.Getenv(webhook.SecretEnv)) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	if s.running == nil {
+		s.running = map[string]bool{}
+	}
+	if s.running[name] {
+		s.mu.Unlock()
+		http.Error(w, fmt.Sprintf("webhook '%s' is already running", name), http.StatusConflict)
+		return
+	}
+	s.running[name] = true
+	s.mu.Unlock()
+
+	report := fmt.Sprintf("%s-%s.json", name, utils.TimeNow().UTC().Format("20060102T150405"))
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, name)
+			s.mu.Unlock()
+		}()
+		s.runWebhook(name, webhook, report)
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"report": report})
+}
+
+// runWebhook runs shipshape with the webhook's arguments.
+func (s *Server) runWebhook(name string, webhook Webhook, report string) {
+	exe := s.Executable
+	if exe == "" {
+		var err error
+		if exe, err = os.Executable(); err != nil {
+			log.WithError(err).Error("unable to determine the shipshape executable")
+			return
+		}
+	}
+	args := append(append([]string{}, webhook.Args...),
+		"-o", "json", "--output-file", filepath.Join(s.Dir, report))
+	contextLogger := log.WithFields(log.Fields{"webhook": name, "report": report})
+	contextLogger.Info("running webhook")
+	if _, err := command.ShellCommander(exe, args...).Output(); err != nil {
+		// The exit code is also non-zero when failures are detected with
+		// --error-code, in which case the report is still written.
+		contextLogger.WithError(err).Warn(command.GetMsgFromCommandError(err))
+	}
+}
+
+// Authenticated verifies the request using either a GitHub signature
+// (X-Hub-Signature-256), a GitLab token (X-Gitlab-Token) or a bearer token.
+func Authenticated(r *http.Request, body []byte, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	if sig, found := strings.CutPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256="); found {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(sig), []byte(expected))
+	}
+	if token := r.Header.Get("X-Gitlab-Token"); token != "" {
+		return secureEqual(token, secret)
+	}
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		return secureEqual(token, secret)
+	}
+	return false
+}
+
+func secureEqual(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
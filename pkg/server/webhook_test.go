@@ -0,0 +1,133 @@
+package server_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	. "github.com/salsadigitalauorg/shipshape/pkg/server"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWebhooks(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	t.Setenv("TEST_WEBHOOK_SECRET", "s3cr3t")
+
+	f := filepath.Join(dir, "webhooks.yml")
+	os.WriteFile(f, []byte(`
+webhooks:
+  production:
+    secret-env: TEST_WEBHOOK_SECRET
+    args: [-f, shipshape.yml, /app]
+`), 0644)
+	webhooks, err := ReadWebhooks(f)
+	assert.NoError(err)
+	assert.Equal(map[string]Webhook{"production": {
+		SecretEnv: "TEST_WEBHOOK_SECRET",
+		Args:      []string{"-f", "shipshape.yml", "/app"},
+	}}, webhooks)
+
+	os.WriteFile(f, []byte("webhooks:\n  production:\n    secret-env: TEST_WEBHOOK_MISSING\n"), 0644)
+	_, err = ReadWebhooks(f)
+	assert.EqualError(err, "no secret for webhook 'production'; set it in the environment variable defined by secret-env")
+
+	os.WriteFile(f, []byte("webhooks:\n  ../prod:\n    secret-env: TEST_WEBHOOK_SECRET\n"), 0644)
+	_, err = ReadWebhooks(f)
+	assert.EqualError(err, "invalid webhook name '../prod'")
+}
+
+func TestAuthenticated(t *testing.T) {
+	body := []byte(`{"ref": "main"}`)
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tt := []struct {
+		name     string
+		header   string
+		value    string
+		secret   string
+		expected bool
+	}{
+		{name: "githubSignature", header: "X-Hub-Signature-256", value: signature, expected: true},
+		{name: "githubInvalidSignature", header: "X-Hub-Signature-256", value: "sha256=abc"},
+		{name: "gitlabToken", header: "X-Gitlab-Token", value: "s3cr3t", expected: true},
+		{name: "gitlabInvalidToken", header: "X-Gitlab-Token", value: "secret"},
+		{name: "bearerToken", header: "Authorization", value: "Bearer s3cr3t", expected: true},
+		{name: "basicAuth", header: "Authorization", value: "Basic s3cr3t"},
+		{name: "noSecret", header: "X-Gitlab-Token", value: "", secret: "-"},
+		{name: "none"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/webhooks/production", nil)
+			if tc.header != "" {
+				r.Header.Set(tc.header, tc.value)
+			}
+			secret := "s3cr3t"
+			if tc.secret == "-" {
+				secret = ""
+			}
+			assert.Equal(t, tc.expected, Authenticated(r, body, secret))
+		})
+	}
+}
+
+func TestHandleWebhook(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("TEST_WEBHOOK_SECRET", "s3cr3t")
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Date(2026, 10, 16, 1, 2, 3, 0, time.UTC) }
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(nil, nil, &generatedCommand)
+
+	s := &Server{
+		Dir:        "/reports",
+		Executable: "/usr/local/bin/shipshape",
+		Webhooks: map[string]Webhook{"production": {
+			SecretEnv: "TEST_WEBHOOK_SECRET",
+			Args:      []string{"-f", "shipshape.yml", "/app"},
+		}},
+	}
+	h := s.Handler()
+
+	post := func(url string, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader("{}"))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	assert.Equal(http.StatusNotFound, post("/webhooks/staging", "s3cr3t").Code)
+	assert.Equal(http.StatusUnauthorized, post("/webhooks/production", "").Code)
+	assert.Equal(http.StatusUnauthorized, post("/webhooks/production", "secret").Code)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks/production", nil))
+	assert.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	rec = post("/webhooks/production", "s3cr3t")
+	s.Wait()
+	assert.Equal(http.StatusAccepted, rec.Code)
+	assert.JSONEq(`{"report": "production-20261016T010203.json"}`, rec.Body.String())
+	assert.Equal("/usr/local/bin/shipshape -f shipshape.yml /app -o json --output-file /reports/production-20261016T010203.json", generatedCommand)
+}
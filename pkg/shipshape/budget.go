@@ -0,0 +1,41 @@
+package shipshape
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxDuration is the time budget of the run; once it is reached, no new
+// checks are started, while the running ones are completed. There is no
+// budget if 0.
+var MaxDuration time.Duration
+
+// MaxConcurrentChecks is the maximum number of checks run at the same time
+// against a target; the budget is checked before starting each of them.
+// There is no limit if 0.
+var MaxConcurrentChecks int
+
+// budgetReached determines whether the run started at start has used its
+// time budget.
+func budgetReached(start time.Time) bool {
+	return MaxDuration > 0 && utils.TimeNow().Sub(start) >= MaxDuration
+}
+
+// skipOverBudgetChecks records the checks as skipped since the run's time
+// budget was reached.
+func skipOverBudgetChecks(checks []config.Check) {
+	for _, c := range checks {
+		log.WithFields(log.Fields{
+			"check-type":   c.GetType(),
+			"check-name":   c.GetName(),
+			"max-duration": MaxDuration,
+		}).Print("skipping check since the run's time budget was reached")
+		skipCheck(c, result.Skipped, result.SkipReasonBudget, fmt.Sprintf("skipped since the run's time budget of %s was reached", MaxDuration))
+	}
+}
@@ -0,0 +1,118 @@
+package shipshape_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// slowCheck passes after advancing the clock.
+type slowCheck struct {
+	testchecks.TestCheck1Check `yaml:",inline"`
+	now                        *time.Time
+}
+
+func (c *slowCheck) RequiresData() bool { return false }
+
+func (c *slowCheck) RunCheck() {
+	*c.now = c.now.Add(5 * time.Minute)
+	c.AddPass("done")
+	c.Result.Status = result.Pass
+}
+
+// runSlowChecks runs a first slow check followed by a second one, configured
+// by secondYaml, with the time budget.
+func runSlowChecks(now *time.Time, maxDuration time.Duration, secondYaml string) map[string]result.Result {
+	first := &slowCheck{now: now}
+	yaml.Unmarshal([]byte("name: first"), first)
+	first.Init(testchecks.TestCheck1)
+	second := &slowCheck{now: now}
+	yaml.Unmarshal([]byte(secondYaml), second)
+	second.Init(testchecks.TestCheck1)
+	RunConfig = config.Config{
+		Checks: config.CheckMap{testchecks.TestCheck1: {first, second}},
+	}
+
+	MaxDuration = maxDuration
+	RunResultList = result.NewResultList(false)
+	RunChecks()
+	results := map[string]result.Result{}
+	for _, r := range RunResultList.Results {
+		results[r.Name] = r
+	}
+	return results
+}
+
+func TestRunChecksMaxDuration(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	now := time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC)
+	curTimeNow := utils.TimeNow
+	defer func() {
+		utils.TimeNow = curTimeNow
+		MaxDuration = 0
+		MaxConcurrentChecks = 0
+		RunConfig = config.Config{}
+	}()
+	utils.TimeNow = func() time.Time { return now }
+
+	runChecks := func(maxDuration time.Duration) map[string]result.Result {
+		return runSlowChecks(&now, maxDuration, "name: second\ndepends-on: [first]")
+	}
+
+	results := runChecks(0)
+	assert.Equal(result.Pass, results["first"].Status)
+	assert.Equal(result.Pass, results["second"].Status)
+
+	// The second check is not started since the first one used the budget.
+	results = runChecks(5 * time.Minute)
+	assert.Equal(result.Pass, results["first"].Status)
+	assert.Equal(result.Skipped, results["second"].Status)
+	assert.Equal(result.SkipReasonBudget, results["second"].SkipReason)
+	assert.Empty(results["second"].Passes)
+	assert.Equal([]string{"skipped since the run's time budget of 5m0s was reached"},
+		results["second"].Warnings)
+	assert.Equal(result.Pass, RunResultList.Status())
+}
+
+func TestRunChecksMaxDurationWithinStage(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	now := time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC)
+	curTimeNow := utils.TimeNow
+	defer func() {
+		utils.TimeNow = curTimeNow
+		MaxDuration = 0
+		MaxConcurrentChecks = 0
+		RunConfig = config.Config{}
+	}()
+	utils.TimeNow = func() time.Time { return now }
+
+	// Both checks are in the same stage; the second one is not started since
+	// it waits for the first one, which uses the budget.
+	MaxConcurrentChecks = 1
+	results := runSlowChecks(&now, 5*time.Minute, "name: second")
+	assert.Equal(result.Pass, results["first"].Status)
+	assert.Equal(result.Skipped, results["second"].Status)
+	assert.Equal(result.SkipReasonBudget, results["second"].SkipReason)
+	assert.Empty(results["second"].Passes)
+	assert.Equal(result.Pass, RunResultList.Status())
+}
@@ -0,0 +1,114 @@
+package shipshape
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+)
+
+// ControlCoverage is the status of a compliance framework control, based on
+// the results of the checks mapped to it.
+type ControlCoverage struct {
+	Control string        `json:"control"`
+	Status  result.Status `json:"status"`
+	Checks  []string      `json:"checks"`
+}
+
+// FrameworkCoverage summarises the coverage of a compliance framework's
+// controls by the checks.
+type FrameworkCoverage struct {
+	Framework string            `json:"framework"`
+	Passed    int               `json:"passed"`
+	Failed    int               `json:"failed"`
+	Controls  []ControlCoverage `json:"controls"`
+}
+
+// Coverage determines the status of the compliance framework controls the
+// checks map to; a control fails if any of its checks failed.
+func Coverage(rl result.ResultList) []FrameworkCoverage {
+	controls := map[string]map[string]*ControlCoverage{}
+	for _, r := range rl.Results {
+		checkName := r.Name
+		if r.Target != "" {
+			checkName = fmt.Sprintf("%s [%s]", r.Name, r.Target)
+		}
+		for _, fc := range r.Controls {
+			framework, control, found := strings.Cut(fc, ":")
+			if !found {
+				framework, control = "", fc
+			}
+			if controls[framework] == nil {
+				controls[framework] = map[string]*ControlCoverage{}
+			}
+			cc, ok := controls[framework][control]
+			if !ok {
+				cc = &ControlCoverage{Control: control, Status: result.Pass}
+				controls[framework][control] = cc
+			}
+			cc.Checks = append(cc.Checks, checkName)
+			if r.Status != result.Pass {
+				cc.Status = result.Fail
+			}
+		}
+	}
+
+	frameworks := []string{}
+	for f := range controls {
+		frameworks = append(frameworks, f)
+	}
+	sort.Strings(frameworks)
+
+	coverage := []FrameworkCoverage{}
+	for _, f := range frameworks {
+		fc := FrameworkCoverage{Framework: f, Controls: []ControlCoverage{}}
+		ids := []string{}
+		for id := range controls[f] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			cc := controls[f][id]
+			sort.Strings(cc.Checks)
+			if cc.Status == result.Pass {
+				fc.Passed++
+			} else {
+				fc.Failed++
+			}
+			fc.Controls = append(fc.Controls, *cc)
+		}
+		coverage = append(coverage, fc)
+	}
+	return coverage
+}
+
+// CoverageDisplay generates the compliance coverage output for the
+// ResultList.
+func CoverageDisplay(w *bufio.Writer) {
+	coverage := Coverage(RunResultList)
+	if len(coverage) == 0 {
+		fmt.Fprint(w, "No checks mapped to controls; add them using the checks' controls field.\n")
+		w.Flush()
+		return
+	}
+
+	fmt.Fprint(w, "# Compliance coverage\n\n")
+	for _, fc := range coverage {
+		framework := fc.Framework
+		if framework == "" {
+			framework = "(no framework)"
+		}
+		fmt.Fprintf(w, "## %s: %d/%d controls passing\n\n", framework,
+			fc.Passed, fc.Passed+fc.Failed)
+		for _, cc := range fc.Controls {
+			fmt.Fprintf(w, "  [%s] %s\n", cc.Status, cc.Control)
+			for _, c := range cc.Checks {
+				fmt.Fprintf(w, "     -- %s\n", c)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+}
@@ -0,0 +1,91 @@
+package shipshape_test
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoverage(t *testing.T) {
+	assert := assert.New(t)
+
+	rl := result.NewResultList(false)
+	assert.Equal([]FrameworkCoverage{}, Coverage(rl))
+
+	rl.Results = append(rl.Results,
+		result.Result{
+			Name:     "a",
+			Status:   result.Pass,
+			Controls: []string{"iso27001:A.12.4.1", "soc2:CC7.1"},
+		},
+		result.Result{
+			Name:     "b",
+			Target:   "web",
+			Status:   result.Fail,
+			Controls: []string{"iso27001:A.12.6.1", "soc2:CC7.1"},
+		},
+		result.Result{
+			Name:     "c",
+			Status:   result.Pass,
+			Controls: []string{"iso27001:A.12.6.1", "backups"},
+		},
+		result.Result{Name: "d", Status: result.Fail},
+	)
+	assert.Equal([]FrameworkCoverage{
+		{
+			Framework: "",
+			Passed:    1,
+			Controls: []ControlCoverage{
+				{Control: "backups", Status: result.Pass, Checks: []string{"c"}},
+			},
+		},
+		{
+			Framework: "iso27001",
+			Passed:    1,
+			Failed:    1,
+			Controls: []ControlCoverage{
+				{Control: "A.12.4.1", Status: result.Pass, Checks: []string{"a"}},
+				{Control: "A.12.6.1", Status: result.Fail, Checks: []string{"b [web]", "c"}},
+			},
+		},
+		{
+			Framework: "soc2",
+			Failed:    1,
+			Controls: []ControlCoverage{
+				{Control: "CC7.1", Status: result.Fail, Checks: []string{"a", "b [web]"}},
+			},
+		},
+	}, Coverage(rl))
+}
+
+func TestCoverageDisplay(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	RunResultList = result.NewResultList(false)
+	w := bufio.NewWriter(&buf)
+	CoverageDisplay(w)
+	assert.Equal("No checks mapped to controls; add them using the checks' controls field.\n", buf.String())
+
+	RunResultList.Results = append(RunResultList.Results,
+		result.Result{Name: "a", Status: result.Pass, Controls: []string{"iso27001:A.12.4.1"}},
+		result.Result{Name: "b", Status: result.Fail, Controls: []string{"iso27001:A.12.6.1"}},
+	)
+	buf = bytes.Buffer{}
+	w = bufio.NewWriter(&buf)
+	CoverageDisplay(w)
+	assert.Equal(`# Compliance coverage
+
+## iso27001: 1/2 controls passing
+
+  [Pass] A.12.4.1
+     -- a
+  [Fail] A.12.6.1
+     -- b
+
+`, buf.String())
+}
@@ -0,0 +1,111 @@
+package shipshape
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ValidateDependencies verifies the checks only depend on existing checks
+// and that there is no circular dependency between them.
+func ValidateDependencies(cm config.CheckMap) error {
+	deps := map[string][]string{}
+	for _, checks := range cm {
+		for _, c := range checks {
+			deps[c.GetName()] = append(deps[c.GetName()], c.GetDependsOn()...)
+		}
+	}
+
+	names := []string{}
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, d := range deps[name] {
+			if _, ok := deps[d]; !ok {
+				return fmt.Errorf("check '%s' depends on unknown check '%s'", name, d)
+			}
+		}
+	}
+
+	// Depth-first search, keeping the path to report the cycle.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		path = append(path, name)
+		switch state[name] {
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			return fmt.Errorf("circular dependency between checks: %s",
+				strings.Join(path[start:], " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, d := range deps[name] {
+			if err := visit(d, path); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// failedDependency finds the result of the first of the check's dependencies
+// which did not pass; dependencies which were not run, including the ones
+// disabled or whose condition is not met, are ignored.
+func failedDependency(c config.Check, rl *result.ResultList) (result.Result, bool) {
+	for _, d := range c.GetDependsOn() {
+		for _, r := range rl.Results {
+			if r.Name != d || r.Status == result.Pass {
+				continue
+			}
+			switch r.SkipReason {
+			case result.SkipReasonDisabled, result.SkipReasonCondition, result.SkipReasonPlatform:
+				continue
+			}
+			return r, true
+		}
+	}
+	return result.Result{}, false
+}
+
+// skipDependentChecks records the checks whose dependencies did not pass as
+// skipped, returning the checks which can run.
+func skipDependentChecks(checks []config.Check) []config.Check {
+	toRun := []config.Check{}
+	for _, c := range checks {
+		dep, failed := failedDependency(c, &RunResultList)
+		if !failed {
+			toRun = append(toRun, c)
+			continue
+		}
+		log.WithFields(log.Fields{
+			"check-type": c.GetType(),
+			"check-name": c.GetName(),
+			"dependency": dep.Name,
+		}).Print("skipping check since its dependency did not pass")
+		skipCheck(c, result.Skipped, result.SkipReasonDependency, fmt.Sprintf("skipped since its dependency '%s' did not pass", dep.Name))
+	}
+	return toRun
+}
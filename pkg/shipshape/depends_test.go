@@ -0,0 +1,132 @@
+package shipshape_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	assert := assert.New(t)
+
+	check := func(name string, dependsOn ...string) config.Check {
+		return &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: name, DependsOn: dependsOn}}
+	}
+
+	assert.NoError(ValidateDependencies(config.CheckMap{
+		testchecks.TestCheck1: {check("a"), check("b", "a")},
+		testchecks.TestCheck2: {check("c", "a", "b")},
+	}))
+
+	err := ValidateDependencies(config.CheckMap{
+		testchecks.TestCheck1: {check("a"), check("b", "unknown")},
+	})
+	assert.EqualError(err, "check 'b' depends on unknown check 'unknown'")
+
+	err = ValidateDependencies(config.CheckMap{
+		testchecks.TestCheck1: {check("a", "b"), check("b", "a")},
+	})
+	assert.EqualError(err, "circular dependency between checks: a -> b -> a")
+
+	err = ValidateDependencies(config.CheckMap{
+		testchecks.TestCheck1: {check("a"), check("b", "c"), check("c", "d"), check("d", "b")},
+	})
+	assert.EqualError(err, "circular dependency between checks: b -> c -> d -> b")
+}
+
+func TestCheckStagesDependsOn(t *testing.T) {
+	assert := assert.New(t)
+
+	first := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "first"}}
+	second := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{
+		Name: "second", DependsOn: []string{"first"}}}
+	third := &testchecks.TestCheck2Check{CheckBase: config.CheckBase{
+		Name: "third", DependsOn: []string{"first", "second"}}}
+	other := &testchecks.TestCheck2Check{CheckBase: config.CheckBase{Name: "other"}}
+	// Dependencies which are not run do not hold the check back.
+	notRun := &testchecks.TestCheck2Check{CheckBase: config.CheckBase{
+		Name: "not-run", DependsOn: []string{"excluded"}}}
+
+	stages := CheckStages([]config.Check{third, second, other, first, notRun})
+	assert.Equal([][]config.Check{
+		{other, first, notRun},
+		{second},
+		{third},
+	}, stages)
+}
+
+func TestRunChecksDependsOn(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	failing := &testchecks.TestCheck1Check{}
+	yaml.Unmarshal([]byte("name: failing"), failing)
+	failing.Init(testchecks.TestCheck1)
+	dependent := &testchecks.TestCheck2Check{}
+	yaml.Unmarshal([]byte("name: dependent\ndepends-on: [failing]"), dependent)
+	dependent.Init(testchecks.TestCheck2)
+	transitive := &testchecks.TestCheck2Check{}
+	yaml.Unmarshal([]byte("name: transitive\ndepends-on: [dependent]"), transitive)
+	transitive.Init(testchecks.TestCheck2)
+	RunConfig = config.Config{
+		Checks: config.CheckMap{
+			testchecks.TestCheck1: {failing},
+			testchecks.TestCheck2: {dependent, transitive},
+		},
+	}
+
+	RunResultList = result.NewResultList(false)
+	RunChecks()
+	assert.Equal(uint32(3), RunResultList.TotalChecks)
+	assert.Equal(uint32(1), RunResultList.TotalBreaches)
+
+	results := map[string]result.Result{}
+	for _, r := range RunResultList.Results {
+		results[r.Name] = r
+	}
+	assert.Equal(result.Fail, results["failing"].Status)
+	assert.Len(results["failing"].Breaches, 1)
+
+	assert.Equal(result.Skipped, results["dependent"].Status)
+	assert.Equal(result.SkipReasonDependency, results["dependent"].SkipReason)
+	assert.Empty(results["dependent"].Breaches)
+	assert.Equal([]string{"skipped since its dependency 'failing' did not pass"},
+		results["dependent"].Warnings)
+
+	assert.Equal(result.Skipped, results["transitive"].Status)
+	assert.Empty(results["transitive"].Breaches)
+	assert.Equal([]string{"skipped since its dependency 'dependent' did not pass"},
+		results["transitive"].Warnings)
+
+	// Only the failing check is reported as failed by the outputs.
+	data, err := json.Marshal(RunResultList)
+	assert.NoError(err)
+	assert.Equal(1, strings.Count(string(data), `"status":"Fail"`))
+	assert.Equal(2, strings.Count(string(data), `"status":"Skipped"`))
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	JUnit(w)
+	assert.Contains(buf.String(), `skipped="2"`)
+
+	buf.Reset()
+	SimpleDisplay(w)
+	assert.Contains(buf.String(), "# Skipped checks")
+	assert.Contains(buf.String(), "### dependent")
+	assert.Contains(buf.String(), "### transitive")
+}
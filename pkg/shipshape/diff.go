@@ -0,0 +1,99 @@
+package shipshape
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+)
+
+// DiffFormats is the list of formats the diff between two reports can be
+// displayed as; markdown is suitable for pull request comments.
+var DiffFormats = []string{"simple", "markdown", "json"}
+
+type diffSection struct {
+	title    string
+	breaches []result.BreachDiff
+}
+
+func diffSections(d result.ResultListDiff) []diffSection {
+	return []diffSection{
+		{"New breaches", d.New},
+		{"Resolved breaches", d.Resolved},
+		{"Persisting breaches", d.Persisting},
+	}
+}
+
+// DiffDisplay generates the output for the diff between two reports in the
+// given format.
+func DiffDisplay(w *bufio.Writer, d result.ResultListDiff, format string) error {
+	switch format {
+	case "json":
+		data, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	case "simple":
+		diffSimpleDisplay(w, d)
+	case "markdown":
+		diffMarkdownDisplay(w, d)
+	default:
+		return fmt.Errorf("invalid diff format '%s'; needs to be one of: %s",
+			format, strings.Join(DiffFormats, "|"))
+	}
+	return w.Flush()
+}
+
+func diffSimpleDisplay(w *bufio.Writer, d result.ResultListDiff) {
+	for _, s := range diffSections(d) {
+		fmt.Fprintf(w, "# %s (%d)\n\n", s.title, len(s.breaches))
+		prevCheck := ""
+		for _, bd := range s.breaches {
+			check := bd.Check
+			if bd.Target != "" {
+				check = fmt.Sprintf("%s [%s]", bd.Check, bd.Target)
+			}
+			if check != prevCheck {
+				if prevCheck != "" {
+					fmt.Fprintln(w)
+				}
+				fmt.Fprintf(w, "  ### %s\n", check)
+				prevCheck = check
+			}
+			fmt.Fprintf(w, "     -- %s\n", bd.Breach)
+		}
+		if prevCheck != "" {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// markdownCell escapes the value for use in a markdown table cell.
+func markdownCell(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "|", `\|`), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.Join(lines, "<br>")
+}
+
+func diffMarkdownDisplay(w *bufio.Writer, d result.ResultListDiff) {
+	fmt.Fprint(w, "## Shipshape diff\n\n")
+	fmt.Fprintf(w, "**%d** new, **%d** resolved and **%d** persisting breaches.\n",
+		len(d.New), len(d.Resolved), len(d.Persisting))
+	for _, s := range diffSections(d) {
+		fmt.Fprintf(w, "\n### %s (%d)\n\n", s.title, len(s.breaches))
+		if len(s.breaches) == 0 {
+			fmt.Fprint(w, "None.\n")
+			continue
+		}
+		fmt.Fprint(w, "| Check | Target | Breach |\n|---|---|---|\n")
+		for _, bd := range s.breaches {
+			fmt.Fprintf(w, "| %s | %s | %s |\n", markdownCell(bd.Check),
+				markdownCell(bd.Target), markdownCell(bd.Breach.String()))
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package shipshape_test
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffDisplay(t *testing.T) {
+	assert := assert.New(t)
+
+	d := result.ResultListDiff{
+		New: []result.BreachDiff{
+			{Check: "Illegal files", Breach: &result.ValueBreach{Value: "bigdump.php"}},
+			{Check: "Illegal files", Breach: &result.ValueBreach{Value: "a|b.php"}},
+			{Check: "Roles", Target: "prod", Breach: &result.KeyValuesBreach{
+				KeyLabel: "role", Key: "editor", ValueLabel: "permissions",
+				Values: []string{"administer users", "administer site configuration"}}},
+		},
+		Resolved: []result.BreachDiff{
+			{Check: "Illegal files", Breach: &result.ValueBreach{Value: "adminer.php"}},
+		},
+		Persisting: []result.BreachDiff{},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(DiffDisplay(bufio.NewWriter(&buf), d, "simple"))
+	assert.Equal(`# New breaches (3)
+
+  ### Illegal files
+     -- bigdump.php
+     -- a|b.php
+
+  ### Roles [prod]
+     -- [role:editor] permissions:
+        - administer users
+        - administer site configuration
+
+# Resolved breaches (1)
+
+  ### Illegal files
+     -- adminer.php
+
+# Persisting breaches (0)
+
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(DiffDisplay(bufio.NewWriter(&buf), d, "markdown"))
+	assert.Equal(`## Shipshape diff
+
+**3** new, **1** resolved and **0** persisting breaches.
+
+### New breaches (3)
+
+| Check | Target | Breach |
+|---|---|---|
+| Illegal files |  | bigdump.php |
+| Illegal files |  | a\|b.php |
+| Roles | prod | [role:editor] permissions:<br>- administer users<br>- administer site configuration |
+
+### Resolved breaches (1)
+
+| Check | Target | Breach |
+|---|---|---|
+| Illegal files |  | adminer.php |
+
+### Persisting breaches (0)
+
+None.
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(DiffDisplay(bufio.NewWriter(&buf), result.ResultListDiff{
+		New:        []result.BreachDiff{{Check: "Illegal files", Breach: &result.ValueBreach{BreachType: result.BreachTypeValue, Value: "bigdump.php"}}},
+		Resolved:   []result.BreachDiff{},
+		Persisting: []result.BreachDiff{},
+	}, "json"))
+	assert.Equal(`{"new":[{"check":"Illegal files","breach":{"breach-type":"value","check-type":"","check-name":"","severity":"","value":"bigdump.php","remediation":{}}}],"resolved":[],"persisting":[]}
+`, buf.String())
+
+	assert.EqualError(DiffDisplay(bufio.NewWriter(&buf), d, "xml"),
+		"invalid diff format 'xml'; needs to be one of: simple|markdown|json")
+}
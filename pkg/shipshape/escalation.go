@@ -0,0 +1,139 @@
+package shipshape
+
+import (
+	"fmt"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// ValidateEscalation verifies the escalation rules are valid.
+func ValidateEscalation(rules []config.EscalationRule) error {
+	for _, rule := range rules {
+		if rule.After != "" {
+			if _, err := utils.ParseDuration(rule.After); err != nil {
+				return fmt.Errorf("invalid escalation duration '%s': %w", rule.After, err)
+			}
+		}
+		if !rule.Severity.IsValid() {
+			return fmt.Errorf("invalid escalation severity '%s'", rule.Severity)
+		}
+		for _, s := range rule.Severities {
+			if !s.IsValid() {
+				return fmt.Errorf("invalid escalation severities '%s'", s)
+			}
+		}
+		if rule.MinBreaches < 0 {
+			return fmt.Errorf("invalid escalation min-breaches '%d'", rule.MinBreaches)
+		}
+	}
+	return nil
+}
+
+// EscalateResults raises the severity of the breaches meeting the escalation
+// rules once all the checks are run, so that rules can apply to groups of
+// breaches across checks. A breach is escalated to the highest severity of the
+// rules it meets, the rules being evaluated against the severities reported by
+// the checks, and its result's severity is raised to match; severities are
+// never lowered.
+func EscalateResults(rl *result.ResultList) {
+	if len(RunConfig.Escalation) == 0 {
+		return
+	}
+
+	type breachRef struct{ r, b int }
+	escalated := map[breachRef]config.Severity{}
+	for _, rule := range RunConfig.Escalation {
+		matched := []breachRef{}
+		for i, r := range rl.Results {
+			if !escalationResultMatches(rule, r) {
+				continue
+			}
+			for j, b := range r.Breaches {
+				if !isResolved(b) && escalationBreachMatches(rule, r, b) {
+					matched = append(matched, breachRef{i, j})
+				}
+			}
+		}
+		if len(matched) == 0 || len(matched) < rule.MinBreaches {
+			continue
+		}
+		for _, ref := range matched {
+			if rule.Severity.Compare(escalated[ref]) > 0 {
+				escalated[ref] = rule.Severity
+			}
+		}
+	}
+
+	changed := false
+	for ref, severity := range escalated {
+		r := &rl.Results[ref.r]
+		b := r.Breaches[ref.b]
+		if severity.Compare(config.Severity(b.GetSeverity())) <= 0 {
+			continue
+		}
+		b.SetCommonValues(b.GetCheckType(), b.GetCheckName(), string(severity))
+		if severity.Compare(config.Severity(r.Severity)) > 0 {
+			r.Severity = string(severity)
+			changed = true
+		}
+	}
+	if changed {
+		rl.BreachCountBySeverity = map[string]int{}
+		for _, r := range rl.Results {
+			rl.BreachCountBySeverity[r.Severity] += len(r.Breaches)
+		}
+	}
+}
+
+// escalationResultMatches determines whether the result's check meets the
+// rule's conditions on the checks.
+func escalationResultMatches(rule config.EscalationRule, r result.Result) bool {
+	if len(rule.Checks) > 0 && !utils.StringSliceMatchAny(rule.Checks, r.Name) {
+		return false
+	}
+	if len(rule.Targets) > 0 && !utils.StringSliceMatchAny(rule.Targets, r.Target) {
+		return false
+	}
+	if len(rule.Tags) > 0 {
+		tagged := false
+		for _, t := range r.Tags {
+			if utils.StringSliceMatchAny(rule.Tags, t) {
+				tagged = true
+				break
+			}
+		}
+		if !tagged {
+			return false
+		}
+	}
+	return true
+}
+
+// escalationBreachMatches determines whether the breach meets the rule's
+// conditions on the breaches.
+func escalationBreachMatches(rule config.EscalationRule, r result.Result, b result.Breach) bool {
+	if len(rule.Severities) > 0 {
+		found := false
+		for _, s := range rule.Severities {
+			if string(s) == b.GetSeverity() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if rule.After != "" {
+		if RunHistory == nil {
+			return false
+		}
+		after, _ := utils.ParseDuration(rule.After)
+		if RunHistory.BreachAge(r, b) < after {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package shipshape_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateEscalation(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ValidateEscalation([]config.EscalationRule{{After: "30d", Severity: config.HighSeverity}}))
+	assert.NoError(ValidateEscalation([]config.EscalationRule{{Tags: []string{"security"}, Severity: config.CriticalSeverity}}))
+	assert.ErrorContains(ValidateEscalation([]config.EscalationRule{{After: "soon", Severity: config.HighSeverity}}),
+		"invalid escalation duration 'soon'")
+	assert.EqualError(ValidateEscalation([]config.EscalationRule{{After: "30d", Severity: "urgent"}}),
+		"invalid escalation severity 'urgent'")
+	assert.EqualError(ValidateEscalation([]config.EscalationRule{{Severities: []config.Severity{"warning"}, Severity: config.HighSeverity}}),
+		"invalid escalation severities 'warning'")
+	assert.EqualError(ValidateEscalation([]config.EscalationRule{{MinBreaches: -1, Severity: config.HighSeverity}}),
+		"invalid escalation min-breaches '-1'")
+}
+
+func TestEscalateResultsAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	utils.TimeNow = func() time.Time { return time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC) }
+
+	newResultList := func() result.ResultList {
+		rl := result.NewResultList(false)
+		rl.AddResult(result.Result{
+			Name:      "a",
+			CheckType: "file",
+			Severity:  "normal",
+			Breaches: []result.Breach{
+				&result.ValueBreach{Severity: "normal", Value: "40 days"},
+				&result.ValueBreach{Severity: "normal", Value: "10 days"},
+				&result.ValueBreach{Severity: "normal", Value: "new"},
+			},
+		})
+		return rl
+	}
+
+	defer func() {
+		RunHistory = nil
+		RunConfig = config.Config{}
+	}()
+	RunConfig.Escalation = []config.EscalationRule{
+		{After: "30d", Severity: config.CriticalSeverity},
+		{After: "7d", Severity: config.HighSeverity},
+	}
+
+	// No history.
+	rl := newResultList()
+	EscalateResults(&rl)
+	assert.Equal(newResultList(), rl)
+
+	RunHistory = &History{FirstSeen: map[string]map[string]time.Time{
+		"file:a": {
+			"40 days": time.Date(2026, 9, 6, 0, 0, 0, 0, time.UTC),
+			"10 days": time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC),
+		},
+	}}
+	EscalateResults(&rl)
+	r := rl.Results[0]
+	assert.Equal("critical", r.Severity)
+	assert.Equal("critical", r.Breaches[0].GetSeverity())
+	assert.Equal("high", r.Breaches[1].GetSeverity())
+	assert.Equal("normal", r.Breaches[2].GetSeverity())
+	assert.Equal(map[string]int{"critical": 3}, rl.BreachCountBySeverity)
+
+	// Severity is never lowered.
+	rl = newResultList()
+	rl.Results[0].Severity = "critical"
+	rl.Results[0].Breaches[1].SetCommonValues("file", "a", "critical")
+	EscalateResults(&rl)
+	assert.Equal("critical", rl.Results[0].Severity)
+	assert.Equal("critical", rl.Results[0].Breaches[1].GetSeverity())
+}
+
+func TestEscalateResultsConditions(t *testing.T) {
+	assert := assert.New(t)
+
+	newResultList := func(lowBreaches int) result.ResultList {
+		rl := result.NewResultList(false)
+		rl.AddResult(result.Result{
+			Name: "headers", CheckType: "http-headers", Severity: "normal", Target: "prod",
+			Tags:     []string{"security"},
+			Breaches: []result.Breach{&result.ValueBreach{Severity: "normal", Value: "no hsts"}},
+		})
+		rl.AddResult(result.Result{
+			Name: "headers", CheckType: "http-headers", Severity: "normal", Target: "staging",
+			Tags:     []string{"security"},
+			Breaches: []result.Breach{&result.ValueBreach{Severity: "normal", Value: "no hsts"}},
+		})
+		for _, name := range []string{"lint", "modules"} {
+			r := result.Result{Name: name, CheckType: "file", Severity: "low"}
+			for i := 0; i < lowBreaches; i++ {
+				r.Breaches = append(r.Breaches, &result.ValueBreach{Severity: "low", Value: name})
+			}
+			rl.AddResult(r)
+		}
+		return rl
+	}
+
+	defer func() { RunConfig = config.Config{} }()
+	RunConfig.Escalation = []config.EscalationRule{
+		{Tags: []string{"security"}, Targets: []string{"glob:prod*"}, Severity: config.CriticalSeverity},
+		{Severities: []config.Severity{config.LowSeverity}, MinBreaches: 10, Severity: config.HighSeverity},
+		// The severities are matched before escalation.
+		{Severities: []config.Severity{config.HighSeverity}, Severity: config.CriticalSeverity},
+	}
+
+	rl := newResultList(4)
+	EscalateResults(&rl)
+	assert.Equal("critical", rl.Results[0].Severity)
+	assert.Equal("critical", rl.Results[0].Breaches[0].GetSeverity())
+	assert.Equal("normal", rl.Results[1].Severity)
+	assert.Equal("low", rl.Results[2].Severity)
+	assert.Equal("low", rl.Results[3].Breaches[0].GetSeverity())
+	assert.Equal(map[string]int{"critical": 1, "normal": 1, "low": 8}, rl.BreachCountBySeverity)
+
+	rl = newResultList(5)
+	EscalateResults(&rl)
+	assert.Equal("high", rl.Results[2].Severity)
+	assert.Equal("high", rl.Results[2].Breaches[4].GetSeverity())
+	assert.Equal("high", rl.Results[3].Severity)
+	assert.Equal(map[string]int{"critical": 1, "normal": 1, "high": 10}, rl.BreachCountBySeverity)
+
+	// Resolved breaches are not escalated.
+	rl = newResultList(0)
+	rl.Results[0].Breaches[0].SetRemediation(result.RemediationStatusSuccess, "fixed")
+	EscalateResults(&rl)
+	assert.Equal("normal", rl.Results[0].Severity)
+
+	RunConfig.Escalation = []config.EscalationRule{{Checks: []string{"lint"}, Severity: config.NormalSeverity}}
+	rl = newResultList(1)
+	EscalateResults(&rl)
+	assert.Equal("normal", rl.Results[2].Severity)
+	assert.Equal("low", rl.Results[3].Severity)
+}
@@ -0,0 +1,102 @@
+package shipshape
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+// ExportFormats is the list of formats the policy inventory can be exported
+// as.
+var ExportFormats = []string{"csv", "json"}
+
+// PolicyEntry is a configured check as listed in the policy inventory.
+type PolicyEntry struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Severity string   `json:"severity"`
+	Target   string   `json:"target,omitempty"`
+	Tags     []string `json:"tags"`
+	Owner    string   `json:"owner"`
+	Controls []string `json:"controls"`
+	// What the check verifies & why its policy exists.
+	Description string `json:"description,omitempty"`
+	Rationale   string `json:"rationale,omitempty"`
+}
+
+// Inventory lists the configured checks along with their governance
+// metadata, without running them.
+func Inventory() []PolicyEntry {
+	inventory := []PolicyEntry{}
+	for ct, checks := range RunConfig.Checks {
+		for _, c := range checks {
+			severity := c.GetSeverity()
+			if severity == "" {
+				severity = config.NormalSeverity
+			}
+			e := PolicyEntry{
+				Name:        c.GetName(),
+				Type:        string(ct),
+				Severity:    string(severity),
+				Target:      c.GetTarget(),
+				Tags:        c.GetTags(),
+				Owner:       c.GetOwner(),
+				Controls:    c.GetControls(),
+				Description: c.GetDescription(),
+				Rationale:   c.GetRationale(),
+			}
+			if e.Tags == nil {
+				e.Tags = []string{}
+			}
+			if e.Controls == nil {
+				e.Controls = []string{}
+			}
+			inventory = append(inventory, e)
+		}
+	}
+	sort.SliceStable(inventory, func(i, j int) bool {
+		if inventory[i].Type != inventory[j].Type {
+			return inventory[i].Type < inventory[j].Type
+		}
+		if inventory[i].Name != inventory[j].Name {
+			return inventory[i].Name < inventory[j].Name
+		}
+		return inventory[i].Target < inventory[j].Target
+	})
+	return inventory
+}
+
+// ExportDisplay generates the output for the policy inventory in the given
+// format; lists are separated by semicolons in csv.
+func ExportDisplay(w *bufio.Writer, inventory []PolicyEntry, format string) error {
+	switch format {
+	case "json":
+		data, err := json.Marshal(inventory)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"name", "type", "severity", "target", "tags", "owner", "controls",
+			"description", "rationale"})
+		for _, e := range inventory {
+			cw.Write([]string{e.Name, e.Type, e.Severity, e.Target,
+				strings.Join(e.Tags, ";"), e.Owner, strings.Join(e.Controls, ";"),
+				e.Description, e.Rationale})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid export format '%s'; needs to be one of: %s",
+			format, strings.Join(ExportFormats, "|"))
+	}
+	return w.Flush()
+}
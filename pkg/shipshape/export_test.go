@@ -0,0 +1,57 @@
+package shipshape_test
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/audit"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventory(t *testing.T) {
+	assert := assert.New(t)
+
+	RunConfig = config.Config{
+		Checks: config.CheckMap{
+			audit.DependencyAudit: {&audit.DependencyAuditCheck{CheckBase: config.CheckBase{
+				Name:      "npm",
+				Severity:  config.HighSeverity,
+				Target:    "web",
+				Tags:      []string{"security", "node"},
+				Owner:     "platform-team",
+				Controls:  []string{"iso27001:A.12.6.1", "soc2:CC7.1"},
+				Rationale: "Vulnerable packages, e.g, with remote code execution, are the most exploited",
+			}}},
+			testchecks.TestCheck1: {&testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "b"}}},
+		},
+	}
+
+	inventory := Inventory()
+	assert.Equal([]PolicyEntry{
+		{Name: "npm", Type: "dependency-audit", Severity: "high", Target: "web",
+			Tags: []string{"security", "node"}, Owner: "platform-team",
+			Controls:  []string{"iso27001:A.12.6.1", "soc2:CC7.1"},
+			Rationale: "Vulnerable packages, e.g, with remote code execution, are the most exploited"},
+		{Name: "b", Type: "test-check-1", Severity: "normal",
+			Tags: []string{}, Controls: []string{}},
+	}, inventory)
+
+	var buf bytes.Buffer
+	assert.NoError(ExportDisplay(bufio.NewWriter(&buf), inventory, "csv"))
+	assert.Equal(`name,type,severity,target,tags,owner,controls,description,rationale
+npm,dependency-audit,high,web,security;node,platform-team,iso27001:A.12.6.1;soc2:CC7.1,,"Vulnerable packages, e.g, with remote code execution, are the most exploited"
+b,test-check-1,normal,,,,,,
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(ExportDisplay(bufio.NewWriter(&buf), inventory[1:], "json"))
+	assert.Equal(`[{"name":"b","type":"test-check-1","severity":"normal","tags":[],"owner":"","controls":[]}]
+`, buf.String())
+
+	assert.EqualError(ExportDisplay(bufio.NewWriter(&buf), inventory, "xml"),
+		"invalid export format 'xml'; needs to be one of: csv|json")
+}
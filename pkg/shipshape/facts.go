@@ -0,0 +1,153 @@
+package shipshape
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// FactsFormats is the list of formats the facts can be displayed as.
+var FactsFormats = []string{"simple", "json", "yaml"}
+
+// CheckFacts is the data fetched by a check, as seen by the check when it is
+// run.
+type CheckFacts struct {
+	Type   string            `json:"type" yaml:"type"`
+	Name   string            `json:"name" yaml:"name"`
+	Target string            `json:"target,omitempty" yaml:"target,omitempty"`
+	Data   map[string]string `json:"data" yaml:"data"`
+	// Sensitive checks have their data left out.
+	Sensitive bool `json:"sensitive,omitempty" yaml:"sensitive,omitempty"`
+	// Errors encountered when fetching the data.
+	Errors []string `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// Facts fetches the data of the checks requiring any, without running them;
+// if only is provided, only the checks with matching names are included.
+func Facts(only []string) []CheckFacts {
+	checks := []config.Check{}
+	for _, cs := range RunConfig.Checks {
+		for _, c := range cs {
+			if !c.RequiresData() {
+				continue
+			}
+			if len(only) > 0 && !utils.StringSliceMatchAny(only, c.GetName()) {
+				continue
+			}
+			checks = append(checks, c)
+		}
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		if checks[i].GetTarget() != checks[j].GetTarget() {
+			return checks[i].GetTarget() < checks[j].GetTarget()
+		}
+		if checks[i].GetType() != checks[j].GetType() {
+			return checks[i].GetType() < checks[j].GetType()
+		}
+		return checks[i].GetName() < checks[j].GetName()
+	})
+
+	facts := []CheckFacts{}
+	for _, c := range checks {
+		facts = append(facts, checkFacts(c))
+	}
+	return facts
+}
+
+func checkFacts(c config.Check) CheckFacts {
+	f := CheckFacts{
+		Type:   string(c.GetType()),
+		Name:   c.GetName(),
+		Target: c.GetTarget(),
+		Data:   map[string]string{},
+	}
+	target := c.GetTarget()
+	if target == "" {
+		target = DefaultTarget
+	}
+	restore, ok := useTarget(target)
+	if !ok {
+		f.Errors = []string{fmt.Sprintf("unknown target '%s'", target)}
+		return f
+	}
+	defer restore()
+
+	c.FetchData()
+	if c.IsSensitive() {
+		f.Sensitive = true
+		c.GetResult().MaskBreaches()
+	} else {
+		dataMap := c.GetDataMap()
+		if m, ok := c.(config.DataMasker); ok {
+			dataMap = m.MaskedDataMap()
+		}
+		for k, v := range dataMap {
+			f.Data[k] = string(v)
+		}
+	}
+	for _, b := range c.GetResult().Breaches {
+		f.Errors = append(f.Errors, b.String())
+	}
+	return f
+}
+
+// FactsDisplay generates the output for the facts in the given format.
+func FactsDisplay(w *bufio.Writer, facts []CheckFacts, format string) error {
+	switch format {
+	case "json":
+		data, err := json.Marshal(facts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(facts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, string(data))
+	case "simple":
+		factsSimpleDisplay(w, facts)
+	default:
+		return fmt.Errorf("invalid facts format '%s'; needs to be one of: %s",
+			format, strings.Join(FactsFormats, "|"))
+	}
+	return w.Flush()
+}
+
+func factsSimpleDisplay(w *bufio.Writer, facts []CheckFacts) {
+	if len(facts) == 0 {
+		fmt.Fprint(w, "No checks fetching data; ensure your shipshape.yml is configured correctly.\n")
+		return
+	}
+
+	prevTarget := ""
+	for i, f := range facts {
+		if f.Target != "" && (i == 0 || f.Target != prevTarget) {
+			fmt.Fprintf(w, "## Target: %s\n\n", f.Target)
+		}
+		prevTarget = f.Target
+		fmt.Fprintf(w, "  ### %s [%s]\n", f.Name, f.Type)
+		for _, e := range f.Errors {
+			fmt.Fprintf(w, "     !! %s\n", e)
+		}
+		if f.Sensitive {
+			fmt.Fprint(w, "     -- data not displayed for sensitive check\n")
+		} else if len(f.Data) == 0 && len(f.Errors) == 0 {
+			fmt.Fprint(w, "     -- no data\n")
+		}
+		for _, k := range utils.SortedKeys(f.Data) {
+			fmt.Fprintf(w, "     -- %s\n", k)
+			for _, line := range strings.Split(strings.TrimRight(f.Data[k], "\n"), "\n") {
+				fmt.Fprintf(w, "        %s\n", line)
+			}
+		}
+		fmt.Fprint(w, "\n")
+	}
+}
@@ -0,0 +1,194 @@
+package shipshape_test
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/audit"
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/env"
+	yamlchecks "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFacts(t *testing.T) {
+	assert := assert.New(t)
+
+	curProjectDir := config.ProjectDir
+	curShellCommander := command.ShellCommander
+	defer func() {
+		config.ProjectDir = curProjectDir
+		command.ShellCommander = curShellCommander
+	}()
+	config.ProjectDir = t.TempDir()
+	os.WriteFile(filepath.Join(config.ProjectDir, "services.yml"), []byte("parameters:\n  debug: true\n"), 0644)
+
+	out := `{"vulnerabilities":{}}`
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(&out, nil, &generatedCommand)
+
+	services := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{CheckBase: config.CheckBase{Name: "services"}},
+		File:     "services.yml",
+	}
+	services.Init(yamlchecks.Yaml)
+	npm := &audit.DependencyAuditCheck{CheckBase: config.CheckBase{Name: "npm", Target: "web"}, Tool: "npm"}
+	npm.Init(audit.DependencyAudit)
+	db := &audit.DependencyAuditCheck{CheckBase: config.CheckBase{Name: "composer", Target: "db"}, Tool: "composer"}
+	db.Init(audit.DependencyAudit)
+	other := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "other"}}
+	other.Init(testchecks.TestCheck1)
+	RunConfig = config.Config{
+		Targets: map[string]config.Target{
+			"web": {Exec: []string{"docker", "exec", "web"}, ProjectDir: "/app"},
+		},
+		Checks: config.CheckMap{
+			audit.DependencyAudit: {npm, db},
+			yamlchecks.Yaml:       {services},
+			testchecks.TestCheck1: {other},
+		},
+	}
+
+	facts := Facts(nil)
+	assert.Len(facts, 4)
+	assert.Equal(CheckFacts{
+		Type: "test-check-1", Name: "other", Data: map[string]string{},
+	}, facts[0])
+	facts = facts[1:]
+	assert.Equal(CheckFacts{
+		Type: "yaml", Name: "services",
+		Data: map[string]string{"services.yml": "parameters:\n  debug: true\n"},
+	}, facts[0])
+	assert.Equal(CheckFacts{
+		Type: "dependency-audit", Name: "composer", Target: "db",
+		Data:   map[string]string{},
+		Errors: []string{"unknown target 'db'"},
+	}, facts[1])
+	assert.Equal("dependency-audit", facts[2].Type)
+	assert.Equal("npm", facts[2].Name)
+	assert.Equal("web", facts[2].Target)
+	assert.Contains(facts[2].Data, "npm")
+	assert.Equal("docker exec web npm --prefix /app audit --json", generatedCommand)
+
+	// The project directory & shell commander are restored.
+	assert.NotEqual("/app", config.ProjectDir)
+
+	facts = Facts([]string{"glob:serv*", "npm"})
+	assert.Len(facts, 2)
+	assert.Equal("services", facts[0].Name)
+	assert.Equal("npm", facts[1].Name)
+}
+
+func TestFactsSecrets(t *testing.T) {
+	assert := assert.New(t)
+
+	curProjectDir := config.ProjectDir
+	defer func() { config.ProjectDir = curProjectDir }()
+	config.ProjectDir = t.TempDir()
+	os.WriteFile(filepath.Join(config.ProjectDir, "settings.yml"), []byte("password: s3cr3t\n"), 0644)
+	t.Setenv("SHIPSHAPE_TEST_TOKEN", "t0k3n")
+
+	settings := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{CheckBase: config.CheckBase{Name: "settings", Sensitive: true}},
+		File:     "settings.yml",
+	}
+	settings.Init(yamlchecks.Yaml)
+	missing := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{CheckBase: config.CheckBase{Name: "missing", Sensitive: true}},
+		File:     "s3cr3t.yml",
+	}
+	missing.Init(yamlchecks.Yaml)
+	vars := &env.VarsCheck{
+		YamlBase: yamlchecks.YamlBase{CheckBase: config.CheckBase{Name: "vars"}},
+		Vars:     []string{"SHIPSHAPE_TEST_*"},
+	}
+	vars.Init(env.Vars)
+	RunConfig = config.Config{
+		Checks: config.CheckMap{
+			yamlchecks.Yaml: {settings, missing},
+			env.Vars:        {vars},
+		},
+	}
+
+	facts := Facts(nil)
+	assert.Len(facts, 3)
+	assert.Equal(CheckFacts{
+		Type: "env-vars", Name: "vars",
+		Data: map[string]string{"env": "SHIPSHAPE_TEST_TOKEN: " + result.MaskValue("t0k3n") + "\n"},
+	}, facts[0])
+	assert.Equal(CheckFacts{
+		Type: "yaml", Name: "missing", Data: map[string]string{}, Sensitive: true,
+		Errors: facts[1].Errors,
+	}, facts[1])
+	assert.Len(facts[1].Errors, 1)
+	assert.NotContains(facts[1].Errors[0], "no such file")
+	assert.Equal(CheckFacts{
+		Type: "yaml", Name: "settings", Data: map[string]string{}, Sensitive: true,
+	}, facts[2])
+
+	var buf bytes.Buffer
+	assert.NoError(FactsDisplay(bufio.NewWriter(&buf), facts[2:], "simple"))
+	assert.Equal(`  ### settings [yaml]
+     -- data not displayed for sensitive check
+
+`, buf.String())
+}
+
+func TestFactsDisplay(t *testing.T) {
+	assert := assert.New(t)
+
+	facts := []CheckFacts{
+		{Type: "yaml", Name: "services", Data: map[string]string{
+			"services.yml": "parameters:\n  debug: true\n"}},
+		{Type: "yaml", Name: "empty", Data: map[string]string{}},
+		{Type: "dependency-audit", Name: "npm", Target: "web",
+			Data: map[string]string{}, Errors: []string{"unknown target 'web'"}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(FactsDisplay(bufio.NewWriter(&buf), facts, "simple"))
+	assert.Equal(`  ### services [yaml]
+     -- services.yml
+        parameters:
+          debug: true
+
+  ### empty [yaml]
+     -- no data
+
+## Target: web
+
+  ### npm [dependency-audit]
+     !! unknown target 'web'
+
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(FactsDisplay(bufio.NewWriter(&buf), facts[:1], "json"))
+	assert.Equal(`[{"type":"yaml","name":"services","data":{"services.yml":"parameters:\n  debug: true\n"}}]
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(FactsDisplay(bufio.NewWriter(&buf), facts[:1], "yaml"))
+	assert.Equal(`- type: yaml
+  name: services
+  data:
+    services.yml: |
+        parameters:
+          debug: true
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(FactsDisplay(bufio.NewWriter(&buf), []CheckFacts{}, "simple"))
+	assert.Equal("No checks fetching data; ensure your shipshape.yml is configured correctly.\n", buf.String())
+
+	assert.EqualError(FactsDisplay(bufio.NewWriter(&buf), facts, "xml"),
+		"invalid facts format 'xml'; needs to be one of: simple|json|yaml")
+}
@@ -0,0 +1,230 @@
+package shipshape
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultFleetConcurrency = 4
+const DefaultFleetStateDir = ".shipshape-fleet"
+
+// DefaultTarget is the target the checks not specifying one are run against;
+// they are run locally if empty.
+var DefaultTarget string
+
+// FleetSleep waits between the start of the targets' runs; it can be
+// overridden in tests.
+var FleetSleep = time.Sleep
+
+// FleetRunner runs the same config against many targets, each in its own
+// shipshape process. The results of each target are kept in the state
+// directory once its run is complete, so that an interrupted fleet run can be
+// resumed without running the completed targets again.
+type FleetRunner struct {
+	// File defining the targets; it is passed to each run as an additional
+	// config file.
+	TargetsFile string
+	Targets     map[string]config.Target
+	// Path to the shipshape binary.
+	Executable string
+	// Arguments passed to each run, e.g, the config files.
+	Args []string
+	// Maximum number of targets run at the same time.
+	Concurrency int
+	// Minimum duration between the start of the targets' runs.
+	Interval time.Duration
+	StateDir string
+}
+
+// FleetRun is the outcome of a target's run.
+type FleetRun struct {
+	Target string
+	// Whether the results were read from a previous, interrupted, fleet run.
+	Resumed bool
+	Error   error
+	Results result.ResultList
+}
+
+// ReadFleetTargets reads the targets from a file using the same format as
+// the targets in the config.
+func ReadFleetTargets(path string) (map[string]config.Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	targets := struct {
+		Targets map[string]config.Target `yaml:"targets"`
+	}{}
+	if err := yaml.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("invalid targets file '%s': %w", path, err)
+	}
+	if len(targets.Targets) == 0 {
+		return nil, fmt.Errorf("no targets defined in '%s'", path)
+	}
+	return targets.Targets, nil
+}
+
+// Run runs the targets which have not completed yet, then returns the
+// outcome of all of them sorted by target. The state directory is removed
+// once all the targets have completed successfully.
+func (f *FleetRunner) Run() ([]FleetRun, error) {
+	if err := os.MkdirAll(f.StateDir, 0755); err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for name := range f.Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	runs := make([]FleetRun, len(names))
+	pending := []int{}
+	for i, name := range names {
+		rl, err := f.readState(name)
+		if err != nil {
+			pending = append(pending, i)
+			continue
+		}
+		log.WithField("target", name).Info("resuming target's completed run")
+		runs[i] = FleetRun{Target: name, Resumed: true, Results: rl}
+	}
+
+	concurrency := f.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultFleetConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for n, i := range pending {
+		if n > 0 && f.Interval > 0 {
+			FleetSleep(f.Interval)
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			runs[i] = f.runTarget(names[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, r := range runs {
+		if r.Error != nil {
+			return runs, nil
+		}
+	}
+	return runs, os.RemoveAll(f.StateDir)
+}
+
+// runTarget runs the checks against the target and saves its results.
+func (f *FleetRunner) runTarget(name string) FleetRun {
+	contextLogger := log.WithField("target", name)
+	contextLogger.Print("running checks against target")
+	run := FleetRun{Target: name}
+
+	args := append(append([]string{}, f.Args...),
+		"-f", f.TargetsFile, "--target", name, "-o", "json", "--no-progress")
+	out, err := command.ShellCommander(f.Executable, args...).Output()
+	if err != nil {
+		run.Error = errors.New(command.GetMsgFromCommandError(err))
+		contextLogger.WithError(run.Error).Error("unable to run checks against target")
+		return run
+	}
+	if err := json.Unmarshal(out, &run.Results); err != nil {
+		run.Error = fmt.Errorf("invalid results: %w", err)
+		contextLogger.WithError(run.Error).Error("unable to run checks against target")
+		return run
+	}
+
+	if err := f.writeState(name, out); err != nil {
+		contextLogger.WithError(err).Warn("unable to save the target's results")
+	}
+	return run
+}
+
+func (f *FleetRunner) stateFile(name string) string {
+	return filepath.Join(f.StateDir, url.PathEscape(name)+".json")
+}
+
+func (f *FleetRunner) readState(name string) (result.ResultList, error) {
+	rl := result.ResultList{}
+	data, err := os.ReadFile(f.stateFile(name))
+	if err != nil {
+		return rl, err
+	}
+	err = json.Unmarshal(data, &rl)
+	return rl, err
+}
+
+// writeState saves the target's results through a temporary file, so that a
+// partially written file is never mistaken for a completed run.
+func (f *FleetRunner) writeState(name string, data []byte) error {
+	tmp := f.stateFile(name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.stateFile(name))
+}
+
+// AggregateFleet merges the results of all the targets into a single list,
+// setting the target of each result to the one it was run against; a target
+// whose run failed is reported as a failing breach.
+func AggregateFleet(runs []FleetRun) result.ResultList {
+	rl := result.NewResultList(false)
+	for _, run := range runs {
+		if run.Error != nil {
+			rl.IncrChecks("fleet", 1)
+			rl.AddResult(result.Result{
+				Name:      "fleet run",
+				CheckType: "fleet",
+				Target:    run.Target,
+				Severity:  string(config.HighSeverity),
+				Status:    result.Fail,
+				DataError: true,
+				Breaches: []result.Breach{&result.ValueBreach{
+					BreachType: result.BreachTypeValue,
+					CheckType:  "fleet",
+					CheckName:  "fleet run",
+					Severity:   string(config.HighSeverity),
+					ValueLabel: "run failed",
+					Value:      run.Error.Error(),
+				}},
+			})
+			rl.FailingBreaches++
+			continue
+		}
+
+		targetRl := run.Results
+		targetRl.Results = make([]result.Result, len(run.Results.Results))
+		for i, r := range run.Results.Results {
+			// Checks run against one of the config's targets keep it.
+			if r.Target != "" {
+				r.Target = run.Target + "/" + r.Target
+			} else {
+				r.Target = run.Target
+			}
+			targetRl.Results[i] = r
+		}
+		rl.Merge(targetRl)
+	}
+	rl.Sort()
+	rl.RemediationTotalsCount()
+	rl.SetReasonCodes()
+	return rl
+}
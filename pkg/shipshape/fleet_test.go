@@ -0,0 +1,191 @@
+package shipshape_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFleetTargets(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	f := filepath.Join(dir, "targets.yml")
+	os.WriteFile(f, []byte(`
+targets:
+  web-1:
+    exec: [ssh, deploy@web-1]
+  app:
+    exec: [kubectl, exec, -i, deploy/app, --]
+    project-dir: /srv/app
+`), 0644)
+	targets, err := ReadFleetTargets(f)
+	assert.NoError(err)
+	assert.Equal(map[string]config.Target{
+		"web-1": {Exec: []string{"ssh", "deploy@web-1"}},
+		"app":   {Exec: []string{"kubectl", "exec", "-i", "deploy/app", "--"}, ProjectDir: "/srv/app"},
+	}, targets)
+
+	os.WriteFile(f, []byte("targets: {}"), 0644)
+	_, err = ReadFleetTargets(f)
+	assert.EqualError(err, "no targets defined in '"+f+"'")
+
+	os.WriteFile(f, []byte("targets: foo"), 0644)
+	_, err = ReadFleetTargets(f)
+	assert.ErrorContains(err, "invalid targets file '"+f+"'")
+
+	_, err = ReadFleetTargets(filepath.Join(dir, "missing.yml"))
+	assert.ErrorIs(err, os.ErrNotExist)
+}
+
+// fleetCommander mocks the runs of the targets, returning a result list with
+// a breach for each target, except the failing ones.
+func fleetCommander(failing map[string]bool, ran *[]string, mu *sync.Mutex) func(string, ...string) command.IShellCommand {
+	return func(name string, arg ...string) command.IShellCommand {
+		target := ""
+		for i := range arg {
+			if arg[i] == "--target" {
+				target = arg[i+1]
+			}
+		}
+		return internal.TestShellCommand{OutputterFunc: func() ([]byte, error) {
+			mu.Lock()
+			*ran = append(*ran, target)
+			mu.Unlock()
+			if failing[target] {
+				return nil, errors.New("connection refused")
+			}
+			rl := result.NewResultList(false)
+			rl.IncrChecks("file", 1)
+			rl.AddResult(result.Result{
+				Name:      "Illegal files",
+				CheckType: "file",
+				Severity:  "high",
+				Status:    result.Fail,
+				Breaches: []result.Breach{&result.ValueBreach{
+					BreachType: result.BreachTypeValue,
+					CheckType:  "file",
+					CheckName:  "Illegal files",
+					Severity:   "high",
+					Value:      target + ".php",
+				}},
+			})
+			rl.FailingBreaches = 1
+			return json.Marshal(rl)
+		}}
+	}
+}
+
+func TestFleetRunnerRun(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+	curSleep := FleetSleep
+	defer func() { FleetSleep = curSleep }()
+	sleeps := []time.Duration{}
+	FleetSleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	ran := []string{}
+	mu := sync.Mutex{}
+	stateDir := filepath.Join(t.TempDir(), "state")
+	runner := &FleetRunner{
+		TargetsFile: "targets.yml",
+		Targets: map[string]config.Target{
+			"web-1": {Exec: []string{"ssh", "web-1"}},
+			"web-2": {Exec: []string{"ssh", "web-2"}},
+			"web-3": {Exec: []string{"ssh", "web-3"}},
+		},
+		Executable:  "shipshape",
+		Args:        []string{"-f", "shipshape.yml"},
+		Concurrency: 2,
+		Interval:    time.Second,
+		StateDir:    stateDir,
+	}
+
+	// The failing target is not saved, so that it is run again when resumed.
+	command.ShellCommander = fleetCommander(map[string]bool{"web-2": true}, &ran, &mu)
+	runs, err := runner.Run()
+	assert.NoError(err)
+	assert.ElementsMatch([]string{"web-1", "web-2", "web-3"}, ran)
+	assert.Equal([]time.Duration{time.Second, time.Second}, sleeps)
+	assert.Len(runs, 3)
+	assert.Equal("web-1", runs[0].Target)
+	assert.NoError(runs[0].Error)
+	assert.Equal(uint32(1), runs[0].Results.TotalBreaches)
+	assert.Equal("web-2", runs[1].Target)
+	assert.EqualError(runs[1].Error, "connection refused")
+	assert.FileExists(filepath.Join(stateDir, "web-1.json"))
+	assert.NoFileExists(filepath.Join(stateDir, "web-2.json"))
+	assert.FileExists(filepath.Join(stateDir, "web-3.json"))
+
+	// Only the failing target is run when resuming, and the state is removed
+	// once all the targets have completed.
+	ran = []string{}
+	command.ShellCommander = fleetCommander(nil, &ran, &mu)
+	runs, err = runner.Run()
+	assert.NoError(err)
+	assert.Equal([]string{"web-2"}, ran)
+	assert.True(runs[0].Resumed)
+	assert.False(runs[1].Resumed)
+	assert.NoError(runs[1].Error)
+	assert.True(runs[2].Resumed)
+	assert.Equal("web-3.php", result.BreachGetValue(runs[2].Results.Results[0].Breaches[0]))
+	assert.NoDirExists(stateDir)
+}
+
+func TestAggregateFleet(t *testing.T) {
+	assert := assert.New(t)
+
+	targetRl := func(target string) result.ResultList {
+		rl := result.NewResultList(false)
+		rl.IncrChecks("file", 2)
+		rl.AddResult(result.Result{Name: "a", CheckType: "file", Severity: "normal", Status: result.Pass})
+		rl.AddResult(result.Result{Name: "b", CheckType: "file", Severity: "normal", Target: target,
+			Status: result.Fail, Breaches: []result.Breach{&result.ValueBreach{Value: "x"}}})
+		rl.FailingBreaches = 1
+		return rl
+	}
+
+	rl := AggregateFleet([]FleetRun{
+		{Target: "web-1", Results: targetRl("")},
+		{Target: "web-2", Results: targetRl("db")},
+		{Target: "web-3", Error: errors.New("connection refused")},
+	})
+	assert.Equal(uint32(5), rl.TotalChecks)
+	assert.Equal(uint32(3), rl.TotalBreaches)
+	assert.Equal(uint32(3), rl.FailingBreaches)
+	assert.Equal(map[string]int{"file": 4, "fleet": 1}, rl.CheckCountByType)
+	assert.Equal(map[string]int{"web-1": 1, "web-2": 0, "web-2/db": 1, "web-3": 1}, rl.BreachCountByTarget)
+	assert.Equal(result.Fail, rl.Status())
+	assert.Equal([]result.ReasonCode{result.ReasonFactError, result.ReasonBreachesHigh}, rl.ReasonCodes)
+
+	targets := []string{}
+	for _, r := range rl.Results {
+		targets = append(targets, r.Target+":"+r.Name)
+	}
+	assert.ElementsMatch([]string{"web-1:a", "web-1:b", "web-2:a", "web-2/db:b", "web-3:fleet run"}, targets)
+	for _, r := range rl.Results {
+		if r.Target == "web-3" {
+			assert.Equal("connection refused", result.BreachGetValue(r.Breaches[0]))
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package shipshape
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultGateInterval = "10s"
+const DefaultGateTimeout = "1h"
+
+type GateStatus string
+
+const (
+	GateApproved GateStatus = "approved"
+	GateRejected GateStatus = "rejected"
+	GatePending  GateStatus = "pending"
+)
+
+// GateSleep waits between polls; it can be overridden in tests.
+var GateSleep = time.Sleep
+
+// GateRequest describes the remediation awaiting approval; it is posted to
+// the gate's url and available to the file template.
+type GateRequest struct {
+	Check     string   `json:"check"`
+	CheckType string   `json:"check-type"`
+	Target    string   `json:"target,omitempty"`
+	Breaches  []string `json:"breaches"`
+}
+
+// ValidateGate verifies the remediation gate is valid.
+func ValidateGate(g *config.RemediationGate) error {
+	if g == nil {
+		return nil
+	}
+	if (g.Url == "") == (g.File == "") {
+		return errors.New("remediation gate requires either a url or a file")
+	}
+	if g.File != "" {
+		if _, err := template.New("gate").Parse(g.File); err != nil {
+			return fmt.Errorf("invalid remediation gate file: %w", err)
+		}
+	}
+	_, _, err := gateDurations(g)
+	return err
+}
+
+func gateDurations(g *config.RemediationGate) (time.Duration, time.Duration, error) {
+	intervalStr, timeoutStr := g.Interval, g.Timeout
+	if intervalStr == "" {
+		intervalStr = DefaultGateInterval
+	}
+	if timeoutStr == "" {
+		timeoutStr = DefaultGateTimeout
+	}
+	interval, err := utils.ParseDuration(intervalStr)
+	if err != nil || interval <= 0 {
+		return 0, 0, fmt.Errorf("invalid remediation gate interval '%s'", intervalStr)
+	}
+	timeout, err := utils.ParseDuration(timeoutStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid remediation gate timeout '%s'", timeoutStr)
+	}
+	return interval, timeout, nil
+}
+
+// PollGate checks once whether the remediation is approved.
+func PollGate(g *config.RemediationGate, req GateRequest) (GateStatus, error) {
+	if g.File != "" {
+		return pollGateFile(g.File, req)
+	}
+	token := g.Token
+	if token == "" && g.TokenEnv != "" {
+		token = os.Getenv(g.TokenEnv)
+	}
+	return pollGateUrl(g.Url, token, req)
+}
+
+func pollGateFile(tmpl string, req GateRequest) (GateStatus, error) {
+	t, err := template.New("gate").Parse(tmpl)
+	if err != nil {
+		return GatePending, err
+	}
+	buf := bytes.Buffer{}
+	if err := t.Execute(&buf, req); err != nil {
+		return GatePending, err
+	}
+	data, err := os.ReadFile(buf.String())
+	if errors.Is(err, os.ErrNotExist) {
+		return GatePending, nil
+	} else if err != nil {
+		return GatePending, err
+	}
+	if strings.TrimSpace(string(data)) == string(GateRejected) {
+		return GateRejected, nil
+	}
+	return GateApproved, nil
+}
+
+func pollGateUrl(url string, token string, req GateRequest) (GateStatus, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return GatePending, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return GatePending, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := utils.HttpClient.Do(httpReq)
+	if err != nil {
+		return GatePending, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return GatePending, fmt.Errorf("gate responded with %s: %s",
+			resp.Status, strings.TrimSpace(string(body)))
+	}
+	var gateResp struct {
+		Status GateStatus `json:"status"`
+	}
+	if err := json.Unmarshal(body, &gateResp); err != nil {
+		return GatePending, fmt.Errorf("invalid gate response: %w", err)
+	}
+	switch gateResp.Status {
+	case GateApproved, GateRejected, GatePending:
+		return gateResp.Status, nil
+	}
+	return GatePending, fmt.Errorf("invalid gate status '%s'", gateResp.Status)
+}
+
+// AwaitRemediationApproval polls the remediation gate, if any, until the
+// check's remediation is approved or rejected, or the timeout is reached.
+// The reason is returned when it is not approved.
+func AwaitRemediationApproval(c config.Check) (bool, string) {
+	g := RunConfig.Remediation.Gate
+	if g == nil {
+		return true, ""
+	}
+	interval, timeout, err := gateDurations(g)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	req := GateRequest{
+		Check:     c.GetName(),
+		CheckType: string(c.GetType()),
+		Target:    c.GetTarget(),
+		Breaches:  []string{},
+	}
+	for _, b := range c.GetResult().Breaches {
+		req.Breaches = append(req.Breaches, b.String())
+	}
+
+	contextLogger := log.WithFields(log.Fields{
+		"check-type": c.GetType(),
+		"check-name": c.GetName(),
+	})
+	var lastErr error
+	for waited := time.Duration(0); ; waited += interval {
+		status, err := PollGate(g, req)
+		if err != nil {
+			lastErr = err
+			contextLogger.WithError(err).Warn("unable to poll the remediation gate")
+		}
+		switch status {
+		case GateApproved:
+			return true, ""
+		case GateRejected:
+			return false, "rejected by the gate"
+		}
+		if waited+interval > timeout {
+			break
+		}
+		contextLogger.Print("awaiting remediation approval")
+		GateSleep(interval)
+	}
+	if lastErr != nil {
+		return false, fmt.Sprintf("not approved within %s: %s", timeout, lastErr)
+	}
+	return false, fmt.Sprintf("not approved within %s", timeout)
+}
+
+// skipRemediation records the breaches as not remediated since the
+// remediation was not approved.
+func skipRemediation(r *result.Result, reason string) {
+	for _, b := range r.Breaches {
+		b.SetRemediation(result.RemediationStatusFailed, "remediation not approved: "+reason)
+	}
+}
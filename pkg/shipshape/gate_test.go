@@ -0,0 +1,187 @@
+package shipshape_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ValidateGate(nil))
+	assert.NoError(ValidateGate(&config.RemediationGate{Url: "https://example.com", Timeout: "2h"}))
+	assert.NoError(ValidateGate(&config.RemediationGate{File: "/tmp/{{ .Check }}", Interval: "1m"}))
+	assert.EqualError(ValidateGate(&config.RemediationGate{}),
+		"remediation gate requires either a url or a file")
+	assert.EqualError(ValidateGate(&config.RemediationGate{Url: "https://example.com", File: "/tmp/ok"}),
+		"remediation gate requires either a url or a file")
+	assert.ErrorContains(ValidateGate(&config.RemediationGate{File: "/tmp/{{ .Check"}),
+		"invalid remediation gate file")
+	assert.EqualError(ValidateGate(&config.RemediationGate{File: "/tmp/ok", Interval: "0s"}),
+		"invalid remediation gate interval '0s'")
+	assert.EqualError(ValidateGate(&config.RemediationGate{File: "/tmp/ok", Timeout: "soon"}),
+		"invalid remediation gate timeout 'soon'")
+}
+
+func TestPollGateFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	g := &config.RemediationGate{File: filepath.Join(dir, "{{ .CheckType }}-{{ .Check }}")}
+	req := GateRequest{Check: "modules", CheckType: "drupal-db-module"}
+
+	status, err := PollGate(g, req)
+	assert.NoError(err)
+	assert.Equal(GatePending, status)
+
+	os.WriteFile(filepath.Join(dir, "drupal-db-module-modules"), []byte("CHG0012345\n"), 0644)
+	status, err = PollGate(g, req)
+	assert.NoError(err)
+	assert.Equal(GateApproved, status)
+
+	os.WriteFile(filepath.Join(dir, "drupal-db-module-modules"), []byte("rejected\n"), 0644)
+	status, err = PollGate(g, req)
+	assert.NoError(err)
+	assert.Equal(GateRejected, status)
+}
+
+func TestPollGateUrl(t *testing.T) {
+	assert := assert.New(t)
+
+	var received GateRequest
+	var auth string
+	response := `{"status":"pending"}`
+	code := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(code)
+		w.Write([]byte(response))
+	}))
+	defer ts.Close()
+
+	t.Setenv("GATE_TOKEN", "s3cr3t")
+	g := &config.RemediationGate{Url: ts.URL, TokenEnv: "GATE_TOKEN"}
+	req := GateRequest{Check: "modules", CheckType: "drupal-db-module", Breaches: []string{"devel"}}
+
+	status, err := PollGate(g, req)
+	assert.NoError(err)
+	assert.Equal(GatePending, status)
+	assert.Equal(req, received)
+	assert.Equal("Bearer s3cr3t", auth)
+
+	response = `{"status":"approved"}`
+	status, err = PollGate(g, req)
+	assert.NoError(err)
+	assert.Equal(GateApproved, status)
+
+	response = `{"status":"maybe"}`
+	status, err = PollGate(g, req)
+	assert.EqualError(err, "invalid gate status 'maybe'")
+	assert.Equal(GatePending, status)
+
+	code = http.StatusForbidden
+	response = "forbidden"
+	_, err = PollGate(g, req)
+	assert.EqualError(err, "gate responded with 403 Forbidden: forbidden")
+
+	// The token can be provided in the config.
+	code = http.StatusOK
+	response = `{"status":"approved"}`
+	g = &config.RemediationGate{Url: ts.URL, Token: "t0k3n", TokenEnv: "GATE_TOKEN"}
+	_, err = PollGate(g, req)
+	assert.NoError(err)
+	assert.Equal("Bearer t0k3n", auth)
+}
+
+func TestAwaitRemediationApproval(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	curSleep := GateSleep
+	defer func() { GateSleep = curSleep }()
+	sleeps := 0
+	dir := t.TempDir()
+	approval := filepath.Join(dir, "approved")
+	GateSleep = func(d time.Duration) {
+		assert.Equal(t, time.Minute, d)
+		sleeps++
+		// Approved after the second poll.
+		if sleeps == 2 {
+			os.WriteFile(approval, nil, 0644)
+		}
+	}
+
+	c := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "test"}}
+	c.Init(testchecks.TestCheck1)
+	c.AddBreach(&result.ValueBreach{Value: "foo"})
+
+	t.Run("noGate", func(t *testing.T) {
+		RunConfig = config.Config{}
+		approved, reason := AwaitRemediationApproval(c)
+		assert.True(t, approved)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("approved", func(t *testing.T) {
+		RunConfig = config.Config{Remediation: config.RemediationConfig{
+			Gate: &config.RemediationGate{File: approval, Interval: "1m", Timeout: "5m"}}}
+		approved, reason := AwaitRemediationApproval(c)
+		assert.True(t, approved)
+		assert.Empty(t, reason)
+		assert.Equal(t, 2, sleeps)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		sleeps = 0
+		RunConfig = config.Config{Remediation: config.RemediationConfig{
+			Gate: &config.RemediationGate{File: filepath.Join(dir, "never"), Interval: "1m", Timeout: "3m"}}}
+		approved, reason := AwaitRemediationApproval(c)
+		assert.False(t, approved)
+		assert.Equal(t, "not approved within 3m0s", reason)
+		assert.Equal(t, 3, sleeps)
+	})
+}
+
+func TestRunChecksRemediationRejected(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	approval := filepath.Join(t.TempDir(), "approval")
+	os.WriteFile(approval, []byte("rejected"), 0644)
+
+	c := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "test1stcheck"}}
+	c.Init(testchecks.TestCheck1)
+	c.SetPerformRemediation(true)
+	RunConfig = config.Config{
+		Checks:      config.CheckMap{testchecks.TestCheck1: {c}},
+		Remediation: config.RemediationConfig{Gate: &config.RemediationGate{File: approval}},
+	}
+	defer func() { RunConfig = config.Config{} }()
+
+	RunResultList = result.NewResultList(true)
+	RunChecks()
+	assert.Equal(result.Remediation{
+		Status:   result.RemediationStatusFailed,
+		Messages: []string{"remediation not approved: rejected by the gate"},
+	}, *RunResultList.Results[0].Breaches[0].GetRemediation())
+}
@@ -0,0 +1,132 @@
+package shipshape
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// History records when the breaches were first seen, so that their age can
+// be determined across runs, along with the checks' last outcome.
+type History struct {
+	// First time each breach was seen, keyed by check then by breach.
+	FirstSeen map[string]map[string]time.Time `json:"first-seen"`
+	// Outcome of each check in the last run it was part of.
+	Checks map[string]CheckRecord `json:"checks,omitempty"`
+}
+
+// CheckRecord is the outcome of a check in the last run it was part of.
+type CheckRecord struct {
+	Status   result.Status `json:"status"`
+	Breaches []string      `json:"breaches,omitempty"`
+	LastRun  time.Time     `json:"last-run"`
+	// Zero if the check never passed since it was first recorded.
+	LastPassed time.Time `json:"last-passed"`
+}
+
+// RunHistory is the history of the previous runs; breaches are not
+// escalated when it is nil.
+var RunHistory *History
+
+// ReadHistory reads the history from the file, returning an empty history
+// if it does not exist yet.
+func ReadHistory(path string) (*History, error) {
+	h := &History{
+		FirstSeen: map[string]map[string]time.Time{},
+		Checks:    map[string]CheckRecord{},
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("invalid history file '%s': %w", path, err)
+	}
+	if h.FirstSeen == nil {
+		h.FirstSeen = map[string]map[string]time.Time{}
+	}
+	if h.Checks == nil {
+		h.Checks = map[string]CheckRecord{}
+	}
+	return h, nil
+}
+
+// Write saves the history to the file.
+func (h *History) Write(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Update records the breaches of the results not seen before and forgets
+// the ones which have been resolved, along with the checks' outcome; checks
+// which did not run, including the skipped ones, are left as-is.
+func (h *History) Update(rl result.ResultList) {
+	now := utils.TimeNow()
+	if h.Checks == nil {
+		h.Checks = map[string]CheckRecord{}
+	}
+	for _, r := range rl.Results {
+		if r.Status == result.Skipped {
+			continue
+		}
+		key := historyCheckKey(r)
+		record := CheckRecord{
+			Status:     r.Status,
+			LastRun:    now,
+			LastPassed: h.Checks[key].LastPassed,
+		}
+		if r.Status == result.Pass {
+			record.LastPassed = now
+		}
+		for _, b := range r.Breaches {
+			record.Breaches = append(record.Breaches, b.String())
+		}
+		h.Checks[key] = record
+
+		seen := map[string]time.Time{}
+		for _, b := range r.Breaches {
+			if isResolved(b) {
+				continue
+			}
+			if t, ok := h.FirstSeen[key][b.String()]; ok {
+				seen[b.String()] = t
+			} else {
+				seen[b.String()] = now
+			}
+		}
+		if len(seen) == 0 {
+			delete(h.FirstSeen, key)
+			continue
+		}
+		h.FirstSeen[key] = seen
+	}
+}
+
+// BreachAge determines for how long the breach has been unresolved; it is
+// zero for a breach not seen before.
+func (h *History) BreachAge(r result.Result, b result.Breach) time.Duration {
+	t, ok := h.FirstSeen[historyCheckKey(r)][b.String()]
+	if !ok {
+		return 0
+	}
+	return utils.TimeNow().Sub(t)
+}
+
+func historyCheckKey(r result.Result) string {
+	return config.PreviousResultKey(r.CheckType, r.Name, r.Target)
+}
+
+func isResolved(b result.Breach) bool {
+	return b.GetRemediation().Status == result.RemediationStatusSuccess
+}
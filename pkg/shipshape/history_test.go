@@ -0,0 +1,86 @@
+package shipshape_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWriteHistory(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	h, err := ReadHistory(path)
+	assert.NoError(err)
+	assert.Equal(&History{
+		FirstSeen: map[string]map[string]time.Time{},
+		Checks:    map[string]CheckRecord{},
+	}, h)
+
+	firstSeen := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	h.FirstSeen["file:foo"] = map[string]time.Time{"bar": firstSeen}
+	assert.NoError(h.Write(path))
+	h, err = ReadHistory(path)
+	assert.NoError(err)
+	assert.Equal(firstSeen, h.FirstSeen["file:foo"]["bar"])
+
+	assert.NoError(os.WriteFile(path, []byte("{"), 0644))
+	_, err = ReadHistory(path)
+	assert.ErrorContains(err, "invalid history file")
+}
+
+func TestHistoryUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	curTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = curTimeNow }()
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	utils.TimeNow = func() time.Time { return now }
+
+	firstSeen := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	h := &History{FirstSeen: map[string]map[string]time.Time{
+		"file:a":     {"old": firstSeen, "resolved": firstSeen},
+		"file:b@web": {"old": firstSeen},
+		"file:c":     {"old": firstSeen},
+	}}
+	rl := result.NewResultList(false)
+	rl.Results = []result.Result{
+		{
+			Name:      "a",
+			CheckType: "file",
+			Breaches: []result.Breach{
+				&result.ValueBreach{Value: "old"},
+				&result.ValueBreach{Value: "new"},
+				&result.ValueBreach{Value: "remediated",
+					Remediation: result.Remediation{Status: result.RemediationStatusSuccess}},
+			},
+		},
+		{Name: "b", CheckType: "file", Target: "web", Status: result.Pass},
+		{Name: "c", CheckType: "file", Status: result.Skipped, SkipReason: result.SkipReasonOffline},
+	}
+	rl.Results[0].Status = result.Fail
+	h.Checks = map[string]CheckRecord{
+		"file:a": {Status: result.Pass, LastRun: firstSeen, LastPassed: firstSeen},
+		"file:c": {Status: result.Fail, LastRun: firstSeen},
+	}
+	h.Update(rl)
+	assert.Equal(map[string]map[string]time.Time{
+		"file:a": {"old": firstSeen, "new": now},
+		// Checks which did not run or were skipped are kept.
+		"file:c": {"old": firstSeen},
+	}, h.FirstSeen)
+	assert.Equal(map[string]CheckRecord{
+		"file:a": {Status: result.Fail, Breaches: []string{"old", "new", "remediated"},
+			LastRun: now, LastPassed: firstSeen},
+		"file:b@web": {Status: result.Pass, LastRun: now, LastPassed: now},
+		"file:c":     {Status: result.Fail, LastRun: firstSeen},
+	}, h.Checks)
+	assert.Equal(45*24*time.Hour, h.BreachAge(rl.Results[0], &result.ValueBreach{Value: "old"}))
+	assert.Equal(time.Duration(0), h.BreachAge(rl.Results[0], &result.ValueBreach{Value: "unknown"}))
+}
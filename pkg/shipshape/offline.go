@@ -0,0 +1,34 @@
+package shipshape
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Offline skips the checks requiring network access, e.g, in air-gapped
+// environments, instead of having them fail.
+var Offline bool
+
+// skipNetworkChecks records the checks requiring network access as skipped
+// in offline mode, returning the checks which can run.
+func skipNetworkChecks(checks []config.Check) []config.Check {
+	if !Offline {
+		return checks
+	}
+	toRun := []config.Check{}
+	for _, c := range checks {
+		nr, ok := c.(config.NetworkRequirer)
+		if !ok || !nr.RequiresNetwork() {
+			toRun = append(toRun, c)
+			continue
+		}
+		log.WithFields(log.Fields{
+			"check-type": c.GetType(),
+			"check-name": c.GetName(),
+		}).Print("skipping check since it requires network access")
+		skipCheck(c, result.Skipped, result.SkipReasonOffline, "skipped since it requires network access in offline mode")
+	}
+	return toRun
+}
@@ -0,0 +1,90 @@
+package shipshape_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type networkCheck struct {
+	testchecks.TestCheck1Check `yaml:",inline"`
+}
+
+func (c *networkCheck) RequiresNetwork() bool { return true }
+
+func TestRunChecksOffline(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	defer func() {
+		Offline = false
+		RunConfig = config.Config{}
+	}()
+
+	runChecks := func(offline bool) map[string]result.Result {
+		network := &networkCheck{}
+		yaml.Unmarshal([]byte("name: network"), network)
+		network.Init(testchecks.TestCheck1)
+		dependent := &testchecks.TestCheck2Check{}
+		yaml.Unmarshal([]byte("name: dependent\ndepends-on: [network]"), dependent)
+		dependent.Init(testchecks.TestCheck2)
+		local := &testchecks.TestCheck2Check{}
+		yaml.Unmarshal([]byte("name: local"), local)
+		local.Init(testchecks.TestCheck2)
+		RunConfig = config.Config{
+			Checks: config.CheckMap{
+				testchecks.TestCheck1: {network},
+				testchecks.TestCheck2: {dependent, local},
+			},
+		}
+
+		Offline = offline
+		RunResultList = result.NewResultList(false)
+		RunChecks()
+		results := map[string]result.Result{}
+		for _, r := range RunResultList.Results {
+			results[r.Name] = r
+		}
+		return results
+	}
+
+	t.Run("online", func(t *testing.T) {
+		assert := assert.New(t)
+		results := runChecks(false)
+		assert.Equal(result.Fail, results["network"].Status)
+		assert.NotEmpty(results["network"].Breaches)
+		assert.Equal(result.Skipped, results["dependent"].Status)
+		assert.Equal(result.Fail, results["local"].Status)
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		assert := assert.New(t)
+		results := runChecks(true)
+		assert.Equal(uint32(3), RunResultList.TotalChecks)
+
+		assert.Equal(result.Skipped, results["network"].Status)
+		assert.Equal(result.SkipReasonOffline, results["network"].SkipReason)
+		assert.Empty(results["network"].Breaches)
+		assert.Equal([]string{"skipped since it requires network access in offline mode"},
+			results["network"].Warnings)
+
+		assert.Equal(result.Skipped, results["dependent"].Status)
+		assert.Equal(result.SkipReasonDependency, results["dependent"].SkipReason)
+		assert.Empty(results["dependent"].Breaches)
+		assert.Equal([]string{"skipped since its dependency 'network' did not pass"},
+			results["dependent"].Warnings)
+
+		// Checks not requiring network access still run.
+		assert.Equal(result.Fail, results["local"].Status)
+		assert.NotEmpty(results["local"].Breaches)
+	})
+}
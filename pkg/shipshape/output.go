@@ -4,11 +4,76 @@ import (
 	"bufio"
 	"encoding/xml"
 	"fmt"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"text/template"
+	"time"
 
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
 )
 
+// OnlyFailures limits the table & simple outputs to the failing checks.
+var OnlyFailures bool
+
+// MaxBreachesPerCheck limits the number of breaches listed for each check in
+// the table & simple outputs, the others being summarised; all the breaches
+// are listed if 0.
+var MaxBreachesPerCheck int
+
+// JUnitErrorSeverity is the severity from which breaches are reported as
+// errors in the JUnit output, those below it being reported as failures; all
+// breaches are errors if empty.
+var JUnitErrorSeverity config.Severity
+
+// limitBreaches returns the breach lines to list, summarising the ones above
+// MaxBreachesPerCheck as "+X more".
+func limitBreaches(breaches []result.Breach) []string {
+	lines := []string{}
+	for i, b := range breaches {
+		if MaxBreachesPerCheck > 0 && i == MaxBreachesPerCheck {
+			lines = append(lines, fmt.Sprintf("+%d more", len(breaches)-i))
+			break
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// breachDocsLines returns the distinct remediation hints & doc urls of the
+// breaches, pointing readers to how to fix them.
+func breachDocsLines(breaches []result.Breach) []string {
+	hints, urls := []string{}, []string{}
+	for _, b := range breaches {
+		if h := b.GetRemediationHint(); h != "" && !utils.StringSliceContains(hints, h) {
+			hints = append(hints, h)
+		}
+		if u := b.GetDocUrl(); u != "" && !utils.StringSliceContains(urls, u) {
+			urls = append(urls, u)
+		}
+	}
+	lines := []string{}
+	for _, h := range hints {
+		lines = append(lines, "hint: "+h)
+	}
+	for _, u := range urls {
+		lines = append(lines, "docs: "+u)
+	}
+	return lines
+}
+
+// statusLabel returns the result's status, along with the reason it was not
+// run if skipped, e.g, "Skipped (offline)".
+func statusLabel(r result.Result) string {
+	if r.SkipReason == "" {
+		return string(r.Status)
+	}
+	return fmt.Sprintf("%s (%s)", r.Status, r.SkipReason)
+}
+
 // TableDisplay generates the tabular output for the ResultList.
 func TableDisplay(w *tabwriter.Writer) {
 	var linePass, lineFail string
@@ -19,21 +84,44 @@ func TableDisplay(w *tabwriter.Writer) {
 		return
 	}
 
-	fmt.Fprintf(w, "NAME\tSTATUS\tPASSES\tFAILS\n")
+	// Results are grouped per target when checks were run against targets.
+	hasTargets := RunResultList.HasTargets()
+	prevTarget := ""
+	if hasTargets {
+		fmt.Fprintf(w, "TARGET\t")
+	}
+	fmt.Fprintf(w, "NAME\tSTATUS\tDURATION\tPASSES\tFAILS\n")
+	first := true
 	for _, r := range RunResultList.Results {
+		if OnlyFailures && r.Status != result.Fail {
+			continue
+		}
+		if hasTargets {
+			lineTarget := ""
+			if first || r.Target != prevTarget {
+				lineTarget = r.Target
+				if lineTarget == "" {
+					lineTarget = "local"
+				}
+			}
+			prevTarget = r.Target
+			fmt.Fprintf(w, "%s\t", lineTarget)
+		}
+		first = false
 		linePass = ""
 		lineFail = ""
+		fails := limitBreaches(r.Breaches)
 		if len(r.Passes) > 0 {
 			linePass = r.Passes[0]
 		}
-		if len(r.Breaches) > 0 {
-			lineFail = r.Breaches[0].String()
+		if len(fails) > 0 {
+			lineFail = fails[0]
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, r.Status, linePass, lineFail)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Name, statusLabel(r), FormatDuration(r.Duration), linePass, lineFail)
 
-		if len(r.Passes) > 1 || len(r.Breaches) > 1 {
+		if len(r.Passes) > 1 || len(fails) > 1 {
 			numPasses := len(r.Passes)
-			numFailures := len(r.Breaches)
+			numFailures := len(fails)
 
 			// How many additional lines?
 			numAddLines := numPasses
@@ -48,9 +136,12 @@ func TableDisplay(w *tabwriter.Writer) {
 					linePass = r.Passes[i]
 				}
 				if numFailures > i {
-					lineFail = r.Breaches[i].String()
+					lineFail = fails[i]
 				}
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "", "", linePass, lineFail)
+				if hasTargets {
+					fmt.Fprintf(w, "\t")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "", "", "", linePass, lineFail)
 			}
 		}
 	}
@@ -65,12 +156,28 @@ func SimpleDisplay(w *bufio.Writer) {
 		return
 	}
 
+	// Results are grouped per target when checks were run against targets.
+	hasTargets := RunResultList.HasTargets()
+	prevTarget := ""
+	printTarget := func(target string) {
+		if !hasTargets || target == prevTarget {
+			return
+		}
+		prevTarget = target
+		if target == "" {
+			target = "local"
+		}
+		fmt.Fprintf(w, "## Target: %s\n\n", target)
+	}
+
 	printRemediations := func() {
+		prevTarget = ""
 		for _, r := range RunResultList.Results {
 			_, successful, _, _ := r.RemediationsCount()
 			if successful == 0 {
 				continue
 			}
+			printTarget(r.Target)
 			fmt.Fprintf(w, "  ### %s\n", r.Name)
 			for _, b := range r.Breaches {
 				if b.GetRemediation().Status != result.RemediationStatusSuccess {
@@ -79,7 +186,38 @@ func SimpleDisplay(w *bufio.Writer) {
 				for _, msg := range b.GetRemediation().Messages {
 					fmt.Fprintf(w, "     -- %s\n", msg)
 				}
+				if diff := b.GetRemediation().Diff; diff != "" {
+					fmt.Fprint(w, "\n")
+					for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+						fmt.Fprintf(w, "        %s\n", line)
+					}
+					fmt.Fprint(w, "\n")
+				}
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	// Skipped checks are listed last, so that audits can tell them apart
+	// from the checks which are not configured.
+	printSkipped := func() {
+		prevTarget = ""
+		header := false
+		for _, r := range RunResultList.Results {
+			if r.SkipReason == "" || (OnlyFailures && r.Status != result.Fail) {
+				continue
+			}
+			if !header {
+				fmt.Fprint(w, "\n# Skipped checks\n\n")
+				header = true
+			}
+			printTarget(r.Target)
+			fmt.Fprintf(w, "  ### %s\n", r.Name)
+			msg := ""
+			if len(r.Warnings) > 0 {
+				msg = ": " + r.Warnings[len(r.Warnings)-1]
 			}
+			fmt.Fprintf(w, "     -- %s%s\n", r.SkipReason, msg)
 			fmt.Fprintln(w)
 		}
 	}
@@ -104,36 +242,65 @@ func SimpleDisplay(w *bufio.Writer) {
 		case result.RemediationStatusSuccess:
 			fmt.Fprintf(w, "Breaches were detected but were all fixed successfully!\n\n")
 			printRemediations()
+			printSkipped()
 			w.Flush()
 			return
 		}
 	} else if RunResultList.Status() == result.Pass {
-		fmt.Fprint(w, "Ship is in top shape; no breach detected!\n")
-		w.Flush()
-		return
-	}
-
-	if !RunResultList.RemediationPerformed {
+		if RunResultList.InformationalBreaches == 0 {
+			fmt.Fprint(w, "Ship is in top shape; no breach detected!\n")
+			printSkipped()
+			w.Flush()
+			return
+		}
+		if OnlyFailures {
+			fmt.Fprint(w, "Ship is in top shape; only breaches below the fail severity were detected.\n")
+			w.Flush()
+			return
+		}
+		fmt.Fprint(w, "Ship is in top shape; only breaches below the fail severity were detected.\n\n")
+		fmt.Fprint(w, "# Informational breaches\n\n")
+	} else if !RunResultList.RemediationPerformed {
 		fmt.Fprint(w, "# Breaches were detected\n\n")
 	}
 
+	prevTarget = ""
 	for _, r := range RunResultList.Results {
 		if len(r.Breaches) == 0 || r.RemediationStatus == result.RemediationStatusSuccess {
 			continue
 		}
+		if OnlyFailures && r.Status != result.Fail {
+			continue
+		}
+		printTarget(r.Target)
 		fmt.Fprintf(w, "  ### %s\n", r.Name)
+		breaches := []result.Breach{}
 		for _, b := range r.Breaches {
 			if b.GetRemediation().Status == result.RemediationStatusSuccess {
 				continue
 			}
-			fmt.Fprintf(w, "     -- %s\n", b)
+			breaches = append(breaches, b)
+		}
+		for _, line := range limitBreaches(breaches) {
+			fmt.Fprintf(w, "     -- %s\n", line)
+		}
+		for _, line := range breachDocsLines(breaches) {
+			fmt.Fprintf(w, "     %s\n", line)
 		}
 		fmt.Fprintln(w)
 	}
+	printSkipped()
 	w.Flush()
 }
 
-// JUnit outputs the checks results in the JUnit XML format.
+// FormatDuration formats a duration in seconds, rounded to the millisecond.
+func FormatDuration(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond).String()
+}
+
+// JUnit outputs the checks results in the JUnit XML format. Breaches are
+// reported as errors or failures depending on JUnitErrorSeverity, with their
+// severity as type.
 func JUnit(w *bufio.Writer) {
 	tss := JUnitTestSuites{
 		Tests:      RunResultList.TotalChecks,
@@ -141,29 +308,98 @@ func JUnit(w *bufio.Writer) {
 		TestSuites: []JUnitTestSuite{},
 	}
 
-	// Create a JUnitTestSuite for each CheckType.
+	// Create a JUnitTestSuite for each CheckType, or for each target &
+	// CheckType when checks are run against targets.
+	// The suites are sorted by CheckType so that the report is the same
+	// across runs.
+	// The skipped checks are reported alongside the ones which ran.
+	allChecks := config.CheckMap{}
 	for ct, checks := range RunConfig.Checks {
-		ts := JUnitTestSuite{
-			Name:      string(ct),
-			Tests:     RunResultList.CheckCountByType[string(ct)],
-			Errors:    RunResultList.BreachCountByType[string(ct)],
-			TestCases: []JUnitTestCase{},
+		allChecks[ct] = append(allChecks[ct], checks...)
+	}
+	for _, s := range skippedChecks {
+		ct := s.check.GetType()
+		allChecks[ct] = append(allChecks[ct], s.check)
+	}
+	cts := []string{}
+	for ct := range allChecks {
+		cts = append(cts, string(ct))
+	}
+	sort.Strings(cts)
+	for _, name := range cts {
+		ct := config.CheckType(name)
+		checks := allChecks[ct]
+		checksByTarget := map[string][]config.Check{}
+		targets := []string{}
+		for _, c := range checks {
+			if _, ok := checksByTarget[c.GetTarget()]; !ok {
+				targets = append(targets, c.GetTarget())
+			}
+			checksByTarget[c.GetTarget()] = append(checksByTarget[c.GetTarget()], c)
 		}
+		sort.Strings(targets)
 
-		// Create a JUnitTestCase for each Check.
-		for _, c := range checks {
-			tc := JUnitTestCase{
-				Name:      c.GetName(),
-				ClassName: c.GetName(),
-				Errors:    []JUnitError{},
+		for _, target := range targets {
+			ts := JUnitTestSuite{
+				Name:      string(ct),
+				Tests:     RunResultList.CheckCountByType[string(ct)],
+				Errors:    RunResultList.BreachCountByType[string(ct)],
+				TestCases: []JUnitTestCase{},
+			}
+			if target != "" {
+				ts.Name = target + "/" + string(ct)
+				ts.Tests = len(checksByTarget[target])
+				ts.Errors = 0
 			}
 
-			for _, b := range RunResultList.GetBreachesByCheckName(c.GetName()) {
-				tc.Errors = append(tc.Errors, JUnitError{Message: b.String()})
+			// Create a JUnitTestCase for each Check.
+			suiteTime := float64(0)
+			for _, c := range checksByTarget[target] {
+				tc := JUnitTestCase{
+					Name:      c.GetName(),
+					ClassName: c.GetName(),
+					Errors:    []JUnitError{},
+				}
+				if d := c.GetResult().Duration; d > 0 {
+					tc.Time = fmt.Sprintf("%.3f", d)
+					suiteTime += d
+				}
+				if r := c.GetResult(); r.SkipReason != "" {
+					msg := string(r.SkipReason)
+					if len(r.Warnings) > 0 {
+						msg += ": " + r.Warnings[len(r.Warnings)-1]
+					}
+					tc.Skipped = &JUnitSkipped{Message: msg}
+					ts.Skipped++
+				}
+
+				breaches := RunResultList.GetBreachesByCheckName(c.GetName())
+				if target != "" {
+					breaches = RunResultList.GetBreachesByCheckNameAndTarget(c.GetName(), target)
+					ts.Errors += len(breaches)
+				}
+				for _, b := range breaches {
+					if JUnitErrorSeverity != "" &&
+						config.Severity(b.GetSeverity()).Compare(JUnitErrorSeverity) < 0 {
+						tc.Failures = append(tc.Failures, JUnitFailure{
+							Message: b.String(), Type: b.GetSeverity()})
+						continue
+					}
+					tc.Errors = append(tc.Errors, JUnitError{
+						Message: b.String(), Type: b.GetSeverity()})
+				}
+				ts.Failures += len(tc.Failures)
+				ts.TestCases = append(ts.TestCases, tc)
+			}
+			if suiteTime > 0 {
+				ts.Time = fmt.Sprintf("%.3f", suiteTime)
 			}
-			ts.TestCases = append(ts.TestCases, tc)
+			// The errors counted so far include all the breaches.
+			ts.Errors -= ts.Failures
+			tss.Failures += uint32(ts.Failures)
+			tss.Errors -= uint32(ts.Failures)
+			tss.TestSuites = append(tss.TestSuites, ts)
 		}
-		tss.TestSuites = append(tss.TestSuites, ts)
 	}
 
 	xmlBytes, err := xml.MarshalIndent(tss, "", "    ")
@@ -177,3 +413,32 @@ func JUnit(w *bufio.Writer) {
 	fmt.Fprintln(w)
 	w.Flush()
 }
+
+// ParseOutputTemplate parses the template for the template output format.
+// Besides the result.BreachTemplateFuncs, the template can use the `join`,
+// `formatDuration` and `previousResult` functions.
+func ParseOutputTemplate(name string, tmpl string) (*template.Template, error) {
+	funcs := template.FuncMap{
+		"join":           strings.Join,
+		"formatDuration": FormatDuration,
+		"previousResult": PreviousResult,
+	}
+	for k, f := range result.BreachTemplateFuncs {
+		funcs[k] = f
+	}
+	return template.New(name).Funcs(funcs).Parse(tmpl)
+}
+
+// TemplateDisplay renders the template with the ResultList.
+func TemplateDisplay(w *bufio.Writer, t *template.Template) error {
+	if err := t.Execute(w, &RunResultList); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// RunPostCommand runs an output's post-command hook through the shell, with
+// the path of the written file as its first argument.
+func RunPostCommand(cmd string, path string) ([]byte, error) {
+	return command.ShellCommander("sh", "-c", cmd, "shipshape", path).Output()
+}
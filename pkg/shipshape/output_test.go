@@ -6,7 +6,9 @@ import (
 	"testing"
 	"text/tabwriter"
 
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/internal"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
 	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
 
@@ -27,8 +29,8 @@ func TestTableDisplay(t *testing.T) {
 	buf = bytes.Buffer{}
 	RunResultList = result.ResultList{Results: []result.Result{{Name: "a", Status: result.Pass}}}
 	TableDisplay(w)
-	assert.Equal("NAME   STATUS   PASSES   FAILS\n"+
-		"a      Pass              \n", buf.String())
+	assert.Equal("NAME   STATUS   DURATION   PASSES   FAILS\n"+
+		"a      Pass     0s                  \n", buf.String())
 
 	buf = bytes.Buffer{}
 	RunResultList = result.ResultList{
@@ -39,24 +41,26 @@ func TestTableDisplay(t *testing.T) {
 		},
 	}
 	TableDisplay(w)
-	assert.Equal("NAME   STATUS   PASSES   FAILS\n"+
-		"a      Pass              \n"+
-		"b      Pass              \n"+
-		"c      Pass              \n",
+	assert.Equal("NAME   STATUS   DURATION   PASSES   FAILS\n"+
+		"a      Pass     0s                  \n"+
+		"b      Pass     0s                  \n"+
+		"c      Pass     0s                  \n",
 		buf.String())
 
 	buf = bytes.Buffer{}
 	RunResultList = result.ResultList{
 		Results: []result.Result{
 			{
-				Name:   "a",
-				Status: result.Pass,
-				Passes: []string{"Pass a", "Pass ab"},
+				Name:     "a",
+				Status:   result.Pass,
+				Passes:   []string{"Pass a", "Pass ab"},
+				Duration: 1.2345,
 			},
 			{
-				Name:   "b",
-				Status: result.Pass,
-				Passes: []string{"Pass b", "Pass bb", "Pass bc"},
+				Name:     "b",
+				Status:   result.Pass,
+				Passes:   []string{"Pass b", "Pass bb", "Pass bc"},
+				Duration: 0.0123,
 			},
 			{
 				Name:   "c",
@@ -78,16 +82,51 @@ func TestTableDisplay(t *testing.T) {
 		},
 	}
 	TableDisplay(w)
-	assert.Equal("NAME   STATUS   PASSES    FAILS\n"+
-		"a      Pass     Pass a    \n"+
-		"                Pass ab   \n"+
-		"b      Pass     Pass b    \n"+
-		"                Pass bb   \n"+
-		"                Pass bc   \n"+
-		"c      Fail               Fail c\n"+
-		"                          Fail cb\n"+
-		"d      Fail     Pass d    Fail c\n"+
-		"                Pass db   Fail cb\n",
+	assert.Equal("NAME   STATUS   DURATION   PASSES    FAILS\n"+
+		"a      Pass     1.235s     Pass a    \n"+
+		"                           Pass ab   \n"+
+		"b      Pass     12ms       Pass b    \n"+
+		"                           Pass bb   \n"+
+		"                           Pass bc   \n"+
+		"c      Fail     0s                   Fail c\n"+
+		"                                     Fail cb\n"+
+		"d      Fail     0s         Pass d    Fail c\n"+
+		"                           Pass db   Fail cb\n",
+		buf.String())
+
+	buf = bytes.Buffer{}
+	RunResultList = result.ResultList{
+		Results: []result.Result{
+			{Name: "a", Status: result.Pass},
+			{Name: "a", Target: "web", Status: result.Pass},
+			{
+				Name:     "b",
+				Target:   "web",
+				Status:   result.Fail,
+				Breaches: []result.Breach{&result.ValueBreach{Value: "Fail b"}},
+				Passes:   []string{"Pass b", "Pass bb"},
+			},
+		},
+	}
+	TableDisplay(w)
+	assert.Equal("TARGET   NAME   STATUS   DURATION   PASSES    FAILS\n"+
+		"local    a      Pass     0s                   \n"+
+		"web      a      Pass     0s                   \n"+
+		"         b      Fail     0s         Pass b    Fail b\n"+
+		"                                    Pass bb   \n",
+		buf.String())
+
+	buf = bytes.Buffer{}
+	RunResultList = result.ResultList{
+		Results: []result.Result{
+			{Name: "a", Status: result.Pass},
+			{Name: "b", Status: result.Skipped, SkipReason: result.SkipReasonOffline},
+		},
+	}
+	TableDisplay(w)
+	assert.Equal("NAME   STATUS              DURATION   PASSES   FAILS\n"+
+		"a      Pass                0s                  \n"+
+		"b      Skipped (offline)   0s                  \n",
 		buf.String())
 }
 
@@ -113,6 +152,41 @@ func TestSimpleDisplay(t *testing.T) {
 		assert.Equal("Ship is in top shape; no breach detected!\n", buf.String())
 	})
 
+	t.Run("skippedChecks", func(t *testing.T) {
+		RunResultList = result.NewResultList(false)
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		RunResultList.Results = append(RunResultList.Results,
+			result.Result{Name: "a", Status: result.Pass},
+			result.Result{
+				Name:       "b",
+				Status:     result.Skipped,
+				SkipReason: result.SkipReasonDisabled,
+				Warnings:   []string{"skipped since it is disabled in the config"},
+			},
+		)
+		SimpleDisplay(w)
+		assert.Equal("Ship is in top shape; no breach detected!\n\n"+
+			"# Skipped checks\n\n  ### b\n"+
+			"     -- config-disabled: skipped since it is disabled in the config\n\n",
+			buf.String())
+	})
+
+	t.Run("informationalBreaches", func(t *testing.T) {
+		RunResultList = result.NewResultList(false)
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		RunResultList.Results = append(RunResultList.Results, result.Result{
+			Name:     "b",
+			Status:   result.Pass,
+			Breaches: []result.Breach{&result.ValueBreach{Value: "Fail b"}},
+		})
+		RunResultList.InformationalBreaches = 1
+		SimpleDisplay(w)
+		assert.Equal("Ship is in top shape; only breaches below the fail severity were detected.\n\n"+
+			"# Informational breaches\n\n  ### b\n     -- Fail b\n\n", buf.String())
+	})
+
 	t.Run("breachesDetected", func(t *testing.T) {
 		RunResultList = result.NewResultList(false)
 		var buf bytes.Buffer
@@ -128,6 +202,59 @@ func TestSimpleDisplay(t *testing.T) {
 		assert.Equal("# Breaches were detected\n\n  ### b\n     -- Fail b\n\n", buf.String())
 	})
 
+	t.Run("breachesDetectedPerTarget", func(t *testing.T) {
+		RunResultList = result.NewResultList(false)
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		RunResultList.Results = append(RunResultList.Results,
+			result.Result{
+				Name:     "a",
+				Target:   "cli",
+				Status:   result.Fail,
+				Breaches: []result.Breach{&result.ValueBreach{Value: "Fail a"}},
+			},
+			result.Result{
+				Name:     "a",
+				Target:   "web",
+				Status:   result.Fail,
+				Breaches: []result.Breach{&result.ValueBreach{Value: "Fail a"}},
+			},
+			result.Result{
+				Name:     "b",
+				Target:   "web",
+				Status:   result.Fail,
+				Breaches: []result.Breach{&result.ValueBreach{Value: "Fail b"}},
+			},
+		)
+		SimpleDisplay(w)
+		assert.Equal("# Breaches were detected\n\n"+
+			"## Target: cli\n\n"+
+			"  ### a\n     -- Fail a\n\n"+
+			"## Target: web\n\n"+
+			"  ### a\n     -- Fail a\n\n"+
+			"  ### b\n     -- Fail b\n\n",
+			buf.String())
+	})
+
+	t.Run("breachesDetectedWithDocs", func(t *testing.T) {
+		RunResultList = result.NewResultList(false)
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		RunResultList.Results = append(RunResultList.Results, result.Result{
+			Name:   "b",
+			Status: result.Fail,
+			Breaches: []result.Breach{
+				&result.ValueBreach{Value: "Fail b", RemediationHint: "Fix b",
+					DocUrl: "https://wiki.example.com/b"},
+				&result.ValueBreach{Value: "Fail b again", RemediationHint: "Fix b",
+					DocUrl: "https://wiki.example.com/b"},
+			},
+		})
+		SimpleDisplay(w)
+		assert.Equal("# Breaches were detected\n\n  ### b\n     -- Fail b\n     -- Fail b again\n"+
+			"     hint: Fix b\n     docs: https://wiki.example.com/b\n\n", buf.String())
+	})
+
 	t.Run("topShapeRemediating", func(t *testing.T) {
 		RunResultList = result.ResultList{RemediationPerformed: true}
 		var buf bytes.Buffer
@@ -162,6 +289,33 @@ func TestSimpleDisplay(t *testing.T) {
 			"  ### a\n     -- fixed 1\n\n", buf.String())
 	})
 
+	t.Run("remediationDiff", func(t *testing.T) {
+		RunResultList = result.ResultList{
+			Results: []result.Result{{
+				Name: "a",
+				Breaches: []result.Breach{
+					&result.ValueBreach{
+						Remediation: result.Remediation{
+							Status:   result.RemediationStatusSuccess,
+							Messages: []string{"fixed 1"},
+							Diff:     "--- a/foo.yml\n+++ b/foo.yml\n@@ -1 +1 @@\n-foo: 0\n+foo: 1\n",
+						},
+					},
+				}}},
+			TotalBreaches:        1,
+			RemediationPerformed: true,
+			RemediationTotals:    map[string]uint32{"successful": 1},
+		}
+
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		SimpleDisplay(w)
+		assert.Equal("Breaches were detected but were all fixed successfully!\n\n"+
+			"  ### a\n     -- fixed 1\n\n"+
+			"        --- a/foo.yml\n        +++ b/foo.yml\n        @@ -1 +1 @@\n"+
+			"        -foo: 0\n        +foo: 1\n\n\n", buf.String())
+	})
+
 	t.Run("someBreachesRemediated", func(t *testing.T) {
 		RunResultList = result.ResultList{
 			Results: []result.Result{{
@@ -224,6 +378,52 @@ func TestSimpleDisplay(t *testing.T) {
 	})
 }
 
+func TestDisplayTrimmed(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func() {
+		OnlyFailures = false
+		MaxBreachesPerCheck = 0
+	}()
+	OnlyFailures = true
+	MaxBreachesPerCheck = 2
+
+	RunResultList = result.NewResultList(false)
+	RunResultList.Results = []result.Result{
+		{Name: "a", Status: result.Pass, Passes: []string{"Pass a"}},
+		{Name: "b", Status: result.Pass, Breaches: []result.Breach{&result.ValueBreach{Value: "Info b"}}},
+		{Name: "c", Status: result.Fail, Breaches: []result.Breach{
+			&result.ValueBreach{Value: "Fail c1"},
+			&result.ValueBreach{Value: "Fail c2"},
+			&result.ValueBreach{Value: "Fail c3"},
+			&result.ValueBreach{Value: "Fail c4"},
+		}},
+	}
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	SimpleDisplay(w)
+	assert.Equal("# Breaches were detected\n\n"+
+		"  ### c\n     -- Fail c1\n     -- Fail c2\n     -- +2 more\n\n", buf.String())
+
+	buf = bytes.Buffer{}
+	tw := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	TableDisplay(tw)
+	assert.Equal("NAME   STATUS   DURATION   PASSES   FAILS\n"+
+		"c      Fail     0s                  Fail c1\n"+
+		"                                    Fail c2\n"+
+		"                                    +2 more\n",
+		buf.String())
+
+	// Informational breaches are not listed.
+	RunResultList.Results = RunResultList.Results[:2]
+	RunResultList.InformationalBreaches = 1
+	buf = bytes.Buffer{}
+	w = bufio.NewWriter(&buf)
+	SimpleDisplay(w)
+	assert.Equal("Ship is in top shape; only breaches below the fail severity were detected.\n", buf.String())
+}
+
 type testCheck struct{ config.CheckBase }
 
 const testCheckType config.CheckType = "test-check"
@@ -277,3 +477,170 @@ func TestJUnit(t *testing.T) {
 </testsuites>
 `, buf.String())
 }
+
+func TestJUnitTargets(t *testing.T) {
+	assert := assert.New(t)
+
+	RunResultList = result.NewResultList(false)
+	RunConfig.Checks = config.CheckMap{testCheckType: []config.Check{
+		&testCheck{CheckBase: config.CheckBase{Name: "a", Target: "web"}},
+		&testCheck{CheckBase: config.CheckBase{Name: "a", Target: "cli"}},
+	}}
+	RunResultList.Results = append(RunResultList.Results,
+		result.Result{Name: "a", Target: "cli", Status: result.Pass},
+		result.Result{
+			Name:     "a",
+			Target:   "web",
+			Status:   result.Fail,
+			Breaches: []result.Breach{&result.ValueBreach{Value: "Fail a"}},
+		},
+	)
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	JUnit(w)
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="0" errors="0">
+    <testsuite name="cli/test-check" tests="1" errors="0">
+        <testcase name="a" classname="a"></testcase>
+    </testsuite>
+    <testsuite name="web/test-check" tests="1" errors="1">
+        <testcase name="a" classname="a">
+            <error message="Fail a"></error>
+        </testcase>
+    </testsuite>
+</testsuites>
+`, buf.String())
+}
+
+func TestJUnitSkipped(t *testing.T) {
+	assert := assert.New(t)
+
+	RunResultList = result.NewResultList(false)
+	RunConfig.Checks = config.CheckMap{testCheckType: []config.Check{
+		&testCheck{CheckBase: config.CheckBase{Name: "a", Result: result.Result{
+			Name:       "a",
+			Status:     result.Skipped,
+			SkipReason: result.SkipReasonOffline,
+			Warnings:   []string{"skipped since it requires network access in offline mode"},
+		}}},
+	}}
+	RunResultList.Results = append(RunResultList.Results, RunConfig.Checks[testCheckType][0].(*testCheck).Result)
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	JUnit(w)
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="0" errors="0">
+    <testsuite name="test-check" tests="0" errors="0" skipped="1">
+        <testcase name="a" classname="a">
+            <skipped message="offline: skipped since it requires network access in offline mode"></skipped>
+        </testcase>
+    </testsuite>
+</testsuites>
+`, buf.String())
+}
+
+func TestJUnitErrorSeverity(t *testing.T) {
+	assert := assert.New(t)
+	defer func() { JUnitErrorSeverity = "" }()
+
+	RunResultList = result.NewResultList(false)
+	RunConfig.Checks = config.CheckMap{testCheckType: []config.Check{
+		&testCheck{CheckBase: config.CheckBase{Name: "a"}},
+	}}
+	RunResultList.IncrChecks(string(testCheckType), 1)
+	RunResultList.AddResult(result.Result{
+		Name:      "a",
+		CheckType: string(testCheckType),
+		Status:    result.Fail,
+		Breaches: []result.Breach{
+			&result.ValueBreach{Value: "Fail high", Severity: "high"},
+			&result.ValueBreach{Value: "Fail low", Severity: "low"},
+		},
+	})
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	JUnit(w)
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="1" errors="2">
+    <testsuite name="test-check" tests="1" errors="2">
+        <testcase name="a" classname="a">
+            <error message="Fail high" type="high"></error>
+            <error message="Fail low" type="low"></error>
+        </testcase>
+    </testsuite>
+</testsuites>
+`, buf.String())
+
+	JUnitErrorSeverity = config.HighSeverity
+	buf = bytes.Buffer{}
+	w = bufio.NewWriter(&buf)
+	JUnit(w)
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="1" errors="1" failures="1">
+    <testsuite name="test-check" tests="1" errors="1" failures="1">
+        <testcase name="a" classname="a">
+            <error message="Fail high" type="high"></error>
+            <failure message="Fail low" type="low"></failure>
+        </testcase>
+    </testsuite>
+</testsuites>
+`, buf.String())
+}
+
+func TestTemplateDisplay(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseOutputTemplate("invalid", "{{ .Status ")
+	assert.Error(err)
+
+	tmpl, err := ParseOutputTemplate("csv", `check,key,value
+{{ range .Results }}{{ $r := . }}{{ range .Breaches }}{{ $r.Name }},{{ breachKey . }},{{ breachValue . }}{{ join (breachValues .) ";" }}
+{{ end }}{{ end }}status: {{ .Status }}
+`)
+	assert.NoError(err)
+
+	RunResultList = result.NewResultList(false)
+	RunResultList.Results = append(RunResultList.Results,
+		result.Result{
+			Name:     "a",
+			Status:   result.Fail,
+			Breaches: []result.Breach{&result.ValueBreach{Value: "Fail a"}},
+		},
+		result.Result{
+			Name:   "b",
+			Status: result.Fail,
+			Breaches: []result.Breach{&result.KeyValuesBreach{
+				Key: "illegal files", Values: []string{"adminer.php", "info.php"}}},
+		},
+	)
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	assert.NoError(TemplateDisplay(w, tmpl))
+	assert.Equal(`check,key,value
+a,,Fail a
+b,illegal files,adminer.php;info.php
+status: Fail
+`, buf.String())
+
+	tmpl, _ = ParseOutputTemplate("missing", "{{ .Missing }}")
+	assert.Error(TemplateDisplay(bufio.NewWriter(&buf), tmpl))
+}
+
+func TestRunPostCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	curShellCommander := command.ShellCommander
+	defer func() { command.ShellCommander = curShellCommander }()
+
+	var generatedCommand string
+	command.ShellCommander = internal.ShellCommanderMaker(nil, nil, &generatedCommand)
+	_, err := RunPostCommand(`aws s3 cp "$1" s3://reports/`, "/tmp/report.json")
+	assert.NoError(err)
+	assert.Equal(`sh -c 'aws s3 cp "$1" s3://reports/' shipshape /tmp/report.json`, generatedCommand)
+
+	command.ShellCommander = curShellCommander
+	out, err := RunPostCommand(`echo "uploading $1"`, "/tmp/report.json")
+	assert.NoError(err)
+	assert.Equal("uploading /tmp/report.json\n", string(out))
+}
@@ -0,0 +1,115 @@
+package shipshape
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+)
+
+// PlannedCheck is a check to be run along with the external commands it
+// would run.
+type PlannedCheck struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Target   string   `json:"target,omitempty"`
+	Commands []string `json:"commands"`
+}
+
+// Plan lists the checks to be run along with the external commands they
+// would run, without running them.
+func Plan() []PlannedCheck {
+	plan := []PlannedCheck{}
+	for ct, checks := range RunConfig.Checks {
+		for _, c := range checks {
+			plan = append(plan, PlannedCheck{
+				Type:     string(ct),
+				Name:     c.GetName(),
+				Target:   c.GetTarget(),
+				Commands: checkCommands(c),
+			})
+		}
+	}
+	sort.SliceStable(plan, func(i, j int) bool {
+		if plan[i].Target != plan[j].Target {
+			return plan[i].Target < plan[j].Target
+		}
+		if plan[i].Type != plan[j].Type {
+			return plan[i].Type < plan[j].Type
+		}
+		return plan[i].Name < plan[j].Name
+	})
+	return plan
+}
+
+// checkCommands determines the commands a check would run, prefixed with its
+// target's exec command.
+func checkCommands(c config.Check) []string {
+	reporter, ok := c.(config.CommandReporter)
+	if !ok {
+		return []string{}
+	}
+
+	var prefix []string
+	if target, ok := RunConfig.Targets[c.GetTarget()]; ok {
+		prefix = target.Exec
+		if target.ProjectDir != "" {
+			curProjectDir := config.ProjectDir
+			defer func() { config.ProjectDir = curProjectDir }()
+			config.ProjectDir = target.ProjectDir
+		}
+	}
+
+	cmds := []string{}
+	for _, args := range reporter.Commands() {
+		cmds = append(cmds, JoinCommand(append(append([]string{}, prefix...), args...)))
+	}
+	return cmds
+}
+
+// JoinCommand joins the arguments of a command into a shell command line,
+// quoting them as required.
+func JoinCommand(args []string) string {
+	quoted := []string{}
+	for _, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"\\$`|&;<>()*?[]{}!#~") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		quoted = append(quoted, a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// PlanDisplay generates the output for the plan.
+func PlanDisplay(w *bufio.Writer, plan []PlannedCheck, remediate bool) {
+	if remediate {
+		fmt.Fprint(w, "Remediation is enabled; checks may modify the project.\n\n")
+	} else {
+		fmt.Fprint(w, "Remediation is disabled; the commands below only read from the project.\n\n")
+	}
+
+	if len(plan) == 0 {
+		fmt.Fprint(w, "No checks to run; ensure your shipshape.yml is configured correctly.\n")
+		w.Flush()
+		return
+	}
+
+	prevTarget := ""
+	for i, p := range plan {
+		if p.Target != "" && (i == 0 || p.Target != prevTarget) {
+			fmt.Fprintf(w, "## Target: %s\n\n", p.Target)
+		}
+		prevTarget = p.Target
+		fmt.Fprintf(w, "  ### %s [%s]\n", p.Name, p.Type)
+		if len(p.Commands) == 0 {
+			fmt.Fprint(w, "     -- no external command\n")
+		}
+		for _, cmd := range p.Commands {
+			fmt.Fprintf(w, "     $ %s\n", cmd)
+		}
+		fmt.Fprint(w, "\n")
+	}
+	w.Flush()
+}
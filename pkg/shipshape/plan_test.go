@@ -0,0 +1,83 @@
+package shipshape_test
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/audit"
+	yamlchecks "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlan(t *testing.T) {
+	assert := assert.New(t)
+
+	curProjectDir := config.ProjectDir
+	defer func() { config.ProjectDir = curProjectDir }()
+	config.ProjectDir = "/tmp/local"
+
+	npm := &audit.DependencyAuditCheck{CheckBase: config.CheckBase{Name: "npm", Target: "web"}, Tool: "npm"}
+	gitleaks := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{CheckBase: config.CheckBase{Name: "gitleaks"}},
+		Command:  "gitleaks detect -r $1",
+	}
+	noCommand := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "nocommand"}}
+	RunConfig = config.Config{
+		Targets: map[string]config.Target{
+			"web": {Exec: []string{"docker", "exec", "web"}, ProjectDir: "/app"},
+		},
+		Checks: config.CheckMap{
+			audit.DependencyAudit: {npm},
+			yamlchecks.Yaml:       {gitleaks},
+			testchecks.TestCheck1: {noCommand},
+		},
+	}
+
+	assert.Equal([]PlannedCheck{
+		{Type: "test-check-1", Name: "nocommand", Commands: []string{}},
+		{Type: "yaml", Name: "gitleaks", Commands: []string{
+			"sh -c 'gitleaks detect -r $1' shipshape '<output file>'"}},
+		{Type: "dependency-audit", Name: "npm", Target: "web", Commands: []string{
+			"docker exec web npm --prefix /app audit --json"}},
+	}, Plan())
+	assert.Equal("/tmp/local", config.ProjectDir)
+}
+
+func TestJoinCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("drush @prod status", JoinCommand([]string{"drush", "@prod", "status"}))
+	assert.Equal(`php -r 'echo "it'\''s";' ''`, JoinCommand([]string{"php", "-r", `echo "it's";`, ""}))
+}
+
+func TestPlanDisplay(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	PlanDisplay(w, []PlannedCheck{}, false)
+	assert.Equal("Remediation is disabled; the commands below only read from the project.\n\n"+
+		"No checks to run; ensure your shipshape.yml is configured correctly.\n", buf.String())
+
+	buf.Reset()
+	PlanDisplay(w, []PlannedCheck{
+		{Type: "test-check-1", Name: "nocommand", Commands: []string{}},
+		{Type: "dependency-audit", Name: "npm", Target: "web", Commands: []string{
+			"docker exec web npm audit --json"}},
+	}, true)
+	assert.Equal(`Remediation is enabled; checks may modify the project.
+
+  ### nocommand [test-check-1]
+     -- no external command
+
+## Target: web
+
+  ### npm [dependency-audit]
+     $ docker exec web npm audit --json
+
+`, buf.String())
+}
@@ -0,0 +1,42 @@
+package shipshape
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// PresetPrefix is used to reference a built-in preset in place of a config
+// file, e.g, `-f preset:drupal-dangerous-modules`.
+const PresetPrefix = "preset:"
+
+//go:embed presets/*.yml
+var presetsFS embed.FS
+
+// Presets returns the sorted list of built-in presets.
+func Presets() []string {
+	entries, _ := presetsFS.ReadDir("presets")
+	presets := []string{}
+	for _, e := range entries {
+		presets = append(presets, strings.TrimSuffix(e.Name(), ".yml"))
+	}
+	sort.Strings(presets)
+	return presets
+}
+
+// IsPreset determines whether a config file reference is a built-in preset.
+func IsPreset(f string) bool {
+	return strings.HasPrefix(f, PresetPrefix)
+}
+
+// FetchPreset returns the config of a built-in preset.
+func FetchPreset(f string) ([]byte, error) {
+	name := strings.TrimPrefix(f, PresetPrefix)
+	data, err := presetsFS.ReadFile(path.Join("presets", name+".yml"))
+	if err != nil {
+		return nil, fmt.Errorf("preset '%s' not found", name)
+	}
+	return data, nil
+}
@@ -0,0 +1,99 @@
+package shipshape_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/checks/toml"
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/audit"
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/dotenv"
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/drupal"
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/file"
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/json"
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/python"
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+)
+
+func TestPresets(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Contains(Presets(), "ci")
+	assert.Contains(Presets(), "drupal-dangerous-modules")
+	assert.Contains(Presets(), "laravel")
+	assert.Contains(Presets(), "node")
+	assert.Contains(Presets(), "python")
+	assert.Contains(Presets(), "symfony")
+	assert.True(IsPreset("preset:drupal-dangerous-modules"))
+	assert.False(IsPreset("shipshape.yml"))
+
+	_, err := FetchPreset("preset:non-existent")
+	assert.EqualError(err, "preset 'non-existent' not found")
+
+	for _, p := range Presets() {
+		t.Run(p, func(t *testing.T) {
+			data, err := FetchPreset(PresetPrefix + p)
+			assert.NoError(err)
+			cfg := config.Config{}
+			assert.NoError(yaml.Unmarshal(data, &cfg))
+			assert.NotEmpty(cfg.Checks)
+		})
+	}
+}
+
+func TestFetchConfigDataPreset(t *testing.T) {
+	assert := assert.New(t)
+
+	data, err := FetchConfigData([]string{"preset:drupal-dangerous-modules"})
+	assert.NoError(err)
+	assert.Len(data, 1)
+
+	_, err = FetchConfigData([]string{"preset:non-existent"})
+	assert.EqualError(err, "preset 'non-existent' not found")
+}
+
+func TestPythonPresetVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	curProjectDir := config.ProjectDir
+	defer func() { config.ProjectDir = curProjectDir }()
+
+	data, err := FetchPreset(PresetPrefix + "python")
+	assert.NoError(err)
+	cfg := config.Config{}
+	assert.NoError(yaml.Unmarshal(data, &cfg))
+	assert.Len(cfg.Checks[toml.Toml], 1)
+
+	tests := []struct {
+		requires string
+		status   result.Status
+	}{
+		{">=2.7", result.Fail},
+		{">=3.9", result.Pass},
+		{">=3.11,<4", result.Pass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requires, func(t *testing.T) {
+			config.ProjectDir = t.TempDir()
+			os.WriteFile(filepath.Join(config.ProjectDir, "pyproject.toml"),
+				[]byte("[project]\nrequires-python = \""+tt.requires+"\"\n"), 0644)
+
+			c := cfg.Checks[toml.Toml][0]
+			c.Init(toml.Toml)
+			c.GetResult().Breaches = nil
+			c.GetResult().Passes = nil
+			rl := result.NewResultList(false)
+			ProcessCheck(&rl, c)
+			assert.Equal(tt.status, rl.Results[0].Status)
+			if tt.status == result.Fail {
+				assert.Len(rl.Results[0].Breaches, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package shipshape
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+)
+
+// RunBaseline is a previous json report the results are compared against;
+// the history is used instead when it is nil.
+var RunBaseline *result.ResultList
+
+// ReadBaseline reads a report produced by the json output.
+func ReadBaseline(path string) (*result.ResultList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rl := &result.ResultList{}
+	if err := json.Unmarshal(data, rl); err != nil {
+		return nil, fmt.Errorf("invalid baseline file '%s': %w", path, err)
+	}
+	return rl, nil
+}
+
+// LoadPreviousResults makes the checks' previous results available from the
+// history and the baseline; the baseline's outcome takes precedence, while
+// the history provides when the checks last ran & passed.
+func LoadPreviousResults() {
+	previous := map[string]config.PreviousResult{}
+	if RunHistory != nil {
+		for key, record := range RunHistory.Checks {
+			previous[key] = config.PreviousResult{
+				Status:     record.Status,
+				Breaches:   record.Breaches,
+				LastRun:    record.LastRun,
+				LastPassed: record.LastPassed,
+			}
+		}
+	}
+	if RunBaseline != nil {
+		for _, r := range RunBaseline.Results {
+			key := historyCheckKey(r)
+			pr := previous[key]
+			pr.Status = r.Status
+			pr.Passes = r.Passes
+			pr.Breaches = nil
+			for _, b := range r.Breaches {
+				pr.Breaches = append(pr.Breaches, b.String())
+			}
+			previous[key] = pr
+		}
+	}
+	config.SetPreviousResults(previous)
+}
+
+// PreviousResult fetches the previous result of the check which produced the
+// result, or nil if there is none, e.g,
+// `{{ with previousResult . }}{{ .Status }}{{ end }}`.
+func PreviousResult(r result.Result) *config.PreviousResult {
+	pr, ok := config.GetPreviousResult(r.CheckType, r.Name, r.Target)
+	if !ok {
+		return nil
+	}
+	return &pr
+}
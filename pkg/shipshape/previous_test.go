@@ -0,0 +1,86 @@
+package shipshape_test
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadBaseline(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	_, err := ReadBaseline(path)
+	assert.Error(err)
+
+	assert.NoError(os.WriteFile(path, []byte(`{"results": [{"name": "a", "check-type": "file", "status": "Fail",
+  "breaches": [{"breach-type": "value", "value": "Fail a"}]}]}`), 0644))
+	rl, err := ReadBaseline(path)
+	assert.NoError(err)
+	assert.Equal(result.Fail, rl.Results[0].Status)
+	assert.Equal([]result.Breach{&result.ValueBreach{BreachType: "value", Value: "Fail a"}}, rl.Results[0].Breaches)
+
+	assert.NoError(os.WriteFile(path, []byte("{"), 0644))
+	_, err = ReadBaseline(path)
+	assert.ErrorContains(err, "invalid baseline file")
+}
+
+func TestLoadPreviousResults(t *testing.T) {
+	assert := assert.New(t)
+
+	curHistory, curBaseline := RunHistory, RunBaseline
+	defer func() {
+		RunHistory, RunBaseline = curHistory, curBaseline
+		config.SetPreviousResults(map[string]config.PreviousResult{})
+	}()
+
+	lastPassed := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	lastRun := time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)
+	RunHistory = &History{Checks: map[string]CheckRecord{
+		"file:a": {Status: result.Fail, Breaches: []string{"old"}, LastRun: lastRun, LastPassed: lastPassed},
+		"file:b": {Status: result.Pass, LastRun: lastRun, LastPassed: lastRun},
+	}}
+	RunBaseline = &result.ResultList{Results: []result.Result{{
+		Name:      "a",
+		CheckType: "file",
+		Status:    result.Fail,
+		Breaches:  []result.Breach{&result.ValueBreach{Value: "baseline"}},
+	}}}
+	LoadPreviousResults()
+
+	// The baseline's outcome takes precedence over the history.
+	assert.Equal(&config.PreviousResult{
+		Status:     result.Fail,
+		Breaches:   []string{"baseline"},
+		LastRun:    lastRun,
+		LastPassed: lastPassed,
+	}, PreviousResult(result.Result{Name: "a", CheckType: "file"}))
+	assert.Equal(result.Pass, PreviousResult(result.Result{Name: "b", CheckType: "file"}).Status)
+	assert.Nil(PreviousResult(result.Result{Name: "c", CheckType: "file"}))
+
+	tmpl, err := ParseOutputTemplate("previous", `{{ range .Results }}{{ .Name }}: {{ .Status }}
+{{- with previousResult . }}{{ if not .LastPassed.IsZero }} (was passing until {{ .LastPassed.Format "2006-01-02" }}){{ end }}{{ end }}
+{{ end }}`)
+	assert.NoError(err)
+	RunResultList = result.NewResultList(false)
+	RunResultList.Results = []result.Result{
+		{Name: "a", CheckType: "file", Status: result.Fail},
+		{Name: "c", CheckType: "file", Status: result.Fail},
+	}
+	var buf bytes.Buffer
+	assert.NoError(TemplateDisplay(bufio.NewWriter(&buf), tmpl))
+	assert.Equal("a: Fail (was passing until 2024-05-01)\nc: Fail\n", buf.String())
+
+	met, err := EvaluateWhen(`{{ range .Results }}{{ with previousResult . }}{{ eq .Status "Pass" }}{{ end }}{{ end }}`,
+		result.ResultList{Results: []result.Result{{Name: "b", CheckType: "file"}}})
+	assert.NoError(err)
+	assert.True(met)
+}
@@ -0,0 +1,94 @@
+package shipshape
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Progress renders the progress of the check runs on a single line, with a
+// spinner, the percentage of checks processed and the name of the last check
+// started. All methods are safe to call on a nil Progress, which renders
+// nothing.
+type Progress struct {
+	w       io.Writer
+	total   int
+	done    int
+	current string
+	frame   int
+	lock    sync.Mutex
+}
+
+// RunProgress is used by RunChecks to render the progress if set.
+var RunProgress *Progress
+
+// NewProgress creates a Progress rendering to the writer.
+func NewProgress(w io.Writer) *Progress {
+	return &Progress{w: w}
+}
+
+// IsInteractive determines whether the file is an interactive terminal.
+func IsInteractive(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// SetTotal sets the number of checks to run.
+func (p *Progress) SetTotal(total int) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.total = total
+	p.done = 0
+	p.render()
+}
+
+// Start renders the check as the current one.
+func (p *Progress) Start(name string) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.current = name
+	p.render()
+}
+
+// Done increments the number of checks processed.
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.done++
+	p.render()
+}
+
+// Finish clears the progress line.
+func (p *Progress) Finish() {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	fmt.Fprint(p.w, "\r\033[K")
+}
+
+func (p *Progress) render() {
+	percent := 100
+	if p.total > 0 {
+		percent = p.done * 100 / p.total
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s [%d/%d] %3d%% %s",
+		spinnerFrames[p.frame%len(spinnerFrames)], p.done, p.total, percent, p.current)
+	p.frame++
+}
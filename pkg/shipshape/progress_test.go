@@ -0,0 +1,64 @@
+package shipshape_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	assert := assert.New(t)
+
+	var p *Progress
+	assert.NotPanics(func() {
+		p.SetTotal(2)
+		p.Start("a")
+		p.Done()
+		p.Finish()
+	})
+
+	var buf bytes.Buffer
+	p = NewProgress(&buf)
+	p.SetTotal(2)
+	assert.Equal("\r\033[K⠋ [0/2]   0% ", buf.String())
+
+	buf.Reset()
+	p.Start("a")
+	assert.Equal("\r\033[K⠙ [0/2]   0% a", buf.String())
+
+	buf.Reset()
+	p.Done()
+	assert.Equal("\r\033[K⠹ [1/2]  50% a", buf.String())
+
+	buf.Reset()
+	p.Finish()
+	assert.Equal("\r\033[K", buf.String())
+}
+
+func TestRunChecksProgress(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	RunProgress = NewProgress(&buf)
+	defer func() { RunProgress = nil }()
+
+	c := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "a"}}
+	c.Init(testchecks.TestCheck1)
+	RunConfig = config.Config{Checks: config.CheckMap{testchecks.TestCheck1: {c}}}
+	RunResultList = result.NewResultList(false)
+	RunChecks()
+	assert.Contains(buf.String(), "[0/1]   0% a")
+	assert.Contains(buf.String(), "[1/1] 100% a")
+	assert.True(bytes.HasSuffix(buf.Bytes(), []byte("\r\033[K")))
+}
@@ -0,0 +1,149 @@
+package shipshape
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// Project is the information detected about the project being audited, which
+// can be used in conditions.
+type Project struct {
+	// Main platform or framework, e.g, drupal or node.
+	Platform string
+	// All the stacks detected, e.g, drupal, node & docker.
+	Stacks []string
+	// Versions of the platforms, keyed by stack.
+	Versions map[string]string
+	// Hosting indicators found, e.g, lagoon or platformsh.
+	Hosting []string
+}
+
+// RunProject is the project detected when initialising shipshape.
+var RunProject Project
+
+// platforms are the stacks which can be the main platform, in order of
+// precedence.
+var platforms = []string{"drupal", "wordpress", "laravel", "symfony", "node", "python"}
+
+// composerPackages are the composer packages providing the platform version.
+var composerPackages = map[string][]string{
+	"drupal":    {"drupal/core", "drupal/core-recommended"},
+	"wordpress": {"roots/wordpress", "johnpbloch/wordpress-core"},
+	"laravel":   {"laravel/framework"},
+	"symfony":   {"symfony/framework-bundle"},
+}
+
+// hostings are the hosting platforms along with the files indicating them.
+var hostings = []struct {
+	Name  string
+	Files []string
+}{
+	{Name: "lagoon", Files: []string{".lagoon.yml"}},
+	{Name: "platformsh", Files: []string{".platform.app.yaml", ".platform/applications.yaml"}},
+	{Name: "pantheon", Files: []string{"pantheon.yml", "pantheon.upstream.yml"}},
+	{Name: "acquia", Files: []string{"acquia-pipelines.yml", "acquia-pipelines.yaml"}},
+}
+
+var wpVersionRegex = regexp.MustCompile(`\$wp_version\s*=\s*'([^']+)'`)
+
+// wordpressDirs are the directories in which WordPress is usually installed.
+var wordpressDirs = []string{"", "web", "web/wp", "wp", "public"}
+
+// DetectProject inspects a project directory for its platform, versions and
+// hosting.
+func DetectProject(dir string) Project {
+	p := Project{Stacks: []string{}, Versions: map[string]string{}, Hosting: []string{}}
+	for _, s := range DetectStacks(dir) {
+		p.Stacks = append(p.Stacks, s.Name)
+	}
+
+	wpVersion := wordpressVersion(dir)
+	if wpVersion != "" || composerRequires(dir, composerPackages["wordpress"]...) {
+		p.Stacks = append(p.Stacks, "wordpress")
+	}
+
+	for _, pf := range platforms {
+		if utils.StringSliceContains(p.Stacks, pf) {
+			p.Platform = pf
+			break
+		}
+	}
+
+	locked := composerLockVersions(dir)
+	for stack, packages := range composerPackages {
+		for _, pkg := range packages {
+			if v, ok := locked[pkg]; ok && utils.StringSliceContains(p.Stacks, stack) {
+				p.Versions[stack] = strings.TrimPrefix(v, "v")
+				break
+			}
+		}
+	}
+	if wpVersion != "" {
+		p.Versions["wordpress"] = wpVersion
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, ".nvmrc")); err == nil {
+		p.Versions["node"] = strings.TrimPrefix(strings.TrimSpace(string(data)), "v")
+	}
+
+	for _, h := range hostings {
+		for _, f := range h.Files {
+			if fileExists(dir, f) {
+				p.Hosting = append(p.Hosting, h.Name)
+				break
+			}
+		}
+	}
+	return p
+}
+
+// HasStack determines whether the stack was detected.
+func (p Project) HasStack(stack string) bool {
+	return utils.StringSliceContains(p.Stacks, stack)
+}
+
+// HasHosting determines whether the hosting was detected.
+func (p Project) HasHosting(hosting string) bool {
+	return utils.StringSliceContains(p.Hosting, hosting)
+}
+
+// composerLockVersions returns the versions of the packages installed by
+// composer.
+func composerLockVersions(dir string) map[string]string {
+	versions := map[string]string{}
+	data, err := os.ReadFile(filepath.Join(dir, "composer.lock"))
+	if err != nil {
+		return versions
+	}
+	lock := struct {
+		Packages []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"packages"`
+	}{}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return versions
+	}
+	for _, p := range lock.Packages {
+		versions[p.Name] = p.Version
+	}
+	return versions
+}
+
+// wordpressVersion returns the version of WordPress from its version.php.
+func wordpressVersion(dir string) string {
+	for _, d := range wordpressDirs {
+		data, err := os.ReadFile(filepath.Join(dir, d, "wp-includes", "version.php"))
+		if err != nil {
+			continue
+		}
+		if m := wpVersionRegex.FindSubmatch(data); m != nil {
+			return string(m[1])
+		}
+	}
+	return ""
+}
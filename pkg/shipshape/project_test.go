@@ -0,0 +1,36 @@
+package shipshape_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectProject(t *testing.T) {
+	assert := assert.New(t)
+
+	p := DetectProject("testdata/project/drupal")
+	assert.Equal(Project{
+		Platform: "drupal",
+		Stacks:   []string{"drupal", "node", "lagoon"},
+		Versions: map[string]string{"drupal": "10.1.6", "node": "18.17.1"},
+		Hosting:  []string{"lagoon"},
+	}, p)
+	assert.True(p.HasStack("node"))
+	assert.False(p.HasStack("python"))
+	assert.True(p.HasHosting("lagoon"))
+
+	assert.Equal(Project{
+		Platform: "wordpress",
+		Stacks:   []string{"wordpress"},
+		Versions: map[string]string{"wordpress": "6.4.2"},
+		Hosting:  []string{"platformsh"},
+	}, DetectProject("testdata/project/wordpress"))
+
+	assert.Equal(Project{
+		Stacks:   []string{},
+		Versions: map[string]string{},
+		Hosting:  []string{},
+	}, DetectProject(t.TempDir()))
+}
@@ -0,0 +1,109 @@
+package shipshape
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+)
+
+// RemediationSleep waits for the interval of a rate limit; it can be
+// overridden in tests.
+var RemediationSleep = time.Sleep
+
+// rateLimiter bounds the remediations of the matching checks run at the same
+// time, as well as how often they start.
+type rateLimiter struct {
+	types    []string
+	sem      chan struct{}
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// remediationSem bounds the number of checks remediated at the same time.
+var remediationSem chan struct{}
+var rateLimiters []*rateLimiter
+
+// InitRemediationLimits sets up the remediation concurrency & rate limits
+// from the config, replacing any previous ones.
+func InitRemediationLimits(rc config.RemediationConfig) error {
+	remediationSem = nil
+	rateLimiters = nil
+	if rc.Concurrency < 0 {
+		return errors.New("remediation concurrency cannot be negative")
+	}
+	if rc.Concurrency > 0 {
+		remediationSem = make(chan struct{}, rc.Concurrency)
+	}
+
+	limiters := []*rateLimiter{}
+	for i, rl := range rc.RateLimits {
+		if len(rl.Types) == 0 {
+			return fmt.Errorf("remediation rate limit #%d has no types", i+1)
+		}
+		if rl.Concurrency < 0 {
+			return fmt.Errorf("remediation rate limit #%d concurrency cannot be negative", i+1)
+		}
+		l := &rateLimiter{types: rl.Types}
+		if rl.Concurrency > 0 {
+			l.sem = make(chan struct{}, rl.Concurrency)
+		}
+		if rl.Interval != "" {
+			interval, err := utils.ParseDuration(rl.Interval)
+			if err != nil || interval < 0 {
+				return fmt.Errorf("invalid remediation rate limit interval '%s'", rl.Interval)
+			}
+			l.interval = interval
+		}
+		limiters = append(limiters, l)
+	}
+	rateLimiters = limiters
+	return nil
+}
+
+// wait blocks until the interval since the previous remediation started has
+// elapsed.
+func (l *rateLimiter) wait() {
+	if l.interval == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d := l.next.Sub(utils.TimeNow()); d > 0 {
+		RemediationSleep(d)
+	}
+	l.next = utils.TimeNow().Add(l.interval)
+}
+
+// AcquireRemediation blocks until the check's remediation is allowed to run
+// by the concurrency & rate limits; the returned function releases it once
+// done. The limits are always acquired in the same order so that checks
+// matching several of them cannot deadlock.
+func AcquireRemediation(c config.Check) (release func()) {
+	sems := []chan struct{}{}
+	for _, l := range rateLimiters {
+		if !utils.StringSliceMatchAny(l.types, string(c.GetType())) {
+			continue
+		}
+		if l.sem != nil {
+			l.sem <- struct{}{}
+			sems = append(sems, l.sem)
+		}
+		l.wait()
+	}
+	// The overall slot is taken last, so that checks waiting for their rate
+	// limit do not hold it.
+	if remediationSem != nil {
+		remediationSem <- struct{}{}
+		sems = append(sems, remediationSem)
+	}
+	return func() {
+		for _, s := range sems {
+			<-s
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package shipshape_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/salsadigitalauorg/shipshape/pkg/shipshape/testdata/testchecks"
+	"github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitRemediationLimits(t *testing.T) {
+	assert := assert.New(t)
+	defer InitRemediationLimits(config.RemediationConfig{})
+
+	assert.NoError(InitRemediationLimits(config.RemediationConfig{}))
+	assert.NoError(InitRemediationLimits(config.RemediationConfig{
+		Concurrency: 2,
+		RateLimits: []config.RemediationRateLimit{
+			{Types: []string{"glob:drupal-*"}, Concurrency: 1, Interval: "30s"},
+		},
+	}))
+	assert.EqualError(InitRemediationLimits(config.RemediationConfig{Concurrency: -1}),
+		"remediation concurrency cannot be negative")
+	assert.EqualError(InitRemediationLimits(config.RemediationConfig{
+		RateLimits: []config.RemediationRateLimit{{Concurrency: 1}},
+	}), "remediation rate limit #1 has no types")
+	assert.EqualError(InitRemediationLimits(config.RemediationConfig{
+		RateLimits: []config.RemediationRateLimit{{Types: []string{"yaml"}, Concurrency: -1}},
+	}), "remediation rate limit #1 concurrency cannot be negative")
+	assert.EqualError(InitRemediationLimits(config.RemediationConfig{
+		RateLimits: []config.RemediationRateLimit{{Types: []string{"yaml"}, Interval: "often"}},
+	}), "invalid remediation rate limit interval 'often'")
+}
+
+// maxConcurrentRemediations remediates the checks concurrently, returning
+// the maximum number of remediations which ran at the same time.
+func maxConcurrentRemediations(checks []config.Check) int {
+	var mu sync.Mutex
+	running, max := 0, 0
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c config.Check) {
+			defer wg.Done()
+			release := AcquireRemediation(c)
+			mu.Lock()
+			running++
+			if running > max {
+				max = running
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			release()
+		}(c)
+	}
+	wg.Wait()
+	return max
+}
+
+func TestAcquireRemediation(t *testing.T) {
+	defer InitRemediationLimits(config.RemediationConfig{})
+
+	newChecks := func(ct config.CheckType, n int) []config.Check {
+		checks := []config.Check{}
+		for i := 0; i < n; i++ {
+			c := &testchecks.TestCheck1Check{}
+			c.Init(ct)
+			checks = append(checks, c)
+		}
+		return checks
+	}
+
+	t.Run("concurrency", func(t *testing.T) {
+		InitRemediationLimits(config.RemediationConfig{Concurrency: 2})
+		assert.Equal(t, 2, maxConcurrentRemediations(newChecks(testchecks.TestCheck1, 4)))
+	})
+
+	t.Run("rateLimitConcurrency", func(t *testing.T) {
+		InitRemediationLimits(config.RemediationConfig{
+			RateLimits: []config.RemediationRateLimit{
+				{Types: []string{"glob:test-check-*"}, Concurrency: 1},
+			},
+		})
+		assert.Equal(t, 1, maxConcurrentRemediations(append(
+			newChecks(testchecks.TestCheck1, 2), newChecks(testchecks.TestCheck2, 2)...)))
+
+		// Checks not matching the limit are not limited.
+		assert.Greater(t, maxConcurrentRemediations(newChecks("yaml", 3)), 1)
+	})
+
+	t.Run("rateLimitInterval", func(t *testing.T) {
+		assert := assert.New(t)
+
+		curTimeNow := utils.TimeNow
+		curSleep := RemediationSleep
+		defer func() {
+			utils.TimeNow = curTimeNow
+			RemediationSleep = curSleep
+		}()
+		now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+		utils.TimeNow = func() time.Time { return now }
+		sleeps := []time.Duration{}
+		RemediationSleep = func(d time.Duration) {
+			sleeps = append(sleeps, d)
+			now = now.Add(d)
+		}
+
+		InitRemediationLimits(config.RemediationConfig{
+			RateLimits: []config.RemediationRateLimit{
+				{Types: []string{string(testchecks.TestCheck1)}, Interval: "30s"},
+			},
+		})
+		checks := newChecks(testchecks.TestCheck1, 3)
+		AcquireRemediation(checks[0])()
+		now = now.Add(10 * time.Second)
+		AcquireRemediation(checks[1])()
+		AcquireRemediation(checks[2])()
+		AcquireRemediation(newChecks("yaml", 1)[0])()
+		assert.Equal([]time.Duration{20 * time.Second, 30 * time.Second}, sleeps)
+	})
+}
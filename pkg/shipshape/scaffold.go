@@ -0,0 +1,189 @@
+package shipshape
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed scaffold/*.yml
+var scaffoldFS embed.FS
+
+// Stack is a type of project detected when scaffolding a config, along with
+// the checks to generate for it.
+type Stack struct {
+	Name string
+	// Config file providing the checks, either a preset (preset:node) or a
+	// scaffold template.
+	Config string
+	Detect func(dir string) bool
+}
+
+// Stacks is the ordered list of stacks which can be detected.
+var Stacks = []Stack{
+	{
+		Name:   "drupal",
+		Config: PresetPrefix + "drupal-dangerous-modules",
+		Detect: func(dir string) bool {
+			return composerRequires(dir, "drupal/core", "drupal/core-recommended") ||
+				fileExists(dir, "web/core/lib/Drupal.php") ||
+				fileExists(dir, "docroot/core/lib/Drupal.php")
+		},
+	},
+	{
+		Name:   "laravel",
+		Config: PresetPrefix + "laravel",
+		Detect: func(dir string) bool {
+			return composerRequires(dir, "laravel/framework")
+		},
+	},
+	{
+		Name:   "symfony",
+		Config: PresetPrefix + "symfony",
+		Detect: func(dir string) bool {
+			return composerRequires(dir, "symfony/framework-bundle")
+		},
+	},
+	{
+		Name:   "node",
+		Config: PresetPrefix + "node",
+		Detect: func(dir string) bool {
+			return fileExists(dir, "package.json")
+		},
+	},
+	{
+		Name:   "python",
+		Config: PresetPrefix + "python",
+		Detect: func(dir string) bool {
+			return fileExists(dir, "requirements.txt")
+		},
+	},
+	{
+		Name:   "docker",
+		Config: "docker",
+		Detect: func(dir string) bool {
+			return fileExists(dir, "Dockerfile")
+		},
+	},
+	{
+		Name:   "lagoon",
+		Config: "lagoon",
+		Detect: func(dir string) bool {
+			return fileExists(dir, ".lagoon.yml")
+		},
+	},
+}
+
+// DetectStacks inspects a project directory and returns the stacks found.
+func DetectStacks(dir string) []Stack {
+	stacks := []Stack{}
+	for _, s := range Stacks {
+		if s.Detect(dir) {
+			stacks = append(stacks, s)
+		}
+	}
+	return stacks
+}
+
+// Scaffold generates a starter config with the checks relevant to the stacks
+// detected in the project directory.
+func Scaffold(dir string) ([]byte, error) {
+	stacks := DetectStacks(dir)
+	if len(stacks) == 0 {
+		return nil, fmt.Errorf("no supported project type detected in '%s'", dir)
+	}
+
+	names := []string{}
+	checkTypes := []string{}
+	checksByType := map[string][]*yaml.Node{}
+	for _, s := range stacks {
+		names = append(names, s.Name)
+
+		data, err := fetchScaffoldConfig(s.Config)
+		if err != nil {
+			return nil, err
+		}
+		cfg := struct {
+			Checks yaml.Node `yaml:"checks"`
+		}{}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("unable to parse config for %s: %w", s.Name, err)
+		}
+
+		// Mapping node content alternates between keys & values.
+		for i := 0; i+1 < len(cfg.Checks.Content); i += 2 {
+			ct := cfg.Checks.Content[i].Value
+			if _, ok := checksByType[ct]; !ok {
+				checkTypes = append(checkTypes, ct)
+			}
+			checksByType[ct] = append(checksByType[ct], cfg.Checks.Content[i+1].Content...)
+		}
+	}
+
+	checks := &yaml.Node{Kind: yaml.MappingNode}
+	for _, ct := range checkTypes {
+		checks.Content = append(checks.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: ct},
+			&yaml.Node{Kind: yaml.SequenceNode, Content: checksByType[ct]})
+	}
+	root := &yaml.Node{
+		Kind: yaml.MappingNode,
+		HeadComment: fmt.Sprintf("Generated by shipshape init for: %s.\n"+
+			"Review the checks below and adjust them to the project.",
+			strings.Join(names, ", ")),
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "checks"},
+			checks,
+		},
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	enc.Close()
+	return buf.Bytes(), nil
+}
+
+// fetchScaffoldConfig returns either a preset or a scaffold template.
+func fetchScaffoldConfig(f string) ([]byte, error) {
+	if IsPreset(f) {
+		return FetchPreset(f)
+	}
+	return scaffoldFS.ReadFile(path.Join("scaffold", f+".yml"))
+}
+
+func fileExists(dir string, f string) bool {
+	_, err := os.Stat(filepath.Join(dir, f))
+	return err == nil
+}
+
+// composerRequires determines whether composer.json requires any of the
+// given packages.
+func composerRequires(dir string, packages ...string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	if err != nil {
+		return false
+	}
+	composer := struct {
+		Require map[string]string `json:"require"`
+	}{}
+	if err := json.Unmarshal(data, &composer); err != nil {
+		return false
+	}
+	for _, p := range packages {
+		if _, ok := composer.Require[p]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package shipshape_test
+
+import (
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	_ "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
+)
+
+func TestDetectStacks(t *testing.T) {
+	assert := assert.New(t)
+
+	names := []string{}
+	for _, s := range DetectStacks("testdata/scaffold") {
+		names = append(names, s.Name)
+	}
+	assert.Equal([]string{"drupal", "node", "docker", "lagoon"}, names)
+
+	assert.Empty(DetectStacks(t.TempDir()))
+}
+
+func TestScaffold(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	_, err := Scaffold(dir)
+	assert.EqualError(err, "no supported project type detected in '"+dir+"'")
+
+	data, err := Scaffold("testdata/scaffold")
+	assert.NoError(err)
+	assert.Contains(string(data), "# Generated by shipshape init for: drupal, node, docker, lagoon.\n")
+
+	cfg := config.Config{}
+	assert.NoError(yaml.Unmarshal(data, &cfg))
+	assert.Len(cfg.Checks["drupal-file-module"], 2)
+	assert.Len(cfg.Checks["drupal-db-module"], 2)
+	// From both the drupal & node presets.
+	assert.Len(cfg.Checks["json"], 5)
+	assert.Len(cfg.Checks["dependency-audit"], 1)
+	assert.Len(cfg.Checks["file"], 1)
+	assert.Len(cfg.Checks["yamllint"], 1)
+	assert.Len(cfg.Checks["yaml"], 1)
+}
@@ -0,0 +1,37 @@
+package shipshape
+
+import (
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+)
+
+// FailSeverity is set from the command line to override the config's
+// fail-severity; breaches below it are then informational and do not fail
+// their check.
+var FailSeverity config.Severity
+
+// ClassifyBreaches counts the unresolved breaches at or above the fail
+// severity as failing, the other ones being informational. When informational
+// is true, results having only informational breaches pass.
+func ClassifyBreaches(rl *result.ResultList, threshold config.Severity, informational bool) {
+	rl.FailingBreaches = 0
+	rl.InformationalBreaches = 0
+	for i := range rl.Results {
+		r := &rl.Results[i]
+		failing := 0
+		for _, b := range r.Breaches {
+			if isResolved(b) {
+				continue
+			}
+			if config.Severity(b.GetSeverity()).Compare(threshold) >= 0 {
+				failing++
+				rl.FailingBreaches++
+			} else {
+				rl.InformationalBreaches++
+			}
+		}
+		if informational && failing == 0 && r.Status == result.Fail {
+			r.Status = result.Pass
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package shipshape_test
+
+import (
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyBreaches(t *testing.T) {
+	assert := assert.New(t)
+
+	newBreach := func(severity string) result.Breach {
+		b := &result.ValueBreach{Value: "foo"}
+		b.SetCommonValues("file", "a", severity)
+		return b
+	}
+	remediated := newBreach("critical")
+	remediated.SetRemediation(result.RemediationStatusSuccess, "fixed")
+	newResultList := func() result.ResultList {
+		rl := result.NewResultList(false)
+		rl.Results = []result.Result{
+			{Name: "a", Status: result.Fail, Breaches: []result.Breach{
+				newBreach("low"), newBreach("normal")}},
+			{Name: "b", Status: result.Fail, Breaches: []result.Breach{
+				newBreach("low"), newBreach("high")}},
+			{Name: "c", Status: result.Pass, Breaches: []result.Breach{remediated}},
+			{Name: "d", Status: result.Pass},
+		}
+		return rl
+	}
+
+	t.Run("statusKept", func(t *testing.T) {
+		rl := newResultList()
+		ClassifyBreaches(&rl, config.HighSeverity, false)
+		assert.Equal(uint32(1), rl.FailingBreaches)
+		assert.Equal(uint32(3), rl.InformationalBreaches)
+		assert.Equal(result.Fail, rl.Results[0].Status)
+		assert.Equal(result.Fail, rl.Results[1].Status)
+	})
+
+	t.Run("informational", func(t *testing.T) {
+		rl := newResultList()
+		ClassifyBreaches(&rl, config.HighSeverity, true)
+		assert.Equal(uint32(1), rl.FailingBreaches)
+		assert.Equal(uint32(3), rl.InformationalBreaches)
+		assert.Equal(result.Pass, rl.Results[0].Status)
+		assert.Equal(result.Fail, rl.Results[1].Status)
+		assert.Equal(result.Pass, rl.Results[2].Status)
+		assert.Equal(result.Fail, rl.Status())
+	})
+
+	t.Run("allFailing", func(t *testing.T) {
+		rl := newResultList()
+		ClassifyBreaches(&rl, config.LowSeverity, true)
+		assert.Equal(uint32(4), rl.FailingBreaches)
+		assert.Equal(uint32(0), rl.InformationalBreaches)
+		assert.Equal(result.Fail, rl.Results[0].Status)
+	})
+}
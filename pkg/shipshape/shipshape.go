@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/salsadigitalauorg/shipshape/pkg/command"
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/lagoon"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
@@ -19,7 +22,7 @@ import (
 
 var RunConfig config.Config
 var RunResultList result.ResultList
-var OutputFormats = []string{"json", "junit", "simple", "table"}
+var OutputFormats = []string{"coverage", "json", "junit", "simple", "table", "template"}
 
 func Init(projectDir string, configFiles []string, checkTypesToRun []string, excludeDb bool, remediate bool, logLevel string, lagoonApiBaseUrl string, lagoonApiToken string) error {
 	if logLevel == "" {
@@ -37,6 +40,30 @@ func Init(projectDir string, configFiles []string, checkTypesToRun []string, exc
 		return err
 	}
 
+	if err := ValidateEscalation(RunConfig.Escalation); err != nil {
+		return err
+	}
+
+	if err := ValidateGate(RunConfig.Remediation.Gate); err != nil {
+		return err
+	}
+
+	if err := InitRemediationLimits(RunConfig.Remediation); err != nil {
+		return err
+	}
+
+	if err := RunConfig.Naming.Validate(RunConfig.Checks); err != nil {
+		return err
+	}
+
+	if err := ValidateDependencies(RunConfig.Checks); err != nil {
+		return err
+	}
+
+	if FailSeverity != "" {
+		RunConfig.FailSeverity = FailSeverity
+	}
+
 	config.ProjectDir = RunConfig.ProjectDir
 	RunResultList = result.NewResultList(remediate)
 
@@ -52,6 +79,9 @@ func Init(projectDir string, configFiles []string, checkTypesToRun []string, exc
 		lagoon.ApiBaseUrl = RunConfig.LagoonApiBaseUrl
 	}
 	lagoon.ApiToken = lagoonApiToken
+	if lagoon.ApiToken == "" {
+		lagoon.ApiToken = RunConfig.Credentials.LagoonApiToken
+	}
 
 	log.WithFields(log.Fields{
 		"ProjectDir":    RunConfig.ProjectDir,
@@ -73,14 +103,76 @@ func Init(projectDir string, configFiles []string, checkTypesToRun []string, exc
 	log.Print("filtering checks")
 	RunConfig.FilterChecksToRun(checkTypesToRun, excludeDb)
 	log.WithField("checksCount", checksCount).Print("checks filtered")
+
+	RunProject = DetectProject(RunConfig.ProjectDir)
+	log.WithField("project", fmt.Sprintf("%+v", RunProject)).Print("project detected")
+	if err := FilterChecksByWhen(); err != nil {
+		return err
+	}
 	jsonChecks, _ := json.Marshal(RunConfig.Checks)
 	log.WithFields(log.Fields{
-		"Checks": string(jsonChecks),
+		"Checks": config.RedactString(string(jsonChecks)),
 	}).Debug("checks initialised and filtered")
 
 	return nil
 }
 
+// skippedCheck is a check removed from the config before the run, to be
+// reported as skipped.
+type skippedCheck struct {
+	check  config.Check
+	reason result.SkipReason
+	msg    string
+}
+
+// skippedChecks are the checks disabled or whose condition is not met.
+var skippedChecks []skippedCheck
+
+// FilterChecksByWhen removes the disabled checks and the checks whose
+// condition is not met, recording them to be reported as skipped.
+func FilterChecksByWhen() error {
+	skippedChecks = nil
+	newCm := config.CheckMap{}
+	for ct, checks := range RunConfig.Checks {
+		newChecks := []config.Check{}
+		for _, c := range checks {
+			if c.IsDisabled() {
+				log.WithFields(log.Fields{
+					"check-type": ct,
+					"check-name": c.GetName(),
+				}).Print("skipping check since it is disabled")
+				skippedChecks = append(skippedChecks, skippedCheck{c,
+					result.SkipReasonDisabled, "skipped since it is disabled in the config"})
+				continue
+			}
+			met, err := EvaluateWhen(c.GetWhen(), RunResultList)
+			if err != nil {
+				return fmt.Errorf("invalid condition for check '%s': %w", c.GetName(), err)
+			}
+			if !met {
+				log.WithFields(log.Fields{
+					"check-type": ct,
+					"check-name": c.GetName(),
+					"when":       c.GetWhen(),
+				}).Print("skipping check since its condition is not met")
+				reason := result.SkipReasonCondition
+				if isProjectCondition(c.GetWhen()) {
+					reason = result.SkipReasonPlatform
+				}
+				skippedChecks = append(skippedChecks, skippedCheck{c, reason,
+					fmt.Sprintf("skipped since its condition '%s' is not met", c.GetWhen())})
+				continue
+			}
+			newChecks = append(newChecks, c)
+		}
+		if len(newChecks) > 0 {
+			newCm[ct] = newChecks
+		}
+	}
+	RunConfig.Checks = newCm
+	return nil
+}
+
 func ReadAndParseConfig(projectDir string, files []string) error {
 	configData, err := FetchConfigData(files)
 	if err != nil {
@@ -112,7 +204,14 @@ func FetchConfigData(files []string) ([][]byte, error) {
 	for _, f := range files {
 		var data []byte
 		log.WithField("source", f).Info("fetching config")
-		if utils.StringIsUrl(f) {
+		if IsPreset(f) {
+			data, err = FetchPreset(f)
+			if err != nil {
+				log.WithField("preset", f).WithError(
+					err).Error("could not fetch config from preset")
+				return nil, err
+			}
+		} else if utils.StringIsUrl(f) {
 			data, err = utils.FetchContentFromUrl(f)
 			if err != nil {
 				log.WithField("url", f).WithError(
@@ -133,21 +232,70 @@ func FetchConfigData(files []string) ([][]byte, error) {
 }
 
 func ParseConfigData(configData [][]byte) error {
-	finalCfg := config.Config{}
-	for i, data := range configData {
+	// The variables are determined from all the files first, so that they
+	// can be used in any of them.
+	nodes := []*yaml.Node{}
+	vars := map[string]string{}
+	targetVars := map[string]string{}
+	for _, data := range configData {
 		log.Print("parsing config")
+		n := &yaml.Node{}
+		varsCfg := struct {
+			Vars    map[string]string        `yaml:"vars"`
+			Targets map[string]config.Target `yaml:"targets"`
+		}{}
+		err := yaml.Unmarshal(data, n)
+		if err == nil {
+			// Encrypted values are decrypted first, so that they can be
+			// used in variables as well.
+			err = config.DecryptValues(n)
+		}
+		if err == nil {
+			err = n.Decode(&varsCfg)
+		}
+		if err != nil {
+			log.WithError(err).Error("could not parse config")
+			return err
+		}
+		for name, v := range varsCfg.Vars {
+			vars[name] = v
+		}
+		for name, v := range varsCfg.Targets[DefaultTarget].Vars {
+			targetVars[name] = v
+		}
+		nodes = append(nodes, n)
+	}
+	for name, v := range targetVars {
+		vars[name] = v
+	}
+	if err := config.ExpandEnvVars(vars); err != nil {
+		log.WithError(err).Error("could not parse config")
+		return err
+	}
+
+	cfgs := []config.Config{}
+	naming := config.NamingConfig{}
+	for _, n := range nodes {
 		cfg := config.Config{}
-		if err := yaml.Unmarshal(data, &cfg); err != nil {
+		err := config.InterpolateVars(n, vars)
+		if err == nil {
+			err = n.Decode(&cfg)
+		}
+		if err != nil {
 			log.WithError(err).Error("could not parse config")
 			return err
 		}
+		naming.Merge(cfg.Naming)
+		cfgs = append(cfgs, cfg)
+	}
 
+	finalCfg := config.Config{}
+	for i, cfg := range cfgs {
+		// Legacy names are renamed before merging, so that the checks are
+		// merged into the ones using the current names.
+		cfg.Checks.RenameAliases(naming.Aliases)
 		if i == 0 {
 			finalCfg = cfg
-			if len(configData) == 1 {
-				RunConfig = finalCfg
-				return nil
-			}
 			continue
 		}
 
@@ -163,30 +311,212 @@ func ParseConfigData(configData [][]byte) error {
 
 func RunChecks() {
 	log.Print("preparing concurrent check runs")
-	var wg sync.WaitGroup
+	config.ResetPublishedData()
+	allChecks := []config.Check{}
 	for ct, checks := range RunConfig.Checks {
-		checks := checks
 		RunResultList.IncrChecks(string(ct), len(checks))
-		for i := range checks {
-			wg.Add(1)
-			check := checks[i]
-			go func() {
-				defer wg.Done()
-				ProcessCheck(&RunResultList, check)
-			}()
+		allChecks = append(allChecks, checks...)
+	}
+	for _, s := range skippedChecks {
+		RunResultList.IncrChecks(string(s.check.GetType()), 1)
+	}
+
+	RunProgress.SetTotal(len(allChecks) + len(skippedChecks))
+	defer RunProgress.Finish()
+	for _, s := range skippedChecks {
+		skipCheck(s.check, result.Skipped, s.reason, s.msg)
+	}
+	start := utils.TimeNow()
+	for _, stage := range CheckStages(allChecks) {
+		if budgetReached(start) {
+			skipOverBudgetChecks(stage)
+			continue
+		}
+		checksByTarget := map[string][]config.Check{}
+		for _, c := range skipDependentChecks(skipNetworkChecks(stage)) {
+			t := c.GetTarget()
+			if t == "" {
+				t = DefaultTarget
+			}
+			checksByTarget[t] = append(checksByTarget[t], c)
+		}
+
+		// Targets are processed one after the other since the project
+		// directory and the shell commander are swapped for each of them.
+		targets := []string{}
+		for t := range checksByTarget {
+			targets = append(targets, t)
+		}
+		sort.Strings(targets)
+		for _, t := range targets {
+			if budgetReached(start) {
+				skipOverBudgetChecks(checksByTarget[t])
+				continue
+			}
+			RunTargetChecks(t, checksByTarget[t], start)
 		}
 	}
-	wg.Wait()
 	RunResultList.Sort()
 	RunResultList.RemediationTotalsCount()
+	EscalateResults(&RunResultList)
+	ClassifyBreaches(&RunResultList, RunConfig.FailSeverity, FailSeverity != "")
+	RunResultList.SetReasonCodes()
+}
+
+// CheckStages orders the checks into stages which are run one after the
+// other, so that checks consuming published data run after the checks
+// publishing it, and checks run after the checks they depend on.
+func CheckStages(checks []config.Check) [][]config.Check {
+	// Number of checks yet to run for each published data name & check name.
+	pendingPublishers := map[string]int{}
+	pendingNames := map[string]int{}
+	for _, c := range checks {
+		if p, ok := c.(config.DataPublisher); ok {
+			for _, name := range p.PublishesData() {
+				pendingPublishers[name]++
+			}
+		}
+		pendingNames[c.GetName()]++
+	}
+
+	isPending := func(c config.Check) bool {
+		for _, name := range c.GetDependsOn() {
+			if pendingNames[name] > 0 {
+				return true
+			}
+		}
+		consumer, ok := c.(config.DataConsumer)
+		if !ok {
+			return false
+		}
+		for _, name := range consumer.ConsumesData() {
+			if pendingPublishers[name] > 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	stages := [][]config.Check{}
+	for len(checks) > 0 {
+		stage := []config.Check{}
+		remaining := []config.Check{}
+		for _, c := range checks {
+			if isPending(c) {
+				remaining = append(remaining, c)
+			} else {
+				stage = append(stage, c)
+			}
+		}
+		// Circular dependencies; run the remaining checks, which will fail
+		// due to the missing data.
+		if len(stage) == 0 {
+			log.Error("circular dependency between checks publishing data")
+			stage, remaining = remaining, nil
+		}
+		for _, c := range stage {
+			if p, ok := c.(config.DataPublisher); ok {
+				for _, name := range p.PublishesData() {
+					pendingPublishers[name]--
+				}
+			}
+			pendingNames[c.GetName()]--
+		}
+		stages = append(stages, stage)
+		checks = remaining
+	}
+	return stages
+}
+
+// RunTargetChecks concurrently runs the checks against the named target,
+// or locally if the name is empty; checks are not started once the budget
+// of the run started at start is reached.
+func RunTargetChecks(name string, checks []config.Check, start time.Time) {
+	restore, ok := useTarget(name)
+	if !ok {
+		log.WithField("target", name).Error("unknown target")
+		for _, c := range checks {
+			c.AddBreach(&result.ValueBreach{
+				ValueLabel: "unknown target",
+				Value:      name,
+			})
+			c.GetResult().DetermineResultStatus(false)
+			RunResultList.AddResult(*c.GetResult())
+		}
+		return
+	}
+	defer restore()
+
+	limit := len(checks)
+	if MaxConcurrentChecks > 0 && MaxConcurrentChecks < limit {
+		limit = MaxConcurrentChecks
+	}
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := range checks {
+		check := checks[i]
+		slots <- struct{}{}
+		if budgetReached(start) {
+			<-slots
+			skipOverBudgetChecks([]config.Check{check})
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			ProcessCheck(&RunResultList, check)
+		}()
+	}
+	wg.Wait()
+}
+
+// useTarget swaps the project directory and the shell commander for the
+// target's, returning the function restoring them; the local project is
+// used if the name is empty.
+func useTarget(name string) (restore func(), ok bool) {
+	if name == "" {
+		return func() {}, true
+	}
+	target, ok := RunConfig.Targets[name]
+	if !ok {
+		return nil, false
+	}
+
+	curProjectDir := config.ProjectDir
+	curShellCommander := command.ShellCommander
+	if target.ProjectDir != "" {
+		config.ProjectDir = target.ProjectDir
+	}
+	command.ShellCommander = command.PrefixShellCommander(curShellCommander, target.Exec...)
+	return func() {
+		config.ProjectDir = curProjectDir
+		command.ShellCommander = curShellCommander
+	}, true
+}
+
+// skipCheck records the check as not run, with the reason's details as a
+// warning.
+func skipCheck(c config.Check, status result.Status, reason result.SkipReason, msg string) {
+	RunProgress.Start(c.GetName())
+	r := c.GetResult()
+	r.Status = status
+	r.SkipReason = reason
+	r.Warnings = append(r.Warnings, msg)
+	RunResultList.AddResult(*r)
+	RunProgress.Done()
 }
 
 func ProcessCheck(rl *result.ResultList, c config.Check) {
 	contextLogger := log.WithFields(log.Fields{
 		"check-type": c.GetType(),
 		"check-name": c.GetName(),
+		"target":     c.GetTarget(),
 	})
 	contextLogger.Print("processing check")
+	RunProgress.Start(c.GetName())
+	defer RunProgress.Done()
+	start := time.Now()
 	if c.RequiresData() {
 		contextLogger.Print("fetching data")
 		c.FetchData()
@@ -194,16 +524,29 @@ func ProcessCheck(rl *result.ResultList, c config.Check) {
 		if len(c.GetResult().Breaches) == 0 {
 			c.UnmarshalDataMap()
 		}
+		// Breaches at this stage mean the data could not be fetched.
+		c.GetResult().DataError = len(c.GetResult().Breaches) > 0
 	}
 	if len(c.GetResult().Breaches) == 0 && len(c.GetResult().Passes) == 0 {
 		contextLogger.Print("running check")
 		c.RunCheck()
 	}
 	if len(c.GetResult().Breaches) > 0 && c.ShouldPerformRemediation() {
-		contextLogger.Print("performing remediation")
-		c.Remediate()
+		if approved, reason := AwaitRemediationApproval(c); approved {
+			release := AcquireRemediation(c)
+			contextLogger.Print("performing remediation")
+			c.Remediate()
+			release()
+		} else {
+			contextLogger.WithField("reason", reason).Warn("remediation not approved")
+			skipRemediation(c.GetResult(), reason)
+		}
+	}
+	if c.IsSensitive() {
+		c.GetResult().MaskBreaches()
 	}
 	c.GetResult().DetermineResultStatus(c.ShouldPerformRemediation())
+	c.GetResult().Duration = time.Since(start).Seconds()
 	contextLogger.
 		WithFields(log.Fields{"result": c.GetResult()}).
 		Print("check processed")
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	yamlchecks "github.com/salsadigitalauorg/shipshape/pkg/checks/yaml"
 	"github.com/salsadigitalauorg/shipshape/pkg/config"
 	"github.com/salsadigitalauorg/shipshape/pkg/result"
 	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
@@ -131,6 +132,113 @@ checks:
 		assert.Equal("My second test check 2", tc22.Name)
 		assert.Equal("zap", tc22.Bar)
 	})
+
+	t.Run("aliases", func(t *testing.T) {
+		testchecks.RegisterChecks()
+		logrus.SetOutput(io.Discard)
+		base := `
+naming:
+  pattern: ^[a-z0-9-]+$
+checks:
+  test-check-1:
+    - name: test-check
+      foo: baz
+`
+		override := `
+naming:
+  aliases:
+    My test check: test-check
+checks:
+  test-check-1:
+    - name: My test check
+      severity: high
+  test-check-2:
+    - name: dependent
+      depends-on: [My test check]
+`
+		err := ParseConfigData([][]byte{[]byte(base), []byte(override)})
+		assert.NoError(err)
+		assert.Equal(config.NamingConfig{
+			Pattern: "^[a-z0-9-]+$",
+			Aliases: map[string]string{"My test check": "test-check"},
+		}, RunConfig.Naming)
+
+		// The check using the legacy name is merged into the current one.
+		if !assert.Len(RunConfig.Checks[testchecks.TestCheck1], 1) {
+			t.FailNow()
+		}
+		tc1 := RunConfig.Checks[testchecks.TestCheck1][0].(*testchecks.TestCheck1Check)
+		assert.Equal("test-check", tc1.Name)
+		assert.Equal(config.HighSeverity, tc1.Severity)
+		assert.Equal("baz", tc1.Foo)
+		assert.Equal([]string{"test-check"}, RunConfig.Checks[testchecks.TestCheck2][0].GetDependsOn())
+		assert.NoError(RunConfig.Naming.Validate(RunConfig.Checks))
+	})
+
+	t.Run("vars", func(t *testing.T) {
+		testchecks.RegisterChecks()
+		logrus.SetOutput(io.Discard)
+		defer func() { DefaultTarget = "" }()
+		data := `
+vars:
+  domain: example.com
+  docroot: web
+checks:
+  test-check-1:
+    - name: My test check 1
+      foo: 'https://{{ vars.domain }}/{{ vars.docroot }}'
+`
+		targets := `
+targets:
+  site-a:
+    exec: [ssh, site-a]
+    vars:
+      domain: a.example.com
+`
+		err := ParseConfigData([][]byte{[]byte(data), []byte(targets)})
+		assert.NoError(err)
+		tc1 := RunConfig.Checks[testchecks.TestCheck1][0].(*testchecks.TestCheck1Check)
+		assert.Equal("https://example.com/web", tc1.Foo)
+
+		// The target's variables override the config's.
+		DefaultTarget = "site-a"
+		err = ParseConfigData([][]byte{[]byte(data), []byte(targets)})
+		assert.NoError(err)
+		tc1 = RunConfig.Checks[testchecks.TestCheck1][0].(*testchecks.TestCheck1Check)
+		assert.Equal("https://a.example.com/web", tc1.Foo)
+
+		err = ParseConfigData([][]byte{[]byte(`
+checks:
+  test-check-1:
+    - name: My test check 1
+      foo: '{{ vars.alias }}'
+`)})
+		assert.EqualError(err, "undefined variable 'alias' in config")
+	})
+
+	t.Run("varsFromEnv", func(t *testing.T) {
+		testchecks.RegisterChecks()
+		logrus.SetOutput(io.Discard)
+		t.Setenv("SITE_DOMAIN", "env.example.com")
+		data := `
+vars:
+  domain: ${env.SITE_DOMAIN}
+checks:
+  test-check-1:
+    - name: My test check 1
+      foo: https://${var.domain}/home
+`
+		err := ParseConfigData([][]byte{[]byte(data)})
+		assert.NoError(err)
+		tc1 := RunConfig.Checks[testchecks.TestCheck1][0].(*testchecks.TestCheck1Check)
+		assert.Equal("https://env.example.com/home", tc1.Foo)
+
+		err = ParseConfigData([][]byte{[]byte(`
+vars:
+  domain: ${env.SITE_DOMAIN_UNSET}
+`)})
+		assert.EqualError(err, "undefined environment variable 'SITE_DOMAIN_UNSET' in variable 'domain'")
+	})
 }
 
 func TestRunChecks(t *testing.T) {
@@ -161,6 +269,11 @@ func TestRunChecks(t *testing.T) {
 		string(testchecks.TestCheck1): 1,
 		string(testchecks.TestCheck2): 1,
 	}, RunResultList.BreachCountByType)
+	// Durations vary between runs, so they are verified separately.
+	for i := range RunResultList.Results {
+		assert.Greater(RunResultList.Results[i].Duration, float64(0))
+		RunResultList.Results[i].Duration = 0
+	}
 	assert.ElementsMatch([]result.Result{
 		{
 			Name:      "test1stcheck",
@@ -175,7 +288,8 @@ func TestRunChecks(t *testing.T) {
 				Severity:   "normal",
 				Value:      "no data available",
 			}},
-			Warnings: []string(nil),
+			Warnings:  []string(nil),
+			DataError: true,
 		},
 		{
 			Name:      "test2ndcheck",
@@ -190,7 +304,248 @@ func TestRunChecks(t *testing.T) {
 				Severity:   "normal",
 				Value:      "no data available",
 			}},
-			Warnings: []string(nil),
+			Warnings:  []string(nil),
+			DataError: true,
 		}},
 		RunResultList.Results)
+	assert.Equal([]result.ReasonCode{result.ReasonFactError, result.ReasonBreachesHigh}, RunResultList.ReasonCodes)
+}
+
+func TestRunChecksSensitive(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	c := &testchecks.TestCheck1Check{}
+	yaml.Unmarshal([]byte("name: test1stcheck\nsensitive: true"), c)
+	c.Init(testchecks.TestCheck1)
+	RunConfig = config.Config{
+		Checks: config.CheckMap{testchecks.TestCheck1: {c}},
+	}
+
+	RunResultList = result.NewResultList(false)
+	RunChecks()
+	assert.Equal([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "test-check-1",
+		CheckName:  "test1stcheck",
+		Severity:   "normal",
+		Value:      result.MaskValue("no data available"),
+	}}, RunResultList.Results[0].Breaches)
+}
+
+func TestRunChecksTargets(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	webCheck := &testchecks.TestCheck1Check{}
+	unknownCheck := &testchecks.TestCheck2Check{}
+	yaml.Unmarshal([]byte("name: webcheck\ntarget: web"), webCheck)
+	webCheck.Init(testchecks.TestCheck1)
+	yaml.Unmarshal([]byte("name: unknowncheck\ntarget: foo"), unknownCheck)
+	unknownCheck.Init(testchecks.TestCheck2)
+	RunConfig = config.Config{
+		Targets: map[string]config.Target{
+			"web": {Exec: []string{"docker", "exec", "web"}, ProjectDir: "/tmp/web"},
+		},
+		Checks: config.CheckMap{
+			testchecks.TestCheck1: {webCheck},
+			testchecks.TestCheck2: {unknownCheck},
+		},
+	}
+
+	config.ProjectDir = "/tmp/local"
+	RunResultList = result.NewResultList(false)
+	RunChecks()
+	assert.Equal("/tmp/local", config.ProjectDir)
+	assert.Equal(uint32(2), RunResultList.TotalChecks)
+	assert.EqualValues(map[string]int{"foo": 1, "web": 1}, RunResultList.BreachCountByTarget)
+	for i := range RunResultList.Results {
+		RunResultList.Results[i].Duration = 0
+	}
+	assert.EqualValues([]result.Result{
+		{
+			Name:      "unknowncheck",
+			Severity:  "normal",
+			CheckType: "test-check-2",
+			Target:    "foo",
+			Status:    "Fail",
+			Breaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "test-check-2",
+				CheckName:  "unknowncheck",
+				Severity:   "normal",
+				ValueLabel: "unknown target",
+				Value:      "foo",
+			}},
+		},
+		{
+			Name:      "webcheck",
+			Severity:  "normal",
+			CheckType: "test-check-1",
+			Target:    "web",
+			Status:    "Fail",
+			Breaches: []result.Breach{&result.ValueBreach{
+				BreachType: "value",
+				CheckType:  "test-check-1",
+				CheckName:  "webcheck",
+				Severity:   "normal",
+				Value:      "no data available",
+			}},
+			DataError: true,
+		}},
+		RunResultList.Results)
+}
+
+func TestRunChecksDefaultTarget(t *testing.T) {
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	assert := assert.New(t)
+
+	defer func() { DefaultTarget = "" }()
+	DefaultTarget = "foo"
+
+	c := &testchecks.TestCheck1Check{}
+	yaml.Unmarshal([]byte("name: test1stcheck"), c)
+	c.Init(testchecks.TestCheck1)
+	RunConfig = config.Config{
+		Checks: config.CheckMap{testchecks.TestCheck1: {c}},
+	}
+
+	RunResultList = result.NewResultList(false)
+	RunChecks()
+	assert.Equal([]result.Breach{&result.ValueBreach{
+		BreachType: "value",
+		CheckType:  "test-check-1",
+		CheckName:  "test1stcheck",
+		Severity:   "normal",
+		ValueLabel: "unknown target",
+		Value:      "foo",
+	}}, RunResultList.Results[0].Breaches)
+}
+
+func TestFilterChecksByWhen(t *testing.T) {
+	assert := assert.New(t)
+
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+
+	curProject := RunProject
+	defer func() {
+		RunProject = curProject
+		RunConfig = config.Config{}
+		FilterChecksByWhen()
+	}()
+	RunProject = Project{Platform: "drupal", Stacks: []string{"drupal"}}
+
+	always := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "always"}}
+	drupal := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "drupal", When: "platform==drupal"}}
+	node := &testchecks.TestCheck2Check{CheckBase: config.CheckBase{Name: "node", When: "stack==node"}}
+	never := &testchecks.TestCheck2Check{CheckBase: config.CheckBase{Name: "never", When: "{{ eq 1 2 }}"}}
+	disabled := &testchecks.TestCheck2Check{CheckBase: config.CheckBase{Name: "disabled", Disabled: true}}
+	// Dependencies which are not run are ignored.
+	dependent := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "dependent", DependsOn: []string{"node"}}}
+	for _, c := range []config.Check{always, drupal, dependent} {
+		c.Init(testchecks.TestCheck1)
+	}
+	for _, c := range []config.Check{node, never, disabled} {
+		c.Init(testchecks.TestCheck2)
+	}
+	RunConfig = config.Config{
+		Checks: config.CheckMap{
+			testchecks.TestCheck1: {always, drupal, dependent},
+			testchecks.TestCheck2: {node, never, disabled},
+		},
+	}
+	RunResultList = result.NewResultList(false)
+	assert.NoError(FilterChecksByWhen())
+	assert.Equal(config.CheckMap{testchecks.TestCheck1: {always, drupal, dependent}}, RunConfig.Checks)
+
+	// The checks filtered out are reported as skipped.
+	RunChecks()
+	assert.Equal(uint32(6), RunResultList.TotalChecks)
+	assert.Equal(6, RunResultList.CheckCountByType[string(testchecks.TestCheck1)]+
+		RunResultList.CheckCountByType[string(testchecks.TestCheck2)])
+	results := map[string]result.Result{}
+	for _, r := range RunResultList.Results {
+		results[r.Name] = r
+	}
+	assert.Equal(result.Skipped, results["node"].Status)
+	assert.Equal(result.SkipReasonPlatform, results["node"].SkipReason)
+	assert.Equal([]string{"skipped since its condition 'stack==node' is not met"},
+		results["node"].Warnings)
+	assert.Equal(result.Skipped, results["never"].Status)
+	assert.Equal(result.SkipReasonCondition, results["never"].SkipReason)
+	assert.Equal(result.Skipped, results["disabled"].Status)
+	assert.Equal(result.SkipReasonDisabled, results["disabled"].SkipReason)
+	assert.Equal([]string{"skipped since it is disabled in the config"},
+		results["disabled"].Warnings)
+	assert.Empty(results["dependent"].SkipReason)
+	assert.Empty(results["always"].SkipReason)
+
+	RunConfig.Checks[testchecks.TestCheck1] = append(RunConfig.Checks[testchecks.TestCheck1],
+		&testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "invalid", When: "sometimes"}})
+	assert.EqualError(FilterChecksByWhen(),
+		"invalid condition for check 'invalid': unknown condition 'sometimes'")
+}
+
+func TestCheckStages(t *testing.T) {
+	assert := assert.New(t)
+
+	publisher := &yamlchecks.YamlCheck{YamlBase: yamlchecks.YamlBase{
+		CheckBase: config.CheckBase{Name: "publisher"},
+		Publish:   []yamlchecks.Publication{{Name: "modules", Key: "$.module"}},
+	}}
+	chained := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{
+			CheckBase: config.CheckBase{Name: "chained"},
+			Publish:   []yamlchecks.Publication{{Name: "count", Key: "$", Aggregate: "count"}},
+		},
+		From: "modules",
+	}
+	consumer := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{CheckBase: config.CheckBase{Name: "consumer"}},
+		From:     "count",
+	}
+	unpublished := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{CheckBase: config.CheckBase{Name: "unpublished"}},
+		From:     "unknown",
+	}
+	other := &testchecks.TestCheck1Check{CheckBase: config.CheckBase{Name: "other"}}
+
+	stages := CheckStages([]config.Check{consumer, chained, other, unpublished, publisher})
+	assert.Equal([][]config.Check{
+		{other, unpublished, publisher},
+		{chained},
+		{consumer},
+	}, stages)
+
+	// Circular dependencies are run in a final stage.
+	cycleA := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{
+			CheckBase: config.CheckBase{Name: "a"},
+			Publish:   []yamlchecks.Publication{{Name: "a", Key: "$"}},
+		},
+		From: "b",
+	}
+	cycleB := &yamlchecks.YamlCheck{
+		YamlBase: yamlchecks.YamlBase{
+			CheckBase: config.CheckBase{Name: "b"},
+			Publish:   []yamlchecks.Publication{{Name: "b", Key: "$"}},
+		},
+		From: "a",
+	}
+	currLogOut := logrus.StandardLogger().Out
+	defer logrus.SetOutput(currLogOut)
+	logrus.SetOutput(io.Discard)
+	stages = CheckStages([]config.Check{other, cycleA, cycleB})
+	assert.Equal([][]config.Check{{other}, {cycleA, cycleB}}, stages)
 }
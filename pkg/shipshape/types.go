@@ -7,13 +7,28 @@ import (
 type JUnitError struct {
 	XMLName xml.Name `xml:"error"`
 	Message string   `xml:"message,attr"`
+	Type    string   `xml:"type,attr,omitempty"`
+}
+
+type JUnitFailure struct {
+	XMLName xml.Name `xml:"failure"`
+	Message string   `xml:"message,attr"`
+	Type    string   `xml:"type,attr,omitempty"`
+}
+
+type JUnitSkipped struct {
+	XMLName xml.Name `xml:"skipped"`
+	Message string   `xml:"message,attr"`
 }
 
 type JUnitTestCase struct {
 	XMLName   xml.Name `xml:"testcase"`
 	Name      string   `xml:"name,attr"`
 	ClassName string   `xml:"classname,attr"`
+	Time      string   `xml:"time,attr,omitempty"`
+	Skipped   *JUnitSkipped
 	Errors    []JUnitError
+	Failures  []JUnitFailure
 }
 
 type JUnitTestSuite struct {
@@ -21,6 +36,9 @@ type JUnitTestSuite struct {
 	Name      string   `xml:"name,attr"`
 	Tests     int      `xml:"tests,attr"`
 	Errors    int      `xml:"errors,attr"`
+	Failures  int      `xml:"failures,attr,omitempty"`
+	Skipped   int      `xml:"skipped,attr,omitempty"`
+	Time      string   `xml:"time,attr,omitempty"`
 	TestCases []JUnitTestCase
 }
 
@@ -29,5 +47,6 @@ type JUnitTestSuites struct {
 	XMLName    xml.Name `xml:"testsuites"`
 	Tests      uint32   `xml:"tests,attr"`
 	Errors     uint32   `xml:"errors,attr"`
+	Failures   uint32   `xml:"failures,attr,omitempty"`
 	TestSuites []JUnitTestSuite
 }
@@ -0,0 +1,111 @@
+package shipshape
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/config"
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+)
+
+var severityConditionRegex = regexp.MustCompile(`^severity\s*(>=|<=|==|=|>|<)\s*(\w+)$`)
+var projectConditionRegex = regexp.MustCompile(`^(platform|stack|hosting)\s*(==|=|!=)\s*([\w.-]+)$`)
+
+// isProjectCondition determines whether the condition is a comparison of the
+// detected project's platform, stacks or hosting.
+func isProjectCondition(when string) bool {
+	return projectConditionRegex.MatchString(strings.TrimSpace(when))
+}
+
+// EvaluateWhen determines whether an output should run based on its
+// condition, which can be one of:
+//   - empty or "always"
+//   - "breaches" or "no-breaches"
+//   - a severity comparison, e.g, "severity>=high", which is met if any of
+//     the breaches satisfies it
+//   - a comparison of the detected project's platform, stacks or hosting,
+//     e.g, "platform==drupal", "stack==node" or "hosting!=lagoon"
+//   - a Go template rendered against the ResultList, met if it renders to
+//     "true", e.g, `{{ gt .TotalBreaches 10 }}`; the detected project is
+//     available using the project function, e.g, `{{ project.HasStack "node" }}`,
+//     and the checks' previous results using the previousResult function
+func EvaluateWhen(when string, rl result.ResultList) (bool, error) {
+	when = strings.TrimSpace(when)
+	switch when {
+	case "", "always":
+		return true, nil
+	case "breaches":
+		return rl.TotalBreaches > 0, nil
+	case "no-breaches":
+		return rl.TotalBreaches == 0, nil
+	}
+
+	if m := severityConditionRegex.FindStringSubmatch(when); m != nil {
+		level := config.Severity(m[2])
+		if !level.IsValid() {
+			return false, fmt.Errorf("unknown severity '%s'", m[2])
+		}
+		for _, r := range rl.Results {
+			for _, b := range r.Breaches {
+				if compareSeverity(config.Severity(b.GetSeverity()).Compare(level), m[1]) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+
+	if m := projectConditionRegex.FindStringSubmatch(when); m != nil {
+		var met bool
+		switch m[1] {
+		case "platform":
+			met = RunProject.Platform == m[3]
+		case "stack":
+			met = RunProject.HasStack(m[3])
+		case "hosting":
+			met = RunProject.HasHosting(m[3])
+		}
+		if m[2] == "!=" {
+			return !met, nil
+		}
+		return met, nil
+	}
+
+	if !strings.Contains(when, "{{") {
+		return false, fmt.Errorf("unknown condition '%s'", when)
+	}
+	t, err := template.New("when").Funcs(template.FuncMap{
+		"project":        func() Project { return RunProject },
+		"previousResult": PreviousResult,
+	}).Parse(when)
+	if err != nil {
+		return false, err
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, rl); err != nil {
+		return false, err
+	}
+	met, err := strconv.ParseBool(strings.TrimSpace(out.String()))
+	if err != nil {
+		return false, fmt.Errorf("condition rendered to '%s' instead of a boolean", out.String())
+	}
+	return met, nil
+}
+
+func compareSeverity(cmp int, op string) bool {
+	switch op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	}
+	return cmp == 0
+}
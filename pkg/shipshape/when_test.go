@@ -0,0 +1,106 @@
+package shipshape_test
+
+import (
+	"testing"
+
+	"github.com/salsadigitalauorg/shipshape/pkg/result"
+	. "github.com/salsadigitalauorg/shipshape/pkg/shipshape"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateWhen(t *testing.T) {
+	noBreaches := result.ResultList{
+		TotalChecks: 1,
+		Results:     []result.Result{{Name: "a", Status: result.Pass}},
+	}
+	breaches := result.ResultList{
+		TotalChecks:   2,
+		TotalBreaches: 2,
+		Results: []result.Result{
+			{
+				Name:     "a",
+				Status:   result.Fail,
+				Breaches: []result.Breach{&result.ValueBreach{Severity: "normal"}},
+			},
+			{
+				Name:     "b",
+				Status:   result.Fail,
+				Breaches: []result.Breach{&result.ValueBreach{Severity: "high"}},
+			},
+		},
+	}
+
+	tt := []struct {
+		name        string
+		when        string
+		rl          result.ResultList
+		expected    bool
+		expectedErr string
+	}{
+		{name: "empty", when: "", rl: noBreaches, expected: true},
+		{name: "always", when: "always", rl: breaches, expected: true},
+		{name: "breachesNone", when: "breaches", rl: noBreaches, expected: false},
+		{name: "breaches", when: "breaches", rl: breaches, expected: true},
+		{name: "noBreaches", when: "no-breaches", rl: noBreaches, expected: true},
+		{name: "severityMet", when: "severity>=high", rl: breaches, expected: true},
+		{name: "severityNotMet", when: "severity >= critical", rl: breaches, expected: false},
+		{name: "severityEqual", when: "severity==normal", rl: breaches, expected: true},
+		{name: "severityLower", when: "severity<normal", rl: breaches, expected: false},
+		{name: "severityNoBreaches", when: "severity>=low", rl: noBreaches, expected: false},
+		{name: "severityUnknown", when: "severity>=urgent", rl: breaches, expectedErr: "unknown severity 'urgent'"},
+		{name: "template", when: "{{ gt .TotalBreaches 1 }}", rl: breaches, expected: true},
+		{name: "templateNotMet", when: "{{ eq .TotalChecks 5 }}", rl: breaches, expected: false},
+		{name: "templateNotBool", when: "{{ .TotalChecks }}", rl: breaches, expectedErr: "condition rendered to '2' instead of a boolean"},
+		{name: "unknown", when: "sometimes", rl: breaches, expectedErr: "unknown condition 'sometimes'"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			met, err := EvaluateWhen(tc.when, tc.rl)
+			if tc.expectedErr != "" {
+				assert.EqualError(err, tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expected, met)
+		})
+	}
+}
+
+func TestEvaluateWhenProject(t *testing.T) {
+	curProject := RunProject
+	defer func() { RunProject = curProject }()
+	RunProject = Project{
+		Platform: "drupal",
+		Stacks:   []string{"drupal", "node"},
+		Versions: map[string]string{"drupal": "10.1.6"},
+		Hosting:  []string{"lagoon"},
+	}
+
+	tt := []struct {
+		name     string
+		when     string
+		expected bool
+	}{
+		{name: "platform", when: "platform==drupal", expected: true},
+		{name: "platformNotMet", when: "platform = wordpress", expected: false},
+		{name: "platformNot", when: "platform!=wordpress", expected: true},
+		{name: "stack", when: "stack==node", expected: true},
+		{name: "stackNotMet", when: "stack==python", expected: false},
+		{name: "hosting", when: "hosting==lagoon", expected: true},
+		{name: "hostingNot", when: "hosting!=lagoon", expected: false},
+		{name: "template", when: `{{ project.HasStack "node" }}`, expected: true},
+		{name: "templateVersion", when: `{{ eq (index project.Versions "drupal") "10.1.6" }}`, expected: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			met, err := EvaluateWhen(tc.when, result.ResultList{})
+			assert.NoError(err)
+			assert.Equal(tc.expected, met)
+		})
+	}
+}
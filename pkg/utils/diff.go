@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FileSnapshot holds the contents of files at a point in time, keyed by path
+// relative to its directory, to determine the changes made to them since.
+type FileSnapshot struct {
+	Dir      string
+	Files    []string
+	Contents map[string][]byte
+}
+
+// SnapshotFiles reads the files, relative to the directory; files which do
+// not exist are recorded as empty.
+func SnapshotFiles(dir string, files []string) FileSnapshot {
+	s := FileSnapshot{Dir: dir, Files: files, Contents: map[string][]byte{}}
+	for _, f := range files {
+		s.Contents[f], _ = os.ReadFile(filepath.Join(dir, f))
+	}
+	return s
+}
+
+// Diff generates a unified diff of the changes made to the files since the
+// snapshot; files left unchanged are omitted.
+func (s FileSnapshot) Diff() string {
+	diffs := []string{}
+	for _, f := range s.Files {
+		before := s.Contents[f]
+		after, _ := os.ReadFile(filepath.Join(s.Dir, f))
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(before),
+			B:        splitLines(after),
+			FromFile: "a/" + f,
+			ToFile:   "b/" + f,
+			Context:  3,
+		})
+		if diff != "" {
+			diffs = append(diffs, diff)
+		}
+	}
+	return strings.Join(diffs, "")
+}
+
+// splitLines splits the content into lines, keeping the line endings;
+// difflib.SplitLines adds an empty line when the content ends with one.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,37 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSnapshotDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "settings.php"), []byte("a\nb\nc\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "unchanged.txt"), []byte("same\n"), 0644)
+
+	s := SnapshotFiles(dir, []string{"settings.php", "unchanged.txt", "new.txt"})
+	assert.Empty(s.Diff())
+
+	os.WriteFile(filepath.Join(dir, "settings.php"), []byte("a\nB\nc\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "new.txt"), []byte("created\n"), 0644)
+	assert.Equal(`--- a/settings.php
++++ b/settings.php
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+--- a/new.txt
++++ b/new.txt
+@@ -0,0 +1 @@
++created
+`, s.Diff())
+}
@@ -0,0 +1,50 @@
+package utils_test
+
+import (
+	"testing"
+
+	. "github.com/salsadigitalauorg/shipshape/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShellQuote(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("devel", ShellQuote("devel"))
+	assert.Equal("system.site:name=a/b", ShellQuote("system.site:name=a/b"))
+	assert.Equal("''", ShellQuote(""))
+	assert.Equal("'My site'", ShellQuote("My site"))
+	assert.Equal(`'it'\''s'`, ShellQuote("it's"))
+	assert.Equal("'devel -y --uri=evil'", ShellQuote("devel -y --uri=evil"))
+}
+
+func TestShellSplit(t *testing.T) {
+	assert := assert.New(t)
+
+	tt := map[string][]string{
+		"":                                       {},
+		"  cr  ":                                 {"cr"},
+		"pmu devel -y":                           {"pmu", "devel", "-y"},
+		`cset system.site name 'My site' -y`:     {"cset", "system.site", "name", "My site", "-y"},
+		`cset system.site name "My \"site\"" -y`: {"cset", "system.site", "name", `My "site"`, "-y"},
+		`a\ b "c\d" ''`:                          {"a b", `c\d`, ""},
+		`'it'\''s'`:                              {"it's"},
+	}
+	for in, expected := range tt {
+		words, err := ShellSplit(in)
+		assert.NoError(err, in)
+		assert.Equal(expected, words, in)
+	}
+
+	for _, s := range []string{"My site", "it's", `a "b" \c`, "", "x\ty"} {
+		words, err := ShellSplit("cset " + ShellQuote(s))
+		assert.NoError(err)
+		assert.Equal([]string{"cset", s}, words)
+	}
+
+	_, err := ShellSplit("cset 'My site")
+	assert.EqualError(err, "unterminated quote")
+	_, err = ShellSplit(`cset site\`)
+	assert.EqualError(err, "unterminated escape")
+}
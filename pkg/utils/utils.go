@@ -14,10 +14,13 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/vmware-labs/yaml-jsonpath/pkg/yamlpath"
 	"github.com/hashicorp/go-version"
+	"github.com/vmware-labs/yaml-jsonpath/pkg/yamlpath"
 	"gopkg.in/yaml.v3"
 )
 
@@ -175,6 +178,33 @@ func StringSliceContains(slice []string, item string) bool {
 	return ok
 }
 
+// MatchString determines whether an item matches a pattern, which can be a
+// regular expression prefixed with "re:", e.g, "re:^drupal/.*", a glob
+// prefixed with "glob:", e.g, "glob:*-dev", or an exact string otherwise.
+// Invalid regular expressions and globs never match.
+func MatchString(pattern string, item string) bool {
+	if re, found := strings.CutPrefix(pattern, "re:"); found {
+		matched, err := regexp.MatchString(re, item)
+		return err == nil && matched
+	}
+	if glob, found := strings.CutPrefix(pattern, "glob:"); found {
+		matched, err := filepath.Match(glob, item)
+		return err == nil && matched
+	}
+	return pattern == item
+}
+
+// StringSliceMatchAny determines whether an item matches any of the patterns
+// in a slice; see MatchString for the supported patterns.
+func StringSliceMatchAny(slice []string, item string) bool {
+	for _, p := range slice {
+		if MatchString(p, item) {
+			return true
+		}
+	}
+	return false
+}
+
 // IntSliceContains determines whether an item exists in a slice of int.
 func IntSliceContains(slice []int, item int) bool {
 	if len(slice) == 0 {
@@ -209,7 +239,8 @@ func StringSlicesIntersect(slc1 []string, slc2 []string) []string {
 	return intersect
 }
 
-// StringSlicesIntersectUnique returns the unique strings in slc2 that also present in slc1.
+// StringSlicesIntersectUnique returns the unique strings in slc2 that also
+// present in slc1, in the order of slc2.
 func StringSlicesIntersectUnique(slc1, slc2 []string) []string {
 	// Convert slc1 to a map for faster lookup.
 	map1 := make(map[string]struct{})
@@ -217,19 +248,20 @@ func StringSlicesIntersectUnique(slc1, slc2 []string) []string {
 		map1[x] = struct{}{}
 	}
 
-	intersect := make(map[string]struct{})
+	intersect := []string(nil)
+	seen := make(map[string]struct{})
 	for _, x := range slc2 {
-		if _, found := map1[x]; found {
-			intersect[x] = struct{}{}
+		if _, found := map1[x]; !found {
+			continue
 		}
+		if _, dup := seen[x]; dup {
+			continue
+		}
+		seen[x] = struct{}{}
+		intersect = append(intersect, x)
 	}
 
-	keys := []string(nil)
-	for k := range intersect {
-		keys = append(keys, k)
-	}
-
-	return keys
+	return intersect
 }
 
 // StringSlicesInterdiff returns the strings in slc2 that do not present in slc1.
@@ -250,7 +282,8 @@ func StringSlicesInterdiff(slc1 []string, slc2 []string) []string {
 	return interdiff
 }
 
-// StringSlicesInterdiffUnique returns the unique strings in slc2 that do not present in slc1.
+// StringSlicesInterdiffUnique returns the unique strings in slc2 that do not
+// present in slc1, in the order of slc2.
 func StringSlicesInterdiffUnique(slc1, slc2 []string) []string {
 	// Convert slc1 to a map for faster lookup.
 	map1 := make(map[string]struct{})
@@ -258,18 +291,30 @@ func StringSlicesInterdiffUnique(slc1, slc2 []string) []string {
 		map1[x] = struct{}{}
 	}
 
-	interdiff := make(map[string]struct{})
+	interdiff := []string(nil)
+	seen := make(map[string]struct{})
 	for _, x := range slc2 {
-		if _, found := map1[x]; !found {
-			interdiff[x] = struct{}{}
+		if _, found := map1[x]; found {
+			continue
 		}
+		if _, dup := seen[x]; dup {
+			continue
+		}
+		seen[x] = struct{}{}
+		interdiff = append(interdiff, x)
 	}
 
-	keys := []string(nil)
-	for k := range interdiff {
+	return interdiff
+}
+
+// SortedKeys returns the keys of the map sorted, so that the map can be
+// iterated over in a deterministic order.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
 		keys = append(keys, k)
 	}
-
+	sort.Strings(keys)
 	return keys
 }
 
@@ -279,9 +324,14 @@ func StringIsUrl(s string) bool {
 	return err == nil && u.Scheme != "" && u.Host != ""
 }
 
+// HttpClient is the client used for the requests made to remote apis &
+// services; unlike http.DefaultClient, its requests time out. It can be
+// overridden in tests.
+var HttpClient = &http.Client{Timeout: 30 * time.Second}
+
 // FetchContentFromUrl fetches the content from a url and returns its bytes.
 func FetchContentFromUrl(u string) ([]byte, error) {
-	rsp, err := http.Get(u)
+	rsp, err := HttpClient.Get(u)
 	if err != nil {
 		return []byte(nil), err
 	}
@@ -410,3 +460,262 @@ func HasComposerDependency(loc string, deps []string) (bool, error) {
 
 	return false, nil
 }
+
+// TimeNow provides a wrapper around time.Now to allow for better testing and
+// mocking.
+var TimeNow = time.Now
+
+// ParseDuration parses a duration string such as "72h", additionally
+// supporting days (e.g, "7d") and weeks (e.g, "2w") as a whole-number prefix.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s'", s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// HumanizeDuration formats a duration using its largest whole unit, from
+// minutes to days, e.g, "3 days" or "1 hour".
+func HumanizeDuration(d time.Duration) string {
+	units := []struct {
+		name string
+		d    time.Duration
+	}{{"day", 24 * time.Hour}, {"hour", time.Hour}, {"minute", time.Minute}}
+	for _, u := range units {
+		n := int(d / u.d)
+		if n == 1 {
+			return "1 " + u.name
+		} else if n > 1 {
+			return fmt.Sprintf("%d %ss", n, u.name)
+		}
+	}
+	return "less than a minute"
+}
+
+// ParseSize parses a number, optionally suffixed with the K, M, G or T
+// multipliers (powers of 1024) as used in php.ini, e.g, "256M"; the
+// multiplier can be followed by B, e.g, "20GB".
+func ParseSize(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	multiplier := float64(1)
+	if len(s) > 2 && strings.ToUpper(s[len(s)-1:]) == "B" &&
+		strings.ContainsAny(strings.ToUpper(s[len(s)-2:len(s)-1]), "KMGT") {
+		s = s[:len(s)-1]
+	}
+	if len(s) > 1 {
+		switch strings.ToUpper(s[len(s)-1:]) {
+		case "K":
+			multiplier = 1024
+		case "M":
+			multiplier = 1024 * 1024
+		case "G":
+			multiplier = 1024 * 1024 * 1024
+		case "T":
+			multiplier = 1024 * 1024 * 1024 * 1024
+		}
+		if multiplier > 1 {
+			s = s[:len(s)-1]
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number '%s'", s)
+	}
+	return n * multiplier, nil
+}
+
+// FormatSize formats a number of bytes using the largest of the K, M, G or T
+// multipliers understood by ParseSize, e.g, "1.5G".
+func FormatSize(n float64) string {
+	units := []string{"", "K", "M", "G", "T"}
+	i := 0
+	for ; i < len(units)-1 && n >= 1024; i++ {
+		n /= 1024
+	}
+	if i == 0 {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(n, 'f', 1, 64) + units[i]
+}
+
+// TimestampLayouts is the list of layouts tried when parsing a timestamp
+// without an explicit layout.
+var TimestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ParseTimestamp parses a timestamp using the provided layout. The special
+// layout "unix" expects seconds since the epoch. If no layout is provided,
+// unix timestamps and the TimestampLayouts are tried in turn.
+func ParseTimestamp(value string, layout string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if layout == "unix" || layout == "" {
+		if secs, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.Unix(int64(secs), 0), nil
+		} else if layout == "unix" {
+			return time.Time{}, fmt.Errorf("invalid unix timestamp '%s'", value)
+		}
+	}
+	if layout != "" {
+		return time.Parse(layout, value)
+	}
+	for _, l := range TimestampLayouts {
+		if t, err := time.Parse(l, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse timestamp '%s'", value)
+}
+
+var versionRegex = regexp.MustCompile(`(!=|>=|<=|>|<|=)?\s*v?(\d+(\.\d+)*)`)
+
+// MinimumVersion extracts the lowest version allowed by a version or a range
+// such as "^18.2", ">=3.9,<4" or "v20.11.0". Upper bounds are ignored, and an
+// error is returned if the range has no lower bound, e.g, "<20".
+func MinimumVersion(value string) (*version.Version, error) {
+	matches := versionRegex.FindAllStringSubmatch(value, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no version found in '%s'", value)
+	}
+
+	var min *version.Version
+	for _, m := range matches {
+		if op := m[1]; op == "<" || op == "<=" || op == "!=" {
+			continue
+		}
+		v, err := version.NewVersion(m[2])
+		if err != nil {
+			return nil, err
+		}
+		if min == nil || v.LessThan(min) {
+			min = v
+		}
+	}
+	if min == nil {
+		return nil, fmt.Errorf("no minimum version found in '%s'", value)
+	}
+	return min, nil
+}
+
+// ParseVersionConstraint parses a version constraint, e.g, ">= 10.3, < 11",
+// also supporting caret ranges, e.g, "^10.3" for ">= 10.3, < 11.0"; a bare
+// version, e.g, "10.3", is a minimum version.
+func ParseVersionConstraint(value string) (version.Constraints, error) {
+	parts := []string{}
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if v, ok := strings.CutPrefix(p, "^"); ok {
+			upper, err := caretUpperBound(v)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, ">= "+v, "< "+upper)
+			continue
+		}
+		if p[0] >= '0' && p[0] <= '9' || p[0] == 'v' {
+			p = ">= " + p
+		}
+		parts = append(parts, p)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no version constraint found in '%s'", value)
+	}
+	return version.NewConstraint(strings.Join(parts, ", "))
+}
+
+// caretUpperBound determines the exclusive upper bound of a caret range,
+// incrementing its first non-zero segment, e.g, 11.0 for ^10.3 or 0.4 for
+// ^0.3.1.
+func caretUpperBound(value string) (string, error) {
+	v, err := version.NewVersion(value)
+	if err != nil {
+		return "", err
+	}
+	specified := len(strings.Split(strings.SplitN(strings.TrimPrefix(value, "v"), "-", 2)[0], "."))
+	segments := v.Segments()
+	if specified > len(segments) {
+		specified = len(segments)
+	}
+	i := 0
+	for i < specified-1 && segments[i] == 0 {
+		i++
+	}
+	upper := []string{}
+	for j := 0; j < i; j++ {
+		upper = append(upper, "0")
+	}
+	upper = append(upper, strconv.Itoa(segments[i]+1))
+	return strings.Join(upper, "."), nil
+}
+
+// SplitPackageVersion splits a package reference into its name and version
+// or version constraint, e.g, drupal/core & 10.3.6 for drupal/core:10.3.6 or
+// php & ^8.1 for php@^8.1.
+func SplitPackageVersion(s string) (string, string) {
+	if i := strings.IndexAny(s, ":@"); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+// PackageVersionAllowed determines whether a package's version is allowed by
+// one of the entries matching its name, as <name>, <name>:<constraint> or
+// <name>@<constraint>, e.g, drupal/core:^10.3. Only the core of the version
+// is verified, e.g, 8.1 for 8.1-alpine. Entries with a bare version, e.g,
+// php:8.1, are verified using PackageCheckString as a minimum version.
+// The constraints of the entries matching the name are returned as well, so
+// that a version not satisfying them can be reported; regex ("re:") and glob
+// ("glob:") entries are ignored.
+func PackageVersionAllowed(entries []string, name string, ver string) (bool, []string, error) {
+	constraints := []string{}
+	for _, e := range entries {
+		if strings.HasPrefix(e, "re:") || strings.HasPrefix(e, "glob:") {
+			continue
+		}
+		entryName, constraint := SplitPackageVersion(e)
+		if entryName != name {
+			continue
+		}
+		if constraint == "" || constraint == "latest" {
+			return true, nil, nil
+		}
+		if constraint[0] >= '0' && constraint[0] <= '9' {
+			if PackageCheckString([]string{e}, name, ver) {
+				return true, nil, nil
+			}
+			continue
+		}
+		constraints = append(constraints, constraint)
+		if ver == "" {
+			continue
+		}
+		c, err := ParseVersionConstraint(constraint)
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid version constraint '%s': %w", constraint, err)
+		}
+		v, err := version.NewVersion(ver)
+		if err != nil {
+			continue
+		}
+		if c.Check(v.Core()) {
+			return true, nil, nil
+		}
+	}
+	return false, constraints, nil
+}
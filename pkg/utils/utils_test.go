@@ -8,7 +8,9 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-version"
 	. "github.com/salsadigitalauorg/shipshape/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
@@ -273,6 +275,17 @@ func TestStringSliceContains(t *testing.T) {
 	assert.True(StringSliceContains([]string{"bar", "foo"}, "foo"))
 }
 
+func TestStringSliceMatchAny(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(StringSliceMatchAny([]string{"foo", "bar"}, "bar"))
+	assert.False(StringSliceMatchAny([]string{"foo", "bar"}, "ba"))
+	assert.True(StringSliceMatchAny([]string{"re:^drupal/(devel|kint)$"}, "drupal/devel"))
+	assert.False(StringSliceMatchAny([]string{"re:^drupal/(devel|kint)$"}, "drupal/devel_php"))
+	assert.False(StringSliceMatchAny([]string{"re:("}, "("))
+	assert.True(StringSliceMatchAny([]string{"glob:bitnami/*"}, "bitnami/kubectl"))
+	assert.False(StringSliceMatchAny([]string{"glob:bitnami/*"}, "library/php"))
+}
+
 func TestIntSliceContains(t *testing.T) {
 	assert := assert.New(t)
 	assert.False(IntSliceContains([]int{}, 10))
@@ -343,6 +356,14 @@ func TestStringSlicesIntersectUnique(t *testing.T) {
 	if len(intersect) != 2 || !reflect.DeepEqual(intersect, expectedIntersect) {
 		t.Errorf("Intersect should have 2 item, got '%+v'", intersect)
 	}
+
+	// The order of the second slice is kept.
+	intersect = StringSlicesIntersectUnique(
+		[]string{"foo", "baz", "zoom"}, []string{"zoom", "bar", "foo", "zoom"})
+	expectedIntersect = []string{"zoom", "foo"}
+	if !reflect.DeepEqual(intersect, expectedIntersect) {
+		t.Errorf("Intersect should be '%+v', got '%+v'", expectedIntersect, intersect)
+	}
 }
 
 func TestStringSlicesInterdiff(t *testing.T) {
@@ -401,6 +422,27 @@ func TestStringSlicesInterdiffUnique(t *testing.T) {
 	if len(interdiff) != 2 || !reflect.DeepEqual(interdiff, expectedInterdiff) {
 		t.Errorf("Interdiff should have 2 item, got '%+v'", interdiff)
 	}
+
+	// The order of the second slice is kept.
+	interdiff = StringSlicesInterdiffUnique(
+		[]string{"foo"}, []string{"zoo", "foo", "bar", "zoo"})
+	expectedInterdiff = []string{"zoo", "bar"}
+	if !reflect.DeepEqual(interdiff, expectedInterdiff) {
+		t.Errorf("Interdiff should be '%+v', got '%+v'", expectedInterdiff, interdiff)
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	keys := SortedKeys(map[string]int{"zoo": 1, "bar": 2, "foo": 3})
+	expectedKeys := []string{"bar", "foo", "zoo"}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Errorf("Keys should be '%+v', got '%+v'", expectedKeys, keys)
+	}
+
+	keys = SortedKeys(map[string]int{})
+	if len(keys) != 0 {
+		t.Errorf("Keys should be empty, got '%+v'", keys)
+	}
 }
 
 func TestStringIsUrl(t *testing.T) {
@@ -486,3 +528,210 @@ func TestPackageCheckString(t *testing.T) {
 	assert.True(PackageCheckString([]string{"bitnami/postgresql@16", "bitnami/kubectl"}, "bitnami/kubectl", "1.24"))
 	assert.True(PackageCheckString([]string{"bitnami/postgresql@16", "bitnami/kubectl:1.24"}, "bitnami/kubectl", "1.25"))
 }
+
+func TestParseDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	d, err := ParseDuration("72h")
+	assert.Nil(err)
+	assert.Equal(72*time.Hour, d)
+
+	d, err = ParseDuration("7d")
+	assert.Nil(err)
+	assert.Equal(7*24*time.Hour, d)
+
+	d, err = ParseDuration("2w")
+	assert.Nil(err)
+	assert.Equal(14*24*time.Hour, d)
+
+	_, err = ParseDuration("1.5d")
+	assert.EqualError(err, "invalid duration '1.5d'")
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("less than a minute", HumanizeDuration(30*time.Second))
+	assert.Equal("1 minute", HumanizeDuration(90*time.Second))
+	assert.Equal("59 minutes", HumanizeDuration(59*time.Minute))
+	assert.Equal("1 hour", HumanizeDuration(time.Hour))
+	assert.Equal("23 hours", HumanizeDuration(23*time.Hour+59*time.Minute))
+	assert.Equal("3 days", HumanizeDuration(3*24*time.Hour+5*time.Hour))
+}
+
+func TestParseSize(t *testing.T) {
+	assert := assert.New(t)
+
+	tt := map[string]float64{
+		"0":    0,
+		"-1":   -1,
+		"0.9":  0.9,
+		"128":  128,
+		"512k": 512 * 1024,
+		"256M": 256 * 1024 * 1024,
+		" 2G ": 2 * 1024 * 1024 * 1024,
+		"1.5M": 1.5 * 1024 * 1024,
+		"20GB": 20 * 1024 * 1024 * 1024,
+		"1t":   1024 * 1024 * 1024 * 1024,
+	}
+	for s, expected := range tt {
+		n, err := ParseSize(s)
+		assert.NoError(err)
+		assert.Equal(expected, n, s)
+	}
+
+	_, err := ParseSize("lots")
+	assert.EqualError(err, "invalid number 'lots'")
+	_, err = ParseSize("M")
+	assert.EqualError(err, "invalid number 'M'")
+	_, err = ParseSize("12B")
+	assert.EqualError(err, "invalid number '12B'")
+}
+
+func TestFormatSize(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0", FormatSize(0))
+	assert.Equal("512", FormatSize(512))
+	assert.Equal("1.0K", FormatSize(1024))
+	assert.Equal("1.5G", FormatSize(1.5*1024*1024*1024))
+	assert.Equal("2048.0T", FormatSize(2*1024*1024*1024*1024*1024))
+}
+
+func TestParseTimestamp(t *testing.T) {
+	assert := assert.New(t)
+
+	ts, err := ParseTimestamp("1700000000", "")
+	assert.Nil(err)
+	assert.Equal(int64(1700000000), ts.Unix())
+
+	ts, err = ParseTimestamp("1700000000", "unix")
+	assert.Nil(err)
+	assert.Equal(int64(1700000000), ts.Unix())
+
+	_, err = ParseTimestamp("2023-11-14", "unix")
+	assert.EqualError(err, "invalid unix timestamp '2023-11-14'")
+
+	ts, err = ParseTimestamp("2023-11-14T22:13:20Z", "")
+	assert.Nil(err)
+	assert.Equal(int64(1700000000), ts.Unix())
+
+	ts, err = ParseTimestamp("2023-11-14 22:13:20", "")
+	assert.Nil(err)
+	assert.Equal(int64(1700000000), ts.Unix())
+
+	ts, err = ParseTimestamp("14/11/2023", "02/01/2006")
+	assert.Nil(err)
+	assert.Equal("2023-11-14", ts.Format("2006-01-02"))
+
+	_, err = ParseTimestamp("yesterday", "")
+	assert.EqualError(err, "unable to parse timestamp 'yesterday'")
+}
+
+func TestMinimumVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	for value, expected := range map[string]string{
+		"18":              "18.0.0",
+		"v20.11.0":        "20.11.0",
+		"^18.2":           "18.2.0",
+		">=3.9,<4":        "3.9.0",
+		"<4,>=3.9":        "3.9.0",
+		"<21 >=16 || ^20": "16.0.0",
+		">= 16.0.0 < 21":  "16.0.0",
+		"~1.2.3 || ^2.0":  "1.2.3",
+		"lts/hydrogen 18": "18.0.0",
+	} {
+		v, err := MinimumVersion(value)
+		assert.Nil(err)
+		assert.Equal(expected, v.String(), value)
+	}
+
+	_, err := MinimumVersion("latest")
+	assert.EqualError(err, "no version found in 'latest'")
+
+	_, err = MinimumVersion("<20")
+	assert.EqualError(err, "no minimum version found in '<20'")
+
+	_, err = MinimumVersion("<=4, !=3.5")
+	assert.EqualError(err, "no minimum version found in '<=4, !=3.5'")
+}
+
+func TestParseVersionConstraint(t *testing.T) {
+	assert := assert.New(t)
+
+	for value, expected := range map[string]string{
+		"10.3":         ">= 10.3",
+		"^10.3":        ">= 10.3, < 11",
+		"^0.3.1":       ">= 0.3.1, < 0.4",
+		"^0.0.3":       ">= 0.0.3, < 0.0.4",
+		"^0":           ">= 0, < 1",
+		">=14, <16":    ">=14, <16",
+		"~> 5.0.19":    "~> 5.0.19",
+		"^8.1, != 8.2": ">= 8.1, < 9, != 8.2",
+	} {
+		c, err := ParseVersionConstraint(value)
+		assert.Nil(err, value)
+		expectedC, _ := version.NewConstraint(expected)
+		assert.Equal(expectedC.String(), c.String(), value)
+	}
+
+	_, err := ParseVersionConstraint(" , ")
+	assert.EqualError(err, "no version constraint found in ' , '")
+	_, err = ParseVersionConstraint("^foo")
+	assert.EqualError(err, "Malformed version: foo")
+}
+
+func TestPackageVersionAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	allowed, constraints, err := PackageVersionAllowed([]string{"drupal/core:^10.3", "drupal/core@^11"}, "drupal/core", "10.3.6")
+	assert.Nil(err)
+	assert.True(allowed)
+	assert.Nil(constraints)
+
+	allowed, constraints, err = PackageVersionAllowed([]string{"drupal/core:^10.3", "drupal/core@^11"}, "drupal/core", "10.2.8")
+	assert.Nil(err)
+	assert.False(allowed)
+	assert.Equal([]string{"^10.3", "^11"}, constraints)
+
+	// Only the core version is verified.
+	allowed, _, _ = PackageVersionAllowed([]string{"php:^8.1"}, "php", "8.3-cli-alpine")
+	assert.True(allowed)
+
+	// Entries without constraint allow any version.
+	allowed, _, _ = PackageVersionAllowed([]string{"php:^8.1", "php"}, "php", "")
+	assert.True(allowed)
+	allowed, constraints, _ = PackageVersionAllowed([]string{"php:^8.1"}, "php", "")
+	assert.False(allowed)
+	assert.Equal([]string{"^8.1"}, constraints)
+
+	// Other packages & patterns are ignored.
+	allowed, constraints, _ = PackageVersionAllowed([]string{"re:^php", "node:^20"}, "php", "8.1")
+	assert.False(allowed)
+	assert.Empty(constraints)
+
+	// Bare versions are minimum versions, not reported as constraints.
+	allowed, constraints, _ = PackageVersionAllowed([]string{"php:8.1"}, "php", "8.2")
+	assert.True(allowed)
+	allowed, constraints, _ = PackageVersionAllowed([]string{"php:8.1"}, "php", "8.0")
+	assert.False(allowed)
+	assert.Empty(constraints)
+
+	_, _, err = PackageVersionAllowed([]string{"php:^x"}, "php", "8.1")
+	assert.EqualError(err, "invalid version constraint '^x': Malformed version: x")
+}
+
+func TestSplitPackageVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	name, ver := SplitPackageVersion("drupal/core:10.3.6")
+	assert.Equal("drupal/core", name)
+	assert.Equal("10.3.6", ver)
+	name, ver = SplitPackageVersion("bitnami/postgresql@>=14,<16")
+	assert.Equal("bitnami/postgresql", name)
+	assert.Equal(">=14,<16", ver)
+	name, ver = SplitPackageVersion("php")
+	assert.Equal("php", name)
+	assert.Equal("", ver)
+}